@@ -123,6 +123,15 @@ type BeaconStateMutator interface {
 	SetCurrentEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochParticipationFlags(flags []cltypes.ParticipationFlags)
 	SetPreviousEpochAttestations(attestations *solid.ListSSZ[*solid.PendingAttestation]) // temporarily skip this mock
+	SetDepositRequestsStartIndex(index uint64)
+	SetDepositBalanceToConsume(balance uint64)
+	SetExitBalanceToConsume(balance uint64)
+	SetEarliestExitEpoch(epoch uint64)
+	SetConsolidationBalanceToConsume(balance uint64)
+	SetEarliestConsolidationEpoch(epoch uint64)
+	SetPendingDeposits(deposits *solid.ListSSZ[*cltypes.PendingDeposit])
+	SetPendingPartialWithdrawals(withdrawals *solid.ListSSZ[*cltypes.PendingPartialWithdrawal])
+	SetPendingConsolidations(consolidations *solid.ListSSZ[*cltypes.PendingConsolidation])
 
 	AddEth1DataVote(vote *cltypes.Eth1Data)
 	AddValidator(validator solid.Validator, balance uint64)
@@ -134,6 +143,9 @@ type BeaconStateMutator interface {
 	AddPreviousEpochParticipationAt(index int, delta byte)
 	AddCurrentEpochAtteastation(attestation *solid.PendingAttestation)
 	AddPreviousEpochAttestation(attestation *solid.PendingAttestation)
+	AppendPendingDeposit(deposit *cltypes.PendingDeposit)
+	AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal)
+	AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation)
 
 	AppendValidator(in solid.Validator)
 
@@ -208,6 +220,16 @@ type BeaconStateMinimal interface {
 	CurrentEpochAttestationsLength() int
 	PreviousEpochAttestations() *solid.ListSSZ[*solid.PendingAttestation]
 	PreviousEpochAttestationsLength() int
+
+	DepositRequestsStartIndex() uint64
+	DepositBalanceToConsume() uint64
+	ExitBalanceToConsume() uint64
+	EarliestExitEpoch() uint64
+	ConsolidationBalanceToConsume() uint64
+	EarliestConsolidationEpoch() uint64
+	PendingDeposits() *solid.ListSSZ[*cltypes.PendingDeposit]
+	PendingPartialWithdrawals() *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]
+	PendingConsolidations() *solid.ListSSZ[*cltypes.PendingConsolidation]
 }
 
 // BeaconStateReader is an interface for reading the beacon state.
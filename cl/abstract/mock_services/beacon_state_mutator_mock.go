@@ -403,6 +403,114 @@ func (c *MockBeaconStateMutatorAddValidatorCall) DoAndReturn(f func(solid.Valida
 	return c
 }
 
+// AppendPendingConsolidation mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingConsolidation", consolidation)
+}
+
+// AppendPendingConsolidation indicates an expected call of AppendPendingConsolidation.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingConsolidation(consolidation any) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingConsolidation", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingConsolidation), consolidation)
+	return &MockBeaconStateMutatorAppendPendingConsolidationCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingConsolidationCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingConsolidationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) Return() *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) Do(f func(*cltypes.PendingConsolidation)) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingConsolidationCall) DoAndReturn(f func(*cltypes.PendingConsolidation)) *MockBeaconStateMutatorAppendPendingConsolidationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// AppendPendingDeposit mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingDeposit(deposit *cltypes.PendingDeposit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingDeposit", deposit)
+}
+
+// AppendPendingDeposit indicates an expected call of AppendPendingDeposit.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingDeposit(deposit any) *MockBeaconStateMutatorAppendPendingDepositCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingDeposit", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingDeposit), deposit)
+	return &MockBeaconStateMutatorAppendPendingDepositCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingDepositCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingDepositCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingDepositCall) Return() *MockBeaconStateMutatorAppendPendingDepositCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingDepositCall) Do(f func(*cltypes.PendingDeposit)) *MockBeaconStateMutatorAppendPendingDepositCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingDepositCall) DoAndReturn(f func(*cltypes.PendingDeposit)) *MockBeaconStateMutatorAppendPendingDepositCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// AppendPendingPartialWithdrawal mocks base method.
+func (m *MockBeaconStateMutator) AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AppendPendingPartialWithdrawal", withdrawal)
+}
+
+// AppendPendingPartialWithdrawal indicates an expected call of AppendPendingPartialWithdrawal.
+func (mr *MockBeaconStateMutatorMockRecorder) AppendPendingPartialWithdrawal(withdrawal any) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendPendingPartialWithdrawal", reflect.TypeOf((*MockBeaconStateMutator)(nil).AppendPendingPartialWithdrawal), withdrawal)
+	return &MockBeaconStateMutatorAppendPendingPartialWithdrawalCall{Call: call}
+}
+
+// MockBeaconStateMutatorAppendPendingPartialWithdrawalCall wrap *gomock.Call
+type MockBeaconStateMutatorAppendPendingPartialWithdrawalCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) Return() *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) Do(f func(*cltypes.PendingPartialWithdrawal)) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall) DoAndReturn(f func(*cltypes.PendingPartialWithdrawal)) *MockBeaconStateMutatorAppendPendingPartialWithdrawalCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // AppendValidator mocks base method.
 func (m *MockBeaconStateMutator) AppendValidator(in solid.Validator) {
 	m.ctrl.T.Helper()
@@ -727,6 +835,42 @@ func (c *MockBeaconStateMutatorSetBlockRootAtCall) DoAndReturn(f func(int, commo
 	return c
 }
 
+// SetConsolidationBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetConsolidationBalanceToConsume(balance uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConsolidationBalanceToConsume", balance)
+}
+
+// SetConsolidationBalanceToConsume indicates an expected call of SetConsolidationBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetConsolidationBalanceToConsume(balance any) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConsolidationBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetConsolidationBalanceToConsume), balance)
+	return &MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetConsolidationBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetCurrentEpochParticipationFlags mocks base method.
 func (m *MockBeaconStateMutator) SetCurrentEpochParticipationFlags(flags []cltypes.ParticipationFlags) {
 	m.ctrl.T.Helper()
@@ -835,6 +979,150 @@ func (c *MockBeaconStateMutatorSetCurrentSyncCommitteeCall) DoAndReturn(f func(*
 	return c
 }
 
+// SetDepositBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetDepositBalanceToConsume(balance uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDepositBalanceToConsume", balance)
+}
+
+// SetDepositBalanceToConsume indicates an expected call of SetDepositBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetDepositBalanceToConsume(balance any) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepositBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetDepositBalanceToConsume), balance)
+	return &MockBeaconStateMutatorSetDepositBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetDepositBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetDepositBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetDepositBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetDepositBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetDepositRequestsStartIndex mocks base method.
+func (m *MockBeaconStateMutator) SetDepositRequestsStartIndex(index uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDepositRequestsStartIndex", index)
+}
+
+// SetDepositRequestsStartIndex indicates an expected call of SetDepositRequestsStartIndex.
+func (mr *MockBeaconStateMutatorMockRecorder) SetDepositRequestsStartIndex(index any) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepositRequestsStartIndex", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetDepositRequestsStartIndex), index)
+	return &MockBeaconStateMutatorSetDepositRequestsStartIndexCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetDepositRequestsStartIndexCall wrap *gomock.Call
+type MockBeaconStateMutatorSetDepositRequestsStartIndexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) Return() *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) Do(f func(uint64)) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetDepositRequestsStartIndexCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetDepositRequestsStartIndexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetEarliestConsolidationEpoch mocks base method.
+func (m *MockBeaconStateMutator) SetEarliestConsolidationEpoch(epoch uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEarliestConsolidationEpoch", epoch)
+}
+
+// SetEarliestConsolidationEpoch indicates an expected call of SetEarliestConsolidationEpoch.
+func (mr *MockBeaconStateMutatorMockRecorder) SetEarliestConsolidationEpoch(epoch any) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEarliestConsolidationEpoch", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetEarliestConsolidationEpoch), epoch)
+	return &MockBeaconStateMutatorSetEarliestConsolidationEpochCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetEarliestConsolidationEpochCall wrap *gomock.Call
+type MockBeaconStateMutatorSetEarliestConsolidationEpochCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) Return() *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) Do(f func(uint64)) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetEarliestConsolidationEpochCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetEarliestConsolidationEpochCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetEarliestExitEpoch mocks base method.
+func (m *MockBeaconStateMutator) SetEarliestExitEpoch(epoch uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetEarliestExitEpoch", epoch)
+}
+
+// SetEarliestExitEpoch indicates an expected call of SetEarliestExitEpoch.
+func (mr *MockBeaconStateMutatorMockRecorder) SetEarliestExitEpoch(epoch any) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEarliestExitEpoch", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetEarliestExitEpoch), epoch)
+	return &MockBeaconStateMutatorSetEarliestExitEpochCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetEarliestExitEpochCall wrap *gomock.Call
+type MockBeaconStateMutatorSetEarliestExitEpochCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) Return() *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) Do(f func(uint64)) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetEarliestExitEpochCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetEarliestExitEpochCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetEffectiveBalanceForValidatorAtIndex mocks base method.
 func (m *MockBeaconStateMutator) SetEffectiveBalanceForValidatorAtIndex(index int, balance uint64) {
 	m.ctrl.T.Helper()
@@ -979,6 +1267,42 @@ func (c *MockBeaconStateMutatorSetEth1DepositIndexCall) DoAndReturn(f func(uint6
 	return c
 }
 
+// SetExitBalanceToConsume mocks base method.
+func (m *MockBeaconStateMutator) SetExitBalanceToConsume(balance uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetExitBalanceToConsume", balance)
+}
+
+// SetExitBalanceToConsume indicates an expected call of SetExitBalanceToConsume.
+func (mr *MockBeaconStateMutatorMockRecorder) SetExitBalanceToConsume(balance any) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExitBalanceToConsume", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetExitBalanceToConsume), balance)
+	return &MockBeaconStateMutatorSetExitBalanceToConsumeCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetExitBalanceToConsumeCall wrap *gomock.Call
+type MockBeaconStateMutatorSetExitBalanceToConsumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) Return() *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) Do(f func(uint64)) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetExitBalanceToConsumeCall) DoAndReturn(f func(uint64)) *MockBeaconStateMutatorSetExitBalanceToConsumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetExitEpochForValidatorAtIndex mocks base method.
 func (m *MockBeaconStateMutator) SetExitEpochForValidatorAtIndex(index int, epoch uint64) {
 	m.ctrl.T.Helper()
@@ -1339,6 +1663,114 @@ func (c *MockBeaconStateMutatorSetNextWithdrawalValidatorIndexCall) DoAndReturn(
 	return c
 }
 
+// SetPendingConsolidations mocks base method.
+func (m *MockBeaconStateMutator) SetPendingConsolidations(consolidations *solid.ListSSZ[*cltypes.PendingConsolidation]) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPendingConsolidations", consolidations)
+}
+
+// SetPendingConsolidations indicates an expected call of SetPendingConsolidations.
+func (mr *MockBeaconStateMutatorMockRecorder) SetPendingConsolidations(consolidations any) *MockBeaconStateMutatorSetPendingConsolidationsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingConsolidations", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetPendingConsolidations), consolidations)
+	return &MockBeaconStateMutatorSetPendingConsolidationsCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetPendingConsolidationsCall wrap *gomock.Call
+type MockBeaconStateMutatorSetPendingConsolidationsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetPendingConsolidationsCall) Return() *MockBeaconStateMutatorSetPendingConsolidationsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetPendingConsolidationsCall) Do(f func(*solid.ListSSZ[*cltypes.PendingConsolidation])) *MockBeaconStateMutatorSetPendingConsolidationsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetPendingConsolidationsCall) DoAndReturn(f func(*solid.ListSSZ[*cltypes.PendingConsolidation])) *MockBeaconStateMutatorSetPendingConsolidationsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetPendingDeposits mocks base method.
+func (m *MockBeaconStateMutator) SetPendingDeposits(deposits *solid.ListSSZ[*cltypes.PendingDeposit]) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPendingDeposits", deposits)
+}
+
+// SetPendingDeposits indicates an expected call of SetPendingDeposits.
+func (mr *MockBeaconStateMutatorMockRecorder) SetPendingDeposits(deposits any) *MockBeaconStateMutatorSetPendingDepositsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingDeposits", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetPendingDeposits), deposits)
+	return &MockBeaconStateMutatorSetPendingDepositsCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetPendingDepositsCall wrap *gomock.Call
+type MockBeaconStateMutatorSetPendingDepositsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetPendingDepositsCall) Return() *MockBeaconStateMutatorSetPendingDepositsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetPendingDepositsCall) Do(f func(*solid.ListSSZ[*cltypes.PendingDeposit])) *MockBeaconStateMutatorSetPendingDepositsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetPendingDepositsCall) DoAndReturn(f func(*solid.ListSSZ[*cltypes.PendingDeposit])) *MockBeaconStateMutatorSetPendingDepositsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetPendingPartialWithdrawals mocks base method.
+func (m *MockBeaconStateMutator) SetPendingPartialWithdrawals(withdrawals *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPendingPartialWithdrawals", withdrawals)
+}
+
+// SetPendingPartialWithdrawals indicates an expected call of SetPendingPartialWithdrawals.
+func (mr *MockBeaconStateMutatorMockRecorder) SetPendingPartialWithdrawals(withdrawals any) *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingPartialWithdrawals", reflect.TypeOf((*MockBeaconStateMutator)(nil).SetPendingPartialWithdrawals), withdrawals)
+	return &MockBeaconStateMutatorSetPendingPartialWithdrawalsCall{Call: call}
+}
+
+// MockBeaconStateMutatorSetPendingPartialWithdrawalsCall wrap *gomock.Call
+type MockBeaconStateMutatorSetPendingPartialWithdrawalsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall) Return() *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall) Do(f func(*solid.ListSSZ[*cltypes.PendingPartialWithdrawal])) *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall) DoAndReturn(f func(*solid.ListSSZ[*cltypes.PendingPartialWithdrawal])) *MockBeaconStateMutatorSetPendingPartialWithdrawalsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetPreviousEpochAttestations mocks base method.
 func (m *MockBeaconStateMutator) SetPreviousEpochAttestations(attestations *solid.ListSSZ[*solid.PendingAttestation]) {
 	m.ctrl.T.Helper()
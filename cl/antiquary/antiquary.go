@@ -61,8 +61,9 @@ type Antiquary struct {
 	validatorsTable *state_accessors.StaticValidatorTable
 	genesisState    *state.CachingBeaconState
 	// set to nil
-	currentState *state.CachingBeaconState
-	balances32   []byte
+	currentState  *state.CachingBeaconState
+	balances32    []byte
+	pendingQueues pendingQueuesDump // pending queues as of the last dump, reset with currentState
 }
 
 func NewAntiquary(ctx context.Context, blobStorage blob_storage.BlobStorage, genesisState *state.CachingBeaconState, validatorsTable *state_accessors.StaticValidatorTable, cfg *clparams.BeaconChainConfig, dirs datadir.Dirs, downloader proto_downloader.DownloaderClient, mainDB kv.RwDB, sn *freezeblocks.CaplinSnapshots, reader freezeblocks.BeaconSnapshotReader, logger log.Logger, states, blocks, blobs, snapgen bool, snBuildSema *semaphore.Weighted) *Antiquary {
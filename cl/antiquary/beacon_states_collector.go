@@ -36,7 +36,7 @@ import (
 	"github.com/erigontech/erigon/cl/transition/impl/eth2"
 )
 
-var stateAntiquaryBufSz = etl.BufferOptimalSize / 8 // 18 collectors * 256mb / 8 = 512mb in worst case
+var stateAntiquaryBufSz = etl.BufferOptimalSize / 8 // 21 collectors * 256mb / 8 = 672mb in worst case
 
 // RATIONALE: MDBX locks the entire database when writing to it, so we need to minimize the time spent in the write lock.
 // so instead of writing the historical states on write transactions, we accumulate them in memory and write them in a single  write transaction.
@@ -62,6 +62,9 @@ type beaconStatesCollector struct {
 	activeValidatorIndiciesCollector *etl.Collector
 	balancesDumpsCollector           *etl.Collector
 	effectiveBalancesDumpCollector   *etl.Collector
+	pendingDepositsCollector         *etl.Collector
+	pendingWithdrawalsCollector      *etl.Collector
+	pendingConsolidationsCollector   *etl.Collector
 
 	buf        *bytes.Buffer
 	compressor *zstd.Encoder
@@ -95,6 +98,9 @@ func newBeaconStatesCollector(beaconCfg *clparams.BeaconChainConfig, tmpdir stri
 		activeValidatorIndiciesCollector: etl.NewCollector(kv.ActiveValidatorIndicies, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
 		balancesDumpsCollector:           etl.NewCollector(kv.BalancesDump, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
 		effectiveBalancesDumpCollector:   etl.NewCollector(kv.EffectiveBalancesDump, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
+		pendingDepositsCollector:         etl.NewCollector(kv.PendingDeposits, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
+		pendingWithdrawalsCollector:      etl.NewCollector(kv.PendingPartialWithdrawals, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
+		pendingConsolidationsCollector:   etl.NewCollector(kv.PendingConsolidations, tmpdir, etl.NewSortableBuffer(stateAntiquaryBufSz), logger).LogLvl(log.LvlTrace),
 		logger:                           logger,
 		beaconCfg:                        beaconCfg,
 
@@ -286,6 +292,45 @@ func (i *beaconStatesCollector) collectInactivityScores(slot uint64, inactivityS
 	return antiquateFullUint64List(i.inactivityScoresCollector, slot, inactivityScores, i.buf, i.compressor)
 }
 
+// pendingQueuesDump is the last dumped encoding of each Electra pending queue.
+type pendingQueuesDump struct {
+	dumped                                       bool
+	deposits, partialWithdrawals, consolidations []byte
+}
+
+// collectPendingQueues dumps the pending queues which changed since last, all of them the first time.
+func (i *beaconStatesCollector) collectPendingQueues(slot uint64, st *state.CachingBeaconState, last *pendingQueuesDump) error {
+	deposits, err := st.PendingDeposits().EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	partialWithdrawals, err := st.PendingPartialWithdrawals().EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	consolidations, err := st.PendingConsolidations().EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	if !last.dumped || !bytes.Equal(deposits, last.deposits) {
+		if err := antiquateFullUint64List(i.pendingDepositsCollector, slot, deposits, i.buf, i.compressor); err != nil {
+			return err
+		}
+	}
+	if !last.dumped || !bytes.Equal(partialWithdrawals, last.partialWithdrawals) {
+		if err := antiquateFullUint64List(i.pendingWithdrawalsCollector, slot, partialWithdrawals, i.buf, i.compressor); err != nil {
+			return err
+		}
+	}
+	if !last.dumped || !bytes.Equal(consolidations, last.consolidations) {
+		if err := antiquateFullUint64List(i.pendingConsolidationsCollector, slot, consolidations, i.buf, i.compressor); err != nil {
+			return err
+		}
+	}
+	*last = pendingQueuesDump{dumped: true, deposits: deposits, partialWithdrawals: partialWithdrawals, consolidations: consolidations}
+	return nil
+}
+
 func (i *beaconStatesCollector) flush(ctx context.Context, tx kv.RwTx) error {
 	loadfunc := func(k, v []byte, table etl.CurrentTableReader, next etl.LoadNextFunc) error {
 		return next(k, k, v)
@@ -343,6 +388,15 @@ func (i *beaconStatesCollector) flush(ctx context.Context, tx kv.RwTx) error {
 		return err
 	}
 
+	if err := i.pendingDepositsCollector.Load(tx, kv.PendingDeposits, loadfunc, etl.TransformArgs{Quit: ctx.Done()}); err != nil {
+		return err
+	}
+	if err := i.pendingWithdrawalsCollector.Load(tx, kv.PendingPartialWithdrawals, loadfunc, etl.TransformArgs{Quit: ctx.Done()}); err != nil {
+		return err
+	}
+	if err := i.pendingConsolidationsCollector.Load(tx, kv.PendingConsolidations, loadfunc, etl.TransformArgs{Quit: ctx.Done()}); err != nil {
+		return err
+	}
 	return i.balancesDumpsCollector.Load(tx, kv.BalancesDump, loadfunc, etl.TransformArgs{Quit: ctx.Done()})
 }
 
@@ -365,6 +419,9 @@ func (i *beaconStatesCollector) close() {
 	i.activeValidatorIndiciesCollector.Close()
 	i.balancesDumpsCollector.Close()
 	i.effectiveBalancesDumpCollector.Close()
+	i.pendingDepositsCollector.Close()
+	i.pendingWithdrawalsCollector.Close()
+	i.pendingConsolidationsCollector.Close()
 }
 
 // antiquateFullUint64List goes on mdbx as it is full of common repeated patter always and thus fits with 16KB pages.
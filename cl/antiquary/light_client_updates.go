@@ -0,0 +1,119 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package antiquary
+
+import (
+	"context"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+// lightClientUpdatesCollector keeps the best light client update of every sync committee period met while
+// replaying historical blocks, so that the node can serve light client updates since genesis.
+type lightClientUpdatesCollector struct {
+	cfg    *clparams.BeaconChainConfig
+	reader freezeblocks.BeaconSnapshotReader
+
+	attestedBlock  *cltypes.SignedBeaconBlock
+	attestedRoot   libcommon.Hash
+	finalizedBlock *cltypes.SignedBeaconBlock
+	finalizedRoot  libcommon.Hash
+
+	best map[uint64]*cltypes.LightClientUpdate // period -> best update
+}
+
+func newLightClientUpdatesCollector(cfg *clparams.BeaconChainConfig, reader freezeblocks.BeaconSnapshotReader) *lightClientUpdatesCollector {
+	return &lightClientUpdatesCollector{
+		cfg:    cfg,
+		reader: reader,
+		best:   make(map[uint64]*cltypes.LightClientUpdate),
+	}
+}
+
+// collect must be called before block is applied, attestedState being the post-state of the parent of block.
+func (l *lightClientUpdatesCollector) collect(ctx context.Context, tx kv.Tx, block *cltypes.SignedBeaconBlock, attestedState *state.CachingBeaconState) error {
+	defer func() {
+		l.attestedBlock = block
+		l.attestedRoot, _ = block.Block.HashSSZ()
+	}()
+	if block.Version() < clparams.AltairVersion || attestedState.Version() < clparams.AltairVersion {
+		return nil
+	}
+	period := l.cfg.SyncCommitteePeriod(attestedState.Slot())
+	if l.cannotBeImproved(period, block.Block.Body.SyncAggregate.Sum()) {
+		return nil
+	}
+
+	var err error
+	if l.attestedBlock == nil || l.attestedRoot != block.Block.ParentRoot {
+		if l.attestedBlock, err = l.reader.ReadBlockByRoot(ctx, tx, block.Block.ParentRoot); err != nil {
+			return err
+		}
+		if l.attestedBlock == nil {
+			return nil
+		}
+	}
+	if finalizedRoot := attestedState.FinalizedCheckpoint().Root; l.finalizedBlock == nil || l.finalizedRoot != finalizedRoot {
+		if l.finalizedBlock, err = l.reader.ReadBlockByRoot(ctx, tx, finalizedRoot); err != nil {
+			return err
+		}
+		l.finalizedRoot = finalizedRoot
+	}
+
+	update, err := lightclient_utils.CreateLightClientUpdateFromAttestedState(l.cfg, block, l.finalizedBlock, l.attestedBlock, attestedState)
+	if err != nil {
+		// not enough participants or missing finality, no update for this block.
+		return nil
+	}
+	if lightclient_utils.IsBetterUpdate(l.cfg, update, l.best[period]) {
+		l.best[period] = update
+	}
+	return nil
+}
+
+// cannotBeImproved tells whether the best update of the period already ranks first on every criteria but
+// participation, in which case a later block needs more participants to replace it.
+func (l *lightClientUpdatesCollector) cannotBeImproved(period uint64, participants int) bool {
+	best, ok := l.best[period]
+	if !ok {
+		return false
+	}
+	bestParticipants := best.SyncAggregate.Sum()
+	if bestParticipants*3 < int(l.cfg.SyncCommitteeSize)*2 || participants > bestParticipants {
+		return false
+	}
+	attestedPeriod := l.cfg.SyncCommitteePeriod(best.AttestedHeader.Beacon.Slot)
+	return attestedPeriod == l.cfg.SyncCommitteePeriod(best.SignatureSlot) &&
+		attestedPeriod == l.cfg.SyncCommitteePeriod(best.FinalizedHeader.Beacon.Slot) &&
+		best.FinalizedHeader.Beacon.Slot != 0
+}
+
+func (l *lightClientUpdatesCollector) flush(tx kv.RwTx) error {
+	for _, update := range l.best {
+		if _, err := lightclient_utils.WriteBestLightClientUpdate(tx, l.cfg, update); err != nil {
+			return err
+		}
+	}
+	clear(l.best)
+	return nil
+}
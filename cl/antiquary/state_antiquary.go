@@ -133,6 +133,7 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 
 	stateAntiquaryCollector := newBeaconStatesCollector(s.cfg, s.dirs.Tmp, s.logger)
 	defer stateAntiquaryCollector.close()
+	lightClientUpdatesCollector := newLightClientUpdatesCollector(s.cfg, s.snReader)
 
 	if err := s.initializeStateAntiquaryIfNeeded(ctx, tx); err != nil {
 		return err
@@ -142,6 +143,11 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 		if err := stateAntiquaryCollector.addGenesisState(ctx, s.currentState); err != nil {
 			return err
 		}
+		if s.currentState.Version() >= clparams.ElectraVersion {
+			if err := stateAntiquaryCollector.collectPendingQueues(s.currentState.Slot(), s.currentState, &s.pendingQueues); err != nil {
+				return err
+			}
+		}
 		// Mark all validators as touched because we just initizialized the whole state.
 		s.currentState.ForEachValidator(func(v solid.Validator, index, total int) bool {
 			changedValidators[uint64(index)] = struct{}{}
@@ -296,6 +302,11 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 		prevValSet = prevValSet[:0]
 		prevValSet = append(prevValSet, s.currentState.RawValidatorSet()...)
 
+		// the state is still the one of the parent block, which is the attested state of the light client update.
+		if err := lightClientUpdatesCollector.collect(ctx, tx, block, s.currentState); err != nil {
+			return err
+		}
+
 		fullValidation := slot%1000 == 0 || first
 		blockRewardsCollector := &eth2.BlockRewardsCollector{}
 		// We sanity check the state every 1k slots or when we start.
@@ -319,6 +330,11 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 		if err := stateAntiquaryCollector.storeSlotData(s.currentState, blockRewardsCollector); err != nil {
 			return err
 		}
+		if s.currentState.Version() >= clparams.ElectraVersion {
+			if err := stateAntiquaryCollector.collectPendingQueues(slot, s.currentState, &s.pendingQueues); err != nil {
+				return err
+			}
+		}
 
 		if err := stateAntiquaryCollector.collectStateEvents(slot, events); err != nil {
 			return err
@@ -380,6 +396,9 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 	if err := stateAntiquaryCollector.flush(ctx, rwTx); err != nil {
 		return err
 	}
+	if err := lightClientUpdatesCollector.flush(rwTx); err != nil {
+		return err
+	}
 
 	if err := state_accessors.SetStateProcessingProgress(rwTx, s.currentState.Slot()); err != nil {
 		return err
@@ -490,6 +509,7 @@ func (s *Antiquary) initializeStateAntiquaryIfNeeded(ctx context.Context, tx kv.
 
 	s.balances32 = s.balances32[:0]
 	s.balances32 = append(s.balances32, s.currentState.RawBalances()...)
+	s.pendingQueues = pendingQueuesDump{}
 	return s.currentState.InitBeaconState()
 }
 
@@ -43,6 +43,10 @@ type RouterConfiguration struct {
 	Node       bool
 	Validator  bool
 	Lighthouse bool
+	Caplin     bool
+
+	// BuilderBoostFactor is used by block production when the request does not carry builder_boost_factor
+	BuilderBoostFactor uint64
 }
 
 func (r *RouterConfiguration) UnwrapEndpointsList(l []string) error {
@@ -67,6 +71,8 @@ func (r *RouterConfiguration) UnwrapEndpointsList(l []string) error {
 			r.Validator = true
 		case "lighthouse":
 			r.Lighthouse = true
+		case "caplin":
+			r.Caplin = true
 		default:
 			r.Active = false
 			r.Beacon = false
@@ -77,7 +83,8 @@ func (r *RouterConfiguration) UnwrapEndpointsList(l []string) error {
 			r.Node = false
 			r.Validator = false
 			r.Lighthouse = false
-			return fmt.Errorf("unknown endpoint for beacon.api: %s. known endpoints: beacon, builder, config, debug, events, node, validator, lighthouse", v)
+			r.Caplin = false
+			return fmt.Errorf("unknown endpoint for beacon.api: %s. known endpoints: beacon, builder, config, debug, events, node, validator, lighthouse, caplin", v)
 		}
 	}
 	return nil
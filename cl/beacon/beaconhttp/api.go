@@ -20,13 +20,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types/ssz"
+	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice/fork_graph"
 )
 
@@ -45,10 +48,12 @@ var ErrorCantFindBeaconState = errors.New("Could not find beacon state")
 var ErrorSszNotSupported = errors.New("This endpoint does not support SSZ response")
 
 func WrapEndpointError(err error) *EndpointError {
-	e := &EndpointError{}
-	if errors.As(err, e) {
+	if e := (*EndpointError)(nil); errors.As(err, &e) {
 		return e
 	}
+	if e := (EndpointError{}); errors.As(err, &e) {
+		return &e
+	}
 	if errors.Is(err, fork_graph.ErrStateNotFound) {
 		return NewEndpointError(http.StatusNotFound, ErrorCantFindBeaconState)
 	}
@@ -77,6 +82,7 @@ func (e EndpointError) Unwrap() error {
 }
 
 func (e *EndpointError) WriteTo(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(e.Code)
 	encErr := json.NewEncoder(w).Encode(e)
 	if encErr != nil {
@@ -84,6 +90,58 @@ func (e *EndpointError) WriteTo(w http.ResponseWriter) {
 	}
 }
 
+// SszStreamer is implemented by responses which can write their SSZ encoding directly to the client, without
+// materializing it in memory first.
+type SszStreamer interface {
+	StreamSSZ(w io.Writer) error
+}
+
+const (
+	contentTypeJSON        = "application/json"
+	contentTypeSSZ         = "application/octet-stream"
+	contentTypeEventStream = "text/event-stream"
+)
+
+// negotiateContentType picks the response content type out of an Accept header, honoring quality values. An empty
+// Accept header, wildcards and text/html all default to JSON. It returns an empty string if no supported content
+// type is acceptable.
+func negotiateContentType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return contentTypeJSON
+	}
+	best, bestQ := "", 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+		for _, param := range params[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		var contentType string
+		switch mediaType {
+		case contentTypeJSON, "text/html", "*/*", "application/*":
+			contentType = contentTypeJSON
+		case contentTypeSSZ:
+			contentType = contentTypeSSZ
+		case contentTypeEventStream:
+			contentType = contentTypeEventStream
+		default:
+			continue
+		}
+		// on ties the first listed type wins
+		if q > bestQ {
+			best, bestQ = contentType, q
+		}
+	}
+	return best
+}
+
 type EndpointHandler[T any] interface {
 	Handle(w http.ResponseWriter, r *http.Request) (T, error)
 }
@@ -115,13 +173,16 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 		contentType := r.Header.Get("Accept")
 
 		// early return for event stream
-		if slices.Contains(w.Header().Values("Content-Type"), "text/event-stream") {
+		if slices.Contains(w.Header().Values("Content-Type"), contentTypeEventStream) {
 			return
 		}
-		switch {
-		case contentType == "*/*", contentType == "", strings.Contains(contentType, "text/html"), strings.Contains(contentType, "application/json"):
+		if resp, ok := any(ans).(*BeaconResponse); ok && resp != nil && resp.Version != nil {
+			w.Header().Set("Eth-Consensus-Version", clparams.ClVersionToString(*resp.Version))
+		}
+		switch negotiateContentType(contentType) {
+		case contentTypeJSON:
 			if !isNil(ans) {
-				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Type", contentTypeJSON)
 				err := json.NewEncoder(w).Encode(ans)
 				if err != nil {
 					// this error is fatal, log to console
@@ -130,21 +191,24 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 			} else {
 				w.WriteHeader(200)
 			}
-		case strings.Contains(contentType, "application/octet-stream"):
+		case contentTypeSSZ:
+			if streamer, ok := any(ans).(SszStreamer); ok && !isNil(ans) {
+				writeSSZ(w, streamer)
+				return
+			}
 			sszMarshaler, ok := any(ans).(ssz.Marshaler)
-			if !ok {
+			if !ok || isNil(ans) {
 				NewEndpointError(http.StatusBadRequest, ErrorSszNotSupported).WriteTo(w)
 				return
 			}
-			w.Header().Set("Content-Type", "application/octet-stream")
-			// TODO: we should probably figure out some way to stream this in the future :)
 			encoded, err := sszMarshaler.EncodeSSZ(nil)
 			if err != nil {
 				WrapEndpointError(err).WriteTo(w)
 				return
 			}
+			w.Header().Set("Content-Type", contentTypeSSZ)
 			w.Write(encoded)
-		case strings.Contains(contentType, "text/event-stream"):
+		case contentTypeEventStream:
 			return
 		default:
 			http.Error(w, "content type must include application/json, application/octet-stream, or text/event-stream, got "+contentType, http.StatusBadRequest)
@@ -152,6 +216,34 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 	})
 }
 
+// countingWriter tracks whether anything was written, so that errors can still be reported as such before the
+// first byte of the response is sent.
+type countingWriter struct {
+	w       http.ResponseWriter
+	written int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.written == 0 {
+		c.w.Header().Set("Content-Type", contentTypeSSZ)
+	}
+	n, err := c.w.Write(p)
+	c.written += n
+	return n, err
+}
+
+func writeSSZ(w http.ResponseWriter, streamer SszStreamer) {
+	cw := &countingWriter{w: w}
+	if err := streamer.StreamSSZ(cw); err != nil {
+		if cw.written == 0 {
+			WrapEndpointError(err).WriteTo(w)
+			return
+		}
+		// the status code is already sent, the client will notice the truncated response.
+		log.Warn("beaconapi failed to stream ssz", "err", err)
+	}
+}
+
 func isNil[T any](t T) bool {
 	v := reflect.ValueOf(t)
 	kind := v.Kind()
@@ -0,0 +1,82 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package beaconhttp
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	cases := map[string]string{
+		"":                 contentTypeJSON,
+		"*/*":              contentTypeJSON,
+		"application/json": contentTypeJSON,
+		"text/html,application/xhtml+xml,*/*;q=0.8":             contentTypeJSON,
+		"application/octet-stream":                              contentTypeSSZ,
+		"application/octet-stream,application/json":             contentTypeSSZ,
+		"application/json,application/octet-stream":             contentTypeJSON,
+		"application/octet-stream;q=1.0,application/json;q=0.9": contentTypeSSZ,
+		"application/json;q=0.5,application/octet-stream;q=0.6": contentTypeSSZ,
+		"text/event-stream":                                     contentTypeEventStream,
+		"text/plain":                                            "",
+	}
+	for accept, want := range cases {
+		require.Equal(t, want, negotiateContentType(accept), accept)
+	}
+}
+
+func serve(t *testing.T, resp *BeaconResponse, accept string) *http.Response {
+	server := httptest.NewServer(HandleEndpointFunc(func(w http.ResponseWriter, r *http.Request) (*BeaconResponse, error) {
+		return resp, nil
+	}))
+	t.Cleanup(server.Close)
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", accept)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+func TestSSZListResponse(t *testing.T) {
+	checkpoints := []*solid.Checkpoint{{Epoch: 2, Root: [32]byte{1}}, {Epoch: 4, Root: [32]byte{3}}}
+	res := serve(t, NewBeaconResponse(checkpoints).WithVersion(clparams.DenebVersion), "application/octet-stream")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "application/octet-stream", res.Header.Get("Content-Type"))
+	require.Equal(t, "deneb", res.Header.Get("Eth-Consensus-Version"))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Len(t, body, 2*40)
+	require.Equal(t, uint64(2), binary.LittleEndian.Uint64(body[:8]))
+	require.Equal(t, byte(3), body[48])
+}
+
+func TestSSZNotSupported(t *testing.T) {
+	res := serve(t, NewBeaconResponse(map[string]string{"a": "b"}), "application/octet-stream")
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	require.Equal(t, "application/json", res.Header.Get("Content-Type"))
+}
@@ -17,11 +17,15 @@
 package beaconhttp
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"io"
 	"net/http"
+	"reflect"
 
 	"github.com/erigontech/erigon-lib/types/ssz"
 	"github.com/erigontech/erigon/cl/clparams"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
 )
 
 type BeaconResponse struct {
@@ -94,21 +98,88 @@ func (b *BeaconResponse) MarshalJSON() ([]byte, error) {
 }
 
 func (b *BeaconResponse) EncodeSSZ(xs []byte) ([]byte, error) {
-	marshaler, ok := b.Data.(ssz.Marshaler)
+	if marshaler, ok := b.Data.(ssz.Marshaler); ok {
+		return marshaler.EncodeSSZ(xs)
+	}
+	elems, ok := sszListElements(b.Data)
 	if !ok {
 		return nil, NewEndpointError(http.StatusBadRequest, ErrorSszNotSupported)
 	}
-	encoded, err := marshaler.EncodeSSZ(nil)
-	if err != nil {
-		return nil, err
-	}
-	return encoded, nil
+	return encodeSSZList(xs, elems)
 }
 
 func (b *BeaconResponse) EncodingSizeSSZ() int {
-	marshaler, ok := b.Data.(ssz.Marshaler)
+	if marshaler, ok := b.Data.(ssz.Marshaler); ok {
+		return marshaler.EncodingSizeSSZ()
+	}
+	elems, ok := sszListElements(b.Data)
 	if !ok {
 		return 9
 	}
-	return marshaler.EncodingSizeSSZ()
+	size := 0
+	for _, elem := range elems {
+		if !isStaticSSZ(elem) {
+			size += 4
+		}
+		size += elem.EncodingSizeSSZ()
+	}
+	return size
+}
+
+// StreamSSZ writes the SSZ encoding of the response data to w. Data implementing SszStreamer is streamed, anything
+// else is encoded in memory first, so that encoding errors are reported before anything is written.
+func (b *BeaconResponse) StreamSSZ(w io.Writer) error {
+	if streamer, ok := b.Data.(SszStreamer); ok {
+		return streamer.StreamSSZ(w)
+	}
+	encoded, err := b.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
+
+// sszListElements returns the elements of data if it is a slice of SSZ objects, which are then encoded as an SSZ
+// list.
+func sszListElements(data any) ([]ssz.Marshaler, bool) {
+	v := reflect.ValueOf(data)
+	if !v.IsValid() || v.Kind() != reflect.Slice || !v.Type().Elem().Implements(reflect.TypeOf((*ssz.Marshaler)(nil)).Elem()) {
+		return nil, false
+	}
+	elems := make([]ssz.Marshaler, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i).Interface().(ssz.Marshaler)
+	}
+	return elems, true
+}
+
+func isStaticSSZ(elem ssz.Marshaler) bool {
+	sized, ok := elem.(ssz2.Sized)
+	return ok && sized.Static()
+}
+
+func encodeSSZList(buf []byte, elems []ssz.Marshaler) ([]byte, error) {
+	if len(elems) == 0 || isStaticSSZ(elems[0]) {
+		var err error
+		for _, elem := range elems {
+			if buf, err = elem.EncodeSSZ(buf); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	// variable-size elements are prefixed by their offsets
+	offset := 4 * len(elems)
+	for _, elem := range elems {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(offset))
+		offset += elem.EncodingSizeSSZ()
+	}
+	var err error
+	for _, elem := range elems {
+		if buf, err = elem.EncodeSSZ(buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
 }
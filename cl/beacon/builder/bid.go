@@ -0,0 +1,113 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/erigontech/erigon-lib/common"
+)
+
+const (
+	gasLimitBoundDivisor = 1024 // bound divisor of the gas limit, used in update calculations
+	minGasLimit          = 5000 // minimum the gas limit may ever be
+)
+
+var (
+	ErrNilBid             = errors.New("bid has no execution header")
+	ErrInvalidBidValue    = errors.New("invalid bid value")
+	ErrParentHashMismatch = errors.New("bid parent hash mismatch")
+	ErrGasLimitMismatch   = errors.New("bid gas limit mismatch")
+	ErrNoRelayAvailable   = errors.New("no relay available")
+	ErrNoBids             = errors.New("no bids received from relays")
+)
+
+// Bid is an execution header offered by a relay for a given slot.
+type Bid struct {
+	Relay  string
+	Header *ExecutionHeader
+}
+
+func (b *Bid) Value() *big.Int {
+	if b == nil || b.Header == nil {
+		return nil
+	}
+	return b.Header.BlockValue()
+}
+
+// BidExpectations is what the proposer knows about the block a relay should build on.
+type BidExpectations struct {
+	ParentHash     common.Hash
+	ParentGasLimit uint64
+	// RegisteredGasLimit is the gas limit the proposer registered with the relays, 0 if unknown.
+	RegisteredGasLimit uint64
+}
+
+// ValidateBid checks that the bid pays something, builds on the expected parent and follows the
+// gas limit the proposer registered with.
+func ValidateBid(bid *Bid, expected BidExpectations) error {
+	if bid == nil || bid.Header == nil || bid.Header.Data.Message.Header == nil {
+		return ErrNilBid
+	}
+	value := bid.Value()
+	if value == nil || value.Sign() <= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidBidValue, bid.Header.Data.Message.Value)
+	}
+	header := bid.Header.Data.Message.Header
+	if header.ParentHash != expected.ParentHash {
+		return fmt.Errorf("%w: got %x, expected %x", ErrParentHashMismatch, header.ParentHash, expected.ParentHash)
+	}
+	if expected.RegisteredGasLimit != 0 && expected.ParentGasLimit != 0 {
+		if want := ExpectedGasLimit(expected.ParentGasLimit, expected.RegisteredGasLimit); header.GasLimit != want {
+			return fmt.Errorf("%w: got %d, expected %d", ErrGasLimitMismatch, header.GasLimit, want)
+		}
+	}
+	return nil
+}
+
+// ExpectedGasLimit computes the gas limit of the block following the parent, moving towards the
+// registered target by at most parentGasLimit/1024 - 1 per block.
+func ExpectedGasLimit(parentGasLimit, targetGasLimit uint64) uint64 {
+	delta := parentGasLimit/gasLimitBoundDivisor - 1
+	if targetGasLimit < minGasLimit {
+		targetGasLimit = minGasLimit
+	}
+	if parentGasLimit < targetGasLimit {
+		return min(parentGasLimit+delta, targetGasLimit)
+	}
+	if parentGasLimit > targetGasLimit {
+		return max(parentGasLimit-delta, targetGasLimit)
+	}
+	return parentGasLimit
+}
+
+// SelectBestBid returns the bid with the highest value, the first one wins ties.
+func SelectBestBid(bids []*Bid) *Bid {
+	var best *Bid
+	for _, bid := range bids {
+		value := bid.Value()
+		if value == nil {
+			continue
+		}
+		if best == nil || value.Cmp(best.Value()) > 0 {
+			best = bid
+		}
+	}
+	return best
+}
@@ -47,15 +47,10 @@ type builderClient struct {
 }
 
 func NewBlockBuilderClient(baseUrl string, beaconConfig *clparams.BeaconChainConfig) *builderClient {
-	u, err := url.Parse(baseUrl)
+	c, err := newBuilderClient(baseUrl, beaconConfig)
 	if err != nil {
 		panic(err)
 	}
-	c := &builderClient{
-		httpClient:   &http.Client{},
-		url:          u,
-		beaconConfig: beaconConfig,
-	}
 	if err := c.GetStatus(context.Background()); err != nil {
 		log.Error("cannot connect to builder client", "url", baseUrl, "error", err)
 		panic("cannot connect to builder client")
@@ -64,6 +59,21 @@ func NewBlockBuilderClient(baseUrl string, beaconConfig *clparams.BeaconChainCon
 	return c
 }
 
+func newBuilderClient(baseUrl string, beaconConfig *clparams.BeaconChainConfig) (*builderClient, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid builder url %q", baseUrl)
+	}
+	return &builderClient{
+		httpClient:   &http.Client{},
+		url:          u,
+		beaconConfig: beaconConfig,
+	}, nil
+}
+
 func (b *builderClient) RegisterValidator(ctx context.Context, registers []*cltypes.ValidatorRegistration) error {
 	// https://ethereum.github.io/builder-specs/#/Builder/registerValidator
 	path := "/eth/v1/builder/validators"
@@ -98,6 +108,14 @@ func (b *builderClient) GetHeader(ctx context.Context, slot int64, parentHash co
 	return header, nil
 }
 
+func (b *builderClient) GetHeaders(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) ([]*Bid, error) {
+	header, err := b.GetHeader(ctx, slot, parentHash, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return []*Bid{{Relay: b.url.String(), Header: header}}, nil
+}
+
 func (b *builderClient) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, error) {
 	// https://ethereum.github.io/builder-specs/#/Builder/submitBlindedBlocks
 	path := "/eth/v1/builder/blinded_blocks"
@@ -28,6 +28,8 @@ import (
 type BuilderClient interface {
 	RegisterValidator(ctx context.Context, registers []*cltypes.ValidatorRegistration) error
 	GetHeader(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) (*ExecutionHeader, error)
+	// GetHeaders returns the bids of every relay that answered in time, GetHeader returns only the best one.
+	GetHeaders(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) ([]*Bid, error)
 	SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, error)
 	GetStatus(ctx context.Context) error
 }
@@ -83,6 +83,45 @@ func (c *MockBuilderClientGetHeaderCall) DoAndReturn(f func(context.Context, int
 	return c
 }
 
+// GetHeaders mocks base method.
+func (m *MockBuilderClient) GetHeaders(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) ([]*builder.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeaders", ctx, slot, parentHash, pubKey)
+	ret0, _ := ret[0].([]*builder.Bid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeaders indicates an expected call of GetHeaders.
+func (mr *MockBuilderClientMockRecorder) GetHeaders(ctx, slot, parentHash, pubKey any) *MockBuilderClientGetHeadersCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeaders", reflect.TypeOf((*MockBuilderClient)(nil).GetHeaders), ctx, slot, parentHash, pubKey)
+	return &MockBuilderClientGetHeadersCall{Call: call}
+}
+
+// MockBuilderClientGetHeadersCall wrap *gomock.Call
+type MockBuilderClientGetHeadersCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBuilderClientGetHeadersCall) Return(arg0 []*builder.Bid, arg1 error) *MockBuilderClientGetHeadersCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBuilderClientGetHeadersCall) Do(f func(context.Context, int64, common.Hash, common.Bytes48) ([]*builder.Bid, error)) *MockBuilderClientGetHeadersCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBuilderClientGetHeadersCall) DoAndReturn(f func(context.Context, int64, common.Hash, common.Bytes48) ([]*builder.Bid, error)) *MockBuilderClientGetHeadersCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetStatus mocks base method.
 func (m *MockBuilderClient) GetStatus(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,220 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/phase1/core/state/lru"
+	"github.com/erigontech/erigon/turbo/engineapi/engine_types"
+)
+
+const bidRelaysCacheSize = 256
+
+var _ BuilderClient = &relayPool{}
+
+// relayPool fans every builder call out to a set of relays. Each relay gets its own timeout so
+// that a slow or unreachable relay never holds up block production.
+type relayPool struct {
+	relays  []*builderClient
+	timeout time.Duration
+	// bidRelays remembers which relay offered a given block hash, so that the signed blinded
+	// block is submitted to the relay that is able to unblind it.
+	bidRelays *lru.Cache[common.Hash, *builderClient]
+}
+
+func NewRelayPool(urls []string, timeout time.Duration, beaconConfig *clparams.BeaconChainConfig) *relayPool {
+	bidRelays, err := lru.New[common.Hash, *builderClient]("builder_bid_relays", bidRelaysCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	p := &relayPool{
+		timeout:   timeout,
+		bidRelays: bidRelays,
+	}
+	for _, u := range urls {
+		relay, err := newBuilderClient(u, beaconConfig)
+		if err != nil {
+			log.Error("[mev builder] skipping relay", "url", u, "err", err)
+			continue
+		}
+		p.relays = append(p.relays, relay)
+	}
+	// unreachable relays are kept, they may come back before the next proposal
+	errs := p.forEachRelay(context.Background(), p.relays, p.timeout, func(ctx context.Context, idx int, relay *builderClient) error {
+		return relay.GetStatus(ctx)
+	})
+	for i, err := range errs {
+		if err != nil {
+			log.Warn("[mev builder] relay is not reachable", "url", p.relays[i].url, "err", err)
+			continue
+		}
+		log.Info("[mev builder] relay is ready", "url", p.relays[i].url)
+	}
+	return p
+}
+
+// forEachRelay runs fn against every relay in parallel and returns the error of each relay.
+func (p *relayPool) forEachRelay(ctx context.Context, relays []*builderClient, timeout time.Duration, fn func(ctx context.Context, idx int, relay *builderClient) error) []error {
+	errs := make([]error, len(relays))
+	var wg sync.WaitGroup
+	for i, relay := range relays {
+		wg.Add(1)
+		go func(i int, relay *builderClient) {
+			defer wg.Done()
+			relayCtx := ctx
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				relayCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			errs[i] = fn(relayCtx, i, relay)
+		}(i, relay)
+	}
+	wg.Wait()
+	return errs
+}
+
+func (p *relayPool) RegisterValidator(ctx context.Context, registers []*cltypes.ValidatorRegistration) error {
+	if len(p.relays) == 0 {
+		return ErrNoRelayAvailable
+	}
+	errs := p.forEachRelay(ctx, p.relays, p.timeout, func(ctx context.Context, idx int, relay *builderClient) error {
+		return relay.RegisterValidator(ctx, registers)
+	})
+	// registering with at least one relay is enough to receive bids
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *relayPool) GetHeaders(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) ([]*Bid, error) {
+	if len(p.relays) == 0 {
+		return nil, ErrNoRelayAvailable
+	}
+	headers := make([]*ExecutionHeader, len(p.relays))
+	errs := p.forEachRelay(ctx, p.relays, p.timeout, func(ctx context.Context, idx int, relay *builderClient) error {
+		header, err := relay.GetHeader(ctx, slot, parentHash, pubKey)
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			// the relay has no bid for this slot
+			return ErrNoContent
+		}
+		headers[idx] = header
+		return nil
+	})
+	bids := []*Bid{}
+	for idx, header := range headers {
+		if errs[idx] != nil {
+			log.Debug("[mev builder] no bid from relay", "url", p.relays[idx].url, "slot", slot, "err", errs[idx])
+			continue
+		}
+		if ethHeader := header.Data.Message.Header; ethHeader != nil {
+			p.bidRelays.Add(ethHeader.BlockHash, p.relays[idx])
+		}
+		bids = append(bids, &Bid{Relay: p.relays[idx].url.String(), Header: header})
+	}
+	if len(bids) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrNoBids, errors.Join(errs...))
+	}
+	return bids, nil
+}
+
+func (p *relayPool) GetHeader(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) (*ExecutionHeader, error) {
+	bids, err := p.GetHeaders(ctx, slot, parentHash, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	best := SelectBestBid(bids)
+	if best == nil {
+		return nil, ErrNoBids
+	}
+	return best.Header, nil
+}
+
+func (p *relayPool) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, error) {
+	if len(p.relays) == 0 {
+		return nil, nil, ErrNoRelayAvailable
+	}
+	relays := p.relays
+	var winnerErr error
+	if block.Block != nil && block.Block.Body != nil && block.Block.Body.ExecutionPayload != nil {
+		if relay, ok := p.bidRelays.Get(block.Block.Body.ExecutionPayload.BlockHash); ok {
+			eth1Block, blobsBundle, err := relay.SubmitBlindedBlocks(ctx, block)
+			if err == nil {
+				return eth1Block, blobsBundle, nil
+			}
+			log.Warn("[mev builder] relay of the winning bid failed to unblind the block, trying the others", "url", relay.url, "err", err)
+			winnerErr = err
+			relays = make([]*builderClient, 0, len(p.relays)-1)
+			for _, r := range p.relays {
+				if r != relay {
+					relays = append(relays, r)
+				}
+			}
+		}
+	}
+
+	type unblinded struct {
+		eth1Block   *cltypes.Eth1Block
+		blobsBundle *engine_types.BlobsBundleV1
+	}
+	results := make([]unblinded, len(relays))
+	// unblinding is not bounded by the relay timeout, missing the block is worse than waiting
+	errs := p.forEachRelay(ctx, relays, 0, func(ctx context.Context, idx int, relay *builderClient) error {
+		eth1Block, blobsBundle, err := relay.SubmitBlindedBlocks(ctx, block)
+		if err != nil {
+			return err
+		}
+		results[idx] = unblinded{eth1Block, blobsBundle}
+		return nil
+	})
+	for idx, err := range errs {
+		if err == nil {
+			return results[idx].eth1Block, results[idx].blobsBundle, nil
+		}
+	}
+	return nil, nil, errors.Join(append(errs, winnerErr)...)
+}
+
+func (p *relayPool) GetStatus(ctx context.Context) error {
+	if len(p.relays) == 0 {
+		return ErrNoRelayAvailable
+	}
+	errs := p.forEachRelay(ctx, p.relays, p.timeout, func(ctx context.Context, idx int, relay *builderClient) error {
+		return relay.GetStatus(ctx)
+	})
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
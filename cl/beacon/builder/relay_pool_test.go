@@ -0,0 +1,171 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRelay is a local relay serving a single bid, used to exercise the relay pool end to end.
+type stubRelay struct {
+	server      *httptest.Server
+	header      *ExecutionHeader
+	delay       time.Duration
+	registered  atomic.Int32
+	submissions atomic.Int32
+}
+
+func newStubRelay(t *testing.T, value string, parentHash, blockHash common.Hash, gasLimit uint64, delay time.Duration) *stubRelay {
+	header := &ExecutionHeader{}
+	require.NoError(t, json.Unmarshal(mockHeaderBytes, header))
+	header.Data.Message.Value = value
+	header.Data.Message.Header.ParentHash = parentHash
+	header.Data.Message.Header.BlockHash = blockHash
+	header.Data.Message.Header.GasLimit = gasLimit
+
+	relay := &stubRelay{header: header, delay: delay}
+	relay.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(relay.delay):
+		case <-r.Context().Done():
+			return
+		}
+		switch {
+		case r.URL.Path == "/eth/v1/builder/status":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/eth/v1/builder/validators":
+			relay.registered.Add(1)
+			w.WriteHeader(http.StatusOK)
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/builder/header/"):
+			require.NoError(t, json.NewEncoder(w).Encode(relay.header))
+		case r.URL.Path == "/eth/v1/builder/blinded_blocks":
+			relay.submissions.Add(1)
+			w.Write(mockBlindedResponseBytes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(relay.server.Close)
+	return relay
+}
+
+func TestRelayPoolGetHeaders(t *testing.T) {
+	parentHash := common.HexToHash("0x01")
+	fast := newStubRelay(t, "10", parentHash, common.HexToHash("0xa1"), 30_000_000, 0)
+	wrongParent := newStubRelay(t, "20", common.HexToHash("0x02"), common.HexToHash("0xa2"), 30_000_000, 0)
+	slow := newStubRelay(t, "100", parentHash, common.HexToHash("0xa3"), 30_000_000, time.Second)
+
+	pool := NewRelayPool([]string{fast.server.URL, wrongParent.server.URL, slow.server.URL}, 200*time.Millisecond, mockBeaconConfig)
+	bids, err := pool.GetHeaders(context.Background(), 1, parentHash, common.Bytes48{})
+	require.NoError(t, err)
+	// the slow relay is dropped by its timeout
+	require.Len(t, bids, 2)
+	require.Equal(t, wrongParent.server.URL, SelectBestBid(bids).Relay)
+
+	valid := []*Bid{}
+	for _, bid := range bids {
+		if ValidateBid(bid, BidExpectations{ParentHash: parentHash}) == nil {
+			valid = append(valid, bid)
+		}
+	}
+	require.Len(t, valid, 1)
+	require.Equal(t, fast.server.URL, valid[0].Relay)
+}
+
+func TestRelayPoolSubmitToWinningRelay(t *testing.T) {
+	parentHash := common.HexToHash("0x01")
+	first := newStubRelay(t, "10", parentHash, common.HexToHash("0xa1"), 30_000_000, 0)
+	second := newStubRelay(t, "20", parentHash, common.HexToHash("0xa2"), 30_000_000, 0)
+	pool := NewRelayPool([]string{first.server.URL, second.server.URL}, time.Second, mockBeaconConfig)
+
+	header, err := pool.GetHeader(context.Background(), 1, parentHash, common.Bytes48{})
+	require.NoError(t, err)
+	require.Equal(t, "20", header.Data.Message.Value)
+
+	block := &cltypes.SignedBlindedBeaconBlock{}
+	require.NoError(t, json.Unmarshal(mockBlindedBlockBytes, block))
+	block.Block.Body.ExecutionPayload.BlockHash = header.Data.Message.Header.BlockHash
+	_, _, err = pool.SubmitBlindedBlocks(context.Background(), block)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), first.submissions.Load())
+	require.Equal(t, int32(1), second.submissions.Load())
+}
+
+func TestRelayPoolUnreachableRelays(t *testing.T) {
+	down := newStubRelay(t, "10", common.Hash{}, common.Hash{}, 30_000_000, 0)
+	down.server.Close()
+	up := newStubRelay(t, "10", common.Hash{}, common.Hash{}, 30_000_000, 0)
+
+	// an unreachable relay must not prevent the node from starting
+	pool := NewRelayPool([]string{down.server.URL, "not a url", up.server.URL}, time.Second, mockBeaconConfig)
+	require.Len(t, pool.relays, 2)
+	require.NoError(t, pool.RegisterValidator(context.Background(), []*cltypes.ValidatorRegistration{{}}))
+	require.Equal(t, int32(1), up.registered.Load())
+
+	up.server.Close()
+	_, err := pool.GetHeader(context.Background(), 1, common.Hash{}, common.Bytes48{})
+	require.ErrorIs(t, err, ErrNoBids)
+	require.Error(t, pool.GetStatus(context.Background()))
+
+	_, err = NewRelayPool(nil, time.Second, mockBeaconConfig).GetHeaders(context.Background(), 1, common.Hash{}, common.Bytes48{})
+	require.ErrorIs(t, err, ErrNoRelayAvailable)
+}
+
+func TestValidateBid(t *testing.T) {
+	parentHash := common.HexToHash("0x01")
+	newBid := func(value string, parent common.Hash, gasLimit uint64) *Bid {
+		header := &ExecutionHeader{}
+		require.NoError(t, json.Unmarshal(mockHeaderBytes, header))
+		header.Data.Message.Value = value
+		header.Data.Message.Header.ParentHash = parent
+		header.Data.Message.Header.GasLimit = gasLimit
+		return &Bid{Header: header}
+	}
+	expected := BidExpectations{
+		ParentHash:         parentHash,
+		ParentGasLimit:     30_000_000,
+		RegisteredGasLimit: 36_000_000,
+	}
+	require.NoError(t, ValidateBid(newBid("1", parentHash, 30_029_295), expected))
+	require.ErrorIs(t, ValidateBid(newBid("0", parentHash, 30_029_295), expected), ErrInvalidBidValue)
+	require.ErrorIs(t, ValidateBid(newBid("abc", parentHash, 30_029_295), expected), ErrInvalidBidValue)
+	require.ErrorIs(t, ValidateBid(newBid("1", common.Hash{}, 30_029_295), expected), ErrParentHashMismatch)
+	require.ErrorIs(t, ValidateBid(newBid("1", parentHash, 36_000_000), expected), ErrGasLimitMismatch)
+	require.ErrorIs(t, ValidateBid(nil, expected), ErrNilBid)
+
+	// gas limit is not checked without a registration
+	expected.RegisteredGasLimit = 0
+	require.NoError(t, ValidateBid(newBid("1", parentHash, 36_000_000), expected))
+}
+
+func TestExpectedGasLimit(t *testing.T) {
+	require.Equal(t, uint64(30_000_000), ExpectedGasLimit(30_000_000, 30_000_000))
+	require.Equal(t, uint64(30_029_295), ExpectedGasLimit(30_000_000, 36_000_000))
+	require.Equal(t, uint64(29_970_705), ExpectedGasLimit(30_000_000, 20_000_000))
+	require.Equal(t, uint64(30_010_000), ExpectedGasLimit(30_000_000, 30_010_000))
+}
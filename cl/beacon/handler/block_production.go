@@ -49,6 +49,7 @@ import (
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/cl/gossip"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
@@ -168,7 +169,7 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 	}
 
 	// builder boost factor controls block choice between local execution node or builder
-	var builderBoostFactor uint64
+	builderBoostFactor := a.routerCfg.BuilderBoostFactor
 	builderBoostFactorStr := r.URL.Query().Get("builder_boost_factor")
 	if builderBoostFactorStr != "" {
 		builderBoostFactor, err = strconv.ParseUint(builderBoostFactorStr, 10, 64)
@@ -312,15 +313,15 @@ func (a *ApiHandler) produceBlock(
 
 	// get the builder payload
 	var (
-		builderHeader *builder.ExecutionHeader
-		builderErr    error
+		builderBid *builder.Bid
+		builderErr error
 	)
 	go func() {
 		defer wg.Done()
 		if a.routerCfg.Builder && a.builderClient != nil {
-			builderHeader, builderErr = a.getBuilderPayload(ctx, baseBlock, baseState, targetSlot)
+			builderBid, builderErr = a.getBuilderPayload(ctx, baseBlock, baseState, targetSlot)
 			if builderErr != nil && builderErr != errBuilderNotEnabled {
-				log.Warn("Failed to get builder payload", "err", builderErr)
+				log.Warn("Failed to get builder payload, falling back to local execution payload", "err", builderErr, "slot", targetSlot)
 			}
 		}
 	}()
@@ -362,10 +363,15 @@ func (a *ApiHandler) produceBlock(
 	// if exec_node_payload_value >= builder_boost_factor * (builder_payload_value // 100), then return a full (unblinded) block containing the execution node payload.
 	// otherwise, return a blinded block containing the builder payload header.
 	execValue := new(big.Int).SetUint64(localExecValue)
-	builderValue := builderHeader.BlockValue()
+	builderHeader := builderBid.Header
+	builderValue := builderBid.Value()
 	boostFactorBig := new(big.Int).SetUint64(boostFactor)
 	useLocalExec := new(big.Int).Mul(execValue, big.NewInt(100)).Cmp(new(big.Int).Mul(builderValue, boostFactorBig)) >= 0
-	log.Info("Check mev bid", "useLocalExec", useLocalExec, "execValue", execValue, "builderValue", builderValue, "boostFactor", boostFactor, "targetSlot", targetSlot)
+	reason := "boosted builder bid is higher than local payload"
+	if useLocalExec {
+		reason = "local payload is at least as valuable as boosted builder bid"
+	}
+	log.Info("Check mev bid", "useLocalExec", useLocalExec, "reason", reason, "relay", builderBid.Relay, "execValue", execValue, "builderValue", builderValue, "boostFactor", boostFactor, "targetSlot", targetSlot)
 
 	if useLocalExec {
 		block.BeaconBody = beaconBody
@@ -400,7 +406,7 @@ func (a *ApiHandler) getBuilderPayload(
 	baseBlock *cltypes.BeaconBlock,
 	baseState *state.CachingBeaconState,
 	targetSlot uint64,
-) (*builder.ExecutionHeader, error) {
+) (*builder.Bid, error) {
 	if !a.routerCfg.Builder || a.builderClient == nil {
 		return nil, errBuilderNotEnabled
 	}
@@ -416,38 +422,62 @@ func (a *ApiHandler) getBuilderPayload(
 	}
 	// get the parent hash of base execution block
 	parentHash := baseBlock.Body.ExecutionPayload.BlockHash
-	header, err := a.builderClient.GetHeader(ctx, int64(targetSlot), parentHash, pubKey)
+	bids, err := a.builderClient.GetHeaders(ctx, int64(targetSlot), parentHash, pubKey)
 	if err != nil {
 		return nil, err
-	} else if header == nil {
-		return nil, errors.New("no error but nil header")
 	}
 
-	// check the version
-	curVersion := baseState.Version().String()
-	if !strings.EqualFold(header.Version, curVersion) {
-		return nil, fmt.Errorf("invalid version %s, expected %s", header.Version, curVersion)
+	expected := builder.BidExpectations{
+		ParentHash:     parentHash,
+		ParentGasLimit: baseBlock.Body.ExecutionPayload.GasLimit,
+	}
+	if gasLimit, ok := a.validatorParams.GetGasLimit(pubKey); ok {
+		expected.RegisteredGasLimit = gasLimit
+	}
+	validBids := make([]*builder.Bid, 0, len(bids))
+	for _, bid := range bids {
+		if err := validateBuilderBid(bid, expected, baseState.Version()); err != nil {
+			log.Warn("Rejected builder bid", "relay", bid.Relay, "slot", targetSlot, "reason", err)
+			continue
+		}
+		validBids = append(validBids, bid)
 	}
-	if ethHeader := header.Data.Message.Header; ethHeader != nil {
-		ethHeader.SetVersion(baseState.Version())
+	best := builder.SelectBestBid(validBids)
+	if best == nil {
+		return nil, fmt.Errorf("no valid builder bid out of %d", len(bids))
+	}
+	best.Header.Data.Message.Header.SetVersion(baseState.Version())
+	return best, nil
+}
+
+func validateBuilderBid(bid *builder.Bid, expected builder.BidExpectations, version clparams.StateVersion) error {
+	if err := builder.ValidateBid(bid, expected); err != nil {
+		return err
+	}
+	header := bid.Header
+	// check the version
+	if !strings.EqualFold(header.Version, version.String()) {
+		return fmt.Errorf("invalid version %s, expected %s", header.Version, version.String())
 	}
 	// check kzg commitments
-	if header != nil && baseState.Version() >= clparams.DenebVersion {
+	if version >= clparams.DenebVersion {
+		if header.Data.Message.BlobKzgCommitments == nil {
+			return errors.New("nil blob kzg commitments")
+		}
 		if header.Data.Message.BlobKzgCommitments.Len() >= cltypes.MaxBlobsCommittmentsPerBlock {
-			return nil, fmt.Errorf("too many blob kzg commitments: %d", header.Data.Message.BlobKzgCommitments.Len())
+			return fmt.Errorf("too many blob kzg commitments: %d", header.Data.Message.BlobKzgCommitments.Len())
 		}
 		for i := 0; i < header.Data.Message.BlobKzgCommitments.Len(); i++ {
 			c := header.Data.Message.BlobKzgCommitments.Get(i)
 			if c == nil {
-				return nil, errors.New("nil blob kzg commitment")
+				return errors.New("nil blob kzg commitment")
 			}
 			if len(c) != length.Bytes48 {
-				return nil, errors.New("invalid blob kzg commitment length")
+				return errors.New("invalid blob kzg commitment length")
 			}
 		}
 	}
-
-	return header, nil
+	return nil
 }
 
 func (a *ApiHandler) produceBeaconBody(
@@ -506,7 +536,7 @@ func (a *ApiHandler) produceBeaconBody(
 		secsDiff := (targetSlot - baseBlock.Slot) * a.beaconChainCfg.SecondsPerSlot
 		feeRecipient, _ := a.validatorParams.GetFeeRecipient(proposerIndex)
 		var withdrawals []*types.Withdrawal
-		clWithdrawals := state.ExpectedWithdrawals(
+		clWithdrawals, _ := state.ExpectedWithdrawals(
 			baseState,
 			targetSlot/a.beaconChainCfg.SlotsPerEpoch,
 		)
@@ -976,7 +1006,24 @@ func (a *ApiHandler) broadcastBlock(ctx context.Context, blk *cltypes.SignedBeac
 
 	header := blk.SignedBeaconBlockHeader()
 
-	if blk.Version() >= clparams.DenebVersion {
+	peerDASActive := a.beaconChainCfg.IsPeerDASActive(blk.Block.Slot / a.beaconChainCfg.SlotsPerEpoch)
+	var dataColumnSidecars []*cltypes.DataColumnSidecar
+	if peerDASActive && blk.Block.Body.BlobKzgCommitments.Len() > 0 {
+		blobs := make([]*cltypes.Blob, 0, blk.Block.Body.BlobKzgCommitments.Len())
+		for i := 0; i < blk.Block.Body.BlobKzgCommitments.Len(); i++ {
+			commitment := blk.Block.Body.BlobKzgCommitments.Get(i)
+			bundle, has := a.blobBundles.Get(libcommon.Bytes48(*commitment))
+			if !has {
+				return fmt.Errorf("missing blob bundle for commitment %x", commitment)
+			}
+			blobs = append(blobs, bundle.Blob)
+		}
+		if dataColumnSidecars, err = das.ComputeDataColumnSidecars(a.beaconChainCfg, blk, blobs); err != nil {
+			return err
+		}
+	}
+
+	if blk.Version() >= clparams.DenebVersion && !peerDASActive {
 		for i := 0; i < blk.Block.Body.BlobKzgCommitments.Len(); i++ {
 			blobSidecar := &cltypes.BlobSidecar{}
 			commitment := blk.Block.Body.BlobKzgCommitments.Get(i)
@@ -1010,7 +1057,7 @@ func (a *ApiHandler) broadcastBlock(ctx context.Context, blk *cltypes.SignedBeac
 		}
 	}
 	go func() {
-		if err := a.storeBlockAndBlobs(context.Background(), blk, blobsSidecars); err != nil {
+		if err := a.storeBlockAndBlobs(context.Background(), blk, blobsSidecars, dataColumnSidecars); err != nil {
 			log.Error("BlockPublishing: Failed to store block and blobs", "err", err)
 		}
 	}()
@@ -1021,6 +1068,8 @@ func (a *ApiHandler) broadcastBlock(ctx context.Context, blk *cltypes.SignedBeac
 		blk.Block.Slot,
 		"blobs",
 		len(blobsSidecars),
+		"columns",
+		len(dataColumnSidecars),
 	)
 	// Broadcast the block and its blobs
 	if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
@@ -1041,6 +1090,21 @@ func (a *ApiHandler) broadcastBlock(ctx context.Context, blk *cltypes.SignedBeac
 			return err
 		}
 	}
+	for _, column := range dataColumnSidecars {
+		columnSSZ, err := column.EncodeSSZ(nil)
+		if err != nil {
+			return err
+		}
+		subnet := das.ComputeSubnetForDataColumnSidecar(a.beaconChainCfg, column.Index)
+		if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{
+			Name:     gossip.TopicNamePrefixDataColumnSidecar,
+			Data:     columnSSZ,
+			SubnetId: &subnet,
+		}); err != nil {
+			log.Error("Failed to publish data column sidecar", "err", err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1048,6 +1112,7 @@ func (a *ApiHandler) storeBlockAndBlobs(
 	ctx context.Context,
 	block *cltypes.SignedBeaconBlock,
 	sidecars []*cltypes.BlobSidecar,
+	dataColumnSidecars []*cltypes.DataColumnSidecar,
 ) error {
 	blockRoot, err := block.Block.HashSSZ()
 	if err != nil {
@@ -1056,6 +1121,11 @@ func (a *ApiHandler) storeBlockAndBlobs(
 	if err := a.blobStoage.WriteBlobSidecars(ctx, blockRoot, sidecars); err != nil {
 		return err
 	}
+	if len(dataColumnSidecars) > 0 {
+		if err := a.dataColumnStore.WriteColumnSidecars(ctx, blockRoot, dataColumnSidecars); err != nil {
+			return err
+		}
+	}
 	if err := a.indiciesDB.Update(ctx, func(tx kv.RwTx) error {
 		if err := beacon_indicies.WriteHighestFinalized(tx, a.forkchoiceStore.FinalizedSlot()); err != nil {
 			return err
@@ -19,7 +19,9 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	libcommon "github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/log/v3"
@@ -66,7 +68,8 @@ func (a *ApiHandler) GetEth1V1BuilderStatesExpectedWithdrawals(w http.ResponseWr
 		return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, errors.New("beacon node is syncing"))
 	}
 	if root == headRoot {
-		return newBeaconResponse(state.ExpectedWithdrawals(a.syncedData.HeadState(), state.Epoch(a.syncedData.HeadState()))).WithFinalized(false), nil
+		withdrawals, _ := state.ExpectedWithdrawals(a.syncedData.HeadState(), state.Epoch(a.syncedData.HeadState()))
+		return newBeaconResponse(withdrawals).WithFinalized(false), nil
 	}
 	lookAhead := 1024
 	for currSlot := *slot + 1; currSlot < *slot+uint64(lookAhead); currSlot++ {
@@ -98,10 +101,20 @@ func (a *ApiHandler) PostEthV1BuilderRegisterValidator(w http.ResponseWriter, r
 	if len(registerReq) == 0 {
 		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("empty request"))
 	}
+	gasLimits := make([]uint64, len(registerReq))
+	for i, v := range registerReq {
+		gasLimit, err := strconv.ParseUint(v.Message.GasLimit, 10, 64)
+		if err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid gas_limit %q: %w", v.Message.GasLimit, err))
+		}
+		gasLimits[i] = gasLimit
+	}
 	if err := a.builderClient.RegisterValidator(r.Context(), registerReq); err != nil {
 		return nil, beaconhttp.NewEndpointError(http.StatusInternalServerError, err)
 	}
-	for _, v := range registerReq {
+	for i, v := range registerReq {
+		// bids are checked against the registered gas limit
+		a.validatorParams.SetGasLimit(v.Message.PubKey, gasLimits[i])
 		a.logger.Debug("[Caplin] Registered new validator", "fee_recipient", v.Message.FeeRecipient)
 	}
 	log.Info("Registered new validator", "count", len(registerReq))
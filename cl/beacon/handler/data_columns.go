@@ -0,0 +1,88 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+)
+
+// GetEthV1DebugBeaconDataColumnSidecars returns the data column sidecars we custody for a block, optionally filtered by column index.
+func (a *ApiHandler) GetEthV1DebugBeaconDataColumnSidecars(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockId, err := beaconhttp.BlockIdFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	blockRoot, err := a.rootFromBlockId(ctx, tx, blockId)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, errors.New("block not found"))
+	}
+	strIdxs, err := beaconhttp.StringListFromQueryParams(r, "indices")
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []uint64
+	if len(strIdxs) == 0 {
+		if indices, err = a.dataColumnStore.ColumnSidecarIndices(ctx, *slot, blockRoot); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, idx := range strIdxs {
+			i, err := strconv.ParseUint(idx, 10, 64)
+			if err != nil {
+				return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+			}
+			if i >= a.beaconChainCfg.NumberOfColumns {
+				return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("column index out of range"))
+			}
+			indices = append(indices, i)
+		}
+	}
+
+	resp := solid.NewDynamicListSSZ[*cltypes.DataColumnSidecar](int(a.beaconChainCfg.NumberOfColumns))
+	for _, idx := range indices {
+		sidecar, found, err := a.dataColumnStore.ReadColumnSidecar(ctx, *slot, blockRoot, idx)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			resp.Append(sidecar)
+		}
+	}
+	return beaconhttp.NewBeaconResponse(resp).WithVersion(a.beaconChainCfg.GetCurrentStateVersion(*slot / a.beaconChainCfg.SlotsPerEpoch)), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+)
+
+var errGossipTracerDisabled = errors.New("gossip tracer is not enabled")
+
+// GetCaplinGossipPeerScores returns the gossipsub score components of every scored peer, lowest score first.
+func (a *ApiHandler) GetCaplinGossipPeerScores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	if a.gossipTracer == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, errGossipTracerDisabled)
+	}
+	return newBeaconResponse(a.gossipTracer.PeerScores()), nil
+}
+
+// GetCaplinGossipTopics returns the message counters and rates of every joined topic.
+func (a *ApiHandler) GetCaplinGossipTopics(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	if a.gossipTracer == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, errGossipTracerDisabled)
+	}
+	return newBeaconResponse(a.gossipTracer.Topics()), nil
+}
+
+// GetCaplinGossipValidation returns the accept/ignore/reject counts of every gossip service.
+func (a *ApiHandler) GetCaplinGossipValidation(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	if a.gossipTracer == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, errGossipTracerDisabled)
+	}
+	return newBeaconResponse(a.gossipTracer.Validation()), nil
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
 	"github.com/erigontech/erigon/cl/phase1/network/services"
 	"github.com/erigontech/erigon/cl/pool"
+	"github.com/erigontech/erigon/cl/sentinel/gossip_tracer"
 	"github.com/erigontech/erigon/cl/utils/eth_clock"
 	"github.com/erigontech/erigon/cl/validator/attestation_producer"
 	"github.com/erigontech/erigon/cl/validator/committee_subscription"
@@ -74,6 +75,7 @@ type ApiHandler struct {
 	stateReader     *historical_states_reader.HistoricalStatesReader
 	sentinel        sentinel.SentinelClient
 	blobStoage      blob_storage.BlobStorage
+	dataColumnStore blob_storage.DataColumnStorage
 	caplinSnapshots *freezeblocks.CaplinSnapshots
 
 	version string // Node's version
@@ -107,6 +109,7 @@ type ApiHandler struct {
 	proposerSlashingService          services.ProposerSlashingService
 	builderClient                    builder.BuilderClient
 	validatorsMonitor                monitor.ValidatorMonitor
+	gossipTracer                     *gossip_tracer.GossipTracer
 }
 
 func NewApiHandler(
@@ -125,6 +128,7 @@ func NewApiHandler(
 	routerCfg *beacon_router_configuration.RouterConfiguration,
 	emitters *beaconevents.EventEmitter,
 	blobStoage blob_storage.BlobStorage,
+	dataColumnStore blob_storage.DataColumnStorage,
 	caplinSnapshots *freezeblocks.CaplinSnapshots,
 	validatorParams *validator_params.ValidatorParams,
 	attestationProducer attestation_producer.AttestationDataProducer,
@@ -141,6 +145,7 @@ func NewApiHandler(
 	proposerSlashingService services.ProposerSlashingService,
 	builderClient builder.BuilderClient,
 	validatorMonitor monitor.ValidatorMonitor,
+	gossipTracer *gossip_tracer.GossipTracer,
 ) *ApiHandler {
 	blobBundles, err := lru.New[common.Bytes48, BlobBundle]("blobs", maxBlobBundleCacheSize)
 	if err != nil {
@@ -167,6 +172,7 @@ func NewApiHandler(
 		routerCfg:                        routerCfg,
 		emitters:                         emitters,
 		blobStoage:                       blobStoage,
+		dataColumnStore:                  dataColumnStore,
 		caplinSnapshots:                  caplinSnapshots,
 		attestationProducer:              attestationProducer,
 		blobBundles:                      blobBundles,
@@ -183,6 +189,7 @@ func NewApiHandler(
 		proposerSlashingService:          proposerSlashingService,
 		builderClient:                    builderClient,
 		validatorsMonitor:                validatorMonitor,
+		gossipTracer:                     gossipTracer,
 	}
 }
 
@@ -203,6 +210,23 @@ func (a *ApiHandler) init() {
 			r.Get("/validator_inclusion/{epoch}/{validator_id}", beaconhttp.HandleEndpointFunc(a.GetLighthouseValidatorInclusion))
 		})
 	}
+	if a.routerCfg.Caplin {
+		r.Route("/caplin/validator_monitor", func(r chi.Router) {
+			r.Get("/validators", beaconhttp.HandleEndpointFunc(a.GetCaplinValidatorMonitorValidators))
+			r.Post("/validators", beaconhttp.HandleEndpointFunc(a.PostCaplinValidatorMonitorValidators))
+			r.Get("/reports/{validator_id}", beaconhttp.HandleEndpointFunc(a.GetCaplinValidatorMonitorReports))
+			r.Post("/summary", beaconhttp.HandleEndpointFunc(a.PostCaplinValidatorMonitorSummary))
+		})
+		r.Route("/caplin/debug", func(r chi.Router) {
+			r.Get("/states/{slot}", beaconhttp.HandleEndpointFunc(a.GetCaplinDebugStateAtSlot))
+			r.Get("/state_diff", beaconhttp.HandleEndpointFunc(a.GetCaplinDebugStateDiff))
+		})
+		r.Route("/caplin/gossip", func(r chi.Router) {
+			r.Get("/peer_scores", beaconhttp.HandleEndpointFunc(a.GetCaplinGossipPeerScores))
+			r.Get("/topics", beaconhttp.HandleEndpointFunc(a.GetCaplinGossipTopics))
+			r.Get("/validation", beaconhttp.HandleEndpointFunc(a.GetCaplinGossipValidation))
+		})
+	}
 	r.Route("/eth", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			if a.routerCfg.Builder {
@@ -225,6 +249,7 @@ func (a *ApiHandler) init() {
 
 			if a.routerCfg.Debug {
 				r.Get("/debug/fork_choice", a.GetEthV1DebugBeaconForkChoice)
+				r.Get("/debug/beacon/data_column_sidecars/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1DebugBeaconDataColumnSidecars))
 			}
 			if a.routerCfg.Config {
 				r.Route("/config", func(r chi.Router) {
@@ -243,6 +268,7 @@ func (a *ApiHandler) init() {
 						r.Get("/blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconRewardsBlocks))
 						r.Post("/attestations/{epoch}", beaconhttp.HandleEndpointFunc(a.PostEthV1BeaconRewardsAttestations))
 					})
+					r.Get("/deposit_snapshot", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconDepositSnapshot))
 					r.Route("/headers", func(r chi.Router) {
 						r.Get("/", beaconhttp.HandleEndpointFunc(a.getHeaders))
 						r.Get("/{block_id}", beaconhttp.HandleEndpointFunc(a.getHeader))
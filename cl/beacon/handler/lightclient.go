@@ -24,6 +24,7 @@ import (
 	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
 )
 
 func (a *ApiHandler) GetEthV1BeaconLightClientBootstrap(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
@@ -101,27 +102,36 @@ func (a *ApiHandler) GetEthV1BeaconLightClientUpdates(w http.ResponseWriter, r *
 
 	resp := []interface{}{}
 	endPeriod := *startPeriod + *count
-	currentSlot := a.ethClock.GetCurrentSlot()
-	if endPeriod > a.beaconChainCfg.SyncCommitteePeriod(currentSlot) {
-		endPeriod = a.beaconChainCfg.SyncCommitteePeriod(currentSlot)
+	currentPeriod := a.beaconChainCfg.SyncCommitteePeriod(a.ethClock.GetCurrentSlot())
+	if endPeriod > currentPeriod+1 {
+		endPeriod = currentPeriod + 1
 	}
 
-	notFoundPrev := false
-	// Fetch from [start_period, start_period + count]
-	for i := *startPeriod; i <= endPeriod; i++ {
-		respUpdate := map[string]interface{}{}
-		update, has := a.forkchoiceStore.GetLightClientUpdate(i)
-		if !has {
-			notFoundPrev = true
-			continue
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Fetch from [start_period, start_period + count), the updates must be consecutive so we stop at the first gap.
+	for period := *startPeriod; period < endPeriod; period++ {
+		inMemory, _ := a.forkchoiceStore.GetLightClientUpdate(period)
+		update, err := lightclient_utils.ReadBestLightClientUpdate(tx, a.beaconChainCfg, period, inMemory)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		if notFoundPrev {
-			resp = []interface{}{}
-			notFoundPrev = false
+		if update == nil {
+			if len(resp) > 0 {
+				break
+			}
+			continue
 		}
-		respUpdate["data"] = update
-		respUpdate["version"] = clparams.ClVersionToString(update.AttestedHeader.Version())
-		resp = append(resp, respUpdate)
+		resp = append(resp, map[string]interface{}{
+			"data":    update,
+			"version": clparams.ClVersionToString(update.AttestedHeader.Version()),
+		})
 	}
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -24,11 +24,11 @@ import (
 	"sort"
 
 	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/beacon/rewards"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	state_accessors "github.com/erigontech/erigon/cl/persistence/state"
-	"github.com/erigontech/erigon/cl/utils"
 )
 
 type blockRewardsResponse struct {
@@ -196,7 +196,7 @@ func (a *ApiHandler) PostEthV1BeaconRewardsSyncCommittees(w http.ResponseWriter,
 		}
 	}
 	committee := syncCommittee.GetCommittee()
-	rewards := make([]syncCommitteeReward, 0, len(committee))
+	committeeRewards := make([]syncCommitteeReward, 0, len(committee))
 
 	syncAggregate := blk.Block.Body.SyncAggregate
 
@@ -206,7 +206,7 @@ func (a *ApiHandler) PostEthV1BeaconRewardsSyncCommittees(w http.ResponseWriter,
 	}
 	// validator index -> accumulated rewards
 	accumulatedRewards := map[uint64]int64{}
-	participantReward := int64(a.syncParticipantReward(totalActiveBalance))
+	participantReward := int64(rewards.SyncParticipantReward(a.beaconChainCfg, totalActiveBalance))
 
 	for committeeIdx, v := range committee {
 		idx, ok, err := state_accessors.ReadValidatorIndexByPublicKey(tx, v)
@@ -228,22 +228,13 @@ func (a *ApiHandler) PostEthV1BeaconRewardsSyncCommittees(w http.ResponseWriter,
 		accumulatedRewards[idx] -= participantReward
 	}
 	for idx, reward := range accumulatedRewards {
-		rewards = append(rewards, syncCommitteeReward{
+		committeeRewards = append(committeeRewards, syncCommitteeReward{
 			ValidatorIndex: idx,
 			Reward:         reward,
 		})
 	}
-	sort.Slice(rewards, func(i, j int) bool {
-		return rewards[i].ValidatorIndex < rewards[j].ValidatorIndex
+	sort.Slice(committeeRewards, func(i, j int) bool {
+		return committeeRewards[i].ValidatorIndex < committeeRewards[j].ValidatorIndex
 	})
-	return newBeaconResponse(rewards).WithFinalized(isFinalized).WithOptimistic(a.forkchoiceStore.IsRootOptimistic(root)), nil
-}
-
-func (a *ApiHandler) syncParticipantReward(activeBalance uint64) uint64 {
-	activeBalanceSqrt := utils.IntegerSquareRoot(activeBalance)
-	totalActiveIncrements := activeBalance / a.beaconChainCfg.EffectiveBalanceIncrement
-	baseRewardPerInc := a.beaconChainCfg.EffectiveBalanceIncrement * a.beaconChainCfg.BaseRewardFactor / activeBalanceSqrt
-	totalBaseRewards := baseRewardPerInc * totalActiveIncrements
-	maxParticipantRewards := totalBaseRewards * a.beaconChainCfg.SyncRewardWeight / a.beaconChainCfg.WeightDenominator / a.beaconChainCfg.SlotsPerEpoch
-	return maxParticipantRewards / a.beaconChainCfg.SyncCommitteeSize
+	return newBeaconResponse(committeeRewards).WithFinalized(isFinalized).WithOptimistic(a.forkchoiceStore.IsRootOptimistic(root)), nil
 }
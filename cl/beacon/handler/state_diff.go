@@ -0,0 +1,152 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/beacon/statediff"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/transition"
+)
+
+// stateAtSlot returns the canonical state at slot, which may also be a slot without a block. Recent states are taken
+// from fork choice, older ones are rebuilt from the archive.
+func (a *ApiHandler) stateAtSlot(ctx context.Context, tx kv.Tx, slot uint64) (*state.CachingBeaconState, error) {
+	if slot > a.ethClock.GetCurrentSlot() {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("slot %d is in the future", slot))
+	}
+	if slot >= a.forkchoiceStore.LowestAvailableSlot() {
+		blockSlot := slot
+		blockRoot := common.Hash{}
+		for ; blockSlot >= a.forkchoiceStore.LowestAvailableSlot(); blockSlot-- {
+			var err error
+			if blockRoot, err = beacon_indicies.ReadCanonicalBlockRoot(tx, blockSlot); err != nil {
+				return nil, err
+			}
+			if blockRoot != (common.Hash{}) || blockSlot == 0 {
+				break
+			}
+		}
+		if blockRoot != (common.Hash{}) {
+			s, err := a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
+			if err != nil {
+				return nil, err
+			}
+			if s != nil {
+				if s.Slot() < slot {
+					if err := transition.DefaultMachine.ProcessSlots(s, slot); err != nil {
+						return nil, err
+					}
+				}
+				return s, nil
+			}
+		}
+	}
+	if a.stateReader == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state at slot %d", slot))
+	}
+	s, err := a.stateReader.ReadHistoricalStateAtSlot(ctx, tx, slot)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state at slot %d", slot))
+	}
+	return s, nil
+}
+
+func slotFromRequest(r *http.Request) (uint64, error) {
+	slot, err := strconv.ParseUint(chi.URLParam(r, "slot"), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid path variable: {slot}")
+	}
+	return slot, nil
+}
+
+// GetCaplinDebugStateAtSlot returns the full state at any slot, including slots without a block.
+func (a *ApiHandler) GetCaplinDebugStateAtSlot(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+	slot, err := slotFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, err := a.stateAtSlot(ctx, tx, slot)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(s).
+		WithFinalized(slot <= a.forkchoiceStore.FinalizedSlot()).
+		WithVersion(s.Version()), nil
+}
+
+// GetCaplinDebugStateDiff returns the changes to validators, balances and participation flags between the states at
+// from_slot and to_slot.
+func (a *ApiHandler) GetCaplinDebugStateDiff(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+	fromSlot, err := beaconhttp.Uint64FromQueryParams(r, "from_slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	toSlot, err := beaconhttp.Uint64FromQueryParams(r, "to_slot")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if fromSlot == nil || toSlot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("from_slot and to_slot are required"))
+	}
+	if *fromSlot > *toSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, statediff.ErrSlotsNotOrdered)
+	}
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	from, err := a.stateAtSlot(ctx, tx, *fromSlot)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.stateAtSlot(ctx, tx, *toSlot)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := statediff.Compute(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(diff).
+		WithFinalized(*toSlot <= a.forkchoiceStore.FinalizedSlot()).
+		WithVersion(to.Version()), nil
+}
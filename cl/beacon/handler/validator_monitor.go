@@ -0,0 +1,164 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/monitor"
+)
+
+// ValidatorMonitorSummary aggregates the epoch reports of a monitored validator over a range of epochs.
+type ValidatorMonitorSummary struct {
+	ValidatorIndex              uint64  `json:"validator_index,string"`
+	Epochs                      uint64  `json:"epochs,string"`
+	MissedAttestations          uint64  `json:"missed_attestations,string"`
+	AverageInclusionDistance    float64 `json:"average_inclusion_distance"`
+	ProposalsScheduled          uint64  `json:"proposals_scheduled,string"`
+	ProposalsIncluded           uint64  `json:"proposals_included,string"`
+	SyncCommitteeParticipations uint64  `json:"sync_committee_participations,string"`
+	SyncCommitteeMisses         uint64  `json:"sync_committee_misses,string"`
+	TotalReward                 int64   `json:"total_reward,string"`
+}
+
+func (a *ApiHandler) GetCaplinValidatorMonitorValidators(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return newBeaconResponse(a.validatorsMonitor.TrackedValidators()), nil
+}
+
+func (a *ApiHandler) PostCaplinValidatorMonitorValidators(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	vids, err := a.validatorMonitorIndiciesFromBody(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, vid := range vids {
+		a.validatorsMonitor.ObserveValidator(vid)
+	}
+	return newBeaconResponse(a.validatorsMonitor.TrackedValidators()), nil
+}
+
+func (a *ApiHandler) GetCaplinValidatorMonitorReports(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	validatorId, err := beaconhttp.StringFromRequest(r, "validator_id")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	fromEpoch, toEpoch, err := a.validatorMonitorEpochRange(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	vids, err := parseQueryValidatorIndicies(tx, []string{validatorId})
+	if err != nil {
+		return nil, err
+	}
+	reports, err := a.validatorsMonitor.ReadReports(r.Context(), vids[0], fromEpoch, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(reports), nil
+}
+
+func (a *ApiHandler) PostCaplinValidatorMonitorSummary(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	fromEpoch, toEpoch, err := a.validatorMonitorEpochRange(r)
+	if err != nil {
+		return nil, err
+	}
+	vids, err := a.validatorMonitorIndiciesFromBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(vids) == 0 {
+		vids = a.validatorsMonitor.TrackedValidators()
+	}
+	summaries := make([]ValidatorMonitorSummary, 0, len(vids))
+	for _, vid := range vids {
+		reports, err := a.validatorsMonitor.ReadReports(r.Context(), vid, fromEpoch, toEpoch)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summarizeValidatorReports(vid, reports))
+	}
+	return newBeaconResponse(summaries), nil
+}
+
+func summarizeValidatorReports(vid uint64, reports []*monitor.ValidatorEpochReport) ValidatorMonitorSummary {
+	summary := ValidatorMonitorSummary{ValidatorIndex: vid, Epochs: uint64(len(reports))}
+	var included, inclusionDistance uint64
+	for _, report := range reports {
+		if report.AttestationIncluded {
+			included++
+			inclusionDistance += report.InclusionDistance
+		} else {
+			summary.MissedAttestations++
+		}
+		summary.ProposalsScheduled += report.ProposalsScheduled
+		summary.ProposalsIncluded += report.ProposalsIncluded
+		summary.SyncCommitteeParticipations += report.SyncCommitteeParticipations
+		summary.SyncCommitteeMisses += report.SyncCommitteeMisses
+		summary.TotalReward += report.TotalReward()
+	}
+	if included > 0 {
+		summary.AverageInclusionDistance = float64(inclusionDistance) / float64(included)
+	}
+	return summary
+}
+
+// validatorMonitorEpochRange parses the from_epoch and to_epoch query parameters, defaulting to every epoch up to
+// the current one.
+func (a *ApiHandler) validatorMonitorEpochRange(r *http.Request) (uint64, uint64, error) {
+	fromEpoch, err := beaconhttp.Uint64FromQueryParams(r, "from_epoch")
+	if err != nil {
+		return 0, 0, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	toEpoch, err := beaconhttp.Uint64FromQueryParams(r, "to_epoch")
+	if err != nil {
+		return 0, 0, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	from, to := uint64(0), a.ethClock.GetCurrentEpoch()
+	if fromEpoch != nil {
+		from = *fromEpoch
+	}
+	if toEpoch != nil {
+		to = *toEpoch
+	}
+	if from > to {
+		return 0, 0, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("from_epoch is after to_epoch"))
+	}
+	return from, to, nil
+}
+
+// validatorMonitorIndiciesFromBody decodes a list of validator indices or public keys from the request body.
+func (a *ApiHandler) validatorMonitorIndiciesFromBody(r *http.Request) ([]uint64, error) {
+	ids := []string{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+		}
+	}
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return parseQueryValidatorIndicies(tx, ids)
+}
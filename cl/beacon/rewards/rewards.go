@@ -0,0 +1,336 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// IdealReward is the reward a validator would have earned with a perfect attestation.
+type IdealReward struct {
+	EffectiveBalance int64 `json:"effective_balance,string"`
+	Head             int64 `json:"head,string"`
+	Target           int64 `json:"target,string"`
+	Source           int64 `json:"source,string"`
+	InclusionDelay   int64 `json:"inclusion_delay,string"`
+	Inactivity       int64 `json:"inactivity,string"`
+}
+
+// TotalReward is the reward a validator actually earned.
+type TotalReward struct {
+	ValidatorIndex int64 `json:"validator_index,string"`
+	Head           int64 `json:"head,string"`
+	Target         int64 `json:"target,string"`
+	Source         int64 `json:"source,string"`
+	InclusionDelay int64 `json:"inclusion_delay,string"`
+	Inactivity     int64 `json:"inactivity,string"`
+}
+
+// AttestationsRewards are the ideal and realized attestation rewards of an epoch.
+type AttestationsRewards struct {
+	IdealRewards []IdealReward `json:"ideal_rewards"`
+	TotalRewards []TotalReward `json:"total_rewards"`
+}
+
+// IsInactivityLeaking returns whether the chain is in an inactivity leak at epoch.
+func IsInactivityLeaking(cfg *clparams.BeaconChainConfig, epoch uint64, finalityCheckpoint solid.Checkpoint) bool {
+	prevEpoch := epoch
+	if epoch > 0 {
+		prevEpoch = epoch - 1
+	}
+	return prevEpoch-finalityCheckpoint.Epoch > cfg.MinEpochsToInactivityPenalty
+}
+
+// BaseReward returns the base reward of a validator with the given effective balance.
+func BaseReward(cfg *clparams.BeaconChainConfig, version clparams.StateVersion, effectiveBalance, activeBalanceRoot uint64) uint64 {
+	basePerIncrement := cfg.EffectiveBalanceIncrement * cfg.BaseRewardFactor / activeBalanceRoot
+	if version != clparams.Phase0Version {
+		return (effectiveBalance / cfg.EffectiveBalanceIncrement) * basePerIncrement
+	}
+	return effectiveBalance * cfg.BaseRewardFactor / activeBalanceRoot / cfg.BaseRewardsPerEpoch
+}
+
+// ComputeAttestationsRewardsForAltair computes the attestation rewards of the epoch before epoch, for all validators
+// or only for filterIndicies if not empty.
+func ComputeAttestationsRewardsForAltair(cfg *clparams.BeaconChainConfig, validatorSet *solid.ValidatorSet, inactivityScores solid.Uint64ListSSZ, previousParticipation *solid.ParticipationBitList, inactivityLeak bool, filterIndicies []uint64, epoch uint64) (*AttestationsRewards, error) {
+	totalActiveBalance := uint64(0)
+	prevEpoch := uint64(0)
+	if epoch > 0 {
+		prevEpoch = epoch - 1
+	}
+	flagsUnslashedIndiciesSet := state.GetUnslashedIndiciesSet(cfg, prevEpoch, validatorSet, previousParticipation)
+	weights := cfg.ParticipationWeights()
+	flagsTotalBalances := make([]uint64, len(weights))
+
+	validatorSet.Range(func(validatorIndex int, v solid.Validator, l int) bool {
+		if v.Active(epoch) {
+			totalActiveBalance += v.EffectiveBalance()
+		}
+
+		for i := range weights {
+			if flagsUnslashedIndiciesSet[i][validatorIndex] {
+				flagsTotalBalances[i] += v.EffectiveBalance()
+			}
+		}
+		return true
+	})
+	version := cfg.GetCurrentStateVersion(epoch)
+	inactivityPenaltyDenominator := cfg.InactivityScoreBias * cfg.GetPenaltyQuotient(version)
+	rewardMultipliers := make([]uint64, len(weights))
+	for i := range weights {
+		rewardMultipliers[i] = weights[i] * (flagsTotalBalances[i] / cfg.EffectiveBalanceIncrement)
+	}
+
+	rewardDenominator := (totalActiveBalance / cfg.EffectiveBalanceIncrement) * cfg.WeightDenominator
+	var response *AttestationsRewards
+	if len(filterIndicies) > 0 {
+		response = &AttestationsRewards{
+			IdealRewards: make([]IdealReward, 0, len(filterIndicies)),
+			TotalRewards: make([]TotalReward, 0, len(filterIndicies)),
+		}
+	} else {
+		response = &AttestationsRewards{
+			IdealRewards: make([]IdealReward, 0, validatorSet.Length()),
+			TotalRewards: make([]TotalReward, 0, validatorSet.Length()),
+		}
+	}
+	// make a map with the filter indicies
+	totalActiveBalanceSqrt := utils.IntegerSquareRoot(totalActiveBalance)
+
+	fn := func(index uint64, v solid.Validator) error {
+		effectiveBalance := v.EffectiveBalance()
+		baseReward := BaseReward(cfg, version, effectiveBalance, totalActiveBalanceSqrt)
+		// not eligible for rewards? then all empty
+		if !(v.Active(prevEpoch) || (v.Slashed() && prevEpoch+1 < v.WithdrawableEpoch())) {
+			response.IdealRewards = append(response.IdealRewards, IdealReward{EffectiveBalance: int64(effectiveBalance)})
+			response.TotalRewards = append(response.TotalRewards, TotalReward{ValidatorIndex: int64(index)})
+			return nil
+		}
+		idealReward := IdealReward{EffectiveBalance: int64(effectiveBalance)}
+		totalReward := TotalReward{ValidatorIndex: int64(index)}
+		if !inactivityLeak {
+			idealReward.Head = int64(baseReward * rewardMultipliers[cfg.TimelyHeadFlagIndex] / rewardDenominator)
+			idealReward.Target = int64(baseReward * rewardMultipliers[cfg.TimelyTargetFlagIndex] / rewardDenominator)
+			idealReward.Source = int64(baseReward * rewardMultipliers[cfg.TimelySourceFlagIndex] / rewardDenominator)
+		}
+		// Note: for altair, we don't have the inclusion delay, always 0.
+		for flagIdx := range weights {
+			if flagsUnslashedIndiciesSet[flagIdx][index] {
+				if flagIdx == int(cfg.TimelyHeadFlagIndex) {
+					totalReward.Head = idealReward.Head
+				} else if flagIdx == int(cfg.TimelyTargetFlagIndex) {
+					totalReward.Target = idealReward.Target
+				} else if flagIdx == int(cfg.TimelySourceFlagIndex) {
+					totalReward.Source = idealReward.Source
+				}
+			} else if flagIdx != int(cfg.TimelyHeadFlagIndex) {
+				down := -int64(baseReward * weights[flagIdx] / cfg.WeightDenominator)
+				if flagIdx == int(cfg.TimelyHeadFlagIndex) {
+					totalReward.Head = down
+				} else if flagIdx == int(cfg.TimelyTargetFlagIndex) {
+					totalReward.Target = down
+				} else if flagIdx == int(cfg.TimelySourceFlagIndex) {
+					totalReward.Source = down
+				}
+			}
+		}
+		if !flagsUnslashedIndiciesSet[cfg.TimelyTargetFlagIndex][index] {
+			inactivityScore := inactivityScores.Get(int(index))
+			totalReward.Inactivity = -int64((effectiveBalance * inactivityScore) / inactivityPenaltyDenominator)
+		}
+		response.IdealRewards = append(response.IdealRewards, idealReward)
+		response.TotalRewards = append(response.TotalRewards, totalReward)
+		return nil
+	}
+
+	if len(filterIndicies) > 0 {
+		for _, index := range filterIndicies {
+			if err := fn(index, validatorSet.Get(int(index))); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for index := uint64(0); index < uint64(validatorSet.Length()); index++ {
+			if err := fn(index, validatorSet.Get(int(index))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return response, nil
+}
+
+// ComputeAttestationsRewardsForPhase0 is the phase0 equivalent of ComputeAttestationsRewardsForAltair.
+func ComputeAttestationsRewardsForPhase0(cfg *clparams.BeaconChainConfig, validatorSet *solid.ValidatorSet, finalityDelay, activeBalance uint64, previousParticipation *solid.ParticipationBitList, inactivityLeak bool, filterIndicies []uint64, epoch uint64) (*AttestationsRewards, error) {
+	response := &AttestationsRewards{}
+	beaconConfig := cfg
+	if epoch == beaconConfig.GenesisEpoch {
+		return response, nil
+	}
+	prevEpoch := uint64(0)
+	if epoch > 0 {
+		prevEpoch = epoch - 1
+	}
+	if len(filterIndicies) > 0 {
+		response = &AttestationsRewards{
+			IdealRewards: make([]IdealReward, 0, len(filterIndicies)),
+			TotalRewards: make([]TotalReward, 0, len(filterIndicies)),
+		}
+	} else {
+		response = &AttestationsRewards{
+			IdealRewards: make([]IdealReward, 0, validatorSet.Length()),
+			TotalRewards: make([]TotalReward, 0, validatorSet.Length()),
+		}
+	}
+
+	rewardDenominator := activeBalance / beaconConfig.EffectiveBalanceIncrement
+	var unslashedMatchingSourceBalanceIncrements, unslashedMatchingTargetBalanceIncrements, unslashedMatchingHeadBalanceIncrements uint64
+	var err error
+
+	validatorSet.Range(func(i int, v solid.Validator, _ int) bool {
+		if v.Slashed() {
+			return true
+		}
+		var previousMatchingSourceAttester, previousMatchingTargetAttester, previousMatchingHeadAttester bool
+		previousParticipation := cltypes.ParticipationFlags(previousParticipation.Get(i))
+		previousMatchingHeadAttester = previousParticipation.HasFlag(int(beaconConfig.TimelyHeadFlagIndex))
+		previousMatchingTargetAttester = previousParticipation.HasFlag(int(beaconConfig.TimelyTargetFlagIndex))
+		previousMatchingSourceAttester = previousParticipation.HasFlag(int(beaconConfig.TimelySourceFlagIndex))
+
+		if previousMatchingSourceAttester {
+			unslashedMatchingSourceBalanceIncrements += v.EffectiveBalance()
+		}
+		if previousMatchingTargetAttester {
+			unslashedMatchingTargetBalanceIncrements += v.EffectiveBalance()
+		}
+		if previousMatchingHeadAttester {
+			unslashedMatchingHeadBalanceIncrements += v.EffectiveBalance()
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Then compute their total increment.
+	unslashedMatchingSourceBalanceIncrements /= beaconConfig.EffectiveBalanceIncrement
+	unslashedMatchingTargetBalanceIncrements /= beaconConfig.EffectiveBalanceIncrement
+	unslashedMatchingHeadBalanceIncrements /= beaconConfig.EffectiveBalanceIncrement
+	totalActiveBalanceSqrt := utils.IntegerSquareRoot(activeBalance)
+	fn := func(index uint64, currentValidator solid.Validator) error {
+		baseReward := BaseReward(cfg, clparams.Phase0Version, currentValidator.EffectiveBalance(), totalActiveBalanceSqrt)
+
+		if err != nil {
+			return err
+		}
+		var previousMatchingSourceAttester, previousMatchingTargetAttester, previousMatchingHeadAttester bool
+
+		previousParticipation := cltypes.ParticipationFlags(previousParticipation.Get(int(index)))
+		previousMatchingHeadAttester = previousParticipation.HasFlag(int(beaconConfig.TimelyHeadFlagIndex))
+		previousMatchingTargetAttester = previousParticipation.HasFlag(int(beaconConfig.TimelyTargetFlagIndex))
+		previousMatchingSourceAttester = previousParticipation.HasFlag(int(beaconConfig.TimelySourceFlagIndex))
+
+		totalReward := TotalReward{ValidatorIndex: int64(index)}
+		idealReward := IdealReward{EffectiveBalance: int64(currentValidator.EffectiveBalance())}
+
+		// TODO: check inclusion delay
+		// if !currentValidator.Slashed() && previousMatchingSourceAttester {
+		// 	var attestation *solid.PendingAttestation
+		// 	if attestation, err = s.ValidatorMinPreviousInclusionDelayAttestation(int(index)); err != nil {
+		// 		return err
+		// 	}
+		// 	proposerReward := (baseReward / beaconConfig.ProposerRewardQuotient)
+		// 	maxAttesterReward := baseReward - proposerReward
+		// 	idealReward.InclusionDelay = int64(maxAttesterReward / attestation.InclusionDelay())
+		// 	totalReward.InclusionDelay = idealReward.InclusionDelay
+		// }
+		// if it is not eligible for rewards, then do not continue further
+		if !(currentValidator.Active(prevEpoch) || (currentValidator.Slashed() && prevEpoch+1 < currentValidator.WithdrawableEpoch())) {
+			response.IdealRewards = append(response.IdealRewards, idealReward)
+			response.TotalRewards = append(response.TotalRewards, totalReward)
+			return nil
+		}
+		if inactivityLeak {
+			idealReward.Source = int64(baseReward)
+			idealReward.Target = int64(baseReward)
+			idealReward.Head = int64(baseReward)
+		} else {
+			idealReward.Source = int64(baseReward * unslashedMatchingSourceBalanceIncrements / rewardDenominator)
+			idealReward.Target = int64(baseReward * unslashedMatchingTargetBalanceIncrements / rewardDenominator)
+			idealReward.Head = int64(baseReward * unslashedMatchingHeadBalanceIncrements / rewardDenominator)
+		}
+		// we can use a multiplier to account for all attesting
+		var attested, missed uint64
+		if currentValidator.Slashed() {
+			missed = 3
+		} else {
+			if previousMatchingSourceAttester {
+				attested++
+				totalReward.Source = idealReward.Source
+			}
+			if previousMatchingTargetAttester {
+				attested++
+				totalReward.Target = idealReward.Target
+			}
+			if previousMatchingHeadAttester {
+				attested++
+				totalReward.Head = idealReward.Head
+			}
+			missed = 3 - attested
+		}
+		// process inactivities
+		if inactivityLeak {
+			proposerReward := baseReward / beaconConfig.ProposerRewardQuotient
+			totalReward.Inactivity = -int64(beaconConfig.BaseRewardsPerEpoch*baseReward - proposerReward)
+			if currentValidator.Slashed() || !previousMatchingTargetAttester {
+				totalReward.Inactivity -= int64(currentValidator.EffectiveBalance() * finalityDelay / beaconConfig.InactivityPenaltyQuotient)
+			}
+		}
+		totalReward.Inactivity -= int64(baseReward * missed)
+		response.IdealRewards = append(response.IdealRewards, idealReward)
+		response.TotalRewards = append(response.TotalRewards, totalReward)
+		return nil
+	}
+	if len(filterIndicies) > 0 {
+		for _, index := range filterIndicies {
+			v := validatorSet.Get(int(index))
+			if err := fn(index, v); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for index := uint64(0); index < uint64(validatorSet.Length()); index++ {
+			v := validatorSet.Get(int(index))
+			if err := fn(index, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return response, nil
+}
+
+// SyncParticipantReward returns the reward of a sync committee member for one slot.
+func SyncParticipantReward(cfg *clparams.BeaconChainConfig, activeBalance uint64) uint64 {
+	activeBalanceSqrt := utils.IntegerSquareRoot(activeBalance)
+	totalActiveIncrements := activeBalance / cfg.EffectiveBalanceIncrement
+	baseRewardPerInc := cfg.EffectiveBalanceIncrement * cfg.BaseRewardFactor / activeBalanceSqrt
+	totalBaseRewards := baseRewardPerInc * totalActiveIncrements
+	maxParticipantRewards := totalBaseRewards * cfg.SyncRewardWeight / cfg.WeightDenominator / cfg.SlotsPerEpoch
+	return maxParticipantRewards / cfg.SyncCommitteeSize
+}
@@ -0,0 +1,256 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package statediff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/erigontech/erigon-lib/types/clonable"
+	"github.com/erigontech/erigon-lib/types/ssz"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+const (
+	validatorSize = 121
+	// fixed part of the container: from_slot, to_slot, validators_length and the offsets of the 3 lists.
+	stateDiffFixedSize     = 8*3 + 4*3
+	validatorDiffSize      = 8 + validatorSize
+	balanceDiffSize        = 8 + 8
+	participationDiffSize  = 8 + 1 + 1
+	streamingBufferSize    = 1 << 16
+	maxStateDiffListLength = 1 << 40 // VALIDATOR_REGISTRY_LIMIT
+)
+
+var ErrSlotsNotOrdered = errors.New("from slot is after to slot")
+
+// ValidatorDiff is the new record of a validator which changed (or was added) between the two states.
+type ValidatorDiff struct {
+	Index     uint64          `json:"index,string"`
+	Validator solid.Validator `json:"validator"`
+}
+
+// BalanceDiff is the new balance of a validator whose balance changed between the two states.
+type BalanceDiff struct {
+	Index   uint64 `json:"index,string"`
+	Balance uint64 `json:"balance,string"`
+}
+
+// ParticipationDiff holds the new participation flags of a validator whose participation changed between the two
+// states. It is only populated for Altair and later states.
+type ParticipationDiff struct {
+	Index                      uint64 `json:"index,string"`
+	PreviousEpochParticipation byte   `json:"previous_epoch_participation,string"`
+	CurrentEpochParticipation  byte   `json:"current_epoch_participation,string"`
+}
+
+// StateDiff is the set of changes to validators, balances and participation flags needed to go from the state at
+// FromSlot to the state at ToSlot.
+//
+// It is encoded as the SSZ container
+//
+//	class StateDiff(Container):
+//	    from_slot: uint64
+//	    to_slot: uint64
+//	    validators_length: uint64
+//	    validators: List[ValidatorDiff, VALIDATOR_REGISTRY_LIMIT]
+//	    balances: List[BalanceDiff, VALIDATOR_REGISTRY_LIMIT]
+//	    participations: List[ParticipationDiff, VALIDATOR_REGISTRY_LIMIT]
+//
+// where every list element is fixed-size, so that the encoding can be streamed without being materialized.
+type StateDiff struct {
+	FromSlot         uint64              `json:"from_slot,string"`
+	ToSlot           uint64              `json:"to_slot,string"`
+	ValidatorsLength uint64              `json:"validators_length,string"`
+	Validators       []ValidatorDiff     `json:"validators"`
+	Balances         []BalanceDiff       `json:"balances"`
+	Participations   []ParticipationDiff `json:"participations"`
+}
+
+// Compute computes the diff between from and to.
+func Compute(from, to *state.CachingBeaconState) (*StateDiff, error) {
+	if from.Slot() > to.Slot() {
+		return nil, fmt.Errorf("%w: %d > %d", ErrSlotsNotOrdered, from.Slot(), to.Slot())
+	}
+	diff := &StateDiff{
+		FromSlot:         from.Slot(),
+		ToSlot:           to.Slot(),
+		ValidatorsLength: uint64(to.ValidatorLength()),
+		Validators:       []ValidatorDiff{},
+		Balances:         []BalanceDiff{},
+		Participations:   []ParticipationDiff{},
+	}
+
+	fromValidators, toValidators := from.RawValidatorSet(), to.RawValidatorSet()
+	for i := 0; i < to.ValidatorLength(); i++ {
+		record := toValidators[i*validatorSize : (i+1)*validatorSize]
+		if (i+1)*validatorSize <= len(fromValidators) && bytes.Equal(record, fromValidators[i*validatorSize:(i+1)*validatorSize]) {
+			continue
+		}
+		validator := solid.NewValidator()
+		copy(validator, record)
+		diff.Validators = append(diff.Validators, ValidatorDiff{Index: uint64(i), Validator: validator})
+	}
+
+	fromBalances, toBalances := from.RawBalances(), to.RawBalances()
+	for i := 0; i < len(toBalances)/8; i++ {
+		balance := toBalances[i*8 : (i+1)*8]
+		if (i+1)*8 <= len(fromBalances) && bytes.Equal(balance, fromBalances[i*8:(i+1)*8]) {
+			continue
+		}
+		diff.Balances = append(diff.Balances, BalanceDiff{Index: uint64(i), Balance: binary.LittleEndian.Uint64(balance)})
+	}
+
+	if to.Version() < clparams.AltairVersion {
+		return diff, nil
+	}
+	var fromPrevious, fromCurrent []byte
+	if from.Version() >= clparams.AltairVersion {
+		fromPrevious, fromCurrent = from.RawPreviousEpochParticipation(), from.RawCurrentEpochParticipation()
+	}
+	toPrevious, toCurrent := to.RawPreviousEpochParticipation(), to.RawCurrentEpochParticipation()
+	for i := 0; i < len(toCurrent) && i < len(toPrevious); i++ {
+		if i < len(fromPrevious) && i < len(fromCurrent) && fromPrevious[i] == toPrevious[i] && fromCurrent[i] == toCurrent[i] {
+			continue
+		}
+		diff.Participations = append(diff.Participations, ParticipationDiff{
+			Index:                      uint64(i),
+			PreviousEpochParticipation: toPrevious[i],
+			CurrentEpochParticipation:  toCurrent[i],
+		})
+	}
+	return diff, nil
+}
+
+func (d *StateDiff) EncodingSizeSSZ() int {
+	return stateDiffFixedSize + len(d.Validators)*validatorDiffSize + len(d.Balances)*balanceDiffSize + len(d.Participations)*participationDiffSize
+}
+
+func (d *StateDiff) EncodeSSZ(buf []byte) ([]byte, error) {
+	out := bytes.NewBuffer(buf)
+	out.Grow(d.EncodingSizeSSZ())
+	if err := d.StreamSSZ(out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// StreamSSZ writes the SSZ encoding of the diff to w, one element at a time.
+func (d *StateDiff) StreamSSZ(w io.Writer) error {
+	bw := bufio.NewWriterSize(w, streamingBufferSize)
+	var scratch [validatorDiffSize]byte
+
+	header := scratch[:stateDiffFixedSize]
+	binary.LittleEndian.PutUint64(header, d.FromSlot)
+	binary.LittleEndian.PutUint64(header[8:], d.ToSlot)
+	binary.LittleEndian.PutUint64(header[16:], d.ValidatorsLength)
+	offset := uint32(stateDiffFixedSize)
+	binary.LittleEndian.PutUint32(header[24:], offset)
+	offset += uint32(len(d.Validators) * validatorDiffSize)
+	binary.LittleEndian.PutUint32(header[28:], offset)
+	offset += uint32(len(d.Balances) * balanceDiffSize)
+	binary.LittleEndian.PutUint32(header[32:], offset)
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	for _, v := range d.Validators {
+		binary.LittleEndian.PutUint64(scratch[:], v.Index)
+		copy(scratch[8:], v.Validator)
+		if _, err := bw.Write(scratch[:validatorDiffSize]); err != nil {
+			return err
+		}
+	}
+	for _, b := range d.Balances {
+		binary.LittleEndian.PutUint64(scratch[:], b.Index)
+		binary.LittleEndian.PutUint64(scratch[8:], b.Balance)
+		if _, err := bw.Write(scratch[:balanceDiffSize]); err != nil {
+			return err
+		}
+	}
+	for _, p := range d.Participations {
+		binary.LittleEndian.PutUint64(scratch[:], p.Index)
+		scratch[8] = p.PreviousEpochParticipation
+		scratch[9] = p.CurrentEpochParticipation
+		if _, err := bw.Write(scratch[:participationDiffSize]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (d *StateDiff) DecodeSSZ(buf []byte, _ int) error {
+	if len(buf) < stateDiffFixedSize {
+		return ssz.ErrLowBufferSize
+	}
+	d.FromSlot = binary.LittleEndian.Uint64(buf)
+	d.ToSlot = binary.LittleEndian.Uint64(buf[8:])
+	d.ValidatorsLength = binary.LittleEndian.Uint64(buf[16:])
+	validatorsOffset := binary.LittleEndian.Uint32(buf[24:])
+	balancesOffset := binary.LittleEndian.Uint32(buf[28:])
+	participationsOffset := binary.LittleEndian.Uint32(buf[32:])
+	if validatorsOffset != stateDiffFixedSize || balancesOffset < validatorsOffset ||
+		participationsOffset < balancesOffset || int(participationsOffset) > len(buf) {
+		return ssz.ErrBadOffset
+	}
+
+	validators := buf[validatorsOffset:balancesOffset]
+	balances := buf[balancesOffset:participationsOffset]
+	participations := buf[participationsOffset:]
+	if len(validators)%validatorDiffSize != 0 || len(balances)%balanceDiffSize != 0 || len(participations)%participationDiffSize != 0 {
+		return ssz.ErrBadDynamicLength
+	}
+	if len(validators)/validatorDiffSize > maxStateDiffListLength || len(balances)/balanceDiffSize > maxStateDiffListLength ||
+		len(participations)/participationDiffSize > maxStateDiffListLength {
+		return ssz.ErrTooBigList
+	}
+
+	d.Validators = make([]ValidatorDiff, len(validators)/validatorDiffSize)
+	for i := range d.Validators {
+		elem := validators[i*validatorDiffSize:]
+		d.Validators[i].Index = binary.LittleEndian.Uint64(elem)
+		d.Validators[i].Validator = solid.NewValidator()
+		copy(d.Validators[i].Validator, elem[8:validatorDiffSize])
+	}
+	d.Balances = make([]BalanceDiff, len(balances)/balanceDiffSize)
+	for i := range d.Balances {
+		elem := balances[i*balanceDiffSize:]
+		d.Balances[i] = BalanceDiff{Index: binary.LittleEndian.Uint64(elem), Balance: binary.LittleEndian.Uint64(elem[8:])}
+	}
+	d.Participations = make([]ParticipationDiff, len(participations)/participationDiffSize)
+	for i := range d.Participations {
+		elem := participations[i*participationDiffSize:]
+		d.Participations[i] = ParticipationDiff{
+			Index:                      binary.LittleEndian.Uint64(elem),
+			PreviousEpochParticipation: elem[8],
+			CurrentEpochParticipation:  elem[9],
+		}
+	}
+	return nil
+}
+
+func (d *StateDiff) Clone() clonable.Clonable {
+	return &StateDiff{}
+}
+
+var _ ssz.EncodableSSZ = (*StateDiff)(nil)
@@ -0,0 +1,105 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package statediff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/antiquary/tests"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+func testStateDiff(t *testing.T, pre, post *state.CachingBeaconState) {
+	diff, err := Compute(pre, post)
+	require.NoError(t, err)
+	require.Equal(t, pre.Slot(), diff.FromSlot)
+	require.Equal(t, post.Slot(), diff.ToSlot)
+	require.NotEmpty(t, diff.Balances)
+
+	// the streamed encoding must match the in-memory one and round trip.
+	encoded, err := diff.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, diff.EncodingSizeSSZ())
+	var streamed bytes.Buffer
+	require.NoError(t, diff.StreamSSZ(&streamed))
+	require.Equal(t, encoded, streamed.Bytes())
+	decoded := &StateDiff{}
+	require.NoError(t, decoded.DecodeSSZ(encoded, 0))
+	require.Equal(t, diff, decoded)
+
+	// applying the diff to the validators, balances and participations of pre must give the ones of post.
+	validators := append([]byte{}, pre.RawValidatorSet()...)
+	balances := append([]byte{}, pre.RawBalances()...)
+	for _, v := range decoded.Validators {
+		if int(v.Index)*validatorSize == len(validators) {
+			validators = append(validators, v.Validator...)
+			balances = append(balances, make([]byte, 8)...)
+			continue
+		}
+		copy(validators[v.Index*validatorSize:], v.Validator)
+	}
+	for _, b := range decoded.Balances {
+		binary.LittleEndian.PutUint64(balances[b.Index*8:], b.Balance)
+	}
+	require.Equal(t, post.RawValidatorSet(), validators)
+	require.Equal(t, post.RawBalances(), balances)
+	require.Equal(t, uint64(post.ValidatorLength()), decoded.ValidatorsLength)
+
+	if post.PreviousEpochParticipation() == nil {
+		require.Empty(t, decoded.Participations)
+		return
+	}
+	previous := append([]byte{}, pre.RawPreviousEpochParticipation()...)
+	current := append([]byte{}, pre.RawCurrentEpochParticipation()...)
+	for _, p := range decoded.Participations {
+		for int(p.Index) >= len(current) {
+			previous, current = append(previous, 0), append(current, 0)
+		}
+		previous[p.Index], current[p.Index] = p.PreviousEpochParticipation, p.CurrentEpochParticipation
+	}
+	require.Equal(t, post.RawPreviousEpochParticipation(), previous)
+	require.Equal(t, post.RawCurrentEpochParticipation(), current)
+}
+
+func TestStateDiffPhase0(t *testing.T) {
+	_, pre, post := tests.GetPhase0Random()
+	testStateDiff(t, pre, post)
+}
+
+func TestStateDiffBellatrix(t *testing.T) {
+	_, pre, post := tests.GetBellatrixRandom()
+	testStateDiff(t, pre, post)
+}
+
+func TestStateDiffSlotsNotOrdered(t *testing.T) {
+	_, pre, post := tests.GetBellatrixRandom()
+	_, err := Compute(post, pre)
+	require.ErrorIs(t, err, ErrSlotsNotOrdered)
+}
+
+func TestStateDiffDecodeBadOffsets(t *testing.T) {
+	diff := &StateDiff{FromSlot: 1, ToSlot: 2, Balances: []BalanceDiff{{Index: 1, Balance: 2}}}
+	encoded, err := diff.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Error(t, (&StateDiff{}).DecodeSSZ(encoded[:len(encoded)-1], 0))
+	encoded[24]++
+	require.Error(t, (&StateDiff{}).DecodeSSZ(encoded, 0))
+}
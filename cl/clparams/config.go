@@ -26,6 +26,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -39,6 +40,8 @@ import (
 
 var LatestStateFileName = "latest.ssz_snappy"
 
+var DepositTreeFileName = "deposit_tree.bin"
+
 type CaplinConfig struct {
 	Backfilling               bool
 	BlobBackfilling           bool
@@ -48,11 +51,37 @@ type CaplinConfig struct {
 	NetworkId                 NetworkType
 	// DisableCheckpointSync is optional and is used to disable checkpoint sync used by default in the node
 	DisabledCheckpointSync bool
+	// CheckpointSyncStateFile is optional, the node starts from this SSZ state instead of fetching it from a beacon API.
+	// CheckpointSyncBlockFile is the optional SSZ signed block the state is checked against.
+	CheckpointSyncStateFile string
+	CheckpointSyncBlockFile string
+	// CheckpointSyncFallback is optional, if no checkpoint sync endpoint is reachable the node starts from the latest local
+	// state (or genesis) instead of failing
+	CheckpointSyncFallback bool
+	// WeakSubjectivityCheckpoint is optional, block_root:epoch that the checkpoint state and the synced chain must agree with
+	WeakSubjectivityCheckpoint string
 	// CaplinMeVRelayUrl is optional and is used to connect to the external builder service.
-	// If it's set, the node will start in builder mode
+	// If it's set, the node will start in builder mode. Multiple relays can be given as a comma separated list.
 	MevRelayUrl string
+	// MevRelayTimeout bounds every call made to a single relay, a slow relay does not delay block production
+	MevRelayTimeout time.Duration
+	// MevBuilderBoostFactor is the percentage applied to builder bids when the validator does not provide builder_boost_factor
+	MevBuilderBoostFactor uint64
 	// EnableValidatorMonitor is used to enable the validator monitor metrics and corresponding logs
 	EnableValidatorMonitor bool
+	// ValidatorMonitorValidators is the set of validator indices tracked by the validator monitor since startup
+	ValidatorMonitorValidators []uint64
+
+	// Built-in validator client
+	EnableValidatorClient bool
+	// ValidatorKeystoresDir and ValidatorPasswordFile are optional and are used to load read-only EIP-2335 keystores
+	ValidatorKeystoresDir string
+	ValidatorPasswordFile string
+	ValidatorFeeRecipient libcommon.Address
+	ValidatorGraffiti     string
+	// KeymanagerAPIAddr is optional, the Keymanager API is served only if it's set
+	KeymanagerAPIAddr      string
+	KeymanagerAPITokenFile string
 
 	// Devnets config
 	CustomConfigPath       string
@@ -66,6 +95,8 @@ type CaplinConfig struct {
 	SentinelPort           uint64
 	SubscribeAllTopics     bool
 	MaxPeerCount           uint64
+	CustodyGroupCount      uint64 // PeerDAS custody groups to sample and serve, 0 means CUSTODY_REQUIREMENT
+	GossipTraceFile        string // JSON lines trace of the sentinel gossip events, disabled if empty
 	// Erigon Sync
 	LoopBlockLimit uint64
 	// Beacon API router configuration
@@ -84,7 +115,17 @@ func (c CaplinConfig) HaveInvalidDevnetParams() bool {
 }
 
 func (c CaplinConfig) RelayUrlExist() bool {
-	return c.MevRelayUrl != ""
+	return len(c.RelayUrls()) > 0
+}
+
+func (c CaplinConfig) RelayUrls() []string {
+	urls := []string{}
+	for _, u := range strings.Split(c.MevRelayUrl, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
 }
 
 type NetworkType int
@@ -548,10 +589,29 @@ type BeaconChainConfig struct {
 	SamplesPerSlot               uint64 `yaml:"SAMPLES_PER_SLOT" spec:"true" json:"SAMPLES_PER_SLOT,string"`                                 // SamplesPerSlot defines the number of samples per slot.
 	CustodyRequirement           uint64 `yaml:"CUSTODY_REQUIREMENT" spec:"true" json:"CUSTODY_REQUIREMENT,string"`                           // CustodyRequirement defines the custody requirement.
 	TargetNumberOfPeers          uint64 `yaml:"TARGET_NUMBER_OF_PEERS" spec:"true" json:"TARGET_NUMBER_OF_PEERS,string"`                     // TargetNumberOfPeers defines the target number of peers.
+	NumberOfCustodyGroups        uint64 `yaml:"NUMBER_OF_CUSTODY_GROUPS" spec:"true" json:"NUMBER_OF_CUSTODY_GROUPS,string"`                 // NumberOfCustodyGroups defines the number of groups the columns are custodied in.
+
+	MinEpochsForDataColumnSidecarsRequests uint64 `yaml:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS" spec:"true" json:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS,string"` // MinEpochsForDataColumnSidecarsRequests defines for how many epochs the data column sidecars are served.
+	Eip7594ForkEpoch                       uint64 `yaml:"EIP7594_FORK_EPOCH" spec:"true" json:"EIP7594_FORK_EPOCH,string"`                                                     // Eip7594ForkEpoch is the epoch from which blobs are distributed as data column sidecars (PeerDAS).
 
 	// Electra
-	MinPerEpochChurnLimitElectra        uint64 `yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA" spec:"true" json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`                 // MinPerEpochChurnLimitElectra defines the minimum per epoch churn limit for Electra.
-	MaxPerEpochActivationExitChurnLimit uint64 `yaml:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT" spec:"true" json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,string"` // MaxPerEpochActivationExitChurnLimit defines the maximum per epoch activation exit churn limit for Electra.
+	MinPerEpochChurnLimitElectra          uint64     `yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA" spec:"true" json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`                   // MinPerEpochChurnLimitElectra defines the minimum per epoch churn limit for Electra.
+	MaxPerEpochActivationExitChurnLimit   uint64     `yaml:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT" spec:"true" json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,string"`   // MaxPerEpochActivationExitChurnLimit defines the maximum per epoch activation exit churn limit for Electra.
+	MinActivationBalance                  uint64     `yaml:"MIN_ACTIVATION_BALANCE" spec:"true" json:"MIN_ACTIVATION_BALANCE,string"`                                         // MinActivationBalance is the minimal balance of validator to become active (EIP-7251).
+	MaxEffectiveBalanceElectra            uint64     `yaml:"MAX_EFFECTIVE_BALANCE_ELECTRA" spec:"true" json:"MAX_EFFECTIVE_BALANCE_ELECTRA,string"`                           // MaxEffectiveBalanceElectra is the maximal effective balance of compounding validator.
+	MinSlashingPenaltyQuotientElectra     uint64     `yaml:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA" spec:"true" json:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA,string"`           // MinSlashingPenaltyQuotientElectra for slashing penalties post Electra hard fork.
+	WhistleBlowerRewardQuotientElectra    uint64     `yaml:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA" spec:"true" json:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA,string"`           // WhistleBlowerRewardQuotientElectra is used to calculate whistle blower reward post Electra hard fork.
+	PendingDepositsLimit                  uint64     `yaml:"PENDING_DEPOSITS_LIMIT" spec:"true" json:"PENDING_DEPOSITS_LIMIT,string"`                                         // PendingDepositsLimit is the maximum length of pending deposits queue.
+	PendingPartialWithdrawalsLimit        uint64     `yaml:"PENDING_PARTIAL_WITHDRAWALS_LIMIT" spec:"true" json:"PENDING_PARTIAL_WITHDRAWALS_LIMIT,string"`                   // PendingPartialWithdrawalsLimit is the maximum length of pending partial withdrawals queue.
+	PendingConsolidationsLimit            uint64     `yaml:"PENDING_CONSOLIDATIONS_LIMIT" spec:"true" json:"PENDING_CONSOLIDATIONS_LIMIT,string"`                             // PendingConsolidationsLimit is the maximum length of pending consolidations queue.
+	MaxPendingPartialsPerWithdrawalsSweep uint64     `yaml:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP" spec:"true" json:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP,string"` // MaxPendingPartialsPerWithdrawalsSweep bounds the number of pending partial withdrawals processed per slot.
+	MaxPendingDepositsPerEpoch            uint64     `yaml:"MAX_PENDING_DEPOSITS_PER_EPOCH" spec:"true" json:"MAX_PENDING_DEPOSITS_PER_EPOCH,string"`                         // MaxPendingDepositsPerEpoch bounds the number of pending deposits processed per epoch.
+	MaxDepositRequestsPerPayload          uint64     `yaml:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD,string"`                     // MaxDepositRequestsPerPayload defines the maximum number of deposit requests in a block (EIP-6110).
+	MaxWithdrawalRequestsPerPayload       uint64     `yaml:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD,string"`               // MaxWithdrawalRequestsPerPayload defines the maximum number of withdrawal requests in a block (EIP-7002).
+	MaxConsolidationRequestsPerPayload    uint64     `yaml:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD" spec:"true" json:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD,string"`         // MaxConsolidationRequestsPerPayload defines the maximum number of consolidation requests in a block (EIP-7251).
+	CompoundingWithdrawalPrefixByte       ConfigByte `yaml:"COMPOUNDING_WITHDRAWAL_PREFIX" spec:"true" json:"COMPOUNDING_WITHDRAWAL_PREFIX"`                                  // CompoundingWithdrawalPrefixByte is the first byte of withdrawal credentials of compounding validator.
+	FullExitRequestAmount                 uint64     `yaml:"FULL_EXIT_REQUEST_AMOUNT" spec:"true" json:"FULL_EXIT_REQUEST_AMOUNT,string"`                                     // FullExitRequestAmount is the amount of withdrawal request which triggers full exit.
+	UnsetDepositRequestsStartIndex        uint64     `yaml:"UNSET_DEPOSIT_REQUESTS_START_INDEX" spec:"true" json:"UNSET_DEPOSIT_REQUESTS_START_INDEX,string"`                 // UnsetDepositRequestsStartIndex marks that no deposit request was processed yet.
 }
 
 func (b *BeaconChainConfig) RoundSlotToEpoch(slot uint64) uint64 {
@@ -573,7 +633,7 @@ func (b *BeaconChainConfig) RoundSlotToVotePeriod(slot uint64) uint64 {
 }
 
 func (b *BeaconChainConfig) GetCurrentStateVersion(epoch uint64) StateVersion {
-	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch}
+	forkEpochList := []uint64{b.AltairForkEpoch, b.BellatrixForkEpoch, b.CapellaForkEpoch, b.DenebForkEpoch, b.ElectraForkEpoch}
 	stateVersion := Phase0Version
 	for _, forkEpoch := range forkEpochList {
 		if forkEpoch > epoch {
@@ -584,6 +644,11 @@ func (b *BeaconChainConfig) GetCurrentStateVersion(epoch uint64) StateVersion {
 	return stateVersion
 }
 
+// IsPeerDASActive tells whether blobs are distributed as data column sidecars at the given epoch.
+func (b *BeaconChainConfig) IsPeerDASActive(epoch uint64) bool {
+	return epoch >= b.Eip7594ForkEpoch
+}
+
 // InitializeForkSchedule initializes the schedules forks baked into the config.
 func (b *BeaconChainConfig) InitializeForkSchedule() {
 	b.ForkVersionSchedule = configForkSchedule(b)
@@ -792,14 +857,33 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 
 	NumberOfColumns:              128,
 	MaxCellsInExtendedMatrix:     768,
-	DataColumnSidecarSubnetCount: 32,
+	DataColumnSidecarSubnetCount: 128,
 	MaxRequestDataColumnSidecars: 16384,
 	SamplesPerSlot:               8,
-	CustodyRequirement:           1,
+	CustodyRequirement:           4,
 	TargetNumberOfPeers:          70,
-
-	MinPerEpochChurnLimitElectra:        128000000000,
-	MaxPerEpochActivationExitChurnLimit: 256000000000,
+	NumberOfCustodyGroups:        128,
+
+	MinEpochsForDataColumnSidecarsRequests: 4096,
+	Eip7594ForkEpoch:                       math.MaxUint64,
+
+	MinPerEpochChurnLimitElectra:          128000000000,
+	MaxPerEpochActivationExitChurnLimit:   256000000000,
+	MinActivationBalance:                  32 * 1e9,
+	MaxEffectiveBalanceElectra:            2048 * 1e9,
+	MinSlashingPenaltyQuotientElectra:     4096,
+	WhistleBlowerRewardQuotientElectra:    4096,
+	PendingDepositsLimit:                  1 << 27,
+	PendingPartialWithdrawalsLimit:        1 << 27,
+	PendingConsolidationsLimit:            1 << 18,
+	MaxPendingPartialsPerWithdrawalsSweep: 8,
+	MaxPendingDepositsPerEpoch:            16,
+	MaxDepositRequestsPerPayload:          8192,
+	MaxWithdrawalRequestsPerPayload:       16,
+	MaxConsolidationRequestsPerPayload:    2,
+	CompoundingWithdrawalPrefixByte:       ConfigByte(2),
+	FullExitRequestAmount:                 0,
+	UnsetDepositRequestsStartIndex:        math.MaxUint64,
 }
 
 func mainnetConfig() BeaconChainConfig {
@@ -961,6 +1045,8 @@ func (b *BeaconChainConfig) GetMinSlashingPenaltyQuotient(version StateVersion)
 		return b.MinSlashingPenaltyQuotientBellatrix
 	case DenebVersion:
 		return b.MinSlashingPenaltyQuotientBellatrix
+	case ElectraVersion:
+		return b.MinSlashingPenaltyQuotientElectra
 	default:
 		panic("not implemented")
 	}
@@ -978,6 +1064,8 @@ func (b *BeaconChainConfig) GetPenaltyQuotient(version StateVersion) uint64 {
 		return b.InactivityPenaltyQuotientBellatrix
 	case DenebVersion:
 		return b.InactivityPenaltyQuotientBellatrix
+	case ElectraVersion:
+		return b.InactivityPenaltyQuotientBellatrix
 	default:
 		panic("not implemented")
 	}
@@ -51,6 +51,10 @@ const (
 	// For electra fork
 	MaxAttesterSlashingsElectra = 1
 	MaxAttestationsElectra      = 8
+
+	MaxDepositRequestsPerPayload       = 8192
+	MaxWithdrawalRequestsPerPayload    = 16
+	MaxConsolidationRequestsPerPayload = 2
 )
 
 var (
@@ -228,6 +232,8 @@ type BeaconBody struct {
 	// The commitments for beacon chain blobs
 	// With a max of 4 per block
 	BlobKzgCommitments *solid.ListSSZ[*KZGCommitment] `json:"blob_kzg_commitments,omitempty"`
+	// Deposit, withdrawal and consolidation requests of execution layer (Electra)
+	ExecutionRequests *ExecutionRequests `json:"execution_requests,omitempty"`
 	// The version of the beacon chain
 	Version   clparams.StateVersion `json:"-"`
 	beaconCfg *clparams.BeaconChainConfig
@@ -322,6 +328,12 @@ func (b *BeaconBody) EncodingSizeSSZ() (size int) {
 	if b.Version >= clparams.DenebVersion {
 		size += b.BlobKzgCommitments.EncodingSizeSSZ()
 	}
+	if b.Version >= clparams.ElectraVersion {
+		if b.ExecutionRequests == nil {
+			b.ExecutionRequests = NewExecutionRequests()
+		}
+		size += b.ExecutionRequests.EncodingSizeSSZ()
+	}
 
 	return
 }
@@ -334,6 +346,13 @@ func (b *BeaconBody) DecodeSSZ(buf []byte, version int) error {
 	}
 
 	b.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	// the body may have been created for another fork, the list limits have to match the decoded one.
+	maxAttSlashing, maxAttestation := MaxAttesterSlashings, MaxAttestations
+	if b.Version.AfterOrEqual(clparams.ElectraVersion) {
+		maxAttSlashing, maxAttestation = MaxAttesterSlashingsElectra, MaxAttestationsElectra
+	}
+	b.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](maxAttSlashing)
+	b.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](maxAttestation)
 
 	err := ssz2.UnmarshalSSZ(buf, version, b.getSchema(false)...)
 	return err
@@ -357,6 +376,7 @@ func (b *BeaconBody) Blinded() (*BlindedBeaconBody, error) {
 		ExecutionPayload:   header,
 		ExecutionChanges:   b.ExecutionChanges,
 		BlobKzgCommitments: b.BlobKzgCommitments,
+		ExecutionRequests:  b.ExecutionRequests,
 		Version:            b.Version,
 		beaconCfg:          b.beaconCfg,
 	}, nil
@@ -380,6 +400,12 @@ func (b *BeaconBody) getSchema(storage bool) []interface{} {
 	if b.Version >= clparams.DenebVersion {
 		s = append(s, b.BlobKzgCommitments)
 	}
+	if b.Version >= clparams.ElectraVersion {
+		if b.ExecutionRequests == nil {
+			b.ExecutionRequests = NewExecutionRequests()
+		}
+		s = append(s, b.ExecutionRequests)
+	}
 	return s
 }
 
@@ -402,6 +428,11 @@ func (b *BeaconBody) KzgCommitmentMerkleProof(index int) ([][32]byte, error) {
 	return append(branch, kzgCommitmentsProof...), nil
 }
 
+// KzgCommitmentsMerkleProof proves the whole blob_kzg_commitments list against the body root, used by data column sidecars.
+func (b *BeaconBody) KzgCommitmentsMerkleProof() ([][32]byte, error) {
+	return merkle_tree.MerkleProof(4, 11, b.getSchema(false)...)
+}
+
 func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	var (
 		maxAttSlashing = MaxAttesterSlashings
@@ -425,6 +456,7 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 		ExecutionPayload   *Eth1Block                                  `json:"execution_payload,omitempty"`
 		ExecutionChanges   *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"bls_to_execution_changes,omitempty"`
 		BlobKzgCommitments *solid.ListSSZ[*KZGCommitment]              `json:"blob_kzg_commitments,omitempty"`
+		ExecutionRequests  *ExecutionRequests                          `json:"execution_requests,omitempty"`
 	}
 	tmp.ProposerSlashings = solid.NewStaticListSSZ[*ProposerSlashing](MaxProposerSlashings, 416)
 	tmp.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](maxAttSlashing)
@@ -434,6 +466,9 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	tmp.ExecutionChanges = solid.NewStaticListSSZ[*SignedBLSToExecutionChange](MaxExecutionChanges, 172)
 	tmp.BlobKzgCommitments = solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48)
 	tmp.ExecutionPayload = NewEth1Block(b.Version, b.beaconCfg)
+	if b.Version >= clparams.ElectraVersion {
+		tmp.ExecutionRequests = NewExecutionRequests()
+	}
 
 	if err := json.Unmarshal(buf, &tmp); err != nil {
 		return err
@@ -450,6 +485,7 @@ func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	b.ExecutionPayload = tmp.ExecutionPayload
 	b.ExecutionChanges = tmp.ExecutionChanges
 	b.BlobKzgCommitments = tmp.BlobKzgCommitments
+	b.ExecutionRequests = tmp.ExecutionRequests
 	return nil
 }
 
@@ -600,3 +636,7 @@ func (b *DenebSignedBeaconBlock) Static() bool {
 	// it's variable size
 	return false
 }
+
+func (b *BeaconBody) GetExecutionRequests() *ExecutionRequests {
+	return b.ExecutionRequests
+}
@@ -224,6 +224,8 @@ type BlindedBeaconBody struct {
 	// The commitments for beacon chain blobs
 	// With a max of 4 per block
 	BlobKzgCommitments *solid.ListSSZ[*KZGCommitment] `json:"blob_kzg_commitments"`
+	// Deposit, withdrawal and consolidation requests of execution layer (Electra)
+	ExecutionRequests *ExecutionRequests `json:"execution_requests,omitempty"`
 	// The version of the beacon chain
 	Version   clparams.StateVersion `json:"-"`
 	beaconCfg *clparams.BeaconChainConfig
@@ -328,6 +330,12 @@ func (b *BlindedBeaconBody) EncodingSizeSSZ() (size int) {
 	if b.Version >= clparams.DenebVersion {
 		size += b.ExecutionChanges.EncodingSizeSSZ()
 	}
+	if b.Version >= clparams.ElectraVersion {
+		if b.ExecutionRequests == nil {
+			b.ExecutionRequests = NewExecutionRequests()
+		}
+		size += b.ExecutionRequests.EncodingSizeSSZ()
+	}
 
 	return
 }
@@ -363,6 +371,12 @@ func (b *BlindedBeaconBody) getSchema(storage bool) []interface{} {
 	if b.Version >= clparams.DenebVersion {
 		s = append(s, b.BlobKzgCommitments)
 	}
+	if b.Version >= clparams.ElectraVersion {
+		if b.ExecutionRequests == nil {
+			b.ExecutionRequests = NewExecutionRequests()
+		}
+		s = append(s, b.ExecutionRequests)
+	}
 	return s
 }
 
@@ -413,6 +427,7 @@ func (b *BlindedBeaconBody) Full(txs *solid.TransactionsSSZ, withdrawals *solid.
 		ExecutionPayload:   executionPayload,
 		ExecutionChanges:   b.ExecutionChanges,
 		BlobKzgCommitments: b.BlobKzgCommitments,
+		ExecutionRequests:  b.ExecutionRequests,
 		Version:            b.Version,
 		beaconCfg:          b.beaconCfg,
 	}
@@ -472,3 +487,7 @@ func (b *BlindedBeaconBody) GetBlobKzgCommitments() *solid.ListSSZ[*KZGCommitmen
 func (b *BlindedBeaconBody) GetExecutionChanges() *solid.ListSSZ[*SignedBLSToExecutionChange] {
 	return b.ExecutionChanges
 }
+
+func (b *BlindedBeaconBody) GetExecutionRequests() *ExecutionRequests {
+	return b.ExecutionRequests
+}
@@ -112,6 +112,25 @@ func TestBeaconBody(t *testing.T) {
 	assert.NotNil(t, b)
 }
 
+func TestBeaconBodyDecodeElectraIntoDenebBody(t *testing.T) {
+	block := types.NewBlock(&types.Header{BaseFee: big.NewInt(1)}, nil, nil, nil, types.Withdrawals{})
+	body := NewBeaconBody(&clparams.MainnetBeaconConfig, clparams.ElectraVersion)
+	body.ExecutionPayload = NewEth1BlockFromHeaderAndBody(block.Header(), block.RawBody(), &clparams.MainnetBeaconConfig)
+	body.SyncAggregate = &SyncAggregate{}
+	body.SetVersion(clparams.ElectraVersion)
+	encoded, err := body.EncodeSSZ(nil)
+	require.NoError(t, err)
+	expectedRoot, err := body.HashSSZ()
+	require.NoError(t, err)
+
+	// the list limits follow the decoded fork, not the one the body was created for.
+	decoded := NewBeaconBody(&clparams.MainnetBeaconConfig, clparams.DenebVersion)
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.ElectraVersion)))
+	root, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, root)
+}
+
 func TestBeaconBlockJson(t *testing.T) {
 	_, bc := clparams.GetConfigsByNetwork(clparams.GnosisNetwork)
 	block := NewSignedBeaconBlock(bc, clparams.DenebVersion)
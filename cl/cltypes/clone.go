@@ -148,3 +148,35 @@ func (*Root) Clone() clonable.Clonable {
 func (*LightClientUpdatesByRangeRequest) Clone() clonable.Clonable {
 	return &LightClientUpdatesByRangeRequest{}
 }
+
+func (*PendingDeposit) Clone() clonable.Clonable {
+	return &PendingDeposit{}
+}
+
+func (*PendingPartialWithdrawal) Clone() clonable.Clonable {
+	return &PendingPartialWithdrawal{}
+}
+
+func (*PendingConsolidation) Clone() clonable.Clonable {
+	return &PendingConsolidation{}
+}
+
+func (*DepositRequest) Clone() clonable.Clonable {
+	return &DepositRequest{}
+}
+
+func (*WithdrawalRequest) Clone() clonable.Clonable {
+	return &WithdrawalRequest{}
+}
+
+func (*ConsolidationRequest) Clone() clonable.Clonable {
+	return &ConsolidationRequest{}
+}
+
+func (*ExecutionRequests) Clone() clonable.Clonable {
+	return NewExecutionRequests()
+}
+
+func (*DepositTreeSnapshot) Clone() clonable.Clonable {
+	return NewDepositTreeSnapshot()
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"encoding/json"
+	"reflect"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon-lib/types/clonable"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// KzgCommitmentsInclusionProofDepth is the depth of the proof of blob_kzg_commitments in the beacon block body.
+const KzgCommitmentsInclusionProofDepth = 4
+
+var (
+	cellT = reflect.TypeOf(Cell{})
+
+	_ ssz2.SizedObjectSSZ = (*Cell)(nil)
+	_ ssz2.SizedObjectSSZ = (*DataColumnSidecar)(nil)
+	_ ssz2.SizedObjectSSZ = (*DataColumnIdentifier)(nil)
+	_ ssz2.SizedObjectSSZ = (*DataColumnSidecarsByRangeRequest)(nil)
+)
+
+// Cell is a chunk of the extended blob, see EIP-7594.
+type Cell kzg.Cell
+
+func (c *Cell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutility.Bytes(c[:]))
+}
+
+func (c *Cell) UnmarshalJSON(in []byte) error {
+	return hexutility.UnmarshalFixedJSON(cellT, in, c[:])
+}
+
+func (c *Cell) Clone() clonable.Clonable {
+	return &Cell{}
+}
+
+func (c *Cell) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c[:])
+}
+
+func (c *Cell) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, c[:]...), nil
+}
+
+func (c *Cell) EncodingSizeSSZ() int {
+	return kzg.BytesPerCell
+}
+
+func (c *Cell) Static() bool {
+	return true
+}
+
+func (c *Cell) HashSSZ() ([32]byte, error) {
+	return merkle_tree.BytesRoot(c[:])
+}
+
+// DataColumnSidecar carries one column of the extended blob matrix: the cell at Index of every blob of the block.
+type DataColumnSidecar struct {
+	Index                        uint64                         `json:"index,string"`
+	Column                       *solid.ListSSZ[*Cell]          `json:"column"`
+	KzgCommitments               *solid.ListSSZ[*KZGCommitment] `json:"kzg_commitments"`
+	KzgProofs                    *solid.ListSSZ[*KZGProof]      `json:"kzg_proofs"`
+	SignedBlockHeader            *SignedBeaconBlockHeader       `json:"signed_block_header"`
+	KzgCommitmentsInclusionProof solid.HashVectorSSZ            `json:"kzg_commitments_inclusion_proof"`
+}
+
+func NewDataColumnSidecar() *DataColumnSidecar {
+	return &DataColumnSidecar{
+		Column:                       solid.NewStaticListSSZ[*Cell](MaxBlobsCommittmentsPerBlock, kzg.BytesPerCell),
+		KzgCommitments:               solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		KzgProofs:                    solid.NewStaticListSSZ[*KZGProof](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		SignedBlockHeader:            &SignedBeaconBlockHeader{Header: &BeaconBlockHeader{}},
+		KzgCommitmentsInclusionProof: solid.NewHashVector(KzgCommitmentsInclusionProofDepth),
+	}
+}
+
+func (d *DataColumnSidecar) UnmarshalJSON(buf []byte) error {
+	type tmpSidecar DataColumnSidecar
+	tmp := (*tmpSidecar)(NewDataColumnSidecar())
+	if err := json.Unmarshal(buf, tmp); err != nil {
+		return err
+	}
+	*d = DataColumnSidecar(*tmp)
+	return nil
+}
+
+func (d *DataColumnSidecar) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) DecodeSSZ(buf []byte, version int) error {
+	*d = *NewDataColumnSidecar()
+	return ssz2.UnmarshalSSZ(buf, version, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) EncodingSizeSSZ() int {
+	if d.Column == nil {
+		*d = *NewDataColumnSidecar()
+	}
+	return 3*4 + 8 + d.Column.EncodingSizeSSZ() + d.KzgCommitments.EncodingSizeSSZ() + d.KzgProofs.EncodingSizeSSZ() +
+		d.SignedBlockHeader.EncodingSizeSSZ() + KzgCommitmentsInclusionProofDepth*length.Hash
+}
+
+func (d *DataColumnSidecar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) Static() bool {
+	return false
+}
+
+func (*DataColumnSidecar) Clone() clonable.Clonable {
+	return NewDataColumnSidecar()
+}
+
+func (d *DataColumnSidecar) getSchema() []interface{} {
+	return []interface{}{&d.Index, d.Column, d.KzgCommitments, d.KzgProofs, d.SignedBlockHeader, d.KzgCommitmentsInclusionProof}
+}
+
+// VerifyKzgCommitmentsInclusionProof checks that the commitments of the sidecar are the ones of the block body.
+func (d *DataColumnSidecar) VerifyKzgCommitmentsInclusionProof() bool {
+	if d.KzgCommitmentsInclusionProof == nil || d.KzgCommitmentsInclusionProof.Length() != KzgCommitmentsInclusionProofDepth {
+		return false
+	}
+	value, err := d.KzgCommitments.HashSSZ()
+	if err != nil {
+		return false
+	}
+	bIndex := uint64(11) // blob_kzg_commitments field index in the body.
+	for i := uint64(0); i < KzgCommitmentsInclusionProofDepth; i++ {
+		curr := d.KzgCommitmentsInclusionProof.Get(int(i))
+		if (bIndex / utils.PowerOf2(i) % 2) == 1 {
+			value = utils.Sha256(append(curr[:], value[:]...))
+		} else {
+			value = utils.Sha256(append(value[:], curr[:]...))
+		}
+	}
+	return value == d.SignedBlockHeader.Header.BodyRoot
+}
+
+type DataColumnIdentifier struct {
+	BlockRoot libcommon.Hash `json:"block_root"`
+	Index     uint64         `json:"index,string"`
+}
+
+func NewDataColumnIdentifier(blockRoot libcommon.Hash, index uint64) *DataColumnIdentifier {
+	return &DataColumnIdentifier{
+		BlockRoot: blockRoot,
+		Index:     index,
+	}
+}
+
+func (d *DataColumnIdentifier) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.BlockRoot[:], &d.Index)
+}
+
+func (d *DataColumnIdentifier) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, d.BlockRoot[:], &d.Index)
+}
+
+func (d *DataColumnIdentifier) EncodingSizeSSZ() int {
+	return length.Hash + length.BlockNum
+}
+
+func (d *DataColumnIdentifier) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.BlockRoot[:], &d.Index)
+}
+
+func (d *DataColumnIdentifier) Static() bool {
+	return true
+}
+
+func (*DataColumnIdentifier) Clone() clonable.Clonable {
+	return &DataColumnIdentifier{}
+}
+
+// DataColumnSidecarsByRangeRequest is the request of data_column_sidecars_by_range.
+type DataColumnSidecarsByRangeRequest struct {
+	StartSlot uint64               `json:"start_slot,string"`
+	Count     uint64               `json:"count,string"`
+	Columns   *solid.RawUint64List `json:"columns"`
+}
+
+func NewDataColumnSidecarsByRangeRequest(numberOfColumns uint64) *DataColumnSidecarsByRangeRequest {
+	return &DataColumnSidecarsByRangeRequest{
+		Columns: solid.NewRawUint64List(int(numberOfColumns), nil),
+	}
+}
+
+func (d *DataColumnSidecarsByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, &d.StartSlot, &d.Count, d.Columns)
+}
+
+func (d *DataColumnSidecarsByRangeRequest) DecodeSSZ(buf []byte, version int) error {
+	if d.Columns == nil {
+		d.Columns = solid.NewRawUint64List(kzg.CellsPerExtBlob, nil)
+	}
+	return ssz2.UnmarshalSSZ(buf, version, &d.StartSlot, &d.Count, d.Columns)
+}
+
+func (d *DataColumnSidecarsByRangeRequest) EncodingSizeSSZ() int {
+	if d.Columns == nil {
+		return 2*length.BlockNum + 4
+	}
+	return 2*length.BlockNum + 4 + d.Columns.EncodingSizeSSZ()
+}
+
+func (d *DataColumnSidecarsByRangeRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(&d.StartSlot, &d.Count, d.Columns)
+}
+
+func (d *DataColumnSidecarsByRangeRequest) Static() bool {
+	return false
+}
+
+func (*DataColumnSidecarsByRangeRequest) Clone() clonable.Clonable {
+	return &DataColumnSidecarsByRangeRequest{}
+}
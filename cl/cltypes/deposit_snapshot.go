@@ -0,0 +1,69 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+)
+
+// DepositTreeDepth is the depth of the deposit contract merkle tree.
+const DepositTreeDepth = 32
+
+// DepositTreeSnapshot is the compact representation of the finalized deposit contract tree (EIP-4881).
+// Finalized holds the roots of the finalized subtrees, ordered from the largest one to the smallest one.
+type DepositTreeSnapshot struct {
+	Finalized            solid.HashListSSZ `json:"finalized"`
+	DepositRoot          libcommon.Hash    `json:"deposit_root"`
+	DepositCount         uint64            `json:"deposit_count,string"`
+	ExecutionBlockHash   libcommon.Hash    `json:"execution_block_hash"`
+	ExecutionBlockHeight uint64            `json:"execution_block_height,string"`
+}
+
+func NewDepositTreeSnapshot() *DepositTreeSnapshot {
+	return &DepositTreeSnapshot{Finalized: solid.NewHashList(DepositTreeDepth)}
+}
+
+func (d *DepositTreeSnapshot) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.Finalized, d.DepositRoot[:], d.DepositCount, d.ExecutionBlockHash[:], d.ExecutionBlockHeight)
+}
+
+func (d *DepositTreeSnapshot) DecodeSSZ(buf []byte, version int) error {
+	if d.Finalized == nil {
+		d.Finalized = solid.NewHashList(DepositTreeDepth)
+	}
+	return ssz2.UnmarshalSSZ(buf, version, d.Finalized, d.DepositRoot[:], &d.DepositCount, d.ExecutionBlockHash[:], &d.ExecutionBlockHeight)
+}
+
+func (d *DepositTreeSnapshot) EncodingSizeSSZ() int {
+	if d.Finalized == nil {
+		d.Finalized = solid.NewHashList(DepositTreeDepth)
+	}
+	return 4 + d.Finalized.EncodingSizeSSZ() + length.Hash*2 + 2*8
+}
+
+func (d *DepositTreeSnapshot) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.Finalized, d.DepositRoot[:], d.DepositCount, d.ExecutionBlockHash[:], d.ExecutionBlockHeight)
+}
+
+func (*DepositTreeSnapshot) Static() bool {
+	return false
+}
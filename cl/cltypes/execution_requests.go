@@ -0,0 +1,157 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"encoding/json"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+)
+
+// DepositRequest is a deposit to deposit contract, passed by execution layer in block (EIP-6110).
+type DepositRequest struct {
+	PubKey                libcommon.Bytes48 `json:"pubkey"`
+	WithdrawalCredentials libcommon.Hash    `json:"withdrawal_credentials"`
+	Amount                uint64            `json:"amount,string"`
+	Signature             libcommon.Bytes96 `json:"signature"`
+	Index                 uint64            `json:"index,string"`
+}
+
+func (d *DepositRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (d *DepositRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, d.PubKey[:], d.WithdrawalCredentials[:], &d.Amount, d.Signature[:], &d.Index)
+}
+
+func (d *DepositRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.PubKey[:], d.WithdrawalCredentials[:], d.Amount, d.Signature[:], d.Index)
+}
+
+func (*DepositRequest) EncodingSizeSSZ() int {
+	return 192
+}
+
+func (*DepositRequest) Static() bool {
+	return true
+}
+
+// WithdrawalRequest is a request of withdrawal credentials owner to exit validator or withdraw its excess balance (EIP-7002).
+type WithdrawalRequest struct {
+	SourceAddress   libcommon.Address `json:"source_address"`
+	ValidatorPubKey libcommon.Bytes48 `json:"validator_pubkey"`
+	Amount          uint64            `json:"amount,string"`
+}
+
+func (w *WithdrawalRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (w *WithdrawalRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, w.SourceAddress[:], w.ValidatorPubKey[:], &w.Amount)
+}
+
+func (w *WithdrawalRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(w.SourceAddress[:], w.ValidatorPubKey[:], w.Amount)
+}
+
+func (*WithdrawalRequest) EncodingSizeSSZ() int {
+	return 76
+}
+
+func (*WithdrawalRequest) Static() bool {
+	return true
+}
+
+// ConsolidationRequest is a request to move balance of source validator to target validator (EIP-7251).
+type ConsolidationRequest struct {
+	SourceAddress libcommon.Address `json:"source_address"`
+	SourcePubKey  libcommon.Bytes48 `json:"source_pubkey"`
+	TargetPubKey  libcommon.Bytes48 `json:"target_pubkey"`
+}
+
+func (c *ConsolidationRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (c *ConsolidationRequest) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (c *ConsolidationRequest) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(c.SourceAddress[:], c.SourcePubKey[:], c.TargetPubKey[:])
+}
+
+func (*ConsolidationRequest) EncodingSizeSSZ() int {
+	return 116
+}
+
+func (*ConsolidationRequest) Static() bool {
+	return true
+}
+
+// ExecutionRequests are requests to consensus layer collected by execution layer in block (Electra beacon block body).
+type ExecutionRequests struct {
+	Deposits       *solid.ListSSZ[*DepositRequest]       `json:"deposits"`
+	Withdrawals    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawals"`
+	Consolidations *solid.ListSSZ[*ConsolidationRequest] `json:"consolidations"`
+}
+
+func NewExecutionRequests() *ExecutionRequests {
+	return &ExecutionRequests{
+		Deposits:       solid.NewStaticListSSZ[*DepositRequest](MaxDepositRequestsPerPayload, 192),
+		Withdrawals:    solid.NewStaticListSSZ[*WithdrawalRequest](MaxWithdrawalRequestsPerPayload, 76),
+		Consolidations: solid.NewStaticListSSZ[*ConsolidationRequest](MaxConsolidationRequestsPerPayload, 116),
+	}
+}
+
+func (e *ExecutionRequests) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (e *ExecutionRequests) DecodeSSZ(buf []byte, version int) error {
+	*e = *NewExecutionRequests()
+	return ssz2.UnmarshalSSZ(buf, version, e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (e *ExecutionRequests) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(e.Deposits, e.Withdrawals, e.Consolidations)
+}
+
+func (e *ExecutionRequests) EncodingSizeSSZ() int {
+	return 12 + e.Deposits.EncodingSizeSSZ() + e.Withdrawals.EncodingSizeSSZ() + e.Consolidations.EncodingSizeSSZ()
+}
+
+func (*ExecutionRequests) Static() bool {
+	return false
+}
+
+func (e *ExecutionRequests) UnmarshalJSON(buf []byte) error {
+	*e = *NewExecutionRequests()
+	var tmp struct {
+		Deposits       *solid.ListSSZ[*DepositRequest]       `json:"deposits"`
+		Withdrawals    *solid.ListSSZ[*WithdrawalRequest]    `json:"withdrawals"`
+		Consolidations *solid.ListSSZ[*ConsolidationRequest] `json:"consolidations"`
+	}
+	tmp.Deposits, tmp.Withdrawals, tmp.Consolidations = e.Deposits, e.Withdrawals, e.Consolidations
+	return json.Unmarshal(buf, &tmp)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes_test
+
+import (
+	"testing"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionRequestsSSZ(t *testing.T) {
+	requests := cltypes.NewExecutionRequests()
+	requests.Deposits.Append(&cltypes.DepositRequest{
+		PubKey:                common.Bytes48{1},
+		WithdrawalCredentials: common.Hash{2},
+		Amount:                32_000_000_000,
+		Signature:             common.Bytes96{3},
+		Index:                 7,
+	})
+	requests.Withdrawals.Append(&cltypes.WithdrawalRequest{
+		SourceAddress:   common.Address{4},
+		ValidatorPubKey: common.Bytes48{5},
+		Amount:          1_000_000_000,
+	})
+	requests.Consolidations.Append(&cltypes.ConsolidationRequest{
+		SourceAddress: common.Address{6},
+		SourcePubKey:  common.Bytes48{7},
+		TargetPubKey:  common.Bytes48{8},
+	})
+
+	encoded, err := requests.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, requests.EncodingSizeSSZ())
+	require.Len(t, encoded, 12+192+76+116)
+
+	decoded := cltypes.NewExecutionRequests()
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.ElectraVersion)))
+	require.Equal(t, *requests.Deposits.Get(0), *decoded.Deposits.Get(0))
+	require.Equal(t, *requests.Withdrawals.Get(0), *decoded.Withdrawals.Get(0))
+	require.Equal(t, *requests.Consolidations.Get(0), *decoded.Consolidations.Get(0))
+
+	expectedRoot, err := requests.HashSSZ()
+	require.NoError(t, err)
+	root, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, root)
+}
@@ -41,18 +41,19 @@ type IndexedAttestation struct {
 }
 
 func NewIndexedAttestation(version clparams.StateVersion) *IndexedAttestation {
-	var attLimit int
-	if version.AfterOrEqual(clparams.ElectraVersion) {
-		attLimit = attestingIndicesLimitElectra
-	} else {
-		attLimit = attestingIndicesLimit
-	}
 	return &IndexedAttestation{
-		AttestingIndices: solid.NewRawUint64List(attLimit, nil),
+		AttestingIndices: solid.NewRawUint64List(attestingIndicesLimitForVersion(version), nil),
 		Data:             &solid.AttestationData{},
 	}
 }
 
+func attestingIndicesLimitForVersion(version clparams.StateVersion) int {
+	if version.AfterOrEqual(clparams.ElectraVersion) {
+		return attestingIndicesLimitElectra
+	}
+	return attestingIndicesLimit
+}
+
 func (i *IndexedAttestation) Static() bool {
 	return false
 }
@@ -81,7 +82,7 @@ func (i *IndexedAttestation) EncodeSSZ(buf []byte) (dst []byte, err error) {
 // DecodeSSZ ssz unmarshals the IndexedAttestation object
 func (i *IndexedAttestation) DecodeSSZ(buf []byte, version int) error {
 	i.Data = &solid.AttestationData{}
-	i.AttestingIndices = solid.NewRawUint64List(2048, nil)
+	i.AttestingIndices = solid.NewRawUint64List(attestingIndicesLimitForVersion(clparams.StateVersion(version)), nil)
 
 	return ssz2.UnmarshalSSZ(buf, version, i.AttestingIndices, i.Data, i.Signature[:])
 }
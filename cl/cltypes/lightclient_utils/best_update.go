@@ -0,0 +1,173 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package lightclient_utils
+
+import (
+	"context"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+// CreateLightClientUpdateFromAttestedState creates the light client update signed by block, attestedState
+// is the post-state of attestedBlock, the parent of block.
+func CreateLightClientUpdateFromAttestedState(cfg *clparams.BeaconChainConfig, block, finalizedBlock, attestedBlock *cltypes.SignedBeaconBlock,
+	attestedState *state.CachingBeaconState) (*cltypes.LightClientUpdate, error) {
+	nextSyncCommitteeBranch, err := attestedState.NextSyncCommitteeBranch()
+	if err != nil {
+		return nil, err
+	}
+	finalityBranch, err := attestedState.FinalityRootBranch()
+	if err != nil {
+		return nil, err
+	}
+	return CreateLightClientUpdate(cfg, block, finalizedBlock, attestedBlock, attestedState.Slot(),
+		attestedState.NextSyncCommittee(), attestedState.FinalizedCheckpoint(), hashVectorFromSlice(nextSyncCommitteeBranch), hashVectorFromSlice(finalityBranch))
+}
+
+func hashVectorFromSlice(in [][32]byte) solid.HashVectorSSZ {
+	out := solid.NewHashVector(len(in))
+	for i, v := range in {
+		out.Set(i, libcommon.Hash(v))
+	}
+	return out
+}
+
+func isEmptyBranch(branch solid.HashVectorSSZ) bool {
+	if branch == nil {
+		return true
+	}
+	for i := 0; i < branch.Length(); i++ {
+		if branch.Get(i) != (libcommon.Hash{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// def is_better_update(new_update: LightClientUpdate, old_update: LightClientUpdate) -> bool
+// IsBetterUpdate implements the specs ranking of light client updates, it returns true if newUpdate
+// should replace oldUpdate as the best update of its sync committee period.
+func IsBetterUpdate(cfg *clparams.BeaconChainConfig, newUpdate, oldUpdate *cltypes.LightClientUpdate) bool {
+	if oldUpdate == nil {
+		return true
+	}
+	// Compare supermajority (> 2/3) sync committee participation
+	maxActiveParticipants := int(cfg.SyncCommitteeSize)
+	newActiveParticipants := newUpdate.SyncAggregate.Sum()
+	oldActiveParticipants := oldUpdate.SyncAggregate.Sum()
+	newHasSupermajority := newActiveParticipants*3 >= maxActiveParticipants*2
+	oldHasSupermajority := oldActiveParticipants*3 >= maxActiveParticipants*2
+	if newHasSupermajority != oldHasSupermajority {
+		return newHasSupermajority
+	}
+	if !newHasSupermajority && newActiveParticipants != oldActiveParticipants {
+		return newActiveParticipants > oldActiveParticipants
+	}
+
+	// Compare presence of relevant sync committee
+	hasRelevantSyncCommittee := func(u *cltypes.LightClientUpdate) bool {
+		return !isEmptyBranch(u.NextSyncCommitteeBranch) &&
+			cfg.SyncCommitteePeriod(u.AttestedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(u.SignatureSlot)
+	}
+	newHasRelevantSyncCommittee := hasRelevantSyncCommittee(newUpdate)
+	if newHasRelevantSyncCommittee != hasRelevantSyncCommittee(oldUpdate) {
+		return newHasRelevantSyncCommittee
+	}
+
+	// Compare indication of any finality
+	newHasFinality := !isEmptyBranch(newUpdate.FinalityBranch)
+	if newHasFinality != !isEmptyBranch(oldUpdate.FinalityBranch) {
+		return newHasFinality
+	}
+
+	// Compare sync committee finality
+	if newHasFinality {
+		hasSyncCommitteeFinality := func(u *cltypes.LightClientUpdate) bool {
+			return cfg.SyncCommitteePeriod(u.FinalizedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(u.AttestedHeader.Beacon.Slot)
+		}
+		newHasSyncCommitteeFinality := hasSyncCommitteeFinality(newUpdate)
+		if newHasSyncCommitteeFinality != hasSyncCommitteeFinality(oldUpdate) {
+			return newHasSyncCommitteeFinality
+		}
+	}
+
+	// Tiebreaker 1: Sync committee participation beyond supermajority
+	if newActiveParticipants != oldActiveParticipants {
+		return newActiveParticipants > oldActiveParticipants
+	}
+
+	// Tiebreaker 2: Prefer older data (fewer changes to best)
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// WriteBestLightClientUpdate stores update as the best update of its attested period, unless a better one is already stored.
+func WriteBestLightClientUpdate(tx kv.RwTx, cfg *clparams.BeaconChainConfig, update *cltypes.LightClientUpdate) (bool, error) {
+	period := cfg.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot)
+	stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	if err != nil {
+		return false, err
+	}
+	if !IsBetterUpdate(cfg, update, stored) {
+		return false, nil
+	}
+	return true, beacon_indicies.WriteLightClientUpdate(tx, period, update)
+}
+
+// PersistLightClientUpdate is WriteBestLightClientUpdate in its own transaction, the write transaction is
+// opened only when the update is better than the stored one.
+func PersistLightClientUpdate(ctx context.Context, db kv.RwDB, cfg *clparams.BeaconChainConfig, update *cltypes.LightClientUpdate) error {
+	if update == nil {
+		return nil
+	}
+	period := cfg.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot)
+	var stored *cltypes.LightClientUpdate
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		stored, err = beacon_indicies.ReadLightClientUpdate(tx, period)
+		return err
+	}); err != nil {
+		return err
+	}
+	if !IsBetterUpdate(cfg, update, stored) {
+		return nil
+	}
+	return db.Update(ctx, func(tx kv.RwTx) error {
+		_, err := WriteBestLightClientUpdate(tx, cfg, update)
+		return err
+	})
+}
+
+// ReadBestLightClientUpdate returns the best between the stored update of the period and the one kept in memory
+// by fork choice (which may be nil), nil if neither exists.
+func ReadBestLightClientUpdate(tx kv.Tx, cfg *clparams.BeaconChainConfig, period uint64, inMemory *cltypes.LightClientUpdate) (*cltypes.LightClientUpdate, error) {
+	stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	if err != nil {
+		return nil, err
+	}
+	if inMemory != nil && IsBetterUpdate(cfg, inMemory, stored) {
+		return inMemory, nil
+	}
+	return stored, nil
+}
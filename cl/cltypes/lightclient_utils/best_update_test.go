@@ -0,0 +1,110 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package lightclient_utils
+
+import (
+	"context"
+	"testing"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUpdate(participants int, attestedSlot, signatureSlot, finalizedSlot uint64, withSyncCommittee, withFinality bool) *cltypes.LightClientUpdate {
+	update := cltypes.NewLightClientUpdate(clparams.AltairVersion)
+	for i := 0; i < participants; i++ {
+		update.SyncAggregate.SyncCommiteeBits[i/8] |= 1 << (i % 8)
+	}
+	update.AttestedHeader.Beacon.Slot = attestedSlot
+	update.SignatureSlot = signatureSlot
+	update.FinalizedHeader.Beacon.Slot = finalizedSlot
+	if withSyncCommittee {
+		update.NextSyncCommitteeBranch.Set(0, libcommon.Hash{1})
+	}
+	if withFinality {
+		update.FinalityBranch.Set(0, libcommon.Hash{1})
+	}
+	return update
+}
+
+func TestIsBetterUpdate(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	period := cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod
+	base := newTestUpdate(400, period+10, period+11, period+1, true, true)
+
+	tests := []struct {
+		name     string
+		update   *cltypes.LightClientUpdate
+		expected bool
+	}{
+		{"no previous update", base, true},
+		{"supermajority wins", newTestUpdate(200, period+10, period+11, period+1, true, true), false},
+		{"relevant sync committee wins", newTestUpdate(512, period+10, period+11, period+1, false, true), false},
+		{"sync committee from another period is not relevant", newTestUpdate(512, period-1, period+11, period-2, true, true), false},
+		{"finality wins", newTestUpdate(512, period+10, period+11, 0, true, false), false},
+		{"sync committee finality wins", newTestUpdate(512, period+10, period+11, period-1, true, true), false},
+		{"more participants win", newTestUpdate(401, period+20, period+21, period+1, true, true), true},
+		{"older attested header wins", newTestUpdate(400, period+5, period+21, period+1, true, true), true},
+		{"older signature slot wins", newTestUpdate(400, period+10, period+10, period+1, true, true), true},
+		{"same update is not better", newTestUpdate(400, period+10, period+11, period+1, true, true), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := base
+			if tt.update == base {
+				old = nil
+			}
+			require.Equal(t, tt.expected, IsBetterUpdate(cfg, tt.update, old))
+		})
+	}
+
+	// below supermajority, participation comes first
+	require.True(t, IsBetterUpdate(cfg, newTestUpdate(300, period+10, period+11, 0, false, false), newTestUpdate(200, period+10, period+11, period+1, true, true)))
+}
+
+func TestPersistLightClientUpdate(t *testing.T) {
+	ctx := context.Background()
+	cfg := &clparams.MainnetBeaconConfig
+	db := memdb.NewTestDB(t)
+	period := cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod
+
+	best := newTestUpdate(450, period+10, period+11, period+1, true, true)
+	require.NoError(t, PersistLightClientUpdate(ctx, db, cfg, newTestUpdate(400, period+2, period+3, period+1, true, true)))
+	require.NoError(t, PersistLightClientUpdate(ctx, db, cfg, best))
+	require.NoError(t, PersistLightClientUpdate(ctx, db, cfg, newTestUpdate(420, period+20, period+21, period+1, true, true)))
+	require.NoError(t, PersistLightClientUpdate(ctx, db, cfg, nil))
+
+	tx, err := db.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	stored, err := ReadBestLightClientUpdate(tx, cfg, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, best.SignatureSlot, stored.SignatureSlot)
+
+	// fork choice may hold a better update than the stored one
+	inMemory := newTestUpdate(500, period+30, period+31, period+1, true, true)
+	stored, err = ReadBestLightClientUpdate(tx, cfg, 1, inMemory)
+	require.NoError(t, err)
+	require.Equal(t, inMemory, stored)
+
+	stored, err = ReadBestLightClientUpdate(tx, cfg, 2, nil)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+}
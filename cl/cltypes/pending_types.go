@@ -0,0 +1,106 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	libcommon "github.com/erigontech/erigon-lib/common"
+
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+)
+
+// PendingDeposit is a deposit waiting in beacon state to be applied under balance churn (EIP-6110, EIP-7251).
+type PendingDeposit struct {
+	PubKey                libcommon.Bytes48 `json:"pubkey"`
+	WithdrawalCredentials libcommon.Hash    `json:"withdrawal_credentials"`
+	Amount                uint64            `json:"amount,string"`
+	Signature             libcommon.Bytes96 `json:"signature"`
+	Slot                  uint64            `json:"slot,string"`
+}
+
+func (p *PendingDeposit) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.PubKey[:], p.WithdrawalCredentials[:], p.Amount, p.Signature[:], p.Slot)
+}
+
+func (p *PendingDeposit) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, p.PubKey[:], p.WithdrawalCredentials[:], &p.Amount, p.Signature[:], &p.Slot)
+}
+
+func (p *PendingDeposit) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.PubKey[:], p.WithdrawalCredentials[:], p.Amount, p.Signature[:], p.Slot)
+}
+
+func (*PendingDeposit) EncodingSizeSSZ() int {
+	return 192
+}
+
+func (*PendingDeposit) Static() bool {
+	return true
+}
+
+// PendingPartialWithdrawal is a partial withdrawal requested by execution layer (EIP-7002), processed in withdrawals sweep.
+type PendingPartialWithdrawal struct {
+	Index             uint64 `json:"index,string"`
+	Amount            uint64 `json:"amount,string"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch,string"`
+}
+
+func (p *PendingPartialWithdrawal) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (p *PendingPartialWithdrawal) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.Index, &p.Amount, &p.WithdrawableEpoch)
+}
+
+func (p *PendingPartialWithdrawal) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.Index, p.Amount, p.WithdrawableEpoch)
+}
+
+func (*PendingPartialWithdrawal) EncodingSizeSSZ() int {
+	return 24
+}
+
+func (*PendingPartialWithdrawal) Static() bool {
+	return true
+}
+
+// PendingConsolidation moves balance of source validator to target validator once source is withdrawable (EIP-7251).
+type PendingConsolidation struct {
+	SourceIndex uint64 `json:"source_index,string"`
+	TargetIndex uint64 `json:"target_index,string"`
+}
+
+func (p *PendingConsolidation) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, p.SourceIndex, p.TargetIndex)
+}
+
+func (p *PendingConsolidation) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, &p.SourceIndex, &p.TargetIndex)
+}
+
+func (p *PendingConsolidation) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(p.SourceIndex, p.TargetIndex)
+}
+
+func (*PendingConsolidation) EncodingSizeSSZ() int {
+	return 16
+}
+
+func (*PendingConsolidation) Static() bool {
+	return true
+}
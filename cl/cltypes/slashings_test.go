@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +98,22 @@ func TestAttesterSlashing(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoot, common.Hash(root))
 }
+
+func TestAttesterSlashingElectraDecode(t *testing.T) {
+	attesterSlashing := NewAttesterSlashing(clparams.ElectraVersion)
+	// more indices than a pre-Electra indexed attestation can hold.
+	for i := uint64(0); i < 3000; i++ {
+		attesterSlashing.Attestation_1.AttestingIndices.Append(i)
+		attesterSlashing.Attestation_2.AttestingIndices.Append(i)
+	}
+	encodedData, err := attesterSlashing.EncodeSSZ(nil)
+	assert.NoError(t, err)
+	expectedRoot, err := attesterSlashing.HashSSZ()
+	assert.NoError(t, err)
+
+	decodedAttesterSlashing := &AttesterSlashing{}
+	assert.NoError(t, decodedAttesterSlashing.DecodeSSZ(encodedData, int(clparams.ElectraVersion)))
+	root, err := decodedAttesterSlashing.HashSSZ()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRoot, root)
+}
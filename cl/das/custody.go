@@ -0,0 +1,105 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package das implements the PeerDAS (EIP-7594) helpers: custody assignment and data column sidecars.
+//
+// PeerDAS is disabled unless EIP7594_FORK_EPOCH is set, on a local devnet it is enabled through the
+// --caplin.custom-config file. --caplin.custody-group-count picks how many custody groups a node samples
+// and serves, setting it to NUMBER_OF_CUSTODY_GROUPS makes the node a supernode holding every column.
+package das
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// GetCustodyGroups implements get_custody_groups, it deterministically assigns custodyGroupCount groups to a node.
+func GetCustodyGroups(cfg *clparams.BeaconChainConfig, nodeID [32]byte, custodyGroupCount uint64) ([]uint64, error) {
+	if custodyGroupCount > cfg.NumberOfCustodyGroups {
+		return nil, fmt.Errorf("custody group count %d is greater than %d", custodyGroupCount, cfg.NumberOfCustodyGroups)
+	}
+	currentID := new(uint256.Int).SetBytes32(nodeID[:])
+	groups := make([]uint64, 0, custodyGroupCount)
+	seen := make(map[uint64]struct{}, custodyGroupCount)
+	for uint64(len(groups)) < custodyGroupCount {
+		// uint_to_bytes is little endian.
+		idBytes := currentID.Bytes32()
+		for i, j := 0, len(idBytes)-1; i < j; i, j = i+1, j-1 {
+			idBytes[i], idBytes[j] = idBytes[j], idBytes[i]
+		}
+		digest := utils.Sha256(idBytes[:])
+		group := binary.LittleEndian.Uint64(digest[:8]) % cfg.NumberOfCustodyGroups
+		if _, ok := seen[group]; !ok {
+			seen[group] = struct{}{}
+			groups = append(groups, group)
+		}
+		// overflows back to 0 on UINT256_MAX.
+		currentID.AddUint64(currentID, 1)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+	return groups, nil
+}
+
+// ComputeColumnsForCustodyGroup implements compute_columns_for_custody_group.
+func ComputeColumnsForCustodyGroup(cfg *clparams.BeaconChainConfig, custodyGroup uint64) []uint64 {
+	columnsPerGroup := cfg.NumberOfColumns / cfg.NumberOfCustodyGroups
+	columns := make([]uint64, columnsPerGroup)
+	for i := range columns {
+		columns[i] = cfg.NumberOfCustodyGroups*uint64(i) + custodyGroup
+	}
+	return columns
+}
+
+// CustodyColumns returns the sorted column indices a node with the given id has to custody.
+func CustodyColumns(cfg *clparams.BeaconChainConfig, nodeID [32]byte, custodyGroupCount uint64) ([]uint64, error) {
+	groups, err := GetCustodyGroups(cfg, nodeID, custodyGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	var columns []uint64
+	for _, group := range groups {
+		columns = append(columns, ComputeColumnsForCustodyGroup(cfg, group)...)
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i] < columns[j] })
+	return columns, nil
+}
+
+// ComputeSubnetForDataColumnSidecar implements compute_subnet_for_data_column_sidecar.
+func ComputeSubnetForDataColumnSidecar(cfg *clparams.BeaconChainConfig, columnIndex uint64) uint64 {
+	return columnIndex % cfg.DataColumnSidecarSubnetCount
+}
+
+// CustodySubnets returns the gossip subnets carrying the given columns.
+func CustodySubnets(cfg *clparams.BeaconChainConfig, columns []uint64) []uint64 {
+	seen := make(map[uint64]struct{})
+	var subnets []uint64
+	for _, column := range columns {
+		subnet := ComputeSubnetForDataColumnSidecar(cfg, column)
+		if _, ok := seen[subnet]; ok {
+			continue
+		}
+		seen[subnet] = struct{}{}
+		subnets = append(subnets, subnet)
+	}
+	sort.Slice(subnets, func(i, j int) bool { return subnets[i] < subnets[j] })
+	return subnets
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package das
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/core/types"
+)
+
+func TestCustodyColumns(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	var nodeID [32]byte
+	_, err := rand.Read(nodeID[:])
+	require.NoError(t, err)
+
+	columns, err := CustodyColumns(cfg, nodeID, cfg.CustodyRequirement)
+	require.NoError(t, err)
+	require.Len(t, columns, int(cfg.CustodyRequirement*cfg.NumberOfColumns/cfg.NumberOfCustodyGroups))
+	again, err := CustodyColumns(cfg, nodeID, cfg.CustodyRequirement)
+	require.NoError(t, err)
+	require.Equal(t, columns, again)
+
+	// a super node custodies everything.
+	all, err := CustodyColumns(cfg, nodeID, cfg.NumberOfCustodyGroups)
+	require.NoError(t, err)
+	require.Len(t, all, int(cfg.NumberOfColumns))
+	for i, column := range all {
+		require.Equal(t, uint64(i), column)
+	}
+
+	// the maximum node id wraps around.
+	for i := range nodeID {
+		nodeID[i] = 0xff
+	}
+	groups, err := GetCustodyGroups(cfg, nodeID, 8)
+	require.NoError(t, err)
+	require.Len(t, groups, 8)
+
+	_, err = GetCustodyGroups(cfg, nodeID, cfg.NumberOfCustodyGroups+1)
+	require.Error(t, err)
+	require.Equal(t, uint64(3), ComputeSubnetForDataColumnSidecar(cfg, 3+cfg.DataColumnSidecarSubnetCount))
+}
+
+func TestDataColumnSidecars(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	block := cltypes.NewSignedBeaconBlock(cfg, clparams.DenebVersion)
+	block.Block.Slot = 100
+	block.Block.Body.SyncAggregate = &cltypes.SyncAggregate{}
+	blobGas := uint64(0)
+	block.Block.Body.ExecutionPayload = cltypes.NewEth1BlockFromHeaderAndBody(&types.Header{
+		Number:        big.NewInt(1),
+		BaseFee:       big.NewInt(1),
+		BlobGasUsed:   &blobGas,
+		ExcessBlobGas: &blobGas,
+	}, &types.RawBody{}, cfg)
+	blobs := make([]*cltypes.Blob, 2)
+	for i := range blobs {
+		blobs[i] = &cltypes.Blob{}
+		// keep every field element canonical.
+		for j := 0; j < cltypes.FIELD_ELEMENTS_PER_BLOB; j++ {
+			blobs[i][j*cltypes.BYTES_PER_FIELD_ELEMENT+31] = byte(i + j)
+		}
+		commitment, err := kzg.Ctx().BlobToKZGCommitment((gokzg4844.Blob)(*blobs[i]), 0)
+		require.NoError(t, err)
+		c := cltypes.KZGCommitment(commitment)
+		block.Block.Body.BlobKzgCommitments.Append(&c)
+	}
+
+	sidecars, err := ComputeDataColumnSidecars(cfg, block, blobs)
+	require.NoError(t, err)
+	require.Len(t, sidecars, int(cfg.NumberOfColumns))
+
+	sidecar := sidecars[5]
+	require.NoError(t, VerifyDataColumnSidecar(cfg, sidecar))
+	require.NoError(t, VerifyDataColumnSidecarInclusionProof(sidecar))
+	require.NoError(t, VerifyDataColumnSidecarKzgProofs(sidecar))
+
+	encoded, err := sidecar.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, encoded, sidecar.EncodingSizeSSZ())
+	decoded := &cltypes.DataColumnSidecar{}
+	require.NoError(t, decoded.DecodeSSZ(encoded, int(clparams.DenebVersion)))
+	expectedRoot, err := sidecar.HashSSZ()
+	require.NoError(t, err)
+	decodedRoot, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, decodedRoot)
+
+	// a sidecar moved to another column must not verify.
+	decoded.Index = 6
+	require.Error(t, VerifyDataColumnSidecarKzgProofs(decoded))
+
+	recovered, err := RecoverDataColumnSidecars(cfg, sidecars[len(sidecars)/2:])
+	require.NoError(t, err)
+	require.Len(t, recovered, len(sidecars))
+	for i := range sidecars {
+		expected, err := sidecars[i].HashSSZ()
+		require.NoError(t, err)
+		actual, err := recovered[i].HashSSZ()
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	_, err = RecoverDataColumnSidecars(cfg, sidecars[:len(sidecars)/2-1])
+	require.ErrorIs(t, err, kzg.ErrNotEnoughCells)
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package das
+
+import (
+	"errors"
+	"fmt"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
+
+var (
+	ErrInvalidColumnIndex    = errors.New("data column sidecar index out of range")
+	ErrNoKzgCommitments      = errors.New("data column sidecar has no kzg commitments")
+	ErrColumnLengthMismatch  = errors.New("data column sidecar column, commitments and proofs lengths mismatch")
+	ErrInclusionProofInvalid = errors.New("data column sidecar kzg commitments inclusion proof is invalid")
+	ErrMissingBlobs          = errors.New("blobs do not match the block kzg commitments")
+)
+
+// ComputeDataColumnSidecars implements get_data_column_sidecars, blobs are in the order of the block commitments.
+func ComputeDataColumnSidecars(cfg *clparams.BeaconChainConfig, block *cltypes.SignedBeaconBlock, blobs []*cltypes.Blob) ([]*cltypes.DataColumnSidecar, error) {
+	commitments := block.Block.Body.BlobKzgCommitments
+	if commitments.Len() == 0 {
+		return nil, nil
+	}
+	if commitments.Len() != len(blobs) {
+		return nil, ErrMissingBlobs
+	}
+	cells := make([][]kzg.Cell, len(blobs))
+	proofs := make([][]gokzg4844.KZGProof, len(blobs))
+	for i, blob := range blobs {
+		var err error
+		if cells[i], proofs[i], err = kzg.ComputeCellsAndKZGProofs((*gokzg4844.Blob)(blob)); err != nil {
+			return nil, fmt.Errorf("failed to compute cells of blob %d: %w", i, err)
+		}
+	}
+	inclusionProof, err := block.Block.Body.KzgCommitmentsMerkleProof()
+	if err != nil {
+		return nil, err
+	}
+	return buildDataColumnSidecars(cfg, block.SignedBeaconBlockHeader(), commitments, inclusionProof, cells, proofs), nil
+}
+
+func buildDataColumnSidecars(
+	cfg *clparams.BeaconChainConfig,
+	header *cltypes.SignedBeaconBlockHeader,
+	commitments *solid.ListSSZ[*cltypes.KZGCommitment],
+	inclusionProof [][32]byte,
+	cells [][]kzg.Cell,
+	proofs [][]gokzg4844.KZGProof,
+) []*cltypes.DataColumnSidecar {
+	sidecars := make([]*cltypes.DataColumnSidecar, cfg.NumberOfColumns)
+	for columnIndex := range sidecars {
+		sidecar := cltypes.NewDataColumnSidecar()
+		sidecar.Index = uint64(columnIndex)
+		sidecar.SignedBlockHeader = header
+		commitments.Range(func(_ int, commitment *cltypes.KZGCommitment, _ int) bool {
+			sidecar.KzgCommitments.Append(commitment)
+			return true
+		})
+		for i, proof := range inclusionProof {
+			sidecar.KzgCommitmentsInclusionProof.Set(i, proof)
+		}
+		for row := range cells {
+			cell := cltypes.Cell(cells[row][columnIndex])
+			proof := cltypes.KZGProof(proofs[row][columnIndex])
+			sidecar.Column.Append(&cell)
+			sidecar.KzgProofs.Append(&proof)
+		}
+		sidecars[columnIndex] = sidecar
+	}
+	return sidecars
+}
+
+// VerifyDataColumnSidecar implements verify_data_column_sidecar, the structural checks of a sidecar.
+func VerifyDataColumnSidecar(cfg *clparams.BeaconChainConfig, sidecar *cltypes.DataColumnSidecar) error {
+	if sidecar.Index >= cfg.NumberOfColumns {
+		return ErrInvalidColumnIndex
+	}
+	if sidecar.KzgCommitments.Len() == 0 {
+		return ErrNoKzgCommitments
+	}
+	if sidecar.Column.Len() != sidecar.KzgCommitments.Len() || sidecar.Column.Len() != sidecar.KzgProofs.Len() {
+		return ErrColumnLengthMismatch
+	}
+	return nil
+}
+
+// VerifyDataColumnSidecarInclusionProof implements verify_data_column_sidecar_inclusion_proof.
+func VerifyDataColumnSidecarInclusionProof(sidecar *cltypes.DataColumnSidecar) error {
+	if !sidecar.VerifyKzgCommitmentsInclusionProof() {
+		return ErrInclusionProofInvalid
+	}
+	return nil
+}
+
+// VerifyDataColumnSidecarKzgProofs implements verify_data_column_sidecar_kzg_proofs.
+func VerifyDataColumnSidecarKzgProofs(sidecar *cltypes.DataColumnSidecar) error {
+	n := sidecar.Column.Len()
+	commitments := make([]gokzg4844.KZGCommitment, n)
+	cellIndices := make([]uint64, n)
+	cells := make([]kzg.Cell, n)
+	proofs := make([]gokzg4844.KZGProof, n)
+	for i := 0; i < n; i++ {
+		commitments[i] = gokzg4844.KZGCommitment(*sidecar.KzgCommitments.Get(i))
+		cellIndices[i] = sidecar.Index
+		cells[i] = kzg.Cell(*sidecar.Column.Get(i))
+		proofs[i] = gokzg4844.KZGProof(*sidecar.KzgProofs.Get(i))
+	}
+	return kzg.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+}
+
+// RecoverDataColumnSidecars rebuilds every column of a block from at least half of them, the sidecars are
+// expected to be verified and to belong to the same block.
+func RecoverDataColumnSidecars(cfg *clparams.BeaconChainConfig, sidecars []*cltypes.DataColumnSidecar) ([]*cltypes.DataColumnSidecar, error) {
+	if uint64(len(sidecars))*2 < cfg.NumberOfColumns {
+		return nil, kzg.ErrNotEnoughCells
+	}
+	first := sidecars[0]
+	blobCount := first.KzgCommitments.Len()
+	cellIndices := make([]uint64, len(sidecars))
+	for i, sidecar := range sidecars {
+		if sidecar.Column.Len() != blobCount {
+			return nil, ErrColumnLengthMismatch
+		}
+		cellIndices[i] = sidecar.Index
+	}
+	cells := make([][]kzg.Cell, blobCount)
+	proofs := make([][]gokzg4844.KZGProof, blobCount)
+	for row := 0; row < blobCount; row++ {
+		rowCells := make([]kzg.Cell, len(sidecars))
+		for i, sidecar := range sidecars {
+			rowCells[i] = kzg.Cell(*sidecar.Column.Get(row))
+		}
+		var err error
+		if cells[row], proofs[row], err = kzg.RecoverCellsAndKZGProofs(cellIndices, rowCells); err != nil {
+			return nil, fmt.Errorf("failed to recover blob %d: %w", row, err)
+		}
+	}
+	inclusionProof := make([][32]byte, cltypes.KzgCommitmentsInclusionProofDepth)
+	for i := range inclusionProof {
+		inclusionProof[i] = first.KzgCommitmentsInclusionProof.Get(i)
+	}
+	return buildDataColumnSidecars(cfg, first.SignedBlockHeader, first.KzgCommitments, inclusionProof, cells, proofs), nil
+}
@@ -1,6 +1,8 @@
 package monitor
 
 import (
+	"context"
+
 	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 )
@@ -10,6 +12,10 @@ type ValidatorMonitor interface {
 	ObserveValidator(vid uint64)
 	RemoveValidator(vid uint64)
 	OnNewBlock(state *state.CachingBeaconState, block *cltypes.BeaconBlock) error
+	// TrackedValidators returns the indices of the observed validators.
+	TrackedValidators() []uint64
+	// ReadReports returns the persisted epoch reports of vid for the epochs in [fromEpoch, toEpoch].
+	ReadReports(ctx context.Context, vid, fromEpoch, toEpoch uint64) ([]*ValidatorEpochReport, error)
 }
 
 type dummyValdatorMonitor struct{}
@@ -21,3 +27,11 @@ func (d *dummyValdatorMonitor) RemoveValidator(vid uint64) {}
 func (d *dummyValdatorMonitor) OnNewBlock(_ *state.CachingBeaconState, _ *cltypes.BeaconBlock) error {
 	return nil
 }
+
+func (d *dummyValdatorMonitor) TrackedValidators() []uint64 {
+	return []uint64{}
+}
+
+func (d *dummyValdatorMonitor) ReadReports(_ context.Context, _, _, _ uint64) ([]*ValidatorEpochReport, error) {
+	return []*ValidatorEpochReport{}, nil
+}
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -42,6 +43,8 @@ var (
 	// Network metrics
 	gossipTopicsMetricCounterPrefix = "gossip_topics_seen"
 	gossipMetricsMap                = sync.Map{}
+	gossipValidationMetricPrefix    = "gossip_validation"
+	gossipValidationMetricsMap      = sync.Map{}
 	aggregateQuality50Per           = metrics.GetOrCreateGauge("aggregate_quality_50")
 	aggregateQuality25Per           = metrics.GetOrCreateGauge("aggregate_quality_25")
 	aggregateQuality75Per           = metrics.GetOrCreateGauge("aggregate_quality_75")
@@ -205,6 +208,16 @@ func ObserveGossipTopicSeen(topic string, l int) {
 	metric.Add(float64(l))
 }
 
+// ObserveGossipValidation increments the gossip validation result metric of a service
+func ObserveGossipValidation(service string, result string) {
+	name := gossipValidationMetricPrefix + "_" + service + "_" + result
+	metricI, ok := gossipValidationMetricsMap.Load(name)
+	if !ok {
+		metricI, _ = gossipValidationMetricsMap.LoadOrStore(name, metrics.GetOrCreateCounter(name))
+	}
+	metricI.(metrics.Counter).Inc()
+}
+
 func ObserveAggregateQuality(participationCount int, totalCount int) {
 	aggregateQualityMetricStruct.observe(participationCount, totalCount)
 }
@@ -252,3 +265,18 @@ func ObserveBlobVerificationTime(startTime time.Time) {
 func ObserveExecutionTime(startTime time.Time) {
 	executionTime.Set(microToMilli(time.Since(startTime).Microseconds()))
 }
+
+// observeValidatorEpochReport exposes the epoch report of an observed validator as per-validator metrics.
+func observeValidatorEpochReport(report *ValidatorEpochReport) {
+	label := fmt.Sprintf(`{validator="%d"}`, report.ValidatorIndex)
+	metrics.GetOrCreateGauge("validator_monitor_epoch_reward" + label).Set(float64(report.TotalReward()))
+	if report.AttestationIncluded {
+		metrics.GetOrCreateGauge("validator_monitor_inclusion_distance" + label).Set(float64(report.InclusionDistance))
+	} else {
+		metrics.GetOrCreateCounter("validator_monitor_missed_attestations" + label).Inc()
+	}
+	metrics.GetOrCreateCounter("validator_monitor_proposals_included" + label).Add(float64(report.ProposalsIncluded))
+	metrics.GetOrCreateCounter("validator_monitor_proposals_missed" + label).Add(float64(report.ProposalsScheduled - report.ProposalsIncluded))
+	metrics.GetOrCreateCounter("validator_monitor_sync_committee_hit" + label).Add(float64(report.SyncCommitteeParticipations))
+	metrics.GetOrCreateCounter("validator_monitor_sync_committee_miss" + label).Add(float64(report.SyncCommitteeMisses))
+}
@@ -10,9 +10,11 @@
 package mock_services
 
 import (
+	context "context"
 	reflect "reflect"
 
 	cltypes "github.com/erigontech/erigon/cl/cltypes"
+	monitor "github.com/erigontech/erigon/cl/monitor"
 	state "github.com/erigontech/erigon/cl/phase1/core/state"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -115,6 +117,45 @@ func (c *MockValidatorMonitorOnNewBlockCall) DoAndReturn(f func(*state.CachingBe
 	return c
 }
 
+// ReadReports mocks base method.
+func (m *MockValidatorMonitor) ReadReports(ctx context.Context, vid, fromEpoch, toEpoch uint64) ([]*monitor.ValidatorEpochReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadReports", ctx, vid, fromEpoch, toEpoch)
+	ret0, _ := ret[0].([]*monitor.ValidatorEpochReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadReports indicates an expected call of ReadReports.
+func (mr *MockValidatorMonitorMockRecorder) ReadReports(ctx, vid, fromEpoch, toEpoch any) *MockValidatorMonitorReadReportsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadReports", reflect.TypeOf((*MockValidatorMonitor)(nil).ReadReports), ctx, vid, fromEpoch, toEpoch)
+	return &MockValidatorMonitorReadReportsCall{Call: call}
+}
+
+// MockValidatorMonitorReadReportsCall wrap *gomock.Call
+type MockValidatorMonitorReadReportsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockValidatorMonitorReadReportsCall) Return(arg0 []*monitor.ValidatorEpochReport, arg1 error) *MockValidatorMonitorReadReportsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockValidatorMonitorReadReportsCall) Do(f func(context.Context, uint64, uint64, uint64) ([]*monitor.ValidatorEpochReport, error)) *MockValidatorMonitorReadReportsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockValidatorMonitorReadReportsCall) DoAndReturn(f func(context.Context, uint64, uint64, uint64) ([]*monitor.ValidatorEpochReport, error)) *MockValidatorMonitorReadReportsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // RemoveValidator mocks base method.
 func (m *MockValidatorMonitor) RemoveValidator(vid uint64) {
 	m.ctrl.T.Helper()
@@ -150,3 +191,41 @@ func (c *MockValidatorMonitorRemoveValidatorCall) DoAndReturn(f func(uint64)) *M
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// TrackedValidators mocks base method.
+func (m *MockValidatorMonitor) TrackedValidators() []uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackedValidators")
+	ret0, _ := ret[0].([]uint64)
+	return ret0
+}
+
+// TrackedValidators indicates an expected call of TrackedValidators.
+func (mr *MockValidatorMonitorMockRecorder) TrackedValidators() *MockValidatorMonitorTrackedValidatorsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackedValidators", reflect.TypeOf((*MockValidatorMonitor)(nil).TrackedValidators))
+	return &MockValidatorMonitorTrackedValidatorsCall{Call: call}
+}
+
+// MockValidatorMonitorTrackedValidatorsCall wrap *gomock.Call
+type MockValidatorMonitorTrackedValidatorsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockValidatorMonitorTrackedValidatorsCall) Return(arg0 []uint64) *MockValidatorMonitorTrackedValidatorsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockValidatorMonitorTrackedValidatorsCall) Do(f func() []uint64) *MockValidatorMonitorTrackedValidatorsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockValidatorMonitorTrackedValidatorsCall) DoAndReturn(f func() []uint64) *MockValidatorMonitorTrackedValidatorsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
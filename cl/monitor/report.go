@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/erigontech/erigon-lib/kv"
+)
+
+// ValidatorEpochReport is the performance of a monitored validator during one epoch.
+type ValidatorEpochReport struct {
+	ValidatorIndex uint64 `json:"validator_index,string"`
+	Epoch          uint64 `json:"epoch,string"`
+	// AttestationIncluded is whether an attestation of the validator for this epoch was included on chain.
+	AttestationIncluded bool `json:"attestation_included"`
+	// InclusionDistance is the minimum distance between the attestation slot and the slot of the including block.
+	InclusionDistance  uint64 `json:"inclusion_distance,string"`
+	ProposalsScheduled uint64 `json:"proposals_scheduled,string"`
+	ProposalsIncluded  uint64 `json:"proposals_included,string"`
+	// Sync committee messages of the validator included (or missing) in the blocks of this epoch.
+	SyncCommitteeParticipations uint64 `json:"sync_committee_participations,string"`
+	SyncCommitteeMisses         uint64 `json:"sync_committee_misses,string"`
+	// Realized rewards and penalties, in gwei.
+	HeadReward          int64 `json:"head_reward,string"`
+	TargetReward        int64 `json:"target_reward,string"`
+	SourceReward        int64 `json:"source_reward,string"`
+	InactivityPenalty   int64 `json:"inactivity_penalty,string"`
+	SyncCommitteeReward int64 `json:"sync_committee_reward,string"`
+}
+
+// TotalReward returns the sum of all the rewards and penalties of the report.
+func (r *ValidatorEpochReport) TotalReward() int64 {
+	return r.HeadReward + r.TargetReward + r.SourceReward + r.InactivityPenalty + r.SyncCommitteeReward
+}
+
+func reportKey(vid, epoch uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, vid)
+	binary.BigEndian.PutUint64(key[8:], epoch)
+	return key
+}
+
+func writeReports(ctx context.Context, db kv.RwDB, reports []*ValidatorEpochReport) error {
+	return db.Update(ctx, func(tx kv.RwTx) error {
+		var buf bytes.Buffer
+		for _, report := range reports {
+			buf.Reset()
+			if err := binary.Write(&buf, binary.BigEndian, report); err != nil {
+				return err
+			}
+			if err := tx.Put(kv.ValidatorMonitorReports, reportKey(report.ValidatorIndex, report.Epoch), buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// readReports reads the reports of vid for the epochs in [fromEpoch, toEpoch].
+func readReports(ctx context.Context, db kv.RoDB, vid, fromEpoch, toEpoch uint64) ([]*ValidatorEpochReport, error) {
+	reports := []*ValidatorEpochReport{}
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		c, err := tx.Cursor(kv.ValidatorMonitorReports)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		end := reportKey(vid, toEpoch)
+		for k, v, err := c.Seek(reportKey(vid, fromEpoch)); k != nil; k, v, err = c.Next() {
+			if err != nil {
+				return err
+			}
+			if bytes.Compare(k, end) > 0 {
+				break
+			}
+			report := &ValidatorEpochReport{}
+			if err := binary.Read(bytes.NewReader(v), binary.BigEndian, report); err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
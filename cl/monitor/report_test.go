@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/kv/memdb"
+)
+
+func TestValidatorEpochReportsPersistence(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+
+	reports := []*ValidatorEpochReport{
+		{ValidatorIndex: 1, Epoch: 9, AttestationIncluded: true, InclusionDistance: 1, HeadReward: 10, TargetReward: 20, SourceReward: 30},
+		{ValidatorIndex: 1, Epoch: 10, InactivityPenalty: -40, SyncCommitteeMisses: 2, SyncCommitteeReward: -8},
+		{ValidatorIndex: 1, Epoch: 11, AttestationIncluded: true, ProposalsScheduled: 1, ProposalsIncluded: 1},
+		{ValidatorIndex: 2, Epoch: 10, AttestationIncluded: true, SyncCommitteeParticipations: 32, SyncCommitteeReward: 128},
+	}
+	require.NoError(t, writeReports(ctx, db, reports))
+
+	got, err := readReports(ctx, db, 1, 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, reports[1:3], got)
+	require.Equal(t, int64(-48), got[0].TotalReward())
+
+	got, err = readReports(ctx, db, 2, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, reports[3:], got)
+
+	got, err = readReports(ctx, db, 3, 0, 100)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestValidatorStatusReport(t *testing.T) {
+	statuses := newValidatorStatuses()
+	require.Nil(t, statuses.getValidatorStatus(5, 1))
+	statuses.addValidator(5)
+	statuses.addValidator(3)
+	require.Equal(t, []uint64{3, 5}, statuses.validators())
+
+	status := statuses.getValidatorStatus(5, 1)
+	report := &ValidatorEpochReport{}
+	status.fillReport(report)
+	require.False(t, report.AttestationIncluded)
+	require.Zero(t, report.InclusionDistance)
+
+	status.attestedBlockRoots.Add([32]byte{1})
+	status.inclusionDistance = min(status.inclusionDistance, 3)
+	status.inclusionDistance = min(status.inclusionDistance, 2)
+	status.updateProposerStatus(true)
+	status.updateProposerStatus(false)
+	status.updateSyncCommitteeStatus(true, 4)
+	status.updateSyncCommitteeStatus(false, 4)
+	status.updateSyncCommitteeStatus(true, 4)
+	status.fillReport(report)
+	require.Equal(t, ValidatorEpochReport{
+		AttestationIncluded:         true,
+		InclusionDistance:           2,
+		ProposalsScheduled:          2,
+		ProposalsIncluded:           1,
+		SyncCommitteeParticipations: 2,
+		SyncCommitteeMisses:         1,
+		SyncCommitteeReward:         4,
+	}, *report)
+}
@@ -1,12 +1,17 @@
 package monitor
 
 import (
+	"context"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/beacon/rewards"
 	"github.com/erigontech/erigon/cl/beacon/synced_data"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
@@ -20,6 +25,7 @@ type validatorMonitorImpl struct {
 	ethClock         eth_clock.EthereumClock
 	beaconCfg        *clparams.BeaconChainConfig
 	vaidatorStatuses *validatorStatuses // map validatorID -> epoch -> validatorStatus
+	db               kv.RwDB            // persisted epoch reports, may be nil
 }
 
 func NewValidatorMonitor(
@@ -27,8 +33,10 @@ func NewValidatorMonitor(
 	ethClock eth_clock.EthereumClock,
 	beaconConfig *clparams.BeaconChainConfig,
 	syncedData *synced_data.SyncedDataManager,
+	db kv.RwDB,
+	trackedValidators []uint64,
 ) ValidatorMonitor {
-	if !enableMonitor {
+	if !enableMonitor && len(trackedValidators) == 0 {
 		return &dummyValdatorMonitor{}
 	}
 
@@ -37,8 +45,12 @@ func NewValidatorMonitor(
 		beaconCfg:        beaconConfig,
 		syncedData:       syncedData,
 		vaidatorStatuses: newValidatorStatuses(),
+		db:               db,
 	}
-	go m.runReportAttesterStatus()
+	for _, vid := range trackedValidators {
+		m.ObserveValidator(vid)
+	}
+	go m.runReportEpochStatus()
 	go m.runReportProposerStatus()
 	return m
 }
@@ -51,6 +63,17 @@ func (m *validatorMonitorImpl) RemoveValidator(vid uint64) {
 	m.vaidatorStatuses.removeValidator(vid)
 }
 
+func (m *validatorMonitorImpl) TrackedValidators() []uint64 {
+	return m.vaidatorStatuses.validators()
+}
+
+func (m *validatorMonitorImpl) ReadReports(ctx context.Context, vid, fromEpoch, toEpoch uint64) ([]*ValidatorEpochReport, error) {
+	if m.db == nil {
+		return []*ValidatorEpochReport{}, nil
+	}
+	return readReports(ctx, m.db, vid, fromEpoch, toEpoch)
+}
+
 func (m *validatorMonitorImpl) OnNewBlock(state *state.CachingBeaconState, block *cltypes.BeaconBlock) error {
 	var (
 		atts         = block.Body.Attestations
@@ -77,7 +100,7 @@ func (m *validatorMonitorImpl) OnNewBlock(state *state.CachingBeaconState, block
 			if status == nil {
 				continue
 			}
-			status.updateAttesterStatus(att)
+			status.updateAttesterStatus(att, block.Slot-slot)
 		}
 		return true
 	})
@@ -86,36 +109,128 @@ func (m *validatorMonitorImpl) OnNewBlock(state *state.CachingBeaconState, block
 	if status := m.vaidatorStatuses.getValidatorStatus(pIndex, blockEpoch); status != nil {
 		status.proposeSlots.Add(block.Slot)
 	}
+	// update sync committee status
+	if block.Version() >= clparams.AltairVersion && block.Body.SyncAggregate != nil {
+		m.updateSyncCommitteeStatus(state, block, blockEpoch)
+	}
 
 	return nil
 }
 
-func (m *validatorMonitorImpl) runReportAttesterStatus() {
-	// every epoch seconds
-	epochDuration := time.Duration(m.beaconCfg.SlotsPerEpoch) * time.Duration(m.beaconCfg.SecondsPerSlot) * time.Second
-	ticker := time.NewTicker(epochDuration)
-	for range ticker.C {
-		currentEpoch := m.ethClock.GetCurrentEpoch()
-		// report attester status for current_epoch - 2
-		epoch := currentEpoch - 2
-		hitCount := 0
-		missCount := 0
-		m.vaidatorStatuses.iterate(func(vindex uint64, epochStatuses map[uint64]*validatorStatus) {
-			if status, ok := epochStatuses[epoch]; ok {
-				successAtt := status.attestedBlockRoots.Cardinality()
-				metricAttestHit.AddInt(successAtt)
-				hitCount += successAtt
-				delete(epochStatuses, epoch)
-				log.Debug("[monitor] report attester status hit", "epoch", epoch, "vindex", vindex, "countAttestedBlock", status.attestedBlockRoots.Cardinality())
-			} else {
-				metricAttestMiss.AddInt(1)
-				missCount++
-				log.Debug("[monitor] report attester status miss", "epoch", epoch, "vindex", vindex, "countAttestedBlock", 0)
+func (m *validatorMonitorImpl) updateSyncCommitteeStatus(s *state.CachingBeaconState, block *cltypes.BeaconBlock, blockEpoch uint64) {
+	committee := s.CurrentSyncCommittee()
+	if committee == nil {
+		return
+	}
+	participantReward := int64(rewards.SyncParticipantReward(m.beaconCfg, s.GetTotalActiveBalance()))
+	for i, pubkey := range committee.GetCommittee() {
+		vidx, ok := s.ValidatorIndexByPubkey(pubkey)
+		if !ok {
+			continue
+		}
+		status := m.vaidatorStatuses.getValidatorStatus(vidx, blockEpoch)
+		if status == nil {
+			continue
+		}
+		status.updateSyncCommitteeStatus(block.Body.SyncAggregate.IsSet(uint64(i)), participantReward)
+	}
+}
+
+// runReportEpochStatus reports, towards the end of every epoch, the performance of the observed validators during the
+// previous one. Attestations for an epoch can be included until the end of the next one, so this is the earliest
+// point where the report is final.
+func (m *validatorMonitorImpl) runReportEpochStatus() {
+	for {
+		epoch := m.ethClock.GetCurrentEpoch()
+		lastSlot := (epoch+1)*m.beaconCfg.SlotsPerEpoch - 1
+		reportTime := m.ethClock.GetSlotTime(lastSlot).Add(time.Duration(m.beaconCfg.SecondsPerSlot) * time.Second * 2 / 3)
+		time.Sleep(time.Until(reportTime))
+		if epoch == 0 {
+			time.Sleep(time.Duration(m.beaconCfg.SecondsPerSlot) * time.Second)
+			continue
+		}
+		m.reportEpochStatus(epoch - 1)
+		// make sure we move past the report time before computing the next one
+		time.Sleep(time.Until(m.ethClock.GetSlotTime(lastSlot + 1)))
+	}
+}
+
+func (m *validatorMonitorImpl) reportEpochStatus(epoch uint64) {
+	attestationRewards := m.computeAttestationRewards(epoch + 1)
+	hitCount := 0
+	missCount := 0
+	reports := []*ValidatorEpochReport{}
+	m.vaidatorStatuses.iterate(func(vindex uint64, epochStatuses map[uint64]*validatorStatus) {
+		report := &ValidatorEpochReport{ValidatorIndex: vindex, Epoch: epoch}
+		if status, ok := epochStatuses[epoch]; ok {
+			status.fillReport(report)
+		}
+		if report.AttestationIncluded {
+			metricAttestHit.AddInt(1)
+			hitCount++
+			log.Debug("[monitor] report attester status hit", "epoch", epoch, "vindex", vindex, "inclusionDistance", report.InclusionDistance)
+		} else {
+			metricAttestMiss.AddInt(1)
+			missCount++
+			log.Debug("[monitor] report attester status miss", "epoch", epoch, "vindex", vindex)
+		}
+		if reward, ok := attestationRewards[vindex]; ok {
+			report.HeadReward = reward.Head
+			report.TargetReward = reward.Target
+			report.SourceReward = reward.Source
+			report.InactivityPenalty = reward.Inactivity
+		}
+		for e := range epochStatuses {
+			if e <= epoch {
+				delete(epochStatuses, e)
 			}
-		})
-		log.Info("[monitor] report attester hit/miss", "epoch", epoch, "hitCount", hitCount, "missCount", missCount, "cur_epoch", currentEpoch)
+		}
+		observeValidatorEpochReport(report)
+		reports = append(reports, report)
+	})
+	log.Info("[monitor] report attester hit/miss", "epoch", epoch, "hitCount", hitCount, "missCount", missCount, "cur_epoch", m.ethClock.GetCurrentEpoch())
+	if m.db == nil || len(reports) == 0 {
+		return
+	}
+	if err := writeReports(context.Background(), m.db, reports); err != nil {
+		log.Warn("[monitor] failed to persist validator reports", "epoch", epoch, "err", err)
 	}
+}
 
+// computeAttestationRewards computes the attestation rewards of the observed validators for the epoch before epoch,
+// using the head state. It returns nothing if the head state is not at epoch or predates Altair.
+func (m *validatorMonitorImpl) computeAttestationRewards(epoch uint64) map[uint64]rewards.TotalReward {
+	ret := map[uint64]rewards.TotalReward{}
+	headState := m.syncedData.HeadState()
+	if headState == nil || headState.Version() < clparams.AltairVersion || state.Epoch(headState) != epoch {
+		return ret
+	}
+	validatorSet := headState.ValidatorSet()
+	filter := []uint64{}
+	for _, vid := range m.TrackedValidators() {
+		if vid < uint64(validatorSet.Length()) {
+			filter = append(filter, vid)
+		}
+	}
+	if len(filter) == 0 {
+		return ret
+	}
+	resp, err := rewards.ComputeAttestationsRewardsForAltair(
+		m.beaconCfg,
+		validatorSet,
+		headState.InactivityScores(),
+		headState.PreviousEpochParticipation(),
+		rewards.IsInactivityLeaking(m.beaconCfg, epoch, headState.FinalizedCheckpoint()),
+		filter,
+		epoch)
+	if err != nil {
+		log.Warn("[monitor] failed to compute attestation rewards", "epoch", epoch, "err", err)
+		return ret
+	}
+	for _, reward := range resp.TotalRewards {
+		ret[uint64(reward.ValidatorIndex)] = reward
+	}
+	return ret
 }
 
 func (m *validatorMonitorImpl) runReportProposerStatus() {
@@ -132,10 +247,12 @@ func (m *validatorMonitorImpl) runReportProposerStatus() {
 		proposerIndex, err := headState.GetBeaconProposerIndexForSlot(prevSlot)
 		if err != nil {
 			log.Warn("failed to get proposer index", "slot", prevSlot, "err", err)
-			return
+			continue
 		}
 		if status := m.vaidatorStatuses.getValidatorStatus(proposerIndex, prevSlot/m.beaconCfg.SlotsPerEpoch); status != nil {
-			if status.proposeSlots.Contains(prevSlot) {
+			hit := status.proposeSlots.Contains(prevSlot)
+			status.updateProposerStatus(hit)
+			if hit {
 				metricProposerHit.AddInt(1)
 				log.Info("[monitor] proposer hit", "slot", prevSlot, "proposerIndex", proposerIndex)
 			} else {
@@ -151,11 +268,57 @@ type validatorStatus struct {
 	attestedBlockRoots mapset.Set[common.Hash]
 	// proposeSlots is the set of slots that the proposer has successfully proposed blocks during one epoch.
 	proposeSlots mapset.Set[uint64]
+
+	mu                          sync.Mutex
+	inclusionDistance           uint64
+	proposalsScheduled          uint64
+	proposalsIncluded           uint64
+	syncCommitteeParticipations uint64
+	syncCommitteeMisses         uint64
+	syncCommitteeReward         int64
 }
 
-func (s *validatorStatus) updateAttesterStatus(att *solid.Attestation) {
+func (s *validatorStatus) updateAttesterStatus(att *solid.Attestation, inclusionDistance uint64) {
 	data := att.Data
 	s.attestedBlockRoots.Add(data.BeaconBlockRoot)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inclusionDistance = min(s.inclusionDistance, inclusionDistance)
+}
+
+func (s *validatorStatus) updateProposerStatus(included bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proposalsScheduled++
+	if included {
+		s.proposalsIncluded++
+	}
+}
+
+func (s *validatorStatus) updateSyncCommitteeStatus(participated bool, participantReward int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if participated {
+		s.syncCommitteeParticipations++
+		s.syncCommitteeReward += participantReward
+	} else {
+		s.syncCommitteeMisses++
+		s.syncCommitteeReward -= participantReward
+	}
+}
+
+func (s *validatorStatus) fillReport(report *ValidatorEpochReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report.AttestationIncluded = s.attestedBlockRoots.Cardinality() > 0
+	if report.AttestationIncluded {
+		report.InclusionDistance = s.inclusionDistance
+	}
+	report.ProposalsScheduled = s.proposalsScheduled
+	report.ProposalsIncluded = s.proposalsIncluded
+	report.SyncCommitteeParticipations = s.syncCommitteeParticipations
+	report.SyncCommitteeMisses = s.syncCommitteeMisses
+	report.SyncCommitteeReward = s.syncCommitteeReward
 }
 
 type validatorStatuses struct {
@@ -182,6 +345,7 @@ func (s *validatorStatuses) getValidatorStatus(vid uint64, epoch uint64) *valida
 		statusByEpoch[epoch] = &validatorStatus{
 			attestedBlockRoots: mapset.NewSet[common.Hash](),
 			proposeSlots:       mapset.NewSet[uint64](),
+			inclusionDistance:  math.MaxUint64,
 		}
 	}
 
@@ -206,6 +370,17 @@ func (s *validatorStatuses) removeValidator(vid uint64) {
 	}
 }
 
+func (s *validatorStatuses) validators() []uint64 {
+	s.vStatusMutex.RLock()
+	defer s.vStatusMutex.RUnlock()
+	vids := make([]uint64, 0, len(s.statuses))
+	for vid := range s.statuses {
+		vids = append(vids, vid)
+	}
+	slices.Sort(vids)
+	return vids
+}
+
 func (s *validatorStatuses) iterate(run func(vid uint64, statuses map[uint64]*validatorStatus)) {
 	s.vStatusMutex.Lock()
 	defer s.vStatusMutex.Unlock()
@@ -443,3 +443,29 @@ func AddBlockRootToParentRootsIndex(tx kv.RwTx, parentRoot, blockRoot libcommon.
 	roots = append(libcommon.Copy(roots), blockRoot[:]...)
 	return tx.Put(kv.ParentRootToBlockRoots, parentRoot[:], roots)
 }
+
+// WriteLightClientUpdate stores the light client update of a sync committee period, prefixed by its fork version.
+func WriteLightClientUpdate(tx kv.RwTx, period uint64, update *cltypes.LightClientUpdate) error {
+	encoded, err := update.EncodeSSZ([]byte{byte(update.AttestedHeader.Version())})
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period), encoded)
+}
+
+// ReadLightClientUpdate reads the light client update of a sync committee period, nil if none is stored.
+func ReadLightClientUpdate(tx kv.Tx, period uint64) (*cltypes.LightClientUpdate, error) {
+	val, err := tx.GetOne(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period))
+	if err != nil {
+		return nil, err
+	}
+	if len(val) == 0 {
+		return nil, nil
+	}
+	version := clparams.StateVersion(val[0])
+	update := cltypes.NewLightClientUpdate(version)
+	if err := update.DecodeSSZ(val[1:], int(version)); err != nil {
+		return nil, fmt.Errorf("failed to decode light client update of period %d: %w", period, err)
+	}
+	return update, nil
+}
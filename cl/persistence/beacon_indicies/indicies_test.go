@@ -188,3 +188,29 @@ func TestWriteExecutionBlockHash(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, tHash2, tHash3)
 }
+
+func TestLightClientUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	tx, _ := db.BeginRw(context.Background())
+	defer tx.Rollback()
+
+	update, err := ReadLightClientUpdate(tx, 3)
+	require.NoError(t, err)
+	require.Nil(t, update)
+
+	expected := cltypes.NewLightClientUpdate(clparams.CapellaVersion)
+	expected.AttestedHeader.Beacon.Slot = 3 * 8192
+	expected.SignatureSlot = 3*8192 + 1
+	expected.SyncAggregate.SyncCommiteeBits[0] = 0xff
+	require.NoError(t, WriteLightClientUpdate(tx, 3, expected))
+
+	update, err = ReadLightClientUpdate(tx, 3)
+	require.NoError(t, err)
+	require.Equal(t, clparams.CapellaVersion, update.AttestedHeader.Version())
+	expectedRoot, err := expected.HashSSZ()
+	require.NoError(t, err)
+	root, err := update.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, root)
+}
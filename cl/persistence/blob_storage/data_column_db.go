@@ -0,0 +1,219 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package blob_storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/das"
+	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+	"github.com/spf13/afero"
+)
+
+type DataColumnStorage interface {
+	WriteColumnSidecars(ctx context.Context, blockRoot libcommon.Hash, sidecars []*cltypes.DataColumnSidecar) error
+	ReadColumnSidecar(ctx context.Context, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (sidecar *cltypes.DataColumnSidecar, found bool, err error)
+	ColumnSidecarIndices(ctx context.Context, slot uint64, blockRoot libcommon.Hash) ([]uint64, error)
+	RemoveColumnSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) error
+	WriteStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) error // Used for P2P networking
+	Prune() error
+}
+
+type DataColumnStore struct {
+	fs                afero.Fs
+	beaconChainConfig *clparams.BeaconChainConfig
+	ethClock          eth_clock.EthereumClock
+	slotsKept         uint64
+}
+
+func NewDataColumnStore(fs afero.Fs, slotsKept uint64, beaconChainConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock) DataColumnStorage {
+	return &DataColumnStore{fs: fs, slotsKept: slotsKept, beaconChainConfig: beaconChainConfig, ethClock: ethClock}
+}
+
+/*
+file system layout: <slot/subdivisionSlot>/<blockRoot>/<columnIndex>
+the folder of a block root doubles as the index of the columns we have for it.
+*/
+func dataColumnFolderPath(slot uint64, blockRoot libcommon.Hash) string {
+	return strconv.FormatUint(slot/subdivisionSlot, 10) + "/" + blockRoot.String()
+}
+
+func dataColumnFilePath(slot uint64, blockRoot libcommon.Hash, columnIndex uint64) string {
+	return dataColumnFolderPath(slot, blockRoot) + "/" + strconv.FormatUint(columnIndex, 10)
+}
+
+// WriteColumnSidecars writes the sidecars on disk, all sidecars are expected to be for the same blockRoot. Columns we already have are left untouched.
+func (ds *DataColumnStore) WriteColumnSidecars(ctx context.Context, blockRoot libcommon.Hash, sidecars []*cltypes.DataColumnSidecar) error {
+	for _, sidecar := range sidecars {
+		slot := sidecar.SignedBlockHeader.Header.Slot
+		filePath := dataColumnFilePath(slot, blockRoot, sidecar.Index)
+		if _, err := ds.fs.Stat(filePath); err == nil {
+			continue
+		}
+		if err := ds.fs.MkdirAll(dataColumnFolderPath(slot, blockRoot), 0755); err != nil {
+			return err
+		}
+		// write in a temporary file first, so that a crash never leaves a truncated column around.
+		tmpPath := filePath + ".tmp"
+		if err := ds.writeColumnFile(tmpPath, sidecar); err != nil {
+			return err
+		}
+		if err := ds.fs.Rename(tmpPath, filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds *DataColumnStore) writeColumnFile(filePath string, sidecar *cltypes.DataColumnSidecar) error {
+	file, err := ds.fs.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := ssz_snappy.EncodeAndWrite(file, sidecar); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// ReadColumnSidecar reads one column of a block, found is false if we do not have it.
+func (ds *DataColumnStore) ReadColumnSidecar(ctx context.Context, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (*cltypes.DataColumnSidecar, bool, error) {
+	file, err := ds.fs.Open(dataColumnFilePath(slot, blockRoot, columnIndex))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer file.Close()
+	sidecar := cltypes.NewDataColumnSidecar()
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(file, sidecar, clparams.DenebVersion); err != nil {
+		return nil, false, err
+	}
+	return sidecar, true, nil
+}
+
+// ColumnSidecarIndices returns the sorted indices of the columns we have for a block.
+func (ds *DataColumnStore) ColumnSidecarIndices(ctx context.Context, slot uint64, blockRoot libcommon.Hash) ([]uint64, error) {
+	entries, err := afero.ReadDir(ds.fs, dataColumnFolderPath(slot, blockRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	indices := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		index, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil {
+			continue // leftover temporary file.
+		}
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices, nil
+}
+
+func (ds *DataColumnStore) RemoveColumnSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) error {
+	return ds.fs.RemoveAll(dataColumnFolderPath(slot, blockRoot))
+}
+
+func (ds *DataColumnStore) WriteStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) error {
+	file, err := ds.fs.Open(dataColumnFilePath(slot, blockRoot, columnIndex))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// Prune removes the columns older than slotsKept, same as the blob sidecars.
+func (ds *DataColumnStore) Prune() error {
+	if ds.slotsKept == math.MaxUint64 {
+		return nil
+	}
+	currentSlot := ds.ethClock.GetCurrentSlot()
+	if currentSlot < ds.slotsKept {
+		return nil
+	}
+	currentSlot -= ds.slotsKept
+	currentSlot = (currentSlot / subdivisionSlot) * subdivisionSlot
+	var startPrune uint64
+	if currentSlot >= 1_000_000 {
+		startPrune = currentSlot - 1_000_000
+	}
+	for i := startPrune; i < currentSlot; i += subdivisionSlot {
+		ds.fs.RemoveAll(strconv.FormatUint(i/subdivisionSlot, 10))
+	}
+	return nil
+}
+
+// VerifyAgainstIdentifiersAndInsertIntoTheDataColumnStore verifies the sidecars received from a peer against the requested
+// identifiers and stores the valid ones. It returns the number of sidecars inserted, unrequested or invalid sidecars fail the whole batch.
+func VerifyAgainstIdentifiersAndInsertIntoTheDataColumnStore(ctx context.Context, storage DataColumnStorage, beaconChainConfig *clparams.BeaconChainConfig, identifiers *solid.ListSSZ[*cltypes.DataColumnIdentifier], sidecars []*cltypes.DataColumnSidecar) (uint64, error) {
+	if identifiers.Len() == 0 || len(sidecars) == 0 {
+		return 0, nil
+	}
+	requested := make(map[cltypes.DataColumnIdentifier]struct{}, identifiers.Len())
+	identifiers.Range(func(_ int, id *cltypes.DataColumnIdentifier, _ int) bool {
+		requested[*id] = struct{}{}
+		return true
+	})
+
+	byBlockRoot := make(map[libcommon.Hash][]*cltypes.DataColumnSidecar)
+	for _, sidecar := range sidecars {
+		blockRoot, err := sidecar.SignedBlockHeader.Header.HashSSZ()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := requested[cltypes.DataColumnIdentifier{BlockRoot: blockRoot, Index: sidecar.Index}]; !ok {
+			return 0, errors.New("received a data column sidecar that was not requested")
+		}
+		if err := das.VerifyDataColumnSidecar(beaconChainConfig, sidecar); err != nil {
+			return 0, err
+		}
+		if err := das.VerifyDataColumnSidecarInclusionProof(sidecar); err != nil {
+			return 0, err
+		}
+		if err := das.VerifyDataColumnSidecarKzgProofs(sidecar); err != nil {
+			return 0, err
+		}
+		byBlockRoot[blockRoot] = append(byBlockRoot[blockRoot], sidecar)
+	}
+
+	var inserted uint64
+	for blockRoot, columns := range byBlockRoot {
+		if err := storage.WriteColumnSidecars(ctx, blockRoot, columns); err != nil {
+			return inserted, err
+		}
+		inserted += uint64(len(columns))
+	}
+	return inserted, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package blob_storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestColumn(index uint64, slot uint64) *cltypes.DataColumnSidecar {
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = index
+	sidecar.SignedBlockHeader.Header.Slot = slot
+	sidecar.Column.Append(&cltypes.Cell{byte(index)})
+	sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{2})
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{3})
+	return sidecar
+}
+
+func TestDataColumnDB(t *testing.T) {
+	ctx := context.Background()
+	ds := NewDataColumnStore(afero.NewMemMapFs(), 12, &clparams.MainnetBeaconConfig, nil)
+	blockRoot := libcommon.Hash{1}
+
+	s1, s2 := newTestColumn(7, 1), newTestColumn(3, 1)
+	require.NoError(t, ds.WriteColumnSidecars(ctx, blockRoot, []*cltypes.DataColumnSidecar{s1, s2}))
+	// writing twice is a no-op.
+	require.NoError(t, ds.WriteColumnSidecars(ctx, blockRoot, []*cltypes.DataColumnSidecar{s1}))
+
+	indices, err := ds.ColumnSidecarIndices(ctx, 1, blockRoot)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 7}, indices)
+
+	read, found, err := ds.ReadColumnSidecar(ctx, 1, blockRoot, 7)
+	require.NoError(t, err)
+	require.True(t, found)
+	expectedRoot, err := s1.HashSSZ()
+	require.NoError(t, err)
+	readRoot, err := read.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, readRoot)
+
+	_, found, err = ds.ReadColumnSidecar(ctx, 1, blockRoot, 8)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	var buf bytes.Buffer
+	require.NoError(t, ds.WriteStream(&buf, 1, blockRoot, 3))
+	require.NotZero(t, buf.Len())
+
+	require.NoError(t, ds.RemoveColumnSidecars(ctx, 1, blockRoot))
+	indices, err = ds.ColumnSidecarIndices(ctx, 1, blockRoot)
+	require.NoError(t, err)
+	require.Empty(t, indices)
+}
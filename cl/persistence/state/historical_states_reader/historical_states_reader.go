@@ -27,13 +27,16 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types/ssz"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/base_encoding"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	state_accessors "github.com/erigontech/erigon/cl/persistence/state"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/phase1/core/state/lru"
+	"github.com/erigontech/erigon/cl/transition"
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
 	"github.com/klauspost/compress/zstd"
 )
@@ -274,6 +277,100 @@ func (r *HistoricalStatesReader) ReadHistoricalState(ctx context.Context, tx kv.
 		return nil, fmt.Errorf("failed to read historical summaries: %w", err)
 	}
 	ret.SetHistoricalSummaries(historicalSummaries)
+	if ret.Version() < clparams.ElectraVersion {
+		return ret, nil
+	}
+
+	// Electra balance churn and pending queues
+	ret.SetDepositRequestsStartIndex(slotData.DepositRequestsStartIndex)
+	ret.SetDepositBalanceToConsume(slotData.DepositBalanceToConsume)
+	ret.SetExitBalanceToConsume(slotData.ExitBalanceToConsume)
+	ret.SetEarliestExitEpoch(slotData.EarliestExitEpoch)
+	ret.SetConsolidationBalanceToConsume(slotData.ConsolidationBalanceToConsume)
+	ret.SetEarliestConsolidationEpoch(slotData.EarliestConsolidationEpoch)
+	pendingDeposits := solid.NewStaticListSSZ[*cltypes.PendingDeposit](int(r.cfg.PendingDepositsLimit), 192)
+	if err := r.readPendingQueue(tx, kv.PendingDeposits, slot, pendingDeposits); err != nil {
+		return nil, fmt.Errorf("failed to read pending deposits: %w", err)
+	}
+	ret.SetPendingDeposits(pendingDeposits)
+	pendingPartialWithdrawals := solid.NewStaticListSSZ[*cltypes.PendingPartialWithdrawal](int(r.cfg.PendingPartialWithdrawalsLimit), 24)
+	if err := r.readPendingQueue(tx, kv.PendingPartialWithdrawals, slot, pendingPartialWithdrawals); err != nil {
+		return nil, fmt.Errorf("failed to read pending partial withdrawals: %w", err)
+	}
+	ret.SetPendingPartialWithdrawals(pendingPartialWithdrawals)
+	pendingConsolidations := solid.NewStaticListSSZ[*cltypes.PendingConsolidation](int(r.cfg.PendingConsolidationsLimit), 16)
+	if err := r.readPendingQueue(tx, kv.PendingConsolidations, slot, pendingConsolidations); err != nil {
+		return nil, fmt.Errorf("failed to read pending consolidations: %w", err)
+	}
+	ret.SetPendingConsolidations(pendingConsolidations)
+	return ret, nil
+}
+
+// readPendingQueue decodes the queue dumped last at or before slot, queues are dumped only when they change.
+func (r *HistoricalStatesReader) readPendingQueue(tx kv.Tx, table string, slot uint64, out ssz.Unmarshaler) error {
+	cursor, err := tx.Cursor(table)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	k, v, err := cursor.Seek(base_encoding.Encode64ToBytes4(slot))
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		k, v, err = cursor.Last()
+	} else if base_encoding.Decode64FromBytes4(k) > slot {
+		k, v, err = cursor.Prev()
+	}
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return fmt.Errorf("dump not found for slot %d", slot)
+	}
+	zstdReader, err := zstd.NewReader(bytes.NewReader(v))
+	if err != nil {
+		return err
+	}
+	defer zstdReader.Close()
+	raw, err := io.ReadAll(zstdReader)
+	if err != nil {
+		return err
+	}
+	return out.DecodeSSZ(raw, int(clparams.ElectraVersion))
+}
+
+// ReadHistoricalStateAtSlot is like ReadHistoricalState, but slot may also be a slot without a block: the state of
+// the latest canonical block before slot is read and advanced through the empty slots.
+func (r *HistoricalStatesReader) ReadHistoricalStateAtSlot(ctx context.Context, tx kv.Tx, slot uint64) (*state.CachingBeaconState, error) {
+	latestProcessedState, err := state_accessors.GetStateProcessingProgress(tx)
+	if err != nil {
+		return nil, err
+	}
+	if slot > latestProcessedState {
+		return nil, nil
+	}
+	blockSlot := slot
+	for ; blockSlot > r.genesisState.Slot(); blockSlot-- {
+		blockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, blockSlot)
+		if err != nil {
+			return nil, err
+		}
+		if blockRoot != (common.Hash{}) {
+			break
+		}
+	}
+	ret, err := r.ReadHistoricalState(ctx, tx, blockSlot)
+	if err != nil || ret == nil || blockSlot == slot {
+		return ret, err
+	}
+	if err := ret.InitBeaconState(); err != nil {
+		return nil, err
+	}
+	if err := transition.DefaultMachine.ProcessSlots(ret, slot); err != nil {
+		return nil, fmt.Errorf("failed to process empty slots from %d to %d: %w", blockSlot, slot, err)
+	}
 	return ret, nil
 }
 
@@ -0,0 +1,79 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package historical_states_reader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/base_encoding"
+)
+
+func TestReadPendingQueue(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	dump := func(slot uint64, consolidations ...*cltypes.PendingConsolidation) {
+		l := solid.NewStaticListSSZ[*cltypes.PendingConsolidation](1<<18, 16)
+		for _, c := range consolidations {
+			l.Append(c)
+		}
+		raw, err := l.EncodeSSZ(nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Put(kv.PendingConsolidations, base_encoding.Encode64ToBytes4(slot), encoder.EncodeAll(raw, nil)))
+	}
+	first := &cltypes.PendingConsolidation{SourceIndex: 1, TargetIndex: 2}
+	second := &cltypes.PendingConsolidation{SourceIndex: 3, TargetIndex: 4}
+	dump(10, first)
+	dump(20, first, second)
+	dump(30)
+
+	r := &HistoricalStatesReader{}
+	read := func(slot uint64) (*solid.ListSSZ[*cltypes.PendingConsolidation], error) {
+		l := solid.NewStaticListSSZ[*cltypes.PendingConsolidation](1<<18, 16)
+		return l, r.readPendingQueue(tx, kv.PendingConsolidations, slot, l)
+	}
+
+	_, err = read(9)
+	require.Error(t, err)
+	for slot, expected := range map[uint64][]*cltypes.PendingConsolidation{
+		10:  {first},
+		15:  {first},
+		20:  {first, second},
+		29:  {first, second},
+		30:  {},
+		100: {},
+	} {
+		l, err := read(slot)
+		require.NoError(t, err)
+		require.Equal(t, len(expected), l.Len(), "slot %d", slot)
+		for i := range expected {
+			require.Equal(t, expected[i], l.Get(i), "slot %d", slot)
+		}
+	}
+}
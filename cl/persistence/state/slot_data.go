@@ -40,6 +40,13 @@ type SlotData struct {
 	// Capella
 	NextWithdrawalIndex          uint64
 	NextWithdrawalValidatorIndex uint64
+	// Electra
+	DepositRequestsStartIndex     uint64
+	DepositBalanceToConsume       uint64
+	ExitBalanceToConsume          uint64
+	EarliestExitEpoch             uint64
+	ConsolidationBalanceToConsume uint64
+	EarliestConsolidationEpoch    uint64
 
 	// BlockRewards for proposer
 	AttestationsRewards  uint64
@@ -52,7 +59,7 @@ func SlotDataFromBeaconState(s *state.CachingBeaconState) *SlotData {
 	justificationCopy := &cltypes.JustificationBits{}
 	jj := s.JustificationBits()
 	copy(justificationCopy[:], jj[:])
-	return &SlotData{
+	slotData := &SlotData{
 		ValidatorLength: uint64(s.ValidatorLength()),
 		Eth1DataLength:  uint64(s.Eth1DataVotes().Len()),
 
@@ -63,6 +70,15 @@ func SlotDataFromBeaconState(s *state.CachingBeaconState) *SlotData {
 		NextWithdrawalValidatorIndex: s.NextWithdrawalValidatorIndex(),
 		Fork:                         s.Fork(),
 	}
+	if s.Version() >= clparams.ElectraVersion {
+		slotData.DepositRequestsStartIndex = s.DepositRequestsStartIndex()
+		slotData.DepositBalanceToConsume = s.DepositBalanceToConsume()
+		slotData.ExitBalanceToConsume = s.ExitBalanceToConsume()
+		slotData.EarliestExitEpoch = s.EarliestExitEpoch()
+		slotData.ConsolidationBalanceToConsume = s.ConsolidationBalanceToConsume()
+		slotData.EarliestConsolidationEpoch = s.EarliestConsolidationEpoch()
+	}
+	return slotData
 }
 
 // Serialize serializes the state into a byte slice with zstd compression.
@@ -122,5 +138,9 @@ func (m *SlotData) getSchema() []interface{} {
 	if m.Version >= clparams.CapellaVersion {
 		schema = append(schema, &m.NextWithdrawalIndex, &m.NextWithdrawalValidatorIndex)
 	}
+	if m.Version >= clparams.ElectraVersion {
+		schema = append(schema, &m.DepositRequestsStartIndex, &m.DepositBalanceToConsume, &m.ExitBalanceToConsume,
+			&m.EarliestExitEpoch, &m.ConsolidationBalanceToConsume, &m.EarliestConsolidationEpoch)
+	}
 	return schema
 }
@@ -45,3 +45,23 @@ func TestSlotData(t *testing.T) {
 
 	require.Equal(t, m, m2)
 }
+
+func TestSlotDataElectra(t *testing.T) {
+	m := &SlotData{
+		Version:                       clparams.ElectraVersion,
+		Eth1Data:                      &cltypes.Eth1Data{},
+		Fork:                          &cltypes.Fork{Epoch: 12},
+		NextWithdrawalIndex:           3,
+		DepositRequestsStartIndex:     4,
+		DepositBalanceToConsume:       5,
+		ExitBalanceToConsume:          6,
+		EarliestExitEpoch:             7,
+		ConsolidationBalanceToConsume: 8,
+		EarliestConsolidationEpoch:    9,
+	}
+	var b bytes.Buffer
+	require.NoError(t, m.WriteTo(&b))
+	m2 := &SlotData{}
+	require.NoError(t, m2.ReadFrom(&b))
+	require.Equal(t, m, m2)
+}
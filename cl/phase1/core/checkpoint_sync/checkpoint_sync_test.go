@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon/cl/antiquary/tests"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/genesisdb"
 	"github.com/erigontech/erigon/cl/utils"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -87,3 +92,97 @@ func TestLocalCheckpointSyncFromGenesis(t *testing.T) {
 
 	assert.Equal(t, haveRoot, wantRoot)
 }
+
+func TestFileCheckpointSync(t *testing.T) {
+	blocks, _, st := tests.GetPhase0Random()
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state.ssz")
+	enc, err := st.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(stateFile, enc, 0644))
+	writeBlock := func(block *cltypes.SignedBeaconBlock) string {
+		enc, err := block.EncodeSSZ(nil)
+		require.NoError(t, err)
+		blockFile := filepath.Join(dir, fmt.Sprintf("block_%d.ssz", block.Block.Slot))
+		require.NoError(t, os.WriteFile(blockFile, enc, 0644))
+		return blockFile
+	}
+
+	// state only
+	state, err := NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, stateFile, "").GetLatestBeaconState(context.Background())
+	require.NoError(t, err)
+	haveRoot, err := st.HashSSZ()
+	require.NoError(t, err)
+	wantRoot, err := state.HashSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, haveRoot, wantRoot)
+
+	// state and its latest block
+	_, err = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, stateFile, writeBlock(blocks[1])).GetLatestBeaconState(context.Background())
+	require.NoError(t, err)
+
+	// state and an older block
+	_, err = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, stateFile, writeBlock(blocks[0])).GetLatestBeaconState(context.Background())
+	require.ErrorIs(t, err, ErrAnchorBlockMismatch)
+}
+
+func TestParseWeakSubjectivityCheckpoint(t *testing.T) {
+	checkpoint, err := ParseWeakSubjectivityCheckpoint("0x86979f6f6dc7626064ef0d38d4dffb89e91d1d4c18492e3fb7d7ee93cedca3ed:260")
+	require.NoError(t, err)
+	require.Equal(t, uint64(260), checkpoint.Epoch)
+	require.Equal(t, "0x86979f6f6dc7626064ef0d38d4dffb89e91d1d4c18492e3fb7d7ee93cedca3ed", checkpoint.Root.Hex())
+
+	for _, invalid := range []string{"", "0x86979f6f:260", "0x86979f6f6dc7626064ef0d38d4dffb89e91d1d4c18492e3fb7d7ee93cedca3ed", "0x86979f6f6dc7626064ef0d38d4dffb89e91d1d4c18492e3fb7d7ee93cedca3ed:abc"} {
+		_, err := ParseWeakSubjectivityCheckpoint(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestVerifyWeakSubjectivityCheckpoint(t *testing.T) {
+	blocks, _, st := tests.GetPhase0Random() // blocks at slots 8288 and 8322, state at slot 8322
+	root0, err := blocks[0].Block.HashSSZ()
+	require.NoError(t, err)
+	root1, err := blocks[1].Block.HashSSZ()
+	require.NoError(t, err)
+
+	// the latest block at or before the first slot of epoch 259 and 260 is the first block
+	require.NoError(t, VerifyWeakSubjectivityCheckpoint(st, solid.Checkpoint{Epoch: 259, Root: root0}))
+	require.NoError(t, VerifyWeakSubjectivityCheckpoint(st, solid.Checkpoint{Epoch: 260, Root: root0}))
+	require.ErrorIs(t, VerifyWeakSubjectivityCheckpoint(st, solid.Checkpoint{Epoch: 260, Root: root1}), ErrWeakSubjectivityCheckpointMismatch)
+	require.ErrorIs(t, VerifyWeakSubjectivityCheckpoint(st, solid.Checkpoint{Epoch: 261, Root: root1}), ErrStateBeforeWeakSubjectivityCheckpoint)
+
+	// the second block is the first one past epoch 260, its parent must be the checkpoint
+	require.NoError(t, VerifyBlockAgainstWeakSubjectivityCheckpoint(&clparams.MainnetBeaconConfig, solid.Checkpoint{Epoch: 260, Root: root0}, blocks[1].Block, root1, blocks[0].Block.Slot))
+	require.ErrorIs(t, VerifyBlockAgainstWeakSubjectivityCheckpoint(&clparams.MainnetBeaconConfig, solid.Checkpoint{Epoch: 260, Root: root1}, blocks[1].Block, root1, blocks[0].Block.Slot), ErrWeakSubjectivityCheckpointMismatch)
+	// blocks which do not cross the checkpoint epoch boundary are not concerned
+	require.NoError(t, VerifyBlockAgainstWeakSubjectivityCheckpoint(&clparams.MainnetBeaconConfig, solid.Checkpoint{Epoch: 259, Root: root1}, blocks[1].Block, root1, blocks[0].Block.Slot))
+}
+
+func TestReadOrFetchLatestBeaconStateFallback(t *testing.T) {
+	_, st, _ := tests.GetPhase0Random()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+	checkpointsURLs := clparams.ConfigurableCheckpointsURLs
+	t.Cleanup(func() { clparams.ConfigurableCheckpointsURLs = checkpointsURLs })
+	clparams.ConfigurableCheckpointsURLs = []string{mockServer.URL}
+
+	dirs := datadir.New(t.TempDir())
+	genesisDB := genesisdb.NewGenesisDB(&clparams.MainnetBeaconConfig, dirs.CaplinGenesis)
+	require.NoError(t, genesisDB.Initialize(st))
+	caplinConfig := clparams.CaplinConfig{NetworkId: clparams.MainnetNetwork}
+
+	// remote checkpoint sync failure is fatal by default
+	_, err := ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, nil)
+	require.Error(t, err)
+
+	caplinConfig.CheckpointSyncFallback = true
+	state, err := ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, nil)
+	require.NoError(t, err)
+	haveRoot, err := st.HashSSZ()
+	require.NoError(t, err)
+	wantRoot, err := state.HashSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, haveRoot, wantRoot)
+}
@@ -0,0 +1,82 @@
+package checkpoint_sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// FileCheckpointSyncer loads the checkpoint state from an SSZ file, e.g. downloaded from /eth/v2/debug/beacon/states/finalized.
+// If a block file is given, the SSZ encoded signed block must be the latest block applied to the state.
+type FileCheckpointSyncer struct {
+	beaconConfig *clparams.BeaconChainConfig
+	stateFile    string
+	blockFile    string
+}
+
+func NewFileCheckpointSyncer(beaconConfig *clparams.BeaconChainConfig, stateFile, blockFile string) CheckpointSyncer {
+	return &FileCheckpointSyncer{
+		beaconConfig: beaconConfig,
+		stateFile:    stateFile,
+		blockFile:    blockFile,
+	}
+}
+
+func (f *FileCheckpointSyncer) GetLatestBeaconState(ctx context.Context) (*state.CachingBeaconState, error) {
+	log.Info("[Checkpoint Sync] Reading beacon state", "file", f.stateFile)
+	encoded, err := os.ReadFile(f.stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read checkpoint state: %w", err)
+	}
+	slot, err := utils.ExtractSlotFromSerializedBeaconState(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not deserialize checkpoint state slot: %w", err)
+	}
+	bs := state.New(f.beaconConfig)
+	if err := bs.DecodeSSZ(encoded, int(f.beaconConfig.GetCurrentStateVersion(slot/f.beaconConfig.SlotsPerEpoch))); err != nil {
+		return nil, fmt.Errorf("could not deserialize checkpoint state: %w", err)
+	}
+	if f.blockFile == "" {
+		return bs, nil
+	}
+
+	encodedBlock, err := os.ReadFile(f.blockFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read anchor block: %w", err)
+	}
+	blockSlot, err := utils.ExtractSlotFromSerializedSignedBeaconBlock(encodedBlock)
+	if err != nil {
+		return nil, fmt.Errorf("could not deserialize anchor block slot: %w", err)
+	}
+	version := f.beaconConfig.GetCurrentStateVersion(blockSlot / f.beaconConfig.SlotsPerEpoch)
+	block := cltypes.NewSignedBeaconBlock(f.beaconConfig, version)
+	if err := block.DecodeSSZ(encodedBlock, int(version)); err != nil {
+		return nil, fmt.Errorf("could not deserialize anchor block: %w", err)
+	}
+	if err := checkAnchorBlock(bs, block.Block); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// checkAnchorBlock checks that block is the latest block applied to the state.
+func checkAnchorBlock(bs *state.CachingBeaconState, block *cltypes.BeaconBlock) error {
+	blockRoot, err := block.HashSSZ()
+	if err != nil {
+		return err
+	}
+	stateBlockRoot, err := latestBlockRoot(bs)
+	if err != nil {
+		return err
+	}
+	if blockRoot != stateBlockRoot {
+		return fmt.Errorf("%w: block %x at slot %d, state latest block %x at slot %d", ErrAnchorBlockMismatch, blockRoot, block.Slot, stateBlockRoot, bs.LatestBlockHeader().Slot)
+	}
+	return nil
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/utils"
 )
@@ -84,3 +86,53 @@ func (r *RemoteCheckpointSync) GetLatestBeaconState(ctx context.Context) (*state
 	return nil, err
 
 }
+
+// GetDepositSnapshot fetches the finalized deposit tree snapshot (EIP-4881) from the checkpoint sync endpoints.
+func (r *RemoteCheckpointSync) GetDepositSnapshot(ctx context.Context) (*cltypes.DepositTreeSnapshot, error) {
+	uris := clparams.GetAllCheckpointSyncEndpoints(r.net)
+	if len(uris) == 0 {
+		return nil, errors.New("no uris for checkpoint sync")
+	}
+
+	fetchDepositSnapshot := func(uri string) (*cltypes.DepositTreeSnapshot, error) {
+		// checkpoint sync endpoints point to the state, the snapshot lives under the same beacon API
+		if idx := strings.Index(uri, "/eth/"); idx >= 0 {
+			uri = uri[:idx]
+		}
+		uri += "/eth/v1/beacon/deposit_snapshot"
+		log.Info("[Checkpoint Sync] Requesting deposit snapshot", "uri", uri)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/octet-stream")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("deposit snapshot request failed, bad status code %d", resp.StatusCode)
+		}
+		marshaled, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("deposit snapshot read failed %s", err)
+		}
+		snapshot := cltypes.NewDepositTreeSnapshot()
+		if err := snapshot.DecodeSSZ(marshaled, 0); err != nil {
+			return nil, fmt.Errorf("deposit snapshot decode failed %s", err)
+		}
+		return snapshot, nil
+	}
+
+	var err error
+	var snapshot *cltypes.DepositTreeSnapshot
+	for _, uri := range uris {
+		snapshot, err = fetchDepositSnapshot(uri)
+		if err == nil {
+			return snapshot, nil
+		}
+		log.Warn("[Checkpoint Sync] Failed to fetch deposit snapshot", "uri", uri, "err", err)
+	}
+	return nil, err
+}
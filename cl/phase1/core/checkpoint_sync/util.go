@@ -2,30 +2,122 @@ package checkpoint_sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/genesisdb"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/spf13/afero"
 )
 
 // ReadOrFetchLatestBeaconState reads the latest beacon state from disk or fetches it from the network.
-func ReadOrFetchLatestBeaconState(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, genesisDB genesisdb.GenesisDB) (*state.CachingBeaconState, error) {
-	var syncer CheckpointSyncer
-	remoteSync := !caplinConfig.DisabledCheckpointSync && !caplinConfig.IsDevnet()
+// The state is read from the checkpoint state file if configured. If no checkpoint sync endpoint is reachable, the node
+// fails unless caplinConfig.CheckpointSyncFallback is set: then it starts from the latest local state (or genesis).
+// If wsCheckpoint is not nil the state must agree with it, states older than the checkpoint are verified while syncing.
+func ReadOrFetchLatestBeaconState(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, genesisDB genesisdb.GenesisDB, wsCheckpoint *solid.Checkpoint) (*state.CachingBeaconState, error) {
+	bs, err := readOrFetchLatestBeaconState(ctx, dirs, beaconCfg, caplinConfig, genesisDB)
+	if err != nil {
+		return nil, err
+	}
+	if wsCheckpoint == nil {
+		return bs, nil
+	}
+	err = VerifyWeakSubjectivityCheckpoint(bs, *wsCheckpoint)
+	switch {
+	case errors.Is(err, ErrStateBeforeWeakSubjectivityCheckpoint):
+		log.Info("[Checkpoint Sync] Weak subjectivity checkpoint will be verified while syncing", "stateSlot", bs.Slot(), "checkpointEpoch", wsCheckpoint.Epoch)
+	case err != nil:
+		return nil, err
+	default:
+		log.Info("[Checkpoint Sync] Weak subjectivity checkpoint verified", "root", wsCheckpoint.Root, "epoch", wsCheckpoint.Epoch)
+	}
+	return bs, nil
+}
+
+func readOrFetchLatestBeaconState(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, genesisDB genesisdb.GenesisDB) (*state.CachingBeaconState, error) {
+	if caplinConfig.CheckpointSyncStateFile != "" {
+		return NewFileCheckpointSyncer(beaconCfg, caplinConfig.CheckpointSyncStateFile, caplinConfig.CheckpointSyncBlockFile).GetLatestBeaconState(ctx)
+	}
 
+	remoteSync := !caplinConfig.DisabledCheckpointSync && !caplinConfig.IsDevnet()
 	if remoteSync {
-		syncer = NewRemoteCheckpointSync(beaconCfg, caplinConfig.NetworkId)
-	} else {
-		aferoFs := afero.NewOsFs()
+		bs, err := NewRemoteCheckpointSync(beaconCfg, caplinConfig.NetworkId).GetLatestBeaconState(ctx)
+		if err == nil {
+			return bs, nil
+		}
+		if !caplinConfig.CheckpointSyncFallback {
+			return nil, err
+		}
+		log.Warn("[Checkpoint Sync] No checkpoint sync endpoint available, starting from the latest local state or genesis", "err", err)
+	}
+
+	aferoFs := afero.NewOsFs()
+	genesisState, err := genesisDB.ReadGenesisState()
+	if err != nil {
+		return nil, fmt.Errorf("could not read genesis state: %w", err)
+	}
+	return NewLocalCheckpointSyncer(genesisState, afero.NewBasePathFs(aferoFs, dirs.CaplinLatest)).GetLatestBeaconState(ctx)
+}
 
-		genesisState, err := genesisDB.ReadGenesisState()
-		if err != nil {
-			return nil, fmt.Errorf("could not read genesis state: %w", err)
+// ReadOrFetchDepositTree restores the deposit tree (EIP-4881) matching the anchor state, either from disk or from the
+// deposit snapshot of the checkpoint sync endpoint. It returns nil if no tree consistent with the anchor state could be found.
+func ReadOrFetchDepositTree(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, anchorState *state.CachingBeaconState) *deposit_tree.DepositTree {
+	depositIndex := anchorState.Eth1DepositIndex()
+	encoded, err := os.ReadFile(filepath.Join(dirs.CaplinLatest, clparams.DepositTreeFileName))
+	if err == nil {
+		tree := deposit_tree.NewDepositTree()
+		if err := tree.UnmarshalBinary(encoded); err != nil {
+			log.Warn("[Checkpoint Sync] Could not decode the local deposit tree", "err", err)
+		} else if err := checkDepositTree(tree, anchorState); err != nil {
+			log.Warn("[Checkpoint Sync] Local deposit tree does not match the anchor state", "err", err)
+		} else {
+			return tree
 		}
-		syncer = NewLocalCheckpointSyncer(genesisState, afero.NewBasePathFs(aferoFs, dirs.CaplinLatest))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Warn("[Checkpoint Sync] Could not read the local deposit tree", "err", err)
+	}
+
+	if depositIndex == 0 {
+		return deposit_tree.NewDepositTree()
+	}
+	if caplinConfig.DisabledCheckpointSync || caplinConfig.IsDevnet() {
+		log.Warn("[Checkpoint Sync] No deposit tree available for the anchor state, deposit snapshot will not be served")
+		return nil
+	}
+	remoteSync := &RemoteCheckpointSync{beaconConfig: beaconCfg, net: caplinConfig.NetworkId}
+	snapshot, err := remoteSync.GetDepositSnapshot(ctx)
+	if err != nil {
+		log.Warn("[Checkpoint Sync] Could not fetch deposit snapshot, deposit snapshot will not be served", "err", err)
+		return nil
+	}
+	tree, err := deposit_tree.NewDepositTreeFromSnapshot(snapshot)
+	if err != nil {
+		log.Warn("[Checkpoint Sync] Invalid deposit snapshot, deposit snapshot will not be served", "err", err)
+		return nil
+	}
+	if err := checkDepositTree(tree, anchorState); err != nil {
+		log.Warn("[Checkpoint Sync] Deposit snapshot does not match the anchor state, deposit snapshot will not be served", "err", err)
+		return nil
+	}
+	return tree
+}
+
+// checkDepositTree checks that the tree contains all the deposits processed by the anchor state.
+func checkDepositTree(tree *deposit_tree.DepositTree, anchorState *state.CachingBeaconState) error {
+	depositIndex := anchorState.Eth1DepositIndex()
+	if tree.DepositCount() < depositIndex {
+		return fmt.Errorf("tree has %d deposits, anchor state processed %d", tree.DepositCount(), depositIndex)
+	}
+	eth1Data := anchorState.Eth1Data()
+	if tree.DepositCount() == eth1Data.DepositCount && tree.Root() != eth1Data.Root {
+		return deposit_tree.ErrRootMismatch
 	}
-	return syncer.GetLatestBeaconState(ctx)
+	return nil
 }
@@ -0,0 +1,104 @@
+package checkpoint_sync
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+var (
+	// ErrWeakSubjectivityCheckpointMismatch is returned when a state or a block conflicts with the weak subjectivity checkpoint.
+	ErrWeakSubjectivityCheckpointMismatch = errors.New("weak subjectivity checkpoint mismatch")
+	// ErrStateBeforeWeakSubjectivityCheckpoint is returned when the state predates the checkpoint, the chain must then be
+	// checked while syncing up to it.
+	ErrStateBeforeWeakSubjectivityCheckpoint = errors.New("state is older than the weak subjectivity checkpoint")
+	// ErrAnchorBlockMismatch is returned when the anchor block given along a checkpoint state is not its latest block.
+	ErrAnchorBlockMismatch = errors.New("anchor block does not match the checkpoint state")
+)
+
+// ParseWeakSubjectivityCheckpoint parses a weak subjectivity checkpoint given as block_root:epoch.
+func ParseWeakSubjectivityCheckpoint(s string) (solid.Checkpoint, error) {
+	rootStr, epochStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return solid.Checkpoint{}, fmt.Errorf("invalid weak subjectivity checkpoint %q, expected block_root:epoch", s)
+	}
+	root, err := hexutil.Decode(rootStr)
+	if err != nil {
+		return solid.Checkpoint{}, fmt.Errorf("invalid weak subjectivity checkpoint root %q: %w", rootStr, err)
+	}
+	if len(root) != length.Hash {
+		return solid.Checkpoint{}, fmt.Errorf("invalid weak subjectivity checkpoint root %q: expected %d bytes", rootStr, length.Hash)
+	}
+	epoch, err := strconv.ParseUint(epochStr, 10, 64)
+	if err != nil {
+		return solid.Checkpoint{}, fmt.Errorf("invalid weak subjectivity checkpoint epoch %q: %w", epochStr, err)
+	}
+	return solid.Checkpoint{Epoch: epoch, Root: libcommon.BytesToHash(root)}, nil
+}
+
+// latestBlockRoot returns the root of the latest block applied to the state.
+func latestBlockRoot(bs *state.CachingBeaconState) (libcommon.Hash, error) {
+	header := bs.LatestBlockHeader()
+	if header.Root == (libcommon.Hash{}) {
+		// the state root is filled in the header only at the next slot processing.
+		stateRoot, err := bs.HashSSZ()
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		header.Root = stateRoot
+	}
+	return header.HashSSZ()
+}
+
+// VerifyWeakSubjectivityCheckpoint checks that the chain of the state goes through the checkpoint, i.e. that the latest
+// block at or before the first slot of the checkpoint epoch is the checkpoint root.
+// ErrStateBeforeWeakSubjectivityCheckpoint is returned if the state predates the checkpoint.
+func VerifyWeakSubjectivityCheckpoint(bs *state.CachingBeaconState, checkpoint solid.Checkpoint) error {
+	beaconCfg := bs.BeaconConfig()
+	checkpointSlot := checkpoint.Epoch * beaconCfg.SlotsPerEpoch
+	if bs.Slot() < checkpointSlot {
+		return ErrStateBeforeWeakSubjectivityCheckpoint
+	}
+	var (
+		root libcommon.Hash
+		err  error
+	)
+	if bs.LatestBlockHeader().Slot <= checkpointSlot {
+		root, err = latestBlockRoot(bs)
+	} else {
+		root, err = bs.GetBlockRootAtSlot(checkpointSlot)
+	}
+	if err != nil {
+		return fmt.Errorf("could not compute the block root at the checkpoint epoch: %w", err)
+	}
+	if root != checkpoint.Root {
+		return fmt.Errorf("%w: state has root %x at epoch %d, checkpoint has %x", ErrWeakSubjectivityCheckpointMismatch, root, checkpoint.Epoch, checkpoint.Root)
+	}
+	return nil
+}
+
+// VerifyBlockAgainstWeakSubjectivityCheckpoint checks a block whose parent is at parentSlot, the first block past the
+// start of the checkpoint epoch must either be the checkpoint block or one of its children.
+func VerifyBlockAgainstWeakSubjectivityCheckpoint(beaconCfg *clparams.BeaconChainConfig, checkpoint solid.Checkpoint, block *cltypes.BeaconBlock, blockRoot libcommon.Hash, parentSlot uint64) error {
+	checkpointSlot := checkpoint.Epoch * beaconCfg.SlotsPerEpoch
+	if block.Slot < checkpointSlot || parentSlot >= checkpointSlot {
+		return nil
+	}
+	root := block.ParentRoot
+	if block.Slot == checkpointSlot {
+		root = blockRoot
+	}
+	if root != checkpoint.Root {
+		return fmt.Errorf("%w: block %x at slot %d is not a descendant of %x", ErrWeakSubjectivityCheckpointMismatch, blockRoot, block.Slot, checkpoint.Root)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package deposit_tree keeps the deposit contract merkle tree in the compact format of EIP-4881.
+package deposit_tree
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+var (
+	ErrMissingDeposits = errors.New("deposit tree is missing deposits")
+	ErrRootMismatch    = errors.New("deposit tree root does not match eth1 data")
+)
+
+// DepositTree is the deposit contract tree: the finalized part is collapsed into the roots of its full subtrees
+// (the snapshot), while the deposits after it are kept as leaves, since they can still be reorged.
+type DepositTree struct {
+	mu       sync.RWMutex
+	snapshot *cltypes.DepositTreeSnapshot
+	leaves   []libcommon.Hash // deposits after snapshot.DepositCount
+}
+
+// NewDepositTree creates an empty deposit tree.
+func NewDepositTree() *DepositTree {
+	snapshot := cltypes.NewDepositTreeSnapshot()
+	snapshot.DepositRoot = computeRoot(branchFromSnapshot(snapshot), 0)
+	return &DepositTree{snapshot: snapshot}
+}
+
+// NewDepositTreeFromSnapshot creates a deposit tree from a finalized snapshot, after checking its consistency.
+func NewDepositTreeFromSnapshot(snapshot *cltypes.DepositTreeSnapshot) (*DepositTree, error) {
+	if snapshot.Finalized.Length() != bits.OnesCount64(snapshot.DepositCount) {
+		return nil, fmt.Errorf("snapshot has %d finalized roots, expected %d", snapshot.Finalized.Length(), bits.OnesCount64(snapshot.DepositCount))
+	}
+	if root := computeRoot(branchFromSnapshot(snapshot), snapshot.DepositCount); root != snapshot.DepositRoot {
+		return nil, fmt.Errorf("snapshot deposit root mismatch, computed %x, expected %x", root, snapshot.DepositRoot)
+	}
+	return &DepositTree{snapshot: copySnapshot(snapshot)}, nil
+}
+
+// DepositCount returns the number of deposits in the tree.
+func (t *DepositTree) DepositCount() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.snapshot.DepositCount + uint64(len(t.leaves))
+}
+
+// Root returns the deposit root, as computed by the deposit contract.
+func (t *DepositTree) Root() libcommon.Hash {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.rootAt(t.snapshot.DepositCount + uint64(len(t.leaves)))
+}
+
+// Snapshot returns a copy of the finalized snapshot.
+func (t *DepositTree) Snapshot() *cltypes.DepositTreeSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copySnapshot(t.snapshot)
+}
+
+// AddDeposits inserts the leaves of deposits starting at startIndex. Deposits already in the tree are replaced if
+// they differ (the block including them was on another fork), everything after them is discarded.
+func (t *DepositTree) AddDeposits(startIndex uint64, leaves []libcommon.Hash) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	finalizedCount := t.snapshot.DepositCount
+	for i, leaf := range leaves {
+		index := startIndex + uint64(i)
+		if index < finalizedCount {
+			continue
+		}
+		position := index - finalizedCount
+		switch {
+		case position > uint64(len(t.leaves)):
+			return fmt.Errorf("%w: got deposit %d, tree has %d", ErrMissingDeposits, index, finalizedCount+uint64(len(t.leaves)))
+		case position == uint64(len(t.leaves)):
+			t.leaves = append(t.leaves, leaf)
+		case t.leaves[position] != leaf:
+			t.leaves = append(t.leaves[:position], leaf)
+		}
+	}
+	return nil
+}
+
+// Finalize collapses the deposits covered by eth1Data into the snapshot. The eth1 data is the one of the finalized
+// beacon state and executionBlockHeight is the number of eth1Data.BlockHash.
+func (t *DepositTree) Finalize(eth1Data *cltypes.Eth1Data, executionBlockHeight uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	finalizedCount := t.snapshot.DepositCount
+	if eth1Data.DepositCount <= finalizedCount {
+		return nil
+	}
+	if eth1Data.DepositCount > finalizedCount+uint64(len(t.leaves)) {
+		return fmt.Errorf("%w: cannot finalize %d deposits, tree has %d", ErrMissingDeposits, eth1Data.DepositCount, finalizedCount+uint64(len(t.leaves)))
+	}
+	newLeaves := eth1Data.DepositCount - finalizedCount
+	branch := branchFromSnapshot(t.snapshot)
+	for i := uint64(0); i < newLeaves; i++ {
+		pushLeaf(&branch, finalizedCount+i, t.leaves[i])
+	}
+	root := computeRoot(branch, eth1Data.DepositCount)
+	if root != eth1Data.Root {
+		return fmt.Errorf("%w: computed %x, expected %x", ErrRootMismatch, root, eth1Data.Root)
+	}
+
+	snapshot := cltypes.NewDepositTreeSnapshot()
+	// The finalized subtrees are the ones matching the bits of the deposit count, the largest first.
+	for height := cltypes.DepositTreeDepth - 1; height >= 0; height-- {
+		if eth1Data.DepositCount&(1<<height) != 0 {
+			snapshot.Finalized.Append(branch[height])
+		}
+	}
+	snapshot.DepositRoot = root
+	snapshot.DepositCount = eth1Data.DepositCount
+	snapshot.ExecutionBlockHash = eth1Data.BlockHash
+	snapshot.ExecutionBlockHeight = executionBlockHeight
+	t.snapshot = snapshot
+	t.leaves = append([]libcommon.Hash{}, t.leaves[newLeaves:]...)
+	return nil
+}
+
+// MarshalBinary encodes the snapshot and the non-finalized leaves, so that the tree can be restored on restart.
+func (t *DepositTree) MarshalBinary() ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	encodedSnapshot, err := t.snapshot.EncodeSSZ(nil)
+	if err != nil {
+		return nil, err
+	}
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(encodedSnapshot)))
+	out = append(out, encodedSnapshot...)
+	for _, leaf := range t.leaves {
+		out = append(out, leaf[:]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary decodes a tree encoded by MarshalBinary.
+func (t *DepositTree) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("deposit tree encoding too short")
+	}
+	snapshotLength := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if len(data) < snapshotLength || (len(data)-snapshotLength)%length.Hash != 0 {
+		return errors.New("bad deposit tree encoding")
+	}
+	snapshot := cltypes.NewDepositTreeSnapshot()
+	if err := snapshot.DecodeSSZ(data[:snapshotLength], 0); err != nil {
+		return err
+	}
+	decoded, err := NewDepositTreeFromSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	leaves := make([]libcommon.Hash, 0, (len(data)-snapshotLength)/length.Hash)
+	for rest := data[snapshotLength:]; len(rest) > 0; rest = rest[length.Hash:] {
+		leaves = append(leaves, libcommon.BytesToHash(rest[:length.Hash]))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot = decoded.snapshot
+	t.leaves = leaves
+	return nil
+}
+
+func (t *DepositTree) rootAt(count uint64) libcommon.Hash {
+	branch := branchFromSnapshot(t.snapshot)
+	for i := t.snapshot.DepositCount; i < count; i++ {
+		pushLeaf(&branch, i, t.leaves[i-t.snapshot.DepositCount])
+	}
+	return computeRoot(branch, count)
+}
+
+// branchFromSnapshot rebuilds the deposit contract branch from the finalized subtree roots.
+func branchFromSnapshot(snapshot *cltypes.DepositTreeSnapshot) (branch [cltypes.DepositTreeDepth]libcommon.Hash) {
+	idx := 0
+	for height := cltypes.DepositTreeDepth - 1; height >= 0; height-- {
+		if snapshot.DepositCount&(1<<height) != 0 && idx < snapshot.Finalized.Length() {
+			branch[height] = snapshot.Finalized.Get(idx)
+			idx++
+		}
+	}
+	return
+}
+
+// pushLeaf inserts the leaf at the given index, this is the deposit contract algorithm.
+func pushLeaf(branch *[cltypes.DepositTreeDepth]libcommon.Hash, index uint64, leaf libcommon.Hash) {
+	node := leaf
+	size := index + 1
+	for height := 0; height < cltypes.DepositTreeDepth; height++ {
+		if size&1 == 1 {
+			branch[height] = node
+			return
+		}
+		node = utils.Sha256(branch[height][:], node[:])
+		size >>= 1
+	}
+}
+
+// computeRoot computes the deposit root of count deposits, mixing in the count like the deposit contract does.
+func computeRoot(branch [cltypes.DepositTreeDepth]libcommon.Hash, count uint64) libcommon.Hash {
+	var node libcommon.Hash
+	size := count
+	for height := 0; height < cltypes.DepositTreeDepth; height++ {
+		if size&1 == 1 {
+			node = utils.Sha256(branch[height][:], node[:])
+		} else {
+			node = utils.Sha256(node[:], merkle_tree.ZeroHashes[height][:])
+		}
+		size >>= 1
+	}
+	var countBytes [32]byte
+	binary.LittleEndian.PutUint64(countBytes[:], count)
+	return utils.Sha256(node[:], countBytes[:])
+}
+
+func copySnapshot(snapshot *cltypes.DepositTreeSnapshot) *cltypes.DepositTreeSnapshot {
+	cpy := cltypes.NewDepositTreeSnapshot()
+	for i := 0; i < snapshot.Finalized.Length(); i++ {
+		cpy.Finalized.Append(snapshot.Finalized.Get(i))
+	}
+	cpy.DepositRoot = snapshot.DepositRoot
+	cpy.DepositCount = snapshot.DepositCount
+	cpy.ExecutionBlockHash = snapshot.ExecutionBlockHash
+	cpy.ExecutionBlockHeight = snapshot.ExecutionBlockHeight
+	return cpy
+}
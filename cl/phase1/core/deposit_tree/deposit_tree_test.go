@@ -0,0 +1,127 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package deposit_tree
+
+import (
+	"encoding/binary"
+	"testing"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+func testLeaves(n int) []libcommon.Hash {
+	leaves := make([]libcommon.Hash, n)
+	for i := range leaves {
+		leaves[i] = utils.Sha256(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+	return leaves
+}
+
+// naiveRoot merkleizes all the leaves layer by layer.
+func naiveRoot(leaves []libcommon.Hash) libcommon.Hash {
+	layer := append([]libcommon.Hash{}, leaves...)
+	for height := 0; height < cltypes.DepositTreeDepth; height++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, merkle_tree.ZeroHashes[height])
+		}
+		next := make([]libcommon.Hash, 0, len(layer)/2)
+		for i := 0; i < len(layer); i += 2 {
+			next = append(next, utils.Sha256(layer[i][:], layer[i+1][:]))
+		}
+		if len(next) == 0 {
+			next = append(next, merkle_tree.ZeroHashes[height+1])
+		}
+		layer = next
+	}
+	var countBytes [32]byte
+	binary.LittleEndian.PutUint64(countBytes[:], uint64(len(leaves)))
+	return utils.Sha256(layer[0][:], countBytes[:])
+}
+
+func TestDepositTreeRoot(t *testing.T) {
+	tree := NewDepositTree()
+	require.Equal(t, naiveRoot(nil), tree.Root())
+	leaves := testLeaves(37)
+	for i := range leaves {
+		require.NoError(t, tree.AddDeposits(uint64(i), leaves[i:i+1]))
+		require.Equal(t, naiveRoot(leaves[:i+1]), tree.Root())
+	}
+	require.ErrorIs(t, tree.AddDeposits(40, leaves[:1]), ErrMissingDeposits)
+}
+
+func TestDepositTreeFinalize(t *testing.T) {
+	leaves := testLeaves(50)
+	tree := NewDepositTree()
+	require.NoError(t, tree.AddDeposits(0, leaves[:30]))
+
+	require.ErrorIs(t, tree.Finalize(&cltypes.Eth1Data{DepositCount: 31}, 100), ErrMissingDeposits)
+	require.ErrorIs(t, tree.Finalize(&cltypes.Eth1Data{DepositCount: 21}, 100), ErrRootMismatch)
+	eth1Data := &cltypes.Eth1Data{Root: naiveRoot(leaves[:21]), DepositCount: 21, BlockHash: libcommon.Hash{1}}
+	require.NoError(t, tree.Finalize(eth1Data, 100))
+
+	snapshot := tree.Snapshot()
+	require.Equal(t, uint64(21), snapshot.DepositCount)
+	require.Equal(t, 3, snapshot.Finalized.Length()) // 16 + 4 + 1
+	require.Equal(t, eth1Data.Root, snapshot.DepositRoot)
+	require.Equal(t, uint64(100), snapshot.ExecutionBlockHeight)
+	require.Equal(t, naiveRoot(leaves[:30]), tree.Root())
+
+	// Deposits keep being appended on top of the finalized part.
+	require.NoError(t, tree.AddDeposits(25, leaves[25:50]))
+	require.Equal(t, naiveRoot(leaves), tree.Root())
+
+	// A tree restored from the snapshot has the same finalized root.
+	restored, err := NewDepositTreeFromSnapshot(snapshot)
+	require.NoError(t, err)
+	require.Equal(t, eth1Data.Root, restored.Root())
+	require.NoError(t, restored.AddDeposits(21, leaves[21:]))
+	require.Equal(t, naiveRoot(leaves), restored.Root())
+
+	snapshot.DepositRoot = libcommon.Hash{}
+	_, err = NewDepositTreeFromSnapshot(snapshot)
+	require.Error(t, err)
+}
+
+func TestDepositTreeReorg(t *testing.T) {
+	leaves := testLeaves(10)
+	tree := NewDepositTree()
+	require.NoError(t, tree.AddDeposits(0, leaves))
+	forked := append(append([]libcommon.Hash{}, leaves[:6]...), libcommon.Hash{9})
+	require.NoError(t, tree.AddDeposits(6, forked[6:]))
+	require.Equal(t, uint64(7), tree.DepositCount())
+	require.Equal(t, naiveRoot(forked), tree.Root())
+}
+
+func TestDepositTreeMarshalBinary(t *testing.T) {
+	leaves := testLeaves(12)
+	tree := NewDepositTree()
+	require.NoError(t, tree.AddDeposits(0, leaves))
+	require.NoError(t, tree.Finalize(&cltypes.Eth1Data{Root: naiveRoot(leaves[:5]), DepositCount: 5}, 1))
+
+	encoded, err := tree.MarshalBinary()
+	require.NoError(t, err)
+	decoded := NewDepositTree()
+	require.NoError(t, decoded.UnmarshalBinary(encoded))
+	require.Equal(t, tree.DepositCount(), decoded.DepositCount())
+	require.Equal(t, tree.Root(), decoded.Root())
+	require.Equal(t, tree.Snapshot(), decoded.Snapshot())
+}
@@ -114,6 +114,25 @@ func IsUnslashedParticipatingIndex(validatorSet *solid.ValidatorSet, previousEpo
 	return validator.Active(epoch) && cltypes.ParticipationFlags(previousEpochParticipation.Get(int(index))).HasFlag(flagIdx) && !validator.Slashed()
 }
 
+// GetUnslashedIndiciesSet returns, for every participation flag, which validators are unslashed and participating
+// with that flag in previousEpoch.
+func GetUnslashedIndiciesSet(cfg *clparams.BeaconChainConfig, previousEpoch uint64, validatorSet *solid.ValidatorSet, previousEpochParticipation *solid.ParticipationBitList) [][]bool {
+	weights := cfg.ParticipationWeights()
+	flagsUnslashedIndiciesSet := make([][]bool, len(weights))
+	for i := range weights {
+		flagsUnslashedIndiciesSet[i] = make([]bool, validatorSet.Length())
+	}
+
+	threading.ParallellForLoop(runtime.NumCPU(), 0, validatorSet.Length(), func(validatorIndex int) error {
+		for i := range weights {
+			flagsUnslashedIndiciesSet[i][validatorIndex] = IsUnslashedParticipatingIndex(validatorSet, previousEpochParticipation, previousEpoch, uint64(validatorIndex), i)
+		}
+		return nil
+	})
+
+	return flagsUnslashedIndiciesSet
+}
+
 // EligibleValidatorsIndicies Implementation of get_eligible_validator_indices as defined in the eth 2.0 specs.
 func EligibleValidatorsIndicies(b abstract.BeaconState) (eligibleValidators []uint64) {
 	/* This is a parallel implementation of get_eligible_validator_indices*/
@@ -225,6 +244,10 @@ func GetUnslashedParticipatingIndices(b abstract.BeaconState, flagIndex int, epo
 // Implementation of is_eligible_for_activation_queue.
 // Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#is_eligible_for_activation_queue
 func IsValidatorEligibleForActivationQueue(b abstract.BeaconState, validator solid.Validator) bool {
+	if b.Version() >= clparams.ElectraVersion {
+		return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
+			validator.EffectiveBalance() >= b.BeaconConfig().MinActivationBalance
+	}
 	return validator.ActivationEligibilityEpoch() == b.BeaconConfig().FarFutureEpoch &&
 		validator.EffectiveBalance() == b.BeaconConfig().MaxEffectiveBalance
 }
@@ -247,27 +270,69 @@ func ComputeTimestampAtSlot(b abstract.BeaconState, slot uint64) uint64 {
 	return b.GenesisTime() + (slot-b.BeaconConfig().GenesisSlot)*b.BeaconConfig().SecondsPerSlot
 }
 
-// ExpectedWithdrawals calculates the expected withdrawals that can be made by validators in the current epoch
-func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes.Withdrawal {
+// ExpectedWithdrawals calculates the expected withdrawals that can be made by validators in the current epoch.
+// From Electra it also returns the number of processed pending partial withdrawals, which must be dequeued from state.
+func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) ([]*cltypes.Withdrawal, uint64) {
 	// Get the current epoch, the next withdrawal index, and the next withdrawal validator index
+	cfg := b.BeaconConfig()
 	nextWithdrawalIndex := b.NextWithdrawalIndex()
 	nextWithdrawalValidatorIndex := b.NextWithdrawalValidatorIndex()
 
 	// Determine the upper bound for the loop and initialize the withdrawals slice with a capacity of bound
 	maxValidators := uint64(b.ValidatorLength())
-	maxValidatorsPerWithdrawalsSweep := b.BeaconConfig().MaxValidatorsPerWithdrawalsSweep
+	maxValidatorsPerWithdrawalsSweep := cfg.MaxValidatorsPerWithdrawalsSweep
 	bound := min(maxValidators, maxValidatorsPerWithdrawalsSweep)
 	withdrawals := make([]*cltypes.Withdrawal, 0, bound)
+	// withdrawn sums amounts of withdrawals of validator in this payload
+	withdrawn := func(validatorIndex uint64) (amount uint64) {
+		for _, w := range withdrawals {
+			if w.Validator == validatorIndex {
+				amount += w.Amount
+			}
+		}
+		return amount
+	}
+
+	// Electra: consume pending partial withdrawals first
+	var partialWithdrawalsCount uint64
+	if b.Version() >= clparams.ElectraVersion {
+		b.PendingPartialWithdrawals().Range(func(_ int, w *cltypes.PendingPartialWithdrawal, _ int) bool {
+			if w.WithdrawableEpoch > currentEpoch || len(withdrawals) == int(cfg.MaxPendingPartialsPerWithdrawalsSweep) {
+				return false
+			}
+			validator, err := b.ValidatorForValidatorIndex(int(w.Index))
+			if err != nil {
+				return false
+			}
+			balance, _ := b.ValidatorBalance(int(w.Index))
+			balance -= withdrawn(w.Index)
+			if validator.ExitEpoch() == cfg.FarFutureEpoch && validator.EffectiveBalance() >= cfg.MinActivationBalance && balance > cfg.MinActivationBalance {
+				wd := validator.WithdrawalCredentials()
+				withdrawals = append(withdrawals, &cltypes.Withdrawal{
+					Index:     nextWithdrawalIndex,
+					Validator: w.Index,
+					Address:   libcommon.BytesToAddress(wd[12:]),
+					Amount:    min(balance-cfg.MinActivationBalance, w.Amount),
+				})
+				nextWithdrawalIndex++
+			}
+			partialWithdrawalsCount++
+			return true
+		})
+	}
 
 	// Loop through the validators to calculate expected withdrawals
-	for validatorCount := uint64(0); validatorCount < bound && len(withdrawals) != int(b.BeaconConfig().MaxWithdrawalsPerPayload); validatorCount++ {
+	for validatorCount := uint64(0); validatorCount < bound && len(withdrawals) != int(cfg.MaxWithdrawalsPerPayload); validatorCount++ {
 		// Get the validator and balance for the current validator index
 		// supposedly this operation is safe because we checked the validator length about
 		currentValidator, _ := b.ValidatorForValidatorIndex(int(nextWithdrawalValidatorIndex))
 		currentBalance, _ := b.ValidatorBalance(int(nextWithdrawalValidatorIndex))
+		if b.Version() >= clparams.ElectraVersion {
+			currentBalance -= withdrawn(nextWithdrawalValidatorIndex)
+		}
 		wd := currentValidator.WithdrawalCredentials()
 		// Check if the validator is fully withdrawable
-		if isFullyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance, currentEpoch) {
+		if isFullyWithdrawableValidator(b, currentValidator, currentBalance, currentEpoch) {
 			// Add a new withdrawal with the validator's withdrawal credentials and balance
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
@@ -277,13 +342,17 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
-		} else if isPartiallyWithdrawableValidator(b.BeaconConfig(), currentValidator, currentBalance) { // Check if the validator is partially withdrawable
+		} else if isPartiallyWithdrawableValidator(b, currentValidator, currentBalance) { // Check if the validator is partially withdrawable
 			// Add a new withdrawal with the validator's withdrawal credentials and balance minus the maximum effective balance
+			maxEffectiveBalance := cfg.MaxEffectiveBalance
+			if b.Version() >= clparams.ElectraVersion {
+				maxEffectiveBalance = GetMaxEffectiveBalance(cfg, currentValidator)
+			}
 			newWithdrawal := &cltypes.Withdrawal{
 				Index:     nextWithdrawalIndex,
 				Validator: nextWithdrawalValidatorIndex,
 				Address:   libcommon.BytesToAddress(wd[12:]),
-				Amount:    currentBalance - b.BeaconConfig().MaxEffectiveBalance,
+				Amount:    currentBalance - maxEffectiveBalance,
 			}
 			withdrawals = append(withdrawals, newWithdrawal)
 			nextWithdrawalIndex++
@@ -294,5 +363,57 @@ func ExpectedWithdrawals(b abstract.BeaconState, currentEpoch uint64) []*cltypes
 	}
 
 	// Return the withdrawals slice
-	return withdrawals
+	return withdrawals, partialWithdrawalsCount
+}
+
+// GetBalanceChurnLimit returns churn limit of current epoch in Gwei (Electra).
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_balance_churn_limit
+func GetBalanceChurnLimit(b abstract.BeaconState) uint64 {
+	cfg := b.BeaconConfig()
+	churn := max(cfg.MinPerEpochChurnLimitElectra, b.GetTotalActiveBalance()/cfg.ChurnLimitQuotient)
+	return churn - churn%cfg.EffectiveBalanceIncrement
+}
+
+// GetActivationExitChurnLimit returns churn limit of current epoch dedicated to activations and exits in Gwei (Electra).
+func GetActivationExitChurnLimit(b abstract.BeaconState) uint64 {
+	return min(b.BeaconConfig().MaxPerEpochActivationExitChurnLimit, GetBalanceChurnLimit(b))
+}
+
+// GetConsolidationChurnLimit returns churn limit of current epoch dedicated to consolidations in Gwei (Electra).
+func GetConsolidationChurnLimit(b abstract.BeaconState) uint64 {
+	return GetBalanceChurnLimit(b) - GetActivationExitChurnLimit(b)
+}
+
+// GetPendingBalanceToWithdraw returns sum of pending partial withdrawals of validator (Electra).
+func GetPendingBalanceToWithdraw(b abstract.BeaconState, validatorIndex uint64) (amount uint64) {
+	b.PendingPartialWithdrawals().Range(func(_ int, w *cltypes.PendingPartialWithdrawal, _ int) bool {
+		if w.Index == validatorIndex {
+			amount += w.Amount
+		}
+		return true
+	})
+	return amount
+}
+
+// IsValidDepositSignature checks the proof of possession of a deposit, which is not verified by the deposit contract.
+func IsValidDepositSignature(b abstract.BeaconState, pubkey libcommon.Bytes48, withdrawalCredentials libcommon.Hash, amount uint64, signature libcommon.Bytes96) (bool, error) {
+	// Agnostic domain.
+	domain, err := fork.ComputeDomain(
+		b.BeaconConfig().DomainDeposit[:],
+		utils.Uint32ToBytes4(uint32(b.BeaconConfig().GenesisForkVersion)),
+		[32]byte{},
+	)
+	if err != nil {
+		return false, err
+	}
+	depositMessageRoot, err := (&cltypes.DepositData{
+		PubKey:                pubkey,
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                amount,
+	}).MessageHash()
+	if err != nil {
+		return false, err
+	}
+	signedRoot := utils.Sha256(depositMessageRoot[:], domain)
+	return bls.Verify(signature[:], signedRoot[:], pubkey[:])
 }
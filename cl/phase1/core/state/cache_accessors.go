@@ -320,16 +320,29 @@ func (b *CachingBeaconState) ComputeNextSyncCommittee() (*solid.SyncCommittee, e
 			return nil, err
 		}
 		candidateIndex := activeValidatorIndicies[shuffledIndex]
-		// Compute random byte.
-		buf := make([]byte, 8)
-		binary.LittleEndian.PutUint64(buf, i/32)
-		input := append(seed[:], buf...)
-		randomByte := uint64(utils.Sha256(input)[i%32])
 		// retrieve validator.
 		validator, err := b.ValidatorForValidatorIndex(int(candidateIndex))
 		if err != nil {
 			return nil, err
 		}
+		buf := make([]byte, 8)
+		if b.Version() >= clparams.ElectraVersion {
+			// Compute 2 random bytes, the max effective balance is the compounding one.
+			binary.LittleEndian.PutUint64(buf, i/16)
+			input := append(seed[:], buf...)
+			offset := (i % 16) * 2
+			randomBytes := utils.Sha256(input)
+			randomValue := uint64(binary.LittleEndian.Uint16(randomBytes[offset : offset+2]))
+			if validator.EffectiveBalance()*math.MaxUint16 >= beaconConfig.MaxEffectiveBalanceElectra*randomValue {
+				syncCommitteePubKeys = append(syncCommitteePubKeys, validator.PublicKey())
+			}
+			i++
+			continue
+		}
+		// Compute random byte.
+		binary.LittleEndian.PutUint64(buf, i/32)
+		input := append(seed[:], buf...)
+		randomByte := uint64(utils.Sha256(input)[i%32])
 		if validator.EffectiveBalance()*math.MaxUint8 >= beaconConfig.MaxEffectiveBalance*randomByte {
 			syncCommitteePubKeys = append(syncCommitteePubKeys, validator.PublicKey())
 		}
@@ -77,7 +77,11 @@ func (b *CachingBeaconState) SlashValidator(slashedInd uint64, whistleblowerInd
 		whistleblowerInd = new(uint64)
 		*whistleblowerInd = proposerInd
 	}
-	whistleBlowerReward := newEffectiveBalance / b.BeaconConfig().WhistleBlowerRewardQuotient
+	whistleBlowerRewardQuotient := b.BeaconConfig().WhistleBlowerRewardQuotient
+	if b.Version() >= clparams.ElectraVersion {
+		whistleBlowerRewardQuotient = b.BeaconConfig().WhistleBlowerRewardQuotientElectra
+	}
+	whistleBlowerReward := newEffectiveBalance / whistleBlowerRewardQuotient
 	proposerReward := b.getSlashingProposerReward(whistleBlowerReward)
 	if err := IncreaseBalance(b, proposerInd, proposerReward); err != nil {
 		return 0, err
@@ -98,6 +102,15 @@ func (b *CachingBeaconState) InitiateValidatorExit(index uint64) error {
 		return nil
 	}
 
+	if b.Version() >= clparams.ElectraVersion {
+		// Electra: exits are limited by churn of balance instead of number of validators
+		effectiveBalance, err := b.ValidatorEffectiveBalance(int(index))
+		if err != nil {
+			return err
+		}
+		return b.setExitEpochs(index, ComputeExitEpochAndUpdateChurn(b, effectiveBalance))
+	}
+
 	currentEpoch := Epoch(b)
 	exitQueueEpoch := ComputeActivationExitEpoch(b.BeaconConfig(), currentEpoch)
 	b.ForEachValidator(func(v solid.Validator, idx, total int) bool {
@@ -117,13 +130,15 @@ func (b *CachingBeaconState) InitiateValidatorExit(index uint64) error {
 	if exitQueueChurn >= int(b.GetValidatorChurnLimit()) {
 		exitQueueEpoch += 1
 	}
+	return b.setExitEpochs(index, exitQueueEpoch)
+}
 
+func (b *CachingBeaconState) setExitEpochs(index uint64, exitQueueEpoch uint64) error {
 	var overflow bool
 	var newWithdrawableEpoch uint64
 	if newWithdrawableEpoch, overflow = math.SafeAdd(exitQueueEpoch, b.BeaconConfig().MinValidatorWithdrawabilityDelay); overflow {
 		return errors.New("withdrawable epoch is too big")
 	}
 	b.SetExitEpochForValidatorAtIndex(int(index), exitQueueEpoch)
-	b.SetWithdrawableEpochForValidatorAtIndex(int(index), newWithdrawableEpoch)
-	return nil
+	return b.SetWithdrawableEpochForValidatorAtIndex(int(index), newWithdrawableEpoch)
 }
@@ -16,7 +16,14 @@
 
 package state
 
-import "github.com/erigontech/erigon/cl/abstract"
+import (
+	libcommon "github.com/erigontech/erigon-lib/common"
+
+	"github.com/erigontech/erigon/cl/abstract"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
 
 func IncreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	currentBalance, err := b.ValidatorBalance(int(index))
@@ -37,3 +44,115 @@ func DecreaseBalance(b abstract.BeaconState, index, delta uint64) error {
 	}
 	return b.SetValidatorBalance(int(index), newBalance)
 }
+
+// ComputeExitEpochAndUpdateChurn consumes exit churn of state for exitBalance and returns exit epoch (Electra).
+// See: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_exit_epoch_and_update_churn
+func ComputeExitEpochAndUpdateChurn(b abstract.BeaconState, exitBalance uint64) uint64 {
+	earliestExitEpoch := max(b.EarliestExitEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochChurn := GetActivationExitChurnLimit(b)
+	// New epoch for exits.
+	exitBalanceToConsume := b.ExitBalanceToConsume()
+	if b.EarliestExitEpoch() < earliestExitEpoch {
+		exitBalanceToConsume = perEpochChurn
+	}
+	// Exit doesn't fit in the current earliest epoch.
+	if exitBalance > exitBalanceToConsume {
+		balanceToProcess := exitBalance - exitBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochChurn + 1
+		earliestExitEpoch += additionalEpochs
+		exitBalanceToConsume += additionalEpochs * perEpochChurn
+	}
+	b.SetExitBalanceToConsume(exitBalanceToConsume - exitBalance)
+	b.SetEarliestExitEpoch(earliestExitEpoch)
+	return earliestExitEpoch
+}
+
+// ComputeConsolidationEpochAndUpdateChurn consumes consolidation churn of state for consolidationBalance and returns
+// epoch of consolidation (Electra). Consolidation churn limit must be checked to be non-zero by caller.
+func ComputeConsolidationEpochAndUpdateChurn(b abstract.BeaconState, consolidationBalance uint64) uint64 {
+	earliestConsolidationEpoch := max(b.EarliestConsolidationEpoch(), ComputeActivationExitEpoch(b.BeaconConfig(), Epoch(b)))
+	perEpochConsolidationChurn := GetConsolidationChurnLimit(b)
+	// New epoch for consolidations.
+	consolidationBalanceToConsume := b.ConsolidationBalanceToConsume()
+	if b.EarliestConsolidationEpoch() < earliestConsolidationEpoch {
+		consolidationBalanceToConsume = perEpochConsolidationChurn
+	}
+	// Consolidation doesn't fit in the current earliest epoch.
+	if consolidationBalance > consolidationBalanceToConsume {
+		balanceToProcess := consolidationBalance - consolidationBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochConsolidationChurn + 1
+		earliestConsolidationEpoch += additionalEpochs
+		consolidationBalanceToConsume += additionalEpochs * perEpochConsolidationChurn
+	}
+	b.SetConsolidationBalanceToConsume(consolidationBalanceToConsume - consolidationBalance)
+	b.SetEarliestConsolidationEpoch(earliestConsolidationEpoch)
+	return earliestConsolidationEpoch
+}
+
+// g2PointAtInfinity is placeholder signature of pending deposits created by state transition itself.
+var g2PointAtInfinity = [96]byte{0xc0}
+
+// QueueExcessActiveBalance moves balance of validator above MinActivationBalance to pending deposits queue (Electra).
+func QueueExcessActiveBalance(b abstract.BeaconState, index uint64) error {
+	balance, err := b.ValidatorBalance(int(index))
+	if err != nil {
+		return err
+	}
+	minActivationBalance := b.BeaconConfig().MinActivationBalance
+	if balance <= minActivationBalance {
+		return nil
+	}
+	if err := b.SetValidatorBalance(int(index), minActivationBalance); err != nil {
+		return err
+	}
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	// G2 point at infinity signature and genesis slot distinguish it from pending deposit of deposit request
+	b.AppendPendingDeposit(&cltypes.PendingDeposit{
+		PubKey:                validator.PublicKey(),
+		WithdrawalCredentials: validator.WithdrawalCredentials(),
+		Amount:                balance - minActivationBalance,
+		Signature:             g2PointAtInfinity,
+		Slot:                  b.BeaconConfig().GenesisSlot,
+	})
+	return nil
+}
+
+// SwitchToCompoundingValidator changes withdrawal credentials of validator to 0x02 and queues its excess balance (Electra).
+func SwitchToCompoundingValidator(b abstract.BeaconState, index uint64) error {
+	validator, err := b.ValidatorForValidatorIndex(int(index))
+	if err != nil {
+		return err
+	}
+	wc := validator.WithdrawalCredentials()
+	wc[0] = byte(b.BeaconConfig().CompoundingWithdrawalPrefixByte)
+	b.SetWithdrawalCredentialForValidatorAtIndex(int(index), wc)
+	return QueueExcessActiveBalance(b, index)
+}
+
+// AddValidatorToRegistry appends a new validator built from deposit fields, with its balance and participation entries.
+func AddValidatorToRegistry(b abstract.BeaconState, pubkey libcommon.Bytes48, withdrawalCredentials libcommon.Hash, amount uint64) {
+	conf := b.BeaconConfig()
+	validator := solid.NewValidator()
+	validator.SetPublicKey(pubkey)
+	validator.SetWithdrawalCredentials(withdrawalCredentials)
+	validator.SetActivationEligibilityEpoch(conf.FarFutureEpoch)
+	validator.SetActivationEpoch(conf.FarFutureEpoch)
+	validator.SetExitEpoch(conf.FarFutureEpoch)
+	validator.SetWithdrawableEpoch(conf.FarFutureEpoch)
+	maxEffectiveBalance := conf.MaxEffectiveBalance
+	if b.Version() >= clparams.ElectraVersion {
+		maxEffectiveBalance = GetMaxEffectiveBalance(conf, validator)
+	}
+	validator.SetEffectiveBalance(min(amount-amount%conf.EffectiveBalanceIncrement, maxEffectiveBalance))
+
+	b.AddValidator(validator, amount)
+	// Altair forward
+	if b.Version() >= clparams.AltairVersion {
+		b.AddCurrentEpochParticipationFlags(cltypes.ParticipationFlags(0))
+		b.AddPreviousEpochParticipationFlags(cltypes.ParticipationFlags(0))
+		b.AddInactivityScore(0)
+	}
+}
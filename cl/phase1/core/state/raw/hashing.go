@@ -34,20 +34,38 @@ func (b *BeaconState) HashSSZ() (out [32]byte, err error) {
 	// for i := 0; i < len(b.leaves); i += 32 {
 	// 	fmt.Println(i/32, libcommon.BytesToHash(b.leaves[i:i+32]))
 	// }
-	// Pad to 32 of length
-	err = merkle_tree.MerkleRootFromFlatLeaves(b.leaves, out[:])
+	// Pad to 32 (64 from Electra) of length
+	err = merkle_tree.MerkleRootFromFlatLeaves(b.versionLeaves(), out[:])
 	return
 }
 
+// versionLeaves returns leaves of fields of current state version: merkle tree has 32 leaves
+// before Electra and 64 leaves from Electra.
+func (b *BeaconState) versionLeaves() []byte {
+	if b.version >= clparams.ElectraVersion {
+		return b.leaves
+	}
+	return b.leaves[:32*32]
+}
+
+// leavesProofDepth returns depth of state fields merkle tree.
+func (b *BeaconState) leavesProofDepth() int {
+	if b.version >= clparams.ElectraVersion {
+		return 6
+	}
+	return 5
+}
+
 func (b *BeaconState) CurrentSyncCommitteeBranch() ([][32]byte, error) {
 	if err := b.computeDirtyLeaves(); err != nil {
 		return nil, err
 	}
 	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
+	leaves := b.versionLeaves()
+	for i := 0; i < len(leaves); i += 32 {
+		schema = append(schema, leaves[i:i+32])
 	}
-	return merkle_tree.MerkleProof(5, 22, schema...)
+	return merkle_tree.MerkleProof(b.leavesProofDepth(), 22, schema...)
 }
 
 func (b *BeaconState) NextSyncCommitteeBranch() ([][32]byte, error) {
@@ -55,10 +73,11 @@ func (b *BeaconState) NextSyncCommitteeBranch() ([][32]byte, error) {
 		return nil, err
 	}
 	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
+	leaves := b.versionLeaves()
+	for i := 0; i < len(leaves); i += 32 {
+		schema = append(schema, leaves[i:i+32])
 	}
-	return merkle_tree.MerkleProof(5, 23, schema...)
+	return merkle_tree.MerkleProof(b.leavesProofDepth(), 23, schema...)
 }
 
 func (b *BeaconState) FinalityRootBranch() ([][32]byte, error) {
@@ -66,10 +85,11 @@ func (b *BeaconState) FinalityRootBranch() ([][32]byte, error) {
 		return nil, err
 	}
 	schema := []interface{}{}
-	for i := 0; i < len(b.leaves); i += 32 {
-		schema = append(schema, b.leaves[i:i+32])
+	leaves := b.versionLeaves()
+	for i := 0; i < len(leaves); i += 32 {
+		schema = append(schema, leaves[i:i+32])
 	}
-	proof, err := merkle_tree.MerkleProof(5, 20, schema...)
+	proof, err := merkle_tree.MerkleProof(b.leavesProofDepth(), 20, schema...)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +198,20 @@ func (b *BeaconState) computeDirtyLeaves() error {
 	beaconStateHasher.add(NextWithdrawalIndexLeafIndex, b.nextWithdrawalIndex)
 	beaconStateHasher.add(NextWithdrawalValidatorIndexLeafIndex, b.nextWithdrawalValidatorIndex)
 	beaconStateHasher.add(HistoricalSummariesLeafIndex, b.historicalSummaries)
+	if b.version < clparams.ElectraVersion {
+		beaconStateHasher.run()
+		return nil
+	}
+	// Electra fields
+	beaconStateHasher.add(DepositRequestsStartIndexLeafIndex, b.depositRequestsStartIndex)
+	beaconStateHasher.add(DepositBalanceToConsumeLeafIndex, b.depositBalanceToConsume)
+	beaconStateHasher.add(ExitBalanceToConsumeLeafIndex, b.exitBalanceToConsume)
+	beaconStateHasher.add(EarliestExitEpochLeafIndex, b.earliestExitEpoch)
+	beaconStateHasher.add(ConsolidationBalanceToConsumeLeafIndex, b.consolidationBalanceToConsume)
+	beaconStateHasher.add(EarliestConsolidationEpochLeafIndex, b.earliestConsolidationEpoch)
+	beaconStateHasher.add(PendingDepositsLeafIndex, b.pendingDeposits)
+	beaconStateHasher.add(PendingPartialWithdrawalsLeafIndex, b.pendingPartialWithdrawals)
+	beaconStateHasher.add(PendingConsolidationsLeafIndex, b.pendingConsolidations)
 
 	beaconStateHasher.run()
 
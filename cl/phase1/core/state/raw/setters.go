@@ -513,3 +513,66 @@ func (b *BeaconState) SetSlashings(slashings solid.Uint64VectorSSZ) {
 	b.markLeaf(SlashingsLeafIndex)
 	b.slashings = slashings
 }
+
+func (b *BeaconState) SetDepositRequestsStartIndex(index uint64) {
+	b.depositRequestsStartIndex = index
+	b.markLeaf(DepositRequestsStartIndexLeafIndex)
+}
+
+func (b *BeaconState) SetDepositBalanceToConsume(balance uint64) {
+	b.depositBalanceToConsume = balance
+	b.markLeaf(DepositBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetExitBalanceToConsume(balance uint64) {
+	b.exitBalanceToConsume = balance
+	b.markLeaf(ExitBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetEarliestExitEpoch(epoch uint64) {
+	b.earliestExitEpoch = epoch
+	b.markLeaf(EarliestExitEpochLeafIndex)
+}
+
+func (b *BeaconState) SetConsolidationBalanceToConsume(balance uint64) {
+	b.consolidationBalanceToConsume = balance
+	b.markLeaf(ConsolidationBalanceToConsumeLeafIndex)
+}
+
+func (b *BeaconState) SetEarliestConsolidationEpoch(epoch uint64) {
+	b.earliestConsolidationEpoch = epoch
+	b.markLeaf(EarliestConsolidationEpochLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingDeposit(deposit *cltypes.PendingDeposit) {
+	b.pendingDeposits.Append(deposit)
+	b.markLeaf(PendingDepositsLeafIndex)
+}
+
+// SetPendingDeposits replaces pending deposits queue, it must be called after in-place changes of PendingDeposits().
+func (b *BeaconState) SetPendingDeposits(l *solid.ListSSZ[*cltypes.PendingDeposit]) {
+	b.pendingDeposits = l
+	b.markLeaf(PendingDepositsLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingPartialWithdrawal(withdrawal *cltypes.PendingPartialWithdrawal) {
+	b.pendingPartialWithdrawals.Append(withdrawal)
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+}
+
+// SetPendingPartialWithdrawals replaces pending partial withdrawals queue, it must be called after in-place changes of PendingPartialWithdrawals().
+func (b *BeaconState) SetPendingPartialWithdrawals(l *solid.ListSSZ[*cltypes.PendingPartialWithdrawal]) {
+	b.pendingPartialWithdrawals = l
+	b.markLeaf(PendingPartialWithdrawalsLeafIndex)
+}
+
+func (b *BeaconState) AppendPendingConsolidation(consolidation *cltypes.PendingConsolidation) {
+	b.pendingConsolidations.Append(consolidation)
+	b.markLeaf(PendingConsolidationsLeafIndex)
+}
+
+// SetPendingConsolidations replaces pending consolidations queue, it must be called after in-place changes of PendingConsolidations().
+func (b *BeaconState) SetPendingConsolidations(l *solid.ListSSZ[*cltypes.PendingConsolidation]) {
+	b.pendingConsolidations = l
+	b.markLeaf(PendingConsolidationsLeafIndex)
+}
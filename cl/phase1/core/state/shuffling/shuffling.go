@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 
+	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/phase1/core/state/raw"
 
 	"github.com/erigontech/erigon/cl/utils"
@@ -30,6 +31,7 @@ func ComputeProposerIndex(b *raw.BeaconState, indices []uint64, seed [32]byte) (
 		return 0, nil
 	}
 	maxRandomByte := uint64(1<<8 - 1)
+	maxRandomValue := uint64(1<<16 - 1)
 	i := uint64(0)
 	total := uint64(len(indices))
 	input := make([]byte, 40)
@@ -43,13 +45,25 @@ func ComputeProposerIndex(b *raw.BeaconState, indices []uint64, seed [32]byte) (
 		if candidateIndex >= uint64(b.ValidatorLength()) {
 			return 0, fmt.Errorf("candidate index out of range: %d for validator set of length: %d", candidateIndex, b.ValidatorLength())
 		}
-		copy(input, seed[:])
-		binary.LittleEndian.PutUint64(input[32:], i/32)
-		randomByte := uint64(utils.Sha256(input)[i%32])
 		validator, err := b.ValidatorForValidatorIndex(int(candidateIndex))
 		if err != nil {
 			return 0, err
 		}
+		copy(input, seed[:])
+		if b.Version() >= clparams.ElectraVersion {
+			// Electra samples with 2 random bytes against the compounding max effective balance.
+			binary.LittleEndian.PutUint64(input[32:], i/16)
+			offset := (i % 16) * 2
+			randomBytes := utils.Sha256(input)
+			randomValue := uint64(binary.LittleEndian.Uint16(randomBytes[offset : offset+2]))
+			if validator.EffectiveBalance()*maxRandomValue >= b.BeaconConfig().MaxEffectiveBalanceElectra*randomValue {
+				return candidateIndex, nil
+			}
+			i += 1
+			continue
+		}
+		binary.LittleEndian.PutUint64(input[32:], i/32)
+		randomByte := uint64(utils.Sha256(input)[i%32])
 		if validator.EffectiveBalance()*maxRandomByte >= b.BeaconConfig().MaxEffectiveBalance*randomByte {
 			return candidateIndex, nil
 		}
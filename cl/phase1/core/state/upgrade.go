@@ -17,6 +17,9 @@
 package state
 
 import (
+	"cmp"
+	"slices"
+
 	libcommon "github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
@@ -145,11 +148,74 @@ func (b *CachingBeaconState) UpgradeToElectra() error {
 	fork.PreviousVersion = fork.CurrentVersion
 	fork.CurrentVersion = utils.Uint32ToBytes4(uint32(b.BeaconConfig().ElectraForkVersion))
 	b.SetFork(fork)
+	// Payload header is the same as in Deneb.
+	cfg := b.BeaconConfig()
+	earliestExitEpoch := ComputeActivationExitEpoch(cfg, epoch)
+	b.ForEachValidator(func(v solid.Validator, _, _ int) bool {
+		if v.ExitEpoch() != cfg.FarFutureEpoch && v.ExitEpoch() > earliestExitEpoch {
+			earliestExitEpoch = v.ExitEpoch()
+		}
+		return true
+	})
+	// Set new fields
+	b.SetDepositRequestsStartIndex(cfg.UnsetDepositRequestsStartIndex)
+	b.SetDepositBalanceToConsume(0)
+	b.SetEarliestExitEpoch(earliestExitEpoch + 1)
+	b.SetEarliestConsolidationEpoch(ComputeActivationExitEpoch(cfg, epoch))
+	b.SetExitBalanceToConsume(GetActivationExitChurnLimit(b))
+	b.SetConsolidationBalanceToConsume(GetConsolidationChurnLimit(b))
+	b.SetPendingDeposits(solid.NewStaticListSSZ[*cltypes.PendingDeposit](int(cfg.PendingDepositsLimit), 192))
+	b.SetPendingPartialWithdrawals(solid.NewStaticListSSZ[*cltypes.PendingPartialWithdrawal](int(cfg.PendingPartialWithdrawalsLimit), 24))
+	b.SetPendingConsolidations(solid.NewStaticListSSZ[*cltypes.PendingConsolidation](int(cfg.PendingConsolidationsLimit), 16))
 
-	// Update the payload header.
-	//header := b.LatestExecutionPayloadHeader()
-	// header.Electra()
-	//b.SetLatestExecutionPayloadHeader(header)
+	// Validators which are not active yet go through pending deposits queue, ordered by eligibility epoch.
+	type preActivationValidator struct{ index, eligibilityEpoch uint64 }
+	preActivation := []preActivationValidator{}
+	b.ForEachValidator(func(v solid.Validator, idx, _ int) bool {
+		if v.ActivationEpoch() == cfg.FarFutureEpoch {
+			preActivation = append(preActivation, preActivationValidator{uint64(idx), v.ActivationEligibilityEpoch()})
+		}
+		return true
+	})
+	slices.SortStableFunc(preActivation, func(a, b preActivationValidator) int {
+		return cmp.Or(cmp.Compare(a.eligibilityEpoch, b.eligibilityEpoch), cmp.Compare(a.index, b.index))
+	})
+	for _, v := range preActivation {
+		index := v.index
+		balance, err := b.ValidatorBalance(int(index))
+		if err != nil {
+			return err
+		}
+		if err := b.SetValidatorBalance(int(index), 0); err != nil {
+			return err
+		}
+		validator, err := b.ValidatorForValidatorIndex(int(index))
+		if err != nil {
+			return err
+		}
+		b.SetEffectiveBalanceForValidatorAtIndex(int(index), 0)
+		b.SetActivationEligibilityEpochForValidatorAtIndex(int(index), cfg.FarFutureEpoch)
+		b.AppendPendingDeposit(&cltypes.PendingDeposit{
+			PubKey:                validator.PublicKey(),
+			WithdrawalCredentials: validator.WithdrawalCredentials(),
+			Amount:                balance,
+			Signature:             g2PointAtInfinity,
+			Slot:                  cfg.GenesisSlot,
+		})
+	}
+	// Early adopters of compounding credentials go through the activation churn.
+	for index := 0; index < b.ValidatorLength(); index++ {
+		validator, err := b.ValidatorForValidatorIndex(index)
+		if err != nil {
+			return err
+		}
+		if !HasCompoundingWithdrawalCredential(cfg, validator) {
+			continue
+		}
+		if err := QueueExcessActiveBalance(b, uint64(index)); err != nil {
+			return err
+		}
+	}
 
 	// Update the state root cache
 	b.SetVersion(clparams.ElectraVersion)
@@ -19,6 +19,9 @@ package state
 import (
 	"sort"
 
+	libcommon "github.com/erigontech/erigon-lib/common"
+
+	"github.com/erigontech/erigon/cl/abstract"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
@@ -65,19 +68,58 @@ func ValidatorFromDeposit(conf *clparams.BeaconChainConfig, deposit *cltypes.Dep
 }
 
 // Check whether a validator is fully withdrawable at the given epoch.
-func isFullyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64, epoch uint64) bool {
+func isFullyWithdrawableValidator(b abstract.BeaconState, validator solid.Validator, balance uint64, epoch uint64) bool {
+	conf := b.BeaconConfig()
 	withdrawalCredentials := validator.WithdrawalCredentials()
+	if b.Version() >= clparams.ElectraVersion {
+		return HasExecutionWithdrawalCredential(conf, validator) && validator.WithdrawableEpoch() <= epoch && balance > 0
+	}
 	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
 		validator.WithdrawableEpoch() <= epoch && balance > 0
 }
 
 // Check whether a validator is partially withdrawable.
-func isPartiallyWithdrawableValidator(conf *clparams.BeaconChainConfig, validator solid.Validator, balance uint64) bool {
+func isPartiallyWithdrawableValidator(b abstract.BeaconState, validator solid.Validator, balance uint64) bool {
+	conf := b.BeaconConfig()
 	withdrawalCredentials := validator.WithdrawalCredentials()
+	if b.Version() >= clparams.ElectraVersion {
+		maxEffectiveBalance := GetMaxEffectiveBalance(conf, validator)
+		return HasExecutionWithdrawalCredential(conf, validator) &&
+			validator.EffectiveBalance() == maxEffectiveBalance && balance > maxEffectiveBalance
+	}
 	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte) &&
 		validator.EffectiveBalance() == conf.MaxEffectiveBalance && balance > conf.MaxEffectiveBalance
 }
 
+// HasEth1WithdrawalCredential checks whether validator has 0x01 withdrawal credentials.
+func HasEth1WithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	withdrawalCredentials := validator.WithdrawalCredentials()
+	return withdrawalCredentials[0] == byte(conf.ETH1AddressWithdrawalPrefixByte)
+}
+
+// IsCompoundingWithdrawalCredential checks whether withdrawal credentials have 0x02 prefix (EIP-7251).
+func IsCompoundingWithdrawalCredential(conf *clparams.BeaconChainConfig, withdrawalCredentials libcommon.Hash) bool {
+	return withdrawalCredentials[0] == byte(conf.CompoundingWithdrawalPrefixByte)
+}
+
+// HasCompoundingWithdrawalCredential checks whether validator is compounding one (EIP-7251).
+func HasCompoundingWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return IsCompoundingWithdrawalCredential(conf, validator.WithdrawalCredentials())
+}
+
+// HasExecutionWithdrawalCredential checks whether validator has 0x01 or 0x02 withdrawal credentials.
+func HasExecutionWithdrawalCredential(conf *clparams.BeaconChainConfig, validator solid.Validator) bool {
+	return HasCompoundingWithdrawalCredential(conf, validator) || HasEth1WithdrawalCredential(conf, validator)
+}
+
+// GetMaxEffectiveBalance returns maximum effective balance of validator: from Electra compounding validators can have up to 2048 ETH.
+func GetMaxEffectiveBalance(conf *clparams.BeaconChainConfig, validator solid.Validator) uint64 {
+	if HasCompoundingWithdrawalCredential(conf, validator) {
+		return conf.MaxEffectiveBalanceElectra
+	}
+	return conf.MinActivationBalance
+}
+
 func ComputeActivationExitEpoch(config *clparams.BeaconChainConfig, epoch uint64) uint64 {
 	return epoch + 1 + config.MaxSeedLookahead
 }
@@ -131,6 +131,11 @@ func (cc *ExecutionClientDirect) IsCanonicalHash(ctx context.Context, hash libco
 	return cc.chainRW.IsCanonicalHash(ctx, hash)
 }
 
+// BlockNumberByHash returns the number of the block with the given hash, nil if unknown.
+func (cc *ExecutionClientDirect) BlockNumberByHash(ctx context.Context, hash libcommon.Hash) (*uint64, error) {
+	return cc.chainRW.HeaderNumber(ctx, hash)
+}
+
 func (cc *ExecutionClientDirect) Ready(ctx context.Context) (bool, error) {
 	return cc.chainRW.Ready(ctx)
 }
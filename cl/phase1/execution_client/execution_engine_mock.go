@@ -45,6 +45,45 @@ func (m *MockExecutionEngine) EXPECT() *MockExecutionEngineMockRecorder {
 	return m.recorder
 }
 
+// BlockNumberByHash mocks base method.
+func (m *MockExecutionEngine) BlockNumberByHash(ctx context.Context, hash common.Hash) (*uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockNumberByHash", ctx, hash)
+	ret0, _ := ret[0].(*uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockNumberByHash indicates an expected call of BlockNumberByHash.
+func (mr *MockExecutionEngineMockRecorder) BlockNumberByHash(ctx, hash any) *MockExecutionEngineBlockNumberByHashCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockNumberByHash", reflect.TypeOf((*MockExecutionEngine)(nil).BlockNumberByHash), ctx, hash)
+	return &MockExecutionEngineBlockNumberByHashCall{Call: call}
+}
+
+// MockExecutionEngineBlockNumberByHashCall wrap *gomock.Call
+type MockExecutionEngineBlockNumberByHashCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockExecutionEngineBlockNumberByHashCall) Return(arg0 *uint64, arg1 error) *MockExecutionEngineBlockNumberByHashCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockExecutionEngineBlockNumberByHashCall) Do(f func(context.Context, common.Hash) (*uint64, error)) *MockExecutionEngineBlockNumberByHashCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockExecutionEngineBlockNumberByHashCall) DoAndReturn(f func(context.Context, common.Hash) (*uint64, error)) *MockExecutionEngineBlockNumberByHashCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CurrentHeader mocks base method.
 func (m *MockExecutionEngine) CurrentHeader(ctx context.Context) (*types.Header, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,142 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package forkchoice
+
+import (
+	"context"
+	"fmt"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/das"
+)
+
+func (f *ForkChoiceStore) AddPreverifiedDataColumnSidecar(dataColumnSidecar *cltypes.DataColumnSidecar) error {
+	blockRoot, err := dataColumnSidecar.SignedBlockHeader.Header.HashSSZ()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, sidecar := range f.hotColumnSidecars[blockRoot] {
+		if sidecar.Index == dataColumnSidecar.Index {
+			return nil // ignore if we already have it
+		}
+	}
+	f.hotColumnSidecars[blockRoot] = append(f.hotColumnSidecars[blockRoot], dataColumnSidecar)
+
+	columnsMaxAge := uint64(4) // same as blob sidecars, a column lives for up to 4 slots in the pool.
+	currentSlot := f.highestSeen.Load()
+	var pruneSlot uint64
+	if currentSlot > columnsMaxAge {
+		pruneSlot = currentSlot - columnsMaxAge
+	}
+	for root, sidecars := range f.hotColumnSidecars {
+		if len(sidecars) == 0 || sidecars[0].SignedBlockHeader.Header.Slot < pruneSlot {
+			delete(f.hotColumnSidecars, root)
+		}
+	}
+	return nil
+}
+
+// isDataColumnsAvailable implements the PeerDAS is_data_available: we need every column we custody. If we have at
+// least half of the columns, the missing ones are reconstructed instead.
+func (f *ForkChoiceStore) isDataColumnsAvailable(ctx context.Context, slot uint64, blockRoot libcommon.Hash, blobKzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment]) error {
+	if f.dataColumnStorage == nil || blobKzgCommitments.Len() == 0 {
+		return nil
+	}
+	stored, err := f.dataColumnStorage.ColumnSidecarIndices(ctx, slot, blockRoot)
+	if err != nil {
+		return fmt.Errorf("cannot check data avaiability. failed to read data column sidecars: %v", err)
+	}
+	available := make(map[uint64]struct{}, len(stored))
+	for _, index := range stored {
+		available[index] = struct{}{}
+	}
+	hotSidecars := f.hotColumnSidecars[blockRoot]
+	for _, sidecar := range hotSidecars {
+		if sidecar.KzgCommitments.Len() != blobKzgCommitments.Len() {
+			return ErrEIP4844DataNotAvailable
+		}
+		available[sidecar.Index] = struct{}{}
+	}
+	missing := 0
+	for _, column := range f.custodyColumns {
+		if _, ok := available[column]; !ok {
+			missing++
+		}
+	}
+	if missing > 0 {
+		if uint64(len(available))*2 < f.beaconCfg.NumberOfColumns {
+			return ErrEIP4844DataNotAvailable // This should then schedule the block for reprocessing
+		}
+		if hotSidecars, err = f.reconstructDataColumns(ctx, slot, blockRoot, stored, hotSidecars); err != nil {
+			return err
+		}
+	}
+	if len(hotSidecars) > 0 {
+		if err := f.dataColumnStorage.WriteColumnSidecars(ctx, blockRoot, hotSidecars); err != nil {
+			return fmt.Errorf("failed to write data column sidecars: %v", err)
+		}
+	}
+	delete(f.hotColumnSidecars, blockRoot)
+	return nil
+}
+
+// reconstructDataColumns recovers the whole matrix and returns the custody columns we did not store yet.
+func (f *ForkChoiceStore) reconstructDataColumns(ctx context.Context, slot uint64, blockRoot libcommon.Hash, stored []uint64, hotSidecars []*cltypes.DataColumnSidecar) ([]*cltypes.DataColumnSidecar, error) {
+	sidecars := make([]*cltypes.DataColumnSidecar, 0, len(stored)+len(hotSidecars))
+	sidecars = append(sidecars, hotSidecars...)
+	for _, index := range stored {
+		sidecar, found, err := f.dataColumnStorage.ReadColumnSidecar(ctx, slot, blockRoot, index)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			sidecars = append(sidecars, sidecar)
+		}
+	}
+	seen := make(map[uint64]struct{}, len(sidecars))
+	unique := sidecars[:0]
+	for _, sidecar := range sidecars {
+		if _, ok := seen[sidecar.Index]; ok {
+			continue
+		}
+		seen[sidecar.Index] = struct{}{}
+		unique = append(unique, sidecar)
+	}
+	recovered, err := das.RecoverDataColumnSidecars(f.beaconCfg, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct data columns: %v", err)
+	}
+	log.Debug("Reconstructed data columns", "slot", slot, "blockRoot", blockRoot, "from", len(unique))
+	storedSet := make(map[uint64]struct{}, len(stored))
+	for _, index := range stored {
+		storedSet[index] = struct{}{}
+	}
+	toStore := make([]*cltypes.DataColumnSidecar, 0, len(f.custodyColumns))
+	for _, column := range f.custodyColumns {
+		if _, ok := storedSet[column]; !ok {
+			toStore = append(toStore, recovered[column])
+		}
+	}
+	return toStore, nil
+}
@@ -104,14 +104,6 @@ type savedStateRecord struct {
 	slot uint64
 }
 
-func convertHashSliceToHashList(in [][32]byte) solid.HashVectorSSZ {
-	out := solid.NewHashVector(len(in))
-	for i, v := range in {
-		out.Set(i, libcommon.Hash(v))
-	}
-	return out
-}
-
 // ForkGraph is our graph for ETH 2.0 consensus forkchoice. Each node is a (block root, changes) pair and
 // each edge is the path described as (prevBlockRoot, currBlockRoot). if we want to go forward we use blocks.
 type forkGraphDisk struct {
@@ -237,24 +229,15 @@ func (f *forkGraphDisk) AddChainSegment(signedBlock *cltypes.SignedBeaconBlock,
 
 	// Before processing the state: update the newest lightclient update.
 	if block.Version() >= clparams.AltairVersion && hasParentBlock && fullValidation && hasFinalized && f.rcfg.Beacon {
-		nextSyncCommitteeBranch, err := newState.NextSyncCommitteeBranch()
-		if err != nil {
-			return nil, LogisticError, err
-		}
-		finalityBranch, err := newState.FinalityRootBranch()
-		if err != nil {
-			return nil, LogisticError, err
-		}
-		lcUpdate, err := lightclient_utils.CreateLightClientUpdate(f.beaconCfg, signedBlock, finalizedBlock, parentBlock, newState.Slot(),
-			newState.NextSyncCommittee(), newState.FinalizedCheckpoint(), convertHashSliceToHashList(nextSyncCommitteeBranch), convertHashSliceToHashList(finalityBranch))
+		lcUpdate, err := lightclient_utils.CreateLightClientUpdateFromAttestedState(f.beaconCfg, signedBlock, finalizedBlock, parentBlock, newState)
 		if err != nil {
 			log.Debug("Could not create light client update", "err", err)
 		} else {
 			f.newestLightClientUpdate.Store(lcUpdate)
 			period := f.beaconCfg.SyncCommitteePeriod(newState.Slot())
-			_, hasPeriod := f.lightClientUpdates.Load(period)
-			if !hasPeriod {
-				log.Info("Adding light client update", "period", period)
+			best, hasPeriod := f.lightClientUpdates.Load(period)
+			if !hasPeriod || lightclient_utils.IsBetterUpdate(f.beaconCfg, lcUpdate, best.(*cltypes.LightClientUpdate)) {
+				log.Debug("Updating best light client update", "period", period, "signatureSlot", lcUpdate.SignatureSlot)
 				f.lightClientUpdates.Store(period, lcUpdate)
 			}
 			// light client events
@@ -29,6 +29,7 @@ import (
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/monitor"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	state2 "github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/phase1/execution_client"
@@ -94,7 +95,8 @@ type ForkChoiceStore struct {
 	genesisValidatorsRoot libcommon.Hash
 	weights               map[libcommon.Hash]uint64
 	headSet               map[libcommon.Hash]struct{}
-	hotSidecars           map[libcommon.Hash][]*cltypes.BlobSidecar // Set of sidecars that are not yet processed.
+	hotSidecars           map[libcommon.Hash][]*cltypes.BlobSidecar       // Set of sidecars that are not yet processed.
+	hotColumnSidecars     map[libcommon.Hash][]*cltypes.DataColumnSidecar // Set of data column sidecars that are not yet processed.
 	// childrens
 	childrens sync.Map
 
@@ -102,6 +104,8 @@ type ForkChoiceStore struct {
 	equivocatingIndicies []byte
 	forkGraph            fork_graph.ForkGraph
 	blobStorage          blob_storage.BlobStorage
+	dataColumnStorage    blob_storage.DataColumnStorage
+	custodyColumns       []uint64
 	// I use the cache due to the convenient auto-cleanup feauture.
 	checkpointStates sync.Map // We keep ssz snappy of it as the full beacon state is full of rendundant data.
 
@@ -136,6 +140,10 @@ type ForkChoiceStore struct {
 	ethClock         eth_clock.EthereumClock
 	optimisticStore  optimistic.OptimisticStore
 	validatorMonitor monitor.ValidatorMonitor
+
+	// Deposit contract tree (EIP-4881), nil if it could not be restored for the anchor state.
+	depositTree *deposit_tree.DepositTree
+	eth1Datas   *lru.Cache[libcommon.Hash, *cltypes.Eth1Data] // block root -> eth1 data of its post state
 }
 
 type LatestMessage struct {
@@ -158,7 +166,10 @@ func NewForkChoiceStore(
 	emitters *beaconevents.EventEmitter,
 	syncedDataManager *synced_data.SyncedDataManager,
 	blobStorage blob_storage.BlobStorage,
+	dataColumnStorage blob_storage.DataColumnStorage,
+	custodyColumns []uint64,
 	validatorMonitor monitor.ValidatorMonitor,
+	depositTree *deposit_tree.DepositTree,
 ) (*ForkChoiceStore, error) {
 	anchorRoot, err := anchorState.BlockRoot()
 	if err != nil {
@@ -224,6 +235,12 @@ func NewForkChoiceStore(
 		return nil, err
 	}
 
+	eth1Datas, err := lru.New[libcommon.Hash, *cltypes.Eth1Data](checkpointsPerCache)
+	if err != nil {
+		return nil, err
+	}
+	eth1Datas.Add(anchorRoot, anchorState.Eth1Data().Copy())
+
 	participation.Add(state.Epoch(anchorState.BeaconState), anchorState.CurrentEpochParticipation().Copy())
 
 	totalActiveBalances.Add(anchorRoot, anchorState.GetTotalActiveBalance())
@@ -255,10 +272,15 @@ func NewForkChoiceStore(
 		nextBlockProposers:    nextBlockProposers,
 		genesisValidatorsRoot: anchorState.GenesisValidatorsRoot(),
 		hotSidecars:           make(map[libcommon.Hash][]*cltypes.BlobSidecar),
+		hotColumnSidecars:     make(map[libcommon.Hash][]*cltypes.DataColumnSidecar),
 		blobStorage:           blobStorage,
+		dataColumnStorage:     dataColumnStorage,
+		custodyColumns:        custodyColumns,
 		ethClock:              ethClock,
 		optimisticStore:       optimistic.NewOptimisticStore(),
 		validatorMonitor:      validatorMonitor,
+		depositTree:           depositTree,
+		eth1Datas:             eth1Datas,
 	}
 	f.justifiedCheckpoint.Store(anchorCheckpoint)
 	f.finalizedCheckpoint.Store(anchorCheckpoint)
@@ -329,6 +351,19 @@ func (f *ForkChoiceStore) FinalizedSlot() uint64 {
 	return f.computeStartSlotAtEpoch(f.finalizedCheckpoint.Load().(solid.Checkpoint).Epoch) + (f.beaconCfg.SlotsPerEpoch - 1)
 }
 
+// GetDepositSnapshot returns the finalized deposit tree snapshot (EIP-4881), if the deposit tree is available.
+func (f *ForkChoiceStore) GetDepositSnapshot() (*cltypes.DepositTreeSnapshot, bool) {
+	if f.depositTree == nil {
+		return nil, false
+	}
+	return f.depositTree.Snapshot(), true
+}
+
+// DepositTree returns the deposit contract tree, nil if it is not available.
+func (f *ForkChoiceStore) DepositTree() *deposit_tree.DepositTree {
+	return f.depositTree
+}
+
 // FinalizedCheckpoint returns justified checkpoint
 func (f *ForkChoiceStore) Engine() execution_client.ExecutionEngine {
 	f.mu.RLock()
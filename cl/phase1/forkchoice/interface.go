@@ -39,6 +39,7 @@ type ForkChoiceStorageReader interface {
 	Engine() execution_client.ExecutionEngine
 	FinalizedCheckpoint() solid.Checkpoint
 	FinalizedSlot() uint64
+	GetDepositSnapshot() (*cltypes.DepositTreeSnapshot, bool)
 	LowestAvailableSlot() uint64
 	GetEth1Hash(eth2Root common.Hash) common.Hash
 	GetHead() (common.Hash, uint64, error)
@@ -91,6 +92,7 @@ type ForkChoiceStorageWriter interface {
 		checkDataAvaibility bool,
 	) error
 	AddPreverifiedBlobSidecar(blobSidecar *cltypes.BlobSidecar) error
+	AddPreverifiedDataColumnSidecar(dataColumnSidecar *cltypes.DataColumnSidecar) error
 	OnTick(time uint64)
 	SetSynced(synced bool)
 	ProcessAttestingIndicies(attestation *solid.Attestation, attestionIndicies []uint64)
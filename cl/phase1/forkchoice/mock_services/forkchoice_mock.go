@@ -64,6 +64,7 @@ type ForkChoiceStorageMock struct {
 	SyncContributionPool      sync_contribution_pool.SyncContributionPool
 	Headers                   map[common.Hash]*cltypes.BeaconBlockHeader
 	GetBeaconCommitteeMock    func(slot, committeeIndex uint64) ([]uint64, error)
+	DepositSnapshotVal        *cltypes.DepositTreeSnapshot
 
 	Pool pool.OperationsPool
 }
@@ -164,6 +165,10 @@ func (f *ForkChoiceStorageMock) FinalizedSlot() uint64 {
 	return f.FinalizedSlotVal
 }
 
+func (f *ForkChoiceStorageMock) GetDepositSnapshot() (*cltypes.DepositTreeSnapshot, bool) {
+	return f.DepositSnapshotVal, f.DepositSnapshotVal != nil
+}
+
 func (f *ForkChoiceStorageMock) GetEth1Hash(eth2Root common.Hash) common.Hash {
 	panic("implement me")
 }
@@ -357,6 +362,10 @@ func (f *ForkChoiceStorageMock) GetPublicKeyForValidator(
 func (f *ForkChoiceStorageMock) AddPreverifiedBlobSidecar(msg *cltypes.BlobSidecar) error {
 	return nil
 }
+
+func (f *ForkChoiceStorageMock) AddPreverifiedDataColumnSidecar(msg *cltypes.DataColumnSidecar) error {
+	return nil
+}
 func (f *ForkChoiceStorageMock) ValidateOnAttestation(attestation *solid.Attestation) error {
 	panic("implement me")
 }
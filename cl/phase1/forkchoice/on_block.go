@@ -112,7 +112,11 @@ func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeac
 
 	// Check if blob data is available
 	if block.Version() >= clparams.DenebVersion && checkDataAvaiability {
-		if err := f.isDataAvailable(ctx, block.Block.Slot, blockRoot, block.Block.Body.BlobKzgCommitments); err != nil {
+		isDataAvailable := f.isDataAvailable
+		if f.beaconCfg.IsPeerDASActive(block.Block.Slot / f.beaconCfg.SlotsPerEpoch) {
+			isDataAvailable = f.isDataColumnsAvailable
+		}
+		if err := isDataAvailable(ctx, block.Block.Slot, blockRoot, block.Block.Body.BlobKzgCommitments); err != nil {
 			if err == ErrEIP4844DataNotAvailable {
 				return err
 			}
@@ -181,6 +185,8 @@ func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeac
 	if block.Block.Body.ExecutionPayload != nil {
 		f.eth2Roots.Add(blockRoot, block.Block.Body.ExecutionPayload.BlockHash)
 	}
+	f.eth1Datas.Add(blockRoot, lastProcessedState.Eth1Data().Copy())
+	f.addDepositsToTree(block.Block.Body.Deposits, lastProcessedState.Eth1DepositIndex())
 
 	if block.Block.Slot > f.highestSeen.Load() {
 		f.highestSeen.Store(block.Block.Slot)
@@ -17,6 +17,7 @@
 package forkchoice
 
 import (
+	"context"
 	"errors"
 
 	"github.com/erigontech/erigon/cl/beacon/beaconevents"
@@ -24,6 +25,7 @@ import (
 
 	libcommon "github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 )
@@ -78,6 +80,58 @@ func (f *ForkChoiceStore) onNewFinalized(newFinalized solid.Checkpoint) {
 	})
 	slotToPrune := ((newFinalized.Epoch - 3) * f.beaconCfg.SlotsPerEpoch) - 1
 	f.forkGraph.Prune(slotToPrune)
+	f.finalizeDepositTree(newFinalized.Root)
+}
+
+// addDepositsToTree inserts the deposits processed by a block into the deposit tree, eth1DepositIndex is the
+// deposit index of the block post state.
+func (f *ForkChoiceStore) addDepositsToTree(deposits *solid.ListSSZ[*cltypes.Deposit], eth1DepositIndex uint64) {
+	if f.depositTree == nil || deposits == nil || deposits.Len() == 0 {
+		return
+	}
+	leaves := make([]libcommon.Hash, 0, deposits.Len())
+	var err error
+	deposits.Range(func(_ int, deposit *cltypes.Deposit, _ int) bool {
+		var leaf [32]byte
+		if leaf, err = deposit.Data.HashSSZ(); err != nil {
+			return false
+		}
+		leaves = append(leaves, leaf)
+		return true
+	})
+	if err != nil {
+		log.Warn("Could not hash deposit data", "err", err)
+		return
+	}
+	if err := f.depositTree.AddDeposits(eth1DepositIndex-uint64(len(leaves)), leaves); err != nil {
+		log.Debug("Could not add deposits to the deposit tree", "err", err)
+	}
+}
+
+// finalizeDepositTree prunes the deposit tree up to the eth1 data of the finalized block (EIP-4881).
+func (f *ForkChoiceStore) finalizeDepositTree(finalizedRoot libcommon.Hash) {
+	if f.depositTree == nil || f.engine == nil {
+		return
+	}
+	eth1Data, ok := f.eth1Datas.Get(finalizedRoot)
+	if !ok {
+		return
+	}
+	if eth1Data.DepositCount <= f.depositTree.Snapshot().DepositCount {
+		return
+	}
+	blockNumber, err := f.engine.BlockNumberByHash(context.Background(), eth1Data.BlockHash)
+	if err != nil {
+		log.Debug("Could not retrieve eth1 block number for deposit tree finalization", "err", err)
+		return
+	}
+	if blockNumber == nil {
+		log.Debug("Eth1 block for deposit tree finalization not found", "hash", eth1Data.BlockHash)
+		return
+	}
+	if err := f.depositTree.Finalize(eth1Data, *blockNumber); err != nil {
+		log.Debug("Could not finalize the deposit tree", "err", err)
+	}
 }
 
 // updateCheckpoints updates the justified and finalized checkpoints if new checkpoints have higher epochs.
@@ -0,0 +1,97 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/rpc"
+)
+
+// DataColumnIdentifiersFromBlocks returns the identifiers of the custody columns of the blocks past the PeerDAS fork which carry blobs.
+func DataColumnIdentifiersFromBlocks(beaconCfg *clparams.BeaconChainConfig, blocks []*cltypes.SignedBeaconBlock, custodyColumns []uint64) (*solid.ListSSZ[*cltypes.DataColumnIdentifier], error) {
+	ids := solid.NewStaticListSSZ[*cltypes.DataColumnIdentifier](int(beaconCfg.MaxRequestDataColumnSidecars), 40)
+	for _, block := range blocks {
+		if !beaconCfg.IsPeerDASActive(block.Block.Slot/beaconCfg.SlotsPerEpoch) || block.Block.Body.BlobKzgCommitments.Len() == 0 {
+			continue
+		}
+		blockRoot, err := block.Block.HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range custodyColumns {
+			ids.Append(cltypes.NewDataColumnIdentifier(blockRoot, column))
+		}
+	}
+	return ids, nil
+}
+
+type PeerAndDataColumnSidecars struct {
+	Peer      string
+	Responses []*cltypes.DataColumnSidecar
+}
+
+// RequestDataColumnsFrantically requests data column sidecars from the network frantically.
+func RequestDataColumnsFrantically(ctx context.Context, r *rpc.BeaconRpcP2P, req *solid.ListSSZ[*cltypes.DataColumnIdentifier]) (*PeerAndDataColumnSidecars, error) {
+	var atomicResp atomic.Value
+
+	atomicResp.Store(&PeerAndDataColumnSidecars{})
+	reqInterval := time.NewTicker(100 * time.Millisecond)
+	defer reqInterval.Stop()
+	timeout := time.NewTimer(requestBlobBatchExpiration)
+	defer timeout.Stop()
+Loop:
+	for {
+		select {
+		case <-reqInterval.C:
+			go func() {
+				if len(atomicResp.Load().(*PeerAndDataColumnSidecars).Responses) > 0 {
+					return
+				}
+				responses, pid, err := r.SendDataColumnSidecarsByRootReq(ctx, req)
+				if err != nil || len(responses) == 0 {
+					return
+				}
+				if len(atomicResp.Load().(*PeerAndDataColumnSidecars).Responses) > 0 {
+					return
+				}
+				atomicResp.Store(&PeerAndDataColumnSidecars{
+					Peer:      pid,
+					Responses: responses,
+				})
+			}()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout.C:
+			log.Debug("RequestDataColumnsFrantically: timeout")
+			return nil, nil
+		default:
+			if len(atomicResp.Load().(*PeerAndDataColumnSidecars).Responses) > 0 {
+				break Loop
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return atomicResp.Load().(*PeerAndDataColumnSidecars), nil
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/erigontech/erigon/cl/monitor"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
 	"github.com/erigontech/erigon/cl/phase1/network/services"
+	"github.com/erigontech/erigon/cl/sentinel/gossip_tracer"
 	"github.com/erigontech/erigon/cl/utils/eth_clock"
 	"github.com/erigontech/erigon/cl/validator/committee_subscription"
 
@@ -58,6 +59,7 @@ type GossipManager struct {
 	// Services for processing messages from the network
 	blockService                 services.BlockService
 	blobService                  services.BlobSidecarsService
+	dataColumnSidecarService     services.DataColumnSidecarService
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService
 	syncContributionService      services.SyncContributionService
 	aggregateAndProofService     services.AggregateAndProofService
@@ -65,6 +67,8 @@ type GossipManager struct {
 	voluntaryExitService         services.VoluntaryExitService
 	blsToExecutionChangeService  services.BLSToExecutionChangeService
 	proposerSlashingService      services.ProposerSlashingService
+
+	gossipTracer *gossip_tracer.GossipTracer // optional
 }
 
 func NewGossipReceiver(
@@ -77,6 +81,7 @@ func NewGossipReceiver(
 	comitteeSub *committee_subscription.CommitteeSubscribeMgmt,
 	blockService services.BlockService,
 	blobService services.BlobSidecarsService,
+	dataColumnSidecarService services.DataColumnSidecarService,
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService,
 	syncContributionService services.SyncContributionService,
 	aggregateAndProofService services.AggregateAndProofService,
@@ -84,6 +89,7 @@ func NewGossipReceiver(
 	voluntaryExitService services.VoluntaryExitService,
 	blsToExecutionChangeService services.BLSToExecutionChangeService,
 	proposerSlashingService services.ProposerSlashingService,
+	gossipTracer *gossip_tracer.GossipTracer,
 ) *GossipManager {
 	return &GossipManager{
 		sentinel:                     s,
@@ -95,6 +101,7 @@ func NewGossipReceiver(
 		committeeSub:                 comitteeSub,
 		blockService:                 blockService,
 		blobService:                  blobService,
+		dataColumnSidecarService:     dataColumnSidecarService,
 		syncCommitteeMessagesService: syncCommitteeMessagesService,
 		syncContributionService:      syncContributionService,
 		aggregateAndProofService:     aggregateAndProofService,
@@ -102,6 +109,7 @@ func NewGossipReceiver(
 		voluntaryExitService:         voluntaryExitService,
 		blsToExecutionChangeService:  blsToExecutionChangeService,
 		proposerSlashingService:      proposerSlashingService,
+		gossipTracer:                 gossipTracer,
 	}
 }
 
@@ -140,8 +148,10 @@ func (g *GossipManager) onRecv(ctx context.Context, data *sentinel.GossipData, l
 	monitor.ObserveGossipTopicSeen(data.Name, len(data.Data))
 
 	if err := g.routeAndProcess(ctx, data); err != nil {
+		g.recordValidation(data.Name, err)
 		return err
 	}
+	g.recordValidation(data.Name, nil)
 	if errors.Is(err, services.ErrIgnore) {
 		return nil
 	}
@@ -155,6 +165,19 @@ func (g *GossipManager) onRecv(ctx context.Context, data *sentinel.GossipData, l
 	return nil
 }
 
+// recordValidation reports the outcome of routeAndProcess to the gossip tracer and metrics.
+func (g *GossipManager) recordValidation(topic string, err error) {
+	result := gossip_tracer.ValidationAccept
+	switch {
+	case errors.Is(err, services.ErrIgnore):
+		result = gossip_tracer.ValidationIgnore
+	case err != nil:
+		result = gossip_tracer.ValidationReject
+	}
+	monitor.ObserveGossipValidation(gossip_tracer.ServiceFromTopic(topic), result.String())
+	g.gossipTracer.RecordValidation(topic, result)
+}
+
 func (g *GossipManager) isReadyToProcessOperations() bool {
 	return g.forkChoice.HighestSeen()+8 >= g.ethClock.GetCurrentSlot()
 }
@@ -244,6 +267,13 @@ func (g *GossipManager) routeAndProcess(ctx context.Context, data *sentinel.Goss
 			defer log.Debug("Received blob sidecar via gossip", "index", *data.SubnetId, "size", datasize.ByteSize(len(blobSideCar.Blob)))
 			// The background checks above are enough for now.
 			return g.blobService.ProcessMessage(ctx, data.SubnetId, blobSideCar)
+		case gossip.IsTopicDataColumnSidecar(data.Name):
+			dataColumnSidecar := cltypes.NewDataColumnSidecar()
+			if err := dataColumnSidecar.DecodeSSZ(data.Data, int(version)); err != nil {
+				return err
+			}
+			defer log.Debug("Received data column sidecar via gossip", "index", dataColumnSidecar.Index, "subnet", *data.SubnetId)
+			return g.dataColumnSidecarService.ProcessMessage(ctx, data.SubnetId, dataColumnSidecar)
 		case gossip.IsTopicSyncCommittee(data.Name):
 			msg := &cltypes.SyncCommitteeMessage{}
 			if err := msg.DecodeSSZ(common.CopyBytes(data.Data), int(version)); err != nil {
@@ -276,10 +306,12 @@ func (g *GossipManager) Start(ctx context.Context) {
 	attestationCh := make(chan *sentinel.GossipData, 1<<20) // large quantity of attestation messages from gossip
 	operationsCh := make(chan *sentinel.GossipData, 1<<16)
 	blobsCh := make(chan *sentinel.GossipData, 1<<16)
+	dataColumnsCh := make(chan *sentinel.GossipData, 1<<16)
 	blocksCh := make(chan *sentinel.GossipData, 1<<10)
 	syncCommitteesCh := make(chan *sentinel.GossipData, 1<<16)
 	defer close(operationsCh)
 	defer close(blobsCh)
+	defer close(dataColumnsCh)
 	defer close(blocksCh)
 	defer close(syncCommitteesCh)
 	defer close(attestationCh)
@@ -308,16 +340,18 @@ func (g *GossipManager) Start(ctx context.Context) {
 	goWorker(operationsCh, 1)
 	goWorker(blocksCh, 1)
 	goWorker(blobsCh, 1)
+	goWorker(dataColumnsCh, 4)
 
 	sendOrDrop := func(ch chan<- *sentinel.GossipData, data *sentinel.GossipData) {
 		// Skip processing the received data if the node is not ready to process operations.
-		if !g.isReadyToProcessOperations() && data.Name != gossip.TopicNameBeaconBlock && !gossip.IsTopicBlobSidecar(data.Name) {
+		if !g.isReadyToProcessOperations() && data.Name != gossip.TopicNameBeaconBlock && !gossip.IsTopicBlobSidecar(data.Name) && !gossip.IsTopicDataColumnSidecar(data.Name) {
 			return
 		}
 		select {
 		case ch <- data:
 		default:
 			//log.Warn("[Beacon Gossip] Dropping message due to full channel", "topic", data.Name)
+			g.gossipTracer.RecordDrop(data.Name)
 		}
 	}
 
@@ -350,6 +384,8 @@ Reconnect:
 				sendOrDrop(blocksCh, data)
 			case gossip.IsTopicBlobSidecar(data.Name):
 				sendOrDrop(blobsCh, data)
+			case gossip.IsTopicDataColumnSidecar(data.Name):
+				sendOrDrop(dataColumnsCh, data)
 			case gossip.IsTopicSyncCommittee(data.Name) || data.Name == gossip.TopicNameSyncCommitteeContributionAndProof:
 				sendOrDrop(syncCommitteesCh, data)
 			case gossip.IsTopicBeaconAttestation(data.Name):
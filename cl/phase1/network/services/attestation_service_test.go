@@ -19,6 +19,7 @@ package services
 import (
 	"context"
 	"log"
+	"math"
 	"testing"
 	"time"
 
@@ -77,7 +78,7 @@ func (t *attestationTestSuite) SetupTest() {
 	t.beaconStateReader = mockState.NewMockBeaconStateReader(t.gomockCtrl)
 	t.committeeSubscibe = mockCommittee.NewMockCommitteeSubscribe(t.gomockCtrl)
 	t.ethClock = eth_clock.NewMockEthereumClock(t.gomockCtrl)
-	t.beaconConfig = &clparams.BeaconChainConfig{SlotsPerEpoch: mockSlotsPerEpoch}
+	t.beaconConfig = &clparams.BeaconChainConfig{SlotsPerEpoch: mockSlotsPerEpoch, ElectraForkEpoch: math.MaxUint64}
 	netConfig := &clparams.NetworkConfig{}
 	emitters := beaconevents.NewEventEmitter()
 	computeSigningRoot = func(obj ssz.HashableSSZ, domain []byte) ([32]byte, error) { return [32]byte{}, nil }
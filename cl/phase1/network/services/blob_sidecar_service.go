@@ -158,13 +158,18 @@ func (b *blobSidecarService) verifyAndStoreBlobSidecar(headState *state.CachingB
 }
 
 func (b *blobSidecarService) verifySidecarsSignature(headState *state.CachingBeaconState, header *cltypes.SignedBeaconBlockHeader) error {
-	parentHeader, ok := b.forkchoiceStore.GetHeader(header.Header.ParentRoot)
+	return verifySidecarHeaderSignature(b.beaconCfg, b.forkchoiceStore, headState, header)
+}
+
+// verifySidecarHeaderSignature checks the proposer signature of the block header carried by blob and data column sidecars.
+func verifySidecarHeaderSignature(beaconCfg *clparams.BeaconChainConfig, forkchoiceStore forkchoice.ForkChoiceStorage, headState *state.CachingBeaconState, header *cltypes.SignedBeaconBlockHeader) error {
+	parentHeader, ok := forkchoiceStore.GetHeader(header.Header.ParentRoot)
 	if !ok {
 		return errors.New("parent header not found")
 	}
-	currentVersion := b.beaconCfg.GetCurrentStateVersion(parentHeader.Slot / b.beaconCfg.SlotsPerEpoch)
-	forkVersion := b.beaconCfg.GetForkVersionByVersion(currentVersion)
-	domain, err := fork.ComputeDomain(b.beaconCfg.DomainBeaconProposer[:], utils.Uint32ToBytes4(forkVersion), headState.GenesisValidatorsRoot())
+	currentVersion := beaconCfg.GetCurrentStateVersion(parentHeader.Slot / beaconCfg.SlotsPerEpoch)
+	forkVersion := beaconCfg.GetForkVersionByVersion(currentVersion)
+	domain, err := fork.ComputeDomain(beaconCfg.DomainBeaconProposer[:], utils.Uint32ToBytes4(forkVersion), headState.GenesisValidatorsRoot())
 	if err != nil {
 		return err
 	}
@@ -180,7 +185,7 @@ func (b *blobSidecarService) verifySidecarsSignature(headState *state.CachingBea
 		return err
 	}
 	if !ok {
-		return errors.New("blob signature validation: signature not valid")
+		return errors.New("sidecar signature validation: signature not valid")
 	}
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/erigontech/erigon/cl/beacon/synced_data"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/phase1/core/state/lru"
@@ -211,7 +212,16 @@ func (b *blockService) processAndStoreBlock(ctx context.Context, block *cltypes.
 	}
 	go b.importBlockOperations(block)
 	if err := b.db.Update(ctx, func(tx kv.RwTx) error {
-		return beacon_indicies.WriteHighestFinalized(tx, b.forkchoiceStore.FinalizedSlot())
+		if err := beacon_indicies.WriteHighestFinalized(tx, b.forkchoiceStore.FinalizedSlot()); err != nil {
+			return err
+		}
+		if update := b.forkchoiceStore.NewestLightClientUpdate(); update != nil {
+			// keep the best update of each period so that light clients can sync across restarts
+			if _, err := lightclient_utils.WriteBestLightClientUpdate(tx, b.beaconCfg, update); err != nil {
+				return err
+			}
+		}
+		return nil
 	}); err != nil {
 		return err
 	}
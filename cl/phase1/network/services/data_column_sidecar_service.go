@@ -0,0 +1,194 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/beacon/synced_data"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/das"
+	"github.com/erigontech/erigon/cl/monitor"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/phase1/forkchoice"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+var ErrDataColumnSubnetMismatch = errors.New("data column sidecar subnet mismatch")
+
+type dataColumnSidecarService struct {
+	forkchoiceStore   forkchoice.ForkChoiceStorage
+	beaconCfg         *clparams.BeaconChainConfig
+	syncedDataManager *synced_data.SyncedDataManager
+	ethClock          eth_clock.EthereumClock
+
+	dataColumnSidecarsScheduledForLaterExecution sync.Map
+}
+
+type dataColumnSidecarJob struct {
+	dataColumnSidecar *cltypes.DataColumnSidecar
+	creationTime      time.Time
+}
+
+// NewDataColumnSidecarService creates a new data column sidecar service
+func NewDataColumnSidecarService(
+	ctx context.Context,
+	beaconCfg *clparams.BeaconChainConfig,
+	forkchoiceStore forkchoice.ForkChoiceStorage,
+	syncedDataManager *synced_data.SyncedDataManager,
+	ethClock eth_clock.EthereumClock,
+) DataColumnSidecarService {
+	d := &dataColumnSidecarService{
+		beaconCfg:         beaconCfg,
+		forkchoiceStore:   forkchoiceStore,
+		syncedDataManager: syncedDataManager,
+		ethClock:          ethClock,
+	}
+	go d.loop(ctx)
+	return d
+}
+
+// ProcessMessage processes a data column sidecar message
+func (d *dataColumnSidecarService) ProcessMessage(ctx context.Context, subnetId *uint64, msg *cltypes.DataColumnSidecar) error {
+	sidecarSlot := msg.SignedBlockHeader.Header.Slot
+	if !d.beaconCfg.IsPeerDASActive(sidecarSlot / d.beaconCfg.SlotsPerEpoch) {
+		return ErrIgnore
+	}
+	// [REJECT] The sidecar is valid as verified by verify_data_column_sidecar(sidecar).
+	if err := das.VerifyDataColumnSidecar(d.beaconCfg, msg); err != nil {
+		return err
+	}
+	// [REJECT] The sidecar is for the correct subnet -- i.e. compute_subnet_for_data_column_sidecar(sidecar.index) == subnet_id.
+	if subnetId != nil && das.ComputeSubnetForDataColumnSidecar(d.beaconCfg, msg.Index) != *subnetId {
+		return ErrDataColumnSubnetMismatch
+	}
+
+	headState := d.syncedDataManager.HeadState()
+	if headState == nil {
+		d.scheduleDataColumnSidecarForLaterExecution(msg)
+		return ErrIgnore
+	}
+
+	// [IGNORE] The sidecar is not from a future slot (with a MAXIMUM_GOSSIP_CLOCK_DISPARITY allowance).
+	currentSlot := d.ethClock.GetCurrentSlot()
+	if currentSlot < sidecarSlot && !d.ethClock.IsSlotCurrentSlotWithMaximumClockDisparity(sidecarSlot) {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar is from a slot greater than the latest finalized slot.
+	if d.forkchoiceStore.FinalizedSlot() >= sidecarSlot {
+		return ErrIgnore
+	}
+
+	blockRoot, err := msg.SignedBlockHeader.Header.HashSSZ()
+	if err != nil {
+		return err
+	}
+	// Do not bother with blocks processed by fork choice already.
+	if _, has := d.forkchoiceStore.GetHeader(blockRoot); has {
+		return ErrIgnore
+	}
+
+	parentHeader, has := d.forkchoiceStore.GetHeader(msg.SignedBlockHeader.Header.ParentRoot)
+	if !has {
+		d.scheduleDataColumnSidecarForLaterExecution(msg)
+		return ErrIgnore
+	}
+	if sidecarSlot <= parentHeader.Slot {
+		return ErrInvalidSidecarSlot
+	}
+
+	return d.verifyAndStoreDataColumnSidecar(headState, msg)
+}
+
+func (d *dataColumnSidecarService) verifyAndStoreDataColumnSidecar(headState *state.CachingBeaconState, msg *cltypes.DataColumnSidecar) error {
+	// [REJECT] The sidecar's kzg_commitments field inclusion proof is valid.
+	if err := das.VerifyDataColumnSidecarInclusionProof(msg); err != nil {
+		return err
+	}
+	start := time.Now()
+	// [REJECT] The sidecar's column data is valid as verified by verify_data_column_sidecar_kzg_proofs(sidecar).
+	if err := das.VerifyDataColumnSidecarKzgProofs(msg); err != nil {
+		return err
+	}
+	// [REJECT] The proposer signature of sidecar.signed_block_header is valid with respect to the block_header.proposer_index pubkey.
+	if err := verifySidecarHeaderSignature(d.beaconCfg, d.forkchoiceStore, headState, msg.SignedBlockHeader); err != nil {
+		return err
+	}
+	monitor.ObserveBlobVerificationTime(start)
+	return d.forkchoiceStore.AddPreverifiedDataColumnSidecar(msg)
+}
+
+func (d *dataColumnSidecarService) scheduleDataColumnSidecarForLaterExecution(dataColumnSidecar *cltypes.DataColumnSidecar) {
+	job := &dataColumnSidecarJob{
+		dataColumnSidecar: dataColumnSidecar,
+		creationTime:      time.Now(),
+	}
+	blockRoot, err := dataColumnSidecar.SignedBlockHeader.Header.HashSSZ()
+	if err != nil {
+		return
+	}
+	d.dataColumnSidecarsScheduledForLaterExecution.Store(*cltypes.NewDataColumnIdentifier(blockRoot, dataColumnSidecar.Index), job)
+}
+
+// loop is the main loop of the data column sidecar service
+func (d *dataColumnSidecarService) loop(ctx context.Context) {
+	ticker := time.NewTicker(blobJobsIntervalTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		headState := d.syncedDataManager.HeadState()
+		if headState == nil {
+			continue
+		}
+		d.dataColumnSidecarsScheduledForLaterExecution.Range(func(key, value any) bool {
+			job := value.(*dataColumnSidecarJob)
+			// check if it has expired
+			if time.Since(job.creationTime) > blobJobExpiry {
+				d.dataColumnSidecarsScheduledForLaterExecution.Delete(key)
+				return true
+			}
+			blockRoot, err := job.dataColumnSidecar.SignedBlockHeader.Header.HashSSZ()
+			if err != nil {
+				log.Debug("data column sidecar verification failed", "err", err)
+				return true
+			}
+			if _, has := d.forkchoiceStore.GetHeader(blockRoot); has {
+				d.dataColumnSidecarsScheduledForLaterExecution.Delete(key)
+				return true
+			}
+			if _, has := d.forkchoiceStore.GetHeader(job.dataColumnSidecar.SignedBlockHeader.Header.ParentRoot); !has {
+				return true
+			}
+			if err := d.verifyAndStoreDataColumnSidecar(headState, job.dataColumnSidecar); err != nil {
+				log.Trace("data column sidecar verification failed", "err", err,
+					"slot", job.dataColumnSidecar.SignedBlockHeader.Header.Slot, "index", job.dataColumnSidecar.Index)
+				return true
+			}
+			d.dataColumnSidecarsScheduledForLaterExecution.Delete(key)
+			return true
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/erigontech/erigon/cl/beacon/synced_data"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/das"
+	"github.com/erigontech/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+func setupDataColumnSidecarService(t *testing.T, ctrl *gomock.Controller, peerDASEpoch uint64) (DataColumnSidecarService, *clparams.BeaconChainConfig) {
+	ctx, cn := context.WithTimeout(context.Background(), 1)
+	cn()
+	cfg := clparams.MainnetBeaconConfig
+	cfg.Eip7594ForkEpoch = peerDASEpoch
+	syncedDataManager := synced_data.NewSyncedDataManager(true, &cfg)
+	ethClock := eth_clock.NewMockEthereumClock(ctrl)
+	forkchoiceMock := mock_services.NewForkChoiceStorageMock(t)
+	return NewDataColumnSidecarService(ctx, &cfg, forkchoiceMock, syncedDataManager, ethClock), &cfg
+}
+
+func TestDataColumnSidecarServiceBeforeFork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := setupDataColumnSidecarService(t, ctrl, 10)
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.SignedBlockHeader.Header.Slot = 1
+
+	require.ErrorIs(t, service.ProcessMessage(context.Background(), nil, sidecar), ErrIgnore)
+}
+
+func TestDataColumnSidecarServiceInvalidIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, cfg := setupDataColumnSidecarService(t, ctrl, 0)
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = cfg.NumberOfColumns
+
+	require.ErrorIs(t, service.ProcessMessage(context.Background(), nil, sidecar), das.ErrInvalidColumnIndex)
+}
+
+func TestDataColumnSidecarServiceInvalidSubnet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, cfg := setupDataColumnSidecarService(t, ctrl, 0)
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = 3
+	sidecar.Column.Append(&cltypes.Cell{})
+	sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{})
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{})
+	subnet := (das.ComputeSubnetForDataColumnSidecar(cfg, sidecar.Index) + 1) % cfg.DataColumnSidecarSubnetCount
+
+	require.ErrorIs(t, service.ProcessMessage(context.Background(), &subnet, sidecar), ErrDataColumnSubnetMismatch)
+}
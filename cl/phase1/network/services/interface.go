@@ -34,6 +34,9 @@ type BlockService Service[*cltypes.SignedBeaconBlock]
 //go:generate mockgen -typed=true -destination=./mock_services/blob_sidecars_service_mock.go -package=mock_services . BlobSidecarsService
 type BlobSidecarsService Service[*cltypes.BlobSidecar]
 
+//go:generate mockgen -typed=true -destination=./mock_services/data_column_sidecar_service_mock.go -package=mock_services . DataColumnSidecarService
+type DataColumnSidecarService Service[*cltypes.DataColumnSidecar]
+
 //go:generate mockgen -typed=true -destination=./mock_services/sync_committee_messages_service_mock.go -package=mock_services . SyncCommitteeMessagesService
 type SyncCommitteeMessagesService Service[*cltypes.SyncCommitteeMessage]
 
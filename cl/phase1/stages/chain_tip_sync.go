@@ -70,8 +70,13 @@ func fetchBlocksFromReqResp(ctx context.Context, cfg *Cfg, from uint64, count ui
 		return nil, nil
 	}
 
+	// Past the PeerDAS fork, we fetch our custody columns instead of the blobs.
+	if err := downloadAndProcessDataColumns(ctx, cfg, blocks); err != nil {
+		log.Debug("[Caplin] Could not fetch data columns", "err", err)
+	}
+
 	// Generate blob identifiers from the retrieved blocks
-	ids, err := network2.BlobsIdentifiersFromBlocks(blocks)
+	ids, err := network2.BlobsIdentifiersFromBlocks(blocksBeforePeerDAS(cfg.beaconCfg, blocks))
 	if err != nil {
 		return nil, err
 	}
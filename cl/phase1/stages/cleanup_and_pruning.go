@@ -25,5 +25,8 @@ func cleanupAndPruning(ctx context.Context, logger log.Logger, cfg *Cfg, args Ar
 	if err := tx.Commit(); err != nil {
 		return err
 	}
-	return cfg.blobStore.Prune()
+	if err := cfg.blobStore.Prune(); err != nil {
+		return err
+	}
+	return cfg.dataColumnStore.Prune()
 }
@@ -28,6 +28,8 @@ import (
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/clstages"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/monitor"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
@@ -62,8 +64,12 @@ type Cfg struct {
 	blockCollector          block_collector.BlockCollector
 	sn                      *freezeblocks.CaplinSnapshots
 	blobStore               blob_storage.BlobStorage
+	dataColumnStore         blob_storage.DataColumnStorage
+	custodyColumns          []uint64
 	attestationDataProducer attestation_producer.AttestationDataProducer
 	validatorMonitor        monitor.ValidatorMonitor
+	// weakSubjectivityCheckpoint is optional, the synced chain must go through it
+	weakSubjectivityCheckpoint *solid.Checkpoint
 
 	hasDownloaded, backfilling, blobBackfilling bool
 }
@@ -96,8 +102,11 @@ func ClStagesCfg(
 	syncedData *synced_data.SyncedDataManager,
 	emitters *beaconevents.EventEmitter,
 	blobStore blob_storage.BlobStorage,
+	dataColumnStore blob_storage.DataColumnStorage,
+	custodyColumns []uint64,
 	attestationDataProducer attestation_producer.AttestationDataProducer,
 	validatorMonitor monitor.ValidatorMonitor,
+	weakSubjectivityCheckpoint *solid.Checkpoint,
 ) *Cfg {
 	return &Cfg{
 		rpc:                     rpc,
@@ -116,10 +125,14 @@ func ClStagesCfg(
 		syncedData:              syncedData,
 		emitter:                 emitters,
 		blobStore:               blobStore,
+		dataColumnStore:         dataColumnStore,
+		custodyColumns:          custodyColumns,
 		blockCollector:          block_collector.NewBlockCollector(log.Root(), executionClient, beaconCfg, syncBackLoopLimit, dirs.Tmp),
 		blobBackfilling:         blobBackfilling,
 		attestationDataProducer: attestationDataProducer,
 		validatorMonitor:        validatorMonitor,
+
+		weakSubjectivityCheckpoint: weakSubjectivityCheckpoint,
 	}
 }
 
@@ -168,7 +181,14 @@ func processBlock(ctx context.Context, cfg *Cfg, db kv.RwDB, block *cltypes.Sign
 		return nil
 	}
 
-	return cfg.forkChoice.OnBlock(ctx, block, newPayload, fullValidation, checkDataAvaiability)
+	if err := cfg.forkChoice.OnBlock(ctx, block, newPayload, fullValidation, checkDataAvaiability); err != nil {
+		return err
+	}
+	if !fullValidation {
+		// light client updates are only produced for fully validated blocks
+		return nil
+	}
+	return lightclient_utils.PersistLightClientUpdate(ctx, db, cfg.beaconCfg, cfg.forkChoice.NewestLightClientUpdate())
 }
 
 /*
@@ -239,7 +239,8 @@ func emitNextPaylodAttributesEvent(cfg *Cfg, headSlot uint64, headRoot common.Ha
 		return err
 	}
 	withdrawals := []*types.Withdrawal{}
-	for _, w := range state.ExpectedWithdrawals(s, epoch) {
+	expectedWithdrawals, _ := state.ExpectedWithdrawals(s, epoch)
+	for _, w := range expectedWithdrawals {
 		withdrawals = append(withdrawals, &types.Withdrawal{
 			Amount:    w.Amount,
 			Index:     w.Index,
@@ -291,6 +292,16 @@ func saveHeadStateOnDiskIfNeeded(cfg *Cfg, headState *state.CachingBeaconState)
 		if err != nil {
 			return fmt.Errorf("failed to write head state to disk: %w", err)
 		}
+		// Write the deposit tree alongside, so that it can be restored together with the head state
+		if depositTree := cfg.forkChoice.DepositTree(); depositTree != nil {
+			encodedTree, err := depositTree.MarshalBinary()
+			if err != nil {
+				return fmt.Errorf("failed to encode deposit tree: %w", err)
+			}
+			if err := os.WriteFile(fmt.Sprintf("%s/%s", cfg.dirs.CaplinLatest, clparams.DepositTreeFileName), encodedTree, 0644); err != nil {
+				return fmt.Errorf("failed to write deposit tree to disk: %w", err)
+			}
+		}
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package stages
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync/atomic"
@@ -15,6 +16,7 @@ import (
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/phase1/core/checkpoint_sync"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	network2 "github.com/erigontech/erigon/cl/phase1/network"
 )
@@ -81,6 +83,59 @@ func downloadAndProcessEip4844DA(ctx context.Context, logger log.Logger, cfg *Cf
 	return highestProcessed - 1, err
 }
 
+// blocksBeforePeerDAS filters out the blocks whose data availability is checked through data columns rather than blobs.
+func blocksBeforePeerDAS(beaconCfg *clparams.BeaconChainConfig, blocks []*cltypes.SignedBeaconBlock) []*cltypes.SignedBeaconBlock {
+	filtered := make([]*cltypes.SignedBeaconBlock, 0, len(blocks))
+	for _, block := range blocks {
+		if beaconCfg.IsPeerDASActive(block.Block.Slot / beaconCfg.SlotsPerEpoch) {
+			continue
+		}
+		filtered = append(filtered, block)
+	}
+	return filtered
+}
+
+// downloadAndProcessDataColumns downloads the custody columns of the blocks past the PeerDAS fork and stores them.
+// It fails if any of the custody columns could not be retrieved, so that the batch is requested again.
+func downloadAndProcessDataColumns(ctx context.Context, cfg *Cfg, blocks []*cltypes.SignedBeaconBlock) error {
+	ids, err := network2.DataColumnIdentifiersFromBlocks(cfg.beaconCfg, blocks, cfg.custodyColumns)
+	if err != nil {
+		return fmt.Errorf("failed to get data column identifiers: %w", err)
+	}
+	if ids.Len() == 0 {
+		return nil
+	}
+	columns, err := network2.RequestDataColumnsFrantically(ctx, cfg.rpc, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get data columns: %w", err)
+	}
+	if columns == nil {
+		return errors.New("timed out requesting data columns")
+	}
+	inserted, err := blob_storage.VerifyAgainstIdentifiersAndInsertIntoTheDataColumnStore(ctx, cfg.dataColumnStore, cfg.beaconCfg, ids, columns.Responses)
+	if err != nil {
+		cfg.rpc.BanPeer(columns.Peer)
+		return fmt.Errorf("failed to verify data columns: %w", err)
+	}
+	if inserted != uint64(ids.Len()) {
+		return fmt.Errorf("missing data columns: got %d, expected %d", inserted, ids.Len())
+	}
+	return nil
+}
+
+// verifyWeakSubjectivityCheckpoint rejects the blocks which conflict with the weak subjectivity checkpoint, if any.
+func verifyWeakSubjectivityCheckpoint(cfg *Cfg, block *cltypes.SignedBeaconBlock, blockRoot common.Hash) error {
+	if cfg.weakSubjectivityCheckpoint == nil {
+		return nil
+	}
+	parentHeader, ok := cfg.forkChoice.GetHeader(block.Block.ParentRoot)
+	if !ok {
+		// unknown parent, the block is rejected by the fork choice anyway.
+		return nil
+	}
+	return checkpoint_sync.VerifyBlockAgainstWeakSubjectivityCheckpoint(cfg.beaconCfg, *cfg.weakSubjectivityCheckpoint, block.Block, blockRoot, parentHeader.Slot)
+}
+
 // processDownloadedBlockBatches processes a batch of downloaded blocks.
 // It takes the highest block processed, a flag to determine if insertion is needed, and a list of signed beacon blocks as input.
 // It returns the new highest block processed and an error if any.
@@ -115,6 +170,10 @@ func processDownloadedBlockBatches(ctx context.Context, cfg *Cfg, highestBlockPr
 			return
 		}
 
+		if err = verifyWeakSubjectivityCheckpoint(cfg, block, blockRoot); err != nil {
+			return
+		}
+
 		// Process the block
 		if err = processBlock(ctx, cfg, cfg.indiciesDB, block, false, true, false); err != nil {
 			// Return an error if block processing fails
@@ -184,6 +243,13 @@ func forwardSync(ctx context.Context, logger log.Logger, cfg *Cfg, args Args) er
 			return initialHighestSlotProcessed, err
 		}
 
+		// Past the PeerDAS fork, data availability is given by the custody columns.
+		if err := downloadAndProcessDataColumns(ctx, cfg, blocks); err != nil {
+			logger.Warn("[Caplin] Failed to process data columns", "err", err)
+			return initialHighestSlotProcessed, err
+		}
+		blocks = blocksBeforePeerDAS(cfg.beaconCfg, blocks)
+
 		// Exit if we are pre-EIP-4844
 		if !shouldProcessBlobs(blocks) {
 			currentSlot.Store(highestSlotProcessed)
@@ -144,8 +144,17 @@ func (b *BeaconRpcP2P) sendBlocksRequest(ctx context.Context, topic string, reqD
 }
 
 func (b *BeaconRpcP2P) sendBlobsSidecar(ctx context.Context, topic string, reqData []byte, count uint64) ([]*cltypes.BlobSidecar, string, error) {
+	return sendSidecarsRequest(ctx, b, topic, reqData, count, func() *cltypes.BlobSidecar { return &cltypes.BlobSidecar{} })
+}
+
+func (b *BeaconRpcP2P) sendDataColumnSidecars(ctx context.Context, topic string, reqData []byte, count uint64) ([]*cltypes.DataColumnSidecar, string, error) {
+	return sendSidecarsRequest(ctx, b, topic, reqData, count, cltypes.NewDataColumnSidecar)
+}
+
+// sendSidecarsRequest sends a sidecars request and decodes up to count response chunks with newSidecar.
+func sendSidecarsRequest[T interface{ DecodeSSZ([]byte, int) error }](ctx context.Context, b *BeaconRpcP2P, topic string, reqData []byte, count uint64, newSidecar func() T) ([]T, string, error) {
 	// Prepare output slice.
-	responsePacket := []*cltypes.BlobSidecar{}
+	responsePacket := []T{}
 
 	ctx, cn := context.WithTimeout(ctx, time.Second*2)
 	defer cn()
@@ -204,7 +213,7 @@ func (b *BeaconRpcP2P) sendBlobsSidecar(ctx context.Context, topic string, reqDa
 		if err != nil {
 			return nil, message.Peer.Pid, err
 		}
-		responseChunk := &cltypes.BlobSidecar{}
+		responseChunk := newSidecar()
 
 		if err = responseChunk.DecodeSSZ(raw, int(version)); err != nil {
 			return nil, message.Peer.Pid, err
@@ -242,6 +251,17 @@ func (b *BeaconRpcP2P) SendBlobsSidecarByRangerReq(ctx context.Context, start, c
 	return b.sendBlobsSidecar(ctx, communication.BlobSidecarByRangeProtocolV1, data, count*b.beaconConfig.MaxBlobsPerBlock)
 }
 
+// SendDataColumnSidecarsByRootReq retrieves data column sidecars by block root and column index.
+func (b *BeaconRpcP2P) SendDataColumnSidecarsByRootReq(ctx context.Context, req *solid.ListSSZ[*cltypes.DataColumnIdentifier]) ([]*cltypes.DataColumnSidecar, string, error) {
+	var buffer buffer.Buffer
+	if err := ssz_snappy.EncodeAndWrite(&buffer, req); err != nil {
+		return nil, "", err
+	}
+
+	data := libcommon.CopyBytes(buffer.Bytes())
+	return b.sendDataColumnSidecars(ctx, communication.DataColumnSidecarsByRootProtocolV1, data, uint64(req.Len()))
+}
+
 // SendBeaconBlocksByRangeReq retrieves blocks range from beacon chain.
 func (b *BeaconRpcP2P) SendBeaconBlocksByRangeReq(ctx context.Context, start, count uint64) ([]*cltypes.SignedBeaconBlock, string, error) {
 	req := &cltypes.BeaconBlocksByRangeRequest{
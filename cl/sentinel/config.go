@@ -30,6 +30,7 @@ import (
 
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/sentinel/gossip_tracer"
 )
 
 type SentinelConfig struct {
@@ -52,6 +53,11 @@ type SentinelConfig struct {
 	SubscribeAllTopics bool // Capture all topics
 	ActiveIndicies     uint64
 	MaxPeerCount       uint64
+
+	NodeKey           *ecdsa.PrivateKey // Optional, a random one is generated if missing. The custody columns are derived from it.
+	CustodyGroupCount uint64            // PeerDAS custody groups advertised in the ENR.
+
+	GossipTracer *gossip_tracer.GossipTracer // Optional, collects gossip events and peer scores for introspection.
 }
 
 func convertToCryptoPrivkey(privkey *ecdsa.PrivateKey) (crypto.PrivKey, error) {
@@ -19,6 +19,7 @@ package sentinel
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
@@ -36,6 +37,9 @@ import (
 const (
 	peerSubnetTarget                 = 4
 	goRoutinesOpeningPeerConnections = 4
+
+	// custodyGroupCountEnrKey is the ENR key of the PeerDAS custody group count.
+	custodyGroupCountEnrKey = "cgc"
 )
 
 // ConnectWithPeer is used to attempt to connect and add the peer to our pool
@@ -173,6 +177,9 @@ func (s *Sentinel) setupENR(
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.Eth2key, forkId))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, bitfield.NewBitvector64().Bytes()))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.SyncCommsSubnetKey, bitfield.Bitvector4{byte(0x00)}.Bytes()))
+	if s.cfg.BeaconConfig.Eip7594ForkEpoch != math.MaxUint64 {
+		node.Set(enr.WithEntry(custodyGroupCountEnrKey, s.cfg.CustodyGroupCount))
+	}
 	return node, nil
 }
 
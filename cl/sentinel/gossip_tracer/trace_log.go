@@ -0,0 +1,84 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package gossip_tracer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// traceEvent is a single line of the structured gossip trace log.
+type traceEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Peer     string    `json:"peer,omitempty"`
+	Topic    string    `json:"topic,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Size     int       `json:"size,omitempty"`
+	Messages int       `json:"messages,omitempty"`
+	Service  string    `json:"service,omitempty"`
+}
+
+// traceLog appends gossip events as JSON lines to a file.
+type traceLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func openTraceLog(path string) (*traceLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &traceLog{f: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (l *traceLog) write(ev traceEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.enc == nil {
+		return
+	}
+	_ = l.enc.Encode(ev) // write errors are reported on flush
+}
+
+func (l *traceLog) flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.w == nil {
+		return nil
+	}
+	return l.w.Flush()
+}
+
+func (l *traceLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	l.w.Flush()
+	err := l.f.Close()
+	l.f, l.w, l.enc = nil, nil, nil
+	return err
+}
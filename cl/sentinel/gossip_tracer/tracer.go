@@ -0,0 +1,452 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package gossip_tracer collects gossipsub events, peer score snapshots and Caplin validation
+// results so that operators can see why peers are scored down and which topics drop messages.
+package gossip_tracer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/erigontech/erigon-lib/diagnostics"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+// DefaultRateWindow is the period over which per-topic message rates are computed.
+const DefaultRateWindow = 12 * time.Second
+
+type topicCounters struct {
+	delivered     uint64
+	duplicate     uint64
+	rejected      uint64
+	undeliverable uint64
+	rejectReasons map[string]uint64
+	meshPeers     map[peer.ID]struct{}
+
+	// rate computation
+	lastDelivered      uint64
+	lastReceived       uint64
+	deliveredPerSecond float64
+	receivedPerSecond  float64
+}
+
+func (c *topicCounters) received() uint64 {
+	return c.delivered + c.duplicate + c.rejected
+}
+
+// GossipTracer implements pubsub.RawTracer and keeps the introspection data of the sentinel gossip.
+type GossipTracer struct {
+	mu          sync.RWMutex
+	topics      map[string]*topicCounters
+	validation  map[string]*ValidationStatistics
+	peerScores  map[peer.ID]*pubsub.PeerScoreSnapshot
+	throttled   map[peer.ID]uint64
+	lastRatesAt time.Time
+
+	rateWindow time.Duration
+	trace      *traceLog // nil if the trace log is disabled
+	logger     log.Logger
+}
+
+var _ pubsub.RawTracer = (*GossipTracer)(nil)
+
+// NewGossipTracer creates a tracer, if traceFile is not empty every gossip event is appended to it as a JSON line.
+func NewGossipTracer(traceFile string, logger log.Logger) (*GossipTracer, error) {
+	t := &GossipTracer{
+		topics:      make(map[string]*topicCounters),
+		validation:  make(map[string]*ValidationStatistics),
+		peerScores:  make(map[peer.ID]*pubsub.PeerScoreSnapshot),
+		throttled:   make(map[peer.ID]uint64),
+		lastRatesAt: time.Now(),
+		rateWindow:  DefaultRateWindow,
+		logger:      logger,
+	}
+	if traceFile != "" {
+		trace, err := openTraceLog(traceFile)
+		if err != nil {
+			return nil, err
+		}
+		t.trace = trace
+	}
+	return t, nil
+}
+
+// Start periodically computes the topic rates, flushes the trace log and publishes the statistics to diagnostics.
+func (t *GossipTracer) Start(ctx context.Context) {
+	ticker := time.NewTicker(t.rateWindow)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if t.trace != nil {
+				if err := t.trace.close(); err != nil {
+					t.logger.Warn("[GossipTracer] failed to close trace log", "err", err)
+				}
+			}
+			return
+		case <-ticker.C:
+			t.updateRates(time.Now())
+			if t.trace != nil {
+				if err := t.trace.flush(); err != nil {
+					t.logger.Warn("[GossipTracer] failed to flush trace log", "err", err)
+				}
+			}
+			t.sendDiagnostics()
+		}
+	}
+}
+
+func (t *GossipTracer) updateRates(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	elapsed := now.Sub(t.lastRatesAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	for _, c := range t.topics {
+		received := c.received()
+		c.deliveredPerSecond = float64(c.delivered-c.lastDelivered) / elapsed
+		c.receivedPerSecond = float64(received-c.lastReceived) / elapsed
+		c.lastDelivered, c.lastReceived = c.delivered, received
+	}
+	t.lastRatesAt = now
+}
+
+// InspectPeerScores is meant to be passed to pubsub.WithPeerScoreInspect.
+func (t *GossipTracer) InspectPeerScores(scores map[peer.ID]*pubsub.PeerScoreSnapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.peerScores = scores
+	// forget the throttling of peers which are no longer scored.
+	for pid := range t.throttled {
+		if _, ok := scores[pid]; !ok {
+			delete(t.throttled, pid)
+		}
+	}
+}
+
+// RecordValidation records the outcome of the Caplin validation of a message received on topic.
+// It is a no-op on a nil tracer.
+func (t *GossipTracer) RecordValidation(topic string, result ValidationResult) {
+	if t == nil {
+		return
+	}
+	service := ServiceFromTopic(topic)
+	t.mu.Lock()
+	stats := t.validationStats(service)
+	switch result {
+	case ValidationAccept:
+		stats.Accepted++
+	case ValidationIgnore:
+		stats.Ignored++
+	case ValidationReject:
+		stats.Rejected++
+	}
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "validation", Topic: topic, Service: service, Reason: result.String()})
+}
+
+// RecordDrop records a message received on topic which was dropped because the service queue was full.
+// It is a no-op on a nil tracer.
+func (t *GossipTracer) RecordDrop(topic string) {
+	if t == nil {
+		return
+	}
+	service := ServiceFromTopic(topic)
+	t.mu.Lock()
+	t.validationStats(service).Dropped++
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "drop", Topic: topic, Service: service})
+}
+
+func (t *GossipTracer) validationStats(service string) *ValidationStatistics {
+	stats, ok := t.validation[service]
+	if !ok {
+		stats = &ValidationStatistics{Service: service}
+		t.validation[service] = stats
+	}
+	return stats
+}
+
+// PeerScores returns the score breakdown of every scored peer, lowest score first.
+func (t *GossipTracer) PeerScores() []PeerScore {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]PeerScore, 0, len(t.peerScores))
+	for pid, snapshot := range t.peerScores {
+		score := PeerScore{
+			PeerID:             pid.String(),
+			Score:              snapshot.Score,
+			AppSpecificScore:   snapshot.AppSpecificScore,
+			IPColocationFactor: snapshot.IPColocationFactor,
+			BehaviourPenalty:   snapshot.BehaviourPenalty,
+			Throttled:          t.throttled[pid],
+			Topics:             make(map[string]TopicScore, len(snapshot.Topics)),
+		}
+		for topic, ts := range snapshot.Topics {
+			score.Topics[topicName(topic)] = TopicScore{
+				TimeInMeshSeconds:        ts.TimeInMesh.Seconds(),
+				FirstMessageDeliveries:   ts.FirstMessageDeliveries,
+				MeshMessageDeliveries:    ts.MeshMessageDeliveries,
+				InvalidMessageDeliveries: ts.InvalidMessageDeliveries,
+			}
+		}
+		out = append(out, score)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score == out[j].Score {
+			return out[i].PeerID < out[j].PeerID
+		}
+		return out[i].Score < out[j].Score
+	})
+	return out
+}
+
+// Topics returns the message counters and rates of every topic, sorted by name.
+func (t *GossipTracer) Topics() []TopicStatistics {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]TopicStatistics, 0, len(t.topics))
+	for topic, c := range t.topics {
+		reasons := make(map[string]uint64, len(c.rejectReasons))
+		for reason, count := range c.rejectReasons {
+			reasons[reason] = count
+		}
+		out = append(out, TopicStatistics{
+			Topic:              topic,
+			MeshPeers:          len(c.meshPeers),
+			Delivered:          c.delivered,
+			Duplicate:          c.duplicate,
+			Rejected:           c.rejected,
+			Undeliverable:      c.undeliverable,
+			RejectReasons:      reasons,
+			DeliveredPerSecond: c.deliveredPerSecond,
+			ReceivedPerSecond:  c.receivedPerSecond,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Topic < out[j].Topic })
+	return out
+}
+
+// Validation returns the validation outcomes of every gossip service, sorted by name.
+func (t *GossipTracer) Validation() []ValidationStatistics {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]ValidationStatistics, 0, len(t.validation))
+	for _, stats := range t.validation {
+		out = append(out, *stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Service < out[j].Service })
+	return out
+}
+
+func (t *GossipTracer) sendDiagnostics() {
+	if !diagnostics.TypeOf(diagnostics.GossipStatisticsUpdate{}).Enabled() {
+		return
+	}
+	update := diagnostics.GossipStatisticsUpdate{}
+	for _, p := range t.PeerScores() {
+		topics := make(map[string]diagnostics.GossipTopicScore, len(p.Topics))
+		for topic, ts := range p.Topics {
+			topics[topic] = diagnostics.GossipTopicScore{
+				TimeInMeshSeconds:        ts.TimeInMeshSeconds,
+				FirstMessageDeliveries:   ts.FirstMessageDeliveries,
+				MeshMessageDeliveries:    ts.MeshMessageDeliveries,
+				InvalidMessageDeliveries: ts.InvalidMessageDeliveries,
+			}
+		}
+		update.Peers = append(update.Peers, diagnostics.GossipPeerScore{
+			PeerID:             p.PeerID,
+			Score:              p.Score,
+			AppSpecificScore:   p.AppSpecificScore,
+			IPColocationFactor: p.IPColocationFactor,
+			BehaviourPenalty:   p.BehaviourPenalty,
+			Throttled:          p.Throttled,
+			Topics:             topics,
+		})
+	}
+	for _, topic := range t.Topics() {
+		update.Topics = append(update.Topics, diagnostics.GossipTopicStatistics{
+			Topic:              topic.Topic,
+			MeshPeers:          topic.MeshPeers,
+			Delivered:          topic.Delivered,
+			Duplicate:          topic.Duplicate,
+			Rejected:           topic.Rejected,
+			Undeliverable:      topic.Undeliverable,
+			RejectReasons:      topic.RejectReasons,
+			DeliveredPerSecond: topic.DeliveredPerSecond,
+			ReceivedPerSecond:  topic.ReceivedPerSecond,
+		})
+	}
+	for _, stats := range t.Validation() {
+		update.Validation = append(update.Validation, diagnostics.GossipValidationStatistics{
+			Service:  stats.Service,
+			Accepted: stats.Accepted,
+			Ignored:  stats.Ignored,
+			Rejected: stats.Rejected,
+			Dropped:  stats.Dropped,
+		})
+	}
+	diagnostics.Send(update)
+}
+
+// topicName turns /eth2/<fork_digest>/<name>/ssz_snappy into <name>.
+func topicName(topic string) string {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 4 {
+		return topic
+	}
+	return parts[3]
+}
+
+func (t *GossipTracer) topic(topic string) *topicCounters {
+	name := topicName(topic)
+	c, ok := t.topics[name]
+	if !ok {
+		c = &topicCounters{
+			rejectReasons: make(map[string]uint64),
+			meshPeers:     make(map[peer.ID]struct{}),
+		}
+		t.topics[name] = c
+	}
+	return c
+}
+
+func (t *GossipTracer) traceEvent(ev traceEvent) {
+	if t.trace == nil {
+		return
+	}
+	ev.Time = time.Now()
+	t.trace.write(ev)
+}
+
+// pubsub.RawTracer implementation
+
+func (t *GossipTracer) AddPeer(p peer.ID, proto protocol.ID) {
+	t.traceEvent(traceEvent{Event: "add_peer", Peer: p.String(), Reason: string(proto)})
+}
+
+func (t *GossipTracer) RemovePeer(p peer.ID) {
+	t.mu.Lock()
+	for _, c := range t.topics {
+		delete(c.meshPeers, p)
+	}
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "remove_peer", Peer: p.String()})
+}
+
+func (t *GossipTracer) Join(topic string) {
+	t.mu.Lock()
+	t.topic(topic)
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "join", Topic: topicName(topic)})
+}
+
+func (t *GossipTracer) Leave(topic string) {
+	t.mu.Lock()
+	delete(t.topics, topicName(topic))
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "leave", Topic: topicName(topic)})
+}
+
+func (t *GossipTracer) Graft(p peer.ID, topic string) {
+	t.mu.Lock()
+	t.topic(topic).meshPeers[p] = struct{}{}
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "graft", Peer: p.String(), Topic: topicName(topic)})
+}
+
+func (t *GossipTracer) Prune(p peer.ID, topic string) {
+	t.mu.Lock()
+	delete(t.topic(topic).meshPeers, p)
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "prune", Peer: p.String(), Topic: topicName(topic)})
+}
+
+func (t *GossipTracer) ValidateMessage(msg *pubsub.Message) {}
+
+func (t *GossipTracer) DeliverMessage(msg *pubsub.Message) {
+	t.mu.Lock()
+	t.topic(msg.GetTopic()).delivered++
+	t.mu.Unlock()
+	t.traceMessage("deliver", msg, "")
+}
+
+func (t *GossipTracer) RejectMessage(msg *pubsub.Message, reason string) {
+	t.mu.Lock()
+	c := t.topic(msg.GetTopic())
+	c.rejected++
+	c.rejectReasons[reason]++
+	t.mu.Unlock()
+	t.traceMessage("reject", msg, reason)
+}
+
+func (t *GossipTracer) DuplicateMessage(msg *pubsub.Message) {
+	t.mu.Lock()
+	t.topic(msg.GetTopic()).duplicate++
+	t.mu.Unlock()
+	t.traceMessage("duplicate", msg, "")
+}
+
+func (t *GossipTracer) UndeliverableMessage(msg *pubsub.Message) {
+	t.mu.Lock()
+	t.topic(msg.GetTopic()).undeliverable++
+	t.mu.Unlock()
+	t.traceMessage("undeliverable", msg, "")
+}
+
+func (t *GossipTracer) ThrottlePeer(p peer.ID) {
+	t.mu.Lock()
+	t.throttled[p]++
+	t.mu.Unlock()
+	t.traceEvent(traceEvent{Event: "throttle", Peer: p.String()})
+}
+
+func (t *GossipTracer) RecvRPC(rpc *pubsub.RPC) {}
+
+func (t *GossipTracer) SendRPC(rpc *pubsub.RPC, p peer.ID) {}
+
+func (t *GossipTracer) DropRPC(rpc *pubsub.RPC, p peer.ID) {
+	t.traceEvent(traceEvent{Event: "drop_rpc", Peer: p.String(), Messages: rpcMessagesCount(rpc)})
+}
+
+func (t *GossipTracer) traceMessage(event string, msg *pubsub.Message, reason string) {
+	if t.trace == nil {
+		return
+	}
+	t.traceEvent(traceEvent{
+		Event:  event,
+		Peer:   msg.ReceivedFrom.String(),
+		Topic:  topicName(msg.GetTopic()),
+		Reason: reason,
+		Size:   len(msg.GetData()),
+	})
+}
+
+func rpcMessagesCount(rpc *pubsub.RPC) int {
+	if rpc == nil {
+		return 0
+	}
+	return len(rpc.GetPublish())
+}
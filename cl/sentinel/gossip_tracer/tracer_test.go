@@ -0,0 +1,166 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package gossip_tracer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+const testTopic = "/eth2/d31f6191/beacon_attestation_3/ssz_snappy"
+
+func testMessage(topic string, from peer.ID) *pubsub.Message {
+	return &pubsub.Message{
+		Message:      &pubsubpb.Message{Topic: &topic, Data: []byte{1, 2, 3}},
+		ReceivedFrom: from,
+	}
+}
+
+func TestServiceFromTopic(t *testing.T) {
+	require.Equal(t, "beacon_attestation", ServiceFromTopic("beacon_attestation_12"))
+	require.Equal(t, "blob_sidecar", ServiceFromTopic("blob_sidecar_0"))
+	require.Equal(t, "beacon_block", ServiceFromTopic("beacon_block"))
+	require.Equal(t, "sync_committee_contribution_and_proof", ServiceFromTopic("sync_committee_contribution_and_proof"))
+}
+
+func TestGossipTracerTopics(t *testing.T) {
+	tracer, err := NewGossipTracer("", log.New())
+	require.NoError(t, err)
+	p := peer.ID("peer1")
+
+	tracer.Join(testTopic)
+	tracer.Graft(p, testTopic)
+	tracer.DeliverMessage(testMessage(testTopic, p))
+	tracer.DeliverMessage(testMessage(testTopic, p))
+	tracer.DuplicateMessage(testMessage(testTopic, p))
+	tracer.RejectMessage(testMessage(testTopic, p), pubsub.RejectValidationQueueFull)
+	tracer.updateRates(tracer.lastRatesAt.Add(2 * time.Second))
+
+	topics := tracer.Topics()
+	require.Len(t, topics, 1)
+	require.Equal(t, "beacon_attestation_3", topics[0].Topic)
+	require.Equal(t, 1, topics[0].MeshPeers)
+	require.Equal(t, uint64(2), topics[0].Delivered)
+	require.Equal(t, uint64(1), topics[0].Duplicate)
+	require.Equal(t, uint64(1), topics[0].Rejected)
+	require.Equal(t, uint64(1), topics[0].RejectReasons[pubsub.RejectValidationQueueFull])
+	require.InDelta(t, 1.0, topics[0].DeliveredPerSecond, 1e-9)
+	require.InDelta(t, 2.0, topics[0].ReceivedPerSecond, 1e-9)
+
+	tracer.RemovePeer(p)
+	require.Equal(t, 0, tracer.Topics()[0].MeshPeers)
+	tracer.Leave(testTopic)
+	require.Empty(t, tracer.Topics())
+}
+
+func TestGossipTracerValidation(t *testing.T) {
+	tracer, err := NewGossipTracer("", log.New())
+	require.NoError(t, err)
+
+	tracer.RecordValidation("beacon_attestation_1", ValidationAccept)
+	tracer.RecordValidation("beacon_attestation_2", ValidationIgnore)
+	tracer.RecordValidation("beacon_block", ValidationReject)
+	tracer.RecordDrop("beacon_attestation_5")
+
+	require.Equal(t, []ValidationStatistics{
+		{Service: "beacon_attestation", Accepted: 1, Ignored: 1, Dropped: 1},
+		{Service: "beacon_block", Rejected: 1},
+	}, tracer.Validation())
+
+	// a nil tracer must be usable by the gossip manager
+	var nilTracer *GossipTracer
+	nilTracer.RecordValidation("beacon_block", ValidationAccept)
+	nilTracer.RecordDrop("beacon_block")
+}
+
+func TestGossipTracerPeerScores(t *testing.T) {
+	tracer, err := NewGossipTracer("", log.New())
+	require.NoError(t, err)
+	good, bad := peer.ID("good"), peer.ID("bad")
+
+	tracer.ThrottlePeer(bad)
+	tracer.InspectPeerScores(map[peer.ID]*pubsub.PeerScoreSnapshot{
+		good: {Score: 10, AppSpecificScore: 1},
+		bad: {
+			Score:            -100,
+			BehaviourPenalty: 4,
+			Topics: map[string]*pubsub.TopicScoreSnapshot{
+				testTopic: {TimeInMesh: 3 * time.Second, InvalidMessageDeliveries: 2},
+			},
+		},
+	})
+
+	scores := tracer.PeerScores()
+	require.Len(t, scores, 2)
+	require.Equal(t, bad.String(), scores[0].PeerID)
+	require.Equal(t, uint64(1), scores[0].Throttled)
+	require.Equal(t, 4.0, scores[0].BehaviourPenalty)
+	require.Equal(t, TopicScore{TimeInMeshSeconds: 3, InvalidMessageDeliveries: 2}, scores[0].Topics["beacon_attestation_3"])
+	require.Equal(t, good.String(), scores[1].PeerID)
+
+	// throttling is forgotten once the peer is no longer scored
+	tracer.InspectPeerScores(map[peer.ID]*pubsub.PeerScoreSnapshot{good: {Score: 10}})
+	require.Len(t, tracer.PeerScores(), 1)
+	require.Empty(t, tracer.throttled)
+}
+
+func TestGossipTracerTraceLog(t *testing.T) {
+	traceFile := filepath.Join(t.TempDir(), "gossip.jsonl")
+	tracer, err := NewGossipTracer(traceFile, log.New())
+	require.NoError(t, err)
+	p := peer.ID("peer1")
+
+	tracer.DeliverMessage(testMessage(testTopic, p))
+	tracer.RejectMessage(testMessage(testTopic, p), pubsub.RejectValidationFailed)
+	tracer.RecordValidation("beacon_attestation_3", ValidationIgnore)
+	require.NoError(t, tracer.trace.close())
+
+	f, err := os.Open(traceFile)
+	require.NoError(t, err)
+	defer f.Close()
+	var events []traceEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev traceEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		events = append(events, ev)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 3)
+	require.Equal(t, "deliver", events[0].Event)
+	require.Equal(t, "beacon_attestation_3", events[0].Topic)
+	require.Equal(t, 3, events[0].Size)
+	require.Equal(t, "reject", events[1].Event)
+	require.Equal(t, pubsub.RejectValidationFailed, events[1].Reason)
+	require.Equal(t, "validation", events[2].Event)
+	require.Equal(t, "beacon_attestation", events[2].Service)
+	require.Equal(t, "ignore", events[2].Reason)
+
+	// events after closing are discarded
+	tracer.DeliverMessage(testMessage(testTopic, p))
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package gossip_tracer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ValidationResult is the outcome of the Caplin-side validation of a gossip message.
+type ValidationResult int
+
+const (
+	ValidationAccept ValidationResult = iota
+	ValidationIgnore
+	ValidationReject
+)
+
+func (v ValidationResult) String() string {
+	switch v {
+	case ValidationAccept:
+		return "accept"
+	case ValidationIgnore:
+		return "ignore"
+	case ValidationReject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// TopicScore holds the gossipsub score counters of a peer within a single topic.
+type TopicScore struct {
+	TimeInMeshSeconds        float64 `json:"time_in_mesh_seconds"`
+	FirstMessageDeliveries   float64 `json:"first_message_deliveries"`
+	MeshMessageDeliveries    float64 `json:"mesh_message_deliveries"`
+	InvalidMessageDeliveries float64 `json:"invalid_message_deliveries"`
+}
+
+// PeerScore breaks down the gossipsub score of a peer into its components.
+type PeerScore struct {
+	PeerID             string                `json:"peer_id"`
+	Score              float64               `json:"score"`
+	AppSpecificScore   float64               `json:"app_specific_score"`
+	IPColocationFactor float64               `json:"ip_colocation_factor"`
+	BehaviourPenalty   float64               `json:"behaviour_penalty"`
+	Throttled          uint64                `json:"throttled"`
+	Topics             map[string]TopicScore `json:"topics"`
+}
+
+// TopicStatistics holds the gossipsub message counters of a topic.
+type TopicStatistics struct {
+	Topic              string            `json:"topic"`
+	MeshPeers          int               `json:"mesh_peers"`
+	Delivered          uint64            `json:"delivered"`
+	Duplicate          uint64            `json:"duplicate"`
+	Rejected           uint64            `json:"rejected"`
+	Undeliverable      uint64            `json:"undeliverable"`
+	RejectReasons      map[string]uint64 `json:"reject_reasons"`
+	DeliveredPerSecond float64           `json:"delivered_per_second"`
+	ReceivedPerSecond  float64           `json:"received_per_second"`
+}
+
+// ValidationStatistics holds the validation outcomes of a gossip service, messages dropped before
+// validation because the service queue was full are counted separately.
+type ValidationStatistics struct {
+	Service  string `json:"service"`
+	Accepted uint64 `json:"accepted"`
+	Ignored  uint64 `json:"ignored"`
+	Rejected uint64 `json:"rejected"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+// ServiceFromTopic strips the subnet suffix from a topic name, e.g. beacon_attestation_12 -> beacon_attestation.
+func ServiceFromTopic(topic string) string {
+	idx := strings.LastIndexByte(topic, '_')
+	if idx <= 0 {
+		return topic
+	}
+	if _, err := strconv.ParseUint(topic[idx+1:], 10, 64); err != nil {
+		return topic
+	}
+	return topic[:idx]
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/erigontech/erigon/cl/utils"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+const maxDataColumnsThroughoutputPerRequest = 512
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRangeHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+
+	req := cltypes.NewDataColumnSidecarsByRangeRequest(c.beaconConfig.NumberOfColumns)
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+	if err := c.checkRateLimit(peerId, "dataColumnSidecar", rateLimits.dataColumnSidecarsLimit, int(req.Count)*req.Columns.Length()); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := 0
+	for slot := req.StartSlot; slot < req.StartSlot+req.Count && written < maxDataColumnsThroughoutputPerRequest; slot++ {
+		blockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, slot)
+		if err != nil {
+			return err
+		}
+		if blockRoot == (libcommon.Hash{}) {
+			continue
+		}
+		stored, err := c.dataColumnStorage.ColumnSidecarIndices(c.ctx, slot, blockRoot)
+		if err != nil {
+			return err
+		}
+		storedSet := make(map[uint64]struct{}, len(stored))
+		for _, idx := range stored {
+			storedSet[idx] = struct{}{}
+		}
+
+		for i := 0; i < req.Columns.Length() && written < maxDataColumnsThroughoutputPerRequest; i++ {
+			columnIndex := req.Columns.Get(i)
+			if _, ok := storedSet[columnIndex]; !ok {
+				continue
+			}
+			if err := c.writeDataColumnSidecar(s, slot, blockRoot, columnIndex); err != nil {
+				return err
+			}
+			written++
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRootHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+
+	req := solid.NewStaticListSSZ[*cltypes.DataColumnIdentifier](int(c.beaconConfig.MaxRequestDataColumnSidecars), 40)
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+
+	if err := c.checkRateLimit(peerId, "dataColumnSidecar", rateLimits.dataColumnSidecarsLimit, req.Len()); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := 0
+	for i := 0; i < req.Len() && written < maxDataColumnsThroughoutputPerRequest; i++ {
+		id := req.Get(i)
+		slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, id.BlockRoot)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			continue
+		}
+		_, found, err := c.dataColumnStorage.ReadColumnSidecar(c.ctx, *slot, id.BlockRoot, id.Index)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		if err := c.writeDataColumnSidecar(s, *slot, id.BlockRoot, id.Index); err != nil {
+			return err
+		}
+		written++
+	}
+	return nil
+}
+
+// writeDataColumnSidecar writes a single response chunk: the success prefix, the fork digest and the stored sidecar.
+func (c *ConsensusHandlers) writeDataColumnSidecar(s network.Stream, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) error {
+	version := c.beaconConfig.GetCurrentStateVersion(slot / c.beaconConfig.SlotsPerEpoch)
+	forkDigest, err := c.ethClock.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(version)))
+	if err != nil {
+		return err
+	}
+	if _, err := s.Write([]byte{0}); err != nil {
+		return err
+	}
+	if _, err := s.Write(forkDigest[:]); err != nil {
+		return err
+	}
+	return c.dataColumnStorage.WriteStream(s, slot, blockRoot, columnIndex)
+}
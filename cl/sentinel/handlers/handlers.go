@@ -55,6 +55,7 @@ type RateLimits struct {
 	beaconBlocksByRootLimit  int
 	lightClientLimit         int
 	blobSidecarsLimit        int
+	dataColumnSidecarsLimit  int
 }
 
 const (
@@ -63,6 +64,8 @@ const (
 	blockHandlerRateLimit = 200
 	lightClientRateLimit  = 500
 	blobHandlerRateLimit  = 50 // very generous here.
+	// columns are requested in bulk (slots * columns), so the budget is expressed in sidecars.
+	dataColumnHandlerRateLimit = 8192
 )
 
 var rateLimits = RateLimits{
@@ -75,6 +78,7 @@ var rateLimits = RateLimits{
 	beaconBlocksByRootLimit:  blockHandlerRateLimit,
 	lightClientLimit:         lightClientRateLimit,
 	blobSidecarsLimit:        blobHandlerRateLimit,
+	dataColumnSidecarsLimit:  dataColumnHandlerRateLimit,
 }
 
 type ConsensusHandlers struct {
@@ -93,6 +97,7 @@ type ConsensusHandlers struct {
 	me                 *enode.LocalNode
 	netCfg             *clparams.NetworkConfig
 	blobsStorage       blob_storage.BlobStorage
+	dataColumnStorage  blob_storage.DataColumnStorage
 
 	enableBlocks bool
 }
@@ -104,7 +109,7 @@ const (
 )
 
 func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotReader, indiciesDB kv.RoDB, host host.Host,
-	peers *peers.Pool, netCfg *clparams.NetworkConfig, me *enode.LocalNode, beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, hs *handshake.HandShaker, forkChoiceReader forkchoice.ForkChoiceStorageReader, blobsStorage blob_storage.BlobStorage, enabledBlocks bool) *ConsensusHandlers {
+	peers *peers.Pool, netCfg *clparams.NetworkConfig, me *enode.LocalNode, beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, hs *handshake.HandShaker, forkChoiceReader forkchoice.ForkChoiceStorageReader, blobsStorage blob_storage.BlobStorage, dataColumnStorage blob_storage.DataColumnStorage, enabledBlocks bool) *ConsensusHandlers {
 	c := &ConsensusHandlers{
 		host:               host,
 		hs:                 hs,
@@ -120,6 +125,7 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		me:                 me,
 		netCfg:             netCfg,
 		blobsStorage:       blobsStorage,
+		dataColumnStorage:  dataColumnStorage,
 	}
 
 	hm := map[string]func(s network.Stream) error{
@@ -139,6 +145,10 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		hm[communication.BeaconBlocksByRootProtocolV2] = c.beaconBlocksByRootHandler
 		hm[communication.BlobSidecarByRangeProtocolV1] = c.blobsSidecarsByRangeHandler
 		hm[communication.BlobSidecarByRootProtocolV1] = c.blobsSidecarsByIdsHandler
+		if dataColumnStorage != nil {
+			hm[communication.DataColumnSidecarsByRangeProtocolV1] = c.dataColumnSidecarsByRangeHandler
+			hm[communication.DataColumnSidecarsByRootProtocolV1] = c.dataColumnSidecarsByRootHandler
+		}
 	}
 
 	c.handlers = map[protocol.ID]network.StreamHandler{}
@@ -100,7 +100,7 @@ func TestPing(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, nil, true,
 	)
 	c.Start()
 
@@ -154,7 +154,7 @@ func TestGoodbye(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, nil, true,
 	)
 	c.Start()
 
@@ -215,7 +215,7 @@ func TestMetadataV2(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, nil, true,
 	)
 	c.Start()
 
@@ -274,7 +274,7 @@ func TestMetadataV1(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, nil, true,
 	)
 	c.Start()
 
@@ -339,7 +339,7 @@ func TestStatus(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		getEthClock(t),
-		hs, f, nil, true,
+		hs, f, nil, nil, true,
 	)
 	c.Start()
 
@@ -19,6 +19,7 @@ package handlers
 import (
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
 	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
 	"github.com/erigontech/erigon/cl/utils"
 	"github.com/libp2p/go-libp2p/core/network"
@@ -122,17 +123,24 @@ func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(s network.Stream) e
 		endPeriod = c.beaconConfig.SyncCommitteePeriod(currentSlot) + 1
 	}
 
-	notFoundPrev := false
-	// Fetch from [start_period, start_period + count]
-	for i := req.StartPeriod; i < endPeriod; i++ {
-		update, has := c.forkChoiceReader.GetLightClientUpdate(i)
-		if !has {
-			notFoundPrev = true
-			continue
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Fetch from [start_period, start_period + count), the updates must be consecutive so we stop at the first gap.
+	for period := req.StartPeriod; period < endPeriod; period++ {
+		inMemory, _ := c.forkChoiceReader.GetLightClientUpdate(period)
+		update, err := lightclient_utils.ReadBestLightClientUpdate(tx, c.beaconConfig, period, inMemory)
+		if err != nil {
+			return err
 		}
-		if notFoundPrev {
-			lightClientUpdates = lightClientUpdates[0:]
-			notFoundPrev = false
+		if update == nil {
+			if len(lightClientUpdates) > 0 {
+				break
+			}
+			continue
 		}
 
 		lightClientUpdates = append(lightClientUpdates, update)
@@ -140,6 +148,7 @@ func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(s network.Stream) e
 			break
 		}
 	}
+	tx.Rollback()
 
 	// Write the updates
 	for _, update := range lightClientUpdates {
@@ -67,6 +67,12 @@ func (s *Sentinel) pubsubOptions() []pubsub.Option {
 		pubsub.WithPeerScore(scoreParams, thresholds),
 		pubsub.WithGossipSubParams(pubsubGossipParam()),
 	}
+	if s.cfg.GossipTracer != nil {
+		psOpts = append(psOpts,
+			pubsub.WithRawTracer(s.cfg.GossipTracer),
+			pubsub.WithPeerScoreInspect(s.cfg.GossipTracer.InspectPeerScores, s.oneSlotDuration()),
+		)
+	}
 	return psOpts
 }
 
@@ -93,8 +93,9 @@ type Sentinel struct {
 
 	handshaker *handshake.HandShaker
 
-	blockReader freezeblocks.BeaconSnapshotReader
-	blobStorage blob_storage.BlobStorage
+	blockReader       freezeblocks.BeaconSnapshotReader
+	blobStorage       blob_storage.BlobStorage
+	dataColumnStorage blob_storage.DataColumnStorage
 
 	indiciesDB kv.RoDB
 
@@ -190,7 +191,7 @@ func (s *Sentinel) createListener() (*discover.UDPv5, error) {
 	if err != nil {
 		return nil, err
 	}
-	handlers.NewConsensusHandlers(s.ctx, s.blockReader, s.indiciesDB, s.host, s.peers, s.cfg.NetworkConfig, localNode, s.cfg.BeaconConfig, s.ethClock, s.handshaker, s.forkChoiceReader, s.blobStorage, s.cfg.EnableBlocks).Start()
+	handlers.NewConsensusHandlers(s.ctx, s.blockReader, s.indiciesDB, s.host, s.peers, s.cfg.NetworkConfig, localNode, s.cfg.BeaconConfig, s.ethClock, s.handshaker, s.forkChoiceReader, s.blobStorage, s.dataColumnStorage, s.cfg.EnableBlocks).Start()
 
 	return net, err
 }
@@ -202,20 +203,22 @@ func New(
 	ethClock eth_clock.EthereumClock,
 	blockReader freezeblocks.BeaconSnapshotReader,
 	blobStorage blob_storage.BlobStorage,
+	dataColumnStorage blob_storage.DataColumnStorage,
 	indiciesDB kv.RoDB,
 	logger log.Logger,
 	forkChoiceReader forkchoice.ForkChoiceStorageReader,
 ) (*Sentinel, error) {
 	s := &Sentinel{
-		ctx:              ctx,
-		cfg:              cfg,
-		blockReader:      blockReader,
-		indiciesDB:       indiciesDB,
-		metrics:          true,
-		logger:           logger,
-		forkChoiceReader: forkChoiceReader,
-		blobStorage:      blobStorage,
-		ethClock:         ethClock,
+		ctx:               ctx,
+		cfg:               cfg,
+		blockReader:       blockReader,
+		indiciesDB:        indiciesDB,
+		metrics:           true,
+		logger:            logger,
+		forkChoiceReader:  forkChoiceReader,
+		blobStorage:       blobStorage,
+		dataColumnStorage: dataColumnStorage,
+		ethClock:          ethClock,
 	}
 
 	// Setup discovery
@@ -227,9 +230,12 @@ func New(
 		}
 		enodes[i] = newNode
 	}
-	privateKey, err := crypto.GenerateKey()
-	if err != nil {
-		return nil, err
+	privateKey := cfg.NodeKey
+	if privateKey == nil {
+		var err error
+		if privateKey, err = crypto.GenerateKey(); err != nil {
+			return nil, err
+		}
 	}
 	s.discoverConfig = discover.Config{
 		PrivateKey: privateKey,
@@ -357,6 +363,11 @@ func (s *Sentinel) String() string {
 	return s.listener.Self().String()
 }
 
+// NodeID is the discovery id of the node, the PeerDAS custody is derived from it.
+func (s *Sentinel) NodeID() enode.ID {
+	return enode.PubkeyToIDV4(&s.discoverConfig.PrivateKey.PublicKey)
+}
+
 func (s *Sentinel) HasTooManyPeers() bool {
 	active, _, _ := s.GetPeersCount()
 	return active >= int(s.cfg.MaxPeerCount)
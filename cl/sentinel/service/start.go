@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/cl/gossip"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
@@ -84,6 +85,7 @@ func createSentinel(
 	cfg *sentinel.SentinelConfig,
 	blockReader freezeblocks.BeaconSnapshotReader,
 	blobStorage blob_storage.BlobStorage,
+	dataColumnStorage blob_storage.DataColumnStorage,
 	indiciesDB kv.RwDB,
 	forkChoiceReader forkchoice.ForkChoiceStorageReader,
 	ethClock eth_clock.EthereumClock,
@@ -94,6 +96,7 @@ func createSentinel(
 		ethClock,
 		blockReader,
 		blobStorage,
+		dataColumnStorage,
 		indiciesDB,
 		logger,
 		forkChoiceReader,
@@ -161,6 +164,35 @@ func createSentinel(
 			logger.Error("[Sentinel] failed to start sentinel", "err", err)
 		}
 	}
+
+	if cfg.BeaconConfig.Eip7594ForkEpoch != math.MaxUint64 {
+		// join every data column subnet so that we can publish on all of them, but only listen on the ones we custody.
+		custodyColumns, err := das.CustodyColumns(cfg.BeaconConfig, sent.NodeID(), cfg.CustodyGroupCount)
+		if err != nil {
+			return nil, err
+		}
+		custodySubnets := make(map[uint64]struct{})
+		for _, subnet := range das.CustodySubnets(cfg.BeaconConfig, custodyColumns) {
+			custodySubnets[subnet] = struct{}{}
+		}
+		for subnet := uint64(0); subnet < cfg.BeaconConfig.DataColumnSidecarSubnetCount; subnet++ {
+			topic := sentinel.GossipTopic{
+				Name:     gossip.TopicNameDataColumnSidecar(subnet),
+				CodecStr: sentinel.SSZSnappyCodec,
+			}
+			if err := sent.Unsubscribe(topic); err != nil {
+				logger.Error("[Sentinel] failed to start sentinel", "err", err)
+				continue
+			}
+			expiration := time.Unix(0, 0)
+			if _, ok := custodySubnets[subnet]; ok || cfg.SubscribeAllTopics {
+				expiration = time.Unix(0, math.MaxInt64)
+			}
+			if _, err := sent.SubscribeGossip(topic, expiration); err != nil {
+				logger.Error("[Sentinel] failed to start sentinel", "err", err)
+			}
+		}
+	}
 	return sent, nil
 }
 
@@ -168,6 +200,7 @@ func StartSentinelService(
 	cfg *sentinel.SentinelConfig,
 	blockReader freezeblocks.BeaconSnapshotReader,
 	blobStorage blob_storage.BlobStorage,
+	dataColumnStorage blob_storage.DataColumnStorage,
 	indiciesDB kv.RwDB,
 	srvCfg *ServerConfig,
 	ethClock eth_clock.EthereumClock,
@@ -178,6 +211,7 @@ func StartSentinelService(
 		cfg,
 		blockReader,
 		blobStorage,
+		dataColumnStorage,
 		indiciesDB,
 		forkChoiceReader,
 		ethClock,
@@ -43,6 +43,8 @@ func init() {
 		With("inactivity_updates", inactivityUpdateTest).
 		With("justification_and_finalization", justificationFinalizationTest).
 		With("participation_flag_updates", participationFlagUpdatesTest).
+		With("pending_consolidations", pendingConsolidationsTest).
+		With("pending_deposits", pendingDepositsTest).
 		With("randao_mixes_reset", randaoMixesTest).
 		With("registry_updates", registryUpdatesTest).
 		With("rewards_and_penalties", rewardsAndPenaltiesTest).
@@ -62,7 +64,10 @@ func init() {
 		With("validity", spectest.UnimplementedHandler).
 		With("initialization", spectest.UnimplementedHandler)
 	TestFormats.Add("kzg").
-		With("", spectest.UnimplementedHandler)
+		With("compute_cells", KzgComputeCells).
+		With("compute_cells_and_kzg_proofs", KzgComputeCellsAndKZGProofs).
+		With("verify_cell_kzg_proof_batch", KzgVerifyCellKZGProofBatch).
+		With("recover_cells_and_kzg_proofs", KzgRecoverCellsAndKZGProofs)
 	TestFormats.Add("light_client").
 		WithFn("single_merkle_proof", LightClientBeaconBlockBodyExecutionMerkleProof)
 	TestFormats.Add("merkle_proof").
@@ -76,7 +81,10 @@ func init() {
 		WithFn("voluntary_exit", operationVoluntaryExitHandler).
 		WithFn("sync_aggregate", operationSyncAggregateHandler).
 		WithFn("withdrawals", operationWithdrawalHandler).
-		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler)
+		WithFn("bls_to_execution-change", operationSignedBlsChangeHandler).
+		WithFn("deposit_request", operationDepositRequestHandler).
+		WithFn("withdrawal_request", operationWithdrawalRequestHandler).
+		WithFn("consolidation_request", operationConsolidationRequestHandler)
 	TestFormats.Add("random").
 		With("random", SanityBlocks)
 	TestFormats.Add("rewards").
@@ -112,13 +120,16 @@ func addSszTests() {
 		With("BlobSidecar", getSSZStaticConsensusTest(&cltypes.BlobSidecar{})).
 		With("BLSToExecutionChange", getSSZStaticConsensusTest(&cltypes.BLSToExecutionChange{})).
 		With("Checkpoint", getSSZStaticConsensusTest(&solid.Checkpoint{})).
+		With("ConsolidationRequest", getSSZStaticConsensusTest(&cltypes.ConsolidationRequest{})).
 		With("ContributionAndProof", getSSZStaticConsensusTest(&cltypes.ContributionAndProof{})).
 		With("Deposit", getSSZStaticConsensusTest(&cltypes.Deposit{})).
 		With("DepositData", getSSZStaticConsensusTest(&cltypes.DepositData{})).
+		With("DepositRequest", getSSZStaticConsensusTest(&cltypes.DepositRequest{})).
 		//	With("DepositMessage", getSSZStaticConsensusTest(&cltypes.DepositMessage{})).
 		// With("Eth1Block", getSSZStaticConsensusTest(&cltypes.Eth1Block{})).
 		With("Eth1Data", getSSZStaticConsensusTest(&cltypes.Eth1Data{})).
 		With("ExecutionPayload", getSSZStaticConsensusTest(cltypes.NewEth1Block(clparams.Phase0Version, &clparams.MainnetBeaconConfig))).
+		With("ExecutionRequests", getSSZStaticConsensusTest(cltypes.NewExecutionRequests())).
 		//With("ExecutionPayloadHeader", getSSZStaticConsensusTest(&cltypes.Eth1Header{})).
 		With("Fork", getSSZStaticConsensusTest(&cltypes.Fork{})).
 		//With("ForkData", getSSZStaticConsensusTest(&cltypes.ForkData{})).
@@ -131,6 +142,9 @@ func addSszTests() {
 		With("LightClientOptimisticUpdate", getSSZStaticConsensusTest(&cltypes.LightClientOptimisticUpdate{})).
 		With("LightClientUpdate", getSSZStaticConsensusTest(&cltypes.LightClientUpdate{})).
 		With("PendingAttestation", getSSZStaticConsensusTest(&solid.PendingAttestation{})).
+		With("PendingConsolidation", getSSZStaticConsensusTest(&cltypes.PendingConsolidation{})).
+		With("PendingDeposit", getSSZStaticConsensusTest(&cltypes.PendingDeposit{})).
+		With("PendingPartialWithdrawal", getSSZStaticConsensusTest(&cltypes.PendingPartialWithdrawal{})).
 		//		With("PowBlock", getSSZStaticConsensusTest(&cltypes.PowBlock{})). Unimplemented
 		With("ProposerSlashing", getSSZStaticConsensusTest(&cltypes.ProposerSlashing{})).
 		With("SignedAggregateAndProof", getSSZStaticConsensusTest(&cltypes.SignedAggregateAndProof{})).
@@ -146,7 +160,8 @@ func addSszTests() {
 		With("SyncCommittee", getSSZStaticConsensusTest(&solid.SyncCommittee{})).
 		//	With("SyncCommitteeContribution", getSSZStaticConsensusTest(&cltypes.SyncCommitteeContribution{})).
 		//	With("SyncCommitteeMessage", getSSZStaticConsensusTest(&cltypes.SyncCommitteeMessage{})).
-		With("Validator", getSSZStaticConsensusTest(solid.NewValidator()))
+		With("Validator", getSSZStaticConsensusTest(solid.NewValidator())).
+		With("WithdrawalRequest", getSSZStaticConsensusTest(&cltypes.WithdrawalRequest{}))
 	// With("VoluntaryExit", getSSZStaticConsensusTest(&cltypes.VoluntaryExit{})) TODO
 	// With("Withdrawal", getSSZStaticConsensusTest(&types.Withdrawal{})) TODO
 }
@@ -102,6 +102,10 @@ var randaoMixesTest = NewEpochProcessing(func(s abstract.BeaconState) error {
 	return nil
 })
 
+var pendingDepositsTest = NewEpochProcessing(statechange.ProcessPendingDeposits)
+
+var pendingConsolidationsTest = NewEpochProcessing(statechange.ProcessPendingConsolidations)
+
 var registryUpdatesTest = NewEpochProcessing(statechange.ProcessRegistryUpdates)
 
 var rewardsAndPenaltiesTest = NewEpochProcessing(func(s abstract.BeaconState) error {
@@ -0,0 +1,211 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus_tests
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon/spectest"
+)
+
+// The EIP-7594 kzg vectors live in tests/general/<fork>/kzg/<handler>/kzg-mainnet/<case>/data.yaml, a null
+// output means the inputs must be rejected.
+
+func decodeFixedHex(s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return b, nil
+}
+
+func decodeBlob(s string) (*gokzg4844.Blob, error) {
+	b, err := decodeFixedHex(s, len(gokzg4844.Blob{}))
+	if err != nil {
+		return nil, err
+	}
+	return (*gokzg4844.Blob)(b), nil
+}
+
+func decodeCells(hexCells []string) ([]kzg.Cell, error) {
+	cells := make([]kzg.Cell, len(hexCells))
+	for i := range hexCells {
+		b, err := decodeFixedHex(hexCells[i], kzg.BytesPerCell)
+		if err != nil {
+			return nil, err
+		}
+		cells[i] = kzg.Cell(b)
+	}
+	return cells, nil
+}
+
+func decodeBytes48(hexPoints []string) ([][48]byte, error) {
+	points := make([][48]byte, len(hexPoints))
+	for i := range hexPoints {
+		b, err := decodeFixedHex(hexPoints[i], 48)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = [48]byte(b)
+	}
+	return points, nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func requireCellsAndProofs(t *testing.T, expected [][]string, cells []kzg.Cell, proofs []gokzg4844.KZGProof, err error) {
+	if expected == nil {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	require.Len(t, expected, 2)
+	require.Len(t, cells, len(expected[0]))
+	for i := range cells {
+		require.Equal(t, expected[0][i], encodeHex(cells[i][:]), "cell %d", i)
+	}
+	require.Len(t, proofs, len(expected[1]))
+	for i := range proofs {
+		require.Equal(t, expected[1][i], encodeHex(proofs[i][:]), "proof %d", i)
+	}
+}
+
+var KzgComputeCells = spectest.HandlerFunc(func(t *testing.T, root fs.FS, c spectest.TestCase) (err error) {
+	var data struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		} `yaml:"input"`
+		Output []string `yaml:"output"`
+	}
+	require.NoError(t, spectest.ReadYml(root, "data.yaml", &data))
+
+	blob, err := decodeBlob(data.Input.Blob)
+	var cells []kzg.Cell
+	if err == nil {
+		cells, err = kzg.ComputeCells(blob)
+	}
+	if data.Output == nil {
+		require.Error(t, err)
+		return nil
+	}
+	require.NoError(t, err)
+	require.Len(t, cells, len(data.Output))
+	for i := range cells {
+		require.Equal(t, data.Output[i], encodeHex(cells[i][:]), "cell %d", i)
+	}
+	return nil
+})
+
+var KzgComputeCellsAndKZGProofs = spectest.HandlerFunc(func(t *testing.T, root fs.FS, c spectest.TestCase) (err error) {
+	var data struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		} `yaml:"input"`
+		Output [][]string `yaml:"output"`
+	}
+	require.NoError(t, spectest.ReadYml(root, "data.yaml", &data))
+
+	blob, err := decodeBlob(data.Input.Blob)
+	var cells []kzg.Cell
+	var proofs []gokzg4844.KZGProof
+	if err == nil {
+		cells, proofs, err = kzg.ComputeCellsAndKZGProofs(blob)
+	}
+	requireCellsAndProofs(t, data.Output, cells, proofs, err)
+	return nil
+})
+
+var KzgVerifyCellKZGProofBatch = spectest.HandlerFunc(func(t *testing.T, root fs.FS, c spectest.TestCase) (err error) {
+	var data struct {
+		Input struct {
+			Commitments []string `yaml:"commitments"`
+			CellIndices []uint64 `yaml:"cell_indices"`
+			Cells       []string `yaml:"cells"`
+			Proofs      []string `yaml:"proofs"`
+		} `yaml:"input"`
+		Output *bool `yaml:"output"`
+	}
+	require.NoError(t, spectest.ReadYml(root, "data.yaml", &data))
+
+	err = func() error {
+		commitments, err := decodeBytes48(data.Input.Commitments)
+		if err != nil {
+			return err
+		}
+		cells, err := decodeCells(data.Input.Cells)
+		if err != nil {
+			return err
+		}
+		proofs, err := decodeBytes48(data.Input.Proofs)
+		if err != nil {
+			return err
+		}
+		kzgCommitments := make([]gokzg4844.KZGCommitment, len(commitments))
+		for i := range commitments {
+			kzgCommitments[i] = commitments[i]
+		}
+		kzgProofs := make([]gokzg4844.KZGProof, len(proofs))
+		for i := range proofs {
+			kzgProofs[i] = proofs[i]
+		}
+		return kzg.VerifyCellKZGProofBatch(kzgCommitments, data.Input.CellIndices, cells, kzgProofs)
+	}()
+	switch {
+	case data.Output == nil:
+		require.Error(t, err)
+		require.False(t, errors.Is(err, kzg.ErrInvalidCellProof), "malformed input must be rejected, not fail verification")
+	case *data.Output:
+		require.NoError(t, err)
+	default:
+		require.ErrorIs(t, err, kzg.ErrInvalidCellProof)
+	}
+	return nil
+})
+
+var KzgRecoverCellsAndKZGProofs = spectest.HandlerFunc(func(t *testing.T, root fs.FS, c spectest.TestCase) (err error) {
+	var data struct {
+		Input struct {
+			CellIndices []uint64 `yaml:"cell_indices"`
+			Cells       []string `yaml:"cells"`
+		} `yaml:"input"`
+		Output [][]string `yaml:"output"`
+	}
+	require.NoError(t, spectest.ReadYml(root, "data.yaml", &data))
+
+	cells, err := decodeCells(data.Input.Cells)
+	var recovered []kzg.Cell
+	var proofs []gokzg4844.KZGProof
+	if err == nil {
+		recovered, proofs, err = kzg.RecoverCellsAndKZGProofs(data.Input.CellIndices, cells)
+	}
+	requireCellsAndProofs(t, data.Output, recovered, proofs, err)
+	return nil
+})
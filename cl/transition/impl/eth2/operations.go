@@ -17,6 +17,7 @@
 package eth2
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"slices"
@@ -171,35 +172,29 @@ func (I *impl) ProcessDeposit(s abstract.BeaconState, deposit *cltypes.Deposit)
 	// Check if pub key is in validator set
 	validatorIndex, has := s.ValidatorIndexByPubkey(publicKey)
 	if !has {
-		// Agnostic domain.
-		domain, err := fork.ComputeDomain(
-			s.BeaconConfig().DomainDeposit[:],
-			utils.Uint32ToBytes4(uint32(s.BeaconConfig().GenesisForkVersion)),
-			[32]byte{},
-		)
-		if err != nil {
-			return err
-		}
-		depositMessageRoot, err := deposit.Data.MessageHash()
-		if err != nil {
-			return err
-		}
-		signedRoot := utils.Sha256(depositMessageRoot[:], domain)
 		// Perform BLS verification and if successful noice.
-		valid, err := bls.Verify(deposit.Data.Signature[:], signedRoot[:], publicKey[:])
+		valid, err := state.IsValidDepositSignature(s, publicKey, deposit.Data.WithdrawalCredentials, amount, deposit.Data.Signature)
 		// Literally you can input it trash.
 		if !valid || err != nil {
 			log.Debug("Validator BLS verification failed", "valid", valid, "err", err)
 			return nil
 		}
-		// Append validator
-		s.AddValidator(state.ValidatorFromDeposit(s.BeaconConfig(), deposit), amount)
-		// Altair forward
-		if s.Version() >= clparams.AltairVersion {
-			s.AddCurrentEpochParticipationFlags(cltypes.ParticipationFlags(0))
-			s.AddPreviousEpochParticipationFlags(cltypes.ParticipationFlags(0))
-			s.AddInactivityScore(0)
+		if s.Version() < clparams.ElectraVersion {
+			// Append validator
+			state.AddValidatorToRegistry(s, publicKey, deposit.Data.WithdrawalCredentials, amount)
+			return nil
 		}
+		// After electra, the balance of the new validator is credited through the pending deposits queue.
+		state.AddValidatorToRegistry(s, publicKey, deposit.Data.WithdrawalCredentials, 0)
+	}
+	if s.Version() >= clparams.ElectraVersion {
+		s.AppendPendingDeposit(&cltypes.PendingDeposit{
+			PubKey:                publicKey,
+			WithdrawalCredentials: deposit.Data.WithdrawalCredentials,
+			Amount:                amount,
+			Signature:             deposit.Data.Signature,
+			Slot:                  s.BeaconConfig().GenesisSlot,
+		})
 		return nil
 	}
 	// Increase the balance if exists already
@@ -226,6 +221,10 @@ func IsVoluntaryExitApplicable(s abstract.BeaconState, voluntaryExit *cltypes.Vo
 	if currentEpoch < validator.ActivationEpoch()+s.BeaconConfig().ShardCommitteePeriod {
 		return errors.New("ProcessVoluntaryExit: exit is happening too fast")
 	}
+	// Only exit validator if it has no pending withdrawals in the queue
+	if s.Version() >= clparams.ElectraVersion && state.GetPendingBalanceToWithdraw(s, voluntaryExit.ValidatorIndex) > 0 {
+		return errors.New("ProcessVoluntaryExit: validator has pending partial withdrawals")
+	}
 	return nil
 }
 
@@ -256,9 +255,17 @@ func (I *impl) ProcessWithdrawals(
 	beaconConfig := s.BeaconConfig()
 	numValidators := uint64(s.ValidatorLength())
 
+	// Processed pending partial withdrawals are removed from the queue after electra.
+	var (
+		expectedWithdrawals     []*cltypes.Withdrawal
+		partialWithdrawalsCount uint64
+	)
+	if I.FullValidation || s.Version() >= clparams.ElectraVersion {
+		expectedWithdrawals, partialWithdrawalsCount = state.ExpectedWithdrawals(s, state.Epoch(s))
+	}
+
 	// Check if full validation is required and verify expected withdrawals.
 	if I.FullValidation {
-		expectedWithdrawals := state.ExpectedWithdrawals(s, state.Epoch(s))
 		if len(expectedWithdrawals) != withdrawals.Len() {
 			return fmt.Errorf(
 				"ProcessWithdrawals: expected %d withdrawals, but got %d",
@@ -285,6 +292,12 @@ func (I *impl) ProcessWithdrawals(
 		return err
 	}
 
+	if partialWithdrawalsCount > 0 {
+		pendingPartialWithdrawals := s.PendingPartialWithdrawals()
+		pendingPartialWithdrawals.Cut(int(partialWithdrawalsCount))
+		s.SetPendingPartialWithdrawals(pendingPartialWithdrawals)
+	}
+
 	// Update next withdrawal index based on number of withdrawals.
 	if withdrawals.Len() > 0 {
 		lastWithdrawalIndex := withdrawals.Get(withdrawals.Len() - 1).Index
@@ -444,6 +457,184 @@ func (I *impl) ProcessBlsToExecutionChange(
 	return nil
 }
 
+// ProcessDepositRequest queues a deposit coming from the execution layer deposit contract (EIP-6110).
+func (I *impl) ProcessDepositRequest(s abstract.BeaconState, depositRequest *cltypes.DepositRequest) error {
+	// Set deposit request start index
+	if s.DepositRequestsStartIndex() == s.BeaconConfig().UnsetDepositRequestsStartIndex {
+		s.SetDepositRequestsStartIndex(depositRequest.Index)
+	}
+	s.AppendPendingDeposit(&cltypes.PendingDeposit{
+		PubKey:                depositRequest.PubKey,
+		WithdrawalCredentials: depositRequest.WithdrawalCredentials,
+		Amount:                depositRequest.Amount,
+		Signature:             depositRequest.Signature,
+		Slot:                  s.Slot(),
+	})
+	return nil
+}
+
+// ProcessWithdrawalRequest processes an execution layer triggered exit or partial withdrawal (EIP-7002).
+// Invalid requests are not an error, they are simply ignored.
+func (I *impl) ProcessWithdrawalRequest(s abstract.BeaconState, withdrawalRequest *cltypes.WithdrawalRequest) error {
+	beaconConfig := s.BeaconConfig()
+	currentEpoch := state.Epoch(s)
+	amount := withdrawalRequest.Amount
+	isFullExitRequest := amount == beaconConfig.FullExitRequestAmount
+	// If partial withdrawal queue is full, only full exits are processed
+	if uint64(s.PendingPartialWithdrawals().Len()) == beaconConfig.PendingPartialWithdrawalsLimit && !isFullExitRequest {
+		return nil
+	}
+	validatorIndex, ok := s.ValidatorIndexByPubkey(withdrawalRequest.ValidatorPubKey)
+	if !ok {
+		return nil
+	}
+	validator, err := s.ValidatorForValidatorIndex(int(validatorIndex))
+	if err != nil {
+		return err
+	}
+	// Verify withdrawal credentials
+	wc := validator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, validator) || !bytes.Equal(wc[12:], withdrawalRequest.SourceAddress[:]) {
+		return nil
+	}
+	// Verify the validator is active, has not initiated exit and has been active long enough
+	if !validator.Active(currentEpoch) || validator.ExitEpoch() != beaconConfig.FarFutureEpoch ||
+		currentEpoch < validator.ActivationEpoch()+beaconConfig.ShardCommitteePeriod {
+		return nil
+	}
+
+	pendingBalanceToWithdraw := state.GetPendingBalanceToWithdraw(s, validatorIndex)
+	if isFullExitRequest {
+		// Only exit validator if it has no pending withdrawals in the queue
+		if pendingBalanceToWithdraw == 0 {
+			return s.InitiateValidatorExit(validatorIndex)
+		}
+		return nil
+	}
+
+	balance, err := s.ValidatorBalance(int(validatorIndex))
+	if err != nil {
+		return err
+	}
+	hasSufficientEffectiveBalance := validator.EffectiveBalance() >= beaconConfig.MinActivationBalance
+	hasExcessBalance := balance > beaconConfig.MinActivationBalance+pendingBalanceToWithdraw
+	// Only allow partial withdrawals with compounding withdrawal credentials
+	if !state.HasCompoundingWithdrawalCredential(beaconConfig, validator) || !hasSufficientEffectiveBalance || !hasExcessBalance {
+		return nil
+	}
+	toWithdraw := min(balance-beaconConfig.MinActivationBalance-pendingBalanceToWithdraw, amount)
+	exitQueueEpoch := state.ComputeExitEpochAndUpdateChurn(s, toWithdraw)
+	s.AppendPendingPartialWithdrawal(&cltypes.PendingPartialWithdrawal{
+		Index:             validatorIndex,
+		Amount:            toWithdraw,
+		WithdrawableEpoch: exitQueueEpoch + beaconConfig.MinValidatorWithdrawabilityDelay,
+	})
+	return nil
+}
+
+// isValidSwitchToCompoundingRequest checks whether a consolidation request asks to switch the source to 0x02 credentials.
+func isValidSwitchToCompoundingRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) (uint64, bool, error) {
+	beaconConfig := s.BeaconConfig()
+	// Switch to compounding requires source and target be equal
+	if consolidationRequest.SourcePubKey != consolidationRequest.TargetPubKey {
+		return 0, false, nil
+	}
+	sourceIndex, ok := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+	if !ok {
+		return 0, false, nil
+	}
+	sourceValidator, err := s.ValidatorForValidatorIndex(int(sourceIndex))
+	if err != nil {
+		return 0, false, err
+	}
+	// Verify request has been authorized and source has 0x01 credentials
+	wc := sourceValidator.WithdrawalCredentials()
+	if !bytes.Equal(wc[12:], consolidationRequest.SourceAddress[:]) || !state.HasEth1WithdrawalCredential(beaconConfig, sourceValidator) {
+		return 0, false, nil
+	}
+	// Verify the source is active and exit has not been initiated
+	if !sourceValidator.Active(state.Epoch(s)) || sourceValidator.ExitEpoch() != beaconConfig.FarFutureEpoch {
+		return 0, false, nil
+	}
+	return sourceIndex, true, nil
+}
+
+// ProcessConsolidationRequest processes an execution layer triggered consolidation of two validators (EIP-7251).
+// Invalid requests are not an error, they are simply ignored.
+func (I *impl) ProcessConsolidationRequest(s abstract.BeaconState, consolidationRequest *cltypes.ConsolidationRequest) error {
+	beaconConfig := s.BeaconConfig()
+	currentEpoch := state.Epoch(s)
+	sourceIndex, isSwitchToCompounding, err := isValidSwitchToCompoundingRequest(s, consolidationRequest)
+	if err != nil {
+		return err
+	}
+	if isSwitchToCompounding {
+		return state.SwitchToCompoundingValidator(s, sourceIndex)
+	}
+	// Verify that source != target, so a consolidation cannot be used as an exit
+	if consolidationRequest.SourcePubKey == consolidationRequest.TargetPubKey {
+		return nil
+	}
+	// If the pending consolidations queue is full, consolidation requests are ignored
+	if uint64(s.PendingConsolidations().Len()) == beaconConfig.PendingConsolidationsLimit {
+		return nil
+	}
+	// If there is too little available consolidation churn limit, consolidation requests are ignored
+	if state.GetConsolidationChurnLimit(s) <= beaconConfig.MinActivationBalance {
+		return nil
+	}
+	sourceIndex, ok := s.ValidatorIndexByPubkey(consolidationRequest.SourcePubKey)
+	if !ok {
+		return nil
+	}
+	targetIndex, ok := s.ValidatorIndexByPubkey(consolidationRequest.TargetPubKey)
+	if !ok {
+		return nil
+	}
+	sourceValidator, err := s.ValidatorForValidatorIndex(int(sourceIndex))
+	if err != nil {
+		return err
+	}
+	targetValidator, err := s.ValidatorForValidatorIndex(int(targetIndex))
+	if err != nil {
+		return err
+	}
+	// Verify source withdrawal credentials
+	wc := sourceValidator.WithdrawalCredentials()
+	if !state.HasExecutionWithdrawalCredential(beaconConfig, sourceValidator) || !bytes.Equal(wc[12:], consolidationRequest.SourceAddress[:]) {
+		return nil
+	}
+	// Verify that target has compounding withdrawal credentials
+	if !state.HasCompoundingWithdrawalCredential(beaconConfig, targetValidator) {
+		return nil
+	}
+	// Verify the source and the target are active and their exits have not been initiated
+	if !sourceValidator.Active(currentEpoch) || !targetValidator.Active(currentEpoch) ||
+		sourceValidator.ExitEpoch() != beaconConfig.FarFutureEpoch || targetValidator.ExitEpoch() != beaconConfig.FarFutureEpoch {
+		return nil
+	}
+	// Verify the source has been active long enough
+	if currentEpoch < sourceValidator.ActivationEpoch()+beaconConfig.ShardCommitteePeriod {
+		return nil
+	}
+	// Verify the source has no pending withdrawals in the queue
+	if state.GetPendingBalanceToWithdraw(s, sourceIndex) > 0 {
+		return nil
+	}
+
+	// Initiate source validator exit and append pending consolidation
+	exitEpoch := state.ComputeConsolidationEpochAndUpdateChurn(s, sourceValidator.EffectiveBalance())
+	s.SetExitEpochForValidatorAtIndex(int(sourceIndex), exitEpoch)
+	if err := s.SetWithdrawableEpochForValidatorAtIndex(int(sourceIndex), exitEpoch+beaconConfig.MinValidatorWithdrawabilityDelay); err != nil {
+		return err
+	}
+	s.AppendPendingConsolidation(&cltypes.PendingConsolidation{
+		SourceIndex: sourceIndex,
+		TargetIndex: targetIndex,
+	})
+	return nil
+}
+
 func (I *impl) ProcessAttestations(
 	s abstract.BeaconState,
 	attestations *solid.ListSSZ[*solid.Attestation],
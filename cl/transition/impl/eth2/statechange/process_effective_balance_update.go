@@ -18,12 +18,14 @@ package statechange
 
 import (
 	"github.com/erigontech/erigon/cl/abstract"
+	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
 )
 
 // ProcessEffectiveBalanceUpdates updates the effective balance of validators. Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#effective-balances-updates
-func ProcessEffectiveBalanceUpdates(state abstract.BeaconState) error {
-	beaconConfig := state.BeaconConfig()
+func ProcessEffectiveBalanceUpdates(s abstract.BeaconState) error {
+	beaconConfig := s.BeaconConfig()
 	// Define non-changing constants to avoid recomputation.
 	histeresisIncrement := beaconConfig.EffectiveBalanceIncrement / beaconConfig.HysteresisQuotient
 	downwardThreshold := histeresisIncrement * beaconConfig.HysteresisDownwardMultiplier
@@ -31,16 +33,20 @@ func ProcessEffectiveBalanceUpdates(state abstract.BeaconState) error {
 	// Iterate over validator set and compute the diff of each validator.
 	var err error
 	var balance uint64
-	state.ForEachValidator(func(validator solid.Validator, index, total int) bool {
-		balance, err = state.ValidatorBalance(index)
+	s.ForEachValidator(func(validator solid.Validator, index, total int) bool {
+		balance, err = s.ValidatorBalance(index)
 		if err != nil {
 			return false
 		}
 		eb := validator.EffectiveBalance()
 		if balance+downwardThreshold < eb || eb+upwardThreshold < balance {
+			maxEffectiveBalance := beaconConfig.MaxEffectiveBalance
+			if s.Version() >= clparams.ElectraVersion {
+				maxEffectiveBalance = state.GetMaxEffectiveBalance(beaconConfig, validator)
+			}
 			// Set new effective balance
-			effectiveBalance := min(balance-(balance%beaconConfig.EffectiveBalanceIncrement), beaconConfig.MaxEffectiveBalance)
-			state.SetEffectiveBalanceForValidatorAtIndex(index, effectiveBalance)
+			effectiveBalance := min(balance-(balance%beaconConfig.EffectiveBalanceIncrement), maxEffectiveBalance)
+			s.SetEffectiveBalanceForValidatorAtIndex(index, effectiveBalance)
 		}
 		return true
 	})
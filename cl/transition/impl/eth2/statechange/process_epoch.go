@@ -17,40 +17,20 @@
 package statechange
 
 import (
-	"runtime"
 	"time"
 
 	"github.com/erigontech/erigon/cl/abstract"
 	"github.com/erigontech/erigon/cl/clparams"
-	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/monitor"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
-	"github.com/erigontech/erigon/cl/utils/threading"
 )
 
-func GetUnslashedIndiciesSet(cfg *clparams.BeaconChainConfig, previousEpoch uint64, validatorSet *solid.ValidatorSet, previousEpochParticipation *solid.ParticipationBitList) [][]bool {
-	weights := cfg.ParticipationWeights()
-	flagsUnslashedIndiciesSet := make([][]bool, len(weights))
-	for i := range weights {
-		flagsUnslashedIndiciesSet[i] = make([]bool, validatorSet.Length())
-	}
-
-	threading.ParallellForLoop(runtime.NumCPU(), 0, validatorSet.Length(), func(validatorIndex int) error {
-		for i := range weights {
-			flagsUnslashedIndiciesSet[i][validatorIndex] = state.IsUnslashedParticipatingIndex(validatorSet, previousEpochParticipation, previousEpoch, uint64(validatorIndex), i)
-		}
-		return nil
-	})
-
-	return flagsUnslashedIndiciesSet
-}
-
 // ProcessEpoch process epoch transition.
 func ProcessEpoch(s abstract.BeaconState) error {
 	eligibleValidators := state.EligibleValidatorsIndicies(s)
 	var unslashedIndiciesSet [][]bool
 	if s.Version() >= clparams.AltairVersion {
-		unslashedIndiciesSet = GetUnslashedIndiciesSet(s.BeaconConfig(), state.PreviousEpoch(s), s.ValidatorSet(), s.PreviousEpochParticipation())
+		unslashedIndiciesSet = state.GetUnslashedIndiciesSet(s.BeaconConfig(), state.PreviousEpoch(s), s.ValidatorSet(), s.PreviousEpochParticipation())
 	}
 	start := time.Now()
 	if err := ProcessJustificationBitsAndFinality(s, unslashedIndiciesSet); err != nil {
@@ -90,6 +70,14 @@ func ProcessEpoch(s abstract.BeaconState) error {
 
 	// fmt.Println("ProcessSlashings", time.Since(start))
 	ProcessEth1DataReset(s)
+	if s.Version() >= clparams.ElectraVersion {
+		if err := ProcessPendingDeposits(s); err != nil {
+			return err
+		}
+		if err := ProcessPendingConsolidations(s); err != nil {
+			return err
+		}
+	}
 	start = time.Now()
 	if err := ProcessEffectiveBalanceUpdates(s); err != nil {
 		return err
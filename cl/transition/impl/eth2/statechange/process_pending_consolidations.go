@@ -0,0 +1,62 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package statechange
+
+import (
+	"github.com/erigontech/erigon/cl/abstract"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+// ProcessPendingConsolidations moves the active balance of withdrawable consolidation sources to their targets (Electra).
+// Specs at: https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-process_pending_consolidations
+func ProcessPendingConsolidations(s abstract.BeaconState) error {
+	nextEpoch := state.Epoch(s) + 1
+	nextPendingConsolidation := 0
+	var err error
+	s.PendingConsolidations().Range(func(_ int, consolidation *cltypes.PendingConsolidation, _ int) bool {
+		sourceValidator := s.ValidatorSet().Get(int(consolidation.SourceIndex))
+		if sourceValidator.Slashed() {
+			nextPendingConsolidation++
+			return true
+		}
+		if sourceValidator.WithdrawableEpoch() > nextEpoch {
+			return false
+		}
+		var sourceBalance uint64
+		if sourceBalance, err = s.ValidatorBalance(int(consolidation.SourceIndex)); err != nil {
+			return false
+		}
+		// Move active balance to target. Excess balance is withdrawable.
+		sourceEffectiveBalance := min(sourceBalance, sourceValidator.EffectiveBalance())
+		if err = state.DecreaseBalance(s, consolidation.SourceIndex, sourceEffectiveBalance); err != nil {
+			return false
+		}
+		if err = state.IncreaseBalance(s, consolidation.TargetIndex, sourceEffectiveBalance); err != nil {
+			return false
+		}
+		nextPendingConsolidation++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	pendingConsolidations := s.PendingConsolidations()
+	pendingConsolidations.Cut(nextPendingConsolidation)
+	s.SetPendingConsolidations(pendingConsolidations)
+	return nil
+}
@@ -43,6 +43,9 @@ type minimizeQueuedValidator struct {
 func ProcessRegistryUpdates(s abstract.BeaconState) error {
 	beaconConfig := s.BeaconConfig()
 	currentEpoch := state.Epoch(s)
+	if s.Version() >= clparams.ElectraVersion {
+		return processRegistryUpdatesElectra(s, currentEpoch)
+	}
 	// start also initializing the activation queue.
 	var m sync.Mutex
 	activationQueue := make([]minimizeQueuedValidator, 0)
@@ -92,3 +95,23 @@ func ProcessRegistryUpdates(s abstract.BeaconState) error {
 	}
 	return nil
 }
+
+// processRegistryUpdatesElectra activates every eligible validator, since activations are rate limited by the pending deposits churn.
+// Exits consume the balance churn in validator order, so the loop has to be sequential.
+func processRegistryUpdatesElectra(s abstract.BeaconState, currentEpoch uint64) error {
+	beaconConfig := s.BeaconConfig()
+	activationEpoch := computeActivationExitEpoch(beaconConfig, currentEpoch)
+	for i := 0; i < s.ValidatorLength(); i++ {
+		validator := s.ValidatorSet().Get(i)
+		if state.IsValidatorEligibleForActivationQueue(s, validator) {
+			s.SetActivationEligibilityEpochForValidatorAtIndex(i, currentEpoch+1)
+		} else if validator.Active(currentEpoch) && validator.EffectiveBalance() <= beaconConfig.EjectionBalance {
+			if err := s.InitiateValidatorExit(uint64(i)); err != nil {
+				return err
+			}
+		} else if state.IsValidatorEligibleForActivation(s, validator) {
+			s.SetActivationEpochForValidatorAtIndex(i, activationEpoch)
+		}
+	}
+	return nil
+}
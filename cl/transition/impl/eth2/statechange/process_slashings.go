@@ -38,6 +38,9 @@ func processSlashings(s abstract.BeaconState, slashingMultiplier uint64) error {
 		slashing = totalBalance
 	}
 	beaconConfig := s.BeaconConfig()
+	if s.Version() >= clparams.ElectraVersion {
+		return processSlashingsElectra(s, epoch, totalBalance, slashing)
+	}
 	// Apply penalties to validators who have been slashed and reached the withdrawable epoch
 	return threading.ParallellForLoop(runtime.NumCPU(), 0, s.ValidatorSet().Length(), func(i int) error {
 		validator := s.ValidatorSet().Get(i)
@@ -55,6 +58,21 @@ func processSlashings(s abstract.BeaconState, slashingMultiplier uint64) error {
 	})
 }
 
+// processSlashingsElectra computes the penalty per effective balance increment once, which avoids the precision loss of the former formula.
+func processSlashingsElectra(s abstract.BeaconState, epoch, totalBalance, slashing uint64) error {
+	beaconConfig := s.BeaconConfig()
+	increment := beaconConfig.EffectiveBalanceIncrement
+	penaltyPerEffectiveBalanceIncrement := slashing / (totalBalance / increment)
+	return threading.ParallellForLoop(runtime.NumCPU(), 0, s.ValidatorSet().Length(), func(i int) error {
+		validator := s.ValidatorSet().Get(i)
+		if !validator.Slashed() || epoch+beaconConfig.EpochsPerSlashingsVector/2 != validator.WithdrawableEpoch() {
+			return nil
+		}
+		penalty := penaltyPerEffectiveBalanceIncrement * (validator.EffectiveBalance() / increment)
+		return state.DecreaseBalance(s, uint64(i), penalty)
+	})
+}
+
 func ProcessSlashings(state abstract.BeaconState) error {
 	// Depending on the version of the state, use different multipliers
 	switch state.Version() {
@@ -59,7 +59,7 @@ func ProcessBlock(impl BlockProcessor, s abstract.BeaconState, block cltypes.Gen
 	if version >= clparams.BellatrixVersion && executionEnabled(s, payloadHeader.BlockHash) {
 		if s.Version() >= clparams.CapellaVersion {
 			// Process withdrawals in the execution payload.
-			expect := state.ExpectedWithdrawals(s, state.Epoch(s))
+			expect, _ := state.ExpectedWithdrawals(s, state.Epoch(s))
 			expectWithdrawals := solid.NewStaticListSSZ[*cltypes.Withdrawal](int(s.BeaconConfig().MaxWithdrawalsPerPayload), 44)
 			for i := range expect {
 				expectWithdrawals.Append(expect[i])
@@ -173,9 +173,46 @@ func ProcessOperations(impl BlockOperationProcessor, s abstract.BeaconState, blo
 	}
 	signatures, messages, publicKeys = append(signatures, sigs...), append(messages, msgs...), append(publicKeys, pubKeys...)
 
+	if s.Version() < clparams.ElectraVersion {
+		return
+	}
+
+	// Process the execution layer requests. this will only have entries after the electra fork.
+	if err = processExecutionRequests(impl, s, blockBody); err != nil {
+		return nil, nil, nil, err
+	}
 	return
 }
 
+func processExecutionRequests(impl BlockOperationProcessor, s abstract.BeaconState, blockBody cltypes.GenericBeaconBody) error {
+	requests := blockBody.GetExecutionRequests()
+	if requests == nil {
+		return nil
+	}
+	if err := solid.RangeErr[*cltypes.DepositRequest](requests.Deposits, func(index int, request *cltypes.DepositRequest, length int) error {
+		if err := impl.ProcessDepositRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessDepositRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := solid.RangeErr[*cltypes.WithdrawalRequest](requests.Withdrawals, func(index int, request *cltypes.WithdrawalRequest, length int) error {
+		if err := impl.ProcessWithdrawalRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessWithdrawalRequest: %s", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return solid.RangeErr[*cltypes.ConsolidationRequest](requests.Consolidations, func(index int, request *cltypes.ConsolidationRequest, length int) error {
+		if err := impl.ProcessConsolidationRequest(s, request); err != nil {
+			return fmt.Errorf("ProcessConsolidationRequest: %s", err)
+		}
+		return nil
+	})
+}
+
 func processRandao(impl BlockProcessor, s abstract.BeaconState, body cltypes.GenericBeaconBody, block cltypes.GenericBeaconBlock) (sigs [][]byte, msgs [][]byte, pubKeys [][]byte, err error) {
 	// Process RANDAO reveal.
 	proposerIndex := block.GetProposerIndex()
@@ -325,7 +362,15 @@ func processBlsToExecutionChanges(impl BlockOperationProcessor, s abstract.Beaco
 }
 
 func maximumDeposits(s abstract.BeaconState) (maxDeposits uint64) {
-	maxDeposits = s.Eth1Data().DepositCount - s.Eth1DepositIndex()
+	depositIndexLimit := s.Eth1Data().DepositCount
+	if s.Version() >= clparams.ElectraVersion {
+		// After electra, legacy eth1 deposits stop once the execution layer deposit requests take over.
+		depositIndexLimit = min(depositIndexLimit, s.DepositRequestsStartIndex())
+	}
+	if s.Eth1DepositIndex() >= depositIndexLimit {
+		return 0
+	}
+	maxDeposits = depositIndexLimit - s.Eth1DepositIndex()
 	if maxDeposits > s.BeaconConfig().MaxDeposits {
 		maxDeposits = s.BeaconConfig().MaxDeposits
 	}
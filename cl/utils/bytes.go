@@ -190,3 +190,12 @@ func ExtractSlotFromSerializedBeaconState(beaconState []byte) (uint64, error) {
 	}
 	return binary.LittleEndian.Uint64(beaconState[40:48]), nil
 }
+
+// ExtractSlotFromSerializedSignedBeaconBlock reads the slot of an SSZ encoded signed beacon block, the message offset and
+// the signature come first.
+func ExtractSlotFromSerializedSignedBeaconBlock(signedBlock []byte) (uint64, error) {
+	if len(signedBlock) < 108 {
+		return 0, errors.New("signed block read failed, too short")
+	}
+	return binary.LittleEndian.Uint64(signedBlock[100:108]), nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+)
+
+var (
+	ErrInvalidPassword    = errors.New("keystore: invalid password")
+	ErrUnsupportedVersion = errors.New("keystore: unsupported version")
+)
+
+const keystoreVersion = 4
+
+// Keystore is an EIP-2335 BLS12-381 keystore.
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description,omitempty"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+type Crypto struct {
+	Kdf      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Module is one of the kdf, checksum and cipher modules of the keystore.
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	Dklen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	Dklen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Parse decodes a keystore from its JSON representation.
+func Parse(data []byte) (*Keystore, error) {
+	k := &Keystore{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, fmt.Errorf("keystore: %w", err)
+	}
+	if k.Version != keystoreVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if _, err := k.PublicKey(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// PublicKey returns the public key declared in the keystore.
+func (k *Keystore) PublicKey() (libcommon.Bytes48, error) {
+	var pk libcommon.Bytes48
+	decoded, err := hex.DecodeString(strings.TrimPrefix(k.Pubkey, "0x"))
+	if err != nil {
+		return pk, fmt.Errorf("keystore: invalid pubkey: %w", err)
+	}
+	if len(decoded) != len(pk) {
+		return pk, fmt.Errorf("keystore: invalid pubkey length %d", len(decoded))
+	}
+	copy(pk[:], decoded)
+	return pk, nil
+}
+
+// Decrypt returns the secret key stored in the keystore.
+func (k *Keystore) Decrypt(password string) ([]byte, error) {
+	decryptionKey, err := k.deriveKey(normalizePassword(password))
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := decodeHex(k.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	if k.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("keystore: unsupported checksum function %s", k.Crypto.Checksum.Function)
+	}
+	expectedChecksum, err := decodeHex(k.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(append(append([]byte{}, decryptionKey[16:32]...), cipherMessage...))
+	if !bytes.Equal(checksum[:], expectedChecksum) {
+		return nil, ErrInvalidPassword
+	}
+
+	if k.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("keystore: unsupported cipher function %s", k.Crypto.Cipher.Function)
+	}
+	var params cipherParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &params); err != nil {
+		return nil, fmt.Errorf("keystore: invalid cipher params: %w", err)
+	}
+	iv, err := decodeHex(params.IV)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(decryptionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("keystore: invalid iv length %d", len(iv))
+	}
+	secret := make([]byte, len(cipherMessage))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherMessage)
+	return secret, nil
+}
+
+func (k *Keystore) deriveKey(password []byte) ([]byte, error) {
+	switch k.Crypto.Kdf.Function {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, fmt.Errorf("keystore: invalid scrypt params: %w", err)
+		}
+		if params.Dklen < 32 {
+			return nil, fmt.Errorf("keystore: dklen %d too short", params.Dklen)
+		}
+		salt, err := decodeHex(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		return scrypt.Key(password, salt, params.N, params.R, params.P, params.Dklen)
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, fmt.Errorf("keystore: invalid pbkdf2 params: %w", err)
+		}
+		if params.Prf != "hmac-sha256" {
+			return nil, fmt.Errorf("keystore: unsupported prf %s", params.Prf)
+		}
+		if params.Dklen < 32 {
+			return nil, fmt.Errorf("keystore: dklen %d too short", params.Dklen)
+		}
+		salt, err := decodeHex(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(password, salt, params.C, params.Dklen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("keystore: unsupported kdf function %s", k.Crypto.Kdf.Function)
+	}
+}
+
+// normalizePassword applies the EIP-2335 password processing: NFKD normalization and removal of control codes.
+func normalizePassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	out := make([]byte, 0, len(normalized))
+	for _, r := range normalized {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			continue
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+func decodeHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("keystore: invalid hex: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package slashing_protection
+
+import (
+	"errors"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+)
+
+// InterchangeFormatVersion is the version of the EIP-3076 interchange format.
+const InterchangeFormatVersion = "5"
+
+var (
+	ErrUnsupportedInterchangeVersion = errors.New("unsupported interchange format version")
+	ErrGenesisValidatorsRootMismatch = errors.New("interchange genesis validators root mismatch")
+)
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string         `json:"interchange_format_version"`
+	GenesisValidatorsRoot    libcommon.Hash `json:"genesis_validators_root"`
+}
+
+type InterchangeData struct {
+	Pubkey             libcommon.Bytes48   `json:"pubkey"`
+	SignedBlocks       []SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation `json:"signed_attestations"`
+}
+
+type SignedBlock struct {
+	Slot        uint64          `json:"slot,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
+
+type SignedAttestation struct {
+	SourceEpoch uint64          `json:"source_epoch,string"`
+	TargetEpoch uint64          `json:"target_epoch,string"`
+	SigningRoot *libcommon.Hash `json:"signing_root,omitempty"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package slashing_protection
+
+import (
+	"context"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+)
+
+// SlashingProtection keeps track of the blocks and attestations signed by the validators, refusing any signature
+// that could get them slashed (EIP-3076).
+//
+//go:generate mockgen -typed=true -destination=./mock_services/slashing_protection_mock.go -package=mock_services . SlashingProtection
+type SlashingProtection interface {
+	// CheckAndRecordBlock records the block proposal, it returns ErrSlashableBlock if the block must not be signed.
+	CheckAndRecordBlock(ctx context.Context, pubkey libcommon.Bytes48, slot uint64, signingRoot libcommon.Hash) error
+	// CheckAndRecordAttestation records the attestation, it returns ErrSlashableAttestation if the attestation must not be signed.
+	CheckAndRecordAttestation(ctx context.Context, pubkey libcommon.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot libcommon.Hash) error
+	// ImportInterchange merges an EIP-3076 interchange into the database.
+	ImportInterchange(ctx context.Context, interchange *Interchange) error
+	// ExportInterchange exports the history of the given public keys, or of all keys if pubkeys is empty.
+	ExportInterchange(ctx context.Context, pubkeys []libcommon.Bytes48) (*Interchange, error)
+}
@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/erigontech/erigon/cl/validator/slashing_protection (interfaces: SlashingProtection)
+//
+// Generated by this command:
+//
+//	mockgen -typed=true -destination=./mock_services/slashing_protection_mock.go -package=mock_services . SlashingProtection
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	common "github.com/erigontech/erigon-lib/common"
+	slashing_protection "github.com/erigontech/erigon/cl/validator/slashing_protection"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSlashingProtection is a mock of SlashingProtection interface.
+type MockSlashingProtection struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlashingProtectionMockRecorder
+	isgomock struct{}
+}
+
+// MockSlashingProtectionMockRecorder is the mock recorder for MockSlashingProtection.
+type MockSlashingProtectionMockRecorder struct {
+	mock *MockSlashingProtection
+}
+
+// NewMockSlashingProtection creates a new mock instance.
+func NewMockSlashingProtection(ctrl *gomock.Controller) *MockSlashingProtection {
+	mock := &MockSlashingProtection{ctrl: ctrl}
+	mock.recorder = &MockSlashingProtectionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSlashingProtection) EXPECT() *MockSlashingProtectionMockRecorder {
+	return m.recorder
+}
+
+// CheckAndRecordAttestation mocks base method.
+func (m *MockSlashingProtection) CheckAndRecordAttestation(ctx context.Context, pubkey common.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAndRecordAttestation", ctx, pubkey, sourceEpoch, targetEpoch, signingRoot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAndRecordAttestation indicates an expected call of CheckAndRecordAttestation.
+func (mr *MockSlashingProtectionMockRecorder) CheckAndRecordAttestation(ctx, pubkey, sourceEpoch, targetEpoch, signingRoot any) *MockSlashingProtectionCheckAndRecordAttestationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAndRecordAttestation", reflect.TypeOf((*MockSlashingProtection)(nil).CheckAndRecordAttestation), ctx, pubkey, sourceEpoch, targetEpoch, signingRoot)
+	return &MockSlashingProtectionCheckAndRecordAttestationCall{Call: call}
+}
+
+// MockSlashingProtectionCheckAndRecordAttestationCall wrap *gomock.Call
+type MockSlashingProtectionCheckAndRecordAttestationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSlashingProtectionCheckAndRecordAttestationCall) Return(arg0 error) *MockSlashingProtectionCheckAndRecordAttestationCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSlashingProtectionCheckAndRecordAttestationCall) Do(f func(context.Context, common.Bytes48, uint64, uint64, common.Hash) error) *MockSlashingProtectionCheckAndRecordAttestationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSlashingProtectionCheckAndRecordAttestationCall) DoAndReturn(f func(context.Context, common.Bytes48, uint64, uint64, common.Hash) error) *MockSlashingProtectionCheckAndRecordAttestationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CheckAndRecordBlock mocks base method.
+func (m *MockSlashingProtection) CheckAndRecordBlock(ctx context.Context, pubkey common.Bytes48, slot uint64, signingRoot common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAndRecordBlock", ctx, pubkey, slot, signingRoot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAndRecordBlock indicates an expected call of CheckAndRecordBlock.
+func (mr *MockSlashingProtectionMockRecorder) CheckAndRecordBlock(ctx, pubkey, slot, signingRoot any) *MockSlashingProtectionCheckAndRecordBlockCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAndRecordBlock", reflect.TypeOf((*MockSlashingProtection)(nil).CheckAndRecordBlock), ctx, pubkey, slot, signingRoot)
+	return &MockSlashingProtectionCheckAndRecordBlockCall{Call: call}
+}
+
+// MockSlashingProtectionCheckAndRecordBlockCall wrap *gomock.Call
+type MockSlashingProtectionCheckAndRecordBlockCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSlashingProtectionCheckAndRecordBlockCall) Return(arg0 error) *MockSlashingProtectionCheckAndRecordBlockCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSlashingProtectionCheckAndRecordBlockCall) Do(f func(context.Context, common.Bytes48, uint64, common.Hash) error) *MockSlashingProtectionCheckAndRecordBlockCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSlashingProtectionCheckAndRecordBlockCall) DoAndReturn(f func(context.Context, common.Bytes48, uint64, common.Hash) error) *MockSlashingProtectionCheckAndRecordBlockCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ExportInterchange mocks base method.
+func (m *MockSlashingProtection) ExportInterchange(ctx context.Context, pubkeys []common.Bytes48) (*slashing_protection.Interchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportInterchange", ctx, pubkeys)
+	ret0, _ := ret[0].(*slashing_protection.Interchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportInterchange indicates an expected call of ExportInterchange.
+func (mr *MockSlashingProtectionMockRecorder) ExportInterchange(ctx, pubkeys any) *MockSlashingProtectionExportInterchangeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportInterchange", reflect.TypeOf((*MockSlashingProtection)(nil).ExportInterchange), ctx, pubkeys)
+	return &MockSlashingProtectionExportInterchangeCall{Call: call}
+}
+
+// MockSlashingProtectionExportInterchangeCall wrap *gomock.Call
+type MockSlashingProtectionExportInterchangeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSlashingProtectionExportInterchangeCall) Return(arg0 *slashing_protection.Interchange, arg1 error) *MockSlashingProtectionExportInterchangeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSlashingProtectionExportInterchangeCall) Do(f func(context.Context, []common.Bytes48) (*slashing_protection.Interchange, error)) *MockSlashingProtectionExportInterchangeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSlashingProtectionExportInterchangeCall) DoAndReturn(f func(context.Context, []common.Bytes48) (*slashing_protection.Interchange, error)) *MockSlashingProtectionExportInterchangeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ImportInterchange mocks base method.
+func (m *MockSlashingProtection) ImportInterchange(ctx context.Context, interchange *slashing_protection.Interchange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportInterchange", ctx, interchange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportInterchange indicates an expected call of ImportInterchange.
+func (mr *MockSlashingProtectionMockRecorder) ImportInterchange(ctx, interchange any) *MockSlashingProtectionImportInterchangeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportInterchange", reflect.TypeOf((*MockSlashingProtection)(nil).ImportInterchange), ctx, interchange)
+	return &MockSlashingProtectionImportInterchangeCall{Call: call}
+}
+
+// MockSlashingProtectionImportInterchangeCall wrap *gomock.Call
+type MockSlashingProtectionImportInterchangeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSlashingProtectionImportInterchangeCall) Return(arg0 error) *MockSlashingProtectionImportInterchangeCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSlashingProtectionImportInterchangeCall) Do(f func(context.Context, *slashing_protection.Interchange) error) *MockSlashingProtectionImportInterchangeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSlashingProtectionImportInterchangeCall) DoAndReturn(f func(context.Context, *slashing_protection.Interchange) error) *MockSlashingProtectionImportInterchangeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
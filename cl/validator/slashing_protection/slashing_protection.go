@@ -0,0 +1,293 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package slashing_protection
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+)
+
+var (
+	ErrSlashableBlock       = errors.New("slashing protection: block is slashable")
+	ErrSlashableAttestation = errors.New("slashing protection: attestation is slashable")
+)
+
+// slashingProtection keeps, for every validator, only the latest signed block and the highest source and target
+// epochs of its signed attestations. This is the "minimal" strategy of EIP-3076: refusing anything at or below these
+// watermarks is enough to prevent double proposals, double votes and surround votes.
+type slashingProtection struct {
+	db                    kv.RwDB
+	genesisValidatorsRoot libcommon.Hash
+}
+
+type blockRecord struct {
+	slot        uint64
+	signingRoot libcommon.Hash
+}
+
+type attestationRecord struct {
+	sourceEpoch uint64
+	targetEpoch uint64
+	signingRoot libcommon.Hash
+}
+
+// NewSlashingProtection creates a slashing protection database backed by db.
+func NewSlashingProtection(db kv.RwDB, genesisValidatorsRoot libcommon.Hash) SlashingProtection {
+	return &slashingProtection{
+		db:                    db,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}
+}
+
+func (s *slashingProtection) CheckAndRecordBlock(ctx context.Context, pubkey libcommon.Bytes48, slot uint64, signingRoot libcommon.Hash) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		last, ok, err := readBlockRecord(tx, pubkey)
+		if err != nil {
+			return err
+		}
+		if ok && slot <= last.slot {
+			// re-signing the very same block is safe
+			if slot == last.slot && signingRoot != (libcommon.Hash{}) && signingRoot == last.signingRoot {
+				return nil
+			}
+			return fmt.Errorf("%w: slot %d, last signed slot %d", ErrSlashableBlock, slot, last.slot)
+		}
+		return writeBlockRecord(tx, pubkey, blockRecord{slot: slot, signingRoot: signingRoot})
+	})
+}
+
+func (s *slashingProtection) CheckAndRecordAttestation(ctx context.Context, pubkey libcommon.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot libcommon.Hash) error {
+	if sourceEpoch > targetEpoch {
+		return fmt.Errorf("%w: source epoch %d after target epoch %d", ErrSlashableAttestation, sourceEpoch, targetEpoch)
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		last, ok, err := readAttestationRecord(tx, pubkey)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if targetEpoch == last.targetEpoch && sourceEpoch == last.sourceEpoch &&
+				signingRoot != (libcommon.Hash{}) && signingRoot == last.signingRoot {
+				return nil
+			}
+			if targetEpoch <= last.targetEpoch {
+				return fmt.Errorf("%w: target epoch %d, last signed target epoch %d", ErrSlashableAttestation, targetEpoch, last.targetEpoch)
+			}
+			if sourceEpoch < last.sourceEpoch {
+				return fmt.Errorf("%w: source epoch %d, last signed source epoch %d", ErrSlashableAttestation, sourceEpoch, last.sourceEpoch)
+			}
+		}
+		return writeAttestationRecord(tx, pubkey, attestationRecord{sourceEpoch: sourceEpoch, targetEpoch: targetEpoch, signingRoot: signingRoot})
+	})
+}
+
+func (s *slashingProtection) ImportInterchange(ctx context.Context, interchange *Interchange) error {
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf("%w: %s", ErrUnsupportedInterchangeVersion, interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisValidatorsRoot != s.genesisValidatorsRoot {
+		return ErrGenesisValidatorsRootMismatch
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		for _, data := range interchange.Data {
+			if err := importBlocks(tx, data.Pubkey, data.SignedBlocks); err != nil {
+				return err
+			}
+			if err := importAttestations(tx, data.Pubkey, data.SignedAttestations); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *slashingProtection) ExportInterchange(ctx context.Context, pubkeys []libcommon.Bytes48) (*Interchange, error) {
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    s.genesisValidatorsRoot,
+		},
+		Data: []InterchangeData{},
+	}
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		if len(pubkeys) == 0 {
+			var err error
+			if pubkeys, err = allPubkeys(tx); err != nil {
+				return err
+			}
+		}
+		for _, pubkey := range pubkeys {
+			data := InterchangeData{
+				Pubkey:             pubkey,
+				SignedBlocks:       []SignedBlock{},
+				SignedAttestations: []SignedAttestation{},
+			}
+			block, ok, err := readBlockRecord(tx, pubkey)
+			if err != nil {
+				return err
+			}
+			if ok {
+				data.SignedBlocks = append(data.SignedBlocks, SignedBlock{Slot: block.slot, SigningRoot: optionalRoot(block.signingRoot)})
+			}
+			attestation, ok, err := readAttestationRecord(tx, pubkey)
+			if err != nil {
+				return err
+			}
+			if ok {
+				data.SignedAttestations = append(data.SignedAttestations, SignedAttestation{
+					SourceEpoch: attestation.sourceEpoch,
+					TargetEpoch: attestation.targetEpoch,
+					SigningRoot: optionalRoot(attestation.signingRoot),
+				})
+			}
+			interchange.Data = append(interchange.Data, data)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return interchange, nil
+}
+
+// importBlocks raises the block watermark of pubkey to the highest imported slot.
+func importBlocks(tx kv.RwTx, pubkey libcommon.Bytes48, blocks []SignedBlock) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	record, ok, err := readBlockRecord(tx, pubkey)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, block := range blocks {
+		if ok && block.Slot <= record.slot {
+			continue
+		}
+		record, ok, changed = blockRecord{slot: block.Slot}, true, true
+		if block.SigningRoot != nil {
+			record.signingRoot = *block.SigningRoot
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return writeBlockRecord(tx, pubkey, record)
+}
+
+// importAttestations raises the source and target watermarks of pubkey to the highest imported epochs.
+func importAttestations(tx kv.RwTx, pubkey libcommon.Bytes48, attestations []SignedAttestation) error {
+	if len(attestations) == 0 {
+		return nil
+	}
+	record, ok, err := readAttestationRecord(tx, pubkey)
+	if err != nil {
+		return err
+	}
+	merged := record
+	for i, attestation := range attestations {
+		if !ok && i == 0 {
+			merged = attestationRecord{sourceEpoch: attestation.SourceEpoch, targetEpoch: attestation.TargetEpoch}
+		}
+		merged.sourceEpoch = max(merged.sourceEpoch, attestation.SourceEpoch)
+		merged.targetEpoch = max(merged.targetEpoch, attestation.TargetEpoch)
+	}
+	if ok && merged == record {
+		return nil
+	}
+	// the signing root is kept only if a single attestation holds both watermarks, so that it can be re-signed.
+	merged.signingRoot = libcommon.Hash{}
+	if ok && record.sourceEpoch == merged.sourceEpoch && record.targetEpoch == merged.targetEpoch {
+		merged.signingRoot = record.signingRoot
+	}
+	for _, attestation := range attestations {
+		if attestation.SourceEpoch == merged.sourceEpoch && attestation.TargetEpoch == merged.targetEpoch && attestation.SigningRoot != nil {
+			merged.signingRoot = *attestation.SigningRoot
+		}
+	}
+	return writeAttestationRecord(tx, pubkey, merged)
+}
+
+func readBlockRecord(tx kv.Tx, pubkey libcommon.Bytes48) (blockRecord, bool, error) {
+	v, err := tx.GetOne(kv.SlashingProtectionBlocks, pubkey[:])
+	if err != nil || len(v) == 0 {
+		return blockRecord{}, false, err
+	}
+	if len(v) != 8+length.Hash {
+		return blockRecord{}, false, fmt.Errorf("slashing protection: corrupted block record for %x", pubkey)
+	}
+	return blockRecord{slot: binary.BigEndian.Uint64(v), signingRoot: libcommon.BytesToHash(v[8:])}, true, nil
+}
+
+func writeBlockRecord(tx kv.RwTx, pubkey libcommon.Bytes48, record blockRecord) error {
+	v := make([]byte, 8, 8+length.Hash)
+	binary.BigEndian.PutUint64(v, record.slot)
+	return tx.Put(kv.SlashingProtectionBlocks, pubkey[:], append(v, record.signingRoot[:]...))
+}
+
+func readAttestationRecord(tx kv.Tx, pubkey libcommon.Bytes48) (attestationRecord, bool, error) {
+	v, err := tx.GetOne(kv.SlashingProtectionAttestations, pubkey[:])
+	if err != nil || len(v) == 0 {
+		return attestationRecord{}, false, err
+	}
+	if len(v) != 16+length.Hash {
+		return attestationRecord{}, false, fmt.Errorf("slashing protection: corrupted attestation record for %x", pubkey)
+	}
+	return attestationRecord{
+		sourceEpoch: binary.BigEndian.Uint64(v),
+		targetEpoch: binary.BigEndian.Uint64(v[8:]),
+		signingRoot: libcommon.BytesToHash(v[16:]),
+	}, true, nil
+}
+
+func writeAttestationRecord(tx kv.RwTx, pubkey libcommon.Bytes48, record attestationRecord) error {
+	v := make([]byte, 16, 16+length.Hash)
+	binary.BigEndian.PutUint64(v, record.sourceEpoch)
+	binary.BigEndian.PutUint64(v[8:], record.targetEpoch)
+	return tx.Put(kv.SlashingProtectionAttestations, pubkey[:], append(v, record.signingRoot[:]...))
+}
+
+func allPubkeys(tx kv.Tx) ([]libcommon.Bytes48, error) {
+	seen := map[libcommon.Bytes48]struct{}{}
+	pubkeys := []libcommon.Bytes48{}
+	for _, table := range []string{kv.SlashingProtectionBlocks, kv.SlashingProtectionAttestations} {
+		if err := tx.ForEach(table, nil, func(k, _ []byte) error {
+			var pubkey libcommon.Bytes48
+			copy(pubkey[:], k)
+			if _, ok := seen[pubkey]; !ok {
+				seen[pubkey] = struct{}{}
+				pubkeys = append(pubkeys, pubkey)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return pubkeys, nil
+}
+
+func optionalRoot(root libcommon.Hash) *libcommon.Hash {
+	if root == (libcommon.Hash{}) {
+		return nil
+	}
+	return &root
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package slashing_protection
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+)
+
+var (
+	testGenesisValidatorsRoot = libcommon.HexToHash("0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+	testPubkey                = libcommon.Bytes48{0xb8, 0x45, 0x08, 0x9a}
+)
+
+func TestSlashingProtectionBlocks(t *testing.T) {
+	ctx := context.Background()
+	s := NewSlashingProtection(memdb.NewTestDB(t), testGenesisValidatorsRoot)
+
+	require.NoError(t, s.CheckAndRecordBlock(ctx, testPubkey, 10, libcommon.Hash{1}))
+	// same block can be re-signed
+	require.NoError(t, s.CheckAndRecordBlock(ctx, testPubkey, 10, libcommon.Hash{1}))
+	// double proposal
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, testPubkey, 10, libcommon.Hash{2}), ErrSlashableBlock)
+	// below watermark
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, testPubkey, 9, libcommon.Hash{3}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(ctx, testPubkey, 11, libcommon.Hash{4}))
+	// other validators are independent
+	require.NoError(t, s.CheckAndRecordBlock(ctx, libcommon.Bytes48{1}, 1, libcommon.Hash{5}))
+}
+
+func TestSlashingProtectionAttestations(t *testing.T) {
+	ctx := context.Background()
+	s := NewSlashingProtection(memdb.NewTestDB(t), testGenesisValidatorsRoot)
+
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, testPubkey, 2, 3, libcommon.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, testPubkey, 2, 3, libcommon.Hash{1}))
+	// double vote
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, testPubkey, 2, 3, libcommon.Hash{2}), ErrSlashableAttestation)
+	// surrounded vote
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, testPubkey, 3, 6, libcommon.Hash{3}))
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, testPubkey, 4, 5, libcommon.Hash{4}), ErrSlashableAttestation)
+	// surrounding vote
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, testPubkey, 2, 7, libcommon.Hash{5}), ErrSlashableAttestation)
+	// source after target
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, testPubkey, 9, 8, libcommon.Hash{6}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, testPubkey, 6, 7, libcommon.Hash{7}))
+}
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	ctx := context.Background()
+	interchangeJson := `{
+  "metadata": {
+    "interchange_format_version": "5",
+    "genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+  },
+  "data": [
+    {
+      "pubkey": "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed",
+      "signed_blocks": [
+        {"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"},
+        {"slot": "81951"}
+      ],
+      "signed_attestations": [
+        {"source_epoch": "2290", "target_epoch": "3007", "signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"},
+        {"source_epoch": "2290", "target_epoch": "3008"}
+      ]
+    }
+  ]
+}`
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal([]byte(interchangeJson), &interchange))
+
+	s := NewSlashingProtection(memdb.NewTestDB(t), testGenesisValidatorsRoot)
+	require.NoError(t, s.ImportInterchange(ctx, &interchange))
+	pubkey := interchange.Data[0].Pubkey
+
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 81952, libcommon.Hash{1}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 81952, libcommon.HexToHash("0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b")))
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2290, 3008, libcommon.Hash{1}), ErrSlashableAttestation)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2289, 3009, libcommon.Hash{1}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2290, 3009, libcommon.Hash{1}))
+
+	exported, err := s.ExportInterchange(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 1)
+	require.Equal(t, pubkey, exported.Data[0].Pubkey)
+	require.Equal(t, uint64(81952), exported.Data[0].SignedBlocks[0].Slot)
+	require.Equal(t, uint64(3009), exported.Data[0].SignedAttestations[0].TargetEpoch)
+
+	// the exported interchange can be imported into a fresh database
+	fresh := NewSlashingProtection(memdb.NewTestDB(t), testGenesisValidatorsRoot)
+	require.NoError(t, fresh.ImportInterchange(ctx, exported))
+	require.ErrorIs(t, fresh.CheckAndRecordAttestation(ctx, pubkey, 2290, 3009, libcommon.Hash{2}), ErrSlashableAttestation)
+
+	// interchange of another network is refused
+	other := NewSlashingProtection(memdb.NewTestDB(t), libcommon.Hash{1})
+	require.ErrorIs(t, other.ImportInterchange(ctx, &interchange), ErrGenesisValidatorsRootMismatch)
+}
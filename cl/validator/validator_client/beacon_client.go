@@ -0,0 +1,260 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
+
+// maxValidatorsPerLookup is the maximum amount of ids the beacon API accepts in a single validators lookup.
+const maxValidatorsPerLookup = 128
+
+type attesterDuty struct {
+	Pubkey                  libcommon.Bytes48 `json:"pubkey"`
+	ValidatorIndex          uint64            `json:"validator_index,string"`
+	CommitteeIndex          uint64            `json:"committee_index,string"`
+	CommitteeLength         uint64            `json:"committee_length,string"`
+	ValidatorCommitteeIndex uint64            `json:"validator_committee_index,string"`
+	CommitteesAtSlot        uint64            `json:"committees_at_slot,string"`
+	Slot                    uint64            `json:"slot,string"`
+}
+
+type proposerDuty struct {
+	Pubkey         libcommon.Bytes48 `json:"pubkey"`
+	ValidatorIndex uint64            `json:"validator_index,string"`
+	Slot           uint64            `json:"slot,string"`
+}
+
+type validatorPreparation struct {
+	ValidatorIndex uint64            `json:"validator_index,string"`
+	FeeRecipient   libcommon.Address `json:"fee_recipient"`
+}
+
+// producedBlock is the answer of the v3 block production endpoint, only one of Block and BlindedBlock is set.
+type producedBlock struct {
+	Version      clparams.StateVersion
+	Block        *cltypes.DenebBeaconBlock
+	BlindedBlock *cltypes.BlindedBeaconBlock
+}
+
+// beaconClient is a minimal client of the standard beacon node API, as used by the validator client.
+type beaconClient struct {
+	url       string
+	beaconCfg *clparams.BeaconChainConfig
+	client    *http.Client
+}
+
+func newBeaconClient(url string, beaconCfg *clparams.BeaconChainConfig) *beaconClient {
+	return &beaconClient{
+		url:       strings.TrimSuffix(url, "/"),
+		beaconCfg: beaconCfg,
+		client:    &http.Client{Timeout: 12 * time.Second},
+	}
+}
+
+func (b *beaconClient) do(ctx context.Context, method, path string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.url+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, resp.Header, nil
+}
+
+// getData performs a JSON request and decodes the "data" field of the response into out.
+func (b *beaconClient) getData(ctx context.Context, method, path string, request, out any) error {
+	var body []byte
+	if request != nil {
+		var err error
+		if body, err = json.Marshal(request); err != nil {
+			return err
+		}
+	}
+	respBody, _, err := b.do(ctx, method, path, body, nil)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	resp := struct {
+		Data any `json:"data"`
+	}{Data: out}
+	return json.Unmarshal(respBody, &resp)
+}
+
+// validatorIndicies resolves the indicies of the given public keys, unknown keys are omitted from the result.
+func (b *beaconClient) validatorIndicies(ctx context.Context, pubkeys []libcommon.Bytes48) (map[libcommon.Bytes48]uint64, error) {
+	indicies := make(map[libcommon.Bytes48]uint64, len(pubkeys))
+	for start := 0; start < len(pubkeys); start += maxValidatorsPerLookup {
+		end := min(start+maxValidatorsPerLookup, len(pubkeys))
+		ids := make([]string, 0, end-start)
+		for _, pubkey := range pubkeys[start:end] {
+			ids = append(ids, pubkey.Hex())
+		}
+		var validators []struct {
+			Index     uint64 `json:"index,string"`
+			Validator struct {
+				Pubkey libcommon.Bytes48 `json:"pubkey"`
+			} `json:"validator"`
+		}
+		if err := b.getData(ctx, http.MethodPost, "/eth/v1/beacon/states/head/validators", map[string][]string{"ids": ids}, &validators); err != nil {
+			return nil, err
+		}
+		for _, v := range validators {
+			indicies[v.Validator.Pubkey] = v.Index
+		}
+	}
+	return indicies, nil
+}
+
+func (b *beaconClient) attesterDuties(ctx context.Context, epoch uint64, indicies []uint64) ([]attesterDuty, error) {
+	ids := make([]string, 0, len(indicies))
+	for _, index := range indicies {
+		ids = append(ids, strconv.FormatUint(index, 10))
+	}
+	var duties []attesterDuty
+	if err := b.getData(ctx, http.MethodPost, fmt.Sprintf("/eth/v1/validator/duties/attester/%d", epoch), ids, &duties); err != nil {
+		return nil, err
+	}
+	return duties, nil
+}
+
+func (b *beaconClient) proposerDuties(ctx context.Context, epoch uint64) ([]proposerDuty, error) {
+	var duties []proposerDuty
+	if err := b.getData(ctx, http.MethodGet, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), nil, &duties); err != nil {
+		return nil, err
+	}
+	return duties, nil
+}
+
+func (b *beaconClient) prepareBeaconProposer(ctx context.Context, preparations []validatorPreparation) error {
+	return b.getData(ctx, http.MethodPost, "/eth/v1/validator/prepare_beacon_proposer", preparations, nil)
+}
+
+func (b *beaconClient) subscribeToBeaconCommittees(ctx context.Context, subscriptions []*cltypes.BeaconCommitteeSubscription) error {
+	return b.getData(ctx, http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", subscriptions, nil)
+}
+
+func (b *beaconClient) attestationData(ctx context.Context, slot, committeeIndex uint64) (*solid.AttestationData, error) {
+	data := &solid.AttestationData{}
+	if err := b.getData(ctx, http.MethodGet, fmt.Sprintf("/eth/v1/validator/attestation_data?slot=%d&committee_index=%d", slot, committeeIndex), nil, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *beaconClient) submitAttestations(ctx context.Context, attestations []*solid.Attestation) error {
+	return b.getData(ctx, http.MethodPost, "/eth/v1/beacon/pool/attestations", attestations, nil)
+}
+
+func (b *beaconClient) aggregateAttestation(ctx context.Context, slot uint64, attestationDataRoot libcommon.Hash) (*solid.Attestation, error) {
+	attestation := &solid.Attestation{}
+	if err := b.getData(ctx, http.MethodGet, fmt.Sprintf("/eth/v1/validator/aggregate_attestation?attestation_data_root=%s&slot=%d", attestationDataRoot.Hex(), slot), nil, attestation); err != nil {
+		return nil, err
+	}
+	return attestation, nil
+}
+
+func (b *beaconClient) submitAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error {
+	return b.getData(ctx, http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", aggregates, nil)
+}
+
+// produceBlock asks the beacon node for a block to propose, the block is transferred in SSZ as it carries the blobs.
+func (b *beaconClient) produceBlock(ctx context.Context, slot uint64, randaoReveal libcommon.Bytes96, graffiti libcommon.Hash) (*producedBlock, error) {
+	path := fmt.Sprintf("/eth/v3/validator/blocks/%d?randao_reveal=%s&graffiti=%s", slot, randaoReveal.Hex(), graffiti.Hex())
+	body, headers, err := b.do(ctx, http.MethodGet, path, nil, map[string]string{"Accept": "application/octet-stream"})
+	if err != nil {
+		return nil, err
+	}
+	version, err := clparams.StringToClVersion(headers.Get("Eth-Consensus-Version"))
+	if err != nil {
+		return nil, err
+	}
+	produced := &producedBlock{Version: version}
+	if headers.Get("Eth-Execution-Payload-Blinded") == "true" {
+		produced.BlindedBlock = cltypes.NewBlindedBeaconBlock(b.beaconCfg, version)
+		if err := produced.BlindedBlock.DecodeSSZ(body, int(version)); err != nil {
+			return nil, err
+		}
+		return produced, nil
+	}
+	produced.Block = cltypes.NewDenebBeaconBlock(b.beaconCfg, version)
+	if err := produced.Block.DecodeSSZ(body, int(version)); err != nil {
+		return nil, err
+	}
+	return produced, nil
+}
+
+// publishBlock publishes a signed block, blinded blocks are sent back to the beacon node to be unblinded through the builder.
+func (b *beaconClient) publishBlock(ctx context.Context, version clparams.StateVersion, block []byte, blinded bool) error {
+	path := "/eth/v2/beacon/blocks"
+	if blinded {
+		path = "/eth/v2/beacon/blinded_blocks"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url+path, bytes.NewReader(block))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Eth-Consensus-Version", clparams.ClVersionToString(version))
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("POST %s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/validator/keystore"
+	"github.com/erigontech/erigon/cl/validator/slashing_protection"
+)
+
+const (
+	keystoresDirName   = "keystores"
+	secretsDirName     = "secrets"
+	remoteKeysFileName = "remote_keys.json"
+)
+
+// Keymanager API statuses.
+const (
+	StatusImported  = "imported"
+	StatusDuplicate = "duplicate"
+	StatusDeleted   = "deleted"
+	StatusNotActive = "not_active"
+	StatusNotFound  = "not_found"
+	StatusError     = "error"
+)
+
+var ErrReadOnlyKey = errors.New("key is read-only")
+
+// KeystoreInfo describes a local key.
+type KeystoreInfo struct {
+	ValidatingPubkey libcommon.Bytes48 `json:"validating_pubkey"`
+	DerivationPath   string            `json:"derivation_path,omitempty"`
+	ReadOnly         bool              `json:"readonly"`
+}
+
+// RemoteKey describes a key held by a remote signer.
+type RemoteKey struct {
+	Pubkey   libcommon.Bytes48 `json:"pubkey"`
+	Url      string            `json:"url"`
+	ReadOnly bool              `json:"readonly"`
+}
+
+// OperationStatus is the per-key outcome of a Keymanager API operation.
+type OperationStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type localKey struct {
+	signer   *localSigner
+	path     string
+	readOnly bool
+}
+
+// KeyManager holds the signers of the validator client. Keys loaded from the user-provided keystores directory are
+// read-only, keys imported through the Keymanager API are persisted in the validator data directory.
+type KeyManager struct {
+	dataDir            string
+	slashingProtection slashing_protection.SlashingProtection
+	logger             log.Logger
+
+	mu         sync.RWMutex
+	localKeys  map[libcommon.Bytes48]*localKey
+	remoteKeys map[libcommon.Bytes48]*remoteSigner
+}
+
+// NewKeyManager creates a key manager and loads the keys previously imported in dataDir.
+func NewKeyManager(dataDir string, slashingProtection slashing_protection.SlashingProtection, logger log.Logger) (*KeyManager, error) {
+	k := &KeyManager{
+		dataDir:            dataDir,
+		slashingProtection: slashingProtection,
+		logger:             logger,
+		localKeys:          make(map[libcommon.Bytes48]*localKey),
+		remoteKeys:         make(map[libcommon.Bytes48]*remoteSigner),
+	}
+	for _, dir := range []string{filepath.Join(dataDir, keystoresDirName), filepath.Join(dataDir, secretsDirName)} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(dataDir, keystoresDirName))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		password, err := os.ReadFile(filepath.Join(dataDir, secretsDirName, name))
+		if err != nil {
+			return nil, fmt.Errorf("missing secret for keystore %s: %w", name, err)
+		}
+		if err := k.loadKeystore(filepath.Join(dataDir, keystoresDirName, entry.Name()), string(password), false); err != nil {
+			return nil, err
+		}
+	}
+	remoteKeys, err := os.ReadFile(filepath.Join(dataDir, remoteKeysFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(remoteKeys) > 0 {
+		var keys []RemoteKey
+		if err := json.Unmarshal(remoteKeys, &keys); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", remoteKeysFileName, err)
+		}
+		for _, key := range keys {
+			k.remoteKeys[key.Pubkey] = newRemoteSigner(key.Pubkey, key.Url)
+		}
+	}
+	return k, nil
+}
+
+// LoadKeystores loads, as read-only keys, all the keystores in dir, decrypting them with the password in passwordFile.
+func (k *KeyManager) LoadKeystores(dir, passwordFile string) error {
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := k.loadKeystore(filepath.Join(dir, entry.Name()), strings.TrimRight(string(password), "\r\n"), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KeyManager) loadKeystore(path, password string, readOnly bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ks, err := keystore.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	secret, err := ks.Decrypt(password)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	signer, err := newLocalSigner(secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.localKeys[signer.PublicKey()] = &localKey{signer: signer, path: ks.Path, readOnly: readOnly}
+	k.logger.Info("[Validator] Loaded keystore", "pubkey", signer.PublicKey(), "readonly", readOnly)
+	return nil
+}
+
+// Signers returns the signers of all the active keys.
+func (k *KeyManager) Signers() []Signer {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	signers := make([]Signer, 0, len(k.localKeys)+len(k.remoteKeys))
+	for _, key := range k.localKeys {
+		signers = append(signers, key.signer)
+	}
+	for _, signer := range k.remoteKeys {
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+// Signer returns the signer of pubkey, if it is active.
+func (k *KeyManager) Signer(pubkey libcommon.Bytes48) (Signer, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, ok := k.localKeys[pubkey]; ok {
+		return key.signer, true
+	}
+	if signer, ok := k.remoteKeys[pubkey]; ok {
+		return signer, true
+	}
+	return nil, false
+}
+
+// ListKeystores returns the local keys sorted by public key.
+func (k *KeyManager) ListKeystores() []KeystoreInfo {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	keys := make([]KeystoreInfo, 0, len(k.localKeys))
+	for pubkey, key := range k.localKeys {
+		keys = append(keys, KeystoreInfo{ValidatingPubkey: pubkey, DerivationPath: key.path, ReadOnly: key.readOnly})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ValidatingPubkey.Hex() < keys[j].ValidatingPubkey.Hex() })
+	return keys
+}
+
+// ImportKeystores imports the given EIP-2335 keystores. The slashing protection interchange, if any, is imported
+// before any key is activated.
+func (k *KeyManager) ImportKeystores(ctx context.Context, keystores, passwords []string, interchange *slashing_protection.Interchange) ([]OperationStatus, error) {
+	if len(keystores) != len(passwords) {
+		return nil, fmt.Errorf("got %d keystores and %d passwords", len(keystores), len(passwords))
+	}
+	if interchange != nil {
+		if err := k.slashingProtection.ImportInterchange(ctx, interchange); err != nil {
+			return nil, err
+		}
+	}
+	statuses := make([]OperationStatus, len(keystores))
+	for i := range keystores {
+		statuses[i] = k.importKeystore(keystores[i], passwords[i])
+	}
+	return statuses, nil
+}
+
+func (k *KeyManager) importKeystore(data, password string) OperationStatus {
+	ks, err := keystore.Parse([]byte(data))
+	if err != nil {
+		return OperationStatus{Status: StatusError, Message: err.Error()}
+	}
+	secret, err := ks.Decrypt(password)
+	if err != nil {
+		return OperationStatus{Status: StatusError, Message: err.Error()}
+	}
+	signer, err := newLocalSigner(secret)
+	if err != nil {
+		return OperationStatus{Status: StatusError, Message: err.Error()}
+	}
+	pubkey := signer.PublicKey()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.localKeys[pubkey]; ok {
+		return OperationStatus{Status: StatusDuplicate}
+	}
+	if _, ok := k.remoteKeys[pubkey]; ok {
+		return OperationStatus{Status: StatusDuplicate}
+	}
+	name := pubkey.Hex()
+	if err := os.WriteFile(filepath.Join(k.dataDir, secretsDirName, name), []byte(password), 0600); err != nil {
+		return OperationStatus{Status: StatusError, Message: err.Error()}
+	}
+	if err := os.WriteFile(filepath.Join(k.dataDir, keystoresDirName, name+".json"), []byte(data), 0600); err != nil {
+		return OperationStatus{Status: StatusError, Message: err.Error()}
+	}
+	k.localKeys[pubkey] = &localKey{signer: signer, path: ks.Path}
+	k.logger.Info("[Validator] Imported keystore", "pubkey", pubkey)
+	return OperationStatus{Status: StatusImported}
+}
+
+// DeleteKeystores deactivates and removes the given local keys and returns their slashing protection history.
+func (k *KeyManager) DeleteKeystores(ctx context.Context, pubkeys []libcommon.Bytes48) ([]OperationStatus, *slashing_protection.Interchange, error) {
+	statuses := make([]OperationStatus, len(pubkeys))
+	k.mu.Lock()
+	for i, pubkey := range pubkeys {
+		key, ok := k.localKeys[pubkey]
+		switch {
+		case !ok:
+			statuses[i] = OperationStatus{Status: StatusNotFound}
+		case key.readOnly:
+			statuses[i] = OperationStatus{Status: StatusError, Message: ErrReadOnlyKey.Error()}
+		default:
+			name := pubkey.Hex()
+			if err := removeIfExists(filepath.Join(k.dataDir, keystoresDirName, name+".json")); err != nil {
+				statuses[i] = OperationStatus{Status: StatusError, Message: err.Error()}
+				continue
+			}
+			if err := removeIfExists(filepath.Join(k.dataDir, secretsDirName, name)); err != nil {
+				statuses[i] = OperationStatus{Status: StatusError, Message: err.Error()}
+				continue
+			}
+			delete(k.localKeys, pubkey)
+			statuses[i] = OperationStatus{Status: StatusDeleted}
+			k.logger.Info("[Validator] Deleted keystore", "pubkey", pubkey)
+		}
+	}
+	k.mu.Unlock()
+
+	// the history is exported after the keys are deactivated, so that it cannot change anymore.
+	exported := make([]libcommon.Bytes48, 0, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		if statuses[i].Status == StatusDeleted || statuses[i].Status == StatusNotFound {
+			exported = append(exported, pubkey)
+		}
+	}
+	interchange, err := k.slashingProtection.ExportInterchange(ctx, exported)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := interchange.Data[:0]
+	for _, d := range interchange.Data {
+		hasHistory := len(d.SignedBlocks) > 0 || len(d.SignedAttestations) > 0
+		for i, pubkey := range pubkeys {
+			if pubkey == d.Pubkey && statuses[i].Status == StatusNotFound && hasHistory {
+				statuses[i] = OperationStatus{Status: StatusNotActive}
+			}
+		}
+		if hasHistory {
+			data = append(data, d)
+		}
+	}
+	interchange.Data = data
+	return statuses, interchange, nil
+}
+
+// ListRemoteKeys returns the remote keys sorted by public key.
+func (k *KeyManager) ListRemoteKeys() []RemoteKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.remoteKeysLocked()
+}
+
+func (k *KeyManager) remoteKeysLocked() []RemoteKey {
+	keys := make([]RemoteKey, 0, len(k.remoteKeys))
+	for pubkey, signer := range k.remoteKeys {
+		keys = append(keys, RemoteKey{Pubkey: pubkey, Url: signer.url})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Pubkey.Hex() < keys[j].Pubkey.Hex() })
+	return keys
+}
+
+// ImportRemoteKeys activates the given remote keys.
+func (k *KeyManager) ImportRemoteKeys(keys []RemoteKey) []OperationStatus {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	statuses := make([]OperationStatus, len(keys))
+	for i, key := range keys {
+		_, isLocal := k.localKeys[key.Pubkey]
+		_, isRemote := k.remoteKeys[key.Pubkey]
+		switch {
+		case isLocal || isRemote:
+			statuses[i] = OperationStatus{Status: StatusDuplicate}
+		case key.Url == "":
+			statuses[i] = OperationStatus{Status: StatusError, Message: "missing url"}
+		default:
+			k.remoteKeys[key.Pubkey] = newRemoteSigner(key.Pubkey, key.Url)
+			statuses[i] = OperationStatus{Status: StatusImported}
+		}
+	}
+	if err := k.persistRemoteKeysLocked(); err != nil {
+		for i := range statuses {
+			if statuses[i].Status == StatusImported {
+				delete(k.remoteKeys, keys[i].Pubkey)
+				statuses[i] = OperationStatus{Status: StatusError, Message: err.Error()}
+			}
+		}
+	}
+	return statuses
+}
+
+// DeleteRemoteKeys deactivates the given remote keys.
+func (k *KeyManager) DeleteRemoteKeys(pubkeys []libcommon.Bytes48) []OperationStatus {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	statuses := make([]OperationStatus, len(pubkeys))
+	deleted := make(map[libcommon.Bytes48]*remoteSigner)
+	for i, pubkey := range pubkeys {
+		signer, ok := k.remoteKeys[pubkey]
+		if !ok {
+			statuses[i] = OperationStatus{Status: StatusNotFound}
+			continue
+		}
+		deleted[pubkey] = signer
+		delete(k.remoteKeys, pubkey)
+		statuses[i] = OperationStatus{Status: StatusDeleted}
+	}
+	if err := k.persistRemoteKeysLocked(); err != nil {
+		for i, pubkey := range pubkeys {
+			if signer, ok := deleted[pubkey]; ok {
+				k.remoteKeys[pubkey] = signer
+				statuses[i] = OperationStatus{Status: StatusError, Message: err.Error()}
+			}
+		}
+	}
+	return statuses
+}
+
+func (k *KeyManager) persistRemoteKeysLocked() error {
+	encoded, err := json.MarshalIndent(k.remoteKeysLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(k.dataDir, remoteKeysFileName), encoded, 0600)
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/validator/slashing_protection"
+)
+
+// EIP-2335 test vector.
+const (
+	testPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	testPubkey   = "0x9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+
+	testKeystore = `{
+    "crypto": {
+        "kdf": {
+            "function": "pbkdf2",
+            "params": {
+                "dklen": 32,
+                "c": 262144,
+                "prf": "hmac-sha256",
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+        }
+    },
+    "description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/0/0",
+    "uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+    "version": 4
+}`
+)
+
+var testGenesisValidatorsRoot = libcommon.HexToHash("0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+
+func newTestKeyManager(t *testing.T, dataDir string) (*KeyManager, slashing_protection.SlashingProtection) {
+	sp := slashing_protection.NewSlashingProtection(memdb.NewTestDB(t), testGenesisValidatorsRoot)
+	k, err := NewKeyManager(dataDir, sp, log.New())
+	require.NoError(t, err)
+	return k, sp
+}
+
+func TestKeyManagerKeystores(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	k, sp := newTestKeyManager(t, dataDir)
+
+	var pubkey libcommon.Bytes48
+	require.NoError(t, pubkey.UnmarshalText([]byte(testPubkey)))
+
+	statuses, err := k.ImportKeystores(ctx, []string{testKeystore, testKeystore, "{}"}, []string{testPassword, testPassword, ""}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []OperationStatus{{Status: StatusImported}, {Status: StatusDuplicate}}, statuses[:2])
+	require.Equal(t, StatusError, statuses[2].Status)
+	require.Equal(t, []KeystoreInfo{{ValidatingPubkey: pubkey, DerivationPath: "m/12381/60/0/0"}}, k.ListKeystores())
+	_, ok := k.Signer(pubkey)
+	require.True(t, ok)
+
+	// imported keys survive a restart
+	reloaded, _ := newTestKeyManager(t, dataDir)
+	require.Len(t, reloaded.ListKeystores(), 1)
+
+	require.NoError(t, sp.CheckAndRecordBlock(ctx, pubkey, 5, libcommon.Hash{1}))
+	statuses, interchange, err := k.DeleteKeystores(ctx, []libcommon.Bytes48{pubkey, {1}})
+	require.NoError(t, err)
+	require.Equal(t, []OperationStatus{{Status: StatusDeleted}, {Status: StatusNotFound}}, statuses)
+	require.Len(t, interchange.Data, 1)
+	require.Equal(t, pubkey, interchange.Data[0].Pubkey)
+	require.Equal(t, uint64(5), interchange.Data[0].SignedBlocks[0].Slot)
+	require.Empty(t, k.ListKeystores())
+
+	// the history of a deleted key is still exported
+	statuses, interchange, err = k.DeleteKeystores(ctx, []libcommon.Bytes48{pubkey})
+	require.NoError(t, err)
+	require.Equal(t, []OperationStatus{{Status: StatusNotActive}}, statuses)
+	require.Len(t, interchange.Data, 1)
+
+	reloaded, _ = newTestKeyManager(t, dataDir)
+	require.Empty(t, reloaded.ListKeystores())
+}
+
+func TestKeyManagerRemoteKeys(t *testing.T) {
+	dataDir := t.TempDir()
+	k, _ := newTestKeyManager(t, dataDir)
+
+	keys := []RemoteKey{{Pubkey: libcommon.Bytes48{1}, Url: "http://localhost:9000"}, {Pubkey: libcommon.Bytes48{2}}}
+	require.Equal(t, []OperationStatus{{Status: StatusImported}, {Status: StatusError, Message: "missing url"}}, k.ImportRemoteKeys(keys))
+	require.Equal(t, []OperationStatus{{Status: StatusDuplicate}}, k.ImportRemoteKeys(keys[:1]))
+	require.Equal(t, keys[:1], k.ListRemoteKeys())
+
+	reloaded, _ := newTestKeyManager(t, dataDir)
+	require.Equal(t, keys[:1], reloaded.ListRemoteKeys())
+
+	require.Equal(t, []OperationStatus{{Status: StatusDeleted}, {Status: StatusNotFound}}, k.DeleteRemoteKeys([]libcommon.Bytes48{{1}, {2}}))
+	require.Empty(t, k.ListRemoteKeys())
+}
+
+func TestKeymanagerApi(t *testing.T) {
+	k, _ := newTestKeyManager(t, t.TempDir())
+	server := httptest.NewServer(NewKeymanagerApi(k, "secret", log.New()))
+	defer server.Close()
+
+	do := func(method, path, token, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := do(http.MethodGet, "/eth/v1/keystores", "", "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+	resp = do(http.MethodGet, "/eth/v1/keystores", "wrong", "")
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = do(http.MethodPost, "/eth/v1/remotekeys", "secret", `{"remote_keys":[{"pubkey":"`+testPubkey+`","url":"http://localhost:9000"}]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var statuses struct {
+		Data []OperationStatus `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&statuses))
+	resp.Body.Close()
+	require.Equal(t, []OperationStatus{{Status: StatusImported}}, statuses.Data)
+
+	resp = do(http.MethodGet, "/eth/v1/remotekeys", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var remoteKeys struct {
+		Data []RemoteKey `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&remoteKeys))
+	resp.Body.Close()
+	require.Len(t, remoteKeys.Data, 1)
+	require.Equal(t, "http://localhost:9000", remoteKeys.Data[0].Url)
+
+	resp = do(http.MethodDelete, "/eth/v1/keystores", "secret", `{"pubkeys":["`+testPubkey+`"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var deleted struct {
+		Data               []OperationStatus `json:"data"`
+		SlashingProtection string            `json:"slashing_protection"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&deleted))
+	resp.Body.Close()
+	require.Equal(t, []OperationStatus{{Status: StatusNotFound}}, deleted.Data)
+	var interchange slashing_protection.Interchange
+	require.NoError(t, json.Unmarshal([]byte(deleted.SlashingProtection), &interchange))
+	require.Equal(t, testGenesisValidatorsRoot, interchange.Metadata.GenesisValidatorsRoot)
+}
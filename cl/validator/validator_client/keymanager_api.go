@@ -0,0 +1,206 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/validator/slashing_protection"
+)
+
+// KeymanagerApi serves the standard Keymanager API (https://github.com/ethereum/keymanager-APIs).
+type KeymanagerApi struct {
+	keyManager *KeyManager
+	token      string
+	logger     log.Logger
+	mux        *chi.Mux
+}
+
+// NewKeymanagerApi creates the Keymanager API, every request must carry token as bearer token.
+func NewKeymanagerApi(keyManager *KeyManager, token string, logger log.Logger) *KeymanagerApi {
+	a := &KeymanagerApi{keyManager: keyManager, token: token, logger: logger, mux: chi.NewRouter()}
+	a.mux.Use(a.authenticate)
+	a.mux.Route("/eth/v1", func(r chi.Router) {
+		r.Get("/keystores", beaconhttp.HandleEndpointFunc(a.getKeystores))
+		r.Post("/keystores", beaconhttp.HandleEndpointFunc(a.postKeystores))
+		r.Delete("/keystores", beaconhttp.HandleEndpointFunc(a.deleteKeystores))
+		r.Get("/remotekeys", beaconhttp.HandleEndpointFunc(a.getRemoteKeys))
+		r.Post("/remotekeys", beaconhttp.HandleEndpointFunc(a.postRemoteKeys))
+		r.Delete("/remotekeys", beaconhttp.HandleEndpointFunc(a.deleteRemoteKeys))
+	})
+	return a
+}
+
+// ReadOrCreateApiToken reads the bearer token from path, generating a new random one if the file does not exist.
+func ReadOrCreateApiToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("empty api token in %s", path)
+		}
+		return token, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := "api-token-0x" + hex.EncodeToString(secret)
+	if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (a *KeymanagerApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the Keymanager API on addr until ctx is cancelled.
+func (a *KeymanagerApi) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:      a,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	a.logger.Info("[Keymanager API] Listening", "addr", addr)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (a *KeymanagerApi) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			beaconhttp.NewEndpointError(http.StatusUnauthorized, errors.New("missing bearer token")).WriteTo(w)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			beaconhttp.NewEndpointError(http.StatusForbidden, errors.New("invalid bearer token")).WriteTo(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *KeymanagerApi) getKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return beaconhttp.NewBeaconResponse(a.keyManager.ListKeystores()), nil
+}
+
+type importKeystoresRequest struct {
+	Keystores          []string `json:"keystores"`
+	Passwords          []string `json:"passwords"`
+	SlashingProtection string   `json:"slashing_protection,omitempty"`
+}
+
+func (a *KeymanagerApi) postKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importKeystoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if len(req.Keystores) != len(req.Passwords) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("got %d keystores and %d passwords", len(req.Keystores), len(req.Passwords)))
+	}
+	var interchange *slashing_protection.Interchange
+	if req.SlashingProtection != "" {
+		interchange = &slashing_protection.Interchange{}
+		if err := json.Unmarshal([]byte(req.SlashingProtection), interchange); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid slashing protection: %w", err))
+		}
+	}
+	statuses, err := a.keyManager.ImportKeystores(r.Context(), req.Keystores, req.Passwords, interchange)
+	if errors.Is(err, slashing_protection.ErrUnsupportedInterchangeVersion) || errors.Is(err, slashing_protection.ErrGenesisValidatorsRootMismatch) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(statuses), nil
+}
+
+type deleteKeysRequest struct {
+	Pubkeys []libcommon.Bytes48 `json:"pubkeys"`
+}
+
+func (a *KeymanagerApi) deleteKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	statuses, interchange, err := a.keyManager.DeleteKeystores(r.Context(), req.Pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(interchange)
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(statuses).With("slashing_protection", string(encoded)), nil
+}
+
+func (a *KeymanagerApi) getRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return beaconhttp.NewBeaconResponse(a.keyManager.ListRemoteKeys()), nil
+}
+
+type importRemoteKeysRequest struct {
+	RemoteKeys []RemoteKey `json:"remote_keys"`
+}
+
+func (a *KeymanagerApi) postRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importRemoteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	return beaconhttp.NewBeaconResponse(a.keyManager.ImportRemoteKeys(req.RemoteKeys)), nil
+}
+
+func (a *KeymanagerApi) deleteRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	return beaconhttp.NewBeaconResponse(a.keyManager.DeleteRemoteKeys(req.Pubkeys)), nil
+}
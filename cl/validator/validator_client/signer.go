@@ -0,0 +1,160 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Giulio2002/bls"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/cltypes"
+)
+
+// Web3Signer signing types.
+const (
+	SigningTypeBlock             = "BLOCK_V2"
+	SigningTypeAttestation       = "ATTESTATION"
+	SigningTypeRandaoReveal      = "RANDAO_REVEAL"
+	SigningTypeAggregationSlot   = "AGGREGATION_SLOT"
+	SigningTypeAggregateAndProof = "AGGREGATE_AND_PROOF"
+)
+
+// ForkInfo is the fork information sent to remote signers.
+type ForkInfo struct {
+	Fork                  *cltypes.Fork  `json:"fork"`
+	GenesisValidatorsRoot libcommon.Hash `json:"genesis_validators_root"`
+}
+
+// SigningRequest describes an object to be signed. Local signers only need the signing root, remote signers also
+// receive the object itself so that they can run their own slashing protection.
+type SigningRequest struct {
+	Type        string
+	SigningRoot libcommon.Hash
+	ForkInfo    *ForkInfo
+	// PayloadKey is the name of the field carrying Payload in the remote signing request, e.g. "attestation".
+	PayloadKey string
+	Payload    any
+}
+
+// Signer signs on behalf of one validator.
+type Signer interface {
+	PublicKey() libcommon.Bytes48
+	Sign(ctx context.Context, request *SigningRequest) (libcommon.Bytes96, error)
+}
+
+// localSigner signs with a secret key decrypted from an EIP-2335 keystore.
+type localSigner struct {
+	publicKey  libcommon.Bytes48
+	privateKey *bls.PrivateKey
+}
+
+func newLocalSigner(secret []byte) (*localSigner, error) {
+	privateKey, err := bls.NewPrivateKeyFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	var publicKey libcommon.Bytes48
+	copy(publicKey[:], bls.CompressPublicKey(privateKey.PublicKey()))
+	return &localSigner{publicKey: publicKey, privateKey: privateKey}, nil
+}
+
+func (s *localSigner) PublicKey() libcommon.Bytes48 {
+	return s.publicKey
+}
+
+func (s *localSigner) Sign(_ context.Context, request *SigningRequest) (libcommon.Bytes96, error) {
+	var signature libcommon.Bytes96
+	copy(signature[:], s.privateKey.Sign(request.SigningRoot[:]).Bytes())
+	return signature, nil
+}
+
+// remoteSigner delegates signing to a Web3Signer compatible remote signer.
+type remoteSigner struct {
+	publicKey libcommon.Bytes48
+	url       string
+	client    *http.Client
+}
+
+func newRemoteSigner(publicKey libcommon.Bytes48, url string) *remoteSigner {
+	return &remoteSigner{
+		publicKey: publicKey,
+		url:       strings.TrimSuffix(url, "/"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *remoteSigner) PublicKey() libcommon.Bytes48 {
+	return s.publicKey
+}
+
+func (s *remoteSigner) Sign(ctx context.Context, request *SigningRequest) (libcommon.Bytes96, error) {
+	var signature libcommon.Bytes96
+	body := map[string]any{
+		"type":        request.Type,
+		"signingRoot": request.SigningRoot,
+	}
+	if request.ForkInfo != nil {
+		body["fork_info"] = request.ForkInfo
+	}
+	if request.PayloadKey != "" {
+		body[request.PayloadKey] = request.Payload
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return signature, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/eth2/sign/%s", s.url, s.publicKey.String()), bytes.NewReader(encoded))
+	if err != nil {
+		return signature, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return signature, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return signature, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return signature, fmt.Errorf("remote signer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	// Web3Signer answers either with a JSON object or with the plain hex signature
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var decoded struct {
+			Signature libcommon.Bytes96 `json:"signature"`
+		}
+		if err := json.Unmarshal(respBody, &decoded); err != nil {
+			return signature, fmt.Errorf("invalid remote signer response: %w", err)
+		}
+		return decoded.Signature, nil
+	}
+	if err := signature.UnmarshalText(bytes.TrimSpace(respBody)); err != nil {
+		return signature, fmt.Errorf("invalid remote signer response: %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+)
+
+func TestLocalSigner(t *testing.T) {
+	privateKey, err := bls.GenerateKey()
+	require.NoError(t, err)
+	signer, err := newLocalSigner(privateKey.Bytes())
+	require.NoError(t, err)
+
+	root := libcommon.Hash{1, 2, 3}
+	signature, err := signer.Sign(context.Background(), &SigningRequest{SigningRoot: root})
+	require.NoError(t, err)
+	publicKey := signer.PublicKey()
+	valid, err := bls.Verify(signature[:], root[:], publicKey[:])
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	pubkey := libcommon.Bytes48{0xaa}
+	expected := libcommon.Bytes96{0xbb}
+	for _, jsonResponse := range []bool{true, false} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v1/eth2/sign/"+pubkey.Hex(), r.URL.Path)
+			var req map[string]json.RawMessage
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.JSONEq(t, `"`+SigningTypeRandaoReveal+`"`, string(req["type"]))
+			require.JSONEq(t, `{"epoch":"3"}`, string(req["randao_reveal"]))
+			require.Contains(t, req, "signingRoot")
+			if jsonResponse {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]libcommon.Bytes96{"signature": expected})
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(expected.Hex()))
+		}))
+		signer := newRemoteSigner(pubkey, server.URL+"/")
+		signature, err := signer.Sign(context.Background(), &SigningRequest{
+			Type:       SigningTypeRandaoReveal,
+			PayloadKey: "randao_reveal",
+			Payload:    map[string]string{"epoch": "3"},
+		})
+		server.Close()
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	}
+}
+
+func TestAggregationBits(t *testing.T) {
+	bits := aggregationBits(10, 3)
+	require.Equal(t, []byte{0x08, 0x04}, bits.Bytes())
+	require.Equal(t, 10, bits.Bits())
+	require.True(t, bits.GetBitAt(3))
+}
@@ -0,0 +1,511 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types/ssz"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/fork"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/utils"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+	"github.com/erigontech/erigon/cl/validator/slashing_protection"
+)
+
+// Config is the configuration of the built-in validator client.
+type Config struct {
+	// BeaconApiUrl is the url of the beacon API the validator client talks to.
+	BeaconApiUrl string
+	FeeRecipient libcommon.Address
+	Graffiti     string
+}
+
+// ValidatorClient performs the attester, aggregator and proposer duties of the keys held by the key manager through
+// the standard beacon API. Sync committee duties are not supported yet.
+type ValidatorClient struct {
+	cfg                Config
+	beaconCfg          *clparams.BeaconChainConfig
+	ethClock           eth_clock.EthereumClock
+	keyManager         *KeyManager
+	slashingProtection slashing_protection.SlashingProtection
+	beacon             *beaconClient
+	logger             log.Logger
+
+	mu             sync.Mutex
+	indicies       map[libcommon.Bytes48]uint64
+	dutiesEpoch    uint64
+	attesterDuties map[uint64][]attesterDuty // slot => duties
+	proposerDuties map[uint64]proposerDuty   // slot => duty
+}
+
+func NewValidatorClient(
+	cfg Config,
+	beaconCfg *clparams.BeaconChainConfig,
+	ethClock eth_clock.EthereumClock,
+	keyManager *KeyManager,
+	slashingProtection slashing_protection.SlashingProtection,
+	logger log.Logger,
+) *ValidatorClient {
+	return &ValidatorClient{
+		cfg:                cfg,
+		beaconCfg:          beaconCfg,
+		ethClock:           ethClock,
+		keyManager:         keyManager,
+		slashingProtection: slashingProtection,
+		beacon:             newBeaconClient(cfg.BeaconApiUrl, beaconCfg),
+		logger:             logger,
+		indicies:           make(map[libcommon.Bytes48]uint64),
+		attesterDuties:     make(map[uint64][]attesterDuty),
+		proposerDuties:     make(map[uint64]proposerDuty),
+	}
+}
+
+// Start runs the duties of every slot until ctx is cancelled.
+func (v *ValidatorClient) Start(ctx context.Context) {
+	v.logger.Info("[Validator] Starting validator client", "beacon", v.cfg.BeaconApiUrl, "keys", len(v.keyManager.Signers()))
+	slot := v.ethClock.GetCurrentSlot()
+	for {
+		if err := sleepUntil(ctx, v.ethClock.GetSlotTime(slot)); err != nil {
+			return
+		}
+		// skip the slots we missed, performing their duties now would be useless or harmful
+		if current := v.ethClock.GetCurrentSlot(); current > slot {
+			slot = current
+		}
+		if err := v.updateDuties(ctx, v.ethClock.GetEpochAtSlot(slot)); err != nil {
+			v.logger.Warn("[Validator] Failed to update duties", "slot", slot, "err", err)
+		}
+		go v.onSlot(ctx, slot)
+		slot++
+	}
+}
+
+func (v *ValidatorClient) onSlot(ctx context.Context, slot uint64) {
+	v.mu.Lock()
+	proposal, hasProposal := v.proposerDuties[slot]
+	attestations := v.attesterDuties[slot]
+	v.mu.Unlock()
+
+	if hasProposal {
+		if err := v.propose(ctx, proposal); err != nil {
+			v.logger.Warn("[Validator] Failed to propose block", "slot", slot, "validator", proposal.ValidatorIndex, "err", err)
+		}
+	}
+	if len(attestations) == 0 {
+		return
+	}
+	slotTime := v.ethClock.GetSlotTime(slot)
+	third := time.Duration(v.beaconCfg.SecondsPerSlot) * time.Second / 3
+	if err := sleepUntil(ctx, slotTime.Add(third)); err != nil {
+		return
+	}
+	attested := v.attest(ctx, slot, attestations)
+	if err := sleepUntil(ctx, slotTime.Add(2*third)); err != nil {
+		return
+	}
+	v.aggregate(ctx, slot, attested)
+}
+
+// updateDuties refreshes the validator indicies and the duties of epoch and of the next one.
+func (v *ValidatorClient) updateDuties(ctx context.Context, epoch uint64) error {
+	v.mu.Lock()
+	upToDate := v.dutiesEpoch == epoch && len(v.attesterDuties) > 0
+	v.mu.Unlock()
+	if upToDate {
+		return nil
+	}
+
+	signers := v.keyManager.Signers()
+	pubkeys := make([]libcommon.Bytes48, 0, len(signers))
+	for _, signer := range signers {
+		pubkeys = append(pubkeys, signer.PublicKey())
+	}
+	indicies, err := v.beacon.validatorIndicies(ctx, pubkeys)
+	if err != nil {
+		return err
+	}
+	if len(indicies) == 0 {
+		return nil
+	}
+	indexList := make([]uint64, 0, len(indicies))
+	preparations := make([]validatorPreparation, 0, len(indicies))
+	ours := make(map[uint64]struct{}, len(indicies))
+	for _, index := range indicies {
+		indexList = append(indexList, index)
+		preparations = append(preparations, validatorPreparation{ValidatorIndex: index, FeeRecipient: v.cfg.FeeRecipient})
+		ours[index] = struct{}{}
+	}
+	if err := v.beacon.prepareBeaconProposer(ctx, preparations); err != nil {
+		v.logger.Warn("[Validator] Failed to prepare beacon proposers", "err", err)
+	}
+
+	attesterDuties := make(map[uint64][]attesterDuty)
+	proposerDuties := make(map[uint64]proposerDuty)
+	subscriptions := []*cltypes.BeaconCommitteeSubscription{}
+	for _, e := range []uint64{epoch, epoch + 1} {
+		duties, err := v.beacon.attesterDuties(ctx, e, indexList)
+		if err != nil {
+			return err
+		}
+		for _, duty := range duties {
+			attesterDuties[duty.Slot] = append(attesterDuties[duty.Slot], duty)
+			subscriptions = append(subscriptions, &cltypes.BeaconCommitteeSubscription{
+				ValidatorIndex:   duty.ValidatorIndex,
+				CommitteeIndex:   duty.CommitteeIndex,
+				CommitteesAtSlot: duty.CommitteesAtSlot,
+				Slot:             duty.Slot,
+			})
+		}
+	}
+	// proposer duties are only known for the current epoch
+	proposals, err := v.beacon.proposerDuties(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	for _, duty := range proposals {
+		if _, ok := ours[duty.ValidatorIndex]; ok {
+			proposerDuties[duty.Slot] = duty
+		}
+	}
+	if len(subscriptions) > 0 {
+		if err := v.beacon.subscribeToBeaconCommittees(ctx, subscriptions); err != nil {
+			v.logger.Warn("[Validator] Failed to subscribe to beacon committees", "err", err)
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.indicies = indicies
+	v.attesterDuties = attesterDuties
+	v.proposerDuties = proposerDuties
+	v.dutiesEpoch = epoch
+	v.logger.Debug("[Validator] Updated duties", "epoch", epoch, "validators", len(indicies), "proposals", len(proposerDuties))
+	return nil
+}
+
+// forkInfo returns the fork active at epoch.
+func (v *ValidatorClient) forkInfo(epoch uint64) *ForkInfo {
+	version := v.ethClock.StateVersionByEpoch(epoch)
+	previous := version
+	if version > clparams.Phase0Version {
+		previous = version - 1
+	}
+	return &ForkInfo{
+		Fork: &cltypes.Fork{
+			PreviousVersion: utils.Uint32ToBytes4(v.beaconCfg.GetForkVersionByVersion(previous)),
+			CurrentVersion:  utils.Uint32ToBytes4(v.beaconCfg.GetForkVersionByVersion(version)),
+			Epoch:           v.beaconCfg.GetForkEpochByVersion(version),
+		},
+		GenesisValidatorsRoot: v.ethClock.GenesisValidatorsRoot(),
+	}
+}
+
+// signingRoot computes the signing root of obj in the given domain at epoch.
+func (v *ValidatorClient) signingRoot(obj ssz.HashableSSZ, epoch uint64, domainType libcommon.Bytes4) (libcommon.Hash, *ForkInfo, error) {
+	forkInfo := v.forkInfo(epoch)
+	domain, err := fork.Domain(forkInfo.Fork, epoch, domainType, forkInfo.GenesisValidatorsRoot)
+	if err != nil {
+		return libcommon.Hash{}, nil, err
+	}
+	signingRoot, err := fork.ComputeSigningRoot(obj, domain)
+	if err != nil {
+		return libcommon.Hash{}, nil, err
+	}
+	return signingRoot, forkInfo, nil
+}
+
+// sign signs obj in the given domain at epoch with signer.
+func (v *ValidatorClient) sign(ctx context.Context, signer Signer, signingType string, obj ssz.HashableSSZ, epoch uint64, domainType libcommon.Bytes4, payloadKey string, payload any) (libcommon.Bytes96, error) {
+	signingRoot, forkInfo, err := v.signingRoot(obj, epoch, domainType)
+	if err != nil {
+		return libcommon.Bytes96{}, err
+	}
+	return signer.Sign(ctx, &SigningRequest{
+		Type:        signingType,
+		SigningRoot: signingRoot,
+		ForkInfo:    forkInfo,
+		PayloadKey:  payloadKey,
+		Payload:     payload,
+	})
+}
+
+// uint64Root lets slots and epochs be signed like any other SSZ object.
+type uint64Root uint64
+
+func (u uint64Root) HashSSZ() ([32]byte, error) {
+	return merkle_tree.Uint64Root(uint64(u)), nil
+}
+
+func (v *ValidatorClient) propose(ctx context.Context, duty proposerDuty) error {
+	signer, ok := v.keyManager.Signer(duty.Pubkey)
+	if !ok {
+		return errors.New("key not active")
+	}
+	epoch := v.ethClock.GetEpochAtSlot(duty.Slot)
+	randaoReveal, err := v.sign(ctx, signer, SigningTypeRandaoReveal, uint64Root(epoch), epoch, v.beaconCfg.DomainRandao,
+		"randao_reveal", map[string]string{"epoch": strconv.FormatUint(epoch, 10)})
+	if err != nil {
+		return err
+	}
+	var graffiti libcommon.Hash
+	copy(graffiti[:], v.cfg.Graffiti)
+	produced, err := v.beacon.produceBlock(ctx, duty.Slot, randaoReveal, graffiti)
+	if err != nil {
+		return err
+	}
+
+	var (
+		header  *cltypes.BeaconBlockHeader
+		toSign  ssz.HashableSSZ
+		blinded = produced.BlindedBlock != nil
+	)
+	if blinded {
+		toSign = produced.BlindedBlock
+		header = &cltypes.BeaconBlockHeader{Slot: produced.BlindedBlock.Slot, ProposerIndex: produced.BlindedBlock.ProposerIndex}
+	} else {
+		toSign = produced.Block.Block
+		header = &cltypes.BeaconBlockHeader{Slot: produced.Block.Block.Slot, ProposerIndex: produced.Block.Block.ProposerIndex}
+	}
+	if header.Slot != duty.Slot || header.ProposerIndex != duty.ValidatorIndex {
+		return errors.New("beacon node produced a block for another slot or proposer")
+	}
+	// the slashing protection is checked on the signing root, before the block is signed
+	signingRoot, forkInfo, err := v.signingRoot(toSign, epoch, v.beaconCfg.DomainBeaconProposer)
+	if err != nil {
+		return err
+	}
+	if err := v.slashingProtection.CheckAndRecordBlock(ctx, duty.Pubkey, duty.Slot, signingRoot); err != nil {
+		return err
+	}
+	blockHeader, err := blockHeaderOf(toSign, header)
+	if err != nil {
+		return err
+	}
+	signature, err := signer.Sign(ctx, &SigningRequest{
+		Type:        SigningTypeBlock,
+		SigningRoot: signingRoot,
+		ForkInfo:    forkInfo,
+		PayloadKey:  "beacon_block",
+		Payload: map[string]any{
+			"version":      clparams.ClVersionToString(produced.Version),
+			"block_header": blockHeader,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var encoded []byte
+	if blinded {
+		signed := &cltypes.SignedBlindedBeaconBlock{Block: produced.BlindedBlock, Signature: signature}
+		encoded, err = signed.EncodeSSZ(nil)
+	} else {
+		var signed *cltypes.DenebSignedBeaconBlock
+		if produced.Version.AfterOrEqual(clparams.ElectraVersion) {
+			signed = cltypes.NewElectraSignedBeaconBlock(v.beaconCfg)
+		} else {
+			signed = cltypes.NewDenebSignedBeaconBlock(v.beaconCfg)
+		}
+		signed.SignedBlock.Block = produced.Block.Block
+		signed.SignedBlock.Signature = signature
+		signed.KZGProofs = produced.Block.KZGProofs
+		signed.Blobs = produced.Block.Blobs
+		encoded, err = signed.EncodeSSZ(nil)
+	}
+	if err != nil {
+		return err
+	}
+	if err := v.beacon.publishBlock(ctx, produced.Version, encoded, blinded); err != nil {
+		return err
+	}
+	v.logger.Info("[Validator] Proposed block", "slot", duty.Slot, "validator", duty.ValidatorIndex, "blinded", blinded)
+	return nil
+}
+
+// blockHeaderOf returns the header of a full or blinded block, as sent to remote signers.
+func blockHeaderOf(block ssz.HashableSSZ, header *cltypes.BeaconBlockHeader) (*cltypes.BeaconBlockHeader, error) {
+	var body ssz.HashableSSZ
+	switch b := block.(type) {
+	case *cltypes.BeaconBlock:
+		header.ParentRoot, header.Root = b.ParentRoot, b.StateRoot
+		body = b.Body
+	case *cltypes.BlindedBeaconBlock:
+		header.ParentRoot, header.Root = b.ParentRoot, b.StateRoot
+		body = b.Body
+	}
+	bodyRoot, err := body.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	header.BodyRoot = bodyRoot
+	return header, nil
+}
+
+// attestedDuty is an attestation performed in the current slot, kept for the aggregation step.
+type attestedDuty struct {
+	duty attesterDuty
+	data *solid.AttestationData
+}
+
+func (v *ValidatorClient) attest(ctx context.Context, slot uint64, duties []attesterDuty) []attestedDuty {
+	var (
+		attestations []*solid.Attestation
+		attested     []attestedDuty
+		dataCache    = make(map[uint64]*solid.AttestationData)
+		electra      = v.ethClock.StateVersionByEpoch(v.ethClock.GetEpochAtSlot(slot)).AfterOrEqual(clparams.ElectraVersion)
+	)
+	for _, duty := range duties {
+		signer, ok := v.keyManager.Signer(duty.Pubkey)
+		if !ok {
+			continue
+		}
+		data, ok := dataCache[duty.CommitteeIndex]
+		if !ok {
+			var err error
+			if data, err = v.beacon.attestationData(ctx, slot, duty.CommitteeIndex); err != nil {
+				v.logger.Warn("[Validator] Failed to get attestation data", "slot", slot, "committee", duty.CommitteeIndex, "err", err)
+				continue
+			}
+			if electra {
+				data.CommitteeIndex = 0
+			}
+			dataCache[duty.CommitteeIndex] = data
+		}
+		targetEpoch := data.Target.Epoch
+		signingRoot, forkInfo, err := v.signingRoot(data, targetEpoch, v.beaconCfg.DomainBeaconAttester)
+		if err != nil {
+			continue
+		}
+		if err := v.slashingProtection.CheckAndRecordAttestation(ctx, duty.Pubkey, data.Source.Epoch, targetEpoch, signingRoot); err != nil {
+			v.logger.Warn("[Validator] Refusing to attest", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+		signature, err := signer.Sign(ctx, &SigningRequest{
+			Type:        SigningTypeAttestation,
+			SigningRoot: signingRoot,
+			ForkInfo:    forkInfo,
+			PayloadKey:  "attestation",
+			Payload:     data,
+		})
+		if err != nil {
+			v.logger.Warn("[Validator] Failed to sign attestation", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+		attestation := &solid.Attestation{
+			AggregationBits: aggregationBits(duty.CommitteeLength, duty.ValidatorCommitteeIndex),
+			Data:            data,
+			Signature:       signature,
+		}
+		if electra {
+			attestation.CommitteeBits = solid.NewBitVector(int(v.beaconCfg.MaxCommitteesPerSlot))
+			if err := attestation.CommitteeBits.SetBitAt(int(duty.CommitteeIndex), true); err != nil {
+				continue
+			}
+		}
+		attestations = append(attestations, attestation)
+		attested = append(attested, attestedDuty{duty: duty, data: data})
+	}
+	if len(attestations) == 0 {
+		return nil
+	}
+	if err := v.beacon.submitAttestations(ctx, attestations); err != nil {
+		v.logger.Warn("[Validator] Failed to submit attestations", "slot", slot, "err", err)
+		return nil
+	}
+	v.logger.Debug("[Validator] Submitted attestations", "slot", slot, "count", len(attestations))
+	return attested
+}
+
+func (v *ValidatorClient) aggregate(ctx context.Context, slot uint64, attested []attestedDuty) {
+	epoch := v.ethClock.GetEpochAtSlot(slot)
+	var aggregates []*cltypes.SignedAggregateAndProof
+	for _, a := range attested {
+		signer, ok := v.keyManager.Signer(a.duty.Pubkey)
+		if !ok {
+			continue
+		}
+		selectionProof, err := v.sign(ctx, signer, SigningTypeAggregationSlot, uint64Root(slot), epoch, v.beaconCfg.DomainSelectionProof,
+			"aggregation_slot", map[string]string{"slot": strconv.FormatUint(slot, 10)})
+		if err != nil {
+			v.logger.Warn("[Validator] Failed to sign selection proof", "slot", slot, "validator", a.duty.ValidatorIndex, "err", err)
+			continue
+		}
+		if !state.IsAggregator(v.beaconCfg, a.duty.CommitteeLength, a.duty.CommitteeIndex, selectionProof) {
+			continue
+		}
+		dataRoot, err := a.data.HashSSZ()
+		if err != nil {
+			continue
+		}
+		aggregate, err := v.beacon.aggregateAttestation(ctx, slot, dataRoot)
+		if err != nil {
+			v.logger.Warn("[Validator] Failed to get aggregate attestation", "slot", slot, "err", err)
+			continue
+		}
+		aggregateAndProof := &cltypes.AggregateAndProof{
+			AggregatorIndex: a.duty.ValidatorIndex,
+			Aggregate:       aggregate,
+			SelectionProof:  selectionProof,
+		}
+		signature, err := v.sign(ctx, signer, SigningTypeAggregateAndProof, aggregateAndProof, epoch, v.beaconCfg.DomainAggregateAndProof,
+			"aggregate_and_proof", aggregateAndProof)
+		if err != nil {
+			v.logger.Warn("[Validator] Failed to sign aggregate", "slot", slot, "validator", a.duty.ValidatorIndex, "err", err)
+			continue
+		}
+		aggregates = append(aggregates, &cltypes.SignedAggregateAndProof{Message: aggregateAndProof, Signature: signature})
+	}
+	if len(aggregates) == 0 {
+		return
+	}
+	if err := v.beacon.submitAggregateAndProofs(ctx, aggregates); err != nil {
+		v.logger.Warn("[Validator] Failed to submit aggregates", "slot", slot, "err", err)
+		return
+	}
+	v.logger.Debug("[Validator] Submitted aggregates", "slot", slot, "count", len(aggregates))
+}
+
+// aggregationBits returns the SSZ bitlist of a committee of committeeLength validators with only index set.
+func aggregationBits(committeeLength, index uint64) *solid.BitList {
+	bits := make([]byte, committeeLength/8+1)
+	bits[index/8] |= 1 << (index % 8)
+	// length delimiter
+	bits[committeeLength/8] |= 1 << (committeeLength % 8)
+	return solid.BitlistFromBytes(bits, int(committeeLength))
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
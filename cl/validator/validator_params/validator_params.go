@@ -24,6 +24,7 @@ import (
 
 type ValidatorParams struct {
 	feeRecipients sync.Map
+	gasLimits     sync.Map // pubkey -> gas limit registered with the builder
 }
 
 func NewValidatorParams() *ValidatorParams {
@@ -41,3 +42,15 @@ func (vp *ValidatorParams) GetFeeRecipient(validatorIndex uint64) (libcommon.Add
 	}
 	return val.(libcommon.Address), true
 }
+
+func (vp *ValidatorParams) SetGasLimit(pubKey libcommon.Bytes48, gasLimit uint64) {
+	vp.gasLimits.Store(pubKey, gasLimit)
+}
+
+func (vp *ValidatorParams) GetGasLimit(pubKey libcommon.Bytes48) (uint64, bool) {
+	val, ok := vp.gasLimits.Load(pubKey)
+	if !ok {
+		return 0, false
+	}
+	return val.(uint64), true
+}
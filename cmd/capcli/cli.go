@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -44,6 +45,7 @@ import (
 	"github.com/erigontech/erigon-lib/metrics"
 
 	"github.com/erigontech/erigon/cl/antiquary"
+	"github.com/erigontech/erigon/cl/beacon/statediff"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/clparams/initial_state"
 	"github.com/erigontech/erigon/cl/cltypes"
@@ -80,6 +82,7 @@ var CLI struct {
 	CheckBlobsSnapshots       CheckBlobsSnapshots       `cmd:"" help:"check blobs snapshots"`
 	CheckBlobsSnapshotsCount  CheckBlobsSnapshotsCount  `cmd:"" help:"check blobs snapshots count"`
 	DumpBlobsSnapshotsToStore DumpBlobsSnapshotsToStore `cmd:"" help:"dump blobs snapshots to store"`
+	StateDiff                 StateDiff                 `cmd:"" help:"stream ssz-encoded state diffs between slots from a beacon node"`
 }
 
 type chainCfg struct {
@@ -1172,3 +1175,76 @@ func (c *DumpBlobsSnapshotsToStore) Run(ctx *Context) error {
 
 	return nil
 }
+
+type StateDiff struct {
+	BeaconApiURL string `help:"beacon api url" default:"http://localhost:5555"`
+	FromSlot     uint64 `help:"slot of the first state" required:""`
+	ToSlot       uint64 `help:"slot of the last state" required:""`
+	Step         uint64 `help:"if set, stream one diff every step slots instead of a single diff between from-slot and to-slot" default:"0"`
+	Out          string `help:"output folder, one <from>_<to>.ssz file is written per diff" default:"." type:"existingdir"`
+	Verify       bool   `help:"decode every diff once downloaded and log its size" default:"false"`
+}
+
+func (s *StateDiff) Run(ctx *Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StderrHandler))
+	if s.FromSlot > s.ToSlot {
+		return statediff.ErrSlotsNotOrdered
+	}
+	step := s.Step
+	if step == 0 {
+		step = s.ToSlot - s.FromSlot
+	}
+	for from := s.FromSlot; ; from += step {
+		to := min(from+step, s.ToSlot)
+		path := filepath.Join(s.Out, fmt.Sprintf("%d_%d.ssz", from, to))
+		start := time.Now()
+		n, err := downloadStateDiff(ctx, s.BeaconApiURL, from, to, path)
+		if err != nil {
+			return err
+		}
+		log.Info("Downloaded state diff", "from", from, "to", to, "bytes", n, "elapsed", time.Since(start), "path", path)
+		if s.Verify {
+			encoded, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			diff := &statediff.StateDiff{}
+			if err := diff.DecodeSSZ(encoded, 0); err != nil {
+				return fmt.Errorf("failed to decode state diff %s: %w", path, err)
+			}
+			log.Info("State diff", "from", diff.FromSlot, "to", diff.ToSlot, "validators", len(diff.Validators), "balances", len(diff.Balances), "participations", len(diff.Participations))
+		}
+		if to >= s.ToSlot {
+			return nil
+		}
+	}
+}
+
+// downloadStateDiff streams the diff between from and to straight into the file at path.
+func downloadStateDiff(ctx context.Context, beaconApiURL string, from, to uint64, path string) (int64, error) {
+	uri := fmt.Sprintf("%s/caplin/debug/state_diff?from_slot=%d&to_slot=%d", beaconApiURL, from, to)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("state diff request failed, status code %d: %s", resp.StatusCode, body)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return n, err
+	}
+	return n, f.Sync()
+}
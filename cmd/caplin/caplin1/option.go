@@ -17,6 +17,8 @@
 package caplin1
 
 import (
+	"time"
+
 	"github.com/erigontech/erigon/cl/beacon/builder"
 	"github.com/erigontech/erigon/cl/clparams"
 )
@@ -27,8 +29,8 @@ type option struct {
 
 type CaplinOption func(*option)
 
-func WithBuilder(mevRelayUrl string, beaconConfig *clparams.BeaconChainConfig) CaplinOption {
+func WithBuilder(mevRelayUrls []string, relayTimeout time.Duration, beaconConfig *clparams.BeaconChainConfig) CaplinOption {
 	return func(o *option) {
-		o.builderClient = builder.NewBlockBuilderClient(mevRelayUrl, beaconConfig)
+		o.builderClient = builder.NewRelayPool(mevRelayUrls, relayTimeout, beaconConfig)
 	}
 }
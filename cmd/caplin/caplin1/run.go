@@ -40,16 +40,23 @@ import (
 	"github.com/erigontech/erigon/cl/beacon/synced_data"
 	"github.com/erigontech/erigon/cl/clparams/initial_state"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/cl/monitor"
 	"github.com/erigontech/erigon/cl/rpc"
 	"github.com/erigontech/erigon/cl/sentinel"
+	"github.com/erigontech/erigon/cl/sentinel/gossip_tracer"
 	"github.com/erigontech/erigon/cl/sentinel/service"
 	"github.com/erigontech/erigon/cl/utils/eth_clock"
 	"github.com/erigontech/erigon/cl/validator/attestation_producer"
 	"github.com/erigontech/erigon/cl/validator/committee_subscription"
+	"github.com/erigontech/erigon/cl/validator/slashing_protection"
 	"github.com/erigontech/erigon/cl/validator/sync_contribution_pool"
+	"github.com/erigontech/erigon/cl/validator/validator_client"
 	"github.com/erigontech/erigon/cl/validator/validator_params"
+	"github.com/erigontech/erigon/crypto"
 	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/params"
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
 
@@ -199,7 +206,15 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		}
 	}
 
-	state, err := checkpoint_sync.ReadOrFetchLatestBeaconState(ctx, dirs, beaconConfig, config, genesisDb)
+	var wsCheckpoint *solid.Checkpoint
+	if config.WeakSubjectivityCheckpoint != "" {
+		checkpoint, err := checkpoint_sync.ParseWeakSubjectivityCheckpoint(config.WeakSubjectivityCheckpoint)
+		if err != nil {
+			return err
+		}
+		wsCheckpoint = &checkpoint
+	}
+	state, err := checkpoint_sync.ReadOrFetchLatestBeaconState(ctx, dirs, beaconConfig, config, genesisDb, wsCheckpoint)
 	if err != nil {
 		return err
 	}
@@ -215,10 +230,23 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		return err
 	}
 
+	// PeerDAS: the columns we custody are derived from our node id, so the node key is generated upfront.
+	nodeKey, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	custodyGroupCount := max(config.CustodyGroupCount, beaconConfig.CustodyRequirement)
+	custodyColumns, err := das.CustodyColumns(beaconConfig, enode.PubkeyToIDV4(&nodeKey.PublicKey), custodyGroupCount)
+	if err != nil {
+		return err
+	}
+	dataColumnStore := blob_storage.NewDataColumnStore(afero.NewBasePathFs(afero.NewOsFs(), path.Join(dirs.CaplinBlobs, "columns")), pruneBlobDistance, beaconConfig, ethClock)
+
 	caplinOptions := []CaplinOption{}
 	if config.BeaconAPIRouter.Builder {
 		if config.RelayUrlExist() {
-			caplinOptions = append(caplinOptions, WithBuilder(config.MevRelayUrl, beaconConfig))
+			caplinOptions = append(caplinOptions, WithBuilder(config.RelayUrls(), config.MevRelayTimeout, beaconConfig))
+			config.BeaconAPIRouter.BuilderBoostFactor = config.MevBuilderBoostFactor
 		} else {
 			log.Warn("builder api enable but relay url not set. Skipping builder mode")
 			config.BeaconAPIRouter.Builder = false
@@ -258,10 +286,11 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	syncContributionPool := sync_contribution_pool.NewSyncContributionPool(beaconConfig)
 	emitters := beaconevents.NewEventEmitter()
 	aggregationPool := aggregation.NewAggregationPool(ctx, beaconConfig, networkConfig, ethClock)
-	validatorMonitor := monitor.NewValidatorMonitor(config.EnableValidatorMonitor, ethClock, beaconConfig, syncedDataManager)
+	validatorMonitor := monitor.NewValidatorMonitor(config.EnableValidatorMonitor, ethClock, beaconConfig, syncedDataManager, indexDB, config.ValidatorMonitorValidators)
+	depositTree := checkpoint_sync.ReadOrFetchDepositTree(ctx, dirs, beaconConfig, config, state)
 	forkChoice, err := forkchoice.NewForkChoiceStore(
 		ethClock, state, engine, pool, fork_graph.NewForkGraphDisk(state, fcuFs, config.BeaconAPIRouter, emitters),
-		emitters, syncedDataManager, blobStorage, validatorMonitor)
+		emitters, syncedDataManager, blobStorage, dataColumnStore, custodyColumns, validatorMonitor, depositTree)
 	if err != nil {
 		logger.Error("Could not create forkchoice", "err", err)
 		return err
@@ -274,6 +303,12 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	}
 	activeIndicies := state.GetActiveValidatorsIndices(state.Slot() / beaconConfig.SlotsPerEpoch)
 
+	gossipTracer, err := gossip_tracer.NewGossipTracer(config.GossipTraceFile, logger)
+	if err != nil {
+		return err
+	}
+	go gossipTracer.Start(ctx)
+
 	sentinel, err := service.StartSentinelService(&sentinel.SentinelConfig{
 		IpAddr:             config.CaplinDiscoveryAddr,
 		Port:               int(config.CaplinDiscoveryPort),
@@ -285,7 +320,10 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		EnableBlocks:       true,
 		ActiveIndicies:     uint64(len(activeIndicies)),
 		MaxPeerCount:       config.MaxPeerCount,
-	}, rcsn, blobStorage, indexDB, &service.ServerConfig{
+		NodeKey:            nodeKey,
+		CustodyGroupCount:  custodyGroupCount,
+		GossipTracer:       gossipTracer,
+	}, rcsn, blobStorage, dataColumnStore, indexDB, &service.ServerConfig{
 		Network: "tcp",
 		Addr:    fmt.Sprintf("%s:%d", config.SentinelAddr, config.SentinelPort),
 		Creds:   creds,
@@ -306,6 +344,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	// Define gossip services
 	blockService := services.NewBlockService(ctx, indexDB, forkChoice, syncedDataManager, ethClock, beaconConfig, emitters)
 	blobService := services.NewBlobSidecarService(ctx, beaconConfig, forkChoice, syncedDataManager, ethClock, emitters, false)
+	dataColumnSidecarService := services.NewDataColumnSidecarService(ctx, beaconConfig, forkChoice, syncedDataManager, ethClock)
 	syncCommitteeMessagesService := services.NewSyncCommitteeMessagesService(beaconConfig, ethClock, syncedDataManager, syncContributionPool, false)
 	attestationService := services.NewAttestationService(ctx, forkChoice, committeeSub, ethClock, syncedDataManager, beaconConfig, networkConfig, emitters, batchSignatureVerifier)
 	syncContributionService := services.NewSyncContributionService(syncedDataManager, beaconConfig, syncContributionPool, ethClock, emitters, false)
@@ -320,8 +359,8 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 
 	// Create the gossip manager
 	gossipManager := network.NewGossipReceiver(sentinel, forkChoice, beaconConfig, networkConfig, ethClock, emitters, committeeSub,
-		blockService, blobService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
-		attestationService, voluntaryExitService, blsToExecutionChangeService, proposerSlashingService)
+		blockService, blobService, dataColumnSidecarService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
+		attestationService, voluntaryExitService, blsToExecutionChangeService, proposerSlashingService, gossipTracer)
 	{ // start ticking forkChoice
 		go func() {
 			tickInterval := time.NewTicker(2 * time.Millisecond)
@@ -411,6 +450,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 			&config.BeaconAPIRouter,
 			emitters,
 			blobStorage,
+			dataColumnStore,
 			csn,
 			validatorParameters,
 			attestationProducer,
@@ -427,12 +467,18 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 			proposerSlashingService,
 			option.builderClient,
 			validatorMonitor,
+			gossipTracer,
 		)
 		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
 			ArchiveApi: apiHandler,
 		}, config.BeaconAPIRouter)
 		log.Info("Beacon API started", "addr", config.BeaconAPIRouter.Address)
 	}
+	if config.EnableValidatorClient {
+		if err := runValidatorClient(ctx, config, dirs, beaconConfig, ethClock, logger); err != nil {
+			return err
+		}
+	}
 
 	stageCfg := stages.ClStagesCfg(
 		beaconRpc,
@@ -453,8 +499,11 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		syncedDataManager,
 		emitters,
 		blobStorage,
+		dataColumnStore,
+		custodyColumns,
 		attestationProducer,
 		validatorMonitor,
+		wsCheckpoint,
 	)
 	sync := stages.ConsensusClStages(ctx, stageCfg)
 
@@ -466,3 +515,53 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	}
 	return err
 }
+
+// runValidatorClient starts the built-in validator client, talking to the local beacon API, and the Keymanager API.
+func runValidatorClient(ctx context.Context, config clparams.CaplinConfig, dirs datadir.Dirs, beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, logger log.Logger) error {
+	if !config.BeaconAPIRouter.Active || !config.BeaconAPIRouter.Beacon || !config.BeaconAPIRouter.Validator {
+		return errors.New("the validator client requires the beacon and validator namespaces of the beacon API, check --beacon.api flag")
+	}
+	slashingProtectionPath := path.Join(dirs.CaplinValidator, "slashing_protection")
+	if err := os.MkdirAll(slashingProtectionPath, 0700); err != nil {
+		return err
+	}
+	slashingProtectionDB := mdbx.MustOpen(slashingProtectionPath)
+	go func() {
+		<-ctx.Done()
+		slashingProtectionDB.Close()
+	}()
+	slashingProtection := slashing_protection.NewSlashingProtection(slashingProtectionDB, ethClock.GenesisValidatorsRoot())
+
+	keyManager, err := validator_client.NewKeyManager(dirs.CaplinValidator, slashingProtection, logger)
+	if err != nil {
+		return err
+	}
+	if config.ValidatorKeystoresDir != "" {
+		if err := keyManager.LoadKeystores(config.ValidatorKeystoresDir, config.ValidatorPasswordFile); err != nil {
+			return err
+		}
+	}
+	if config.KeymanagerAPIAddr != "" {
+		tokenFile := config.KeymanagerAPITokenFile
+		if tokenFile == "" {
+			tokenFile = path.Join(dirs.CaplinValidator, "api-token.txt")
+		}
+		token, err := validator_client.ReadOrCreateApiToken(tokenFile)
+		if err != nil {
+			return err
+		}
+		keymanagerApi := validator_client.NewKeymanagerApi(keyManager, token, logger)
+		go func() {
+			if err := keymanagerApi.ListenAndServe(ctx, config.KeymanagerAPIAddr); err != nil {
+				logger.Error("[Keymanager API] Failed to serve", "err", err)
+			}
+		}()
+	}
+	validatorClient := validator_client.NewValidatorClient(validator_client.Config{
+		BeaconApiUrl: "http://" + config.BeaconAPIRouter.Address,
+		FeeRecipient: config.ValidatorFeeRecipient,
+		Graffiti:     config.ValidatorGraffiti,
+	}, beaconConfig, ethClock, keyManager, slashingProtection, logger)
+	go validatorClient.Start(ctx)
+	return nil
+}
@@ -37,22 +37,30 @@ import (
 type CaplinCliCfg struct {
 	*sentinelcli.SentinelCliCfg
 
-	Chaindata             string        `json:"chaindata"`
-	ErigonPrivateApi      string        `json:"erigon_private_api"`
-	AllowedEndpoints      []string      `json:"endpoints"`
-	BeaconApiReadTimeout  time.Duration `json:"beacon_api_read_timeout"`
-	BeaconApiWriteTimeout time.Duration `json:"beacon_api_write_timeout"`
-	BeaconAddr            string        `json:"beacon_addr"`
-	BeaconProtocol        string        `json:"beacon_protocol"`
-	DataDir               string        `json:"data_dir"`
-	RunEngineAPI          bool          `json:"run_engine_api"`
-	EngineAPIAddr         string        `json:"engine_api_addr"`
-	EngineAPIPort         int           `json:"engine_api_port"`
-	MevRelayUrl           string        `json:"mev_relay_url"`
-	CustomConfig          string        `json:"custom_config"`
-	CustomGenesisState    string        `json:"custom_genesis_state"`
-	MaxPeerCount          uint64        `json:"max_peer_count"`
-	JwtSecret             []byte
+	Chaindata                  string        `json:"chaindata"`
+	ErigonPrivateApi           string        `json:"erigon_private_api"`
+	AllowedEndpoints           []string      `json:"endpoints"`
+	BeaconApiReadTimeout       time.Duration `json:"beacon_api_read_timeout"`
+	BeaconApiWriteTimeout      time.Duration `json:"beacon_api_write_timeout"`
+	BeaconAddr                 string        `json:"beacon_addr"`
+	BeaconProtocol             string        `json:"beacon_protocol"`
+	DataDir                    string        `json:"data_dir"`
+	RunEngineAPI               bool          `json:"run_engine_api"`
+	EngineAPIAddr              string        `json:"engine_api_addr"`
+	EngineAPIPort              int           `json:"engine_api_port"`
+	MevRelayUrl                string        `json:"mev_relay_url"`
+	MevRelayTimeout            time.Duration `json:"mev_relay_timeout"`
+	MevBuilderBoostFactor      uint64        `json:"mev_builder_boost_factor"`
+	CustomConfig               string        `json:"custom_config"`
+	CustomGenesisState         string        `json:"custom_genesis_state"`
+	MaxPeerCount               uint64        `json:"max_peer_count"`
+	CustodyGroupCount          uint64        `json:"custody_group_count"`
+	CheckpointStateFile        string        `json:"checkpoint_state_file"`
+	CheckpointBlockFile        string        `json:"checkpoint_block_file"`
+	CheckpointSyncFallback     bool          `json:"checkpoint_sync_fallback"`
+	WeakSubjectivityCheckpoint string        `json:"weak_subjectivity_checkpoint"`
+	GossipTraceFile            string        `json:"gossip_trace_file"`
+	JwtSecret                  []byte
 
 	AllowedMethods   []string `json:"allowed_methods"`
 	AllowedOrigins   []string `json:"allowed_origins"`
@@ -75,6 +83,8 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 	cfg.BeaconApiReadTimeout = time.Duration(ctx.Uint64(caplinflags.BeaconApiReadTimeout.Name)) * time.Second
 	cfg.BeaconApiWriteTimeout = time.Duration(ctx.Uint(caplinflags.BeaconApiWriteTimeout.Name)) * time.Second
 	cfg.MaxPeerCount = ctx.Uint64(utils.CaplinMaxPeerCount.Name)
+	cfg.CustodyGroupCount = ctx.Uint64(utils.CaplinCustodyGroupCountFlag.Name)
+	cfg.GossipTraceFile = ctx.String(utils.CaplinGossipTraceFileFlag.Name)
 	cfg.BeaconAddr = fmt.Sprintf("%s:%d", ctx.String(caplinflags.BeaconApiAddr.Name), ctx.Int(caplinflags.BeaconApiPort.Name))
 	cfg.AllowCredentials = ctx.Bool(utils.BeaconApiAllowCredentialsFlag.Name)
 	cfg.AllowedMethods = ctx.StringSlice(utils.BeaconApiAllowMethodsFlag.Name)
@@ -100,10 +110,16 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 	if checkpointUrls := ctx.StringSlice(utils.CaplinCheckpointSyncUrlFlag.Name); len(checkpointUrls) > 0 {
 		clparams.ConfigurableCheckpointsURLs = checkpointUrls
 	}
+	cfg.CheckpointStateFile = ctx.String(utils.CaplinCheckpointSyncStateFileFlag.Name)
+	cfg.CheckpointBlockFile = ctx.String(utils.CaplinCheckpointSyncBlockFileFlag.Name)
+	cfg.CheckpointSyncFallback = ctx.Bool(utils.CaplinCheckpointSyncFallbackFlag.Name)
+	cfg.WeakSubjectivityCheckpoint = ctx.String(utils.CaplinWeakSubjectivityCheckpointFlag.Name)
 
 	cfg.Chaindata = ctx.String(caplinflags.ChaindataFlag.Name)
 
 	cfg.MevRelayUrl = ctx.String(caplinflags.MevRelayUrl.Name)
+	cfg.MevRelayTimeout = ctx.Duration(utils.CaplinMevRelayTimeoutFlag.Name)
+	cfg.MevBuilderBoostFactor = ctx.Uint64(utils.CaplinMevBuilderBoostFactorFlag.Name)
 
 	// Custom Chain
 	cfg.CustomConfig = ctx.String(caplinflags.CustomConfig.Name)
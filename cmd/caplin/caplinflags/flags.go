@@ -33,6 +33,8 @@ var CliFlags = []cli.Flag{
 	&EngineApiHostFlag,
 	&EngineApiPortFlag,
 	&MevRelayUrl,
+	&utils.CaplinMevRelayTimeoutFlag,
+	&utils.CaplinMevBuilderBoostFactorFlag,
 	&JwtSecret,
 	&CustomConfig,
 	&CustomGenesisState,
@@ -41,7 +43,13 @@ var CliFlags = []cli.Flag{
 	&utils.BeaconApiAllowMethodsFlag,
 	&utils.BeaconApiAllowOriginsFlag,
 	&utils.CaplinCheckpointSyncUrlFlag,
+	&utils.CaplinCheckpointSyncStateFileFlag,
+	&utils.CaplinCheckpointSyncBlockFileFlag,
+	&utils.CaplinCheckpointSyncFallbackFlag,
+	&utils.CaplinWeakSubjectivityCheckpointFlag,
 	&utils.CaplinMaxPeerCount,
+	&utils.CaplinCustodyGroupCountFlag,
+	&utils.CaplinGossipTraceFileFlag,
 }
 
 var (
@@ -104,7 +112,7 @@ var (
 	}
 	MevRelayUrl = cli.StringFlag{
 		Name:  "mev-relay-url",
-		Usage: "Http URL of the MEV relay",
+		Usage: "Http URL of the MEV relay, or a comma separated list of relays",
 		Value: "",
 	}
 	CustomConfig = cli.StringFlag{
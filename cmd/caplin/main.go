@@ -115,8 +115,17 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		BeaconAPIRouter:        rcfg,
 		NetworkId:              networkId,
 		MevRelayUrl:            cfg.MevRelayUrl,
+		MevRelayTimeout:        cfg.MevRelayTimeout,
+		MevBuilderBoostFactor:  cfg.MevBuilderBoostFactor,
 		CustomConfigPath:       cfg.CustomConfig,
 		CustomGenesisStatePath: cfg.CustomGenesisState,
 		MaxPeerCount:           cfg.MaxPeerCount,
+		CustodyGroupCount:      cfg.CustodyGroupCount,
+		GossipTraceFile:        cfg.GossipTraceFile,
+
+		CheckpointSyncStateFile:    cfg.CheckpointStateFile,
+		CheckpointSyncBlockFile:    cfg.CheckpointBlockFile,
+		CheckpointSyncFallback:     cfg.CheckpointSyncFallback,
+		WeakSubjectivityCheckpoint: cfg.WeakSubjectivityCheckpoint,
 	}, cfg.Dirs, nil, nil, nil, blockSnapBuildSema)
 }
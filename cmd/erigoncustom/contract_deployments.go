@@ -0,0 +1,78 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/eth/stagedsync"
+)
+
+// defining a custom table name
+const contractDeploymentsTable = "custom.ContractDeployments" // blockNum_u64 + address -> txnHash (empty for system txs)
+
+// contractDeployments - example of custom indexer: all contracts created by txs and internal calls (including re-deployments)
+type contractDeployments struct{}
+
+func (contractDeployments) Name() string { return "ContractDeployments" }
+
+func (contractDeployments) Tables() kv.TableCfg {
+	return kv.TableCfg{contractDeploymentsTable: {}}
+}
+
+// NewTracer - no need: state changes of txn have all deployed code
+func (contractDeployments) NewTracer() exec3.GenericTracer { return nil }
+
+func (contractDeployments) Index(txTask *state.TxTask, _ exec3.GenericTracer, _ kv.Tx, w stagedsync.CustomIndexWriter) error {
+	codeWrites, ok := txTask.WriteLists[kv.CodeDomain.String()]
+	if !ok {
+		return nil
+	}
+	var txnHash []byte
+	if txTask.Tx != nil {
+		txnHash = txTask.Tx.Hash().Bytes()
+	}
+	for i, addr := range codeWrites.Keys {
+		if len(codeWrites.Vals[i]) == 0 {
+			continue
+		}
+		k := append(hexutility.EncodeTs(txTask.BlockNum), addr...)
+		if err := w.Put(contractDeploymentsTable, k, txnHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (contractDeployments) Unwind(tx kv.RwTx, unwindTo uint64) error {
+	c, err := tx.RwCursor(contractDeploymentsTable)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(hexutility.EncodeTs(unwindTo + 1)); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/params"
+)
+
+// keccakPrecompile - example of custom precompile: keccak256 of input, same price as KECCAK256 opcode.
+// Activated by chain config of custom network, for example:
+//
+//	"customPrecompiles": [{"name": "keccak", "address": "0x0000000000000000000000000000000000001000", "block": 100}]
+type keccakPrecompile struct{}
+
+func (keccakPrecompile) RequiredGas(input []byte) uint64 {
+	return params.Keccak256Gas + uint64(len(input)+31)/32*params.Keccak256WordGas
+}
+
+func (keccakPrecompile) Run(input []byte) ([]byte, error) {
+	return crypto.Keccak256(input), nil
+}
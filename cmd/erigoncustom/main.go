@@ -22,8 +22,14 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/stagedsync"
 	erigonapp "github.com/erigontech/erigon/turbo/app"
 	erigoncli "github.com/erigontech/erigon/turbo/cli"
+	"github.com/erigontech/erigon/turbo/debug"
+	"github.com/erigontech/erigon/turbo/node"
 )
 
 // defining a custom command-line flag, a string
@@ -32,10 +38,15 @@ var flag = cli.StringFlag{
 	Value: "default-value",
 }
 
-// defining a custom bucket name
-const (
-	customBucketName = "ch.torquem.demo.tgcustom.CUSTOM_BUCKET" //nolint
-)
+// registering custom indexers: must happen before chaindata open - because it adds indexer's tables
+func init() {
+	stagedsync.RegisterCustomIndexer(&contractDeployments{})
+}
+
+// registering custom precompiles: they are active only on networks which chain config has them in `customPrecompiles`
+func init() {
+	vm.RegisterPrecompile("keccak", keccakPrecompile{})
+}
 
 // the regular main function
 func main() {
@@ -50,19 +61,30 @@ func main() {
 }
 
 // Erigon main function
-func runErigon(ctx *cli.Context) error {
-	// running a node and initializing a custom bucket with all default settings
-	//eri := node.New(ctx, node.Params{
-	//	CustomBuckets: map[string]dbutils.BucketConfigItem{
-	//		customBucketName: {},
-	//	},
-	//})
+func runErigon(cliCtx *cli.Context) error {
+	logger, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	logger.Info("Custom stage", "greeting", cliCtx.String(flag.Name))
 
-	//err := eri.Serve()
+	nodeCfg, err := node.NewNodConfigUrfave(cliCtx, logger)
+	if err != nil {
+		return err
+	}
+	if err := datadir.ApplyMigrations(nodeCfg.Dirs); err != nil {
+		return err
+	}
+	ethCfg := node.NewEthConfigUrfave(cliCtx, nodeCfg, logger)
 
-	//if err != nil {
-	//	log.Error("error while serving a Erigon node", "err", err)
-	//  return err
-	//}
-	return nil
+	// running a node: custom indexers are part of it's StagedSync
+	ethNode, err := node.New(cliCtx.Context, nodeCfg, ethCfg, logger)
+	if err != nil {
+		log.Error("Erigon startup", "err", err)
+		return err
+	}
+	if err = ethNode.Serve(); err != nil {
+		log.Error("error while serving a Erigon node", "err", err)
+	}
+	return err
 }
@@ -0,0 +1,237 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain"
+	libcommon "github.com/erigontech/erigon-lib/common"
+
+	"github.com/erigontech/erigon/core/vm"
+)
+
+// Coverage - which opcodes, precompiles and chain rules were reached by state tests corpus.
+// Collected by coverageTracer across all executed subtests, written by `statetest --coverage.json/--coverage.html`.
+type Coverage struct {
+	Subtests int                      `json:"subtests"`
+	Forks    map[string]*ForkCoverage `json:"forks"`
+	Rules    map[string]*RuleCoverage `json:"rules"` // by field of chain.Rules
+}
+
+// ForkCoverage - coverage of subtests of one fork (as named in test: `Cancun`, `Berlin+1884`, ...)
+type ForkCoverage struct {
+	Subtests          int               `json:"subtests"`
+	Opcodes           map[string]uint64 `json:"opcodes"`     // executions by opcode name
+	Precompiles       map[string]uint64 `json:"precompiles"` // calls by address
+	MissedOpcodes     []string          `json:"missedOpcodes"`
+	MissedPrecompiles []string          `json:"missedPrecompiles"`
+
+	rules *chain.Rules // of first executed txn, nil if no txn reached EVM
+}
+
+// RuleCoverage - number of subtests which reached EVM with rule enabled/disabled
+type RuleCoverage struct {
+	Enabled  int `json:"enabled"`
+	Disabled int `json:"disabled"`
+}
+
+func newCoverage() *Coverage {
+	return &Coverage{Forks: map[string]*ForkCoverage{}, Rules: map[string]*RuleCoverage{}}
+}
+
+// finalize - fills missed opcodes/precompiles: active at fork, but never reached
+func (c *Coverage) finalize() {
+	for _, f := range c.Forks {
+		if f.rules == nil {
+			continue
+		}
+		f.MissedOpcodes = f.MissedOpcodes[:0]
+		for _, op := range vm.ActiveOpcodes(f.rules) {
+			if _, ok := f.Opcodes[op.String()]; !ok {
+				f.MissedOpcodes = append(f.MissedOpcodes, op.String())
+			}
+		}
+		f.MissedPrecompiles = f.MissedPrecompiles[:0]
+		for _, addr := range vm.ActivePrecompiles(f.rules) {
+			if _, ok := f.Precompiles[addr.Hex()]; !ok {
+				f.MissedPrecompiles = append(f.MissedPrecompiles, addr.Hex())
+			}
+		}
+		slices.Sort(f.MissedPrecompiles)
+	}
+}
+
+func (c *Coverage) writeJSON(fname string) error {
+	out, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fname, out, 0644)
+}
+
+var coverageHTML = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>EVM coverage</title>
+<style>
+body { font-family: sans-serif; } table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; } .missed { background: #f8d0d0; }
+</style></head><body>
+<h1>EVM coverage of {{.Subtests}} subtests</h1>
+<h2>Rules</h2>
+<table><tr><th>rule</th><th>enabled</th><th>disabled</th></tr>
+{{range $name, $r := .Rules}}<tr><td>{{$name}}</td><td{{if eq $r.Enabled 0}} class="missed"{{end}}>{{$r.Enabled}}</td><td>{{$r.Disabled}}</td></tr>
+{{end}}</table>
+{{range $fork, $f := .Forks}}<h2>{{$fork}}: {{$f.Subtests}} subtests, {{len $f.Opcodes}} opcodes and {{len $f.Precompiles}} precompiles reached</h2>
+{{if $f.MissedOpcodes}}<p>Missed opcodes:</p><table><tr>{{range $f.MissedOpcodes}}<td class="missed">{{.}}</td>{{end}}</tr></table>{{end}}
+{{if $f.MissedPrecompiles}}<p>Missed precompiles:</p><table>{{range $f.MissedPrecompiles}}<tr><td class="missed">{{.}}</td></tr>{{end}}</table>{{end}}
+<table><tr><th>opcode</th><th>executed</th></tr>
+{{range $op, $n := $f.Opcodes}}<tr><td>{{$op}}</td><td>{{$n}}</td></tr>
+{{end}}</table>
+{{if $f.Precompiles}}<table><tr><th>precompile</th><th>calls</th></tr>
+{{range $addr, $n := $f.Precompiles}}<tr><td>{{$addr}}</td><td>{{$n}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}</body></html>
+`))
+
+func (c *Coverage) writeHTML(fname string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := coverageHTML.Execute(f, c); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// coverageTracer - collects Coverage, passes all events to `inner` tracer (if any)
+type coverageTracer struct {
+	inner     vm.EVMLogger
+	cov       *Coverage
+	fork      *ForkCoverage
+	seenRules bool // rules of current subtest are counted
+}
+
+func newCoverageTracer(cov *Coverage, inner vm.EVMLogger) *coverageTracer {
+	return &coverageTracer{cov: cov, inner: inner}
+}
+
+// startSubtest - must be called before each subtest
+func (t *coverageTracer) startSubtest(fork string) {
+	f, ok := t.cov.Forks[fork]
+	if !ok {
+		f = &ForkCoverage{Opcodes: map[string]uint64{}, Precompiles: map[string]uint64{}}
+		t.cov.Forks[fork] = f
+	}
+	f.Subtests++
+	t.cov.Subtests++
+	t.fork, t.seenRules = f, false
+}
+
+func (t *coverageTracer) captureRules(rules *chain.Rules) {
+	if t.seenRules {
+		return
+	}
+	t.seenRules = true
+	if t.fork.rules == nil {
+		t.fork.rules = rules
+	}
+	v := reflect.ValueOf(rules).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Bool {
+			continue
+		}
+		name := v.Type().Field(i).Name
+		r, ok := t.cov.Rules[name]
+		if !ok {
+			r = &RuleCoverage{}
+			t.cov.Rules[name] = r
+		}
+		if v.Field(i).Bool() {
+			r.Enabled++
+		} else {
+			r.Disabled++
+		}
+	}
+}
+
+func (t *coverageTracer) capturePrecompile(to libcommon.Address) {
+	t.fork.Precompiles[to.Hex()]++
+}
+
+func (t *coverageTracer) CaptureTxStart(gasLimit uint64) {
+	if t.inner != nil {
+		t.inner.CaptureTxStart(gasLimit)
+	}
+}
+
+func (t *coverageTracer) CaptureTxEnd(restGas uint64) {
+	if t.inner != nil {
+		t.inner.CaptureTxEnd(restGas)
+	}
+}
+
+func (t *coverageTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.captureRules(env.ChainRules())
+	if precompile {
+		t.capturePrecompile(to)
+	}
+	if t.inner != nil {
+		t.inner.CaptureStart(env, from, to, precompile, create, input, gas, value, code)
+	}
+}
+
+func (t *coverageTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	if t.inner != nil {
+		t.inner.CaptureEnd(output, usedGas, err)
+	}
+}
+
+func (t *coverageTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if precompile {
+		t.capturePrecompile(to)
+	}
+	if t.inner != nil {
+		t.inner.CaptureEnter(typ, from, to, precompile, create, input, gas, value, code)
+	}
+}
+
+func (t *coverageTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	if t.inner != nil {
+		t.inner.CaptureExit(output, usedGas, err)
+	}
+}
+
+func (t *coverageTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	t.fork.Opcodes[op.String()]++
+	if t.inner != nil {
+		t.inner.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *coverageTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.inner != nil {
+		t.inner.CaptureFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
@@ -35,7 +35,6 @@ import (
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/tracing"
 	"github.com/erigontech/erigon/core/types"
-	"github.com/erigontech/erigon/turbo/rpchelper"
 )
 
 type Prestate struct {
@@ -84,7 +83,7 @@ type stEnvMarshaling struct {
 func MakePreState(chainRules *chain.Rules, tx kv.RwTx, sd *state3.SharedDomains, accounts types.GenesisAlloc) (state.StateReader, state.WriterWithChangeSets) {
 	var blockNr uint64 = 0
 
-	stateReader, stateWriter := rpchelper.NewLatestStateReader(tx), state.NewWriterV4(sd)
+	stateReader, stateWriter := state.NewReaderV3(sd), state.NewWriterV4(sd)
 	sd.SetBlockNum(blockNr)
 
 	statedb := state.New(stateReader) //ibs
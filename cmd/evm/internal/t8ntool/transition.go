@@ -298,52 +298,80 @@ func Main(ctx *cli.Context) error {
 		return h
 	}
 
-	db, agg := temporaltest.NewTestDB(nil, datadir.New(""))
+	// fresh db and files in temporary datadir - don't leave them in working directory
+	tmpDir, err := os.MkdirTemp("", "evm-t8n-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	result, collector, err := Transition(chainConfig, &vmConfig, block, prestate.Pre, getHash, getTracer, datadir.New(tmpDir))
+	if hashError != nil {
+		return NewError(ErrorMissingBlockhash, fmt.Errorf("blockhash error: %v", hashError))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Dump the execution result
+	body, _ := rlp.EncodeToBytes(txs)
+	return dispatchOutput(ctx, baseDir, result, collector, body)
+}
+
+// Transition - executes `block` on top of `pre` state in a fresh in-memory database (files go to `dirs`).
+// All txs of block share one IntraBlockState, which is written into SharedDomains once - at the end of block.
+// Returns execution result (with post-state root) and post-state alloc.
+func Transition(chainConfig *chain.Config, vmConfig *vm.Config, block *types.Block, pre types.GenesisAlloc,
+	getHash func(num uint64) libcommon.Hash, getTracer func(txIndex int, txHash libcommon.Hash) (vm.EVMLogger, error), dirs datadir.Dirs,
+) (*core.EphemeralExecResult, Alloc, error) {
+	ctx := context.Background()
+	db, agg := temporaltest.NewTestDB(nil, dirs)
 	defer db.Close()
 	defer agg.Close()
 
-	tx, err := db.BeginRw(context.Background())
+	tx, err := db.BeginRw(ctx)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer tx.Rollback()
 
 	sd, err := libstate.NewSharedDomains(tx, log.New())
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer sd.Close()
 
-	reader, writer := MakePreState(chainConfig.Rules(0, 0), tx, sd, prestate.Pre)
+	reader, writer := MakePreState(chainConfig.Rules(0, 0), tx, sd, pre)
 	// Merge engine can be used for pre-merge blocks as well, as it
 	// redirects to the ethash engine based on the block number
 	engine := merge.New(&ethash.FakeEthash{})
 
 	t8logger := log.New("t8ntool")
 	chainReader := consensuschain.NewReader(chainConfig, tx, nil, t8logger)
-	result, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, getHash, engine, block, reader, writer, chainReader, getTracer, t8logger)
-	if hashError != nil {
-		return NewError(ErrorMissingBlockhash, fmt.Errorf("blockhash error: %v", err))
-	}
-
+	result, err := core.ExecuteBlockEphemerally(chainConfig, vmConfig, getHash, engine, block, reader, writer, chainReader, getTracer, t8logger)
 	if err != nil {
-		return fmt.Errorf("error on EBE: %w", err)
+		return nil, nil, fmt.Errorf("error on EBE: %w", err)
 	}
 
 	// state root calculation
-	root, err := CalculateStateRoot(tx)
+	root, err := sd.ComputeCommitment(ctx, true, sd.BlockNum(), "")
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	result.StateRoot = *root
+	result.StateRoot = libcommon.BytesToHash(root)
 
-	// Dump the execution result
-	body, _ := rlp.EncodeToBytes(txs)
+	// post-state is dumped from DB: flush domains and mark all their writes as belonging to `block`
+	if err = sd.Flush(ctx, tx); err != nil {
+		return nil, nil, err
+	}
+	if err = rawdbv3.TxNums.Append(tx, block.NumberU64(), sd.TxNum()); err != nil {
+		return nil, nil, err
+	}
 	collector := make(Alloc)
-
-	dumper := state.NewDumper(tx, rawdbv3.TxNums, prestate.Env.Number)
-	dumper.DumpToCollector(collector, false, false, libcommon.Address{}, 0)
-	return dispatchOutput(ctx, baseDir, result, collector, body)
+	dumper := state.NewDumper(tx, rawdbv3.TxNums, block.NumberU64())
+	if _, err = dumper.DumpToCollector(collector, false, false, libcommon.Address{}, 0); err != nil {
+		return nil, nil, err
+	}
+	return result, collector, nil
 }
 
 // txWithKey is a helper-struct, to allow us to use the types.Transaction along with
@@ -618,6 +646,11 @@ func NewHeader(env stEnv) *types.Header {
 	return &header
 }
 
+// CalculateStateRoot - root of state which is already in `tx` (plain state is re-hashed, domains are read from `tx`).
+//
+// Deprecated: t8n doesn't use it anymore - writes of block execution stay in SharedDomains until commitment, so state root
+// is computed by SharedDomains.ComputeCommitment of those domains (see Transition). This function sees only flushed data:
+// for t8n it returned root of empty state.
 func CalculateStateRoot(tx kv.RwTx) (*libcommon.Hash, error) {
 	// Generate hashed state
 	c, err := tx.RwCursor(kv.PlainState)
@@ -0,0 +1,269 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !nofuzz
+
+package t8ntool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/consensus/ethash"
+	"github.com/erigontech/erigon/consensus/merge"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/eth/consensuschain"
+	"github.com/erigontech/erigon/tests"
+)
+
+// Differential fuzzing of EVM-equivalence: random block executed by `evm t8n` (all txs in one IntraBlockState,
+// written into domains once) must produce the same state root, receipts and logs as execution tx-by-tx
+// through IntraBlockState + SharedDomains (as ExecV3 does: each txn has own txNum and reads previous txs from domains).
+//
+// go test -trimpath -v -fuzz=FuzzTransitionVsDomains -fuzztime=60s ./cmd/evm/internal/t8ntool
+
+func FuzzTransitionVsDomains(f *testing.F) {
+	f.Add([]byte{})
+	// SSTORE(0, 1); LOG0(0, 0); STOP - called by 2 txs
+	f.Add([]byte{11, 0x60, 0x01, 0x60, 0x00, 0x55, 0x60, 0x00, 0x60, 0x00, 0xa0, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 3, 1, 0, 0, 0, 3, 1, 0, 0, 0})
+	// SELFDESTRUCT(CALLER) and contract creation
+	f.Add([]byte{2, 0x33, 0xff, 1, 0x01, 7, 0x42, 0, 0, 0, 0, 0, 0, 0, 2, 0, 3, 0, 0, 0, 0, 2, 5, 1, 9, 9, 0, 4, 0x60, 0x00, 0x60, 0x00})
+	f.Fuzz(func(t *testing.T, in []byte) {
+		chainConfig, _, err := tests.GetChainConfig("Shanghai")
+		require.NoError(t, err)
+		pre, block := genFuzzBlock(chainConfig, &fuzzInput{data: in})
+
+		expect, _, err := Transition(chainConfig, &vm.Config{StatelessExec: true}, block, pre, fuzzGetHash, nil, datadir.New(t.TempDir()))
+		require.NoError(t, err)
+		require.Empty(t, expect.Rejected) // generated txs are always valid
+
+		root, receipts := executeTxByTx(t, chainConfig, block, pre)
+		require.Equal(t, expect.StateRoot, root, "state root")
+		require.Equal(t, len(expect.Receipts), len(receipts))
+		for i, r := range receipts {
+			e := expect.Receipts[i]
+			require.Equal(t, e.Status, r.Status, "txn %d status", i)
+			require.Equal(t, e.GasUsed, r.GasUsed, "txn %d gasUsed", i)
+			require.Equal(t, e.CumulativeGasUsed, r.CumulativeGasUsed, "txn %d cumulativeGasUsed", i)
+			require.Equal(t, e.ContractAddress, r.ContractAddress, "txn %d contractAddress", i)
+			require.Equal(t, e.Bloom, r.Bloom, "txn %d bloom", i)
+			require.Equal(t, len(e.Logs), len(r.Logs), "txn %d logs", i)
+			for j, l := range r.Logs {
+				// log.Index is not compared: it's assigned per block, while tx-by-tx execution numbers logs per txn
+				require.Equal(t, e.Logs[j].Address, l.Address, "txn %d log %d address", i, j)
+				require.Equal(t, e.Logs[j].Topics, l.Topics, "txn %d log %d topics", i, j)
+				require.Equal(t, e.Logs[j].Data, l.Data, "txn %d log %d data", i, j)
+			}
+		}
+		require.Equal(t, expect.ReceiptRoot, types.DeriveSha(receipts), "receipts root")
+	})
+}
+
+// executeTxByTx - executes `block` on top of `pre` state the way ExecV3 does: block initialisation, each txn
+// and block finalisation have own txNum and own IntraBlockState, which is flushed into SharedDomains after each of them
+func executeTxByTx(t *testing.T, chainConfig *chain.Config, block *types.Block, pre types.GenesisAlloc) (libcommon.Hash, types.Receipts) {
+	t.Helper()
+	ctx, logger := context.Background(), log.New()
+	db, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	tx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	sd, err := libstate.NewSharedDomains(tx, logger)
+	require.NoError(t, err)
+	defer sd.Close()
+
+	MakePreState(chainConfig.Rules(0, 0), tx, sd, pre)
+	reader, writer := state.NewReaderV3(sd), state.NewWriterV4(sd)
+	engine := merge.New(&ethash.FakeEthash{})
+	chainReader := consensuschain.NewReader(chainConfig, tx, nil, logger)
+	header := block.HeaderNoCopy()
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+
+	txNum := sd.TxNum() + 1
+	sd.SetTxNum(txNum)
+	ibs := state.New(reader)
+	require.NoError(t, core.InitializeBlockExecution(engine, chainReader, header, chainConfig, ibs, logger, nil))
+	require.NoError(t, ibs.FinalizeTx(rules, writer))
+
+	gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(chainConfig.GetMaxBlobGasPerBlock())
+	usedGas, usedBlobGas := new(uint64), new(uint64)
+	receipts := make(types.Receipts, 0, len(block.Transactions()))
+	for i, txn := range block.Transactions() {
+		txNum++
+		sd.SetTxNum(txNum)
+		ibs := state.New(reader)
+		ibs.SetTxContext(i)
+		receipt, _, err := core.ApplyTransaction(chainConfig, fuzzGetHash, engine, nil, gp, ibs, writer, header, txn, usedGas, usedBlobGas, vm.Config{}) // finalizes txn into `writer`
+		require.NoError(t, err)
+		receipts = append(receipts, receipt)
+	}
+
+	txNum++
+	sd.SetTxNum(txNum)
+	ibs = state.New(reader)
+	_, _, _, _, err = core.FinalizeBlockExecution(engine, reader, header, block.Transactions(), block.Uncles(), writer, chainConfig, ibs, receipts, block.Withdrawals(), chainReader, false, logger)
+	require.NoError(t, err)
+
+	root, err := sd.ComputeCommitment(ctx, true, header.Number.Uint64(), "")
+	require.NoError(t, err)
+	return libcommon.BytesToHash(root), receipts
+}
+
+var fuzzKeys = func() (keys []*ecdsa.PrivateKey) {
+	for i := byte(1); i <= 3; i++ {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte{i}))
+		if err != nil {
+			panic(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}()
+
+func fuzzGetHash(n uint64) libcommon.Hash {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	return crypto.Keccak256Hash(b[:])
+}
+
+// fuzzInput - reads fuzzer's bytes, returns zeros when they are over: any input is a valid block
+type fuzzInput struct {
+	data []byte
+	pos  int
+}
+
+func (in *fuzzInput) byte() byte {
+	if in.pos >= len(in.data) {
+		return 0
+	}
+	in.pos++
+	return in.data[in.pos-1]
+}
+
+func (in *fuzzInput) bytes(n int) []byte {
+	res := make([]byte, n)
+	for i := range res {
+		res[i] = in.byte()
+	}
+	return res
+}
+
+func (in *fuzzInput) uint16() uint64 { return uint64(in.byte())<<8 | uint64(in.byte()) }
+
+// genFuzzBlock - prestate of 3 funded EOAs and 3 contracts with random code and storage, and block of up to 7 valid txs
+// (right nonces and enough gas and balance), which call contracts, send value or create new contracts
+func genFuzzBlock(chainConfig *chain.Config, in *fuzzInput) (types.GenesisAlloc, *types.Block) {
+	pre := types.GenesisAlloc{}
+	var senders, contracts []libcommon.Address
+	for _, key := range fuzzKeys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		senders = append(senders, addr)
+		pre[addr] = types.GenesisAccount{Balance: big.NewInt(1e18)}
+	}
+	for i := byte(0); i < 3; i++ {
+		addr := libcommon.Address{0xc0, i}
+		contracts = append(contracts, addr)
+		acc := types.GenesisAccount{Balance: big.NewInt(int64(in.byte())), Code: in.bytes(int(in.byte() % 64))}
+		if slots := in.byte() % 3; slots > 0 {
+			acc.Storage = map[libcommon.Hash]libcommon.Hash{}
+			for j := byte(0); j < slots; j++ {
+				acc.Storage[libcommon.Hash{31: in.byte()}] = libcommon.Hash{31: in.byte()}
+			}
+		}
+		if len(acc.Code) == 0 && len(acc.Storage) == 0 {
+			acc.Code = []byte{0x00} // STOP - keep it contract
+		}
+		pre[addr] = acc
+	}
+	// coinbase, txn recipients and withdrawals pick among senders, contracts, and fresh address
+	pick := func(b byte) libcommon.Address {
+		switch i := int(b) % (len(senders) + len(contracts) + 1); {
+		case i < len(senders):
+			return senders[i]
+		case i < len(senders)+len(contracts):
+			return contracts[i-len(senders)]
+		default:
+			return libcommon.Address{0xee, b}
+		}
+	}
+
+	header := &types.Header{
+		Coinbase:   pick(in.byte()),
+		Difficulty: big.NewInt(0),
+		GasLimit:   30_000_000,
+		Number:     big.NewInt(1),
+		Time:       1,
+		BaseFee:    big.NewInt(7),
+		MixDigest:  libcommon.Hash{in.byte()},
+	}
+	signer := types.MakeSigner(chainConfig, header.Number.Uint64(), header.Time)
+	nonces := make([]uint64, len(fuzzKeys))
+	txs := make(types.Transactions, 0)
+	for n := in.byte() % 8; n > 0; n-- {
+		from := int(in.byte()) % len(fuzzKeys)
+		value := uint256.NewInt(uint64(in.byte()))
+		gas := 100_000 + in.uint16()*8
+		var to *libcommon.Address
+		if b := in.byte(); b%5 != 0 {
+			addr := pick(b)
+			to = &addr
+		}
+		data := in.bytes(int(in.byte() % 32))
+
+		var txn types.Transaction
+		if in.byte()%2 == 0 {
+			txn = &types.LegacyTx{
+				CommonTx: types.CommonTx{Nonce: nonces[from], Gas: gas, To: to, Value: value, Data: data},
+				GasPrice: uint256.NewInt(10),
+			}
+		} else {
+			txn = &types.DynamicFeeTransaction{
+				CommonTx: types.CommonTx{Nonce: nonces[from], Gas: gas, To: to, Value: value, Data: data},
+				ChainID:  uint256.MustFromBig(chainConfig.ChainID),
+				Tip:      uint256.NewInt(uint64(in.byte() % 4)),
+				FeeCap:   uint256.NewInt(10),
+			}
+		}
+		signed, err := types.SignTx(txn, *signer, fuzzKeys[from])
+		if err != nil {
+			panic(err)
+		}
+		txs = append(txs, signed)
+		nonces[from]++
+	}
+
+	withdrawals := make([]*types.Withdrawal, 0)
+	for n := in.byte() % 3; n > 0; n-- {
+		withdrawals = append(withdrawals, &types.Withdrawal{Index: uint64(len(withdrawals)), Address: pick(in.byte()), Amount: uint64(in.byte())})
+	}
+	return pre, types.NewBlock(header, txs, nil, nil, withdrawals)
+}
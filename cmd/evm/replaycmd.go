@@ -0,0 +1,65 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/eth/ethconsensusconfig"
+	"github.com/erigontech/erigon/turbo/replay/flightrecorder"
+)
+
+var replayCommand = cli.Command{
+	Action:    replayCmd,
+	Name:      "replay",
+	Usage:     "re-executes flight recording of bad block (<datadir>/flight-recorder/*.json.gz) and finds first diverging txn and opcode",
+	ArgsUsage: "<file>",
+}
+
+func replayCmd(ctx *cli.Context) error {
+	if len(ctx.Args().First()) == 0 {
+		return errors.New("missing recording filename")
+	}
+	rec, err := flightrecorder.ReadRecording(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	block, err := rec.DecodeBlock()
+	if err != nil {
+		return err
+	}
+	logger := log.New()
+	engine := ethconsensusconfig.CreateConsensusEngineBareBones(ctx.Context, rec.ChainConfig, logger)
+	defer engine.Close()
+
+	fmt.Printf("block %d (%x), %d txs, recorded: %s\n", block.NumberU64(), block.Hash(), len(block.Transactions()), rec.Reason)
+	d, err := flightrecorder.Replay(rec, engine, logger)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		fmt.Println("replay matches recording: execution is deterministic, look for the bug in state commitment or in recording node binary")
+		return nil
+	}
+	fmt.Printf("divergence: %s\n", d)
+	return errors.New("replay diverged from recording")
+}
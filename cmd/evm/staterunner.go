@@ -45,11 +45,26 @@ import (
 	"github.com/erigontech/erigon/tests"
 )
 
+var (
+	CoverageJSONFlag = cli.StringFlag{
+		Name:  "coverage.json",
+		Usage: "writes opcode, precompile and chain rules coverage of executed tests to the given path",
+	}
+	CoverageHTMLFlag = cli.StringFlag{
+		Name:  "coverage.html",
+		Usage: "writes html summary of opcode, precompile and chain rules coverage of executed tests to the given path",
+	}
+)
+
 var stateTestCommand = cli.Command{
 	Action:    stateTestCmd,
 	Name:      "statetest",
 	Usage:     "executes the given state tests",
 	ArgsUsage: "<file>",
+	Flags: []cli.Flag{
+		&CoverageJSONFlag,
+		&CoverageHTMLFlag,
+	},
 }
 
 // StatetestResult contains the execution status after running a state test, any
@@ -87,16 +102,44 @@ func stateTestCmd(ctx *cli.Context) error {
 		cfg.Tracer = logger.NewStructLogger(config)
 	}
 
+	var cov *coverageTracer
+	if ctx.IsSet(CoverageJSONFlag.Name) || ctx.IsSet(CoverageHTMLFlag.Name) {
+		cov = newCoverageTracer(newCoverage(), cfg.Tracer)
+		cfg.Debug, cfg.Tracer = true, cov
+	}
+
 	if len(ctx.Args().First()) != 0 {
-		return runStateTest(ctx.Args().First(), cfg, ctx.Bool(MachineFlag.Name))
+		if err := runStateTest(ctx.Args().First(), cfg, cov, ctx.Bool(MachineFlag.Name)); err != nil {
+			return err
+		}
+		return writeCoverage(ctx, cov)
 	}
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		fname := scanner.Text()
 		if len(fname) == 0 {
-			return nil
+			break
+		}
+		if err := runStateTest(fname, cfg, cov, ctx.Bool(MachineFlag.Name)); err != nil {
+			return err
 		}
-		if err := runStateTest(fname, cfg, ctx.Bool(MachineFlag.Name)); err != nil {
+	}
+	return writeCoverage(ctx, cov)
+}
+
+// writeCoverage - writes coverage of all executed tests (if requested)
+func writeCoverage(ctx *cli.Context, cov *coverageTracer) error {
+	if cov == nil {
+		return nil
+	}
+	cov.cov.finalize()
+	if fname := ctx.String(CoverageJSONFlag.Name); fname != "" {
+		if err := cov.cov.writeJSON(fname); err != nil {
+			return err
+		}
+	}
+	if fname := ctx.String(CoverageHTMLFlag.Name); fname != "" {
+		if err := cov.cov.writeHTML(fname); err != nil {
 			return err
 		}
 	}
@@ -104,7 +147,7 @@ func stateTestCmd(ctx *cli.Context) error {
 }
 
 // runStateTest loads the state-test given by fname, and executes the test.
-func runStateTest(fname string, cfg vm.Config, jsonOut bool) error {
+func runStateTest(fname string, cfg vm.Config, cov *coverageTracer, jsonOut bool) error {
 	// Load the test content from the input file
 	src, err := os.ReadFile(fname)
 	if err != nil {
@@ -116,7 +159,7 @@ func runStateTest(fname string, cfg vm.Config, jsonOut bool) error {
 	}
 
 	// Iterate over all the stateTests, run them and aggregate the results
-	results, err := aggregateResultsFromStateTests(stateTests, cfg, jsonOut)
+	results, err := aggregateResultsFromStateTests(stateTests, cfg, cov, jsonOut)
 	if err != nil {
 		return err
 	}
@@ -127,7 +170,7 @@ func runStateTest(fname string, cfg vm.Config, jsonOut bool) error {
 }
 
 func aggregateResultsFromStateTests(
-	stateTests map[string]tests.StateTest, cfg vm.Config,
+	stateTests map[string]tests.StateTest, cfg vm.Config, cov *coverageTracer,
 	jsonOut bool) ([]StatetestResult, error) {
 	dirs := datadir.New(filepath.Join(os.TempDir(), "erigon-statetest"))
 	//this DB is shared. means:
@@ -165,6 +208,9 @@ func aggregateResultsFromStateTests(
 		for _, st := range test.Subtests() {
 			// Run the test and aggregate the result
 			result := &StatetestResult{Name: key, Fork: st.Fork, Pass: true}
+			if cov != nil {
+				cov.startSubtest(st.Fork)
+			}
 
 			statedb, root, err := test.Run(tx, st, cfg, dirs)
 			if err != nil {
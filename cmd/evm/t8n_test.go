@@ -253,6 +253,74 @@ func TestT8n(t *testing.T) {
 	}
 }
 
+// TestT8nPostState - post-state alloc and roots of `evm t8n` must match expected outputs. Unlike TestT8n, ignores
+// formatting of other result fields (e.g. empty receipts).
+func TestT8nPostState(t *testing.T) {
+	tt := new(testT8n)
+	tt.TestCmd = cmdtest.NewTestCmd(t, tt)
+	for _, tc := range []struct {
+		base string
+		fork string
+	}{
+		{base: "./testdata/3", fork: "Berlin"},
+		{base: "./testdata/5", fork: "Byzantium"},
+		{base: "./testdata/7", fork: "HomesteadToDaoAt5"},
+		{base: "./testdata/8", fork: "Berlin"},
+		{base: "./testdata/9", fork: "London"},
+		{base: "./testdata/12", fork: "London"},
+		{base: "./testdata/26", fork: "Shanghai"},
+		{base: "./testdata/27", fork: "Shanghai"},
+	} {
+		args := []string{"t8n"}
+		args = append(args, (&t8nOutput{alloc: true, result: true}).get()...)
+		args = append(args, (&t8nInput{"alloc.json", "txs.json", "env.json", tc.fork}).get(tc.base)...)
+		tt.Run("evm-test", args...)
+		want, err := os.ReadFile(fmt.Sprintf("%v/exp.json", tc.base))
+		if err != nil {
+			t.Fatalf("%s: could not read expected output: %v", tc.base, err)
+		}
+		have := tt.Output()
+		tt.WaitExit()
+		if tt.ExitStatus() != 0 {
+			t.Fatalf("%s: wrong exit code %d", tc.base, tt.ExitStatus())
+		}
+		h, err := parsePostState(have)
+		if err != nil {
+			t.Fatalf("%s: json parsing failed: %v", tc.base, err)
+		}
+		w, err := parsePostState(want)
+		if err != nil {
+			t.Fatalf("%s: json parsing failed: %v", tc.base, err)
+		}
+		if !reflect.DeepEqual(h, w) {
+			t.Fatalf("%s: post-state wrong, have \n%v\nwant\n%v\n", tc.base, string(have), string(want))
+		}
+	}
+}
+
+type t8nPostState struct {
+	Alloc  map[string]interface{} `json:"alloc"`
+	Result struct {
+		StateRoot    string `json:"stateRoot"`
+		ReceiptsRoot string `json:"receiptsRoot"`
+		LogsHash     string `json:"logsHash"`
+		GasUsed      string `json:"gasUsed"`
+	} `json:"result"`
+}
+
+func parsePostState(b []byte) (*t8nPostState, error) {
+	var s t8nPostState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	alloc := make(map[string]interface{}, len(s.Alloc))
+	for addr, acc := range s.Alloc {
+		alloc[strings.ToLower(addr)] = acc
+	}
+	s.Alloc = alloc
+	return &s, nil
+}
+
 // cmpJson compares the JSON in two byte slices.
 func cmpJson(a, b []byte) (bool, error) {
 	var j, j2 interface{}
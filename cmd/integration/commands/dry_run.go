@@ -0,0 +1,278 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/config3"
+	"github.com/erigontech/erigon-lib/downloader/snaptype"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
+
+	"github.com/erigontech/erigon/cmd/hack/tool/fromdb"
+	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/eth/stagedsync/stages"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+// stagePlan - what `integration stage_*` command would do with current flags. Built by `--dry-run` instead of running the stage.
+type stagePlan struct {
+	stage              stages.SyncStage
+	mode               string // forward, unwind, prune, reset
+	progress           uint64
+	fromBlock, toBlock uint64 // inclusive, empty if fromBlock > toBlock
+	fromTxNum, toTxNum uint64
+	alsoUnwinds        []stages.SyncStage
+	files              []string // snapshot files inside [fromBlock, toBlock]
+	filesAction        string   // what happens with `files`
+	notes              []string
+	timing             stages.StageTiming
+}
+
+// upstreamStages - stage which progress is the default target of forward run in `integration stage_*`
+var upstreamStages = map[stages.SyncStage]stages.SyncStage{
+	stages.Bodies:      stages.Headers,
+	stages.Senders:     stages.Bodies,
+	stages.Execution:   stages.Senders,
+	stages.CustomTrace: stages.Execution,
+	stages.TxLookup:    stages.Execution,
+}
+
+// printStagePlan - `--dry-run` of `integration stage_*`: logs which stages would run, over which block/txNum range,
+// which snapshot files are affected and estimated duration based on timings saved by previous runs.
+// Doesn't write anything: `db` is opened read-only by openStageDB and snapshots are opened here instead of allSnapshots,
+// because NewAggregator creates (or renames) state salt file if it's missing.
+func printStagePlan(db kv.RoDB, ctx context.Context, stage stages.SyncStage, logger log.Logger) error {
+	dirs := datadir.New(datadirCli)
+	snapCfg := ethconfig.NewSnapCfg(true, true, true, fromdb.ChainConfig(db).ChainName)
+	sn := freezeblocks.NewRoSnapshots(snapCfg, dirs.Snap, 0, logger)
+	defer sn.Close()
+	borSn := freezeblocks.NewBorRoSnapshots(snapCfg, dirs.Snap, 0, logger)
+	defer borSn.Close()
+	sn.OptimisticalyOpenFolder()
+	borSn.OptimisticalyOpenFolder()
+	blockFiles := append(sn.Files(), borSn.Files()...)
+	br := freezeblocks.NewBlockReader(sn, borSn)
+
+	var agg *libstate.Aggregator
+	saltExists, err := dir.FileExist(filepath.Join(dirs.Snap, "salt-state.txt"))
+	if err != nil {
+		return err
+	}
+	if saltExists {
+		if agg, err = libstate.NewAggregator(ctx, dirs, config3.HistoryV3AggregationStep, db, logger); err != nil {
+			return err
+		}
+		defer agg.Close()
+		if err = agg.OpenFolder(); err != nil {
+			return err
+		}
+	}
+
+	return db.View(ctx, func(tx kv.Tx) error {
+		txNumsReader := rawdbv3.TxNums.WithCustomReadTxNumFunc(freezeblocks.ReadTxNumFuncFromBlockReader(ctx, br))
+		p, err := newStagePlan(tx, stage, txNumsReader, blockFiles, agg)
+		if err != nil {
+			return err
+		}
+		p.print(logger)
+		return nil
+	})
+}
+
+// newStagePlan - `agg` is nil if datadir has no state files yet
+func newStagePlan(tx kv.Tx, stage stages.SyncStage, txNumsReader rawdbv3.TxNumsReader, blockFiles []string, agg *libstate.Aggregator) (*stagePlan, error) {
+	progress, err := stages.GetStageProgress(tx, stage)
+	if err != nil {
+		return nil, err
+	}
+	p := &stagePlan{stage: stage, progress: progress}
+	isStateStage := stage == stages.Execution || stage == stages.CustomTrace
+
+	switch {
+	case reset:
+		p.mode = "reset"
+		p.fromBlock, p.toBlock = 0, progress
+		if isStateStage {
+			p.notes = append(p.notes, "reset clears DB tables only: state files are kept and re-opened, next run starts from their end")
+		}
+	case unwind > 0 && stage != stages.CustomTrace:
+		p.mode = "unwind"
+		unwindTo := progress - min(unwind, progress)
+		if stage == stages.Headers {
+			unwindTo = max(1, unwindTo) // keep genesis
+		}
+		if stage == stages.Execution && agg != nil {
+			ac := agg.BeginFilesRo()
+			unwindable, ok, err := ac.CanUnwindBeforeBlockNum(unwindTo, tx)
+			ac.Close()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				p.notes = append(p.notes, fmt.Sprintf("state can't be unwound to %d: it's inside state files or below oldest changeset, will unwind to %d instead", unwindTo, unwindable))
+				unwindTo = unwindable
+			}
+		}
+		p.fromBlock, p.toBlock = unwindTo+1, progress
+		if stage == stages.Headers {
+			bodies, err := stages.GetStageProgress(tx, stages.Bodies)
+			if err != nil {
+				return nil, err
+			}
+			if bodies > unwindTo {
+				p.alsoUnwinds = append(p.alsoUnwinds, stages.Bodies)
+			}
+		}
+		// `integration` unwinds only 1 stage: later stages left ahead of unwind point
+		seen := false
+		for _, s := range stages.AllStages {
+			if s == stage {
+				seen = true
+				continue
+			}
+			if !seen || s == stages.Finish {
+				continue
+			}
+			sp, err := stages.GetStageProgress(tx, s)
+			if err != nil {
+				return nil, err
+			}
+			if sp > unwindTo {
+				p.notes = append(p.notes, fmt.Sprintf("stage %s is at block %d - ahead of unwind point %d, unwind it first", s, sp, unwindTo))
+			}
+		}
+	case pruneTo > 0 && (stage == stages.Execution || stage == stages.TxLookup):
+		p.mode = "prune"
+		pruneProgress, err := stages.GetStagePruneProgress(tx, stage)
+		if err != nil {
+			return nil, err
+		}
+		p.fromBlock, p.toBlock = pruneProgress, pruneTo-1
+		p.notes = append(p.notes, "prune removes data from DB only: snapshot files are not touched")
+	default:
+		p.mode = "forward"
+		if stage == stages.Headers || stage == stages.Bodies {
+			p.notes = append(p.notes, "command only works with --unwind or --reset options")
+			break
+		}
+		to := block
+		if to == 0 {
+			if to, err = stages.GetStageProgress(tx, upstreamStages[stage]); err != nil {
+				return nil, err
+			}
+		}
+		p.fromBlock, p.toBlock = progress+1, to
+		if stage == stages.Execution && agg != nil {
+			ok, filesBlock, err := txNumsReader.FindBlockNum(tx, agg.EndTxNumMinimax())
+			if err != nil {
+				return nil, err
+			}
+			if ok && filesBlock > progress {
+				p.notes = append(p.notes, fmt.Sprintf("state files are ahead of stage progress: execution continues from block %d", filesBlock))
+			}
+		}
+	}
+
+	if p.fromBlock > p.toBlock {
+		return p, nil
+	}
+	if p.fromTxNum, err = txNumsReader.Min(tx, p.fromBlock); err != nil {
+		return nil, err
+	}
+	if p.toTxNum, err = txNumsReader.Max(tx, p.toBlock); err != nil {
+		return nil, err
+	}
+	if p.mode == "forward" || p.mode == "unwind" {
+		if p.timing, err = stages.GetStageTiming(tx, stage, p.mode == "unwind"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case p.mode == "prune":
+	case isStateStage && agg == nil:
+	case isStateStage:
+		stepSize := agg.StepSize()
+		for _, name := range agg.Files() {
+			f, _, ok := snaptype.ParseFileName("", name)
+			if !ok || f.To*stepSize <= p.fromTxNum || f.From*stepSize > p.toTxNum {
+				continue
+			}
+			p.files = append(p.files, name)
+		}
+		p.filesAction = map[string]string{"forward": "read", "unwind": "kept: state can't be unwound inside files", "reset": "re-opened"}[p.mode]
+	default:
+		for _, name := range blockFiles {
+			f, _, ok := snaptype.ParseFileName("", name)
+			if !ok || f.To <= p.fromBlock || f.From > p.toBlock {
+				continue
+			}
+			p.files = append(p.files, name)
+		}
+		p.filesAction = map[string]string{"forward": "read", "unwind": "kept: frozen blocks are not removed, only stage progress moves", "reset": "kept"}[p.mode]
+	}
+	return p, nil
+}
+
+func (p *stagePlan) blocks() uint64 {
+	if p.fromBlock > p.toBlock {
+		return 0
+	}
+	return p.toBlock - p.fromBlock + 1
+}
+
+func (p *stagePlan) print(logger log.Logger) {
+	const maxFiles = 64
+
+	logger.Info("[dry-run] plan", "stage", p.stage, "mode", p.mode, "progress", p.progress)
+	if p.blocks() == 0 {
+		logger.Info("[dry-run] nothing to do", "stage", p.stage)
+	} else {
+		logger.Info("[dry-run] range", "blocks", fmt.Sprintf("%d-%d", p.fromBlock, p.toBlock), "txNums", fmt.Sprintf("%d-%d", p.fromTxNum, p.toTxNum))
+	}
+	for _, s := range p.alsoUnwinds {
+		logger.Info("[dry-run] also unwinds", "stage", s)
+	}
+	for i, f := range p.files {
+		if i == maxFiles {
+			logger.Info("[dry-run] ...", "more_files", len(p.files)-maxFiles)
+			break
+		}
+		logger.Info("[dry-run] file", "name", f, "action", p.filesAction)
+	}
+	for _, n := range p.notes {
+		logger.Warn("[dry-run] " + n)
+	}
+	if p.blocks() == 0 || (p.mode != "forward" && p.mode != "unwind") {
+		return
+	}
+	if p.timing.Blocks == 0 {
+		logger.Info("[dry-run] estimate", "took", "unknown: no saved timings of this stage yet")
+		return
+	}
+	logger.Info("[dry-run] estimate", "took", p.timing.Estimate(p.blocks()).Truncate(time.Second),
+		"based_on_blocks", p.timing.Blocks, "based_on_took", p.timing.Took.Truncate(time.Second))
+}
@@ -33,6 +33,7 @@ var (
 	unwindEvery                              uint64
 	batchSizeStr                             string
 	reset, warmup, noCommit                  bool
+	dryRun                                   bool
 	resetPruneAt                             bool
 	bucket                                   string
 	datadirCli, toChaindata                  string
@@ -57,6 +58,7 @@ var (
 	traceFromTx    uint64
 
 	workers, reconWorkers uint64
+	parallelExec          bool
 	dbWriteMap            bool
 )
 
@@ -121,6 +123,10 @@ func withReset(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&warmup, "warmup", false, "warmup relevant tables by parallel random reads")
 }
 
+func withDryRun(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print which stages would run, their block/txNum ranges, affected snapshot files and estimated duration - without changing anything")
+}
+
 func withResetPruneAt(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&resetPruneAt, "resetPruneAt", false, "reset prune_at to 0 for a given stage")
 }
@@ -175,12 +181,13 @@ func withChain(cmd *cobra.Command) {
 }
 
 func withHeimdall(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&HeimdallURL, "bor.heimdall", "http://localhost:1317", "URL of Heimdall service")
+	cmd.Flags().StringVar(&HeimdallURL, "bor.heimdall", "http://localhost:1317", "URL of Heimdall service, or comma separated URLs of several Heimdall services")
 }
 
 func withWorkers(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&workers, "exec.workers", uint64(ethconfig.Defaults.Sync.ExecWorkerCount), "")
 	cmd.Flags().Uint64Var(&reconWorkers, "recon.workers", uint64(ethconfig.Defaults.Sync.ReconWorkerCount), "")
+	cmd.Flags().BoolVar(&parallelExec, "sync.parallel-execution", false, "Enables optimistic parallel execution of transactions (uses --exec.workers)")
 }
 
 func withStartTx(cmd *cobra.Command) {
@@ -0,0 +1,111 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/cmd/hack/tool/fromdb"
+	"github.com/erigontech/erigon/eth/stagedsync"
+	"github.com/erigontech/erigon/turbo/debug"
+	"github.com/erigontech/erigon/turbo/replay/flightrecorder"
+)
+
+var recordOutput string
+
+func init() {
+	withDataDir(cmdRecordBlock)
+	withChain(cmdRecordBlock)
+	withHeimdall(cmdRecordBlock)
+	withBlock(cmdRecordBlock)
+	cmdRecordBlock.Flags().StringVar(&recordOutput, "output", "", "recording file (default: <datadir>/flight-recorder/<block>-<hash>.json.gz)")
+
+	rootCmd.AddCommand(cmdRecordBlock)
+}
+
+var cmdRecordBlock = &cobra.Command{
+	Use:     "record_block",
+	Short:   "Write flight recording of block (prestate, per-txn writes and opcode traces) for offline `evm replay`",
+	Example: "go run ./cmd/integration record_block --datadir=... --chain=mainnet --block=19000000",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata).Readonly(), false, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := recordBlock(db, cmd.Context(), logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+func recordBlock(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	dirs := datadir.New(datadirCli)
+	sn, borSn, agg, _ := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
+
+	chainConfig := fromdb.ChainConfig(db)
+	br, _ := blocksIO(db, logger)
+	engine, _ := initConsensusEngine(ctx, chainConfig, dirs.DataDir, db, br, logger)
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return fmt.Errorf("expected TemporalTx, got %T", tx)
+	}
+	b, err := br.BlockByNumber(ctx, tx, block)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return fmt.Errorf("block %d not found", block)
+	}
+	rec, err := flightrecorder.RecordBlock(ctx, ttx, chainConfig, engine, br, b, "integration record_block", logger)
+	if err != nil {
+		return err
+	}
+	fname := recordOutput
+	if fname == "" {
+		fname = filepath.Join(dirs.DataDir, stagedsync.FlightRecorderDir, fmt.Sprintf("%d-%x.json.gz", block, b.Hash().Bytes()[:8]))
+	}
+	if err := rec.WriteFile(fname); err != nil {
+		return err
+	}
+	logger.Info("[record] block recorded", "block", block, "cmd", "evm replay "+fname)
+	return nil
+}
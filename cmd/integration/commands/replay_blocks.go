@@ -0,0 +1,161 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/cmd/hack/tool/fromdb"
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/eth/stagedsync/stages"
+	"github.com/erigontech/erigon/turbo/debug"
+	"github.com/erigontech/erigon/turbo/replay"
+)
+
+var (
+	replayFromBlock, replayToBlock uint64
+	replayOutputDir                string
+)
+
+func init() {
+	withDataDir(cmdReplayBlocks)
+	withChain(cmdReplayBlocks)
+	withHeimdall(cmdReplayBlocks)
+	withWorkers(cmdReplayBlocks)
+	cmdReplayBlocks.Flags().Uint64Var(&replayFromBlock, "from", 1, "first block to replay")
+	cmdReplayBlocks.Flags().Uint64Var(&replayToBlock, "to", 0, "last block to replay (default: Execution stage progress)")
+	cmdReplayBlocks.Flags().StringVar(&replayOutputDir, "output", "", "where to write `evm t8n` reproducers of mismatched txs (default: <datadir>/replay)")
+
+	rootCmd.AddCommand(cmdReplayBlocks)
+}
+
+var cmdReplayBlocks = &cobra.Command{
+	Use:     "replay_blocks",
+	Short:   "Re-execute blocks on historical state (read-only) and compare writes and receipts with stored history",
+	Example: "go run ./cmd/integration replay_blocks --datadir=... --chain=mainnet --from=19000000 --to=19000100",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata).Readonly(), false, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		defer func(t time.Time) { logger.Info("total", "took", time.Since(t)) }(time.Now())
+
+		if err := replayBlocks(db, cmd.Context(), logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+func replayBlocks(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	dirs := datadir.New(datadirCli)
+	sn, borSn, agg, _ := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
+
+	chainConfig := fromdb.ChainConfig(db)
+	br, _ := blocksIO(db, logger)
+	engine, _ := initConsensusEngine(ctx, chainConfig, dirs.DataDir, db, br, logger)
+	cfg := &exec3.ExecArgs{
+		ChainDB:     db,
+		BlockReader: br,
+		ChainConfig: chainConfig,
+		Dirs:        dirs,
+		Engine:      engine,
+		Genesis:     core.GenesisBlockByChainName(chain),
+		Workers:     int(workers),
+	}
+	outputDir := replayOutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(dirs.DataDir, "replay")
+	}
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return fmt.Errorf("expected TemporalTx, got %T", tx)
+	}
+
+	toBlock := replayToBlock
+	if toBlock == 0 {
+		if toBlock, err = stages.GetStageProgress(tx, stages.Execution); err != nil {
+			return err
+		}
+	}
+	if replayFromBlock > toBlock {
+		return fmt.Errorf("nothing to replay: from=%d > to=%d", replayFromBlock, toBlock)
+	}
+	logger.Info("[replay] start", "from", replayFromBlock, "to", toBlock, "workers", workers)
+
+	// reproducers are built after replay: `onMismatch` may be called from other goroutine, while `tx` is in use
+	type txnRef struct {
+		blockNum uint64
+		txIndex  int
+	}
+	var mismatches int
+	var toReproduce []txnRef
+	seen := map[txnRef]struct{}{}
+	onMismatch := func(m *replay.Mismatch) error {
+		mismatches++
+		logger.Warn("[replay] " + m.String())
+		ref := txnRef{m.BlockNum, m.TxIndex}
+		if _, ok := seen[ref]; !ok && !m.SystemTx() {
+			seen[ref] = struct{}{}
+			toReproduce = append(toReproduce, ref)
+		}
+		return nil
+	}
+	if err := replay.Run(ctx, ttx, cfg, replayFromBlock, toBlock, onMismatch, logger); err != nil {
+		return err
+	}
+
+	for _, ref := range toReproduce {
+		r, err := replay.NewReproducer(ctx, ttx, cfg, ref.blockNum, ref.txIndex)
+		if err != nil {
+			return fmt.Errorf("reproducer of block %d txn %d: %w", ref.blockNum, ref.txIndex, err)
+		}
+		dir := filepath.Join(outputDir, fmt.Sprintf("%d_%d", ref.blockNum, ref.txIndex))
+		if err := r.Write(dir); err != nil {
+			return err
+		}
+		logger.Info("[replay] reproducer written", "block", ref.blockNum, "txIndex", ref.txIndex, "cmd", r.Command(dir))
+	}
+	logger.Info("[replay] done", "from", replayFromBlock, "to", toBlock, "mismatches", mismatches, "reproducers", len(toReproduce))
+	return nil
+}
@@ -32,7 +32,9 @@ import (
 	"github.com/erigontech/erigon-lib/kv"
 	kv2 "github.com/erigontech/erigon-lib/kv/mdbx"
 
+	"github.com/erigontech/erigon/cmd/hack/tool"
 	"github.com/erigontech/erigon/cmd/utils"
+	"github.com/erigontech/erigon/core/vm"
 	"github.com/erigontech/erigon/migrations"
 	"github.com/erigontech/erigon/turbo/debug"
 	"github.com/erigontech/erigon/turbo/logging"
@@ -109,6 +111,12 @@ func openDB(opts kv2.MdbxOpts, applyMigrations bool, logger log.Logger) (kv.RwDB
 	}
 
 	if opts.GetLabel() == kv.ChainDB {
+		if cc := tool.ChainConfigFromDB(db); cc != nil {
+			if err := vm.CheckCustomPrecompiles(cc); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
 		_, _, agg, _ := allSnapshots(context.Background(), db, logger)
 		tdb, err := temporal.New(db, agg)
 		if err != nil {
@@ -119,3 +127,12 @@ func openDB(opts kv2.MdbxOpts, applyMigrations bool, logger log.Logger) (kv.RwDB
 
 	return db, nil
 }
+
+// openStageDB - opens chaindata for `integration stage_*` commands. `--dry-run` opens it read-only: without migrations
+// and without temporal db, which opens state files by allSnapshots.
+func openStageDB(logger log.Logger) (kv.RwDB, error) {
+	if dryRun {
+		return dbCfg(kv.ChainDB, chaindata).Readonly().Open(context.Background())
+	}
+	return openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+}
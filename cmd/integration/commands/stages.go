@@ -107,7 +107,7 @@ var cmdStageHeaders = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -149,7 +149,7 @@ var cmdStageBodies = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -191,7 +191,7 @@ var cmdStageSenders = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -212,7 +212,7 @@ var cmdStageExec = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -235,7 +235,7 @@ var cmdStageCustomTrace = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -279,7 +279,7 @@ var cmdStageTxLookup = &cobra.Command{
 	Short: "",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := debug.SetupCobra(cmd, "integration")
-		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		db, err := openStageDB(logger)
 		if err != nil {
 			logger.Error("Opening DB", "error", err)
 			return
@@ -500,6 +500,7 @@ func init() {
 	withDataDir(cmdStageSenders)
 	withChain(cmdStageSenders)
 	withHeimdall(cmdStageSenders)
+	withDryRun(cmdStageSenders)
 	rootCmd.AddCommand(cmdStageSenders)
 
 	withConfig(cmdStageSnapshots)
@@ -515,6 +516,7 @@ func init() {
 	withReset(cmdStageHeaders)
 	withChain(cmdStageHeaders)
 	withHeimdall(cmdStageHeaders)
+	withDryRun(cmdStageHeaders)
 	rootCmd.AddCommand(cmdStageHeaders)
 
 	withConfig(cmdStageBorHeimdall)
@@ -531,6 +533,7 @@ func init() {
 	withUnwind(cmdStageBodies)
 	withChain(cmdStageBodies)
 	withHeimdall(cmdStageBodies)
+	withDryRun(cmdStageBodies)
 	rootCmd.AddCommand(cmdStageBodies)
 
 	withConfig(cmdStagePolygon)
@@ -554,6 +557,7 @@ func init() {
 	withChain(cmdStageExec)
 	withHeimdall(cmdStageExec)
 	withWorkers(cmdStageExec)
+	withDryRun(cmdStageExec)
 	rootCmd.AddCommand(cmdStageExec)
 
 	withConfig(cmdStageCustomTrace)
@@ -568,6 +572,7 @@ func init() {
 	withChain(cmdStageCustomTrace)
 	withHeimdall(cmdStageCustomTrace)
 	withWorkers(cmdStageCustomTrace)
+	withDryRun(cmdStageCustomTrace)
 	rootCmd.AddCommand(cmdStageCustomTrace)
 
 	withConfig(cmdStagePatriciaTrie)
@@ -589,6 +594,7 @@ func init() {
 	withPruneTo(cmdStageTxLookup)
 	withChain(cmdStageTxLookup)
 	withHeimdall(cmdStageTxLookup)
+	withDryRun(cmdStageTxLookup)
 	rootCmd.AddCommand(cmdStageTxLookup)
 
 	withConfig(cmdPrintMigrations)
@@ -678,6 +684,9 @@ func stageSnapshots(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 }
 
 func stageHeaders(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.Headers, logger)
+	}
 	dirs := datadir.New(datadirCli)
 	if err := datadir.ApplyMigrations(dirs); err != nil {
 		return err
@@ -854,6 +863,9 @@ func stageBorHeimdall(db kv.RwDB, ctx context.Context, unwindTypes []string, log
 }
 
 func stageBodies(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.Bodies, logger)
+	}
 	sn, borSn, agg, _ := allSnapshots(ctx, db, logger)
 	defer sn.Close()
 	defer borSn.Close()
@@ -921,6 +933,9 @@ func stagePolygonSync(db kv.RwDB, ctx context.Context, logger log.Logger) error
 }
 
 func stageSenders(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.Senders, logger)
+	}
 	tmpdir := datadir.New(datadirCli).Tmp
 	chainConfig := fromdb.ChainConfig(db)
 	sn, borSn, agg, _ := allSnapshots(ctx, db, logger)
@@ -1014,6 +1029,9 @@ func stageSenders(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 }
 
 func stageExec(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.Execution, logger)
+	}
 	dirs := datadir.New(datadirCli)
 	if err := datadir.ApplyMigrations(dirs); err != nil {
 		return err
@@ -1054,6 +1072,7 @@ func stageExec(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 
 	syncCfg := ethconfig.Defaults.Sync
 	syncCfg.ExecWorkerCount = int(workers)
+	syncCfg.ParallelExecution = parallelExec
 	syncCfg.ReconWorkerCount = int(reconWorkers)
 
 	genesis := core.GenesisBlockByChainName(chain)
@@ -1117,6 +1136,9 @@ func stageExec(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 }
 
 func stageCustomTrace(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.CustomTrace, logger)
+	}
 	dirs := datadir.New(datadirCli)
 	if err := datadir.ApplyMigrations(dirs); err != nil {
 		return err
@@ -1151,6 +1173,7 @@ func stageCustomTrace(db kv.RwDB, ctx context.Context, logger log.Logger) error
 	chainConfig, pm := fromdb.ChainConfig(db), fromdb.PruneMode(db)
 	syncCfg := ethconfig.Defaults.Sync
 	syncCfg.ExecWorkerCount = int(workers)
+	syncCfg.ParallelExecution = parallelExec
 	syncCfg.ReconWorkerCount = int(reconWorkers)
 
 	genesis := core.GenesisBlockByChainName(chain)
@@ -1190,6 +1213,9 @@ func stagePatriciaTrie(db kv.RwDB, ctx context.Context, logger log.Logger) error
 }
 
 func stageTxLookup(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	if dryRun {
+		return printStagePlan(db, ctx, stages.TxLookup, logger)
+	}
 	dirs, pm := datadir.New(datadirCli), fromdb.PruneMode(db)
 	_, _, sync, _, _ := newSync(ctx, db, nil /* miningConfig */, logger)
 	chainConfig := fromdb.ChainConfig(db)
@@ -1535,7 +1561,10 @@ func initConsensusEngine(ctx context.Context, cc *chain2.Config, dir string, db
 		consensusConfig = cc.Bor
 		config.HeimdallURL = HeimdallURL
 		if !config.WithoutHeimdall {
-			heimdallClient = heimdall.NewHeimdallClient(config.HeimdallURL, logger)
+			heimdallClient = heimdall.NewHeimdallClientForURLs(heimdall.MultiClientConfig{
+				URLs:   libcommon.CliString2Array(config.HeimdallURL),
+				Logger: logger,
+			})
 		}
 	} else {
 		consensusConfig = &config.Ethash
@@ -335,6 +335,10 @@ func (back *RemoteBackend) LastFrozenEventBlockNum() uint64 {
 	panic("not implemented")
 }
 
+func (back *RemoteBackend) FrozenEventsByIdRange(fromId, toId uint64) ([]rlp.RawValue, []uint64, error) {
+	return back.blockReader.FrozenEventsByIdRange(fromId, toId)
+}
+
 func (back *RemoteBackend) Span(ctx context.Context, tx kv.Getter, spanId uint64) ([]byte, error) {
 	return back.blockReader.Span(ctx, tx, spanId)
 }
@@ -0,0 +1,392 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/holiman/uint256"
+	"github.com/spf13/cobra"
+
+	"github.com/erigontech/erigon-lib/commitment"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/recsplit"
+	"github.com/erigontech/erigon-lib/recsplit/eliasfano32"
+	"github.com/erigontech/erigon-lib/seg"
+	"github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/crypto"
+
+	"github.com/erigontech/erigon/core/types/accounts"
+)
+
+func init() {
+	withFpath(inspectStateFile)
+	withPick(inspectStateFile)
+	inspectStateFile.Flags().StringVar(&inspectCompress, "compress", "auto", "compression of keys or values (k|v|kv|none). 'auto' - detect by file content")
+	inspectStateFile.Flags().StringVar(&inspectIndex, "index", "", "path to .bt/.kvi/.efi/.vi index of file. By default looked up next to file and in ../accessor")
+	inspectStateFile.Flags().StringVar(&inspectEf, "ef", "", "path to .ef file paired with .v file: needed to print keys and txNums of history values")
+	inspectStateFile.Flags().IntVar(&inspectLimit, "limit", 0, "print only first N pairs. 0 - print all")
+	inspectStateFile.Flags().BoolVar(&inspectStatsOnly, "stats-only", false, "don't print pairs, only statistics")
+	rootCmd.AddCommand(inspectStateFile)
+}
+
+var (
+	inspectCompress  string
+	inspectIndex     string
+	inspectEf        string
+	inspectLimit     int
+	inspectStatsOnly bool
+)
+
+var inspectStateFile = &cobra.Command{
+	Use:     "inspect_state_file",
+	Short:   "decode and print pairs of .kv/.v/.ef state file, with key/value sizes histograms, dictionaries and index stats",
+	Example: "go run ./cmd/state inspect_state_file --path=<datadir>/snapshots/domain/v1-accounts.0-32.kv --limit=10",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fpath == "" {
+			return errors.New("path is required")
+		}
+		return inspectFile(fpath)
+	},
+}
+
+// v1-accounts.0-32.kv -> accounts
+var stateFileNameRegex = regexp.MustCompile(`^v[0-9]+-([[:lower:]]+)\.[0-9]+-[0-9]+\.([a-z]+)$`)
+
+func inspectFile(path string) error {
+	_, fName := filepath.Split(path)
+	subs := stateFileNameRegex.FindStringSubmatch(fName)
+	if len(subs) != 3 {
+		return fmt.Errorf("unexpected state file name: %s", fName)
+	}
+	base, ext := subs[1], subs[2]
+	if ext != "kv" && ext != "v" && ext != "ef" {
+		return fmt.Errorf("unsupported file extension: .%s (expected .kv, .v or .ef)", ext)
+	}
+
+	d, err := seg.NewDecompressor(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	compression, err := parseInspectCompression(d)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("File %s size %s words %d compression %s\n", fName, datasize.ByteSize(d.Size()).HR(), d.Count(), compression)
+
+	var pbytes []byte
+	if pick != "" {
+		if pbytes, err = hex.DecodeString(pick); err != nil {
+			return fmt.Errorf("pick: %w", err)
+		}
+	}
+
+	var ks, vs sizeHistogram
+	var rawSize uint64
+	printed := 0
+	printPair := func(k, v []byte, txNum uint64) {
+		if inspectStatsOnly || (inspectLimit > 0 && printed >= inspectLimit) || !bytes.HasPrefix(k, pbytes) {
+			return
+		}
+		printed++
+		if ext == "v" {
+			fmt.Printf("%s txNum=%d -> %s\n", decodeStateKey(base, k), txNum, decodeStateValue(base, ext, k, v))
+			return
+		}
+		fmt.Printf("%s -> %s\n", decodeStateKey(base, k), decodeStateValue(base, ext, k, v))
+	}
+
+	r := seg.NewReader(d.MakeGetter(), compression)
+	switch ext {
+	case "kv", "ef":
+		var k, v []byte
+		for r.HasNext() {
+			k, _ = r.Next(k[:0])
+			if !r.HasNext() {
+				return fmt.Errorf("key %x has no value", k)
+			}
+			v, _ = r.Next(v[:0])
+			ks.add(len(k))
+			vs.add(len(v))
+			rawSize += uint64(len(k) + len(v))
+			printPair(k, v, 0)
+		}
+	case "v":
+		// history values are stored without keys: in order of keys and txNums of paired .ef file
+		var efr *seg.Reader
+		if efPath := inspectEfPath(path); efPath != "" {
+			efd, err := seg.NewDecompressor(efPath)
+			if err != nil {
+				return err
+			}
+			defer efd.Close()
+			efr = seg.NewReader(efd.MakeGetter(), seg.DetectCompressType(efd.MakeGetter()))
+			fmt.Printf("Paired with %s\n", efPath)
+		}
+		var k, efv, v []byte
+		var it *eliasfano32.EliasFanoIter
+		for r.HasNext() {
+			var txNum uint64
+			if efr != nil {
+				for it == nil || !it.HasNext() {
+					if !efr.HasNext() {
+						return errors.New(".ef file has less txNums than values in .v file")
+					}
+					k, _ = efr.Next(k[:0])
+					efv, _ = efr.Next(efv[:0])
+					ef, _ := eliasfano32.ReadEliasFano(efv)
+					it = ef.Iterator()
+				}
+				if txNum, err = it.Next(); err != nil {
+					return err
+				}
+			}
+			v, _ = r.Next(v[:0])
+			vs.add(len(v))
+			rawSize += uint64(len(v))
+			printPair(k, v, txNum)
+		}
+	}
+	if printed > 0 && inspectLimit > 0 && printed == inspectLimit {
+		fmt.Printf("... printed first %d pairs\n", printed)
+	}
+
+	if ext != "v" {
+		fmt.Printf("\nKey sizes (%d keys, %s):\n%s", ks.count, datasize.ByteSize(ks.total).HR(), ks)
+	}
+	fmt.Printf("\nValue sizes (%d values, %s):\n%s", vs.count, datasize.ByteSize(vs.total).HR(), vs)
+
+	st := d.DictStats()
+	fmt.Printf("\nDictionaries:\n")
+	fmt.Printf("  patterns:  %d entries, %s, max code len %d bits\n", st.Patterns, datasize.ByteSize(st.PatternsSize).HR(), st.PatternsMaxDepth)
+	fmt.Printf("  positions: %d entries, %s, max code len %d bits\n", st.Positions, datasize.ByteSize(st.PositionsSize).HR(), st.PositionsMaxDepth)
+	fmt.Printf("  words: raw %s -> encoded %s, ratio %.2f (with dictionaries %.2f)\n",
+		datasize.ByteSize(rawSize).HR(), datasize.ByteSize(st.WordsSize).HR(), ratio(rawSize, st.WordsSize), ratio(rawSize, uint64(d.Size())))
+
+	return inspectIndexFile(path, ext, d, compression)
+}
+
+func parseInspectCompression(d *seg.Decompressor) (seg.FileCompression, error) {
+	switch strings.ToLower(inspectCompress) {
+	case "auto":
+		return seg.DetectCompressType(d.MakeGetter()), nil
+	case "k":
+		return seg.CompressKeys, nil
+	case "v":
+		return seg.CompressVals, nil
+	case "kv":
+		return seg.CompressKeys | seg.CompressVals, nil
+	case "none", "":
+		return seg.CompressNone, nil
+	default:
+		return seg.CompressNone, fmt.Errorf("unknown compression flags %s", inspectCompress)
+	}
+}
+
+// inspectEfPath - .ef file of same range for .v file: --ef flag or `../idx/<name>.ef`
+func inspectEfPath(path string) string {
+	if inspectEf != "" {
+		return inspectEf
+	}
+	dir, fName := filepath.Split(path)
+	candidate := filepath.Join(dir, "..", "idx", strings.TrimSuffix(fName, ".v")+".ef")
+	if _, err := os.Stat(candidate); err != nil {
+		return ""
+	}
+	return candidate
+}
+
+func inspectIndexFile(path, ext string, d *seg.Decompressor, compression seg.FileCompression) error {
+	idxPath := inspectIndex
+	if idxPath == "" {
+		dir, fName := filepath.Split(path)
+		name := strings.TrimSuffix(fName, "."+ext)
+		var candidates []string
+		switch ext {
+		case "kv":
+			candidates = []string{filepath.Join(dir, name+".bt"), filepath.Join(dir, name+".kvi")}
+		case "v":
+			candidates = []string{filepath.Join(dir, "..", "accessor", name+".vi")}
+		case "ef":
+			candidates = []string{filepath.Join(dir, "..", "accessor", name+".efi")}
+		}
+		for _, c := range candidates {
+			if _, err := os.Stat(c); err == nil {
+				idxPath = c
+				break
+			}
+		}
+	}
+	if idxPath == "" {
+		fmt.Printf("\nIndex: not found\n")
+		return nil
+	}
+
+	fmt.Printf("\nIndex %s:\n", filepath.Base(idxPath))
+	if filepath.Ext(idxPath) == ".bt" {
+		bt, err := state.OpenBtreeIndexWithDecompressor(idxPath, state.DefaultBtreeM, d, compression)
+		if err != nil {
+			return err
+		}
+		defer bt.Close()
+		cached, dataReads := bt.Depth()
+		fmt.Printf("  btree: %d keys, %s, M=%d, cached nodes %d, lookup depth %d in-memory + %d data file reads\n",
+			bt.KeyCount(), datasize.ByteSize(bt.Size()).HR(), state.DefaultBtreeM, cached, bits.Len(uint(cached)), dataReads)
+		return nil
+	}
+
+	idx, err := recsplit.OpenIndex(idxPath)
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+	fmt.Printf("  recsplit: %d keys, %s, buckets %d, bucket size %d, leaf size %d\n",
+		idx.KeyCount(), datasize.ByteSize(idx.Size()).HR(), idx.BucketCount(), idx.BucketSize(), idx.LeafSize())
+	depths := idx.Depths()
+	levels := make([]int, 0, len(depths))
+	for l := range depths {
+		levels = append(levels, l)
+	}
+	sort.Ints(levels)
+	for _, l := range levels {
+		fmt.Printf("  depth %d: %d buckets\n", l, depths[l])
+	}
+	return nil
+}
+
+func decodeStateKey(base string, k []byte) string {
+	switch base {
+	case "accounts", "code":
+		if len(k) == length.Addr {
+			return fmt.Sprintf("addr=%x", k)
+		}
+	case "storage":
+		if len(k) == length.Addr+length.Hash {
+			return fmt.Sprintf("addr=%x loc=%x", k[:length.Addr], k[length.Addr:])
+		}
+	case "commitment":
+		if bytes.Equal(k, []byte("state")) {
+			return "state"
+		}
+		return fmt.Sprintf("prefix=%x", k)
+	}
+	return fmt.Sprintf("%x", k)
+}
+
+func decodeStateValue(base, ext string, k, v []byte) (s string) {
+	if ext == "ef" {
+		ef, _ := eliasfano32.ReadEliasFano(v)
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "txNums count=%d min=%d max=%d [", ef.Count(), ef.Min(), ef.Max())
+		it := ef.Iterator()
+		for i := 0; it.HasNext(); i++ {
+			if i == 10 {
+				sb.WriteString(" ...")
+				break
+			}
+			n, err := it.Next()
+			if err != nil {
+				return err.Error()
+			}
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprintf(&sb, "%d", n)
+		}
+		sb.WriteString("]")
+		return sb.String()
+	}
+	if len(v) == 0 {
+		return "<empty>"
+	}
+	switch base {
+	case "accounts":
+		var a accounts.Account
+		if err := accounts.DeserialiseV3(&a, v); err != nil {
+			return fmt.Sprintf("%x (%s)", v, err)
+		}
+		return fmt.Sprintf("nonce=%d balance=%d codeHash=%x incarnation=%d", a.Nonce, &a.Balance, a.CodeHash, a.Incarnation)
+	case "storage":
+		return new(uint256.Int).SetBytes(v).Hex()
+	case "code":
+		return fmt.Sprintf("codeHash=%x len=%d", crypto.Keccak256(v), len(v))
+	case "commitment":
+		if bytes.Equal(k, []byte("state")) {
+			return fmt.Sprintf("%x", v)
+		}
+		defer func() {
+			if rec := recover(); rec != nil {
+				s = fmt.Sprintf("%x (can't decode branch: %v)", v, rec)
+			}
+		}()
+		return strings.TrimSpace(commitment.BranchData(v).String())
+	}
+	return fmt.Sprintf("%x", v)
+}
+
+// sizeHistogram - amount of words by power-of-2 size buckets
+type sizeHistogram struct {
+	buckets [65]uint64
+	count   uint64
+	total   uint64
+	max     int
+}
+
+func (h *sizeHistogram) add(size int) {
+	h.buckets[bits.Len(uint(size))]++
+	h.count++
+	h.total += uint64(size)
+	h.max = max(h.max, size)
+}
+
+func (h sizeHistogram) String() string {
+	if h.count == 0 {
+		return "  <empty>\n"
+	}
+	var sb strings.Builder
+	for i, n := range h.buckets {
+		if n == 0 {
+			continue
+		}
+		from, to := 0, 0
+		if i > 0 {
+			from, to = 1<<(i-1), 1<<i-1
+		}
+		fmt.Fprintf(&sb, "  [%6d-%6d] %12d %5.1f%%\n", from, to, n, 100*float64(n)/float64(h.count))
+	}
+	fmt.Fprintf(&sb, "  avg %.1f, max %d\n", float64(h.total)/float64(h.count), h.max)
+	return sb.String()
+}
+
+func ratio(raw, encoded uint64) float64 {
+	if encoded == 0 {
+		return 0
+	}
+	return float64(raw) / float64(encoded)
+}
@@ -58,6 +58,7 @@ type HistoricalTraceWorker struct {
 	background  bool
 	ctx         context.Context
 	stateWriter state.StateWriter
+	diffWriter  *state.StateWriterBufferedV3 // only if consumer.StateDiff
 	chain       consensus.ChainReader
 	logger      log.Logger
 
@@ -76,9 +77,13 @@ type HistoricalTraceWorker struct {
 }
 
 type TraceConsumer struct {
+	// NewTracer called for each txn. Tracer available in Reduce as `task.Tracer`. Can return nil.
 	NewTracer func() GenericTracer
 	//Reduce receiving results of execution. They are sorted and have no gaps.
+	//Failed txns are passed with `task.Error` set - it's up to consumer to skip them or to fail.
 	Reduce func(task *state.TxTask, tx kv.Tx) error
+	// StateDiff - fill `task.WriteLists` and `task.BalanceIncreaseSet` by state changes of each txn
+	StateDiff bool
 }
 
 func NewHistoricalTraceWorker(
@@ -109,6 +114,9 @@ func NewHistoricalTraceWorker(
 		taskGasPool: new(core.GasPool),
 	}
 	ie.ibs = state.New(ie.stateReader)
+	if consumer.StateDiff {
+		ie.diffWriter = state.NewStateWriterBufferedV3(nil, nil)
+	}
 
 	return ie
 }
@@ -137,6 +145,9 @@ func (rw *HistoricalTraceWorker) RunTxTask(txTask *state.TxTask) {
 	rw.stateReader.SetTxNum(txTask.TxNum)
 	rw.stateReader.ResetReadSet()
 	rw.stateWriter = state.NewNoopWriter()
+	if rw.diffWriter != nil {
+		rw.diffWriter.ResetWriteSet()
+	}
 
 	rw.ibs.Reset()
 	ibs := rw.ibs
@@ -180,9 +191,11 @@ func (rw *HistoricalTraceWorker) RunTxTask(txTask *state.TxTask) {
 		}
 	default:
 		rw.taskGasPool.Reset(txTask.Tx.GetGas(), txTask.Tx.GetBlobGas())
+		rw.vmConfig.Debug, rw.vmConfig.Tracer = false, nil
 		if tracer := rw.consumer.NewTracer(); tracer != nil {
 			rw.vmConfig.Debug = true
 			rw.vmConfig.Tracer = tracer
+			txTask.Tracer = tracer
 		}
 		rw.vmConfig.SkipAnalysis = txTask.SkipAnalysis
 		ibs.SetTxContext(txTask.TxIndex)
@@ -214,6 +227,14 @@ func (rw *HistoricalTraceWorker) RunTxTask(txTask *state.TxTask) {
 			txTask.Logs = ibs.GetRawLogs(txTask.TxIndex)
 		}
 	}
+	if rw.diffWriter != nil && txTask.Error == nil {
+		if err := ibs.MakeWriteSet(rules, rw.diffWriter); err != nil {
+			txTask.Error = err
+			return
+		}
+		txTask.WriteLists = rw.diffWriter.WriteSet()
+		txTask.BalanceIncreaseSet = ibs.BalanceIncreaseSet()
+	}
 }
 func (rw *HistoricalTraceWorker) ResetTx(chainTx kv.Tx) {
 	if rw.background && rw.chainTx != nil {
@@ -248,10 +269,13 @@ func NewHistoricalTraceWorkers(consumer TraceConsumer, cfg *ExecArgs, ctx contex
 	heapLimit := workerCount * 128
 	rws := state.NewResultsQueue(resultChannelLimit, heapLimit) // workerCount * 4
 
-	reducerGroup := &errgroup.Group{}
+	// we all errors in background workers (except ctx.Cancel), because applyLoop will detect this error anyway.
+	// and in applyLoop all errors are critical
+	ctx, cancel := context.WithCancel(ctx)
+	g, ctx = errgroup.WithContext(ctx)
 
-	//Reducer
-	reducerGroup.Go(func() (err error) {
+	//Reducer: in same group with workers - to wait until all results are reduced and to not lose its errors
+	g.Go(func() (err error) {
 		defer func() {
 			if rec := recover(); rec != nil {
 				err = fmt.Errorf("%s, %s", rec, dbg.Stack())
@@ -293,10 +317,6 @@ func NewHistoricalTraceWorkers(consumer TraceConsumer, cfg *ExecArgs, ctx contex
 		return nil
 	})
 
-	// we all errors in background workers (except ctx.Cancel), because applyLoop will detect this error anyway.
-	// and in applyLoop all errors are critical
-	ctx, cancel := context.WithCancel(ctx)
-	g, ctx = errgroup.WithContext(ctx)
 	for i := 0; i < workerCount; i++ {
 		workers[i] = NewHistoricalTraceWorker(consumer, in, rws, true, ctx, cfg, logger)
 	}
@@ -324,7 +344,6 @@ func NewHistoricalTraceWorkers(consumer TraceConsumer, cfg *ExecArgs, ctx contex
 		cancel()
 		g.Wait()
 		rws.Close()
-		reducerGroup.Wait()
 		for _, w := range workers {
 			w.ResetTx(nil)
 		}
@@ -341,10 +360,6 @@ func processResultQueueHistorical(consumer TraceConsumer, rws *state.ResultsQueu
 	outputTxNum = outputTxNumIn
 	for rwsIt.HasNext(outputTxNum) {
 		txTask := rwsIt.PopNext()
-		if txTask.Error != nil {
-			return outputTxNum, false, err
-		}
-
 		if txTask.TxIndex >= 0 && !txTask.Final {
 			txTask.CreateReceipt(tx)
 		}
@@ -495,6 +510,7 @@ func CustomTraceMapReduce(fromBlock, toBlock uint64, consumer TraceConsumer, ctx
 				if err := consumer.Reduce(txTask, tx); err != nil {
 					return err
 				}
+				outTxNum.Store(txTask.TxNum + 1) // reducer of background workers exits after toTxNum
 			} else {
 				in.Add(ctx, txTask)
 			}
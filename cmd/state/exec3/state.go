@@ -30,17 +30,28 @@ import (
 	"github.com/erigontech/erigon-lib/chain"
 	libcommon "github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/kv"
+	libstate "github.com/erigontech/erigon-lib/state"
 
 	"github.com/erigontech/erigon/consensus"
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/types/accounts"
 	"github.com/erigontech/erigon/core/vm"
 	"github.com/erigontech/erigon/core/vm/evmtypes"
 	"github.com/erigontech/erigon/turbo/services"
 	"github.com/erigontech/erigon/turbo/shards"
 )
 
+// StateWriterWithSets - background workers accumulate updates in StateWriterBufferedV3 (applied later by applyLoop),
+// while apply-worker writes directly to SharedDomains by StateWriterV3
+type StateWriterWithSets interface {
+	state.StateWriter
+	ResetWriteSet()
+	WriteSet() map[string]*libstate.KvList
+	PrevAndDels() (map[string][]byte, map[string]*accounts.Account, map[string][]byte, map[string]uint64)
+}
+
 type Worker struct {
 	lock        sync.Locker
 	logger      log.Logger
@@ -50,7 +61,7 @@ type Worker struct {
 	blockReader services.FullBlockReader
 	in          *state.QueueWithRetry
 	rs          *state.StateV3
-	stateWriter *state.StateWriterV3
+	stateWriter StateWriterWithSets
 	stateReader state.ResettableStateReader
 	historyMode bool // if true - stateReader is HistoryReaderV3, otherwise it's state reader
 	chainConfig *chain.Config
@@ -111,7 +122,11 @@ func (rw *Worker) ResetState(rs *state.StateV3, accumulator *shards.Accumulator)
 	} else {
 		rw.SetReader(state.NewReaderV3(rs.Domains()))
 	}
-	rw.stateWriter = state.NewStateWriterV3(rs, accumulator)
+	if rw.background {
+		rw.stateWriter = state.NewStateWriterBufferedV3(rs, accumulator)
+	} else {
+		rw.stateWriter = state.NewStateWriterV3(rs, accumulator)
+	}
 }
 
 func (rw *Worker) Tx() kv.Tx        { return rw.chainTx }
@@ -130,7 +145,9 @@ func (rw *Worker) ResetTx(chainTx kv.Tx) {
 
 func (rw *Worker) Run() error {
 	for txTask, ok := rw.in.Next(rw.ctx); ok; txTask, ok = rw.in.Next(rw.ctx) {
-		rw.RunTxTask(txTask, rw.isMining)
+		if !txTask.Final { // block finalization needs receipts of all block's txs - applyLoop does execute it
+			rw.RunTxTask(txTask, rw.isMining)
+		}
 		if err := rw.resultCh.Add(rw.ctx, txTask); err != nil {
 			return err
 		}
@@ -185,9 +202,12 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask, isMining bool) {
 		rw.chain = consensuschain.NewReader(rw.chainConfig, rw.chainTx, rw.blockReader, rw.logger)
 	}
 	txTask.Error = nil
+	txTask.FlushEpoch = rw.rs.FlushEpoch()
 
 	rw.stateReader.SetTxNum(txTask.TxNum)
-	rw.rs.Domains().SetTxNum(txTask.TxNum)
+	if !rw.background { // background workers don't write to SharedDomains - txNum is set by applyLoop
+		rw.rs.Domains().SetTxNum(txTask.TxNum)
+	}
 	rw.stateReader.ResetReadSet()
 	rw.stateWriter.ResetWriteSet()
 
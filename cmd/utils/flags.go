@@ -783,10 +783,16 @@ var (
 
 	HeimdallURLFlag = cli.StringFlag{
 		Name:  "bor.heimdall",
-		Usage: "URL of Heimdall service",
+		Usage: "URL of Heimdall service, or comma separated URLs of several Heimdall services to fail over between and cross-check",
 		Value: "http://localhost:1317",
 	}
 
+	HeimdallQuorumFlag = cli.IntFlag{
+		Name:  "bor.heimdall.quorum",
+		Usage: "Number of Heimdall services which need to agree on spans, checkpoints and milestones when several are configured (0 = majority)",
+		Value: 0,
+	}
+
 	// WithoutHeimdallFlag no heimdall (for testing purpose)
 	WithoutHeimdallFlag = cli.BoolFlag{
 		Name:  "bor.withoutheimdall",
@@ -825,6 +831,16 @@ var (
 		Usage: "Enabling syncing with a stage that uses the polygon sync component",
 	}
 
+	PolygonSyncFromSnapshotsFlag = cli.BoolFlag{
+		Name:  "polygon.sync.from-snapshots",
+		Usage: "Sync up to the bor snapshots tip using the spans, checkpoints and state sync events they contain, without calling Heimdall (Heimdall is used past the tip unless bor.withoutheimdall is set)",
+	}
+
+	PolygonSyncVerifySnapshotsFlag = cli.BoolFlag{
+		Name:  "polygon.sync.verify-snapshots",
+		Usage: "Verify the spans, checkpoints and state sync events embedded in bor snapshots against checkpoint roots before syncing",
+	}
+
 	ConfigFlag = cli.StringFlag{
 		Name:  "config",
 		Usage: "Sets erigon flags from YAML/TOML file",
@@ -851,6 +867,26 @@ var (
 		Usage: "checkpoint sync endpoint",
 		Value: cli.NewStringSlice(),
 	}
+	CaplinCheckpointSyncStateFileFlag = cli.StringFlag{
+		Name:  "caplin.checkpoint-sync-state-file",
+		Usage: "Start from this SSZ beacon state file instead of fetching it from a checkpoint sync endpoint",
+		Value: "",
+	}
+	CaplinCheckpointSyncBlockFileFlag = cli.StringFlag{
+		Name:  "caplin.checkpoint-sync-block-file",
+		Usage: "SSZ signed beacon block of the checkpoint state file, the state is rejected if the block does not match it",
+		Value: "",
+	}
+	CaplinCheckpointSyncFallbackFlag = cli.BoolFlag{
+		Name:  "caplin.checkpoint-sync-fallback",
+		Usage: "If no checkpoint sync endpoint is reachable, start from the latest local state (or genesis) and sync the whole chain from peers instead of failing",
+		Value: false,
+	}
+	CaplinWeakSubjectivityCheckpointFlag = cli.StringFlag{
+		Name:  "caplin.weak-subjectivity-checkpoint",
+		Usage: "Weak subjectivity checkpoint as block_root:epoch (e.g. 0x1234...:250000), the checkpoint state and the synced chain must agree with it",
+		Value: "",
+	}
 	CaplinSubscribeAllTopicsFlag = cli.BoolFlag{
 		Name:  "caplin.subscibe-all-topics",
 		Usage: "Subscribe to all gossip topics",
@@ -858,19 +894,79 @@ var (
 	}
 	CaplinMevRelayUrl = cli.StringFlag{
 		Name:  "caplin.mev-relay-url",
-		Usage: "MEV relay endpoint. Caplin runs in builder mode if this is set",
+		Usage: "MEV relay endpoint, or a comma separated list of endpoints queried in parallel. Caplin runs in builder mode if this is set",
 		Value: "",
 	}
+	CaplinMevRelayTimeoutFlag = cli.DurationFlag{
+		Name:  "caplin.mev-relay-timeout",
+		Usage: "Timeout of each call to a MEV relay, slower relays are ignored during block production",
+		Value: time.Second,
+	}
+	CaplinMevBuilderBoostFactorFlag = cli.Uint64Flag{
+		Name:  "caplin.mev-builder-boost-factor",
+		Usage: "Percentage multiplier applied to builder bids before comparing them to the local payload, used when the validator client does not set builder_boost_factor",
+		Value: 100,
+	}
 	CaplinValidatorMonitorFlag = cli.BoolFlag{
 		Name:  "caplin.validator-monitor",
 		Usage: "Enable caplin validator monitoring metrics",
 		Value: false,
 	}
+	CaplinValidatorMonitorValidatorsFlag = cli.StringFlag{
+		Name:  "caplin.validator-monitor.validators",
+		Usage: "Comma separated list of validator indices to track with the validator monitor, enables the validator monitor",
+		Value: "",
+	}
+	CaplinValidatorClientFlag = cli.BoolFlag{
+		Name:  "caplin.validator-client",
+		Usage: "Enable the caplin built-in validator client",
+		Value: false,
+	}
+	CaplinValidatorKeystoresDirFlag = cli.StringFlag{
+		Name:  "caplin.validator-client.keystores-dir",
+		Usage: "Directory of EIP-2335 keystores loaded by the caplin validator client as read-only keys",
+		Value: "",
+	}
+	CaplinValidatorPasswordFileFlag = cli.StringFlag{
+		Name:  "caplin.validator-client.password-file",
+		Usage: "File containing the password of the keystores in --caplin.validator-client.keystores-dir",
+		Value: "",
+	}
+	CaplinValidatorFeeRecipientFlag = cli.StringFlag{
+		Name:  "caplin.validator-client.fee-recipient",
+		Usage: "Fee recipient of the blocks proposed by the caplin validator client",
+		Value: "",
+	}
+	CaplinValidatorGraffitiFlag = cli.StringFlag{
+		Name:  "caplin.validator-client.graffiti",
+		Usage: "Graffiti of the blocks proposed by the caplin validator client",
+		Value: "",
+	}
+	CaplinKeymanagerAPIAddrFlag = cli.StringFlag{
+		Name:  "caplin.keymanager-api.addr",
+		Usage: "Address of the Keymanager API of the caplin validator client, disabled if empty",
+		Value: "",
+	}
+	CaplinKeymanagerAPITokenFileFlag = cli.StringFlag{
+		Name:  "caplin.keymanager-api.token-file",
+		Usage: "File containing the bearer token of the Keymanager API, a token is generated if the file does not exist (default: <datadir>/caplin/validator/api-token.txt)",
+		Value: "",
+	}
 	CaplinMaxPeerCount = cli.Uint64Flag{
 		Name:  "caplin.max-peer-count",
 		Usage: "Max number of peers to connect",
 		Value: 128,
 	}
+	CaplinCustodyGroupCountFlag = cli.Uint64Flag{
+		Name:  "caplin.custody-group-count",
+		Usage: "Number of PeerDAS custody groups to sample and serve, 0 means the spec minimum (use the total number of groups for a supernode)",
+		Value: 0,
+	}
+	CaplinGossipTraceFileFlag = cli.StringFlag{
+		Name:  "caplin.gossip-trace-file",
+		Usage: "Append every gossip event of the sentinel to this file as JSON lines (disabled if empty)",
+		Value: "",
+	}
 
 	SentinelAddrFlag = cli.StringFlag{
 		Name:  "sentinel.addr",
@@ -964,7 +1060,7 @@ var (
 
 	BeaconAPIFlag = cli.StringSliceFlag{
 		Name:  "beacon.api",
-		Usage: "Enable beacon API (available endpoints: beacon, builder, config, debug, events, node, validator, lighthouse)",
+		Usage: "Enable beacon API (available endpoints: beacon, builder, config, debug, events, node, validator, lighthouse, caplin)",
 	}
 	BeaconApiProtocolFlag = cli.StringFlag{
 		Name:  "beacon.api.protocol",
@@ -1658,12 +1754,15 @@ func setClique(ctx *cli.Context, cfg *params.ConsensusSnapshotConfig, datadir st
 
 func setBorConfig(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.HeimdallURL = ctx.String(HeimdallURLFlag.Name)
+	cfg.HeimdallQuorum = ctx.Int(HeimdallQuorumFlag.Name)
 	cfg.WithoutHeimdall = ctx.Bool(WithoutHeimdallFlag.Name)
 	cfg.WithHeimdallMilestones = ctx.Bool(WithHeimdallMilestones.Name)
 	cfg.WithHeimdallWaypointRecording = ctx.Bool(WithHeimdallWaypoints.Name)
 	borsnaptype.RecordWayPoints(cfg.WithHeimdallWaypointRecording)
 	cfg.PolygonSync = ctx.Bool(PolygonSyncFlag.Name)
 	cfg.PolygonSyncStage = ctx.Bool(PolygonSyncStageFlag.Name)
+	cfg.PolygonSyncFromSnapshots = ctx.Bool(PolygonSyncFromSnapshotsFlag.Name)
+	cfg.PolygonSyncVerifySnapshots = ctx.Bool(PolygonSyncVerifySnapshotsFlag.Name)
 }
 
 func setMiner(ctx *cli.Context, cfg *params.MiningConfig) {
@@ -1741,9 +1840,36 @@ func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.CaplinConfig.BlobBackfilling = ctx.Bool(CaplinBlobBackfillingFlag.Name)
 	cfg.CaplinConfig.BlobPruningDisabled = ctx.Bool(CaplinDisableBlobPruningFlag.Name)
 	cfg.CaplinConfig.DisabledCheckpointSync = ctx.Bool(CaplinDisableCheckpointSyncFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncStateFile = ctx.String(CaplinCheckpointSyncStateFileFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncBlockFile = ctx.String(CaplinCheckpointSyncBlockFileFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncFallback = ctx.Bool(CaplinCheckpointSyncFallbackFlag.Name)
+	cfg.CaplinConfig.WeakSubjectivityCheckpoint = ctx.String(CaplinWeakSubjectivityCheckpointFlag.Name)
 	cfg.CaplinConfig.Archive = ctx.Bool(CaplinArchiveFlag.Name)
 	cfg.CaplinConfig.MevRelayUrl = ctx.String(CaplinMevRelayUrl.Name)
+	cfg.CaplinConfig.MevRelayTimeout = ctx.Duration(CaplinMevRelayTimeoutFlag.Name)
+	cfg.CaplinConfig.MevBuilderBoostFactor = ctx.Uint64(CaplinMevBuilderBoostFactorFlag.Name)
 	cfg.CaplinConfig.EnableValidatorMonitor = ctx.Bool(CaplinValidatorMonitorFlag.Name)
+	if validators := ctx.String(CaplinValidatorMonitorValidatorsFlag.Name); validators != "" {
+		for _, validator := range libcommon.CliString2Array(validators) {
+			vid, err := strconv.ParseUint(validator, 10, 64)
+			if err != nil {
+				Fatalf("Option %s: invalid validator index %q", CaplinValidatorMonitorValidatorsFlag.Name, validator)
+			}
+			cfg.CaplinConfig.ValidatorMonitorValidators = append(cfg.CaplinConfig.ValidatorMonitorValidators, vid)
+		}
+	}
+	cfg.CaplinConfig.EnableValidatorClient = ctx.Bool(CaplinValidatorClientFlag.Name)
+	cfg.CaplinConfig.ValidatorKeystoresDir = ctx.String(CaplinValidatorKeystoresDirFlag.Name)
+	cfg.CaplinConfig.ValidatorPasswordFile = ctx.String(CaplinValidatorPasswordFileFlag.Name)
+	if feeRecipient := ctx.String(CaplinValidatorFeeRecipientFlag.Name); feeRecipient != "" {
+		if !libcommon.IsHexAddress(feeRecipient) {
+			Fatalf("Option %s: invalid address %q", CaplinValidatorFeeRecipientFlag.Name, feeRecipient)
+		}
+		cfg.CaplinConfig.ValidatorFeeRecipient = libcommon.HexToAddress(feeRecipient)
+	}
+	cfg.CaplinConfig.ValidatorGraffiti = ctx.String(CaplinValidatorGraffitiFlag.Name)
+	cfg.CaplinConfig.KeymanagerAPIAddr = ctx.String(CaplinKeymanagerAPIAddrFlag.Name)
+	cfg.CaplinConfig.KeymanagerAPITokenFile = ctx.String(CaplinKeymanagerAPITokenFileFlag.Name)
 	if checkpointUrls := ctx.StringSlice(CaplinCheckpointSyncUrlFlag.Name); len(checkpointUrls) > 0 {
 		clparams.ConfigurableCheckpointsURLs = checkpointUrls
 	}
@@ -1814,6 +1940,8 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.CaplinConfig.CaplinDiscoveryTCPPort = ctx.Uint64(CaplinDiscoveryTCPPortFlag.Name)
 	cfg.CaplinConfig.SubscribeAllTopics = ctx.Bool(CaplinSubscribeAllTopicsFlag.Name)
 	cfg.CaplinConfig.MaxPeerCount = ctx.Uint64(CaplinMaxPeerCount.Name)
+	cfg.CaplinConfig.CustodyGroupCount = ctx.Uint64(CaplinCustodyGroupCountFlag.Name)
+	cfg.CaplinConfig.GossipTraceFile = ctx.String(CaplinGossipTraceFileFlag.Name)
 
 	cfg.CaplinConfig.SentinelAddr = ctx.String(SentinelAddrFlag.Name)
 	cfg.CaplinConfig.SentinelPort = ctx.Uint64(SentinelPortFlag.Name)
@@ -178,10 +178,15 @@ func WriteGenesisBlock(tx kv.RwTx, genesis *types.Genesis, overridePragueTime *b
 	}
 	// Check config compatibility and write the config. Compatibility errors
 	// are returned to the caller unless we're already at block zero.
-	height := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadHeaderHash(tx))
+	headHash := rawdb.ReadHeadHeaderHash(tx)
+	height := rawdb.ReadHeaderNumber(tx, headHash)
 	if height != nil {
-		compatibilityErr := storedCfg.CheckCompatible(newCfg, *height)
-		if compatibilityErr != nil && *height != 0 && compatibilityErr.RewindTo != 0 {
+		var headTime uint64
+		if head := rawdb.ReadHeader(tx, headHash, *height); head != nil {
+			headTime = head.Time
+		}
+		compatibilityErr := storedCfg.CheckCompatible(newCfg, *height, headTime)
+		if compatibilityErr != nil && *height != 0 && (compatibilityErr.RewindTo != 0 || compatibilityErr.RewindToTime != 0) {
 			return newCfg, storedBlock, compatibilityErr
 		}
 	}
@@ -197,7 +197,7 @@ func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bo
 					return nil, err
 				}
 				if r != nil {
-					account.Code = r
+					account.Code = libcommon.Copy(r)
 				}
 			}
 		}
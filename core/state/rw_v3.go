@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/erigontech/erigon-lib/log/v3"
@@ -50,6 +52,10 @@ type StateV3 struct {
 	addrIncBuf          []byte // buffer for ApplyState. Doesn't need mutex because Apply is single-threaded
 	logger              log.Logger
 
+	// flushEpoch - incremented when in-mem updates are flushed to db during parallel execution.
+	// ReadsValid checks only in-mem updates - so read-sets of txs executed before flush can't be validated.
+	flushEpoch atomic.Uint64
+
 	trace bool
 }
 
@@ -117,6 +123,23 @@ func (rs *StateV3) CommitTxNum(sender *common.Address, txNum uint64, in *QueueWi
 func (rs *StateV3) applyState(txTask *TxTask, domains *libstate.SharedDomains) error {
 	var acc accounts.Account
 
+	if len(txTask.AccountDels) > 0 {
+		// re-created accounts (see StateWriterBufferedV3.UpdateAccountData): drop old code/storage before applying new writes
+		addrs := make([]string, 0, len(txTask.AccountDels))
+		for addr := range txTask.AccountDels {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		for _, addr := range addrs {
+			if err := domains.DomainDel(kv.CodeDomain, []byte(addr), nil, nil, 0); err != nil {
+				return err
+			}
+			if err := domains.DomainDelPrefix(kv.StorageDomain, []byte(addr)); err != nil {
+				return err
+			}
+		}
+	}
+
 	//maps are unordered in Go! don't iterate over it. SharedDomains.deleteAccount will call GetLatest(Code) and expecting it not been delete yet
 	if txTask.WriteLists != nil {
 		for _, domain := range []kv.Domain{kv.AccountsDomain, kv.CodeDomain, kv.StorageDomain} {
@@ -338,6 +361,9 @@ func (rs *StateV3) ReadsValid(readLists map[string]*libstate.KvList) bool {
 	return rs.domains.ReadsValid(readLists)
 }
 
+func (rs *StateV3) FlushEpoch() uint64 { return rs.flushEpoch.Load() }
+func (rs *StateV3) IncFlushEpoch()     { rs.flushEpoch.Add(1) }
+
 // StateWriterBufferedV3 - used by parallel workers to accumulate updates and then send them to conflict-resolution.
 type StateWriterBufferedV3 struct {
 	rs           *StateV3
@@ -386,15 +412,11 @@ func (w *StateWriterBufferedV3) UpdateAccountData(address common.Address, origin
 	}
 	if original.Incarnation > account.Incarnation {
 		//del, before create: to clanup code/storage
-		if err := w.rs.domains.DomainDel(kv.CodeDomain, address[:], nil, nil, 0); err != nil {
-			return err
-		}
-		if err := w.rs.domains.IterateStoragePrefix(address[:], func(k, v []byte, step uint64) error {
-			w.writeLists[kv.StorageDomain.String()].Push(string(k), nil)
-			return nil
-		}); err != nil {
-			return err
+		//it's done by applyLoop (see `applyState`): worker can't read/write SharedDomains by `rwTx` of applyLoop
+		if w.accountDels == nil {
+			w.accountDels = map[string]*accounts.Account{}
 		}
+		w.accountDels[string(address[:])] = original
 	}
 	value := accounts.SerialiseV3(account)
 	if w.accumulator != nil {
@@ -661,6 +683,7 @@ type ReaderParallelV3 struct {
 	txNum     uint64
 	trace     bool
 	sd        *libstate.SharedDomains
+	tx        kv.Tx
 	composite []byte
 
 	discardReadList bool
@@ -678,19 +701,29 @@ func NewReaderParallelV3(sd *libstate.SharedDomains) *ReaderParallelV3 {
 
 func (r *ReaderParallelV3) DiscardReadList()                     { r.discardReadList = true }
 func (r *ReaderParallelV3) SetTxNum(txNum uint64)                { r.txNum = txNum }
-func (r *ReaderParallelV3) SetTx(tx kv.Tx)                       {}
+func (r *ReaderParallelV3) SetTx(tx kv.Tx)                       { r.tx = tx }
 func (r *ReaderParallelV3) ReadSet() map[string]*libstate.KvList { return r.readLists }
 func (r *ReaderParallelV3) SetTrace(trace bool)                  { r.trace = trace }
 func (r *ReaderParallelV3) ResetReadSet()                        { r.readLists = newReadList() }
 
+// domainGet - reads SharedDomains in-mem updates first and then own tx: worker can't use `sd.roTx`
+func (r *ReaderParallelV3) domainGet(domain kv.Domain, k []byte) ([]byte, error) {
+	if r.tx == nil {
+		v, _, err := r.sd.DomainGet(domain, k, nil)
+		return v, err
+	}
+	v, _, err := r.sd.DomainGetWithTx(domain, k, nil, r.tx)
+	return v, err
+}
+
 func (r *ReaderParallelV3) ReadAccountData(address common.Address) (*accounts.Account, error) {
-	enc, _, err := r.sd.DomainGet(kv.AccountsDomain, address[:], nil)
+	enc, err := r.domainGet(kv.AccountsDomain, address[:])
 	if err != nil {
 		return nil, err
 	}
 	if !r.discardReadList {
-		// lifecycle of `r.readList` is less than lifecycle of `r.rs` and `r.tx`, also `r.rs` and `r.tx` do store data immutable way
-		r.readLists[kv.AccountsDomain.String()].Push(string(address[:]), enc)
+		// copy: values may point to reusable buffers of `r.tx`, but readList is validated by applyLoop later
+		r.readLists[kv.AccountsDomain.String()].Push(string(address[:]), common.Copy(enc))
 	}
 	if len(enc) == 0 {
 		if r.trace {
@@ -711,12 +744,12 @@ func (r *ReaderParallelV3) ReadAccountData(address common.Address) (*accounts.Ac
 
 func (r *ReaderParallelV3) ReadAccountStorage(address common.Address, incarnation uint64, key *common.Hash) ([]byte, error) {
 	r.composite = append(append(r.composite[:0], address[:]...), key.Bytes()...)
-	enc, _, err := r.sd.DomainGet(kv.StorageDomain, r.composite, nil)
+	enc, err := r.domainGet(kv.StorageDomain, r.composite)
 	if err != nil {
 		return nil, err
 	}
 	if !r.discardReadList {
-		r.readLists[kv.StorageDomain.String()].Push(string(r.composite), enc)
+		r.readLists[kv.StorageDomain.String()].Push(string(r.composite), common.Copy(enc))
 	}
 	if r.trace {
 		if enc == nil {
@@ -729,13 +762,13 @@ func (r *ReaderParallelV3) ReadAccountStorage(address common.Address, incarnatio
 }
 
 func (r *ReaderParallelV3) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
-	enc, _, err := r.sd.DomainGet(kv.CodeDomain, address[:], nil)
+	enc, err := r.domainGet(kv.CodeDomain, address[:])
 	if err != nil {
 		return nil, err
 	}
 
 	if !r.discardReadList {
-		r.readLists[kv.CodeDomain.String()].Push(string(address[:]), enc)
+		r.readLists[kv.CodeDomain.String()].Push(string(address[:]), common.Copy(enc))
 	}
 	if r.trace {
 		fmt.Printf("ReadAccountCode [%x] => [%x], txNum: %d\n", address, enc, r.txNum)
@@ -744,7 +777,7 @@ func (r *ReaderParallelV3) ReadAccountCode(address common.Address, incarnation u
 }
 
 func (r *ReaderParallelV3) ReadAccountCodeSize(address common.Address, incarnation uint64, codeHash common.Hash) (int, error) {
-	enc, _, err := r.sd.DomainGet(kv.CodeDomain, address[:], nil)
+	enc, err := r.domainGet(kv.CodeDomain, address[:])
 	if err != nil {
 		return 0, err
 	}
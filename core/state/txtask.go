@@ -74,6 +74,9 @@ type TxTask struct {
 
 	UsedGas uint64
 
+	FlushEpoch uint64 // StateV3.FlushEpoch() at execution start - used by parallel execution to detect stale read-sets
+	Tracer     any    // historical re-execution only: tracer created by exec3.TraceConsumer.NewTracer for this txn (core/vm can't be imported here)
+
 	// BlockReceipts is used only by Gnosis:
 	//  - it does store `proof, err := rlp.EncodeToBytes(ValidatorSetProof{Header: header, Receipts: r})`
 	//  - and later read it by filter: len(l.Topics) == 2 && l.Address == s.contractAddress && l.Topics[0] == EVENT_NAME_HASH && l.Topics[1] == header.ParentHash
@@ -146,9 +149,11 @@ func (t *TxTask) Reset() {
 	t.ReadLists = nil
 	returnWriteList(t.WriteLists)
 	t.WriteLists = nil
+	t.AccountPrevs, t.AccountDels, t.StoragePrevs, t.CodePrevs = nil, nil, nil, nil
 	t.Logs = nil
 	t.TraceFroms = nil
 	t.TraceTos = nil
+	t.Tracer = nil
 }
 
 // TxTaskQueue non-thread-safe priority-queue
@@ -188,8 +188,12 @@ func init() {
 	}
 }
 
-// ActivePrecompiles returns the precompiles enabled with the current configuration.
+// ActivePrecompiles returns the precompiles enabled with the current configuration (including custom ones).
 func ActivePrecompiles(rules *chain.Rules) []libcommon.Address {
+	return withCustomPrecompiles(rules, activeBuiltinPrecompiles(rules))
+}
+
+func activeBuiltinPrecompiles(rules *chain.Rules) []libcommon.Address {
 	switch {
 	case rules.IsPrague:
 		return PrecompiledAddressesPrague
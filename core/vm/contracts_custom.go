@@ -0,0 +1,115 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/erigontech/erigon-lib/chain"
+	libcommon "github.com/erigontech/erigon-lib/common"
+)
+
+// Custom precompiles: Go implementations registered by name, activated on custom networks by
+// `chain.Config.CustomPrecompiles` (address and activation block/time). Gas is charged by
+// `RequiredGas` - the same way as for built-in precompiles.
+var customPrecompiles = struct {
+	sync.RWMutex
+	byName map[string]PrecompiledContract
+}{byName: map[string]PrecompiledContract{}}
+
+// RegisterPrecompile - registers implementation of custom precompile under `name`. Registering same name again
+// replaces implementation: it's picked-up by next EVM call (already running calls are not affected).
+// Must be called before node start (from `init()` or `main()`) - see also CheckCustomPrecompiles.
+func RegisterPrecompile(name string, p PrecompiledContract) {
+	if p == nil {
+		panic(fmt.Sprintf("nil precompile: %s", name))
+	}
+	customPrecompiles.Lock()
+	defer customPrecompiles.Unlock()
+	customPrecompiles.byName[name] = p
+}
+
+// UnregisterPrecompile - removes implementation. Networks which activated it will fail CheckCustomPrecompiles.
+func UnregisterPrecompile(name string) {
+	customPrecompiles.Lock()
+	defer customPrecompiles.Unlock()
+	delete(customPrecompiles.byName, name)
+}
+
+func registeredPrecompile(name string) (PrecompiledContract, bool) {
+	customPrecompiles.RLock()
+	defer customPrecompiles.RUnlock()
+	p, ok := customPrecompiles.byName[name]
+	return p, ok
+}
+
+// customPrecompile - implementation of custom precompile active at `addr`
+func customPrecompile(rules *chain.Rules, addr libcommon.Address) (PrecompiledContract, bool) {
+	name, ok := rules.CustomPrecompiles[addr]
+	if !ok {
+		return nil, false
+	}
+	p, ok := registeredPrecompile(name)
+	if !ok {
+		// chain config must be validated by CheckCustomPrecompiles at startup. Tools which skipped it (or unregistered
+		// implementation) get reverting call instead of crash of the whole process.
+		return unregisteredPrecompile{}, true
+	}
+	return p, true
+}
+
+// unregisteredPrecompile - stands for active custom precompile without registered implementation: every call reverts
+type unregisteredPrecompile struct{}
+
+func (unregisteredPrecompile) RequiredGas(input []byte) uint64 { return 0 }
+
+func (unregisteredPrecompile) Run(input []byte) ([]byte, error) { return nil, ErrExecutionReverted }
+
+// CheckCustomPrecompiles - validates `CustomPrecompiles` of chain config: all implementations are registered,
+// activation is scheduled, addresses are unique and don't shadow built-in precompiles.
+func CheckCustomPrecompiles(config *chain.Config) error {
+	if err := config.CheckCustomPrecompiles(); err != nil {
+		return err
+	}
+	for _, p := range config.CustomPrecompiles {
+		if _, ok := registeredPrecompile(p.Name); !ok {
+			return fmt.Errorf("custom precompile %s at %x: implementation is not registered (see vm.RegisterPrecompile)", p.Name, p.Address)
+		}
+		for _, builtin := range []map[libcommon.Address]PrecompiledContract{PrecompiledContractsPrague, PrecompiledContractsNapoli} {
+			if _, ok := builtin[p.Address]; ok {
+				return fmt.Errorf("custom precompile %s at %x: address of built-in precompile", p.Name, p.Address)
+			}
+		}
+	}
+	return nil
+}
+
+// withCustomPrecompiles - `addresses` of built-in precompiles plus active custom ones (sorted), doesn't modify `addresses`
+func withCustomPrecompiles(rules *chain.Rules, addresses []libcommon.Address) []libcommon.Address {
+	if len(rules.CustomPrecompiles) == 0 {
+		return addresses
+	}
+	custom := make([]libcommon.Address, 0, len(rules.CustomPrecompiles))
+	for addr := range rules.CustomPrecompiles {
+		custom = append(custom, addr)
+	}
+	slices.SortFunc(custom, func(a, b libcommon.Address) int { return bytes.Compare(a[:], b[:]) })
+	return slices.Concat(addresses, custom)
+}
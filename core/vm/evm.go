@@ -55,8 +55,10 @@ func (evm *EVM) precompile(addr libcommon.Address) (PrecompiledContract, bool) {
 	default:
 		precompiles = PrecompiledContractsHomestead
 	}
-	p, ok := precompiles[addr]
-	return p, ok
+	if p, ok := precompiles[addr]; ok {
+		return p, ok
+	}
+	return customPrecompile(evm.chainRules, addr)
 }
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
@@ -127,37 +127,41 @@ func copyJumpTable(jt *JumpTable) *JumpTable {
 	return &copy
 }
 
-// NewEVMInterpreter returns a new instance of the Interpreter.
-func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
-	var jt *JumpTable
+// instructionSet returns jump table of fork of given rules (without ExtraEips)
+func instructionSet(rules *chain.Rules) *JumpTable {
 	switch {
-	case evm.ChainRules().IsPrague:
-		jt = &pragueInstructionSet
-	case evm.ChainRules().IsCancun:
-		jt = &cancunInstructionSet
-	case evm.ChainRules().IsNapoli:
-		jt = &napoliInstructionSet
-	case evm.ChainRules().IsShanghai:
-		jt = &shanghaiInstructionSet
-	case evm.ChainRules().IsLondon:
-		jt = &londonInstructionSet
-	case evm.ChainRules().IsBerlin:
-		jt = &berlinInstructionSet
-	case evm.ChainRules().IsIstanbul:
-		jt = &istanbulInstructionSet
-	case evm.ChainRules().IsConstantinople:
-		jt = &constantinopleInstructionSet
-	case evm.ChainRules().IsByzantium:
-		jt = &byzantiumInstructionSet
-	case evm.ChainRules().IsSpuriousDragon:
-		jt = &spuriousDragonInstructionSet
-	case evm.ChainRules().IsTangerineWhistle:
-		jt = &tangerineWhistleInstructionSet
-	case evm.ChainRules().IsHomestead:
-		jt = &homesteadInstructionSet
+	case rules.IsPrague:
+		return &pragueInstructionSet
+	case rules.IsCancun:
+		return &cancunInstructionSet
+	case rules.IsNapoli:
+		return &napoliInstructionSet
+	case rules.IsShanghai:
+		return &shanghaiInstructionSet
+	case rules.IsLondon:
+		return &londonInstructionSet
+	case rules.IsBerlin:
+		return &berlinInstructionSet
+	case rules.IsIstanbul:
+		return &istanbulInstructionSet
+	case rules.IsConstantinople:
+		return &constantinopleInstructionSet
+	case rules.IsByzantium:
+		return &byzantiumInstructionSet
+	case rules.IsSpuriousDragon:
+		return &spuriousDragonInstructionSet
+	case rules.IsTangerineWhistle:
+		return &tangerineWhistleInstructionSet
+	case rules.IsHomestead:
+		return &homesteadInstructionSet
 	default:
-		jt = &frontierInstructionSet
+		return &frontierInstructionSet
 	}
+}
+
+// NewEVMInterpreter returns a new instance of the Interpreter.
+func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
+	jt := instructionSet(evm.ChainRules())
 	if len(cfg.ExtraEips) > 0 {
 		jt = copyJumpTable(jt)
 		for i, eip := range cfg.ExtraEips {
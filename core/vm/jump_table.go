@@ -22,6 +22,8 @@ package vm
 import (
 	"fmt"
 
+	"github.com/erigontech/erigon-lib/chain"
+
 	"github.com/erigontech/erigon/core/vm/stack"
 	"github.com/erigontech/erigon/params"
 )
@@ -51,6 +53,7 @@ type operation struct {
 	opNum   int // only for push, swap, dup
 	// memorySize returns the memory size required for the operation
 	memorySize memorySizeFunc
+	undefined  bool // opcode is not assigned at this fork
 }
 
 var (
@@ -72,6 +75,18 @@ var (
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]*operation
 
+// ActiveOpcodes returns opcodes defined at fork of given rules (ExtraEips are not included).
+func ActiveOpcodes(rules *chain.Rules) []OpCode {
+	jt := instructionSet(rules)
+	ops := make([]OpCode, 0, len(jt))
+	for i, op := range jt {
+		if !op.undefined {
+			ops = append(ops, OpCode(i))
+		}
+	}
+	return ops
+}
+
 func validateAndFillMaxStack(jt *JumpTable) {
 	for i, op := range jt {
 		if op == nil {
@@ -1215,7 +1230,7 @@ func newFrontierInstructionSet() JumpTable {
 	// Fill all unassigned slots with opUndefined.
 	for i, entry := range tbl {
 		if entry == nil {
-			tbl[i] = &operation{execute: opUndefined}
+			tbl[i] = &operation{execute: opUndefined, undefined: true}
 		}
 	}
 
@@ -744,3 +744,59 @@ func TestEip2929Cases(t *testing.T) {
 			"account (cheap)", code)
 	}
 }
+
+type fortyTwo struct{}
+
+func (fortyTwo) RequiredGas(input []byte) uint64  { return 1000 }
+func (fortyTwo) Run(input []byte) ([]byte, error) { return []byte{42}, nil }
+
+func TestCustomPrecompile(t *testing.T) {
+	t.Parallel()
+	vm.RegisterPrecompile("test-forty-two", fortyTwo{})
+	precompile := libcommon.HexToAddress("0x0000000000000000000000000000000000c0ffee")
+
+	// staticcall(gas, precompile, 0, 0, 0, 32); mstore(32, success); return(0, 64)
+	code := func(gas byte) []byte {
+		return []byte{
+			byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+			byte(vm.PUSH3), 0xc0, 0xff, 0xee, byte(vm.PUSH2), 0x03, gas, byte(vm.STATICCALL),
+			byte(vm.PUSH1), 32, byte(vm.MSTORE),
+			byte(vm.PUSH1), 64, byte(vm.PUSH1), 0, byte(vm.RETURN),
+		}
+	}
+	executeNamed := func(name string, blockNum int64, gas byte) (out byte, success bool) {
+		cfg := &Config{BlockNumber: big.NewInt(blockNum)}
+		setDefaults(cfg)
+		chainConfig := *cfg.ChainConfig
+		chainConfig.CustomPrecompiles = []chain.CustomPrecompile{{Name: name, Address: precompile, Block: big.NewInt(10)}}
+		cfg.ChainConfig = &chainConfig
+		ret, _, err := Execute(code(gas), nil, cfg, t.TempDir())
+		require.NoError(t, err)
+		require.Len(t, ret, 64)
+		return ret[0], ret[63] == 1
+	}
+	execute := func(blockNum int64, gas byte) (out byte, success bool) {
+		return executeNamed("test-forty-two", blockNum, gas)
+	}
+
+	out, success := execute(9, 0xe8) // 1000 gas, not active yet: call of empty account
+	require.True(t, success)
+	require.Zero(t, out)
+
+	out, success = execute(10, 0xe8)
+	require.True(t, success)
+	require.Equal(t, byte(42), out)
+
+	_, success = execute(10, 0xe7) // 999 gas: less than RequiredGas
+	require.False(t, success)
+
+	rules := (&chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "test-forty-two", Address: precompile, Block: big.NewInt(10)}}}).Rules(10, 0)
+	require.Contains(t, vm.ActivePrecompiles(rules), precompile)
+	require.Len(t, vm.ActivePrecompiles(rules), len(vm.PrecompiledAddressesHomestead)+1)
+	require.NoError(t, vm.CheckCustomPrecompiles(&chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "test-forty-two", Address: precompile, Block: big.NewInt(10)}}}))
+	require.Error(t, vm.CheckCustomPrecompiles(&chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "not-registered", Address: precompile, Block: big.NewInt(10)}}}))
+	require.Error(t, vm.CheckCustomPrecompiles(&chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "test-forty-two", Address: precompile, Block: big.NewInt(10)}, {Name: "test-forty-two", Address: precompile, Block: big.NewInt(20)}}}))
+
+	_, success = executeNamed("not-registered", 10, 0xe8) // active, but not registered: reverts instead of panic
+	require.False(t, success)
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package diagnostics
+
+import (
+	"net/http"
+
+	diaglib "github.com/erigontech/erigon-lib/diagnostics"
+)
+
+func SetupBlockExecutionAccess(metricsMux *http.ServeMux, diag *diaglib.DiagnosticClient) {
+	if metricsMux == nil {
+		return
+	}
+
+	metricsMux.HandleFunc("/block-execution", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		writeBlockExecution(w, diag)
+	})
+
+	metricsMux.HandleFunc("/block-execution-profiles", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		writeBlockExecutionProfiles(w, diag)
+	})
+}
+
+func writeBlockExecution(w http.ResponseWriter, diag *diaglib.DiagnosticClient) {
+	diag.BlockExecutionInfoJson(w)
+}
+
+func writeBlockExecutionProfiles(w http.ResponseWriter, diag *diaglib.DiagnosticClient) {
+	diag.BlockExecutionProfilesJson(w)
+}
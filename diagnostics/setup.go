@@ -79,6 +79,7 @@ func Setup(ctx *cli.Context, node *node.ErigonNode, metricsMux *http.ServeMux, p
 	diagnostic, err := diaglib.NewDiagnosticClient(ctx.Context, diagMux, node.Backend().DataDir(), speedTest, webseedsList)
 	if err == nil {
 		diagnostic.Setup()
+		node.Backend().SetDiagnosticClient(diagnostic)
 		SetupEndpoints(ctx, node, diagMux, diagnostic)
 	} else {
 		log.Error("[Diagnostics] Failure in setting up diagnostics", "err", err)
@@ -145,6 +146,8 @@ func SetupEndpoints(ctx *cli.Context, node *node.ErigonNode, diagMux *http.Serve
 	SetupMemAccess(diagMux)
 	SetupHeadersAccess(diagMux, diagnostic)
 	SetupBodiesAccess(diagMux, diagnostic)
+	SetupBlockExecutionAccess(diagMux, diagnostic)
 	SetupSysInfoAccess(diagMux, diagnostic)
 	SetupProfileAccess(diagMux, diagnostic)
+	SetupGossipAccess(diagMux, diagnostic)
 }
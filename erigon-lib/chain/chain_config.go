@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"slices"
 	"strconv"
 
 	"github.com/erigontech/erigon-lib/common"
@@ -82,6 +83,9 @@ type Config struct {
 	// See also EIP-6110: Supply validator deposits on chain
 	DepositContract common.Address `json:"depositContractAddress,omitempty"`
 
+	// (Optional) extra precompiles of custom networks. Implementations are registered in core/vm by name.
+	CustomPrecompiles []CustomPrecompile `json:"customPrecompiles,omitempty"`
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
@@ -91,6 +95,20 @@ type Config struct {
 	BorJSON json.RawMessage `json:"bor,omitempty"`
 }
 
+// CustomPrecompile - precompile of custom network: Go implementation registered by `vm.RegisterPrecompile(Name, ...)`
+// becomes active at Address since Block (or since Time, for networks scheduling forks by timestamp).
+// Address can't be shared by several entries - see Config.CheckCustomPrecompiles.
+type CustomPrecompile struct {
+	Name    string         `json:"name"`
+	Address common.Address `json:"address"`
+	Block   *big.Int       `json:"block,omitempty"`
+	Time    *big.Int       `json:"time,omitempty"`
+}
+
+func (p *CustomPrecompile) IsActive(num uint64, time uint64) bool {
+	return isForked(p.Block, num) || isForked(p.Time, time)
+}
+
 type BorConfig interface {
 	fmt.Stringer
 	IsAgra(num uint64) bool
@@ -253,6 +271,41 @@ func (c *Config) GetBurntContract(num uint64) *common.Address {
 	return &addr
 }
 
+// CheckCustomPrecompiles - validates schedule of `CustomPrecompiles`: activation is set by either block or time,
+// every address is used by one entry only. Registration of implementations is checked by vm.CheckCustomPrecompiles.
+func (c *Config) CheckCustomPrecompiles() error {
+	seen := make(map[common.Address]string, len(c.CustomPrecompiles))
+	for _, p := range c.CustomPrecompiles {
+		if p.Block == nil && p.Time == nil {
+			return fmt.Errorf("custom precompile %s at %x: neither block nor time of activation is set", p.Name, p.Address)
+		}
+		if p.Block != nil && p.Time != nil {
+			return fmt.Errorf("custom precompile %s at %x: both block and time of activation are set", p.Name, p.Address)
+		}
+		if name, ok := seen[p.Address]; ok {
+			return fmt.Errorf("custom precompile %s at %x: address is already used by %s", p.Name, p.Address, name)
+		}
+		seen[p.Address] = p.Name
+	}
+	return nil
+}
+
+// ActiveCustomPrecompiles returns name of implementation by address of custom precompiles active at given block
+func (c *Config) ActiveCustomPrecompiles(num uint64, time uint64) map[common.Address]string {
+	var active map[common.Address]string
+	for i := range c.CustomPrecompiles {
+		p := &c.CustomPrecompiles[i]
+		if !p.IsActive(num, time) {
+			continue
+		}
+		if active == nil {
+			active = make(map[common.Address]string, len(c.CustomPrecompiles))
+		}
+		active[p.Address] = p.Name
+	}
+	return active
+}
+
 func (c *Config) GetMinBlobGasPrice() uint64 {
 	if c != nil && c.MinBlobGasPrice != nil {
 		return *c.MinBlobGasPrice
@@ -287,18 +340,22 @@ func (c *Config) GetMaxBlobsPerBlock() uint64 {
 
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
-func (c *Config) CheckCompatible(newcfg *Config, height uint64) *ConfigCompatError {
-	bhead := height
+func (c *Config) CheckCompatible(newcfg *Config, height, time uint64) *ConfigCompatError {
+	bhead, btime := height, time
 
 	// Iterate checkCompatible to find the lowest conflict.
 	var lasterr *ConfigCompatError
 	for {
-		err := c.checkCompatible(newcfg, bhead)
-		if err == nil || (lasterr != nil && err.RewindTo == lasterr.RewindTo) {
+		err := c.checkCompatible(newcfg, bhead, btime)
+		if err == nil || (lasterr != nil && err.RewindTo == lasterr.RewindTo && err.RewindToTime == lasterr.RewindToTime) {
 			break
 		}
 		lasterr = err
-		bhead = err.RewindTo
+		if err.RewindToTime > 0 {
+			btime = err.RewindToTime
+		} else {
+			bhead = err.RewindTo
+		}
 	}
 	return lasterr
 }
@@ -355,10 +412,10 @@ func (c *Config) CheckConfigForkOrder() error {
 			lastFork = fork
 		}
 	}
-	return nil
+	return c.CheckCustomPrecompiles()
 }
 
-func (c *Config) checkCompatible(newcfg *Config, head uint64) *ConfigCompatError {
+func (c *Config) checkCompatible(newcfg *Config, head, headTime uint64) *ConfigCompatError {
 	// returns true if a fork scheduled at s1 cannot be rescheduled to block s2 because head is already past the fork.
 	incompatible := func(s1, s2 *big.Int, head uint64) bool {
 		return (isForked(s1, head) || isForked(s2, head)) && !numEqual(s1, s2)
@@ -414,6 +471,25 @@ func (c *Config) checkCompatible(newcfg *Config, head uint64) *ConfigCompatError
 	if incompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, head) {
 		return newCompatError("Merge netsplit block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	// custom precompile is identified by name and address: added, removed or rescheduled below head is incompatible
+	customSchedule := func(cfg *Config, p CustomPrecompile) (block, time *big.Int) {
+		for _, cp := range cfg.CustomPrecompiles {
+			if cp.Name == p.Name && cp.Address == p.Address {
+				return cp.Block, cp.Time
+			}
+		}
+		return nil, nil
+	}
+	for _, p := range slices.Concat(c.CustomPrecompiles, newcfg.CustomPrecompiles) {
+		storedBlock, storedTime := customSchedule(c, p)
+		newBlock, newTime := customSchedule(newcfg, p)
+		if incompatible(storedBlock, newBlock, head) {
+			return newCompatError("custom precompile "+p.Name+" block", storedBlock, newBlock)
+		}
+		if incompatible(storedTime, newTime, headTime) {
+			return newTimestampCompatError("custom precompile "+p.Name+" timestamp", storedTime, newTime)
+		}
+	}
 
 	return nil
 }
@@ -436,6 +512,8 @@ type ConfigCompatError struct {
 	StoredConfig, NewConfig *big.Int
 	// the block number to which the local chain must be rewound to correct the error
 	RewindTo uint64
+	// the timestamp to which the local chain must be rewound to correct the error, for timestamp scheduled changes
+	RewindToTime uint64
 }
 
 func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatError {
@@ -448,14 +526,34 @@ func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatEr
 	default:
 		rew = newblock
 	}
-	err := &ConfigCompatError{what, storedblock, newblock, 0}
+	err := &ConfigCompatError{What: what, StoredConfig: storedblock, NewConfig: newblock}
 	if rew != nil && rew.Sign() > 0 {
 		err.RewindTo = rew.Uint64() - 1
 	}
 	return err
 }
 
+func newTimestampCompatError(what string, storedtime, newtime *big.Int) *ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedtime == nil:
+		rew = newtime
+	case newtime == nil || storedtime.Cmp(newtime) < 0:
+		rew = storedtime
+	default:
+		rew = newtime
+	}
+	err := &ConfigCompatError{What: what, StoredConfig: storedtime, NewConfig: newtime}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindToTime = rew.Uint64() - 1
+	}
+	return err
+}
+
 func (err *ConfigCompatError) Error() string {
+	if err.RewindToTime > 0 {
+		return fmt.Sprintf("mismatching %s in database (have timestamp %d, want timestamp %d, rewindto timestamp %d)", err.What, err.StoredConfig, err.NewConfig, err.RewindToTime)
+	}
 	return fmt.Sprintf("mismatching %s in database (have %d, want %d, rewindto %d)", err.What, err.StoredConfig, err.NewConfig, err.RewindTo)
 }
 
@@ -512,6 +610,8 @@ type Rules struct {
 	IsCancun, IsNapoli                                bool
 	IsPrague, IsOsaka                                 bool
 	IsAura                                            bool
+
+	CustomPrecompiles map[common.Address]string // address => name of implementation, see Config.CustomPrecompiles
 }
 
 // Rules ensures c's ChainID is not nil and returns a new Rules instance
@@ -538,6 +638,7 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsPrague:           c.IsPrague(time),
 		IsOsaka:            c.IsOsaka(time),
 		IsAura:             c.Aura != nil,
+		CustomPrecompiles:  c.ActiveCustomPrecompiles(num, time),
 	}
 }
 
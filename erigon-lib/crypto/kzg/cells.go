@@ -0,0 +1,215 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+)
+
+// PeerDAS (EIP-7594) extends every blob to twice its size and splits it into cells, each one provable on its own.
+// The cell proofs are computed and verified by go-eth-kzg.
+const (
+	BytesPerFieldElement    = goethkzg.SerializedScalarSize
+	FieldElementsPerBlob    = goethkzg.ScalarsPerBlob
+	FieldElementsPerExtBlob = 2 * FieldElementsPerBlob
+	FieldElementsPerCell    = FieldElementsPerExtBlob / CellsPerExtBlob
+	BytesPerCell            = goethkzg.BytesPerCell
+	CellsPerExtBlob         = goethkzg.CellsPerExtBlob
+)
+
+// Cell is a chunk of FieldElementsPerCell evaluations of the extended blob polynomial.
+type Cell [BytesPerCell]byte
+
+var (
+	ErrInvalidCellIndex   = errors.New("invalid cell index")
+	ErrInvalidCell        = errors.New("invalid cell")
+	ErrInvalidCellProof   = errors.New("invalid cell kzg proof")
+	ErrNotEnoughCells     = errors.New("not enough cells to recover the blob")
+	ErrCellBatchMismatch  = errors.New("mismatched lengths in cell batch")
+	errMissingG2Monomials = errors.New("trusted setup does not have enough g2 points")
+)
+
+var (
+	cellsCtx     *goethkzg.Context
+	cellsCtxErr  error
+	initCellsCtx sync.Once
+)
+
+// InitCellsCtx loads the trusted setup used by cell proofs, it is expensive and done once.
+func InitCellsCtx() error {
+	initCellsCtx.Do(func() {
+		cellsCtx, cellsCtxErr = loadCellsContext()
+	})
+	return cellsCtxErr
+}
+
+func loadCellsContext() (*goethkzg.Context, error) {
+	if trustedSetupFile == "" {
+		return goethkzg.NewContext4096Secure()
+	}
+	setupJson, err := os.ReadFile(trustedSetupFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read trusted setup file: %w", err)
+	}
+	setup := new(goethkzg.JSONTrustedSetup)
+	if err := json.Unmarshal(setupJson, setup); err != nil {
+		return nil, fmt.Errorf("could not unmarshal trusted setup: %w", err)
+	}
+	// go-eth-kzg panics on points it can't parse and on a setup too short for cell proofs.
+	if len(setup.SetupG2) < FieldElementsPerCell+1 {
+		return nil, errMissingG2Monomials
+	}
+	if err := goethkzg.CheckTrustedSetupIsWellFormed(setup); err != nil {
+		return nil, fmt.Errorf("invalid trusted setup: %w", err)
+	}
+	return goethkzg.NewContext4096(setup)
+}
+
+func cellsCtxOrInit() (*goethkzg.Context, error) {
+	if err := InitCellsCtx(); err != nil {
+		return nil, err
+	}
+	return cellsCtx, nil
+}
+
+func fromEthKzgCells(cells [CellsPerExtBlob]*goethkzg.Cell) []Cell {
+	res := make([]Cell, len(cells))
+	for i, cell := range cells {
+		res[i] = Cell(*cell)
+	}
+	return res
+}
+
+func fromEthKzgProofs(proofs [CellsPerExtBlob]goethkzg.KZGProof) []gokzg4844.KZGProof {
+	res := make([]gokzg4844.KZGProof, len(proofs))
+	for i, proof := range proofs {
+		res[i] = gokzg4844.KZGProof(proof)
+	}
+	return res
+}
+
+func toEthKzgCells(cells []Cell) []*goethkzg.Cell {
+	res := make([]*goethkzg.Cell, len(cells))
+	for i := range cells {
+		res[i] = (*goethkzg.Cell)(&cells[i])
+	}
+	return res
+}
+
+// ComputeCells implements compute_cells, it extends the blob and splits it into CellsPerExtBlob cells.
+func ComputeCells(blob *gokzg4844.Blob) ([]Cell, error) {
+	c, err := cellsCtxOrInit()
+	if err != nil {
+		return nil, err
+	}
+	cells, err := c.ComputeCells((*goethkzg.Blob)(blob), 0)
+	if err != nil {
+		return nil, err
+	}
+	return fromEthKzgCells(cells), nil
+}
+
+// ComputeCellsAndKZGProofs implements compute_cells_and_kzg_proofs.
+func ComputeCellsAndKZGProofs(blob *gokzg4844.Blob) ([]Cell, []gokzg4844.KZGProof, error) {
+	c, err := cellsCtxOrInit()
+	if err != nil {
+		return nil, nil, err
+	}
+	cells, proofs, err := c.ComputeCellsAndKZGProofs((*goethkzg.Blob)(blob), 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fromEthKzgCells(cells), fromEthKzgProofs(proofs), nil
+}
+
+func checkCell(cell *Cell) error {
+	for k := 0; k < FieldElementsPerCell; k++ {
+		var scalar goethkzg.Scalar
+		copy(scalar[:], cell[k*BytesPerFieldElement:(k+1)*BytesPerFieldElement])
+		if _, err := goethkzg.DeserializeScalar(scalar); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCell, err)
+		}
+	}
+	return nil
+}
+
+// VerifyCellKZGProofBatch implements verify_cell_kzg_proof_batch, every cell is checked against the commitment
+// of its blob. Malformed input is rejected upfront, so ErrInvalidCellProof is only returned for proofs which
+// don't open the commitments to the cells.
+func VerifyCellKZGProofBatch(commitments []gokzg4844.KZGCommitment, cellIndices []uint64, cells []Cell, proofs []gokzg4844.KZGProof) error {
+	if len(commitments) != len(cellIndices) || len(cellIndices) != len(cells) || len(cells) != len(proofs) {
+		return ErrCellBatchMismatch
+	}
+	c, err := cellsCtxOrInit()
+	if err != nil {
+		return err
+	}
+	ethKzgCommitments := make([]goethkzg.KZGCommitment, len(commitments))
+	ethKzgProofs := make([]goethkzg.KZGProof, len(proofs))
+	for i := range cells {
+		if cellIndices[i] >= CellsPerExtBlob {
+			return ErrInvalidCellIndex
+		}
+		ethKzgCommitments[i] = goethkzg.KZGCommitment(commitments[i])
+		if _, err := goethkzg.DeserializeKZGCommitment(ethKzgCommitments[i]); err != nil {
+			return fmt.Errorf("invalid commitment: %w", err)
+		}
+		ethKzgProofs[i] = goethkzg.KZGProof(proofs[i])
+		if _, err := goethkzg.DeserializeKZGProof(ethKzgProofs[i]); err != nil {
+			return fmt.Errorf("invalid proof: %w", err)
+		}
+		if err := checkCell(&cells[i]); err != nil {
+			return err
+		}
+	}
+	if err := c.VerifyCellKZGProofBatch(ethKzgCommitments, cellIndices, toEthKzgCells(cells), ethKzgProofs); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCellProof, err)
+	}
+	return nil
+}
+
+// RecoverCellsAndKZGProofs implements recover_cells_and_kzg_proofs, any half of the cells of a blob is enough to
+// rebuild all of them.
+func RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([]Cell, []gokzg4844.KZGProof, error) {
+	if len(cellIndices) != len(cells) {
+		return nil, nil, ErrCellBatchMismatch
+	}
+	if len(cellIndices) < CellsPerExtBlob/2 {
+		return nil, nil, ErrNotEnoughCells
+	}
+	c, err := cellsCtxOrInit()
+	if err != nil {
+		return nil, nil, err
+	}
+	recoveredCells, proofs, err := c.RecoverCellsAndComputeKZGProofs(cellIndices, toEthKzgCells(cells), 0)
+	switch {
+	case errors.Is(err, goethkzg.ErrCellIDsNotUnique), errors.Is(err, goethkzg.ErrFoundInvalidCellID):
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCellIndex, err)
+	case errors.Is(err, goethkzg.ErrNonCanonicalScalar):
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCell, err)
+	case err != nil:
+		return nil, nil, err
+	}
+	return fromEthKzgCells(recoveredCells), fromEthKzgProofs(proofs), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBlob(t *testing.T) *gokzg4844.Blob {
+	var blob gokzg4844.Blob
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var e fr.Element
+		_, err := e.SetRandom()
+		require.NoError(t, err)
+		b := e.Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return &blob
+}
+
+func TestComputeCellsMatchesBlob(t *testing.T) {
+	blob := randomBlob(t)
+	cells, err := ComputeCells(blob)
+	require.NoError(t, err)
+	require.Len(t, cells, CellsPerExtBlob)
+	// the first half of the extended blob, in bit-reversed order, is the blob itself.
+	var original []byte
+	for i := 0; i < CellsPerExtBlob/2; i++ {
+		original = append(original, cells[i][:]...)
+	}
+	require.Equal(t, blob[:], original)
+}
+
+func TestCellProofs(t *testing.T) {
+	blob := randomBlob(t)
+	commitment, err := Ctx().BlobToKZGCommitment(*blob, 0)
+	require.NoError(t, err)
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	commitments := make([]gokzg4844.KZGCommitment, CellsPerExtBlob)
+	indices := make([]uint64, CellsPerExtBlob)
+	for i := range indices {
+		commitments[i] = commitment
+		indices[i] = uint64(i)
+	}
+	require.NoError(t, VerifyCellKZGProofBatch(commitments, indices, cells, proofs))
+
+	// a proof for another cell must not verify.
+	require.ErrorIs(t, VerifyCellKZGProofBatch(commitments[:1], indices[:1], cells[:1], proofs[1:2]), ErrInvalidCellProof)
+	cells[3][5] ^= 1
+	require.ErrorIs(t, VerifyCellKZGProofBatch(commitments[3:4], indices[3:4], cells[3:4], proofs[3:4]), ErrInvalidCellProof)
+
+	// malformed input is rejected, it doesn't fail verification.
+	cells[3][0] = 0xff
+	err = VerifyCellKZGProofBatch(commitments[3:4], indices[3:4], cells[3:4], proofs[3:4])
+	require.ErrorIs(t, err, ErrInvalidCell)
+	require.NotErrorIs(t, err, ErrInvalidCellProof)
+	err = VerifyCellKZGProofBatch(commitments[:1], []uint64{CellsPerExtBlob}, cells[:1], proofs[:1])
+	require.ErrorIs(t, err, ErrInvalidCellIndex)
+	require.NotErrorIs(t, err, ErrInvalidCellProof)
+}
+
+func TestLoadCellsContextBadSetupFile(t *testing.T) {
+	defer SetTrustedSetupFilePath(trustedSetupFile)
+
+	SetTrustedSetupFilePath(filepath.Join(t.TempDir(), "missing.json"))
+	_, err := loadCellsContext()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "setup.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"g1_lagrange": [], "g2_monomial": ["0x00"]}`), 0o600))
+	SetTrustedSetupFilePath(path)
+	_, err = loadCellsContext()
+	require.ErrorIs(t, err, errMissingG2Monomials)
+}
+
+func TestRecoverCellsAndKZGProofs(t *testing.T) {
+	blob := randomBlob(t)
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	require.NoError(t, err)
+
+	var indices []uint64
+	var partial []Cell
+	var seed [1]byte
+	for i := uint64(0); i < CellsPerExtBlob; i++ {
+		_, err := rand.Read(seed[:])
+		require.NoError(t, err)
+		if len(indices) < CellsPerExtBlob/2 && (seed[0]%2 == 0 || CellsPerExtBlob-i <= uint64(CellsPerExtBlob/2-len(indices))) {
+			indices = append(indices, i)
+			partial = append(partial, cells[i])
+		}
+	}
+	require.Len(t, indices, CellsPerExtBlob/2)
+
+	recoveredCells, recoveredProofs, err := RecoverCellsAndKZGProofs(indices, partial)
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+	require.Equal(t, proofs, recoveredProofs)
+
+	_, _, err = RecoverCellsAndKZGProofs(indices[1:], partial[1:])
+	require.ErrorIs(t, err, ErrNotEnoughCells)
+	_, _, err = RecoverCellsAndKZGProofs(append([]uint64{indices[1]}, indices[1:]...), partial)
+	require.ErrorIs(t, err, ErrInvalidCellIndex)
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/erigontech/erigon-lib/log/v3"
 )
@@ -44,6 +45,71 @@ type BlockExecutionStatistics struct {
 	TimeElapsed float64 `json:"timeElapsed"`
 }
 
+// BlockExecutionProfile - per-block profile of stage Execution, recorded when `--sync.exec-profile` is set
+type BlockExecutionProfile struct {
+	BlockNumber    uint64               `json:"blockNumber"`
+	BlockHash      string               `json:"blockHash"`
+	Time           time.Time            `json:"time"`
+	Txs            int                  `json:"txs"`
+	GasUsed        uint64               `json:"gasUsed"`
+	TotalTime      float64              `json:"totalTime"`      // seconds: block read + execution + commitment
+	ExecutionTime  float64              `json:"executionTime"`  // seconds: txs execution and applying their state changes
+	CommitmentTime float64              `json:"commitmentTime"` // seconds: zero if commitment was not computed for this block
+	MgasPerSec     float64              `json:"mgasPerSec"`
+	Reads          []DomainReadsProfile `json:"reads"`
+	TopTxs         []TxExecutionProfile `json:"topTxs"` // most expensive txs by execution time
+	Slow           bool                 `json:"slow"`   // TotalTime exceeded `--sync.slow-block-threshold`
+}
+
+type DomainReadsProfile struct {
+	Domain   string  `json:"domain"`
+	Reads    uint64  `json:"reads"`
+	HitRatio float64 `json:"hitRatio"` // part of reads served by not-flushed data in RAM
+}
+
+type TxExecutionProfile struct {
+	TxIndex int     `json:"txIndex"` // -1 - block initialization, len(txs) - block finalization
+	TxHash  string  `json:"txHash,omitempty"`
+	GasUsed uint64  `json:"gasUsed"`
+	Time    float64 `json:"time"` // seconds
+}
+
+func (p BlockExecutionProfile) Type() Type {
+	return TypeOf(p)
+}
+
+const blockExecutionProfilesLimit = 256
+
+// BlockExecutionProfiles - ring buffer of last profiles, filled by DiagnosticClient
+type BlockExecutionProfiles struct {
+	mu    sync.Mutex
+	ring  [blockExecutionProfilesLimit]BlockExecutionProfile
+	next  int
+	count int
+}
+
+func (b *BlockExecutionProfiles) Add(p BlockExecutionProfile) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ring[b.next] = p
+	b.next = (b.next + 1) % len(b.ring)
+	b.count = min(b.count+1, len(b.ring))
+}
+
+// Last - up to `limit` (0 - all available) last profiles, newest first
+func (b *BlockExecutionProfiles) Last(limit int) []BlockExecutionProfile {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if limit <= 0 || limit > b.count {
+		limit = b.count
+	}
+	res := make([]BlockExecutionProfile, 0, limit)
+	for i := 1; i <= limit; i++ {
+		res = append(res, b.ring[(b.next-i+len(b.ring))%len(b.ring)])
+	}
+	return res
+}
+
 func (b *BlockEexcStatsData) SetData(d BlockExecutionStatistics) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -59,6 +125,7 @@ func (b *BlockEexcStatsData) Data() (d BlockExecutionStatistics) {
 
 func (d *DiagnosticClient) setupBlockExecutionDiagnostics(rootCtx context.Context) {
 	d.runBlockExecutionListener(rootCtx)
+	d.runBlockExecutionProfilesListener(rootCtx)
 }
 
 func (d *DiagnosticClient) runBlockExecutionListener(rootCtx context.Context) {
@@ -81,8 +148,37 @@ func (d *DiagnosticClient) runBlockExecutionListener(rootCtx context.Context) {
 	}()
 }
 
+// runBlockExecutionProfilesListener - unlike statistics, profiles are useful at chain tip: listen until shutdown
+func (d *DiagnosticClient) runBlockExecutionProfilesListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[BlockExecutionProfile](rootCtx, 64)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(BlockExecutionProfile{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				d.blockExecutionProfiles.Add(info)
+			}
+		}
+	}()
+}
+
+// BlockExecutionProfiles - up to `limit` (0 - all available) last profiles received by this client, newest first
+func (d *DiagnosticClient) BlockExecutionProfiles(limit int) []BlockExecutionProfile {
+	return d.blockExecutionProfiles.Last(limit)
+}
+
 func (d *DiagnosticClient) BlockExecutionInfoJson(w io.Writer) {
 	if err := json.NewEncoder(w).Encode(d.BlockExecution.Data()); err != nil {
 		log.Debug("[diagnostics] BlockExecutionInfoJson", "err", err)
 	}
 }
+
+func (d *DiagnosticClient) BlockExecutionProfilesJson(w io.Writer) {
+	if err := json.NewEncoder(w).Encode(d.BlockExecutionProfiles(0)); err != nil {
+		log.Debug("[diagnostics] BlockExecutionProfilesJson", "err", err)
+	}
+}
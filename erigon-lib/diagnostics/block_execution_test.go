@@ -0,0 +1,51 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package diagnostics_test
+
+import (
+	"testing"
+
+	"github.com/erigontech/erigon-lib/diagnostics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockExecutionProfiles(t *testing.T) {
+	blockNums := func(profiles []diagnostics.BlockExecutionProfile) (res []uint64) {
+		for _, p := range profiles {
+			res = append(res, p.BlockNumber)
+		}
+		return res
+	}
+
+	var profiles diagnostics.BlockExecutionProfiles
+	require.Empty(t, profiles.Last(10))
+
+	for i := uint64(1); i <= 3; i++ {
+		profiles.Add(diagnostics.BlockExecutionProfile{BlockNumber: i})
+	}
+	require.Equal(t, []uint64{3, 2, 1}, blockNums(profiles.Last(0)))
+	require.Equal(t, []uint64{3, 2}, blockNums(profiles.Last(2)))
+
+	// ring buffer: keeps only last profiles
+	for i := uint64(4); i <= 1000; i++ {
+		profiles.Add(diagnostics.BlockExecutionProfile{BlockNumber: i})
+	}
+	require.Equal(t, []uint64{1000, 999, 998}, blockNums(profiles.Last(3)))
+	all := profiles.Last(0)
+	require.Equal(t, uint64(1000), all[0].BlockNumber)
+	require.Equal(t, uint64(1000-len(all)+1), all[len(all)-1].BlockNumber)
+}
@@ -55,6 +55,10 @@ type DiagnosticClient struct {
 	networkSpeed        NetworkSpeedTestResult
 	networkSpeedMutex   sync.Mutex
 	webseedsList        []string
+	gossip              GossipStatisticsUpdate
+	gossipMutex         sync.Mutex
+
+	blockExecutionProfiles BlockExecutionProfiles
 }
 
 func NewDiagnosticClient(ctx context.Context, metricsMux *http.ServeMux, dataDirPath string, speedTest bool, webseedsList []string) (*DiagnosticClient, error) {
@@ -119,6 +123,7 @@ func (d *DiagnosticClient) Setup() {
 	d.setupBodiesDiagnostics(rootCtx)
 	d.setupResourcesUsageDiagnostics(rootCtx)
 	d.setupSpeedtestDiagnostics(rootCtx)
+	d.setupGossipDiagnostics(rootCtx)
 	d.runSaveProcess(rootCtx)
 
 	//d.logDiagMsgs()
@@ -72,6 +72,49 @@ type PeerStatisticMsgUpdate struct {
 	Bytes    int
 }
 
+type GossipTopicScore struct {
+	TimeInMeshSeconds        float64 `json:"timeInMeshSeconds"`
+	FirstMessageDeliveries   float64 `json:"firstMessageDeliveries"`
+	MeshMessageDeliveries    float64 `json:"meshMessageDeliveries"`
+	InvalidMessageDeliveries float64 `json:"invalidMessageDeliveries"`
+}
+
+type GossipPeerScore struct {
+	PeerID             string                      `json:"peerId"`
+	Score              float64                     `json:"score"`
+	AppSpecificScore   float64                     `json:"appSpecificScore"`
+	IPColocationFactor float64                     `json:"ipColocationFactor"`
+	BehaviourPenalty   float64                     `json:"behaviourPenalty"`
+	Throttled          uint64                      `json:"throttled"`
+	Topics             map[string]GossipTopicScore `json:"topics"`
+}
+
+type GossipTopicStatistics struct {
+	Topic              string            `json:"topic"`
+	MeshPeers          int               `json:"meshPeers"`
+	Delivered          uint64            `json:"delivered"`
+	Duplicate          uint64            `json:"duplicate"`
+	Rejected           uint64            `json:"rejected"`
+	Undeliverable      uint64            `json:"undeliverable"`
+	RejectReasons      map[string]uint64 `json:"rejectReasons"`
+	DeliveredPerSecond float64           `json:"deliveredPerSecond"`
+	ReceivedPerSecond  float64           `json:"receivedPerSecond"`
+}
+
+type GossipValidationStatistics struct {
+	Service  string `json:"service"`
+	Accepted uint64 `json:"accepted"`
+	Ignored  uint64 `json:"ignored"`
+	Rejected uint64 `json:"rejected"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+type GossipStatisticsUpdate struct {
+	Peers      []GossipPeerScore            `json:"peers"`
+	Topics     []GossipTopicStatistics      `json:"topics"`
+	Validation []GossipValidationStatistics `json:"validation"`
+}
+
 type SyncStatistics struct {
 	SnapshotDownload SnapshotDownloadStatistics `json:"snapshotDownload"`
 	SnapshotIndexing SnapshotIndexingStatistics `json:"snapshotIndexing"`
@@ -331,6 +374,10 @@ func (ti PeerStatisticMsgUpdate) Type() Type {
 	return TypeOf(ti)
 }
 
+func (ti GossipStatisticsUpdate) Type() Type {
+	return TypeOf(ti)
+}
+
 func (ti HeadersWaitingUpdate) Type() Type {
 	return TypeOf(ti)
 }
@@ -0,0 +1,58 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+func (d *DiagnosticClient) setupGossipDiagnostics(rootCtx context.Context) {
+	d.runGossipStatisticsListener(rootCtx)
+}
+
+func (d *DiagnosticClient) GossipJson(w io.Writer) {
+	d.gossipMutex.Lock()
+	defer d.gossipMutex.Unlock()
+
+	if err := json.NewEncoder(w).Encode(d.gossip); err != nil {
+		log.Debug("[diagnostics] GossipJson", "err", err)
+	}
+}
+
+func (d *DiagnosticClient) runGossipStatisticsListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[GossipStatisticsUpdate](rootCtx, 1)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(GossipStatisticsUpdate{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				// every update is a full snapshot of the sentinel gossip
+				d.gossipMutex.Lock()
+				d.gossip = info
+				d.gossipMutex.Unlock()
+			}
+		}
+	}()
+}
@@ -0,0 +1,536 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: remote/chainevents.proto
+
+package remoteproto
+
+import (
+	typesproto "github.com/erigontech/erigon-lib/gointerfaces/typesproto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChainEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromBlockNumber uint64           `protobuf:"varint,1,opt,name=from_block_number,json=fromBlockNumber,proto3" json:"from_block_number,omitempty"` // cursor: last block processed by consumer. Zero hash - canonical block of given number
+	FromBlockHash   *typesproto.H256 `protobuf:"bytes,2,opt,name=from_block_hash,json=fromBlockHash,proto3" json:"from_block_hash,omitempty"`
+	WithReceipts    bool             `protobuf:"varint,3,opt,name=with_receipts,json=withReceipts,proto3" json:"with_receipts,omitempty"`      // consensus-encoded receipts of FORWARD events
+	WithStateDiff   bool             `protobuf:"varint,4,opt,name=with_state_diff,json=withStateDiff,proto3" json:"with_state_diff,omitempty"` // accounts, code and storage changed by block
+}
+
+func (x *ChainEventsRequest) Reset() {
+	*x = ChainEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_chainevents_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChainEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainEventsRequest) ProtoMessage() {}
+
+func (x *ChainEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_chainevents_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainEventsRequest.ProtoReflect.Descriptor instead.
+func (*ChainEventsRequest) Descriptor() ([]byte, []int) {
+	return file_remote_chainevents_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChainEventsRequest) GetFromBlockNumber() uint64 {
+	if x != nil {
+		return x.FromBlockNumber
+	}
+	return 0
+}
+
+func (x *ChainEventsRequest) GetFromBlockHash() *typesproto.H256 {
+	if x != nil {
+		return x.FromBlockHash
+	}
+	return nil
+}
+
+func (x *ChainEventsRequest) GetWithReceipts() bool {
+	if x != nil {
+		return x.WithReceipts
+	}
+	return false
+}
+
+func (x *ChainEventsRequest) GetWithStateDiff() bool {
+	if x != nil {
+		return x.WithStateDiff
+	}
+	return false
+}
+
+type ChainEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction   Direction        `protobuf:"varint,1,opt,name=direction,proto3,enum=remote.Direction" json:"direction,omitempty"` // FORWARD - block applied to canonical chain, UNWIND - block reverted
+	BlockNumber uint64           `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	BlockHash   *typesproto.H256 `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	ParentHash  *typesproto.H256 `protobuf:"bytes,4,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
+	Receipts    [][]byte         `protobuf:"bytes,5,rep,name=receipts,proto3" json:"receipts,omitempty"` // FORWARD only: consensus-encoded receipts, in txs order
+	Accounts    []*AccountDiff   `protobuf:"bytes,6,rep,name=accounts,proto3" json:"accounts,omitempty"` // state after this event: after block for FORWARD, before block for UNWIND
+	Storage     []*StorageDiff   `protobuf:"bytes,7,rep,name=storage,proto3" json:"storage,omitempty"`
+}
+
+func (x *ChainEvent) Reset() {
+	*x = ChainEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_chainevents_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChainEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainEvent) ProtoMessage() {}
+
+func (x *ChainEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_chainevents_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainEvent.ProtoReflect.Descriptor instead.
+func (*ChainEvent) Descriptor() ([]byte, []int) {
+	return file_remote_chainevents_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChainEvent) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_FORWARD
+}
+
+func (x *ChainEvent) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *ChainEvent) GetBlockHash() *typesproto.H256 {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *ChainEvent) GetParentHash() *typesproto.H256 {
+	if x != nil {
+		return x.ParentHash
+	}
+	return nil
+}
+
+func (x *ChainEvent) GetReceipts() [][]byte {
+	if x != nil {
+		return x.Receipts
+	}
+	return nil
+}
+
+func (x *ChainEvent) GetAccounts() []*AccountDiff {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *ChainEvent) GetStorage() []*StorageDiff {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+type AccountDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address  *typesproto.H160 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Deleted  bool             `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"` // account doesn't exist after event
+	Nonce    uint64           `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Balance  *typesproto.H256 `protobuf:"bytes,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	CodeHash *typesproto.H256 `protobuf:"bytes,5,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+	Code     []byte           `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"` // nil if code is not changed by event
+}
+
+func (x *AccountDiff) Reset() {
+	*x = AccountDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_chainevents_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccountDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountDiff) ProtoMessage() {}
+
+func (x *AccountDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_chainevents_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountDiff.ProtoReflect.Descriptor instead.
+func (*AccountDiff) Descriptor() ([]byte, []int) {
+	return file_remote_chainevents_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AccountDiff) GetAddress() *typesproto.H160 {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *AccountDiff) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *AccountDiff) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *AccountDiff) GetBalance() *typesproto.H256 {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *AccountDiff) GetCodeHash() *typesproto.H256 {
+	if x != nil {
+		return x.CodeHash
+	}
+	return nil
+}
+
+func (x *AccountDiff) GetCode() []byte {
+	if x != nil {
+		return x.Code
+	}
+	return nil
+}
+
+type StorageDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address  *typesproto.H160 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Location *typesproto.H256 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Value    []byte           `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"` // empty if slot deleted
+}
+
+func (x *StorageDiff) Reset() {
+	*x = StorageDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_chainevents_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorageDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageDiff) ProtoMessage() {}
+
+func (x *StorageDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_chainevents_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageDiff.ProtoReflect.Descriptor instead.
+func (*StorageDiff) Descriptor() ([]byte, []int) {
+	return file_remote_chainevents_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StorageDiff) GetAddress() *typesproto.H160 {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *StorageDiff) GetLocation() *typesproto.H256 {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *StorageDiff) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_remote_chainevents_proto protoreflect.FileDescriptor
+
+var file_remote_chainevents_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x11, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x6b, 0x76, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xc2, 0x01, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x72,
+	0x6f, 0x6d, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x66, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x0f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x32, 0x35, 0x36, 0x52, 0x0d, 0x66, 0x72,
+	0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x77,
+	0x69, 0x74, 0x68, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x64,
+	0x69, 0x66, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x77, 0x69, 0x74, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x22, 0xb6, 0x02, 0x0a, 0x0a, 0x43, 0x68, 0x61,
+	0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x0a, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x32, 0x35, 0x36, 0x52, 0x09, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2c, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x32, 0x35, 0x36, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74,
+	0x73, 0x12, 0x2f, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x44, 0x69, 0x66, 0x66, 0x52, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x22, 0xc9, 0x01, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x69, 0x66,
+	0x66, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x31, 0x36, 0x30, 0x52,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x2e, 0x48, 0x32, 0x35, 0x36, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x28, 0x0a, 0x09, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x32, 0x35, 0x36, 0x52,
+	0x08, 0x63, 0x6f, 0x64, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x73, 0x0a,
+	0x0b, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x69, 0x66, 0x66, 0x12, 0x25, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x31, 0x36, 0x30, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x48, 0x32,
+	0x35, 0x36, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x32, 0x84, 0x01, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x36, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x3d, 0x0a, 0x09, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1a, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x43, 0x68, 0x61,
+	0x69, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x16, 0x5a, 0x14, 0x2e, 0x2f, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x3b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_remote_chainevents_proto_rawDescOnce sync.Once
+	file_remote_chainevents_proto_rawDescData = file_remote_chainevents_proto_rawDesc
+)
+
+func file_remote_chainevents_proto_rawDescGZIP() []byte {
+	file_remote_chainevents_proto_rawDescOnce.Do(func() {
+		file_remote_chainevents_proto_rawDescData = protoimpl.X.CompressGZIP(file_remote_chainevents_proto_rawDescData)
+	})
+	return file_remote_chainevents_proto_rawDescData
+}
+
+var file_remote_chainevents_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_remote_chainevents_proto_goTypes = []any{
+	(*ChainEventsRequest)(nil),      // 0: remote.ChainEventsRequest
+	(*ChainEvent)(nil),              // 1: remote.ChainEvent
+	(*AccountDiff)(nil),             // 2: remote.AccountDiff
+	(*StorageDiff)(nil),             // 3: remote.StorageDiff
+	(*typesproto.H256)(nil),         // 4: types.H256
+	(Direction)(0),                  // 5: remote.Direction
+	(*typesproto.H160)(nil),         // 6: types.H160
+	(*emptypb.Empty)(nil),           // 7: google.protobuf.Empty
+	(*typesproto.VersionReply)(nil), // 8: types.VersionReply
+}
+var file_remote_chainevents_proto_depIdxs = []int32{
+	4,  // 0: remote.ChainEventsRequest.from_block_hash:type_name -> types.H256
+	5,  // 1: remote.ChainEvent.direction:type_name -> remote.Direction
+	4,  // 2: remote.ChainEvent.block_hash:type_name -> types.H256
+	4,  // 3: remote.ChainEvent.parent_hash:type_name -> types.H256
+	2,  // 4: remote.ChainEvent.accounts:type_name -> remote.AccountDiff
+	3,  // 5: remote.ChainEvent.storage:type_name -> remote.StorageDiff
+	6,  // 6: remote.AccountDiff.address:type_name -> types.H160
+	4,  // 7: remote.AccountDiff.balance:type_name -> types.H256
+	4,  // 8: remote.AccountDiff.code_hash:type_name -> types.H256
+	6,  // 9: remote.StorageDiff.address:type_name -> types.H160
+	4,  // 10: remote.StorageDiff.location:type_name -> types.H256
+	7,  // 11: remote.ChainEvents.Version:input_type -> google.protobuf.Empty
+	0,  // 12: remote.ChainEvents.Subscribe:input_type -> remote.ChainEventsRequest
+	8,  // 13: remote.ChainEvents.Version:output_type -> types.VersionReply
+	1,  // 14: remote.ChainEvents.Subscribe:output_type -> remote.ChainEvent
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_remote_chainevents_proto_init() }
+func file_remote_chainevents_proto_init() {
+	if File_remote_chainevents_proto != nil {
+		return
+	}
+	file_remote_kv_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_remote_chainevents_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ChainEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_chainevents_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ChainEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_chainevents_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*AccountDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_chainevents_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*StorageDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_remote_chainevents_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_remote_chainevents_proto_goTypes,
+		DependencyIndexes: file_remote_chainevents_proto_depIdxs,
+		MessageInfos:      file_remote_chainevents_proto_msgTypes,
+	}.Build()
+	File_remote_chainevents_proto = out.File
+	file_remote_chainevents_proto_rawDesc = nil
+	file_remote_chainevents_proto_goTypes = nil
+	file_remote_chainevents_proto_depIdxs = nil
+}
@@ -0,0 +1,190 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.1
+// source: remote/chainevents.proto
+
+package remoteproto
+
+import (
+	context "context"
+	typesproto "github.com/erigontech/erigon-lib/gointerfaces/typesproto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	ChainEvents_Version_FullMethodName   = "/remote.ChainEvents/Version"
+	ChainEvents_Subscribe_FullMethodName = "/remote.ChainEvents/Subscribe"
+)
+
+// ChainEventsClient is the client API for ChainEvents service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ChainEvents - durable, replayable feed of canonical chain changes.
+// Derived from blocks, receipts, ChangeSets3 and state history - so it survives restarts and reconnects.
+type ChainEventsClient interface {
+	// Version returns the service version number
+	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*typesproto.VersionReply, error)
+	// Subscribe - first sends UNWIND events for blocks of cursor's chain which are not canonical anymore (from the cursor down to the common ancestor),
+	// then FORWARD events for canonical blocks after the cursor, then FORWARD/UNWIND events as the chain changes.
+	Subscribe(ctx context.Context, in *ChainEventsRequest, opts ...grpc.CallOption) (ChainEvents_SubscribeClient, error)
+}
+
+type chainEventsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChainEventsClient(cc grpc.ClientConnInterface) ChainEventsClient {
+	return &chainEventsClient{cc}
+}
+
+func (c *chainEventsClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*typesproto.VersionReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(typesproto.VersionReply)
+	err := c.cc.Invoke(ctx, ChainEvents_Version_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainEventsClient) Subscribe(ctx context.Context, in *ChainEventsRequest, opts ...grpc.CallOption) (ChainEvents_SubscribeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChainEvents_ServiceDesc.Streams[0], ChainEvents_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chainEventsSubscribeClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChainEvents_SubscribeClient interface {
+	Recv() (*ChainEvent, error)
+	grpc.ClientStream
+}
+
+type chainEventsSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *chainEventsSubscribeClient) Recv() (*ChainEvent, error) {
+	m := new(ChainEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChainEventsServer is the server API for ChainEvents service.
+// All implementations must embed UnimplementedChainEventsServer
+// for forward compatibility
+//
+// ChainEvents - durable, replayable feed of canonical chain changes.
+// Derived from blocks, receipts, ChangeSets3 and state history - so it survives restarts and reconnects.
+type ChainEventsServer interface {
+	// Version returns the service version number
+	Version(context.Context, *emptypb.Empty) (*typesproto.VersionReply, error)
+	// Subscribe - first sends UNWIND events for blocks of cursor's chain which are not canonical anymore (from the cursor down to the common ancestor),
+	// then FORWARD events for canonical blocks after the cursor, then FORWARD/UNWIND events as the chain changes.
+	Subscribe(*ChainEventsRequest, ChainEvents_SubscribeServer) error
+	mustEmbedUnimplementedChainEventsServer()
+}
+
+// UnimplementedChainEventsServer must be embedded to have forward compatible implementations.
+type UnimplementedChainEventsServer struct {
+}
+
+func (UnimplementedChainEventsServer) Version(context.Context, *emptypb.Empty) (*typesproto.VersionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (UnimplementedChainEventsServer) Subscribe(*ChainEventsRequest, ChainEvents_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedChainEventsServer) mustEmbedUnimplementedChainEventsServer() {}
+
+// UnsafeChainEventsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChainEventsServer will
+// result in compilation errors.
+type UnsafeChainEventsServer interface {
+	mustEmbedUnimplementedChainEventsServer()
+}
+
+func RegisterChainEventsServer(s grpc.ServiceRegistrar, srv ChainEventsServer) {
+	s.RegisterService(&ChainEvents_ServiceDesc, srv)
+}
+
+func _ChainEvents_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainEventsServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChainEvents_Version_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainEventsServer).Version(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainEvents_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChainEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChainEventsServer).Subscribe(m, &chainEventsSubscribeServer{ServerStream: stream})
+}
+
+type ChainEvents_SubscribeServer interface {
+	Send(*ChainEvent) error
+	grpc.ServerStream
+}
+
+type chainEventsSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *chainEventsSubscribeServer) Send(m *ChainEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ChainEvents_ServiceDesc is the grpc.ServiceDesc for ChainEvents service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChainEvents_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "remote.ChainEvents",
+	HandlerType: (*ChainEventsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Version",
+			Handler:    _ChainEvents_Version_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _ChainEvents_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "remote/chainevents.proto",
+}
@@ -158,6 +158,8 @@ const (
 	HeimdallDB      Label = 6
 	DiagnosticsDB   Label = 7
 	PolygonBridgeDB Label = 8
+
+	HeimdallClientCacheDB Label = 9
 )
 
 func (l Label) String() string {
@@ -180,6 +182,8 @@ func (l Label) String() string {
 		return "diagnostics"
 	case PolygonBridgeDB:
 		return "polygon-bridge"
+	case HeimdallClientCacheDB:
+		return "heimdall-client-cache"
 	default:
 		return "unknown"
 	}
@@ -204,6 +208,8 @@ func UnmarshalLabel(s string) Label {
 		return DiagnosticsDB
 	case "polygon-bridge":
 		return PolygonBridgeDB
+	case "heimdall-client-cache":
+		return HeimdallClientCacheDB
 	default:
 		panic(fmt.Sprintf("unexpected label: %s", s))
 	}
@@ -18,6 +18,7 @@ package kv
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -343,6 +344,7 @@ const (
 	BorCheckpoints          = "BorCheckpoints"            // checkpoint_id -> checkpoint (in JSON encoding)
 	BorCheckpointEnds       = "BorCheckpointEnds"         // start block_num -> checkpoint_id (first block of checkpoint)
 	BorProducerSelections   = "BorProducerSelections"     // span_id -> span selection with accumulated proposer priorities (in JSON encoding)
+	HeimdallEventsCache     = "HeimdallEventsCache"       // event_id -> event record with time (in JSON encoding), heimdall client cache
 
 	// Downloader
 	BittorrentCompletion = "BittorrentCompletion"
@@ -458,6 +460,10 @@ const (
 	HistoricalRoots      = "HistoricalRoots"
 	HistoricalSummaries  = "HistoricalSummaries"
 	Eth1DataVotes        = "Eth1DataVotes"
+	// Electra pending queues, dumped only at the slots where they change
+	PendingDeposits           = "PendingDeposits"
+	PendingPartialWithdrawals = "PendingPartialWithdrawals"
+	PendingConsolidations     = "PendingConsolidations"
 
 	IntraRandaoMixes = "IntraRandaoMixes" // [validator_index+slot] => [randao_mix]
 	RandaoMixes      = "RandaoMixes"      // [validator_index+slot] => [randao_mix]
@@ -465,6 +471,16 @@ const (
 
 	StatesProcessingProgress = "StatesProcessingProgress"
 
+	// Validator client slashing protection (EIP-3076)
+	SlashingProtectionBlocks       = "SlashingProtectionBlocks"       // [pubkey] => [slot + signing root]
+	SlashingProtectionAttestations = "SlashingProtectionAttestations" // [pubkey] => [source epoch + target epoch + signing root]
+
+	// Validator monitor
+	ValidatorMonitorReports = "ValidatorMonitorReports" // [validator_index+epoch] => [report]
+
+	// Light client
+	LightClientUpdates = "LightClientUpdates" // [sync_committee_period] => [version+best_light_client_update]
+
 	//Diagnostics tables
 	DiagSystemInfo = "DiagSystemInfo"
 	DiagSyncStages = "DiagSyncStages"
@@ -630,6 +646,15 @@ var ChaindataTables = []string{
 	ActiveValidatorIndicies,
 	EffectiveBalancesDump,
 	BalancesDump,
+	PendingDeposits,
+	PendingPartialWithdrawals,
+	PendingConsolidations,
+	// Validator client
+	SlashingProtectionBlocks,
+	SlashingProtectionAttestations,
+	// Validator monitor
+	ValidatorMonitorReports,
+	LightClientUpdates,
 }
 
 const (
@@ -786,6 +811,32 @@ func TablesCfgByLabel(label Label) TableCfg {
 		panic(fmt.Sprintf("unexpected label: %s", label))
 	}
 }
+
+// RegisterChaindataTables - allows apps built on top of Erigon (see cmd/erigoncustom) to add own tables.
+// Must be called before chaindata open: tables are created at db open.
+func RegisterChaindataTables(cfg TableCfg) {
+	for name, item := range cfg {
+		if _, ok := ChaindataTablesCfg[name]; ok {
+			panic(fmt.Sprintf("table already registered: %s", name))
+		}
+		ChaindataTables = append(ChaindataTables, name)
+		ChaindataTablesCfg[name] = item
+	}
+	reinit()
+}
+
+// UnregisterChaindataTables - reverts RegisterChaindataTables (for tests): tables are not dropped from already open dbs.
+func UnregisterChaindataTables(cfg TableCfg) {
+	ChaindataTables = slices.DeleteFunc(ChaindataTables, func(name string) bool {
+		_, ok := cfg[name]
+		return ok
+	})
+	for name := range cfg {
+		delete(ChaindataTablesCfg, name)
+	}
+	reinit()
+}
+
 func sortBuckets() {
 	sort.SliceStable(ChaindataTables, func(i, j int) bool {
 		return strings.Compare(ChaindataTables[i], ChaindataTables[j]) < 0
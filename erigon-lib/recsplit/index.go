@@ -376,6 +376,41 @@ func (idx *Index) Has(bucketHash, i uint64) bool {
 	return true
 }
 
+func (idx *Index) BucketCount() uint64 { return idx.bucketCount }
+func (idx *Index) BucketSize() int      { return idx.bucketSize }
+func (idx *Index) LeafSize() uint16     { return idx.leafSize }
+
+// Depths - histogram: worst-case amount of golomb-rice reads (levels of split tree) done by Lookup -> amount of buckets with such depth.
+// Useful to diagnose slow lookups in files with skewed bucket sizes.
+func (idx *Index) Depths() map[int]int {
+	depths := map[int]int{}
+	if idx.keyCount <= 1 {
+		return depths
+	}
+	for bucket := uint64(0); bucket < idx.bucketCount; bucket++ {
+		cumKeys, cumKeysNext, _ := idx.ef.Get3(bucket)
+		depths[idx.bucketDepth(uint16(cumKeysNext-cumKeys))]++
+	}
+	return depths
+}
+
+// bucketDepth - follows the biggest branch of split tree for bucket of `m` keys. Mirrors the loop in Lookup
+func (idx *Index) bucketDepth(m uint16) (level int) {
+	for m > idx.secondaryAggrBound { // fanout = 2
+		split := (((m+1)/2 + idx.secondaryAggrBound - 1) / idx.secondaryAggrBound) * idx.secondaryAggrBound
+		m = max(split, m-split)
+		level++
+	}
+	if m > idx.primaryAggrBound {
+		m = idx.primaryAggrBound
+		level++
+	}
+	if m > idx.leafSize {
+		level++
+	}
+	return level + 1 // leaf bijection
+}
+
 func (idx *Index) ExtractOffsets() map[uint64]uint64 {
 	m := map[uint64]uint64{}
 	pos := 1 + 8 + idx.bytesPerRec
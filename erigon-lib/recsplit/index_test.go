@@ -100,3 +100,37 @@ func TestForwardCompatibility(t *testing.T) {
 		assert.ErrorIs(t, err, IncompatibleErr)
 	})
 }
+
+func TestIndexDepths(t *testing.T) {
+	logger := log.New()
+	tmpDir := t.TempDir()
+	indexFile := filepath.Join(tmpDir, "index")
+	salt := uint32(1)
+	rs, err := NewRecSplit(RecSplitArgs{
+		KeyCount:   1000,
+		BucketSize: 100,
+		Salt:       &salt,
+		TmpDir:     tmpDir,
+		IndexFile:  indexFile,
+		LeafSize:   8,
+	}, logger)
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, rs.AddKey([]byte(fmt.Sprintf("key %d", i)), uint64(i*17)))
+	}
+	require.NoError(t, rs.Build(context.Background()))
+	idx := MustOpen(indexFile)
+	defer idx.Close()
+
+	require.Equal(t, 100, idx.BucketSize())
+	require.Equal(t, uint16(8), idx.LeafSize())
+
+	var buckets int
+	for depth, n := range idx.Depths() {
+		require.Positive(t, depth)
+		buckets += n
+	}
+	require.Equal(t, int(idx.BucketCount()), buckets)
+	require.Equal(t, 1, idx.bucketDepth(idx.leafSize))
+	require.Equal(t, 2, idx.bucketDepth(idx.leafSize+1))
+}
@@ -133,8 +133,12 @@ type Decompressor struct {
 	wordsCount      uint64
 	emptyWordsCount uint64
 
-	serializedDictSize uint64
-	dictWords          int
+	serializedDictSize    uint64
+	dictWords             int
+	patternMaxDepth       uint64
+	serializedPosDictSize uint64
+	posDictWords          int
+	posMaxDepth           uint64
 
 	filePath, FileName1 string
 
@@ -263,6 +267,7 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 		dictPos += l
 	}
 	d.dictWords = len(patterns)
+	d.patternMaxDepth = patternMaxDepth
 
 	if dictSize > 0 {
 		var bitLen int
@@ -284,6 +289,7 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 	pos += dictSize // offset patterns
 	// read positions
 	dictSize = binary.BigEndian.Uint64(d.data[pos : pos+8])
+	d.serializedPosDictSize = dictSize
 	pos += 8
 
 	if pos+dictSize > uint64(d.size) {
@@ -314,6 +320,8 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 		dictPos += uint64(n)
 		poss = append(poss, dp)
 	}
+	d.posDictWords = len(poss)
+	d.posMaxDepth = posMaxDepth
 
 	if dictSize > 0 {
 		var bitLen int
@@ -443,6 +451,26 @@ func (d *Decompressor) DataHandle() unsafe.Pointer {
 func (d *Decompressor) SerializedDictSize() uint64 { return d.serializedDictSize }
 func (d *Decompressor) DictWords() int             { return d.dictWords }
 
+// DictStats - shape of huffman-coded dictionaries of file. Useful to diagnose bad compression ratio.
+type DictStats struct {
+	Patterns, Positions                 int    // amount of entries in dictionaries
+	PatternsSize, PositionsSize         uint64 // serialized size of dictionaries
+	PatternsMaxDepth, PositionsMaxDepth uint64 // max huffman code length
+	WordsSize                           uint64 // size of encoded words (without dictionaries)
+}
+
+func (d *Decompressor) DictStats() DictStats {
+	return DictStats{
+		Patterns:          d.dictWords,
+		Positions:         d.posDictWords,
+		PatternsSize:      d.serializedDictSize,
+		PositionsSize:     d.serializedPosDictSize,
+		PatternsMaxDepth:  d.patternMaxDepth,
+		PositionsMaxDepth: d.posMaxDepth,
+		WordsSize:         uint64(d.size) - d.wordsStart,
+	}
+}
+
 func (d *Decompressor) Size() int64 {
 	return d.size
 }
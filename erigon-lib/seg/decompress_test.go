@@ -293,6 +293,18 @@ func TestUncompressed(t *testing.T) {
 	}
 }
 
+func TestDecompressDictStats(t *testing.T) {
+	d := prepareLoremDict(t)
+	defer d.Close()
+	st := d.DictStats()
+	require.Equal(t, d.DictWords(), st.Patterns)
+	require.Equal(t, d.SerializedDictSize(), st.PatternsSize)
+	require.Positive(t, st.Positions)
+	require.Positive(t, st.PositionsMaxDepth)
+	require.Less(t, st.WordsSize, uint64(d.Size()))
+	require.Equal(t, uint64(d.Size()), 24+st.PatternsSize+8+st.PositionsSize+st.WordsSize)
+}
+
 func TestDecompressor_OpenCorrupted(t *testing.T) {
 	t.Helper()
 	logger := log.New()
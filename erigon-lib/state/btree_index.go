@@ -1089,5 +1089,15 @@ func (b *BtIndex) OrdinalLookup(getter *seg.Reader, i uint64) *Cursor {
 	}
 	return b.newCursor(context.Background(), k, v, i, getter)
 }
+
+// Depth - returns amount of nodes cached in memory and max amount of binary-search reads from data file done by lookup.
+// Lookup does binary search over cached nodes first, then over at most M keys in data file.
+func (b *BtIndex) Depth() (cachedNodes int, dataReads uint64) {
+	if b.Empty() || b.bplus == nil {
+		return 0, 0
+	}
+	return len(b.bplus.mx), logBase(b.bplus.M, 2)
+}
+
 func (b *BtIndex) Offsets() *eliasfano32.EliasFano { return b.bplus.Offsets() }
 func (b *BtIndex) Distances() (map[int]int, error) { return b.bplus.Distances() }
@@ -70,6 +70,8 @@ func Test_BtreeIndex_Init(t *testing.T) {
 	bt, err := OpenBtreeIndexWithDecompressor(filepath.Join(tmp, "a.bt"), M, decomp, seg.CompressKeys|seg.CompressVals)
 	require.NoError(t, err)
 	require.EqualValues(t, bt.KeyCount(), keyCount)
+	_, dataReads := bt.Depth()
+	require.EqualValues(t, 2, dataReads)
 	bt.Close()
 }
 
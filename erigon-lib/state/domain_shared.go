@@ -26,6 +26,7 @@ import (
 	"math"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -91,7 +92,10 @@ type SharedDomains struct {
 	blockNum atomic.Uint64
 	estSize  int
 	trace    bool //nolint
-	//muMaps   sync.RWMutex
+	// muMaps guards domains, storage and estSize: parallel exec workers read them while applyLoop writes.
+	// Taken only if `parallel` is set - serial execution doesn't pay for it.
+	muMaps   sync.RWMutex
+	parallel bool
 	//walLock sync.RWMutex
 
 	domains [kv.DomainLen]map[string]dataWithPrevStep
@@ -102,6 +106,10 @@ type SharedDomains struct {
 
 	currentChangesAccumulator *StateChangeSet
 	pastChangesAccumulator    map[string]*StateChangeSet
+
+	// reads, readsFromRAM - counted only if `readStats` is set, see ReadStats
+	readStats           bool
+	reads, readsFromRAM [kv.DomainLen]atomic.Uint64
 }
 
 type HasAggTx interface {
@@ -140,6 +148,10 @@ func NewSharedDomains(tx kv.Tx, logger log.Logger) (*SharedDomains, error) {
 	return sd, nil
 }
 
+// SetParallel - enables locking of in-RAM state, required when it's read by parallel exec workers.
+// Must be called when no other goroutine uses `sd`.
+func (sd *SharedDomains) SetParallel(parallel bool) { sd.parallel = parallel }
+
 func (sd *SharedDomains) SetChangesetAccumulator(acc *StateChangeSet) {
 	sd.currentChangesAccumulator = acc
 	for idx := range sd.domainWriters {
@@ -371,8 +383,10 @@ func (sd *SharedDomains) SeekCommitment(ctx context.Context, tx kv.Tx) (txsFromB
 }
 
 func (sd *SharedDomains) ClearRam(resetCommitment bool) {
-	//sd.muMaps.Lock()
-	//defer sd.muMaps.Unlock()
+	if sd.parallel {
+		sd.muMaps.Lock()
+		defer sd.muMaps.Unlock()
+	}
 	for i := range sd.domains {
 		sd.domains[i] = map[string]dataWithPrevStep{}
 	}
@@ -386,8 +400,10 @@ func (sd *SharedDomains) ClearRam(resetCommitment bool) {
 }
 
 func (sd *SharedDomains) put(domain kv.Domain, key string, val []byte) {
-	// disable mutex - because work on parallel execution postponed after E3 release.
-	//sd.muMaps.Lock()
+	if sd.parallel {
+		sd.muMaps.Lock()
+		defer sd.muMaps.Unlock()
+	}
 	valWithPrevStep := dataWithPrevStep{data: val, prevStep: sd.txNum / sd.aggTx.a.StepSize()}
 	if domain == kv.StorageDomain {
 		if old, ok := sd.storage.Set(key, valWithPrevStep); ok {
@@ -404,12 +420,14 @@ func (sd *SharedDomains) put(domain kv.Domain, key string, val []byte) {
 		sd.estSize += len(key) + len(val)
 	}
 	sd.domains[domain][key] = valWithPrevStep
-	//sd.muMaps.Unlock()
 }
 
 // get returns cached value by key. Cache is invalidated when associated WAL is flushed
 func (sd *SharedDomains) get(table kv.Domain, key []byte) (v []byte, prevStep uint64, ok bool) {
-	//sd.muMaps.RLock()
+	if sd.parallel {
+		sd.muMaps.RLock()
+		defer sd.muMaps.RUnlock()
+	}
 	keyS := *(*string)(unsafe.Pointer(&key))
 	var dataWithPrevStep dataWithPrevStep
 	//keyS := string(key)
@@ -420,20 +438,54 @@ func (sd *SharedDomains) get(table kv.Domain, key []byte) (v []byte, prevStep ui
 	}
 	dataWithPrevStep, ok = sd.domains[table][keyS]
 	return dataWithPrevStep.data, dataWithPrevStep.prevStep, ok
-	//sd.muMaps.RUnlock()
 }
 
 func (sd *SharedDomains) SizeEstimate() uint64 {
-	//sd.muMaps.RLock()
-	//defer sd.muMaps.RUnlock()
+	if sd.parallel {
+		sd.muMaps.RLock()
+		defer sd.muMaps.RUnlock()
+	}
 
 	// multiply 2: to cover data-structures overhead (and keep accounting cheap)
 	// and muliply 2 more: for Commitment calculation when batch is full
 	return uint64(sd.estSize) * 4
 }
 
+// DomainReadStats - amount of latest-state reads per domain, and how many of them were served by not-flushed data in RAM
+// (without DB and files access)
+type DomainReadStats struct {
+	Reads, ReadsFromRAM [kv.DomainLen]uint64
+}
+
+// Sub - reads happened between `prev` and `s`
+func (s DomainReadStats) Sub(prev DomainReadStats) (res DomainReadStats) {
+	for i := range s.Reads {
+		res.Reads[i] = s.Reads[i] - prev.Reads[i]
+		res.ReadsFromRAM[i] = s.ReadsFromRAM[i] - prev.ReadsFromRAM[i]
+	}
+	return res
+}
+
+// SetReadStats - enables counting of reads for ReadStats. Disabled by default: it's not free for the hot read path.
+func (sd *SharedDomains) SetReadStats(enabled bool) { sd.readStats = enabled }
+
+// ReadStats - counters of DomainGet/LatestCommitment calls since SetReadStats(true)
+func (sd *SharedDomains) ReadStats() (s DomainReadStats) {
+	for i := range s.Reads {
+		s.Reads[i] = sd.reads[i].Load()
+		s.ReadsFromRAM[i] = sd.readsFromRAM[i].Load()
+	}
+	return s
+}
+
 func (sd *SharedDomains) LatestCommitment(prefix []byte) ([]byte, uint64, error) {
+	if sd.readStats {
+		sd.reads[kv.CommitmentDomain].Add(1)
+	}
 	if v, prevStep, ok := sd.get(kv.CommitmentDomain, prefix); ok {
+		if sd.readStats {
+			sd.readsFromRAM[kv.CommitmentDomain].Add(1)
+		}
 		// sd cache values as is (without transformation) so safe to return
 		return v, prevStep, nil
 	}
@@ -553,8 +605,10 @@ func (sd *SharedDomains) replaceShortenedKeysInBranch(prefix []byte, branch comm
 const CodeSizeTableFake = "CodeSize"
 
 func (sd *SharedDomains) ReadsValid(readLists map[string]*KvList) bool {
-	//sd.muMaps.RLock()
-	//defer sd.muMaps.RUnlock()
+	if sd.parallel {
+		sd.muMaps.RLock()
+		defer sd.muMaps.RUnlock()
+	}
 
 	for table, list := range readLists {
 		switch table {
@@ -962,7 +1016,13 @@ func (sd *SharedDomains) DomainGet(domain kv.Domain, k, k2 []byte) (v []byte, st
 	if k2 != nil {
 		k = append(k, k2...)
 	}
+	if sd.readStats {
+		sd.reads[domain].Add(1)
+	}
 	if v, prevStep, ok := sd.get(domain, k); ok {
+		if sd.readStats {
+			sd.readsFromRAM[domain].Add(1)
+		}
 		return v, prevStep, nil
 	}
 	v, step, _, err = sd.aggTx.GetLatest(domain, k, nil, sd.roTx)
@@ -972,6 +1032,35 @@ func (sd *SharedDomains) DomainGet(domain kv.Domain, k, k2 []byte) (v []byte, st
 	return v, step, nil
 }
 
+// DomainGetWithTx - like DomainGet, but falls back to given `roTx` (and it's own files view) instead of `sd.roTx`.
+// Allows reading of latest state from multiple goroutines: each of them must have own roTx.
+func (sd *SharedDomains) DomainGetWithTx(domain kv.Domain, k, k2 []byte, roTx kv.Tx) (v []byte, step uint64, err error) {
+	if domain == kv.CommitmentDomain {
+		panic("DomainGetWithTx: commitment domain is not supported")
+	}
+	if k2 != nil {
+		k = append(k, k2...)
+	}
+	if sd.readStats {
+		sd.reads[domain].Add(1)
+	}
+	if v, prevStep, ok := sd.get(domain, k); ok {
+		if sd.readStats {
+			sd.readsFromRAM[domain].Add(1)
+		}
+		return v, prevStep, nil
+	}
+	casted, ok := roTx.(HasAggTx)
+	if !ok {
+		return nil, 0, fmt.Errorf("type %T need AggTx method", roTx)
+	}
+	v, step, _, err = casted.AggTx().(*AggregatorRoTx).GetLatest(domain, k, nil, roTx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("domain '%s' %x read error: %w", domain, k, err)
+	}
+	return v, step, nil
+}
+
 // DomainGetAsOfFile returns value from domain with respect to limit ofMaxTxnum
 func (sd *SharedDomains) domainGetAsOfFile(domain kv.Domain, k, k2 []byte, ofMaxTxnum uint64) (v []byte, step uint64, err error) {
 	if domain == kv.CommitmentDomain {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/rawdbv3"
@@ -522,3 +523,122 @@ func TestSharedDomain_StorageIter(t *testing.T) {
 	domains.Close()
 	ac.Close()
 }
+
+func TestSharedDomain_DomainGetWithTx(t *testing.T) {
+	t.Parallel()
+
+	stepSize := uint64(10)
+	db, agg := testDbAndAggregatorv3(t, stepSize)
+
+	ctx := context.Background()
+	rwTx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+
+	ac := agg.BeginFilesRo()
+	defer ac.Close()
+
+	domains, err := NewSharedDomains(WrapTxWithCtx(rwTx, ac), log.New())
+	require.NoError(t, err)
+	defer domains.Close()
+
+	addrFlushed, addrRam := make([]byte, length.Addr), make([]byte, length.Addr)
+	addrFlushed[0], addrRam[0] = 1, 2
+	acc := func(i uint64) []byte {
+		return types.EncodeAccountBytesV3(i, uint256.NewInt(i*1e6), nil, 0)
+	}
+
+	domains.SetTxNum(1)
+	require.NoError(t, domains.DomainPut(kv.AccountsDomain, addrFlushed, nil, acc(1), nil, 0))
+	require.NoError(t, domains.Flush(ctx, rwTx))
+	domains.ClearRam(true)
+	require.NoError(t, rwTx.Commit())
+	domains.Close()
+	ac.Close()
+
+	rwTx, err = db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+
+	ac = agg.BeginFilesRo()
+	defer ac.Close()
+
+	domains, err = NewSharedDomains(WrapTxWithCtx(rwTx, ac), log.New())
+	require.NoError(t, err)
+	defer domains.Close()
+	domains.SetParallel(true)
+
+	domains.SetTxNum(2)
+	require.NoError(t, domains.DomainPut(kv.AccountsDomain, addrRam, nil, acc(2), nil, 0))
+
+	// parallel exec workers read from own goroutine with own roTx: mdbx doesn't allow read and write txs on same thread
+	var fromDb, fromRam []byte
+	g := errgroup.Group{}
+	g.Go(func() error {
+		roTx, err := db.BeginRo(ctx)
+		if err != nil {
+			return err
+		}
+		defer roTx.Rollback()
+		roAc := agg.BeginFilesRo()
+		defer roAc.Close()
+		wrapped := WrapTxWithCtx(roTx, roAc)
+
+		// not in RAM - must be read through given tx
+		if fromDb, _, err = domains.DomainGetWithTx(kv.AccountsDomain, addrFlushed, nil, wrapped); err != nil {
+			return err
+		}
+		// not yet flushed - must be served from RAM
+		fromRam, _, err = domains.DomainGetWithTx(kv.AccountsDomain, addrRam, nil, wrapped)
+		return err
+	})
+	require.NoError(t, g.Wait())
+	require.Equal(t, acc(1), fromDb)
+	require.Equal(t, acc(2), fromRam)
+}
+
+func TestSharedDomain_ReadStats(t *testing.T) {
+	t.Parallel()
+
+	stepSize := uint64(10)
+	db, agg := testDbAndAggregatorv3(t, stepSize)
+
+	ctx := context.Background()
+	rwTx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+
+	ac := agg.BeginFilesRo()
+	defer ac.Close()
+
+	domains, err := NewSharedDomains(WrapTxWithCtx(rwTx, ac), log.New())
+	require.NoError(t, err)
+	defer domains.Close()
+
+	addrFlushed, addrRam := make([]byte, length.Addr), make([]byte, length.Addr)
+	addrFlushed[0], addrRam[0] = 1, 2
+	acc := types.EncodeAccountBytesV3(1, uint256.NewInt(1e6), nil, 0)
+
+	domains.SetTxNum(1)
+	require.NoError(t, domains.DomainPut(kv.AccountsDomain, addrFlushed, nil, acc, nil, 0))
+	require.NoError(t, domains.Flush(ctx, rwTx))
+	domains.ClearRam(true)
+	require.NoError(t, domains.DomainPut(kv.AccountsDomain, addrRam, nil, acc, nil, 0))
+
+	// disabled by default
+	_, _, err = domains.DomainGet(kv.AccountsDomain, addrRam, nil)
+	require.NoError(t, err)
+	require.Zero(t, domains.ReadStats().Reads[kv.AccountsDomain])
+
+	domains.SetReadStats(true)
+	before := domains.ReadStats()
+	for _, addr := range [][]byte{addrFlushed, addrRam, addrRam} {
+		v, _, err := domains.DomainGet(kv.AccountsDomain, addr, nil)
+		require.NoError(t, err)
+		require.Equal(t, acc, v)
+	}
+	stats := domains.ReadStats().Sub(before)
+	require.Equal(t, uint64(3), stats.Reads[kv.AccountsDomain])
+	require.Equal(t, uint64(2), stats.ReadsFromRAM[kv.AccountsDomain])
+	require.Zero(t, stats.Reads[kv.StorageDomain])
+}
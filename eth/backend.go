@@ -122,12 +122,14 @@ import (
 	polygonsync "github.com/erigontech/erigon/polygon/sync"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/turbo/builder"
+	"github.com/erigontech/erigon/turbo/chainevents"
 	"github.com/erigontech/erigon/turbo/engineapi"
 	"github.com/erigontech/erigon/turbo/engineapi/engine_block_downloader"
 	"github.com/erigontech/erigon/turbo/engineapi/engine_helpers"
 	"github.com/erigontech/erigon/turbo/execution/eth1"
 	"github.com/erigontech/erigon/turbo/execution/eth1/eth1_chain_reader.go"
 	"github.com/erigontech/erigon/turbo/jsonrpc"
+	"github.com/erigontech/erigon/turbo/jsonrpc/receipts"
 	"github.com/erigontech/erigon/turbo/services"
 	"github.com/erigontech/erigon/turbo/shards"
 	"github.com/erigontech/erigon/turbo/silkworm"
@@ -219,6 +221,8 @@ type Ethereum struct {
 	polygonBridge      bridge.PolygonBridge
 	heimdallService    heimdall.Service
 	stopNode           func() error
+
+	diagnosticClient atomic.Pointer[diagnostics.DiagnosticClient] // set after node start, nil if diagnostics are disabled
 }
 
 func splitAddrIntoHostAndPort(addr string) (host string, port int, err error) {
@@ -318,6 +322,9 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 	}); err != nil {
 		panic(err)
 	}
+	if err := vm.CheckCustomPrecompiles(chainConfig); err != nil {
+		return nil, err
+	}
 	backend.chainConfig = chainConfig
 	backend.genesisBlock = genesis
 	backend.genesisHash = genesis.Hash()
@@ -543,29 +550,44 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 	if chainConfig.Bor != nil {
 		if !config.WithoutHeimdall {
-			heimdallClient = heimdall.NewHeimdallClient(config.HeimdallURL, logger)
+			heimdallClient = heimdall.NewHeimdallClientForURLs(heimdall.MultiClientConfig{
+				URLs:     libcommon.CliString2Array(config.HeimdallURL),
+				Quorum:   config.HeimdallQuorum,
+				CacheDir: dirs.DataDir,
+				Logger:   logger,
+			})
 		}
 
 		if config.PolygonSync {
 			borConfig := consensusConfig.(*borcfg.BorConfig)
 			roTxLimit := int64(stack.Config().Http.DBReadConcurrency)
 
+			polygonSyncHeimdallClient := heimdallClient
+			if config.PolygonSyncFromSnapshots {
+				snapshotReader, ok := blockReader.(heimdall.SnapshotReader)
+				if !ok {
+					return nil, errors.New("polygon sync from snapshots requires a block reader with bor snapshots")
+				}
+
+				polygonSyncHeimdallClient = heimdall.NewSnapshotClient(snapshotReader, heimdallClient, logger)
+			}
+
 			bridgeConfig := bridge.Config{
 				DataDir:      config.Dirs.DataDir,
 				Logger:       logger,
 				BorConfig:    borConfig,
-				EventFetcher: heimdallClient,
+				EventFetcher: polygonSyncHeimdallClient,
 				RoTxLimit:    roTxLimit,
 			}
 			polygonBridge = bridge.Assemble(bridgeConfig)
 
 			heimdallConfig := heimdall.ServiceConfig{
-				BorConfig:   borConfig,
-				HeimdallURL: config.HeimdallURL,
-				DataDir:     dirs.DataDir,
-				TempDir:     tmpdir,
-				Logger:      logger,
-				RoTxLimit:   roTxLimit,
+				BorConfig: borConfig,
+				Client:    polygonSyncHeimdallClient,
+				DataDir:   dirs.DataDir,
+				TempDir:   tmpdir,
+				Logger:    logger,
+				RoTxLimit: roTxLimit,
 			}
 			heimdallService = heimdall.AssembleService(heimdallConfig)
 
@@ -766,6 +788,10 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 	// Initialize ethbackend
 	ethBackendRPC := privateapi.NewEthBackendServer(ctx, backend, backend.chainDB, backend.notifications.Events, blockReader, logger, latestBlockBuiltStore)
+	receiptsGenerator := receipts.NewGenerator(32, blockReader, backend.engine)
+	chainEventsRPC := privateapi.NewChainEventsServer(ctx, backend.chainDB, backend.notifications.Events, chainevents.NewReader(blockReader, func(ctx context.Context, tx kv.TemporalTx, block *types.Block) (types.Receipts, error) {
+		return receiptsGenerator.GetReceipts(ctx, chainConfig, tx, block)
+	}), logger)
 	// initialize engine backend
 
 	blockSnapBuildSema := semaphore.NewWeighted(int64(dbg.BuildSnapshotAllowance))
@@ -790,6 +816,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			miningRPC,
 			bridgeRPC,
 			heimdallRPC,
+			chainEventsRPC,
 			stack.Config().PrivateApiAddr,
 			stack.Config().PrivateApiRateLimit,
 			creds,
@@ -1039,7 +1066,7 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 		}
 	}
 
-	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, &httpRpcCfg, s.engine, s.logger, s.polygonBridge, s.heimdallService)
+	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, &httpRpcCfg, s.engine, s.logger, s.polygonBridge, s.heimdallService, s)
 
 	if config.SilkwormRpcDaemon && httpRpcCfg.Enabled {
 		interface_log_settings := silkworm.RpcInterfaceLogSettings{
@@ -1519,7 +1546,13 @@ func (s *Ethereum) Start() error {
 		s.waitForStageLoopStop = nil // Shutdown is handled by context
 		go func() {
 			ctx := s.sentryCtx
-			err := s.polygonSyncService.Run(ctx)
+			var err error
+			if s.config.PolygonSyncVerifySnapshots {
+				err = s.verifyPolygonSnapshots(ctx)
+			}
+			if err == nil {
+				err = s.polygonSyncService.Run(ctx)
+			}
 			if err == nil || errors.Is(err, context.Canceled) {
 				return
 			}
@@ -1553,6 +1586,16 @@ func (s *Ethereum) Start() error {
 	return nil
 }
 
+// verifyPolygonSnapshots checks the Heimdall data embedded in bor snapshots against checkpoint roots
+func (s *Ethereum) verifyPolygonSnapshots(ctx context.Context) error {
+	reader, ok := s.blockReader.(polygonsync.SnapshotsVerifierReader)
+	if !ok {
+		return errors.New("polygon snapshots verification requires a block reader with bor snapshots")
+	}
+
+	return polygonsync.VerifySnapshots(ctx, reader, s.logger)
+}
+
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
@@ -1713,6 +1756,19 @@ func (s *Ethereum) DataDir() string {
 	return s.config.Dirs.DataDir
 }
 
+func (s *Ethereum) SetDiagnosticClient(d *diagnostics.DiagnosticClient) {
+	s.diagnosticClient.Store(d)
+}
+
+// BlockExecutionProfiles - profiles recorded by stage Execution with `--sync.exec-profile`, see debug_getBlockExecutionProfiles
+func (s *Ethereum) BlockExecutionProfiles(limit int) ([]diagnostics.BlockExecutionProfile, error) {
+	d := s.diagnosticClient.Load()
+	if d == nil {
+		return nil, errors.New("block execution profiles require diagnostics, which are disabled")
+	}
+	return d.BlockExecutionProfiles(limit), nil
+}
+
 // setBorDefaultMinerGasPrice enforces Miner.GasPrice to be equal to BorDefaultMinerGasPrice (25gwei by default)
 func setBorDefaultMinerGasPrice(chainConfig *chain.Config, config *ethconfig.Config, logger log.Logger) {
 	if chainConfig.Bor != nil && (config.Miner.GasPrice == nil || config.Miner.GasPrice.Cmp(ethconfig.BorDefaultMinerGasPrice) != 0) {
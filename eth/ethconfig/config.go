@@ -225,8 +225,10 @@ type Config struct {
 
 	StateStream bool
 
-	// URL to connect to Heimdall node
+	// URL to connect to Heimdall node, or comma separated URLs of several Heimdall nodes
 	HeimdallURL string
+	// Number of Heimdall nodes which need to agree on spans, checkpoints and milestones (0 = majority)
+	HeimdallQuorum int
 	// No heimdall service
 	WithoutHeimdall bool
 	// Heimdall services active
@@ -236,6 +238,10 @@ type Config struct {
 	// Use polygon checkpoint sync in preference to POW downloader
 	PolygonSync      bool
 	PolygonSyncStage bool
+	// Serve spans, checkpoints and state sync events from bor snapshots up to their tip, without calling Heimdall
+	PolygonSyncFromSnapshots bool
+	// Check the Heimdall data embedded in bor snapshots against checkpoint roots before syncing
+	PolygonSyncVerifySnapshots bool
 
 	// Ethstats service
 	Ethstats string
@@ -272,6 +278,9 @@ type Sync struct {
 	BreakAfterStage            string
 	LoopBlockLimit             uint
 	ParallelStateFlushing      bool
+	ParallelExecution          bool          // optimistic parallel execution of txs in ExecV3 (catch-up only: disabled near chain-tip)
+	ExecProfile                bool          // record per-block execution profile in ExecV3 (serial execution only)
+	SlowBlockThreshold         time.Duration // with ExecProfile: dump blocks which took longer. 0 - disabled
 
 	UploadLocation   string
 	UploadFrom       rpc.BlockNumber
@@ -48,11 +48,14 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCTxFeeCap                    float64 `toml:",omitempty"`
 		StateStream                    bool
 		HeimdallURL                    string
+		HeimdallQuorum                 int
 		WithoutHeimdall                bool
 		WithHeimdallMilestones         bool
 		WithHeimdallWaypointRecording  bool
 		PolygonSync                    bool
 		PolygonSyncStage               bool
+		PolygonSyncFromSnapshots       bool
+		PolygonSyncVerifySnapshots     bool
 		Ethstats                       string
 		InternalCL                     bool
 		CaplinDiscoveryAddr            string
@@ -101,11 +104,14 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.StateStream = c.StateStream
 	enc.HeimdallURL = c.HeimdallURL
+	enc.HeimdallQuorum = c.HeimdallQuorum
 	enc.WithoutHeimdall = c.WithoutHeimdall
 	enc.WithHeimdallMilestones = c.WithHeimdallMilestones
 	enc.WithHeimdallWaypointRecording = c.WithHeimdallWaypointRecording
 	enc.PolygonSync = c.PolygonSync
 	enc.PolygonSyncStage = c.PolygonSyncStage
+	enc.PolygonSyncFromSnapshots = c.PolygonSyncFromSnapshots
+	enc.PolygonSyncVerifySnapshots = c.PolygonSyncVerifySnapshots
 	enc.Ethstats = c.Ethstats
 	enc.InternalCL = c.InternalCL
 	enc.CaplinConfig.CaplinDiscoveryAddr = c.CaplinConfig.CaplinDiscoveryAddr
@@ -158,11 +164,14 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCTxFeeCap                    *float64 `toml:",omitempty"`
 		StateStream                    *bool
 		HeimdallURL                    *string
+		HeimdallQuorum                 *int
 		WithoutHeimdall                *bool
 		WithHeimdallMilestones         *bool
 		WithHeimdallWaypointRecording  *bool
 		PolygonSync                    *bool
 		PolygonSyncStage               *bool
+		PolygonSyncFromSnapshots       *bool
+		PolygonSyncVerifySnapshots     *bool
 		Ethstats                       *string
 		InternalCL                     *bool
 		CaplinDiscoveryAddr            *string
@@ -264,6 +273,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.HeimdallURL != nil {
 		c.HeimdallURL = *dec.HeimdallURL
 	}
+	if dec.HeimdallQuorum != nil {
+		c.HeimdallQuorum = *dec.HeimdallQuorum
+	}
 	if dec.WithoutHeimdall != nil {
 		c.WithoutHeimdall = *dec.WithoutHeimdall
 	}
@@ -279,6 +291,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.PolygonSyncStage != nil {
 		c.PolygonSyncStage = *dec.PolygonSyncStage
 	}
+	if dec.PolygonSyncFromSnapshots != nil {
+		c.PolygonSyncFromSnapshots = *dec.PolygonSyncFromSnapshots
+	}
+	if dec.PolygonSyncVerifySnapshots != nil {
+		c.PolygonSyncVerifySnapshots = *dec.PolygonSyncVerifySnapshots
+	}
 	if dec.Ethstats != nil {
 		c.Ethstats = *dec.Ethstats
 	}
@@ -0,0 +1,605 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/etl"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/wrap"
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/eth/stagedsync/stages"
+)
+
+// CustomIndexer - user-defined index, built by re-execution of history (same as CustomTrace stage).
+// Indexers are compiled into app (see cmd/erigoncustom) and registered by RegisterCustomIndexer.
+// Each indexer has own progress in kv.SyncStageProgress (see stages.CustomIndexer) - new indexer
+// can be added to existing node and will catch-up from genesis.
+type CustomIndexer interface {
+	// Name - unique name of indexer
+	Name() string
+	// Tables - own tables of indexer. Index can write only to them.
+	Tables() kv.TableCfg
+	// NewTracer - called for each txn. Can return nil
+	NewTracer() exec3.GenericTracer
+	// Index - called for each txn of each block in order: including block-init (txTask.TxIndex == -1) and block-end (txTask.Final) system txs.
+	//  - tracer: created by NewTracer for this txn
+	//  - txTask.BlockReceipts[txTask.TxIndex]: receipt of txn
+	//  - txTask.WriteLists: state changes of txn (by domain name)
+	//  - txTask.BalanceIncreaseSet: balance increases of accounts which txn didn't read (coinbase fee, rewards) - not in WriteLists
+	//  - tx: read-only view of db (without data of current batch of Index calls)
+	// Can be called from background goroutine.
+	Index(txTask *state.TxTask, tracer exec3.GenericTracer, tx kv.Tx, w CustomIndexWriter) error
+	// Unwind - must delete all data of blocks > unwindTo
+	Unwind(tx kv.RwTx, unwindTo uint64) error
+}
+
+// CustomDomainIndexer - indexer which also keeps own domains: key-value state with history, like domains of Erigon's state.
+// Stage keeps latest value and value as-of each block (see CustomDomainGet, CustomDomainGetAsOf) and unwinds domains itself:
+// Unwind of indexer must handle only it's Tables.
+// Each domain is stored in 3 tables: `<domain>` (key -> latest value), `<domain>History` (key + blockNum_u64 -> value before block)
+// and `<domain>ChangeSet` (blockNum_u64 + key -> value before block).
+type CustomDomainIndexer interface {
+	CustomIndexer
+	Domains() []string
+}
+
+// CustomIndexWriter - writes are buffered and loaded into tables at end of batch
+type CustomIndexWriter interface {
+	Put(table string, k, v []byte) error
+	// PutDomain - sets value of `k` in own domain since current block. Empty `v` deletes key.
+	PutDomain(domain string, k, v []byte) error
+	// GetDomain - latest value of `k` in own domain, including not loaded writes of current batch
+	GetDomain(domain string, k []byte) ([]byte, error)
+}
+
+var customIndexers []CustomIndexer
+
+// RegisterCustomIndexer - must be called before chaindata open (from `init()` or `main()`):
+// tables of indexer are created at db open.
+func RegisterCustomIndexer(ix CustomIndexer) {
+	for _, registered := range customIndexers {
+		if registered.Name() == ix.Name() {
+			panic(fmt.Sprintf("custom indexer already registered: %s", ix.Name()))
+		}
+	}
+	kv.RegisterChaindataTables(customIndexerTables(ix))
+	customIndexers = append(customIndexers, ix)
+}
+
+// UnregisterCustomIndexer - reverts RegisterCustomIndexer (for tests)
+func UnregisterCustomIndexer(name string) {
+	customIndexers = slices.DeleteFunc(customIndexers, func(ix CustomIndexer) bool {
+		if ix.Name() != name {
+			return false
+		}
+		kv.UnregisterChaindataTables(customIndexerTables(ix))
+		return true
+	})
+}
+
+func CustomIndexers() []CustomIndexer { return customIndexers }
+
+func customIndexerDomains(ix CustomIndexer) []string {
+	if dix, ok := ix.(CustomDomainIndexer); ok {
+		return dix.Domains()
+	}
+	return nil
+}
+
+// customIndexerTables - own tables of indexer plus tables of it's domains
+func customIndexerTables(ix CustomIndexer) kv.TableCfg {
+	tables := kv.TableCfg{}
+	for name, cfg := range ix.Tables() {
+		tables[name] = cfg
+	}
+	for _, domain := range customIndexerDomains(ix) {
+		tables[domain] = kv.TableCfgItem{}
+		tables[customDomainHistory(domain)] = kv.TableCfgItem{}
+		tables[customDomainChangeSet(domain)] = kv.TableCfgItem{}
+	}
+	return tables
+}
+
+func customDomainHistory(domain string) string   { return domain + "History" }
+func customDomainChangeSet(domain string) string { return domain + "ChangeSet" }
+
+// CustomDomainGet - latest value of `key` in domain of custom indexer
+func CustomDomainGet(tx kv.Getter, domain string, key []byte) ([]byte, error) {
+	return tx.GetOne(domain, key)
+}
+
+// CustomDomainGetAsOf - value of `key` in domain of custom indexer after execution of block `blockNum`
+func CustomDomainGetAsOf(tx kv.Tx, domain string, key []byte, blockNum uint64) ([]byte, error) {
+	c, err := tx.Cursor(customDomainHistory(domain))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	// first change of `key` after `blockNum` has value before it. Keys of other length with same prefix are skipped.
+	for k, v, err := c.Seek(append(libcommon.Copy(key), hexutility.EncodeTs(blockNum+1)...)); k != nil && bytes.HasPrefix(k, key); k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if len(k) == len(key)+8 {
+			if len(v) == 0 {
+				return nil, nil
+			}
+			return v, nil
+		}
+	}
+	return CustomDomainGet(tx, domain, key)
+}
+
+// unwindCustomDomain - restores values of keys changed after `unwindTo` and deletes their history
+func unwindCustomDomain(tx kv.RwTx, domain string, unwindTo uint64) error {
+	c, err := tx.RwCursor(customDomainChangeSet(domain))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	restored := map[string]struct{}{}
+	for k, v, err := c.Seek(hexutility.EncodeTs(unwindTo + 1)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		key := k[8:]
+		// changes are iterated in order of blocks: first change after `unwindTo` has value at `unwindTo`
+		if _, ok := restored[string(key)]; !ok {
+			restored[string(key)] = struct{}{}
+			if len(v) == 0 {
+				err = tx.Delete(domain, key)
+			} else {
+				err = tx.Put(domain, key, v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(customDomainHistory(domain), append(libcommon.Copy(key), k[:8]...)); err != nil {
+			return err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const customIndexersBatchSize = 100_000 // blocks
+
+func SpawnCustomIndexers(s *StageState, txc wrap.TxContainer, cfg CustomTraceCfg, ctx context.Context, logger log.Logger) (err error) {
+	indexers := cfg.indexers
+	if len(indexers) == 0 {
+		return nil
+	}
+	if txc.Doms != nil { // in-memory execution: state is not in db, nothing to re-execute
+		return nil
+	}
+	tx := txc.Tx
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if tx != nil {
+				tx.Rollback()
+			}
+		}()
+	}
+
+	endBlock, err := s.ExecutionAt(tx)
+	if err != nil {
+		return err
+	}
+	progress := make([]uint64, len(indexers))
+	startBlock := endBlock
+	for i, ix := range indexers {
+		if progress[i], err = stages.GetStageProgress(tx, stages.CustomIndexer(ix.Name())); err != nil {
+			return err
+		}
+		startBlock = min(startBlock, progress[i])
+	}
+	if startBlock >= endBlock {
+		return nil
+	}
+	if cfg.prune.History.Enabled() {
+		// history is needed for re-execution: indexers will not have data of pruned blocks
+		if pruneTo := cfg.prune.History.PruneTo(endBlock); pruneTo > startBlock {
+			logger.Warn(fmt.Sprintf("[%s] history is pruned, custom indexers skip blocks", s.LogPrefix()), "from", startBlock+1, "to", pruneTo)
+			startBlock = pruneTo
+		}
+	}
+
+	execArgs := cfg.execArgs
+	if useExternalTx {
+		// background workers use own RoTx - they can't see data of external tx
+		argsCopy := *cfg.execArgs
+		argsCopy.Workers = 1
+		execArgs = &argsCopy
+	}
+
+	for fromBlock := startBlock + 1; fromBlock <= endBlock; fromBlock += customIndexersBatchSize {
+		toBlock := min(fromBlock+customIndexersBatchSize-1, endBlock)
+		if err := customIndexersBatch(ctx, execArgs, tx.(kv.TemporalRwTx), indexers, progress, fromBlock, toBlock, s.LogPrefix(), cfg.tmpdir, logger); err != nil {
+			return err
+		}
+		for i, ix := range indexers {
+			progress[i] = max(progress[i], toBlock)
+			if err := stages.SaveStageProgress(tx, stages.CustomIndexer(ix.Name()), progress[i]); err != nil {
+				return err
+			}
+		}
+		if err := s.Update(tx, toBlock); err != nil {
+			return err
+		}
+		if !useExternalTx {
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+			if tx, err = cfg.db.BeginRw(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !useExternalTx {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		tx = nil
+	}
+	return nil
+}
+
+func customIndexersBatch(ctx context.Context, cfg *exec3.ExecArgs, tx kv.TemporalRwTx, indexers []CustomIndexer, progress []uint64, fromBlock, toBlock uint64, logPrefix, tmpdir string, logger log.Logger) error {
+	const logPeriod = 20 * time.Second
+	logEvery := time.NewTicker(logPeriod)
+	defer logEvery.Stop()
+
+	writers := make([]*customIndexWriter, len(indexers))
+	for i, ix := range indexers {
+		writers[i] = newCustomIndexWriter(fmt.Sprintf("%s %s", logPrefix, ix.Name()), tmpdir, ix.Tables(), customIndexerDomains(ix), logger)
+		defer writers[i].close()
+	}
+
+	if err := exec3.CustomTraceMapReduce(fromBlock, toBlock, exec3.TraceConsumer{
+		NewTracer: func() exec3.GenericTracer {
+			var tracers customIndexersTracer
+			for i, ix := range indexers {
+				if tracer := ix.NewTracer(); tracer != nil {
+					if tracers == nil {
+						tracers = make(customIndexersTracer, len(indexers))
+					}
+					tracers[i] = tracer
+				}
+			}
+			if tracers == nil {
+				return nil
+			}
+			return tracers
+		},
+		Reduce: func(txTask *state.TxTask, tx kv.Tx) error {
+			if txTask.Error != nil {
+				return txTask.Error
+			}
+			fillContractAddress(txTask)
+			tracers, _ := txTask.Tracer.(customIndexersTracer)
+			for i, ix := range indexers {
+				if txTask.BlockNum <= progress[i] {
+					continue
+				}
+				var tracer exec3.GenericTracer
+				if tracers != nil {
+					tracer = tracers[i]
+				}
+				writers[i].tx, writers[i].blockNum = tx, txTask.BlockNum
+				if err := ix.Index(txTask, tracer, tx, writers[i]); err != nil {
+					return fmt.Errorf("%s: %w", ix.Name(), err)
+				}
+			}
+
+			select {
+			case <-logEvery.C:
+				logger.Info(fmt.Sprintf("[%s] Indexing", logPrefix), "block", txTask.BlockNum, "to", toBlock)
+			default:
+			}
+			return nil
+		},
+		StateDiff: true,
+	}, ctx, tx, cfg, logger); err != nil {
+		return err
+	}
+
+	for _, w := range writers {
+		if err := w.load(tx, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillContractAddress - receipts created by re-execution don't have ContractAddress (Execution stage doesn't need it)
+func fillContractAddress(txTask *state.TxTask) {
+	if txTask.TxIndex < 0 || txTask.Final || txTask.TxIndex >= len(txTask.BlockReceipts) || txTask.Sender == nil || txTask.Tx.GetTo() != nil {
+		return
+	}
+	if receipt := txTask.BlockReceipts[txTask.TxIndex]; receipt != nil {
+		receipt.ContractAddress = crypto.CreateAddress(*txTask.Sender, txTask.Tx.GetNonce())
+	}
+}
+
+func UnwindCustomIndexers(u *UnwindState, s *StageState, txc wrap.TxContainer, cfg CustomTraceCfg, ctx context.Context, logger log.Logger) (err error) {
+	if len(cfg.indexers) == 0 {
+		return nil
+	}
+	tx := txc.Tx
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	for _, ix := range cfg.indexers {
+		progress, err := stages.GetStageProgress(tx, stages.CustomIndexer(ix.Name()))
+		if err != nil {
+			return err
+		}
+		if progress <= u.UnwindPoint {
+			continue
+		}
+		if err := ix.Unwind(tx, u.UnwindPoint); err != nil {
+			return fmt.Errorf("unwind %s: %w", ix.Name(), err)
+		}
+		for _, domain := range customIndexerDomains(ix) {
+			if err := unwindCustomDomain(tx, domain, u.UnwindPoint); err != nil {
+				return fmt.Errorf("unwind %s: %w", ix.Name(), err)
+			}
+		}
+		if err := stages.SaveStageProgress(tx, stages.CustomIndexer(ix.Name()), u.UnwindPoint); err != nil {
+			return err
+		}
+	}
+	if err := u.Done(tx); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type customIndexWriter struct {
+	logPrefix  string
+	tmpdir     string
+	tables     kv.TableCfg
+	collectors map[string]*etl.Collector
+	logger     log.Logger
+
+	// domains: latest values of current batch are in RAM - history records value before first change of key in block
+	domains  map[string]*customDomainWriter
+	tx       kv.Tx // db view without current batch
+	blockNum uint64
+}
+
+type customDomainWriter struct {
+	latest    map[string][]byte
+	changedAt map[string]uint64 // block of last history record of key
+	history   *etl.Collector
+	changeSet *etl.Collector
+}
+
+func newCustomIndexWriter(logPrefix, tmpdir string, tables kv.TableCfg, domains []string, logger log.Logger) *customIndexWriter {
+	w := &customIndexWriter{logPrefix: logPrefix, tmpdir: tmpdir, tables: tables, collectors: map[string]*etl.Collector{}, logger: logger,
+		domains: map[string]*customDomainWriter{}}
+	for _, domain := range domains {
+		w.domains[domain] = &customDomainWriter{
+			latest:    map[string][]byte{},
+			changedAt: map[string]uint64{},
+			history:   w.newCollector(),
+			changeSet: w.newCollector(),
+		}
+	}
+	return w
+}
+
+func (w *customIndexWriter) newCollector() *etl.Collector {
+	c := etl.NewCollector(w.logPrefix, w.tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize), w.logger)
+	c.LogLvl(log.LvlDebug)
+	return c
+}
+
+func (w *customIndexWriter) Put(table string, k, v []byte) error {
+	c, ok := w.collectors[table]
+	if !ok {
+		if _, ok := w.tables[table]; !ok {
+			return fmt.Errorf("table %s is not owned by indexer", table)
+		}
+		c = w.newCollector()
+		w.collectors[table] = c
+	}
+	return c.Collect(k, v)
+}
+
+func (w *customIndexWriter) GetDomain(domain string, k []byte) ([]byte, error) {
+	d, ok := w.domains[domain]
+	if !ok {
+		return nil, fmt.Errorf("domain %s is not owned by indexer", domain)
+	}
+	if v, ok := d.latest[string(k)]; ok {
+		return v, nil
+	}
+	return CustomDomainGet(w.tx, domain, k)
+}
+
+func (w *customIndexWriter) PutDomain(domain string, k, v []byte) error {
+	prev, err := w.GetDomain(domain, k)
+	if err != nil {
+		return err
+	}
+	d := w.domains[domain]
+	if changedAt, ok := d.changedAt[string(k)]; !ok || changedAt != w.blockNum {
+		d.changedAt[string(k)] = w.blockNum
+		blockNum := hexutility.EncodeTs(w.blockNum)
+		if err := d.history.Collect(append(libcommon.Copy(k), blockNum...), prev); err != nil {
+			return err
+		}
+		if err := d.changeSet.Collect(append(blockNum, k...), prev); err != nil {
+			return err
+		}
+	}
+	d.latest[string(k)] = libcommon.Copy(v)
+	return nil
+}
+
+func (w *customIndexWriter) load(tx kv.RwTx, ctx context.Context) error {
+	for table, c := range w.collectors {
+		if err := c.Load(tx, table, etl.IdentityLoadFunc, etl.TransformArgs{Quit: ctx.Done()}); err != nil {
+			return err
+		}
+		c.Close()
+		delete(w.collectors, table)
+	}
+	for domain, d := range w.domains {
+		for k, v := range d.latest {
+			var err error
+			if len(v) == 0 {
+				err = tx.Delete(domain, []byte(k))
+			} else {
+				err = tx.Put(domain, []byte(k), v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		// empty value of history: key didn't exist before block
+		if err := d.history.Load(tx, customDomainHistory(domain), etl.IdentityLoadFunc, etl.TransformArgs{Quit: ctx.Done(), EmptyVals: true}); err != nil {
+			return err
+		}
+		if err := d.changeSet.Load(tx, customDomainChangeSet(domain), etl.IdentityLoadFunc, etl.TransformArgs{Quit: ctx.Done(), EmptyVals: true}); err != nil {
+			return err
+		}
+		d.latest, d.changedAt = map[string][]byte{}, map[string]uint64{}
+	}
+	return nil
+}
+
+func (w *customIndexWriter) close() {
+	for _, c := range w.collectors {
+		c.Close()
+	}
+	for _, d := range w.domains {
+		d.history.Close()
+		d.changeSet.Close()
+	}
+}
+
+// customIndexersTracer - vm supports 1 tracer: this one fans-out to tracers of all indexers (nil for indexers without tracer)
+type customIndexersTracer []exec3.GenericTracer
+
+func (t customIndexersTracer) CaptureTxStart(gasLimit uint64) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureTxStart(gasLimit)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureTxEnd(restGas uint64) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureTxEnd(restGas)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureStart(env, from, to, precompile, create, input, gas, value, code)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureEnd(output, usedGas, err)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureEnter(typ, from, to, precompile, create, input, gas, value, code)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureExit(output, usedGas, err)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+		}
+	}
+}
+func (t customIndexersTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.CaptureFault(pc, op, gas, cost, scope, depth, err)
+		}
+	}
+}
+func (t customIndexersTracer) SetTransaction(txn types.Transaction) {
+	for _, tr := range t {
+		if tr != nil {
+			tr.SetTransaction(txn)
+		}
+	}
+}
+func (t customIndexersTracer) Found() bool {
+	for _, tr := range t {
+		if tr != nil && tr.Found() {
+			return true
+		}
+	}
+	return false
+}
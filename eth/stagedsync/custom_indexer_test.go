@@ -0,0 +1,221 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/eth/stagedsync"
+	"github.com/erigontech/erigon/eth/stagedsync/stages"
+	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+const (
+	testDeploymentsTable  = "test.CustomIndexerDeployments"
+	testDeployCountDomain = "test.CustomIndexerDeployCount" // deployer -> amount of deployed contracts (u64)
+)
+
+// testDeploymentsIndexer - blockNum_u64 + address -> 1 if created by top-level call (seen by tracer), 0 otherwise
+type testDeploymentsIndexer struct{}
+
+func (testDeploymentsIndexer) Name() string                   { return "TestDeployments" }
+func (testDeploymentsIndexer) Tables() kv.TableCfg            { return kv.TableCfg{testDeploymentsTable: {}} }
+func (testDeploymentsIndexer) NewTracer() exec3.GenericTracer { return &createTracer{} }
+func (testDeploymentsIndexer) Domains() []string              { return []string{testDeployCountDomain} }
+
+func (testDeploymentsIndexer) Index(txTask *state.TxTask, tracer exec3.GenericTracer, _ kv.Tx, w stagedsync.CustomIndexWriter) error {
+	if txTask.Tx == nil { // system txs are not traced
+		return nil
+	}
+	created := tracer.(*createTracer).created
+	receipt := txTask.BlockReceipts[txTask.TxIndex]
+	for i, addr := range txTask.WriteLists[kv.CodeDomain.String()].Keys {
+		if len(txTask.WriteLists[kv.CodeDomain.String()].Vals[i]) == 0 {
+			continue
+		}
+		if receipt == nil || receipt.ContractAddress != libcommon.BytesToAddress([]byte(addr)) {
+			return fmt.Errorf("unexpected receipt %+v at block %d", receipt, txTask.BlockNum)
+		}
+		v := []byte{0}
+		if created == receipt.ContractAddress {
+			v[0] = 1
+		}
+		if err := w.Put(testDeploymentsTable, append(hexutility.EncodeTs(txTask.BlockNum), addr...), v); err != nil {
+			return err
+		}
+		count, err := w.GetDomain(testDeployCountDomain, txTask.Sender[:])
+		if err != nil {
+			return err
+		}
+		if len(count) == 0 {
+			count = make([]byte, 8)
+		}
+		if err := w.PutDomain(testDeployCountDomain, txTask.Sender[:], hexutility.EncodeTs(binary.BigEndian.Uint64(count)+1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (testDeploymentsIndexer) Unwind(tx kv.RwTx, unwindTo uint64) error {
+	c, err := tx.RwCursor(testDeploymentsTable)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(hexutility.EncodeTs(unwindTo + 1)); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type createTracer struct {
+	created libcommon.Address
+}
+
+func (t *createTracer) CaptureTxStart(gasLimit uint64)                      {}
+func (t *createTracer) CaptureTxEnd(restGas uint64)                         {}
+func (t *createTracer) CaptureEnd(output []byte, usedGas uint64, err error) {}
+func (t *createTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+}
+func (t *createTracer) CaptureExit(output []byte, usedGas uint64, err error) {}
+func (t *createTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *createTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *createTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if create {
+		t.created = to
+	}
+}
+func (t *createTracer) SetTransaction(tx types.Transaction) {}
+func (t *createTracer) Found() bool                         { return t.created != libcommon.Address{} }
+
+func TestCustomIndexer(t *testing.T) {
+	// indexer's tables are created at db open - register before mock
+	stagedsync.RegisterCustomIndexer(testDeploymentsIndexer{})
+	t.Cleanup(func() { stagedsync.UnregisterCustomIndexer(testDeploymentsIndexer{}.Name()) })
+
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSignerForChainID(nil)
+		gspec   = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1e18)}},
+		}
+		// init code returning 10 bytes of runtime code
+		initCode = hexutility.MustDecodeHex("0x600a600c600039600a6000f3602a60005260206000f3")
+	)
+	m := mock.MockWithGenesis(t, gspec, key, false)
+	require := require.New(t)
+
+	deployAt := func(n int, deployBlocks ...int) *core.ChainPack {
+		chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, n, func(i int, b *core.BlockGen) {
+			for _, deployBlock := range deployBlocks {
+				if i+1 == deployBlock {
+					txn, err := types.SignTx(types.NewContractCreation(b.TxNonce(address), new(uint256.Int), 1e6, new(uint256.Int), initCode), *signer, key)
+					require.NoError(err)
+					b.AddTx(txn)
+					return
+				}
+			}
+			b.SetCoinbase(libcommon.Address{byte(i)}) // make blocks of chains different
+		})
+		require.NoError(err)
+		return chain
+	}
+	readIndex := func() (blocks []uint64, addrs []libcommon.Address) {
+		require.NoError(m.DB.View(m.Ctx, func(tx kv.Tx) error {
+			return tx.ForEach(testDeploymentsTable, nil, func(k, v []byte) error {
+				require.Equal([]byte{1}, v)
+				blocks = append(blocks, binary.BigEndian.Uint64(k))
+				addrs = append(addrs, libcommon.BytesToAddress(k[8:]))
+				return nil
+			})
+		}))
+		return blocks, addrs
+	}
+	progress := func() uint64 {
+		var p uint64
+		require.NoError(m.DB.View(m.Ctx, func(tx kv.Tx) (err error) {
+			p, err = stages.GetStageProgress(tx, stages.CustomIndexer(testDeploymentsIndexer{}.Name()))
+			return err
+		}))
+		return p
+	}
+
+	// deploy count of `address` after each block, 0 - no value
+	deployCounts := func(blocks uint64) (counts []uint64) {
+		require.NoError(m.DB.View(m.Ctx, func(tx kv.Tx) error {
+			for blockNum := uint64(0); blockNum <= blocks; blockNum++ {
+				v, err := stagedsync.CustomDomainGetAsOf(tx, testDeployCountDomain, address[:], blockNum)
+				if err != nil {
+					return err
+				}
+				var count uint64
+				if len(v) > 0 {
+					count = binary.BigEndian.Uint64(v)
+				}
+				counts = append(counts, count)
+			}
+			latest, err := stagedsync.CustomDomainGet(tx, testDeployCountDomain, address[:])
+			require.Equal(hexutility.EncodeTs(counts[blocks]), latest)
+			return err
+		}))
+		return counts
+	}
+
+	chain, longerChain := deployAt(3, 1, 3), deployAt(4, 4)
+
+	require.NoError(m.InsertChain(chain))
+	blocks, addrs := readIndex()
+	require.Equal([]uint64{1, 3}, blocks)
+	require.Equal([]libcommon.Address{crypto.CreateAddress(address, 0), crypto.CreateAddress(address, 1)}, addrs)
+	require.Equal(uint64(3), progress())
+	require.Equal([]uint64{0, 1, 1, 2}, deployCounts(3))
+
+	// reorg to longer chain: data of unwound blocks must be removed
+	require.NoError(m.InsertChain(longerChain))
+	blocks, addrs = readIndex()
+	require.Equal([]uint64{4}, blocks)
+	require.Equal([]libcommon.Address{crypto.CreateAddress(address, 0)}, addrs)
+	require.Equal(uint64(4), progress())
+	require.Equal([]uint64{0, 0, 0, 0, 1}, deployCounts(4))
+}
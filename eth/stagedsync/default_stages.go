@@ -157,6 +157,19 @@ func DefaultStages(ctx context.Context,
 		//		return PruneCustomTrace(p, tx, cfg, ctx, logger)
 		//	},
 		//},
+		{
+			ID:          stages.CustomIndexers,
+			Description: "User-defined indexers, on top of re-execution of history",
+			Disabled:    dbg.StagesOnlyBlocks || len(CustomIndexers()) == 0,
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageCustomTraceCfg(exec.db, exec.prune, exec.dirs, exec.blockReader, exec.chainConfig, exec.engine, exec.genesis, &exec.syncCfg)
+				return SpawnCustomIndexers(s, txc, cfg, ctx, logger)
+			},
+			Unwind: func(u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageCustomTraceCfg(exec.db, exec.prune, exec.dirs, exec.blockReader, exec.chainConfig, exec.engine, exec.genesis, &exec.syncCfg)
+				return UnwindCustomIndexers(u, s, txc, cfg, ctx, logger)
+			},
+		},
 		{
 			ID:          stages.TxLookup,
 			Description: "Generate txn lookup index",
@@ -244,7 +257,19 @@ func PipelineStages(ctx context.Context, snapshots SnapshotsCfg, blockHashCfg Bl
 				return PruneExecutionStage(p, tx, exec, ctx)
 			},
 		},
-
+		{
+			ID:          stages.CustomIndexers,
+			Description: "User-defined indexers, on top of re-execution of history",
+			Disabled:    len(CustomIndexers()) == 0,
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageCustomTraceCfg(exec.db, exec.prune, exec.dirs, exec.blockReader, exec.chainConfig, exec.engine, exec.genesis, &exec.syncCfg)
+				return SpawnCustomIndexers(s, txc, cfg, ctx, logger)
+			},
+			Unwind: func(u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				cfg := StageCustomTraceCfg(exec.db, exec.prune, exec.dirs, exec.blockReader, exec.chainConfig, exec.engine, exec.genesis, &exec.syncCfg)
+				return UnwindCustomIndexers(u, s, txc, cfg, ctx, logger)
+			},
+		},
 		{
 			ID:          stages.TxLookup,
 			Description: "Generate txn lookup index",
@@ -553,6 +578,7 @@ var DefaultForwardOrder = UnwindOrder{
 	stages.Senders,
 	stages.Execution,
 	//stages.CustomTrace,
+	stages.CustomIndexers,
 	stages.TxLookup,
 	stages.Finish,
 }
@@ -568,6 +594,7 @@ var DefaultUnwindOrder = UnwindOrder{
 	stages.Finish,
 	stages.TxLookup,
 
+	stages.CustomIndexers,
 	//stages.CustomTrace,
 	stages.Execution,
 	stages.Senders,
@@ -581,6 +608,7 @@ var DefaultUnwindOrder = UnwindOrder{
 var PipelineUnwindOrder = UnwindOrder{
 	stages.Finish,
 	stages.TxLookup,
+	stages.CustomIndexers,
 
 	stages.Execution,
 	stages.Senders,
@@ -177,14 +177,15 @@ WAL it's like StateV3 just without reading api (can only write there). WAL flush
 
 - RoTx - see everything what committed to DB. Commit is done by rwLoop goroutine.
 rwloop does:
-  - stop all Workers
-  - call StateV3.Flush()
+  - stop applyLoop and all Workers
+  - check state root of last applied block (same as sequential execution), call StateV3.Flush()
   - commit
   - open new RoTx
   - set new RoTx to all Workers
   - start Worker start workers
 
-When rwLoop has nothing to do - it does Prune, or flush of WAL to RwTx (agg.rotate+agg.Flush)
+Parallel execution is enabled by `--sync.parallel-execution` and used only for catch-up (far from chain-tip):
+applyLoop does apply txs in-order, re-execute conflicting txs and does produce same receipts/state as sequential execution.
 */
 func ExecV3(ctx context.Context,
 	execStage *StageState, u Unwinder, workerCount int, cfg ExecuteBlockCfg, txc wrap.TxContainer,
@@ -194,9 +195,6 @@ func ExecV3(ctx context.Context,
 	initialCycle bool,
 	isMining bool,
 ) error {
-	// TODO: e35 doesn't support parallel-exec yet
-	parallel = false //nolint
-
 	batchSize := cfg.batchSize
 	chainDb := cfg.db
 	blockReader := cfg.blockReader
@@ -213,16 +211,17 @@ func ExecV3(ctx context.Context,
 	applyTx := txc.Tx
 	useExternalTx := applyTx != nil
 	if !useExternalTx {
-		if !parallel {
-			var err error
-			applyTx, err = chainDb.BeginRw(ctx) //nolint
-			if err != nil {
-				return err
-			}
-			defer func() { // need callback - because tx may be committed
-				applyTx.Rollback()
-			}()
+		// parallel exec also needs it: to restore progress. It's closed later - rwLoop does manage own RwTx
+		var err error
+		applyTx, err = chainDb.BeginRw(ctx) //nolint
+		if err != nil {
+			return err
 		}
+		defer func() { // need callback - because tx may be committed
+			if applyTx != nil {
+				applyTx.Rollback()
+			}
+		}()
 	}
 	agg := cfg.db.(state2.HasAgg).Agg().(*state2.Aggregator)
 	if initialCycle {
@@ -381,7 +380,18 @@ func ExecV3(ctx context.Context,
 			accumulator = shards.NewAccumulator()
 		}
 	}
+
+	// parallel exec is for catch-up: near chain-tip sequential exec is good enough and it does support all features
+	parallel = parallel && cfg.syncCfg.ParallelExecution && !useExternalTx && !inMemExec && !isMining && !cfg.blockProduction &&
+		!shouldGenerateChangesets && !shouldReportToTxPool && chainConfig.Bor == nil
+	if parallel {
+		applyTx.Rollback() // rwLoop and applyLoop have own txs
+		applyTx = nil
+		doms.SetParallel(true)
+	}
+
 	rs := state.NewStateV3(doms, logger)
+	profiler := newBlockExecProfiler(cfg.syncCfg.ExecProfile && !parallel && !isMining, doms, cfg.syncCfg.SlowBlockThreshold, execStage.LogPrefix(), logger)
 
 	////TODO: owner of `resultCh` is main goroutine, but owner of `retryQueue` is applyLoop.
 	// Now rwLoop closing both (because applyLoop we completely restart)
@@ -394,7 +404,7 @@ func ExecV3(ctx context.Context,
 	rwsConsumed := make(chan struct{}, 1)
 	defer close(rwsConsumed)
 
-	execWorkers, _, rws, stopWorkers, waitWorkers := exec3.NewWorkersPool(lock.RLocker(), accumulator, logger, ctx, parallel, chainDb, rs, in, blockReader, chainConfig, genesis, engine, workerCount+1, cfg.dirs, isMining)
+	execWorkers, _, rws, stopWorkers, _ := exec3.NewWorkersPool(lock.RLocker(), accumulator, logger, ctx, parallel, chainDb, rs, in, blockReader, chainConfig, genesis, engine, workerCount+1, cfg.dirs, isMining)
 	defer stopWorkers()
 	applyWorker := cfg.applyWorker
 	if isMining {
@@ -422,6 +432,12 @@ func ExecV3(ctx context.Context,
 	applyLoopWg := sync.WaitGroup{} // to wait for finishing of applyLoop after applyCtx cancel
 	defer applyLoopWg.Wait()
 
+	blockResult := &parallelBlockResult{
+		checkReceipts: func(blockNum uint64) bool {
+			return !cfg.vmConfig.StatelessExec && chainConfig.IsByzantium(blockNum) && !cfg.vmConfig.NoReceipts && !isMining
+		},
+	}
+
 	applyLoopInner := func(ctx context.Context) error {
 		tx, err := chainDb.BeginRo(ctx)
 		if err != nil {
@@ -429,6 +445,9 @@ func ExecV3(ctx context.Context,
 		}
 		defer tx.Rollback()
 
+		// RwTx of rwLoop can't be used from another goroutine. It's safe to read by own RoTx:
+		// rwLoop does write to db only when applyLoop is stopped.
+		doms.SetTx(tx)
 		applyWorker.ResetTx(tx)
 
 		var lastBlockNum uint64
@@ -438,7 +457,7 @@ func ExecV3(ctx context.Context,
 				return err
 			}
 
-			processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err := processResultQueue(ctx, in, rws, outputTxNum.Load(), rs, agg, tx, rwsConsumed, applyWorker, true, false, isMining)
+			processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err := processResultQueue(ctx, in, rws, outputTxNum.Load(), rs, blockResult, tx, rwsConsumed, applyWorker, true, false, isMining)
 			if err != nil {
 				return err
 			}
@@ -462,84 +481,124 @@ func ExecV3(ctx context.Context,
 		defer func() {
 			if rec := recover(); rec != nil {
 				log.Warn("[dbg] apply loop panic", "rec", rec)
+				sendErr(errCh, fmt.Errorf("apply loop panic: %v, %s", rec, dbg.Stack()))
 			}
 			log.Warn("[dbg] apply loop exit")
 		}()
-		if err := applyLoopInner(ctx); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				errCh <- err
-			}
+		err := applyLoopInner(ctx)
+		if errors.Is(err, context.Canceled) {
+			return
 		}
+		sendErr(errCh, err) // nil: all txs applied - wakeup rwLoop
 	}
 
 	var rwLoopErrCh chan error
 
 	var rwLoopG *errgroup.Group
+	rwLoopCtx := ctx
 	if parallel {
+		rwLoopErrCh = make(chan error, 1)
+
 		// `rwLoop` lives longer than `applyLoop`
 		rwLoop := func(ctx context.Context) error {
 			tx, err := chainDb.BeginRw(ctx)
 			if err != nil {
 				return err
 			}
-			defer tx.Rollback()
-
-			doms.SetTx(tx)
+			defer func() { tx.Rollback() }()
 
-			defer applyLoopWg.Wait()
 			applyCtx, cancelApplyCtx := context.WithCancel(ctx)
-			defer cancelApplyCtx()
+			defer func() { cancelApplyCtx() }()
 			applyLoopWg.Add(1)
 			go applyLoop(applyCtx, rwLoopErrCh)
-			for outputTxNum.Load() <= maxTxNum {
+
+			// applyLoop must not run when rwLoop does use `doms`
+			stopApplyLoop := func() error {
+				cancelApplyCtx()
+				applyLoopWg.Wait()
 				select {
-				case <-ctx.Done():
-					return ctx.Err()
-
-				case <-logEvery.C:
-					stepsInDB = rawdbhelpers.IdxStepsCountV3(tx)
-					progress.Log("", rs, in, rws, rs.DoneCount(), logGas, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets)
-					if agg.HasBackgroundFilesBuild() {
-						logger.Info(fmt.Sprintf("[%s] Background files build", execStage.LogPrefix()), "progress", agg.BackgroundProgress())
+				case err := <-rwLoopErrCh:
+					if err != nil {
+						return err
 					}
-				case <-pruneEvery.C:
-					if rs.SizeEstimate() < commitThreshold {
-						if doms.BlockNum() != outputBlockNum.GetValueUint64() {
-							panic(fmt.Errorf("%d != %d", doms.BlockNum(), outputBlockNum.GetValueUint64()))
-						}
-						_, err := doms.ComputeCommitment(ctx, true, outputBlockNum.GetValueUint64(), execStage.LogPrefix())
+				default:
+				}
+				doms.SetTx(tx)
+				applyWorker.ResetTx(tx)
+				return nil
+			}
+
+			committedTxNum := outputTxNum.Load()
+			// commit - checks state root of last applied block, then flush and commit. returns false if root mismatch.
+			commit := func() (bool, error) {
+				if outputTxNum.Load() == committedTxNum {
+					return true, nil
+				}
+				header, err := blockReader.HeaderByNumber(ctx, tx, outputBlockNum.GetValueUint64())
+				if err != nil {
+					return false, err
+				}
+				if header == nil {
+					return false, fmt.Errorf("header not found: %d", outputBlockNum.GetValueUint64())
+				}
+				if ok, err := flushAndCheckCommitmentV3(ctx, header, tx, doms, cfg, execStage, outputBlockNum.GetValueUint64(), false, logger, u, false); err != nil || !ok {
+					return ok, err
+				}
+				if _, err := tx.(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneSmallBatches(ctx, 10*time.Hour, tx); err != nil {
+					return false, err
+				}
+				doms.ClearRam(true)
+				// txs executed before flush have read-sets which can't be validated anymore
+				rs.IncFlushEpoch()
+
+				tx.CollectMetrics()
+				if err := tx.Commit(); err != nil {
+					return false, err
+				}
+				committedTxNum = outputTxNum.Load()
+				for i := 0; i < len(execWorkers); i++ {
+					execWorkers[i].ResetTx(nil)
+				}
+				agg.BuildFilesInBackground(committedTxNum)
+				return true, nil
+			}
+
+			loop := func() error {
+				for outputTxNum.Load() <= maxTxNum {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+
+					case err := <-rwLoopErrCh:
 						if err != nil {
 							return err
 						}
-						ac := agg.BeginFilesRo()
-						if _, err = ac.PruneSmallBatches(ctx, 10*time.Second, tx); err != nil { // prune part of retired data, before commit
-							return err
+
+					case <-logEvery.C:
+						stepsInDB = rawdbhelpers.IdxStepsCountV3(tx)
+						progress.Log("", rs, in, rws, rs.DoneCount(), blockResult.gasUsed.Load(), inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), mxExecRepeats.GetValueUint64(), stepsInDB, shouldGenerateChangesets)
+						if agg.HasBackgroundFilesBuild() {
+							logger.Info(fmt.Sprintf("[%s] Background files build", execStage.LogPrefix()), "progress", agg.BackgroundProgress())
 						}
-						ac.Close()
-						if !inMemExec {
-							if err = doms.Flush(ctx, tx); err != nil {
-								return err
-							}
+					case <-pruneEvery.C:
+						if rs.SizeEstimate() < commitThreshold {
+							break
 						}
-						break
-					}
-					if inMemExec {
-						break
-					}
 
-					cancelApplyCtx()
-					applyLoopWg.Wait()
+						if err := stopApplyLoop(); err != nil {
+							return err
+						}
 
-					var t0, t1, t2, t3, t4 time.Duration
-					commitStart := time.Now()
-					logger.Info("Committing (parallel)...", "blockComplete.Load()", blockComplete.Load())
-					if err := func() error {
+						var t0, t1 time.Duration
+						commitStart := time.Now()
+						logger.Info("Committing (parallel)...", "blockComplete.Load()", blockComplete.Load())
 						//Drain results (and process) channel because read sets do not carry over
 						for !blockComplete.Load() {
-							rws.DrainNonBlocking(ctx)
-							applyWorker.ResetTx(tx)
+							if err := rws.DrainNonBlocking(ctx); err != nil {
+								return err
+							}
 
-							processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err := processResultQueue(ctx, in, rws, outputTxNum.Load(), rs, agg, tx, nil, applyWorker, false, true, isMining)
+							processedTxNum, conflicts, triggers, processedBlockNum, stoppedAtBlockEnd, err := processResultQueue(ctx, in, rws, outputTxNum.Load(), rs, blockResult, tx, nil, applyWorker, false, true, isMining)
 							if err != nil {
 								return err
 							}
@@ -555,90 +614,95 @@ func ExecV3(ctx context.Context,
 							}
 						}
 						t0 = time.Since(commitStart)
-						lock.Lock() // This is to prevent workers from starting work on any new txTask
-						defer lock.Unlock()
 
-						select {
-						case rwsConsumed <- struct{}{}:
-						default:
-						}
+						if err := func() error {
+							lock.Lock() // This is to prevent workers from starting work on any new txTask
+							defer lock.Unlock()
 
-						// Drain results channel because read sets do not carry over
-						rws.DropResults(ctx, func(txTask *state.TxTask) {
-							rs.ReTry(txTask, in)
-						})
+							select {
+							case rwsConsumed <- struct{}{}:
+							default:
+							}
 
-						//lastTxNumInDb, _ := txNumsReader.Max(tx, outputBlockNum.Get())
-						//if lastTxNumInDb != outputTxNum.Load()-1 {
-						//	panic(fmt.Sprintf("assert: %d != %d", lastTxNumInDb, outputTxNum.Load()))
-						//}
+							// Drain results channel because read sets do not carry over
+							rws.DropResults(ctx, func(txTask *state.TxTask) {
+								rs.ReTry(txTask, in)
+							})
 
-						t1 = time.Since(commitStart)
-						tt := time.Now()
-						t2 = time.Since(tt)
-						tt = time.Now()
-
-						if err := doms.Flush(ctx, tx); err != nil {
+							ok, err := commit()
+							if err != nil {
+								return err
+							}
+							if !ok {
+								return errStateRootMismatch
+							}
+							return nil
+						}(); err != nil {
 							return err
 						}
-						doms.ClearRam(true)
-						t3 = time.Since(tt)
+						t1 = time.Since(commitStart)
 
-						if err = execStage.Update(tx, outputBlockNum.GetValueUint64()); err != nil {
+						if tx, err = chainDb.BeginRw(ctx); err != nil {
 							return err
 						}
-						if _, err = rawdb.IncrementStateVersion(applyTx); err != nil {
-							return fmt.Errorf("writing plain state version: %w", err)
-						}
 
-						tx.CollectMetrics()
-						tt = time.Now()
-						if err = tx.Commit(); err != nil {
-							return err
-						}
-						t4 = time.Since(tt)
-						for i := 0; i < len(execWorkers); i++ {
-							execWorkers[i].ResetTx(nil)
-						}
+						applyCtx, cancelApplyCtx = context.WithCancel(ctx)
+						applyLoopWg.Add(1)
+						go applyLoop(applyCtx, rwLoopErrCh)
 
-						return nil
-					}(); err != nil {
-						return err
+						logger.Info("Committed", "time", time.Since(commitStart), "drain", t0, "flush+commitment+commit", t1-t0)
 					}
-					if tx, err = chainDb.BeginRw(ctx); err != nil {
-						return err
-					}
-					defer tx.Rollback()
-					doms.SetTx(tx)
-
-					applyCtx, cancelApplyCtx = context.WithCancel(ctx)
-					defer cancelApplyCtx()
-					applyLoopWg.Add(1)
-					go applyLoop(applyCtx, rwLoopErrCh)
+				}
 
-					logger.Info("Committed", "time", time.Since(commitStart), "drain", t0, "drain_and_lock", t1, "rs.flush", t2, "agg.flush", t3, "tx.commit", t4)
+				if err := stopApplyLoop(); err != nil {
+					return err
 				}
+				ok, err := commit()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errStateRootMismatch
+				}
+				return nil
 			}
-			if err = doms.Flush(ctx, tx); err != nil {
+
+			err = loop()
+			if errors.Is(err, errStateRootMismatch) {
+				// unwind is already requested by flushAndCheckCommitmentV3
+				return tx.Commit()
+			}
+			if err == nil || !errors.Is(err, consensus.ErrInvalidBlock) || cfg.badBlockHalt || u == nil {
 				return err
 			}
-			if err = execStage.Update(tx, outputBlockNum.GetValueUint64()); err != nil {
+
+			// same as sequential execution: unwind to parent of bad block
+			cancelApplyCtx()
+			applyLoopWg.Wait()
+			badBlockNum := blockResult.invalidBlockNum
+			header, hErr := blockReader.HeaderByNumber(ctx, tx, badBlockNum)
+			if hErr != nil || header == nil {
 				return err
 			}
-			if err = tx.Commit(); err != nil {
+			logger.Warn(fmt.Sprintf("[%s] Execution failed", execStage.LogPrefix()), "block", badBlockNum, "hash", header.Hash().String(), "err", err)
+			if cfg.hd != nil && cfg.hd.POSSync() {
+				cfg.hd.ReportBadHeaderPoS(header.Hash(), header.ParentHash)
+			}
+			if err := u.UnwindTo(badBlockNum-1, BadBlock(header.Hash(), err), tx); err != nil {
 				return err
 			}
-			return nil
+			return tx.Commit()
 		}
 
-		rwLoopCtx, rwLoopCtxCancel := context.WithCancel(ctx)
-		defer rwLoopCtxCancel()
-		rwLoopG, rwLoopCtx = errgroup.WithContext(rwLoopCtx)
-		defer rwLoopG.Wait()
+		var rwLoopCtxCancel context.CancelFunc
+		rwLoopCtx, rwLoopCtxCancel = context.WithCancel(ctx)
+		rwLoopG = &errgroup.Group{}
+		defer func() {
+			rwLoopCtxCancel()
+			rwLoopG.Wait()
+		}()
 		rwLoopG.Go(func() error {
-			defer rws.Close()
-			defer in.Close()
-			defer applyLoopWg.Wait()
+			defer rwLoopCtxCancel() // main loop must stop sending new txs
 			defer func() {
 				log.Warn("[dbg] rwloop exit")
 			}()
@@ -695,6 +759,7 @@ func ExecV3(ctx context.Context,
 
 Loop:
 	for ; blockNum <= maxBlockNum; blockNum++ {
+		blockStart := time.Now()
 		// set shouldGenerateChangesets=true if we are at last n blocks from maxBlockNum. this is as a safety net in chains
 		// where during initial sync we can expect bogus blocks to be imported.
 		if !shouldGenerateChangesets && shouldGenerateChangesetsForLastBlocks && blockNum > cfg.blockReader.FrozenBlocks() && blockNum+changesetSafeRange >= maxBlockNum {
@@ -721,7 +786,9 @@ Loop:
 			}
 		}
 		inputBlockNum.Store(blockNum)
-		doms.SetBlockNum(blockNum)
+		if !parallel {
+			doms.SetBlockNum(blockNum)
+		}
 
 		b, err = blockWithSenders(ctx, chainDb, applyTx, blockReader, blockNum)
 		if err != nil {
@@ -732,6 +799,7 @@ Loop:
 			return fmt.Errorf("nil block %d", blockNum)
 		}
 		metrics2.UpdateBlockConsumerPreExecutionDelay(b.Time(), blockNum, logger)
+		profiler.startBlock(b, blockStart)
 		txs := b.Transactions()
 		header := b.HeaderNoCopy()
 		skipAnalysis := core.SkipAnalysis(chainConfig, blockNum)
@@ -749,10 +817,8 @@ Loop:
 		// print type of engine
 		if parallel {
 			select {
-			case err := <-rwLoopErrCh:
-				if err != nil {
-					return err
-				}
+			case <-rwLoopCtx.Done(): // rwLoop stopped: by error or by unwind request
+				break Loop
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
@@ -818,7 +884,7 @@ Loop:
 
 				Config: chainConfig,
 			}
-			if txTask.HistoryExecution && usedGas == 0 {
+			if txTask.HistoryExecution && usedGas == 0 && !parallel {
 				usedGas, blobGasUsed, _, err = rawtemporaldb.ReceiptAsOf(applyTx.(kv.TemporalTx), txTask.TxNum)
 				if err != nil {
 					return err
@@ -832,10 +898,15 @@ Loop:
 			if txTask.TxNum <= txNumInDB && txTask.TxNum > 0 {
 				inputTxNum++
 				skipPostEvaluation = true
+				if parallel { // applyLoop is waiting for txs in-order
+					outputTxNum.Store(inputTxNum)
+				}
 				continue
 			}
-			doms.SetTxNum(txTask.TxNum)
-			doms.SetBlockNum(txTask.BlockNum)
+			if !parallel {
+				doms.SetTxNum(txTask.TxNum)
+				doms.SetBlockNum(txTask.BlockNum)
+			}
 
 			if txIndex >= 0 && txIndex < len(txs) {
 				txTask.Tx = txs[txIndex]
@@ -859,10 +930,10 @@ Loop:
 			if parallel {
 				if txTask.TxIndex >= 0 && txTask.TxIndex < len(txs) {
 					if ok := rs.RegisterSender(txTask); ok {
-						rs.AddWork(ctx, txTask, in)
+						rs.AddWork(rwLoopCtx, txTask, in)
 					}
 				} else {
-					rs.AddWork(ctx, txTask, in)
+					rs.AddWork(rwLoopCtx, txTask, in)
 				}
 				stageProgress = blockNum
 				inputTxNum++
@@ -873,6 +944,7 @@ Loop:
 			if txTask.Error != nil {
 				break Loop
 			}
+			txStart := time.Now()
 			applyWorker.RunTxTaskNoLock(txTask, isMining)
 			if err := func() error {
 				if errors.Is(txTask.Error, context.Canceled) {
@@ -948,6 +1020,7 @@ Loop:
 			if err := rs.ApplyState4(ctx, txTask); err != nil {
 				return err
 			}
+			profiler.txDone(txTask, txStart)
 
 			stageProgress = blockNum
 			outputTxNum.Add(1)
@@ -963,6 +1036,7 @@ Loop:
 				return err
 			}
 			ts += time.Since(start)
+			profiler.commitmentDone(time.Since(start))
 			aggTx.RestrictSubsetFileDeletions(false)
 			doms.SavePastChangesetAccumulator(b.Hash(), blockNum, changeset)
 			if !inMemExec {
@@ -972,6 +1046,7 @@ Loop:
 			}
 			doms.SetChangesetAccumulator(nil)
 		}
+		profiler.endBlock()
 
 		mxExecBlocks.Add(1)
 
@@ -1063,6 +1138,7 @@ Loop:
 						return err
 					}
 					doms.SetTxNum(inputTxNum)
+					profiler.setDomains(doms)
 					rs = state.NewStateV3(doms, logger)
 
 					applyWorker.ResetTx(applyTx)
@@ -1102,7 +1178,10 @@ Loop:
 		if err := rwLoopG.Wait(); err != nil {
 			return err
 		}
-		waitWorkers()
+		stopWorkers()
+		txCount, logGas = rs.DoneCount(), blockResult.gasUsed.Load()
+		// rwLoop did check state root and commit
+		return nil
 	}
 
 	if u != nil && !u.HasUnwindPoint() {
@@ -1185,7 +1264,12 @@ func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyT
 
 	// E2 state root check was in another stage - means we did flush state even if state root will not match
 	// And Unwind expecting it
+	var flushedBlock uint64
 	if !parallel {
+		var err error
+		if flushedBlock, err = e.ExecutionAt(applyTx); err != nil {
+			return false, err
+		}
 		if err := e.Update(applyTx, maxBlockNum); err != nil {
 			return false, err
 		}
@@ -1225,6 +1309,9 @@ func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyT
 		return true, nil
 	}
 	logger.Error(fmt.Sprintf("[%s] Wrong trie root of block %d: %x, expected (from header): %x. Block hash: %x", e.LogPrefix(), header.Number.Uint64(), rh, header.Root.Bytes(), header.Hash()))
+	if !parallel && !inMemExec {
+		recordBadBlock(ctx, applyTx, header, flushedBlock, rh, cfg, e.LogPrefix(), logger)
+	}
 	if cfg.badBlockHalt {
 		return false, errors.New("wrong trie root")
 	}
@@ -1285,7 +1372,69 @@ func blockWithSenders(ctx context.Context, db kv.RoDB, tx kv.Tx, blockReader ser
 	return b, err
 }
 
-func processResultQueue(ctx context.Context, in *state.QueueWithRetry, rws *state.ResultsQueue, outputTxNumIn uint64, rs *state.StateV3, agg *state2.Aggregator, applyTx kv.Tx, backPressure chan struct{}, applyWorker *exec3.Worker, canRetry, forceStopAtBlockEnd bool, isMining bool) (outputTxNum uint64, conflicts, triggers int, processedBlockNum uint64, stopedAtBlockEnd bool, err error) {
+// parallelBlockResult - block-level results of parallel execution. applyLoop does apply txs in-order,
+// so receipts and post-validation of block are done same way as in sequential execution.
+type parallelBlockResult struct {
+	blockNum           uint64
+	usedGas            uint64
+	blobGasUsed        uint64
+	skipPostEvaluation bool // first block may be partially executed before restart - can't validate gasUsed
+
+	checkReceipts   func(blockNum uint64) bool
+	invalidBlockNum uint64        // set when returned error is consensus.ErrInvalidBlock
+	gasUsed         atomic.Uint64 // for progress logs
+}
+
+func (b *parallelBlockResult) apply(txTask *state.TxTask, tx kv.Tx, doms *state2.SharedDomains, isMining bool) error {
+	if txTask.TxIndex == -1 || txTask.BlockNum != b.blockNum {
+		b.blockNum, b.usedGas, b.blobGasUsed = txTask.BlockNum, 0, 0
+		b.skipPostEvaluation = txTask.TxIndex != -1
+		if b.skipPostEvaluation {
+			var err error
+			if b.usedGas, b.blobGasUsed, _, err = rawtemporaldb.ReceiptAsOf(tx.(kv.TemporalTx), txTask.TxNum); err != nil {
+				return err
+			}
+		}
+	}
+
+	b.usedGas += txTask.UsedGas
+	b.gasUsed.Add(txTask.UsedGas)
+	mxExecGas.Add(float64(txTask.UsedGas))
+	mxExecTransactions.Add(1)
+	if txTask.Tx != nil {
+		b.blobGasUsed += txTask.Tx.GetBlobGas()
+	}
+
+	txTask.CreateReceipt(tx)
+
+	if txTask.Final {
+		if txTask.BlockNum > 0 && !b.skipPostEvaluation { //Disable check for genesis. Maybe need somehow improve it in future - to satisfy TestExecutionSpec
+			if err := core.BlockPostValidation(b.usedGas, b.blobGasUsed, b.checkReceipts(txTask.BlockNum), txTask.BlockReceipts, txTask.Header, isMining); err != nil {
+				b.invalidBlockNum = txTask.BlockNum
+				return fmt.Errorf("%w, txnIdx=%d, %v", consensus.ErrInvalidBlock, txTask.TxIndex, err) //same as in stage_exec.go
+			}
+		}
+		return nil
+	}
+
+	var receipt *types.Receipt
+	if txTask.TxIndex >= 0 {
+		receipt = txTask.BlockReceipts[txTask.TxIndex]
+	}
+	return rawtemporaldb.AppendReceipt(doms, receipt, b.blobGasUsed)
+}
+
+var errStateRootMismatch = errors.New("state root mismatch")
+
+// sendErr - non-blocking: errCh has buffer for 1 error and only first one matters
+func sendErr(errCh chan error, err error) {
+	select {
+	case errCh <- err:
+	default:
+	}
+}
+
+func processResultQueue(ctx context.Context, in *state.QueueWithRetry, rws *state.ResultsQueue, outputTxNumIn uint64, rs *state.StateV3, blockResult *parallelBlockResult, applyTx kv.Tx, backPressure chan struct{}, applyWorker *exec3.Worker, canRetry, forceStopAtBlockEnd bool, isMining bool) (outputTxNum uint64, conflicts, triggers int, processedBlockNum uint64, stopedAtBlockEnd bool, err error) {
 	rwsIt := rws.Iter()
 	defer rwsIt.Close()
 
@@ -1293,34 +1442,37 @@ func processResultQueue(ctx context.Context, in *state.QueueWithRetry, rws *stat
 	outputTxNum = outputTxNumIn
 	for rwsIt.HasNext(outputTxNum) {
 		txTask := rwsIt.PopNext()
-		if txTask.Error != nil || !rs.ReadsValid(txTask.ReadLists) {
-			conflicts++
+		rs.SetTxNum(txTask.TxNum, txTask.BlockNum)
+
+		// Final txn always executed here (workers skip it): block finalization needs receipts of all txs of block
+		conflicted := !txTask.Final && (txTask.Error != nil || txTask.FlushEpoch != rs.FlushEpoch() || !rs.ReadsValid(txTask.ReadLists))
+		if txTask.Final || conflicted {
+			if conflicted {
+				conflicts++
+			}
 
-			if i > 0 && canRetry {
+			if conflicted && i > 0 && canRetry {
 				//send to re-exex
 				rs.ReTry(txTask, in)
 				continue
 			}
 
 			// resolve first conflict right here: it's faster and conflict-free
-			applyWorker.RunTxTask(txTask, isMining)
+			applyWorker.RunTxTaskNoLock(txTask, isMining)
 			if txTask.Error != nil {
-				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("%w: %v", consensus.ErrInvalidBlock, txTask.Error)
+				blockResult.invalidBlockNum = txTask.BlockNum
+				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("%w: txnIdx=%d, %v", consensus.ErrInvalidBlock, txTask.TxIndex, txTask.Error)
+			}
+			if conflicted {
+				i++
 			}
-			// TODO: post-validation of gasUsed and blobGasUsed
-			i++
 		}
 
-		if txTask.Final {
-			rs.SetTxNum(txTask.TxNum, txTask.BlockNum)
-			err := rs.ApplyState4(ctx, txTask)
-			if err != nil {
-				return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("StateV3.Apply: %w", err)
-			}
-			//if !bytes.Equal(rh, txTask.BlockRoot[:]) {
-			//	log.Error("block hash mismatch", "rh", hex.EncodeToString(rh), "blockRoot", hex.EncodeToString(txTask.BlockRoot[:]), "bn", txTask.BlockNum, "txn", txTask.TxNum)
-			//	return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("block hashk mismatch: %x != %x bn =%d, txn= %d", rh, txTask.BlockRoot[:], txTask.BlockNum, txTask.TxNum)
-			//}
+		if err := blockResult.apply(txTask, applyTx, rs.Domains(), isMining); err != nil {
+			return outputTxNum, conflicts, triggers, processedBlockNum, false, err
+		}
+		if err := rs.ApplyState4(ctx, txTask); err != nil {
+			return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("StateV3.Apply: %w", err)
 		}
 		triggers += rs.CommitTxNum(txTask.Sender, txTask.TxNum, in)
 		outputTxNum++
@@ -1330,9 +1482,6 @@ func processResultQueue(ctx context.Context, in *state.QueueWithRetry, rws *stat
 			default:
 			}
 		}
-		if err := rs.ApplyLogsAndTraces4(txTask, rs.Domains()); err != nil {
-			return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("StateV3.Apply: %w", err)
-		}
 		processedBlockNum = txTask.BlockNum
 		stopedAtBlockEnd = txTask.Final
 		if forceStopAtBlockEnd && txTask.Final {
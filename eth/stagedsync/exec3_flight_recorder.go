@@ -0,0 +1,69 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/turbo/replay/flightrecorder"
+)
+
+// FlightRecorderDir - where ExecV3 writes recordings of blocks with wrong state root (relative to datadir)
+const FlightRecorderDir = "flight-recorder"
+
+// recordBadBlock - writes flight recording of block with wrong state root, for offline `evm replay`.
+// Recording needs state as of block start: it's read from `applyTx`, so recording is automatic only if parent of block
+// is the last flushed block - it's the case at chain tip, where each block's state root is checked.
+// SharedDomains keeps only latest values (not state as of each block of batch) and in batched execution state root is
+// checked once per batch: bad block may be any of batch, so only manual commands are logged for it.
+// Errors are logged: recording is best-effort and must not change behaviour of stage.
+func recordBadBlock(ctx context.Context, applyTx kv.RwTx, header *types.Header, flushedBlock uint64, root []byte, cfg ExecuteBlockCfg, logPrefix string, logger log.Logger) {
+	blockNum := header.Number.Uint64()
+	if blockNum != flushedBlock+1 {
+		logger.Warn(fmt.Sprintf("[%s] flight recorder: state root was checked for batch of blocks, records only at chain tip. Record blocks of batch manually", logPrefix),
+			"blocks", fmt.Sprintf("%d-%d", flushedBlock+1, blockNum),
+			"cmd", fmt.Sprintf("integration stage_exec --datadir=%[1]s --block=<N-1> && integration record_block --datadir=%[1]s --block=<N>", cfg.dirs.DataDir))
+		return
+	}
+	ttx, ok := applyTx.(kv.TemporalTx)
+	if !ok {
+		return
+	}
+	b, err := blockWithSenders(ctx, cfg.db, applyTx, cfg.blockReader, blockNum)
+	if err != nil || b == nil {
+		logger.Warn(fmt.Sprintf("[%s] flight recorder: reading block", logPrefix), "block", blockNum, "err", err)
+		return
+	}
+	reason := fmt.Sprintf("wrong trie root: %x, expected (from header): %x", root, header.Root)
+	rec, err := flightrecorder.RecordBlock(ctx, ttx, cfg.chainConfig, cfg.engine, cfg.blockReader, b, reason, logger)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("[%s] flight recorder: recording block", logPrefix), "block", blockNum, "err", err)
+		return
+	}
+	fname := filepath.Join(cfg.dirs.DataDir, FlightRecorderDir, fmt.Sprintf("%d-%x.json.gz", blockNum, header.Hash().Bytes()[:8]))
+	if err := rec.WriteFile(fname); err != nil {
+		logger.Warn(fmt.Sprintf("[%s] flight recorder: writing recording", logPrefix), "file", fname, "err", err)
+		return
+	}
+	logger.Warn(fmt.Sprintf("[%s] flight recorder: bad block recorded", logPrefix), "block", blockNum, "cmd", "evm replay "+fname)
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/erigontech/erigon-lib/diagnostics"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	state2 "github.com/erigontech/erigon-lib/state"
+
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+)
+
+// blockProfileTopTxs - amount of most expensive txs kept in profile
+const blockProfileTopTxs = 5
+
+// blockExecProfiler - records per-block profile of serial ExecV3: execution and commitment time, state reads, most expensive txs.
+// Profiles are sent to diagnostics client. Blocks slower than threshold are logged with txs breakdown.
+// nil profiler is valid and does nothing.
+type blockExecProfiler struct {
+	doms               *state2.SharedDomains
+	slowBlockThreshold time.Duration
+	logPrefix          string
+	logger             log.Logger
+
+	block      *types.Block
+	blockStart time.Time
+	readsStart state2.DomainReadStats
+	execTime   time.Duration
+	commitTime time.Duration
+	txs        []diagnostics.TxExecutionProfile
+}
+
+func newBlockExecProfiler(enabled bool, doms *state2.SharedDomains, slowBlockThreshold time.Duration, logPrefix string, logger log.Logger) *blockExecProfiler {
+	if !enabled {
+		return nil
+	}
+	p := &blockExecProfiler{slowBlockThreshold: slowBlockThreshold, logPrefix: logPrefix, logger: logger}
+	p.setDomains(doms)
+	return p
+}
+
+// setDomains - ExecV3 re-creates SharedDomains after each commit of batch
+func (p *blockExecProfiler) setDomains(doms *state2.SharedDomains) {
+	if p == nil {
+		return
+	}
+	doms.SetReadStats(true)
+	p.doms = doms
+}
+
+func (p *blockExecProfiler) startBlock(b *types.Block, start time.Time) {
+	if p == nil {
+		return
+	}
+	p.block, p.blockStart, p.readsStart = b, start, p.doms.ReadStats()
+	p.execTime, p.commitTime, p.txs = 0, 0, p.txs[:0]
+}
+
+// txDone - txTask was executed and applied, `start` is time when its execution started
+func (p *blockExecProfiler) txDone(txTask *state.TxTask, start time.Time) {
+	if p == nil {
+		return
+	}
+	took := time.Since(start)
+	p.execTime += took
+	tx := diagnostics.TxExecutionProfile{TxIndex: txTask.TxIndex, GasUsed: txTask.UsedGas, Time: took.Seconds()}
+	if txTask.Tx != nil {
+		tx.TxHash = txTask.Tx.Hash().String()
+	}
+	p.txs = append(p.txs, tx)
+}
+
+func (p *blockExecProfiler) commitmentDone(took time.Duration) {
+	if p == nil {
+		return
+	}
+	p.commitTime += took
+}
+
+func (p *blockExecProfiler) endBlock() {
+	if p == nil || p.block == nil {
+		return
+	}
+	total := time.Since(p.blockStart)
+	profile := diagnostics.BlockExecutionProfile{
+		BlockNumber:    p.block.NumberU64(),
+		BlockHash:      p.block.Hash().String(),
+		Time:           p.blockStart,
+		Txs:            len(p.block.Transactions()),
+		GasUsed:        p.block.GasUsed(),
+		TotalTime:      total.Seconds(),
+		ExecutionTime:  p.execTime.Seconds(),
+		CommitmentTime: p.commitTime.Seconds(),
+		Slow:           p.slowBlockThreshold > 0 && total > p.slowBlockThreshold,
+	}
+	if p.execTime > 0 {
+		profile.MgasPerSec = float64(p.block.GasUsed()) / 1e6 / p.execTime.Seconds()
+	}
+	reads := p.doms.ReadStats().Sub(p.readsStart)
+	for d := kv.Domain(0); d < kv.DomainLen; d++ {
+		if reads.Reads[d] == 0 {
+			continue
+		}
+		profile.Reads = append(profile.Reads, diagnostics.DomainReadsProfile{
+			Domain:   d.String(),
+			Reads:    reads.Reads[d],
+			HitRatio: float64(reads.ReadsFromRAM[d]) / float64(reads.Reads[d]),
+		})
+	}
+	sort.SliceStable(p.txs, func(i, j int) bool { return p.txs[i].Time > p.txs[j].Time })
+	profile.TopTxs = append(profile.TopTxs, p.txs[:min(len(p.txs), blockProfileTopTxs)]...)
+	diagnostics.Send(profile)
+
+	if profile.Slow {
+		p.logSlowBlock(profile, total)
+	}
+	p.block = nil
+}
+
+func (p *blockExecProfiler) logSlowBlock(profile diagnostics.BlockExecutionProfile, total time.Duration) {
+	args := []interface{}{"block", profile.BlockNumber, "hash", profile.BlockHash, "took", total,
+		"execution", time.Duration(profile.ExecutionTime * float64(time.Second)), "commitment", time.Duration(profile.CommitmentTime * float64(time.Second)),
+		"txs", profile.Txs, "gas", profile.GasUsed, "Mgas/s", fmt.Sprintf("%.1f", profile.MgasPerSec)}
+	for _, r := range profile.Reads {
+		args = append(args, "reads."+r.Domain, fmt.Sprintf("%d (%.0f%% RAM)", r.Reads, r.HitRatio*100))
+	}
+	p.logger.Warn(fmt.Sprintf("[%s] Slow block", p.logPrefix), args...)
+	for _, tx := range profile.TopTxs {
+		p.logger.Warn(fmt.Sprintf("[%s] Slow block: expensive txn", p.logPrefix), "block", profile.BlockNumber, "txIndex", tx.TxIndex, "hash", tx.TxHash,
+			"gas", tx.GasUsed, "took", time.Duration(tx.Time*float64(time.Second)))
+	}
+}
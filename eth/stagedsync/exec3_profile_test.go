@@ -0,0 +1,113 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/diagnostics"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/temporal/temporaltest"
+	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
+
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+)
+
+func TestBlockExecProfiler(t *testing.T) {
+	require.Nil(t, newBlockExecProfiler(false, nil, time.Second, "test", log.New()))
+
+	db, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	doms, err := libstate.NewSharedDomains(tx, log.New())
+	require.NoError(t, err)
+	defer doms.Close()
+
+	var mu sync.Mutex
+	var warns []string
+	logger := log.New()
+	logger.SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Lvl == log.LvlWarn {
+			mu.Lock()
+			warns = append(warns, r.Msg)
+			mu.Unlock()
+		}
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx, profiles, closeChannel := diagnostics.Context[diagnostics.BlockExecutionProfile](ctx, 4)
+	defer closeChannel()
+	diagnostics.StartProviders(ctx, diagnostics.TypeOf(diagnostics.BlockExecutionProfile{}), logger)
+
+	p := newBlockExecProfiler(true, doms, time.Second, "test", logger)
+	addr := libcommon.HexToAddress("0x01")
+	txn := types.NewTransaction(0, addr, uint256.NewInt(1), 21_000, uint256.NewInt(1), nil)
+	execute := func(blockNum uint64, took time.Duration) diagnostics.BlockExecutionProfile {
+		block := types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(blockNum), GasUsed: 21_000}, []types.Transaction{txn}, nil, nil, nil)
+		p.startBlock(block, time.Now().Add(-took))
+		_, _, err := doms.DomainGet(kv.AccountsDomain, addr[:], nil)
+		require.NoError(t, err)
+		p.txDone(&state.TxTask{TxIndex: -1}, time.Now())
+		p.txDone(&state.TxTask{TxIndex: 0, Tx: txn, UsedGas: 21_000}, time.Now().Add(-took/2))
+		p.commitmentDone(took / 4)
+		p.endBlock()
+		select {
+		case profile := <-profiles:
+			return profile
+		case <-time.After(time.Second):
+			t.Fatal("profile was not sent")
+		}
+		return diagnostics.BlockExecutionProfile{}
+	}
+
+	fast := execute(1, 0)
+	require.Equal(t, uint64(1), fast.BlockNumber)
+	require.False(t, fast.Slow)
+	require.Equal(t, 1, fast.Txs)
+	require.Equal(t, uint64(21_000), fast.GasUsed)
+	require.Equal(t, []diagnostics.DomainReadsProfile{{Domain: kv.AccountsDomain.String(), Reads: 1}}, fast.Reads)
+	require.Empty(t, warns)
+
+	slow := execute(2, 2*time.Second)
+	require.Equal(t, uint64(2), slow.BlockNumber)
+	require.True(t, slow.Slow)
+	require.GreaterOrEqual(t, slow.TotalTime, 2.0)
+	require.GreaterOrEqual(t, slow.ExecutionTime, 1.0)
+	require.Equal(t, 0.5, slow.CommitmentTime)
+	require.Positive(t, slow.MgasPerSec)
+	// most expensive txn first
+	require.Len(t, slow.TopTxs, 2)
+	require.Equal(t, 0, slow.TopTxs[0].TxIndex)
+	require.Equal(t, txn.Hash().String(), slow.TopTxs[0].TxHash)
+	require.Equal(t, -1, slow.TopTxs[1].TxIndex)
+	// slow block and each of its top txs are reported
+	require.Equal(t, []string{"[test] Slow block", "[test] Slow block: expensive txn", "[test] Slow block: expensive txn"}, warns)
+}
@@ -46,6 +46,7 @@ type CustomTraceCfg struct {
 	db       kv.RwDB
 	prune    prune.Mode
 	execArgs *exec3.ExecArgs
+	indexers []CustomIndexer
 }
 
 func StageCustomTraceCfg(db kv.RwDB, prune prune.Mode, dirs datadir.Dirs, br services.FullBlockReader, cc *chain.Config,
@@ -61,9 +62,11 @@ func StageCustomTraceCfg(db kv.RwDB, prune prune.Mode, dirs datadir.Dirs, br ser
 		Workers:     syncCfg.ExecWorkerCount,
 	}
 	return CustomTraceCfg{
+		tmpdir:   dirs.Tmp,
 		db:       db,
 		prune:    prune,
 		execArgs: execArgs,
+		indexers: CustomIndexers(),
 	}
 }
 
@@ -1328,6 +1328,22 @@ func (s polygonSyncStageBridgeStore) BlockEventIDsRange(context.Context, uint64)
 	panic("polygonSyncStageBridgeStore.BlockEventIDsRange not supported")
 }
 
+func (s polygonSyncStageBridgeStore) EventBlockNum(context.Context, uint64) (uint64, bool, error) {
+	// used in RPCs
+	// astrid stage integration intends to use the bridge only for scrapping,
+	// not for reading which remains the same in RPCs (via BlockReader)
+	// astrid standalone mode introduces its own reader
+	panic("polygonSyncStageBridgeStore.EventBlockNum not supported")
+}
+
+func (s polygonSyncStageBridgeStore) FirstEventID(context.Context) (uint64, bool, error) {
+	// used in RPCs
+	// astrid stage integration intends to use the bridge only for scrapping,
+	// not for reading which remains the same in RPCs (via BlockReader)
+	// astrid standalone mode introduces its own reader
+	panic("polygonSyncStageBridgeStore.FirstEventID not supported")
+}
+
 func (s polygonSyncStageBridgeStore) EventTxnToBlockNum(context.Context, common.Hash) (uint64, bool, error) {
 	// used in RPCs
 	// astrid stage integration intends to use the bridge only for scrapping,
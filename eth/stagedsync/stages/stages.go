@@ -19,6 +19,7 @@ package stages
 import (
 	"encoding/binary"
 	"fmt"
+	"time"
 
 	"github.com/erigontech/erigon-lib/kv"
 )
@@ -39,6 +40,7 @@ var (
 	Senders         SyncStage = "Senders"         // "From" recovered from signatures, bodies re-written
 	Execution       SyncStage = "Execution"       // Executing each block w/o building a trie
 	CustomTrace     SyncStage = "CustomTrace"     // Executing each block w/o building a trie
+	CustomIndexers  SyncStage = "CustomIndexers"  // User-defined indexers (see stagedsync.RegisterCustomIndexer) on top of historical re-execution
 	Translation     SyncStage = "Translation"     // Translation each marked for translation contract (from EVM to TEVM)
 	VerkleTrie      SyncStage = "VerkleTrie"
 	TxLookup        SyncStage = "TxLookup" // Generating transactions lookup index
@@ -66,6 +68,7 @@ var AllStages = []SyncStage{
 	Senders,
 	Execution,
 	CustomTrace,
+	CustomIndexers,
 	Translation,
 	TxLookup,
 	Finish,
@@ -96,10 +99,66 @@ func GetStagePruneProgress(db kv.Getter, stage SyncStage) (uint64, error) {
 	return unmarshalData(v)
 }
 
+// CustomIndexer - key of progress of user-defined indexer (see stagedsync.RegisterCustomIndexer) in kv.SyncStageProgress
+func CustomIndexer(name string) SyncStage {
+	return CustomIndexers + "." + SyncStage(name)
+}
+
 func SaveStagePruneProgress(db kv.Putter, stage SyncStage, progress uint64) error {
 	return db.Put(kv.SyncStageProgress, []byte("prune_"+stage), encodeBigEndian(progress))
 }
 
+// StageTiming - cumulative amount of blocks processed by stage and time it took, across all sync cycles.
+// Used to estimate duration of future runs (see `integration stage_* --dry-run`).
+type StageTiming struct {
+	Blocks uint64
+	Took   time.Duration
+}
+
+// Estimate - expected duration of processing `blocks` blocks, 0 if there is no history yet
+func (t StageTiming) Estimate(blocks uint64) time.Duration {
+	if t.Blocks == 0 {
+		return 0
+	}
+	return time.Duration(float64(t.Took) / float64(t.Blocks) * float64(blocks))
+}
+
+func stageTimingKey(stage SyncStage, unwind bool) []byte {
+	if unwind {
+		return []byte("unwind_timing_" + stage)
+	}
+	return []byte("timing_" + stage)
+}
+
+// GetStageTiming retrieves saved forward (or unwind) timing of given sync stage from the database
+func GetStageTiming(db kv.Getter, stage SyncStage, unwind bool) (StageTiming, error) {
+	v, err := db.GetOne(kv.SyncStageProgress, stageTimingKey(stage, unwind))
+	if err != nil {
+		return StageTiming{}, err
+	}
+	if len(v) == 0 {
+		return StageTiming{}, nil
+	}
+	if len(v) != 16 {
+		return StageTiming{}, fmt.Errorf("timing value must be 16 bytes, got %d", len(v))
+	}
+	return StageTiming{Blocks: binary.BigEndian.Uint64(v[:8]), Took: time.Duration(binary.BigEndian.Uint64(v[8:]))}, nil
+}
+
+// AddStageTiming adds `blocks` and `took` to saved timing of given sync stage
+func AddStageTiming(db kv.GetPut, stage SyncStage, unwind bool, blocks uint64, took time.Duration) error {
+	t, err := GetStageTiming(db, stage, unwind)
+	if err != nil {
+		return err
+	}
+	t.Blocks += blocks
+	t.Took += took
+	var v [16]byte
+	binary.BigEndian.PutUint64(v[:8], t.Blocks)
+	binary.BigEndian.PutUint64(v[8:], uint64(t.Took))
+	return db.Put(kv.SyncStageProgress, stageTimingKey(stage, unwind), v[:])
+}
+
 func unmarshalData(data []byte) (uint64, error) {
 	if len(data) == 0 {
 		return 0, nil
@@ -46,11 +46,17 @@ type Sync struct {
 	pruningOrder  []*Stage
 	currentStage  uint
 	timings       []Timing
+	unsaved       map[stageTimingKey]stages.StageTiming // see saveTiming
 	logPrefixes   []string
 	logger        log.Logger
 	stagesIdsList []string
 }
 
+type stageTimingKey struct {
+	stage  stages.SyncStage
+	unwind bool
+}
+
 type Timing struct {
 	isUnwind bool
 	isPrune  bool
@@ -542,7 +548,7 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, txc wrap.TxContainer, initialC
 		s.logger.Debug(fmt.Sprintf("[%s] DONE", logPrefix), "in", took)
 	}
 	s.timings = append(s.timings, Timing{stage: stage.ID, took: took})
-	return nil
+	return s.saveTiming(db, txc.Tx, stage.ID, false, stageState.BlockNumber, took)
 }
 
 func (s *Sync) unwindStage(initialCycle bool, stage *Stage, db kv.RwDB, txc wrap.TxContainer) error {
@@ -574,6 +580,54 @@ func (s *Sync) unwindStage(initialCycle bool, stage *Stage, db kv.RwDB, txc wrap
 		s.logger.Info(fmt.Sprintf("[%s] Unwind done", logPrefix), "in", took)
 	}
 	s.timings = append(s.timings, Timing{isUnwind: true, stage: stage.ID, took: took})
+	return s.saveTiming(db, txc.Tx, stage.ID, true, stageState.BlockNumber, took)
+}
+
+// saveTiming - accumulates persistent timing of stage (see stages.StageTiming), if stage progress moved from `before`.
+// Timings are written only into tx of sync cycle: when stages commit own txs (initial sync) they are kept in RAM until
+// next cycle with tx.
+func (s *Sync) saveTiming(db kv.RwDB, tx kv.RwTx, stage stages.SyncStage, unwind bool, before uint64, took time.Duration) error {
+	if tx == nil && db == nil {
+		return nil
+	}
+	var after uint64
+	var err error
+	if tx != nil {
+		after, err = stages.GetStageProgress(tx, stage)
+	} else {
+		err = db.View(context.Background(), func(tx kv.Tx) error {
+			after, err = stages.GetStageProgress(tx, stage)
+			return err
+		})
+	}
+	if err != nil {
+		return err
+	}
+	var blocks uint64
+	if !unwind && after > before {
+		blocks = after - before
+	} else if unwind && before > after {
+		blocks = before - after
+	}
+	if blocks > 0 {
+		if s.unsaved == nil {
+			s.unsaved = map[stageTimingKey]stages.StageTiming{}
+		}
+		key := stageTimingKey{stage: stage, unwind: unwind}
+		t := s.unsaved[key]
+		t.Blocks += blocks
+		t.Took += took
+		s.unsaved[key] = t
+	}
+	if tx == nil {
+		return nil
+	}
+	for key, t := range s.unsaved {
+		if err := stages.AddStageTiming(tx, key.stage, key.unwind, t.Blocks, t.Took); err != nil {
+			return err
+		}
+		delete(s.unsaved, key)
+	}
 	return nil
 }
 
@@ -17,12 +17,15 @@
 package stagedsync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/memdb"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/wrap"
@@ -458,6 +461,90 @@ func TestSyncDoTwice(t *testing.T) {
 	assert.Equal(t, 600, int(stageState.BlockNumber))
 }
 
+func TestStageTimings(t *testing.T) {
+	s := []*Stage{
+		{
+			ID:          stages.Headers,
+			Description: "Downloading headers",
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return s.Update(txc.Tx, s.BlockNumber+100)
+			},
+			Unwind: func(u *UnwindState, s *StageState, txc wrap.TxContainer, logger log.Logger) error {
+				return u.Done(txc.Tx)
+			},
+		},
+		{
+			ID:          stages.Bodies,
+			Description: "Downloading block bodiess",
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				return nil
+			},
+		},
+	}
+
+	state := New(ethconfig.Defaults.Sync, s, []stages.SyncStage{s[1].ID, s[0].ID}, nil, log.New())
+	db, tx := memdb.NewTestTx(t)
+	_, err := state.Run(db, wrap.TxContainer{Tx: tx}, true /* initialCycle */, false)
+	assert.NoError(t, err)
+	_, err = state.Run(db, wrap.TxContainer{Tx: tx}, true /* initialCycle */, false)
+	assert.NoError(t, err)
+
+	timing, err := stages.GetStageTiming(tx, stages.Headers, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, int(timing.Blocks))
+
+	// stage which didn't move has no timing
+	timing, err = stages.GetStageTiming(tx, stages.Bodies, false)
+	assert.NoError(t, err)
+	assert.Equal(t, stages.StageTiming{}, timing)
+
+	_ = state.UnwindTo(50, UnwindReason{}, tx)
+	assert.NoError(t, state.RunUnwind(db, wrap.TxContainer{Tx: tx}))
+	timing, err = stages.GetStageTiming(tx, stages.Headers, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 150, int(timing.Blocks))
+
+	assert.Equal(t, 3*time.Second, stages.StageTiming{Blocks: 100, Took: time.Second}.Estimate(300))
+	assert.Equal(t, time.Duration(0), stages.StageTiming{}.Estimate(300))
+}
+
+func TestStageTimingsWithoutTx(t *testing.T) {
+	var db kv.RwDB
+	s := []*Stage{
+		{
+			ID:          stages.Headers,
+			Description: "Downloading headers",
+			Forward: func(badBlockUnwind bool, s *StageState, u Unwinder, txc wrap.TxContainer, logger log.Logger) error {
+				if txc.Tx != nil {
+					return s.Update(txc.Tx, s.BlockNumber+100)
+				}
+				return db.Update(context.Background(), func(tx kv.RwTx) error { return s.Update(tx, s.BlockNumber+100) })
+			},
+		},
+	}
+	state := New(ethconfig.Defaults.Sync, s, nil, nil, log.New())
+	db = memdb.NewTestDB(t)
+
+	// stage commits own tx: sync doesn't open RwTx to save timing
+	_, err := state.Run(db, wrap.TxContainer{}, true /* initialCycle */, false)
+	assert.NoError(t, err)
+	assert.NoError(t, db.View(context.Background(), func(tx kv.Tx) error {
+		timing, err := stages.GetStageTiming(tx, stages.Headers, false)
+		assert.Equal(t, stages.StageTiming{}, timing)
+		return err
+	}))
+
+	// saved by next cycle with tx
+	tx, err := db.BeginRw(context.Background())
+	assert.NoError(t, err)
+	defer tx.Rollback()
+	_, err = state.Run(db, wrap.TxContainer{Tx: tx}, false /* initialCycle */, false)
+	assert.NoError(t, err)
+	timing, err := stages.GetStageTiming(tx, stages.Headers, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, int(timing.Blocks))
+}
+
 func TestStateSyncInterruptRestart(t *testing.T) {
 	flow := make([]stages.SyncStage, 0)
 	expectedErr := errors.New("interrupt")
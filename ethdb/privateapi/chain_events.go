@@ -0,0 +1,135 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package privateapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/holiman/uint256"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/erigontech/erigon-lib/gointerfaces"
+	remote "github.com/erigontech/erigon-lib/gointerfaces/remoteproto"
+	types2 "github.com/erigontech/erigon-lib/gointerfaces/typesproto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/turbo/chainevents"
+	"github.com/erigontech/erigon/turbo/shards"
+)
+
+// ChainEventsAPIVersion
+// 1.0.0 - initial version
+var ChainEventsAPIVersion = &types2.VersionReply{Major: 1, Minor: 0, Patch: 0}
+
+type ChainEventsServer struct {
+	remote.UnimplementedChainEventsServer // must be embedded to have forward compatible implementations.
+
+	ctx    context.Context
+	db     kv.RoDB
+	events *shards.Events
+	reader *chainevents.Reader
+	logger log.Logger
+}
+
+func NewChainEventsServer(ctx context.Context, db kv.RoDB, events *shards.Events, reader *chainevents.Reader, logger log.Logger) *ChainEventsServer {
+	return &ChainEventsServer{ctx: ctx, db: db, events: events, reader: reader, logger: logger}
+}
+
+func (s *ChainEventsServer) Version(context.Context, *emptypb.Empty) (*types2.VersionReply, error) {
+	return ChainEventsAPIVersion, nil
+}
+
+func (s *ChainEventsServer) Subscribe(req *remote.ChainEventsRequest, srv remote.ChainEvents_SubscribeServer) (err error) {
+	// subscribe before catching up - to not miss blocks produced meanwhile
+	ch, clean := s.events.AddHeaderSubscription()
+	defer clean()
+
+	cursor := chainevents.Cursor{BlockNumber: req.FromBlockNumber}
+	if req.FromBlockHash != nil {
+		cursor.BlockHash = gointerfaces.ConvertH256ToHash(req.FromBlockHash)
+	}
+	opts := chainevents.Options{WithReceipts: req.WithReceipts, WithStateDiff: req.WithStateDiff}
+	s.logger.Info("new subscription to chain events established", "fromBlock", cursor.BlockNumber)
+	defer func() {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Warn("subscription to chain events closed", "reason", err)
+		}
+	}()
+
+	send := func(ev *chainevents.Event) error {
+		reply, err := chainEventToProto(ev)
+		if err != nil {
+			return err
+		}
+		return srv.Send(reply)
+	}
+	for {
+		if cursor, err = s.reader.CatchUp(srv.Context(), s.db, cursor, opts, send); err != nil {
+			return err
+		}
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case <-ch:
+		}
+	}
+}
+
+func chainEventToProto(ev *chainevents.Event) (*remote.ChainEvent, error) {
+	reply := &remote.ChainEvent{
+		Direction:   remote.Direction_FORWARD,
+		BlockNumber: uint64(ev.BlockNumber),
+		BlockHash:   gointerfaces.ConvertHashToH256(ev.BlockHash),
+		ParentHash:  gointerfaces.ConvertHashToH256(ev.ParentHash),
+	}
+	if ev.Type == chainevents.Revert {
+		reply.Direction = remote.Direction_UNWIND
+	}
+	var buf bytes.Buffer
+	for i := range ev.Receipts {
+		buf.Reset()
+		ev.Receipts.EncodeIndex(i, &buf)
+		reply.Receipts = append(reply.Receipts, bytes.Clone(buf.Bytes()))
+	}
+	for _, acc := range ev.Accounts {
+		balance, overflow := uint256.FromBig(acc.Balance.ToInt())
+		if overflow {
+			return nil, errors.New("balance overflow")
+		}
+		reply.Accounts = append(reply.Accounts, &remote.AccountDiff{
+			Address:  gointerfaces.ConvertAddressToH160(acc.Address),
+			Deleted:  acc.Deleted,
+			Nonce:    uint64(acc.Nonce),
+			Balance:  gointerfaces.ConvertUint256IntToH256(balance),
+			CodeHash: gointerfaces.ConvertHashToH256(acc.CodeHash),
+			Code:     acc.Code,
+		})
+	}
+	for _, st := range ev.Storage {
+		reply.Storage = append(reply.Storage, &remote.StorageDiff{
+			Address:  gointerfaces.ConvertAddressToH160(st.Address),
+			Location: gointerfaces.ConvertHashToH256(st.Location),
+			Value:    st.Value,
+		})
+	}
+	return reply, nil
+}
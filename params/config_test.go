@@ -35,6 +35,7 @@ func TestCheckCompatible(t *testing.T) {
 	type test struct {
 		stored, new *chain.Config
 		head        uint64
+		headTime    uint64
 		wantErr     *chain.ConfigCompatError
 	}
 	tests := []test{
@@ -96,16 +97,71 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     30,
 			},
 		},
+		{
+			stored:   &chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "p", Address: common.HexToAddress("0x0100"), Time: big.NewInt(1000)}}},
+			new:      &chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "p", Address: common.HexToAddress("0x0100"), Time: big.NewInt(2000)}}},
+			head:     10,
+			headTime: 500,
+			wantErr:  nil,
+		},
+		{
+			stored:   &chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "p", Address: common.HexToAddress("0x0100"), Time: big.NewInt(1000)}}},
+			new:      &chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "p", Address: common.HexToAddress("0x0100"), Time: big.NewInt(2000)}}},
+			head:     10,
+			headTime: 1500,
+			wantErr: &chain.ConfigCompatError{
+				What:         "custom precompile p timestamp",
+				StoredConfig: big.NewInt(1000),
+				NewConfig:    big.NewInt(2000),
+				RewindToTime: 999,
+			},
+		},
+		{
+			stored: &chain.Config{CustomPrecompiles: []chain.CustomPrecompile{{Name: "p", Address: common.HexToAddress("0x0100"), Block: big.NewInt(5)}}},
+			new:    &chain.Config{},
+			head:   10,
+			wantErr: &chain.ConfigCompatError{
+				What:         "custom precompile p block",
+				StoredConfig: big.NewInt(5),
+				NewConfig:    nil,
+				RewindTo:     4,
+			},
+		},
 	}
 
 	for _, test := range tests {
-		err := test.stored.CheckCompatible(test.new, test.head)
+		err := test.stored.CheckCompatible(test.new, test.head, test.headTime)
 		if !reflect.DeepEqual(err, test.wantErr) {
 			t.Errorf("error mismatch:\nstored: %v\nnew: %v\nhead: %v\nerr: %v\nwant: %v", test.stored, test.new, test.head, err, test.wantErr)
 		}
 	}
 }
 
+func TestCheckCustomPrecompiles(t *testing.T) {
+	addr, other := common.HexToAddress("0x0100"), common.HexToAddress("0x0101")
+	tests := []struct {
+		name    string
+		custom  []chain.CustomPrecompile
+		wantErr bool
+	}{
+		{name: "by block and time", custom: []chain.CustomPrecompile{{Name: "p", Address: addr, Block: big.NewInt(5)}, {Name: "q", Address: other, Time: big.NewInt(1000)}}},
+		{name: "not scheduled", custom: []chain.CustomPrecompile{{Name: "p", Address: addr}}, wantErr: true},
+		{name: "scheduled twice", custom: []chain.CustomPrecompile{{Name: "p", Address: addr, Block: big.NewInt(5), Time: big.NewInt(1000)}}, wantErr: true},
+		{name: "duplicate address", custom: []chain.CustomPrecompile{{Name: "p", Address: addr, Block: big.NewInt(5)}, {Name: "q", Address: addr, Block: big.NewInt(10)}}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := *TestChainConfig
+			config.CustomPrecompiles = test.custom
+			if test.wantErr {
+				require.Error(t, config.CheckConfigForkOrder())
+			} else {
+				require.NoError(t, config.CheckConfigForkOrder())
+			}
+		})
+	}
+}
+
 func TestGetBurntContract(t *testing.T) {
 	// Ethereum
 	assert.Nil(t, MainnetChainConfig.GetBurntContract(0))
@@ -1481,6 +1481,51 @@ func (c *Bor) GetRootHash(ctx context.Context, tx kv.Tx, start, end uint64) (str
 }
 
 func ComputeHeadersRootHash(blockHeaders []*types.Header) ([]byte, error) {
+	tree, err := headersMerkleTree(blockHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.Root().Hash, nil
+}
+
+// ComputeHeadersRootHashProof returns the root hash of blockHeaders (as in ComputeHeadersRootHash) along with
+// the merkle leaf of the header at index and its proof - the sibling hashes on the path from the leaf to the root.
+func ComputeHeadersRootHashProof(blockHeaders []*types.Header, index int) (root []byte, leaf []byte, proof [][]byte, err error) {
+	if index < 0 || index >= len(blockHeaders) {
+		return nil, nil, nil, fmt.Errorf("header index %d out of range [0, %d)", index, len(blockHeaders))
+	}
+
+	tree, err := headersMerkleTree(blockHeaders)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	i := index
+	for level := len(tree.Levels) - 1; level > 0; level-- {
+		proof = append(proof, tree.Levels[level][i^1].Hash)
+		i /= 2
+	}
+
+	return tree.Root().Hash, tree.Levels[len(tree.Levels)-1][index].Hash, proof, nil
+}
+
+// VerifyHeadersRootHashProof checks a proof returned by ComputeHeadersRootHashProof
+func VerifyHeadersRootHashProof(leaf []byte, index uint64, root []byte, proof [][]byte) bool {
+	hash := leaf
+	for _, sibling := range proof {
+		if index%2 == 0 {
+			hash = crypto.Keccak256(hash, sibling)
+		} else {
+			hash = crypto.Keccak256(sibling, hash)
+		}
+		index /= 2
+	}
+
+	return index == 0 && bytes.Equal(hash, root)
+}
+
+func headersMerkleTree(blockHeaders []*types.Header) (*merkle.Tree, error) {
 	headers := make([][32]byte, NextPowerOfTwo(uint64(len(blockHeaders))))
 	for i := 0; i < len(blockHeaders); i++ {
 		blockHeader := blockHeaders[i]
@@ -1500,7 +1545,7 @@ func ComputeHeadersRootHash(blockHeaders []*types.Header) ([]byte, error) {
 		return nil, err
 	}
 
-	return tree.Root().Hash, nil
+	return &tree, nil
 }
 
 func (c *Bor) getHeaderByNumber(ctx context.Context, tx kv.Tx, number uint64) (*types.Header, error) {
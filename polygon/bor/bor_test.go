@@ -514,3 +514,30 @@ func TestSendBlock(t *testing.T) {
 		t.Fatal(err)
 	}
 */
+
+func TestComputeHeadersRootHashProof(t *testing.T) {
+	headers := make([]*types.Header, 5)
+	for i := range headers {
+		headers[i] = &types.Header{
+			Number:      big.NewInt(int64(100 + i)),
+			Time:        uint64(1000 + 2*i),
+			TxHash:      libcommon.BigToHash(big.NewInt(int64(i))),
+			ReceiptHash: libcommon.BigToHash(big.NewInt(int64(10 + i))),
+		}
+	}
+
+	wantRoot, err := bor.ComputeHeadersRootHash(headers)
+	require.NoError(t, err)
+
+	for i := range headers {
+		root, leaf, proof, err := bor.ComputeHeadersRootHashProof(headers, i)
+		require.NoError(t, err)
+		require.Equal(t, wantRoot, root)
+		require.Len(t, proof, 3) // 5 headers are padded to 8 leaves
+		require.True(t, bor.VerifyHeadersRootHashProof(leaf, uint64(i), root, proof))
+		require.False(t, bor.VerifyHeadersRootHashProof(leaf, uint64((i+1)%len(headers)), root, proof))
+	}
+
+	_, _, _, err = bor.ComputeHeadersRootHashProof(headers, len(headers))
+	require.Error(t, err)
+}
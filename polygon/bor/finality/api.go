@@ -24,40 +24,32 @@ import (
 	"github.com/erigontech/erigon/polygon/bor/finality/whitelist"
 )
 
+// GetFinalizedBlockNumber returns the end block of the latest whitelisted milestone or checkpoint,
+// whichever is higher, as long as it is part of the local canonical chain. Returns 0 if there is none.
 func GetFinalizedBlockNumber(tx kv.Tx) uint64 {
-	currentBlockNum := rawdb.ReadCurrentHeader(tx)
+	currentHeader := rawdb.ReadCurrentHeader(tx)
+	if currentHeader == nil {
+		return 0
+	}
 
 	service := whitelist.GetWhitelistingService()
 
-	doExist, number, hash := service.GetWhitelistedMilestone()
-	if doExist && number <= currentBlockNum.Number.Uint64() {
-
-		blockHeader := rawdb.ReadHeaderByNumber(tx, number)
-
-		if blockHeader == nil {
-			return 0
-		}
-
-		if blockHeader.Hash() == hash {
-			return number
+	var finalized uint64
+	for _, whitelisted := range []func() (bool, uint64, common.Hash){service.GetWhitelistedMilestone, service.GetWhitelistedCheckpoint} {
+		doExist, number, hash := whitelisted()
+		if !doExist || number > currentHeader.Number.Uint64() || number <= finalized {
+			continue
 		}
-	}
-
-	doExist, number, hash = service.GetWhitelistedCheckpoint()
-	if doExist && number <= currentBlockNum.Number.Uint64() {
 
 		blockHeader := rawdb.ReadHeaderByNumber(tx, number)
-
-		if blockHeader == nil {
-			return 0
+		if blockHeader == nil || blockHeader.Hash() != hash {
+			continue
 		}
 
-		if blockHeader.Hash() == hash {
-			return number
-		}
+		finalized = number
 	}
 
-	return 0
+	return finalized
 }
 
 // CurrentFinalizedBlock retrieves the current finalized block of the canonical
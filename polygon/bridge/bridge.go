@@ -400,6 +400,14 @@ func (b *Bridge) EventTxnLookup(ctx context.Context, borTxHash libcommon.Hash) (
 	return b.reader.EventTxnLookup(ctx, borTxHash)
 }
 
+func (b *Bridge) EventsByIdRange(ctx context.Context, fromID, toID uint64) ([]*heimdall.EventRecordWithTime, error) {
+	return b.reader.EventsByIdRange(ctx, fromID, toID)
+}
+
+func (b *Bridge) EventBlockNum(ctx context.Context, eventID uint64) (uint64, bool, error) {
+	return b.reader.EventBlockNum(ctx, eventID)
+}
+
 func (b *Bridge) blockEventsTimeWindowEnd(last ProcessedBlockInfo, blockNum uint64, blockTime uint64) (uint64, error) {
 	if b.borConfig.IsIndore(blockNum) {
 		stateSyncDelay := b.borConfig.CalculateStateSyncDelay(blockNum)
@@ -12,10 +12,13 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/core/types"
 	"github.com/erigontech/erigon/polygon/bor/borcfg"
 	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/polygon/polygoncommon"
 	"github.com/erigontech/erigon/turbo/testlog"
 )
 
@@ -187,6 +190,25 @@ func TestBridge(t *testing.T) {
 	require.Equal(t, len(res), 0)
 	require.NoError(t, err)
 
+	records, err := b.EventsByIdRange(ctx, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, uint64(2), records[0].ID)
+	require.Equal(t, event2.Data, records[0].Data)
+	require.Equal(t, uint64(3), records[1].ID)
+	require.Equal(t, event3.Time.Unix(), records[1].Time.Unix())
+
+	for eventID, wantBlockNum := range map[uint64]uint64{1: 4, 2: 4, 3: 6, 4: 10} {
+		blockNum, ok, err := b.EventBlockNum(ctx, eventID)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, wantBlockNum, blockNum)
+	}
+
+	_, ok, err := b.EventBlockNum(ctx, 5)
+	require.NoError(t, err)
+	require.False(t, ok)
+
 	// check block 0
 	res, err = b.Events(ctx, 0)
 	require.Equal(t, len(res), 0)
@@ -429,3 +451,49 @@ func TestBridge_ProcessNewBlocksWithZeroOverride(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestReader_FrozenEvents(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	store := NewStore(polygoncommon.NewDatabase(t.TempDir(), kv.PolygonBridgeDB, databaseTablesCfg, logger, false, 1))
+	require.NoError(t, store.Prepare(ctx))
+	t.Cleanup(store.Close)
+
+	var events []*heimdall.EventRecordWithTime
+	for id := uint64(1); id <= 4; id++ {
+		events = append(events, &heimdall.EventRecordWithTime{
+			EventRecord: heimdall.EventRecord{ID: id, ChainID: "80002"},
+			Time:        time.Unix(int64(id), 0),
+		})
+	}
+	require.NoError(t, store.PutEvents(ctx, events))
+	require.NoError(t, store.PutBlockNumToEventID(ctx, map[uint64]uint64{4: 2, 6: 3, 10: 4}))
+	r := NewReader(store, logger, defaultBorConfig.StateReceiverContractAddress())
+
+	records, err := r.EventsByIdRange(ctx, 0, 4)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+	// prune the events of block 4 as done after freezing them into snapshots
+	tx, err := store.db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	for _, k := range []uint64{1, 2} {
+		require.NoError(t, tx.Delete(kv.BorEvents, hexutility.EncodeTs(k)))
+	}
+	require.NoError(t, tx.Delete(kv.BorEventNums, hexutility.EncodeTs(4)))
+	require.NoError(t, tx.Commit())
+
+	_, err = r.EventsByIdRange(ctx, 2, 4)
+	require.ErrorIs(t, err, ErrEventFrozen)
+	_, _, err = r.EventBlockNum(ctx, 2)
+	require.ErrorIs(t, err, ErrEventFrozen)
+
+	records, err = r.EventsByIdRange(ctx, 3, 4)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	blockNum, ok, err := r.EventBlockNum(ctx, 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(6), blockNum)
+}
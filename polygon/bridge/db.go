@@ -53,6 +53,9 @@ var databaseTablesCfg = kv.TableCfg{
 
 var ErrEventIDRangeNotFound = errors.New("event id range not found")
 
+// ErrEventFrozen is returned for events which have been pruned from the store after being frozen into snapshots
+var ErrEventFrozen = errors.New("event is in frozen range")
+
 type Store interface {
 	Prepare(ctx context.Context) error
 	Close()
@@ -66,6 +69,8 @@ type Store interface {
 	Events(ctx context.Context, start, end uint64) ([][]byte, error)
 	PutBlockNumToEventID(ctx context.Context, blockNumToEventId map[uint64]uint64) error
 	BlockEventIDsRange(ctx context.Context, blockNum uint64) (start uint64, end uint64, err error) // [start,end)
+	EventBlockNum(ctx context.Context, eventID uint64) (uint64, bool, error)
+	FirstEventID(ctx context.Context) (uint64, bool, error)
 	LastProcessedBlockInfo(ctx context.Context) (ProcessedBlockInfo, bool, error)
 	PutProcessedBlockInfo(ctx context.Context, info ProcessedBlockInfo) error
 	LastFrozenEventBlockNum() uint64
@@ -442,6 +447,108 @@ func (s *MdbxStore) BlockEventIDsRange(ctx context.Context, blockNum uint64) (ui
 	return start, end, nil
 }
 
+// EventBlockNum gets the number of the block in which the event with the given ID was committed.
+// Returns false if the event has not been committed to a processed block yet.
+func (s *MdbxStore) FirstEventID(ctx context.Context) (uint64, bool, error) {
+	tx, err := s.db.BeginRo(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	return FirstEventID(tx)
+}
+
+// FirstEventID returns the lowest event ID in BorEvents.
+// Events with lower IDs have been pruned after being frozen into snapshots.
+func FirstEventID(tx kv.Tx) (uint64, bool, error) {
+	cursor, err := tx.Cursor(kv.BorEvents)
+	if err != nil {
+		return 0, false, err
+	}
+	defer cursor.Close()
+
+	k, _, err := cursor.First()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(k) == 0 {
+		return 0, false, nil
+	}
+
+	return binary.BigEndian.Uint64(k), true, nil
+}
+
+func (s *MdbxStore) EventBlockNum(ctx context.Context, eventID uint64) (uint64, bool, error) {
+	tx, err := s.db.BeginRo(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	return EventBlockNum(tx, eventID)
+}
+
+// EventBlockNum binary searches BorEventNums for the first block whose last event ID is >= eventID.
+// This relies on the last event IDs growing monotonically with block numbers.
+// ErrEventFrozen is returned if the event and its block entry have been pruned.
+func EventBlockNum(tx kv.Tx, eventID uint64) (uint64, bool, error) {
+	firstID, ok, err := FirstEventID(tx)
+	if err != nil {
+		return 0, false, err
+	}
+	if ok && eventID > 0 && eventID < firstID {
+		return 0, false, fmt.Errorf("%w: %d < %d", ErrEventFrozen, eventID, firstID)
+	}
+
+	cursor, err := tx.Cursor(kv.BorEventNums)
+	if err != nil {
+		return 0, false, err
+	}
+	defer cursor.Close()
+
+	firstK, _, err := cursor.First()
+	if err != nil {
+		return 0, false, err
+	}
+	lastK, lastV, err := cursor.Last()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(firstK) == 0 || binary.BigEndian.Uint64(lastV) < eventID {
+		return 0, false, nil
+	}
+
+	k := make([]byte, 8)
+	lo, hi := binary.BigEndian.Uint64(firstK), binary.BigEndian.Uint64(lastK)
+	blockNum := hi
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+		binary.BigEndian.PutUint64(k, mid)
+
+		// there is always an entry >= mid since mid <= last
+		foundK, foundV, err := cursor.Seek(k)
+		if err != nil {
+			return 0, false, err
+		}
+
+		found := binary.BigEndian.Uint64(foundK)
+		if binary.BigEndian.Uint64(foundV) < eventID {
+			lo = found + 1
+			continue
+		}
+
+		// no entries in [mid, found), so the answer is either found or below mid
+		blockNum = min(blockNum, found)
+		if mid == 0 {
+			break
+		}
+		hi = mid - 1
+	}
+
+	return blockNum, true, nil
+}
+
 // Unwind deletes unwindable bridge data.
 // The blockNum parameter is exclusive, i.e. only data in the range (blockNum, last] is deleted.
 func (s *MdbxStore) Unwind(ctx context.Context, blockNum uint64) error {
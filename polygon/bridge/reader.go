@@ -17,6 +17,7 @@ import (
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/heimdall"
 	"github.com/erigontech/erigon/polygon/polygoncommon"
 	"github.com/erigontech/erigon/rlp"
 )
@@ -100,6 +101,45 @@ func (r *Reader) EventTxnLookup(ctx context.Context, borTxHash libcommon.Hash) (
 	return r.store.EventTxnToBlockNum(ctx, borTxHash)
 }
 
+// EventsByIdRange returns the decoded sync events with IDs in [fromID, toID].
+// ErrEventFrozen is returned if part of the range has been pruned after being frozen into snapshots.
+func (r *Reader) EventsByIdRange(ctx context.Context, fromID, toID uint64) ([]*heimdall.EventRecordWithTime, error) {
+	if toID < fromID {
+		return nil, nil
+	}
+
+	firstID, ok, err := r.store.FirstEventID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok && max(fromID, 1) < firstID {
+		return nil, fmt.Errorf("%w: %d < %d", ErrEventFrozen, fromID, firstID)
+	}
+
+	eventsRaw, err := r.store.Events(ctx, fromID, toID+1)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*heimdall.EventRecordWithTime, 0, len(eventsRaw))
+	for _, eventRaw := range eventsRaw {
+		var event heimdall.EventRecordWithTime
+		if err := event.UnmarshallBytes(eventRaw); err != nil {
+			return nil, err
+		}
+
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// EventBlockNum returns the number of the block in which the sync event with eventID was committed.
+// ErrEventFrozen is returned if the event has been pruned after being frozen into snapshots.
+func (r *Reader) EventBlockNum(ctx context.Context, eventID uint64) (uint64, bool, error) {
+	return r.store.EventBlockNum(ctx, eventID)
+}
+
 func (r *Reader) Close() {
 	r.store.Close()
 }
@@ -21,6 +21,7 @@ import (
 
 	libcommon "github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/heimdall"
 )
 
 type PolygonBridge interface {
@@ -37,6 +38,12 @@ type PolygonBridgeReader interface {
 	EventTxnLookup(ctx context.Context, borTxHash libcommon.Hash) (uint64, bool, error)
 }
 
+// PolygonBridgeEventsReader is implemented by readers with direct access to the bridge store
+type PolygonBridgeEventsReader interface {
+	EventsByIdRange(ctx context.Context, fromID, toID uint64) ([]*heimdall.EventRecordWithTime, error)
+	EventBlockNum(ctx context.Context, eventID uint64) (uint64, bool, error)
+}
+
 type Service interface {
 	PolygonBridge
 	Run(ctx context.Context) error
@@ -400,14 +400,16 @@ func (c *Client) FetchFirstMilestoneNum(ctx context.Context) (int64, error) {
 		return 0, err
 	}
 
-	var first int64
+	return firstMilestoneNum(count), nil
+}
+
+// firstMilestoneNum returns the number of the oldest milestone Heimdall still keeps
+func firstMilestoneNum(count int64) int64 {
 	if count < milestonePruneNumber {
-		first = 1
-	} else {
-		first = count - milestonePruneNumber + 1
+		return 1
 	}
 
-	return first, nil
+	return count - milestonePruneNumber + 1
 }
 
 // FetchLastNoAckMilestone fetches the last no-ack-milestone from heimdall
@@ -515,6 +517,11 @@ func FetchWithRetryEx[T any](
 
 		client.logger.Warn(heimdallLogPrefix("an error while fetching"), "path", url.Path, "queryParams", url.RawQuery, "attempt", attempt, "err", err)
 
+		if attempt == client.maxRetries {
+			// no point in waiting for a retry which is not going to happen
+			break
+		}
+
 		select {
 		case <-ctx.Done():
 			client.logger.Debug(heimdallLogPrefix("request canceled"), "reason", ctx.Err(), "path", url.Path, "queryParams", url.RawQuery, "attempt", attempt)
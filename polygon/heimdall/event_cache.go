@@ -0,0 +1,163 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package heimdall
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/polygon/polygoncommon"
+)
+
+const eventCacheRoTxLimit = 16
+
+var eventCacheTablesCfg = kv.TableCfg{
+	kv.HeimdallEventsCache: {},
+}
+
+// eventCache keeps the state sync events fetched from Heimdall on disk, as they never change
+// once created and are expensive to fetch. A nil cache is valid and caches nothing.
+type eventCache struct {
+	db *polygoncommon.Database
+}
+
+func newEventCache(dataDir string, logger log.Logger) *eventCache {
+	return &eventCache{
+		db: polygoncommon.NewDatabase(dataDir, kv.HeimdallClientCacheDB, eventCacheTablesCfg, logger, false /* accede */, eventCacheRoTxLimit),
+	}
+}
+
+// Events returns the consecutive cached events starting at fromId which happened before to
+func (c *eventCache) Events(ctx context.Context, fromId uint64, to time.Time, limit int) ([]*EventRecordWithTime, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	if err := c.db.OpenOnce(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor, err := tx.Cursor(kv.HeimdallEventsCache)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var events []*EventRecordWithTime
+	key := entityStoreKey(fromId)
+	for k, v, err := cursor.Seek(key[:]); k != nil; k, v, err = cursor.Next() {
+		if err != nil {
+			return nil, err
+		}
+
+		if entityStoreKeyParse(k) != fromId+uint64(len(events)) {
+			break
+		}
+
+		var event EventRecordWithTime
+		if err := json.Unmarshal(v, &event); err != nil {
+			return nil, err
+		}
+
+		if !event.Time.Before(to) {
+			break
+		}
+
+		events = append(events, &event)
+		if limit > 0 && len(events) >= limit {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+func (c *eventCache) Event(ctx context.Context, id uint64) (*EventRecordWithTime, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	if err := c.db.OpenOnce(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	key := entityStoreKey(id)
+	v, err := tx.GetOne(kv.HeimdallEventsCache, key[:])
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	var event EventRecordWithTime
+	if err := json.Unmarshal(v, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (c *eventCache) Put(ctx context.Context, events []*EventRecordWithTime) error {
+	if c == nil || len(events) == 0 {
+		return nil
+	}
+
+	if err := c.db.OpenOnce(ctx); err != nil {
+		return err
+	}
+
+	tx, err := c.db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		v, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		key := entityStoreKey(event.ID)
+		if err := tx.Put(kv.HeimdallEventsCache, key[:], v); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (c *eventCache) Close() {
+	if c == nil {
+		return
+	}
+
+	c.db.Close()
+}
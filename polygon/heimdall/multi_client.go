@@ -0,0 +1,618 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package heimdall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/metrics"
+)
+
+// ErrInconsistentResponses is returned when the Heimdall endpoints fail to agree on a response
+var ErrInconsistentResponses = errors.New("heimdall endpoints returned inconsistent responses")
+
+// endpoints which are further behind the most advanced endpoint are not preferred for failover
+const maxEndpointLag = 10
+
+type MultiClientConfig struct {
+	URLs []string
+	// Quorum is the number of endpoints which need to return identical spans, checkpoints
+	// and milestones for them to be accepted. Zero means a simple majority of the endpoints.
+	Quorum int
+	// CacheDir is where state sync events are cached. Caching is disabled if empty.
+	CacheDir string
+	Logger   log.Logger
+}
+
+var _ HeimdallClient = &MultiClient{}
+
+// MultiClient is a HeimdallClient which spreads requests over several Heimdall endpoints.
+// Spans, checkpoints and milestones are cross-checked between a quorum of endpoints, while
+// all other requests fail over between the endpoints, preferring the healthiest ones.
+type MultiClient struct {
+	endpoints    []*multiClientEndpoint
+	quorum       int
+	eventCache   *eventCache
+	retryBackOff time.Duration
+	maxRetries   int
+	closeCh      chan struct{}
+	closeOnce    sync.Once
+	logger       log.Logger
+}
+
+// NewHeimdallClientForURLs returns a MultiClient when more than one URL is configured and a plain Client otherwise
+func NewHeimdallClientForURLs(config MultiClientConfig) HeimdallClient {
+	switch len(config.URLs) {
+	case 0:
+		return NewHeimdallClient("", config.Logger)
+	case 1:
+		return NewHeimdallClient(config.URLs[0], config.Logger)
+	}
+
+	return NewMultiClient(config)
+}
+
+func NewMultiClient(config MultiClientConfig) *MultiClient {
+	httpClient := &http.Client{
+		Timeout: apiHeimdallTimeout,
+	}
+
+	clients := make([]*Client, len(config.URLs))
+	for i, urlString := range config.URLs {
+		// retries are handled by the multi client, which prefers moving on to the next endpoint
+		clients[i] = newHeimdallClient(urlString, httpClient, retryBackOff, 1, config.Logger)
+	}
+
+	return newMultiClient(clients, config, retryBackOff, maxRetries)
+}
+
+func newMultiClient(clients []*Client, config MultiClientConfig, retryBackOff time.Duration, maxRetries int) *MultiClient {
+	quorum := config.Quorum
+	if quorum <= 0 {
+		quorum = len(clients)/2 + 1
+	}
+	if quorum > len(clients) {
+		quorum = len(clients)
+	}
+
+	endpoints := make([]*multiClientEndpoint, len(clients))
+	for i, client := range clients {
+		endpoints[i] = newMultiClientEndpoint(client)
+	}
+
+	var cache *eventCache
+	if config.CacheDir != "" {
+		cache = newEventCache(config.CacheDir, config.Logger)
+	}
+
+	return &MultiClient{
+		endpoints:    endpoints,
+		quorum:       quorum,
+		eventCache:   cache,
+		retryBackOff: retryBackOff,
+		maxRetries:   maxRetries,
+		closeCh:      make(chan struct{}),
+		logger:       config.Logger,
+	}
+}
+
+func (c *MultiClient) FetchStateSyncEvents(ctx context.Context, fromId uint64, to time.Time, limit int) ([]*EventRecordWithTime, error) {
+	events, err := c.eventCache.Events(ctx, fromId, to, limit)
+	if err != nil {
+		c.logger.Warn(heimdallLogPrefix("failed to read cached state sync events"), "err", err)
+		events = nil
+	}
+
+	if limit > 0 && len(events) >= limit {
+		return events, nil
+	}
+
+	remainingLimit := limit
+	if limit > 0 {
+		remainingLimit -= len(events)
+	}
+
+	fetched, err := failover(ctx, c, func(ctx context.Context, client *Client) ([]*EventRecordWithTime, error) {
+		return client.FetchStateSyncEvents(ctx, fromId+uint64(len(events)), to, remainingLimit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.eventCache.Put(ctx, fetched); err != nil {
+		c.logger.Warn(heimdallLogPrefix("failed to cache state sync events"), "err", err)
+	}
+
+	return append(events, fetched...), nil
+}
+
+func (c *MultiClient) FetchStateSyncEvent(ctx context.Context, id uint64) (*EventRecordWithTime, error) {
+	event, err := c.eventCache.Event(ctx, id)
+	if err != nil {
+		c.logger.Warn(heimdallLogPrefix("failed to read cached state sync event"), "id", id, "err", err)
+	}
+	if event != nil {
+		return event, nil
+	}
+
+	event, err = failover(ctx, c, func(ctx context.Context, client *Client) (*EventRecordWithTime, error) {
+		return client.FetchStateSyncEvent(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.eventCache.Put(ctx, []*EventRecordWithTime{event}); err != nil {
+		c.logger.Warn(heimdallLogPrefix("failed to cache state sync event"), "id", id, "err", err)
+	}
+
+	return event, nil
+}
+
+func (c *MultiClient) FetchLatestSpan(ctx context.Context) (*Span, error) {
+	spanId, err := agreedHighest(ctx, c, func(ctx context.Context, client *Client) (int64, error) {
+		span, err := client.FetchLatestSpan(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		return int64(span.Id), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.FetchSpan(ctx, uint64(spanId))
+}
+
+func (c *MultiClient) FetchSpan(ctx context.Context, spanID uint64) (*Span, error) {
+	return consensus(ctx, c, fmt.Sprintf("span %d", spanID), func(ctx context.Context, client *Client) (*Span, error) {
+		return client.FetchSpan(ctx, spanID)
+	})
+}
+
+func (c *MultiClient) FetchSpans(ctx context.Context, page uint64, limit uint64) ([]*Span, error) {
+	return consensusList(ctx, c, fmt.Sprintf("spans page %d", page), func(ctx context.Context, client *Client) ([]*Span, error) {
+		return client.FetchSpans(ctx, page, limit)
+	})
+}
+
+func (c *MultiClient) FetchCheckpoint(ctx context.Context, number int64) (*Checkpoint, error) {
+	if number == -1 {
+		count, err := c.FetchCheckpointCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		number = count
+	}
+
+	return consensus(ctx, c, fmt.Sprintf("checkpoint %d", number), func(ctx context.Context, client *Client) (*Checkpoint, error) {
+		return client.FetchCheckpoint(ctx, number)
+	})
+}
+
+func (c *MultiClient) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	return agreedHighest(ctx, c, func(ctx context.Context, client *Client) (int64, error) {
+		return client.FetchCheckpointCount(ctx)
+	})
+}
+
+func (c *MultiClient) FetchCheckpoints(ctx context.Context, page uint64, limit uint64) ([]*Checkpoint, error) {
+	return consensusList(ctx, c, fmt.Sprintf("checkpoints page %d", page), func(ctx context.Context, client *Client) ([]*Checkpoint, error) {
+		return client.FetchCheckpoints(ctx, page, limit)
+	})
+}
+
+func (c *MultiClient) FetchMilestone(ctx context.Context, number int64) (*Milestone, error) {
+	if number == -1 {
+		count, err := c.FetchMilestoneCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		number = count
+	}
+
+	return consensus(ctx, c, fmt.Sprintf("milestone %d", number), func(ctx context.Context, client *Client) (*Milestone, error) {
+		return client.FetchMilestone(ctx, number)
+	})
+}
+
+func (c *MultiClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	return agreedHighest(ctx, c, func(ctx context.Context, client *Client) (int64, error) {
+		return client.FetchMilestoneCount(ctx)
+	})
+}
+
+func (c *MultiClient) FetchFirstMilestoneNum(ctx context.Context) (int64, error) {
+	count, err := c.FetchMilestoneCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return firstMilestoneNum(count), nil
+}
+
+func (c *MultiClient) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	_, err := failover(ctx, c, func(ctx context.Context, client *Client) (struct{}, error) {
+		return struct{}{}, client.FetchNoAckMilestone(ctx, milestoneID)
+	})
+
+	return err
+}
+
+func (c *MultiClient) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
+	return failover(ctx, c, func(ctx context.Context, client *Client) (string, error) {
+		return client.FetchLastNoAckMilestone(ctx)
+	})
+}
+
+func (c *MultiClient) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	_, err := failover(ctx, c, func(ctx context.Context, client *Client) (struct{}, error) {
+		return struct{}{}, client.FetchMilestoneID(ctx, milestoneID)
+	})
+
+	return err
+}
+
+func (c *MultiClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+
+		for _, endpoint := range c.endpoints {
+			endpoint.client.Close()
+		}
+
+		c.eventCache.Close()
+	})
+}
+
+// healthiestEndpoints orders the endpoints so that the ones which are keeping up with the
+// others and have the fewest consecutive failures come first
+func (c *MultiClient) healthiestEndpoints() []*multiClientEndpoint {
+	endpoints := slices.Clone(c.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].lagging() != endpoints[j].lagging() {
+			return !endpoints[i].lagging()
+		}
+
+		return endpoints[i].failures.Load() < endpoints[j].failures.Load()
+	})
+
+	return endpoints
+}
+
+// waitForRetry returns false if the request should be abandoned instead
+func (c *MultiClient) waitForRetry(ctx context.Context, attempt int) (bool, error) {
+	if attempt >= c.maxRetries {
+		return false, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-c.closeCh:
+		return false, ErrShutdownDetected
+	case <-time.After(c.retryBackOff):
+		return true, nil
+	}
+}
+
+// failover tries the endpoints one after the other, healthiest first, until one of them succeeds
+func failover[T any](ctx context.Context, c *MultiClient, fetch func(context.Context, *Client) (T, error)) (T, error) {
+	var zero T
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		answered := false
+		for _, endpoint := range c.healthiestEndpoints() {
+			var result T
+			result, err = fetch(ctx, endpoint.client)
+			endpoint.observe(err)
+			if err == nil {
+				return result, nil
+			}
+
+			if ctx.Err() != nil || errors.Is(err, ErrShutdownDetected) {
+				return zero, err
+			}
+
+			// a lagging endpoint may not know about the entity yet, so the next one is
+			// still asked, but there is no point in retrying if everyone says the same
+			answered = isAnswerError(err)
+		}
+
+		if answered {
+			return zero, err
+		}
+
+		retry, waitErr := c.waitForRetry(ctx, attempt)
+		if waitErr != nil {
+			return zero, waitErr
+		}
+		if !retry {
+			return zero, err
+		}
+	}
+}
+
+type endpointResponse[T any] struct {
+	endpoint *multiClientEndpoint
+	result   T
+	err      error
+}
+
+// fetchFromAll sends the request to all endpoints concurrently
+func fetchFromAll[T any](ctx context.Context, c *MultiClient, fetch func(context.Context, *Client) (T, error)) []endpointResponse[T] {
+	responses := make([]endpointResponse[T], len(c.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range c.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := fetch(ctx, endpoint.client)
+			endpoint.observe(err)
+			responses[i] = endpointResponse[T]{endpoint: endpoint, result: result, err: err}
+		}()
+	}
+	wg.Wait()
+
+	return responses
+}
+
+// consensus returns the response a quorum of endpoints agrees on
+func consensus[T any](ctx context.Context, c *MultiClient, what string, fetch func(context.Context, *Client) (T, error)) (T, error) {
+	return consensusEx(ctx, c, what, fetch, nil)
+}
+
+// consensusList returns the list a quorum of endpoints agrees on. Endpoints which are ahead of
+// the others may return longer lists, so the lists are cut to the length a quorum has reached.
+func consensusList[T any](ctx context.Context, c *MultiClient, what string, fetch func(context.Context, *Client) ([]T, error)) ([]T, error) {
+	truncate := func(responses []endpointResponse[[]T]) {
+		var lengths []int
+		for _, response := range responses {
+			if response.err == nil {
+				lengths = append(lengths, len(response.result))
+			}
+		}
+
+		if len(lengths) < c.quorum {
+			return
+		}
+
+		sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+		length := lengths[c.quorum-1]
+		for i := range responses {
+			if len(responses[i].result) > length {
+				responses[i].result = responses[i].result[:length]
+			}
+		}
+	}
+
+	return consensusEx(ctx, c, what, fetch, truncate)
+}
+
+func consensusEx[T any](
+	ctx context.Context,
+	c *MultiClient,
+	what string,
+	fetch func(context.Context, *Client) (T, error),
+	normalize func([]endpointResponse[T]),
+) (T, error) {
+	var zero T
+
+	for attempt := 1; ; attempt++ {
+		responses := fetchFromAll(ctx, c, fetch)
+		if normalize != nil {
+			normalize(responses)
+		}
+
+		result, err := agree(c, what, responses)
+		if err == nil {
+			return result, nil
+		}
+
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+
+		retry, waitErr := c.waitForRetry(ctx, attempt)
+		if waitErr != nil {
+			return zero, waitErr
+		}
+		if !retry {
+			return zero, err
+		}
+	}
+}
+
+// agree groups the successful responses by their JSON encoding and picks the one returned by a quorum
+func agree[T any](c *MultiClient, what string, responses []endpointResponse[T]) (T, error) {
+	var zero T
+	var err error
+	groups := map[string][]endpointResponse[T]{}
+	var best string
+	succeeded := 0
+
+	for _, response := range responses {
+		if response.err != nil {
+			err = response.err
+			continue
+		}
+
+		encoded, encodeErr := json.Marshal(response.result)
+		if encodeErr != nil {
+			return zero, encodeErr
+		}
+
+		key := string(encoded)
+		groups[key] = append(groups[key], response)
+		if len(groups[key]) > len(groups[best]) {
+			best = key
+		}
+
+		succeeded++
+	}
+
+	if succeeded < c.quorum {
+		return zero, fmt.Errorf("%d of %d endpoints required for %s responded: %w", succeeded, c.quorum, what, err)
+	}
+
+	if len(groups[best]) < c.quorum {
+		c.logger.Warn(heimdallLogPrefix("endpoints disagree"), "entity", what, "responses", succeeded, "quorum", c.quorum)
+		return zero, fmt.Errorf("%w: %s", ErrInconsistentResponses, what)
+	}
+
+	for key, group := range groups {
+		if key == best {
+			continue
+		}
+
+		for _, response := range group {
+			response.endpoint.inconsistent()
+			c.logger.Warn(heimdallLogPrefix("endpoint disagrees with the quorum"), "entity", what, "endpoint", response.endpoint.name)
+		}
+	}
+
+	return groups[best][0].result, nil
+}
+
+// agreedHighest returns the highest value which a quorum of endpoints has reached,
+// and updates how far each endpoint lags behind the most advanced one
+func agreedHighest(ctx context.Context, c *MultiClient, fetch func(context.Context, *Client) (int64, error)) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		responses := fetchFromAll(ctx, c, fetch)
+
+		var err error
+		var values []int64
+		for _, response := range responses {
+			if response.err != nil {
+				err = response.err
+				continue
+			}
+
+			values = append(values, response.result)
+		}
+
+		if len(values) >= c.quorum {
+			slices.Sort(values)
+			slices.Reverse(values)
+
+			highest := values[0]
+			for _, response := range responses {
+				if response.err == nil {
+					response.endpoint.setLag(highest - response.result)
+				}
+			}
+
+			return values[c.quorum-1], nil
+		}
+
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		err = fmt.Errorf("%d of %d required endpoints responded: %w", len(values), c.quorum, err)
+		retry, waitErr := c.waitForRetry(ctx, attempt)
+		if waitErr != nil {
+			return 0, waitErr
+		}
+		if !retry {
+			return 0, err
+		}
+	}
+}
+
+// isAnswerError tells whether the error is a valid answer from Heimdall rather than a failure of the endpoint
+func isAnswerError(err error) bool {
+	return errors.Is(err, ErrEventRecordNotFound) ||
+		errors.Is(err, ErrNotInMilestoneList) ||
+		errors.Is(err, ErrNotInRejectedList)
+}
+
+type multiClientEndpoint struct {
+	client          *Client
+	name            string
+	failures        atomic.Int64 // consecutive failed requests
+	lag             atomic.Int64 // how far behind the most advanced endpoint it was last seen
+	up              metrics.Gauge
+	lagGauge        metrics.Gauge
+	requests        map[bool]metrics.Counter
+	inconsistencies metrics.Counter
+}
+
+func newMultiClientEndpoint(client *Client) *multiClientEndpoint {
+	// only the host is used to identify the endpoint, as the URL may contain credentials
+	name := client.urlString
+	if u, err := url.Parse(client.urlString); err == nil && u.Host != "" {
+		name = u.Host
+	}
+
+	return &multiClientEndpoint{
+		client:   client,
+		name:     name,
+		up:       metrics.GetOrCreateGauge(fmt.Sprintf(`heimdall_endpoint_up{endpoint="%s"}`, name)),
+		lagGauge: metrics.GetOrCreateGauge(fmt.Sprintf(`heimdall_endpoint_lag{endpoint="%s"}`, name)),
+		requests: map[bool]metrics.Counter{
+			true:  metrics.GetOrCreateCounter(fmt.Sprintf(`heimdall_endpoint_requests_total{endpoint="%s",result="success"}`, name)),
+			false: metrics.GetOrCreateCounter(fmt.Sprintf(`heimdall_endpoint_requests_total{endpoint="%s",result="failure"}`, name)),
+		},
+		inconsistencies: metrics.GetOrCreateCounter(fmt.Sprintf(`heimdall_endpoint_inconsistent_responses_total{endpoint="%s"}`, name)),
+	}
+}
+
+func (e *multiClientEndpoint) observe(err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrShutdownDetected) || isAnswerError(err) {
+		return
+	}
+
+	e.requests[err == nil].Inc()
+	if err != nil {
+		e.failures.Add(1)
+		e.up.SetInt(0)
+		return
+	}
+
+	e.failures.Store(0)
+	e.up.SetInt(1)
+}
+
+func (e *multiClientEndpoint) setLag(lag int64) {
+	e.lag.Store(lag)
+	e.lagGauge.SetInt(int(lag))
+}
+
+func (e *multiClientEndpoint) lagging() bool {
+	return e.lag.Load() > maxEndpointLag
+}
+
+func (e *multiClientEndpoint) inconsistent() {
+	e.inconsistencies.Inc()
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package heimdall
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/turbo/testlog"
+)
+
+func jsonResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newTestMultiClient(t *testing.T, config MultiClientConfig, responses ...func(req *http.Request) *http.Response) *MultiClient {
+	ctrl := gomock.NewController(t)
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	clients := make([]*Client, len(responses))
+	for i, respond := range responses {
+		httpClient := NewMockHttpClient(ctrl)
+		httpClient.EXPECT().
+			Do(gomock.Any()).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return respond(req), nil
+			}).
+			AnyTimes()
+		httpClient.EXPECT().CloseIdleConnections().AnyTimes()
+		clients[i] = newHeimdallClient(fmt.Sprintf("https://heimdall%d.test", i), httpClient, time.Millisecond, 1, logger)
+	}
+
+	config.Logger = logger
+	client := newMultiClient(clients, config, time.Millisecond, 2)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func respondWith(statusCode int, body string) func(req *http.Request) *http.Response {
+	return func(req *http.Request) *http.Response {
+		return jsonResponse(statusCode, body)
+	}
+}
+
+func TestMultiClientFailsOverToHealthyEndpoint(t *testing.T) {
+	ctx := context.Background()
+	client := newTestMultiClient(
+		t,
+		MultiClientConfig{},
+		respondWith(500, "internal error"),
+		respondWith(200, `{"height":"1","result":{"result":"0xabc"}}`),
+	)
+
+	id, err := client.FetchLastNoAckMilestone(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "0xabc", id)
+	require.Equal(t, int64(1), client.endpoints[0].failures.Load())
+	require.Equal(t, int64(0), client.endpoints[1].failures.Load())
+
+	// the failing endpoint is now tried last
+	require.Equal(t, client.endpoints[1], client.healthiestEndpoints()[0])
+}
+
+func TestMultiClientSpanConsensus(t *testing.T) {
+	ctx := context.Background()
+	span := `{"height":"1","result":{"span_id":7,"start_block":100,"end_block":200}}`
+	otherSpan := `{"height":"1","result":{"span_id":7,"start_block":100,"end_block":300}}`
+
+	client := newTestMultiClient(t, MultiClientConfig{}, respondWith(200, span), respondWith(200, otherSpan), respondWith(200, span))
+	res, err := client.FetchSpan(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200), res.EndBlock)
+
+	client = newTestMultiClient(t, MultiClientConfig{Quorum: 3}, respondWith(200, span), respondWith(200, otherSpan), respondWith(200, span))
+	_, err = client.FetchSpan(ctx, 7)
+	require.ErrorIs(t, err, ErrInconsistentResponses)
+}
+
+func TestMultiClientCountAgreedByQuorum(t *testing.T) {
+	ctx := context.Background()
+	client := newTestMultiClient(
+		t,
+		MultiClientConfig{Quorum: 2},
+		respondWith(200, `{"height":"1","result":{"result":100}}`),
+		respondWith(200, `{"height":"1","result":{"result":99}}`),
+		respondWith(200, `{"height":"1","result":{"result":20}}`),
+	)
+
+	count, err := client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(99), count)
+	require.False(t, client.endpoints[1].lagging())
+	require.True(t, client.endpoints[2].lagging())
+}
+
+func TestMultiClientCachesStateSyncEvents(t *testing.T) {
+	ctx := context.Background()
+	events := `{"height":"1","result":[` +
+		`{"id":1,"contract":"0x0000000000000000000000000000000000001001","data":"0x01","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000001","log_index":0,"bor_chain_id":"137","record_time":"2024-01-01T00:00:00Z"},` +
+		`{"id":2,"contract":"0x0000000000000000000000000000000000001001","data":"0x02","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","log_index":0,"bor_chain_id":"137","record_time":"2024-01-01T00:01:00Z"}` +
+		`]}`
+
+	var requests int
+	client := newTestMultiClient(
+		t,
+		MultiClientConfig{CacheDir: t.TempDir()},
+		func(req *http.Request) *http.Response {
+			requests++
+			if req.URL.Query().Get("from-id") == "1" {
+				return jsonResponse(200, events)
+			}
+			return jsonResponse(200, `{"height":"1","result":[]}`)
+		},
+	)
+
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	res, err := client.FetchStateSyncEvents(ctx, 1, to, 0)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, 1, requests)
+
+	res, err = client.FetchStateSyncEvents(ctx, 1, to, 2)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, uint64(2), res[1].ID)
+	require.Equal(t, 1, requests)
+
+	event, err := client.FetchStateSyncEvent(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "0x02", event.Data.String())
+	require.Equal(t, 1, requests)
+}
@@ -32,12 +32,12 @@ import (
 )
 
 type ServiceConfig struct {
-	BorConfig   *borcfg.BorConfig
-	HeimdallURL string
-	DataDir     string
-	TempDir     string
-	Logger      log.Logger
-	RoTxLimit   int64
+	BorConfig *borcfg.BorConfig
+	Client    HeimdallClient
+	DataDir   string
+	TempDir   string
+	Logger    log.Logger
+	RoTxLimit int64
 }
 
 type Service interface {
@@ -64,9 +64,8 @@ type service struct {
 
 func AssembleService(config ServiceConfig) Service {
 	store := NewMdbxServiceStore(config.Logger, config.DataDir, config.TempDir, config.RoTxLimit)
-	client := NewHeimdallClient(config.HeimdallURL, config.Logger)
 	reader := NewReader(config.BorConfig, store, config.Logger)
-	return NewService(config.BorConfig, client, store, config.Logger, reader)
+	return NewService(config.BorConfig, config.Client, store, config.Logger, reader)
 }
 
 func NewService(borConfig *borcfg.BorConfig, client HeimdallClient, store ServiceStore, logger log.Logger, reader *Reader) Service {
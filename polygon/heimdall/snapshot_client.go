@@ -0,0 +1,330 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package heimdall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+// ErrNotInSnapshots is returned when running without Heimdall and the requested data is past the bor snapshots tip
+var ErrNotInSnapshots = errors.New("not available in bor snapshots")
+
+// SnapshotReader reads the spans, checkpoints and state sync events embedded in bor snapshot files
+type SnapshotReader interface {
+	Span(ctx context.Context, tx kv.Getter, spanId uint64) ([]byte, error)
+	LastFrozenSpanId() uint64
+	Checkpoint(ctx context.Context, tx kv.Getter, checkpointId uint64) ([]byte, error)
+	LastFrozenCheckpointId() uint64
+	EventsByIdFromSnapshot(from uint64, to time.Time, limit int) ([]*EventRecordWithTime, bool, error)
+	LastFrozenEventId() uint64
+}
+
+var _ HeimdallClient = &SnapshotClient{}
+
+// SnapshotClient is a HeimdallClient which serves spans, checkpoints and state sync events from
+// bor snapshot files up to their tip, without calling Heimdall at all. Once the data in the snapshots
+// has been handed out it switches to the Heimdall client, or keeps reporting the snapshots tip as
+// the latest data if there is none, which allows syncing Polygon history offline.
+type SnapshotClient struct {
+	reader              SnapshotReader
+	heimdall            HeimdallClient // nil when running without Heimdall
+	spansSwitched       atomic.Bool
+	checkpointsSwitched atomic.Bool
+	logger              log.Logger
+}
+
+func NewSnapshotClient(reader SnapshotReader, heimdall HeimdallClient, logger log.Logger) *SnapshotClient {
+	return &SnapshotClient{
+		reader:   reader,
+		heimdall: heimdall,
+		logger:   logger,
+	}
+}
+
+func (c *SnapshotClient) FetchStateSyncEvents(ctx context.Context, fromId uint64, to time.Time, limit int) ([]*EventRecordWithTime, error) {
+	lastFrozenEventId := c.reader.LastFrozenEventId()
+	if fromId > lastFrozenEventId {
+		if c.heimdall == nil {
+			return nil, nil
+		}
+
+		return c.heimdall.FetchStateSyncEvents(ctx, fromId, to, limit)
+	}
+
+	events, limitedByTime, err := c.reader.EventsByIdFromSnapshot(fromId, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if limitedByTime || (limit > 0 && len(events) >= limit) || c.heimdall == nil {
+		return events, nil
+	}
+
+	remainingLimit := limit
+	if limit > 0 {
+		remainingLimit -= len(events)
+	}
+
+	c.logger.Info(heimdallLogPrefix("reached the state sync events snapshots tip, switching to heimdall"), "lastFrozenEventId", lastFrozenEventId)
+	remaining, err := c.heimdall.FetchStateSyncEvents(ctx, lastFrozenEventId+1, to, remainingLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, remaining...), nil
+}
+
+func (c *SnapshotClient) FetchStateSyncEvent(ctx context.Context, id uint64) (*EventRecordWithTime, error) {
+	if id <= c.reader.LastFrozenEventId() {
+		events, _, err := c.reader.EventsByIdFromSnapshot(id, time.Unix(math.MaxInt64, 0), 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(events) > 0 && events[0].ID == id {
+			return events[0], nil
+		}
+	}
+
+	if c.heimdall == nil {
+		return nil, fmt.Errorf("%w: %w: id=%d", ErrEventRecordNotFound, ErrNotInSnapshots, id)
+	}
+
+	return c.heimdall.FetchStateSyncEvent(ctx, id)
+}
+
+func (c *SnapshotClient) FetchLatestSpan(ctx context.Context) (*Span, error) {
+	if c.useHeimdall(&c.spansSwitched, c.reader.LastFrozenSpanId()) {
+		return c.heimdall.FetchLatestSpan(ctx)
+	}
+
+	return c.snapshotSpan(ctx, c.reader.LastFrozenSpanId())
+}
+
+func (c *SnapshotClient) FetchSpan(ctx context.Context, spanID uint64) (*Span, error) {
+	lastFrozenSpanId := c.reader.LastFrozenSpanId()
+	if spanID > lastFrozenSpanId || lastFrozenSpanId == 0 {
+		if c.heimdall == nil {
+			return nil, fmt.Errorf("%w: spanID=%d", ErrNotInSnapshots, spanID)
+		}
+
+		return c.heimdall.FetchSpan(ctx, spanID)
+	}
+
+	span, err := c.snapshotSpan(ctx, spanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if spanID == lastFrozenSpanId {
+		c.switchToHeimdall(&c.spansSwitched, "spans")
+	}
+
+	return span, nil
+}
+
+func (c *SnapshotClient) FetchSpans(ctx context.Context, page uint64, limit uint64) ([]*Span, error) {
+	// span ids start at 0
+	firstId, lastId := (page-1)*limit, page*limit-1
+	lastFrozenSpanId := c.reader.LastFrozenSpanId()
+	if lastId > lastFrozenSpanId && c.useHeimdall(&c.spansSwitched, lastFrozenSpanId) {
+		return c.heimdall.FetchSpans(ctx, page, limit)
+	}
+
+	var spans []*Span
+	for id := firstId; id <= min(lastId, lastFrozenSpanId); id++ {
+		span, err := c.FetchSpan(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		spans = append(spans, span)
+	}
+
+	return spans, nil
+}
+
+func (c *SnapshotClient) FetchCheckpoint(ctx context.Context, number int64) (*Checkpoint, error) {
+	lastFrozenCheckpointId := c.reader.LastFrozenCheckpointId()
+	if number == -1 {
+		if c.useHeimdall(&c.checkpointsSwitched, lastFrozenCheckpointId) {
+			return c.heimdall.FetchCheckpoint(ctx, number)
+		}
+
+		number = int64(lastFrozenCheckpointId)
+	}
+
+	if number < 1 || uint64(number) > lastFrozenCheckpointId {
+		if c.heimdall == nil {
+			return nil, fmt.Errorf("%w: %w: number=%d", ErrNotInCheckpointList, ErrNotInSnapshots, number)
+		}
+
+		return c.heimdall.FetchCheckpoint(ctx, number)
+	}
+
+	checkpoint, err := c.snapshotCheckpoint(ctx, uint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(number) == lastFrozenCheckpointId {
+		c.switchToHeimdall(&c.checkpointsSwitched, "checkpoints")
+	}
+
+	return checkpoint, nil
+}
+
+func (c *SnapshotClient) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	lastFrozenCheckpointId := c.reader.LastFrozenCheckpointId()
+	if c.useHeimdall(&c.checkpointsSwitched, lastFrozenCheckpointId) {
+		return c.heimdall.FetchCheckpointCount(ctx)
+	}
+
+	return int64(lastFrozenCheckpointId), nil
+}
+
+func (c *SnapshotClient) FetchCheckpoints(ctx context.Context, page uint64, limit uint64) ([]*Checkpoint, error) {
+	// checkpoint ids start at 1
+	firstId, lastId := (page-1)*limit+1, page*limit
+	lastFrozenCheckpointId := c.reader.LastFrozenCheckpointId()
+	if lastId > lastFrozenCheckpointId && c.useHeimdall(&c.checkpointsSwitched, lastFrozenCheckpointId) {
+		return c.heimdall.FetchCheckpoints(ctx, page, limit)
+	}
+
+	var checkpoints []*Checkpoint
+	for id := firstId; id <= min(lastId, lastFrozenCheckpointId); id++ {
+		checkpoint, err := c.FetchCheckpoint(ctx, int64(id))
+		if err != nil {
+			return nil, err
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// Milestones are not kept in bor snapshots, and are only needed at the chain tip,
+// so they are not fetched before checkpoints have switched to Heimdall.
+
+func (c *SnapshotClient) FetchMilestone(ctx context.Context, number int64) (*Milestone, error) {
+	if !c.milestonesAvailable() {
+		return nil, fmt.Errorf("%w: %w: number=%d", ErrNotInMilestoneList, ErrNotInSnapshots, number)
+	}
+
+	return c.heimdall.FetchMilestone(ctx, number)
+}
+
+func (c *SnapshotClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	if !c.milestonesAvailable() {
+		return 0, nil
+	}
+
+	return c.heimdall.FetchMilestoneCount(ctx)
+}
+
+func (c *SnapshotClient) FetchFirstMilestoneNum(ctx context.Context) (int64, error) {
+	if !c.milestonesAvailable() {
+		return 1, nil
+	}
+
+	return c.heimdall.FetchFirstMilestoneNum(ctx)
+}
+
+func (c *SnapshotClient) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	if !c.milestonesAvailable() {
+		return fmt.Errorf("%w: %w: milestoneID %q", ErrNotInRejectedList, ErrNotInSnapshots, milestoneID)
+	}
+
+	return c.heimdall.FetchNoAckMilestone(ctx, milestoneID)
+}
+
+func (c *SnapshotClient) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
+	if !c.milestonesAvailable() {
+		return "", nil
+	}
+
+	return c.heimdall.FetchLastNoAckMilestone(ctx)
+}
+
+func (c *SnapshotClient) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	if !c.milestonesAvailable() {
+		return fmt.Errorf("%w: %w: milestoneID %q", ErrNotInMilestoneList, ErrNotInSnapshots, milestoneID)
+	}
+
+	return c.heimdall.FetchMilestoneID(ctx, milestoneID)
+}
+
+func (c *SnapshotClient) Close() {
+	if c.heimdall != nil {
+		c.heimdall.Close()
+	}
+}
+
+// useHeimdall tells whether requests for the latest entities should go to Heimdall, which is
+// the case once all entities in the snapshots have been handed out, or if there are none
+func (c *SnapshotClient) useHeimdall(switched *atomic.Bool, lastFrozenId uint64) bool {
+	return c.heimdall != nil && (switched.Load() || lastFrozenId == 0)
+}
+
+func (c *SnapshotClient) switchToHeimdall(switched *atomic.Bool, entities string) {
+	if c.heimdall != nil && switched.CompareAndSwap(false, true) {
+		c.logger.Info(heimdallLogPrefix("reached the snapshots tip, switching to heimdall"), "entities", entities)
+	}
+}
+
+func (c *SnapshotClient) milestonesAvailable() bool {
+	return c.useHeimdall(&c.checkpointsSwitched, c.reader.LastFrozenCheckpointId())
+}
+
+func (c *SnapshotClient) snapshotSpan(ctx context.Context, id uint64) (*Span, error) {
+	spanBytes, err := c.reader.Span(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var span Span
+	if err := json.Unmarshal(spanBytes, &span); err != nil {
+		return nil, fmt.Errorf("invalid span %d in snapshots: %w", id, err)
+	}
+
+	return &span, nil
+}
+
+func (c *SnapshotClient) snapshotCheckpoint(ctx context.Context, id uint64) (*Checkpoint, error) {
+	checkpointBytes, err := c.reader.Checkpoint(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(checkpointBytes, &checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %d in snapshots: %w", id, err)
+	}
+
+	checkpoint.Id = CheckpointId(id)
+	return &checkpoint, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package heimdall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/turbo/testlog"
+)
+
+type testSnapshotReader struct {
+	spans       [][]byte
+	checkpoints [][]byte
+	events      []*EventRecordWithTime
+}
+
+func newTestSnapshotReader(t *testing.T, spanCount, checkpointCount, eventCount int) *testSnapshotReader {
+	reader := &testSnapshotReader{}
+	for i := 0; i < spanCount; i++ {
+		span, err := json.Marshal(&Span{Id: SpanId(i), StartBlock: uint64(i) * 6400, EndBlock: uint64(i+1)*6400 - 1})
+		require.NoError(t, err)
+		reader.spans = append(reader.spans, span)
+	}
+	for i := 1; i <= checkpointCount; i++ {
+		checkpoint, err := json.Marshal(&Checkpoint{
+			Id: CheckpointId(i),
+			Fields: WaypointFields{
+				StartBlock: big.NewInt(int64(i-1) * 256),
+				EndBlock:   big.NewInt(int64(i)*256 - 1),
+			},
+		})
+		require.NoError(t, err)
+		reader.checkpoints = append(reader.checkpoints, checkpoint)
+	}
+	for i := 1; i <= eventCount; i++ {
+		reader.events = append(reader.events, &EventRecordWithTime{
+			EventRecord: EventRecord{ID: uint64(i)},
+			Time:        time.Unix(int64(i), 0),
+		})
+	}
+	return reader
+}
+
+func (r *testSnapshotReader) Span(_ context.Context, _ kv.Getter, spanId uint64) ([]byte, error) {
+	if spanId >= uint64(len(r.spans)) {
+		return nil, errors.New("span not found")
+	}
+	return r.spans[spanId], nil
+}
+
+func (r *testSnapshotReader) LastFrozenSpanId() uint64 {
+	if len(r.spans) == 0 {
+		return 0
+	}
+	return uint64(len(r.spans) - 1)
+}
+
+func (r *testSnapshotReader) Checkpoint(_ context.Context, _ kv.Getter, checkpointId uint64) ([]byte, error) {
+	if checkpointId == 0 || checkpointId > uint64(len(r.checkpoints)) {
+		return nil, errors.New("checkpoint not found")
+	}
+	return r.checkpoints[checkpointId-1], nil
+}
+
+func (r *testSnapshotReader) LastFrozenCheckpointId() uint64 {
+	return uint64(len(r.checkpoints))
+}
+
+func (r *testSnapshotReader) EventsByIdFromSnapshot(from uint64, to time.Time, limit int) ([]*EventRecordWithTime, bool, error) {
+	var result []*EventRecordWithTime
+	for _, event := range r.events {
+		if event.ID < from {
+			continue
+		}
+		if event.Time.After(to) {
+			return result, true, nil
+		}
+		result = append(result, event)
+		if len(result) == limit {
+			return result, false, nil
+		}
+	}
+	return result, false, nil
+}
+
+func (r *testSnapshotReader) LastFrozenEventId() uint64 {
+	return uint64(len(r.events))
+}
+
+func TestSnapshotClientWithoutHeimdall(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	client := NewSnapshotClient(newTestSnapshotReader(t, 3, 5, 4), nil, logger)
+
+	count, err := client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+
+	checkpoints, err := client.FetchCheckpoints(ctx, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	require.Equal(t, CheckpointId(4), checkpoints[0].Id)
+	require.Equal(t, uint64(1024), checkpoints[1].StartBlock().Uint64())
+
+	_, err = client.FetchCheckpoint(ctx, 6)
+	require.ErrorIs(t, err, ErrNotInSnapshots)
+
+	span, err := client.FetchLatestSpan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SpanId(2), span.Id)
+
+	count, err = client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	events, err := client.FetchStateSyncEvents(ctx, 2, time.Unix(100, 0), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	events, err = client.FetchStateSyncEvents(ctx, 5, time.Unix(100, 0), 0)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestSnapshotClientSwitchesToHeimdallPastTheTip(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	heimdallClient := NewMockHeimdallClient(gomock.NewController(t))
+	client := NewSnapshotClient(newTestSnapshotReader(t, 3, 5, 4), heimdallClient, logger)
+
+	// no heimdall calls until the snapshot checkpoints have been handed out
+	count, err := client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+	count, err = client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	checkpoints, err := client.FetchCheckpoints(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 5)
+
+	heimdallClient.EXPECT().FetchCheckpointCount(gomock.Any()).Return(int64(7), nil)
+	heimdallClient.EXPECT().FetchCheckpoint(gomock.Any(), int64(6)).Return(&Checkpoint{Id: 6}, nil)
+	heimdallClient.EXPECT().FetchMilestoneCount(gomock.Any()).Return(int64(10), nil)
+
+	count, err = client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), count)
+
+	checkpoint, err := client.FetchCheckpoint(ctx, 6)
+	require.NoError(t, err)
+	require.Equal(t, CheckpointId(6), checkpoint.Id)
+
+	count, err = client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), count)
+
+	// events past the snapshots tip are fetched from heimdall
+	to := time.Unix(100, 0)
+	heimdallClient.EXPECT().
+		FetchStateSyncEvents(gomock.Any(), uint64(5), to, 0).
+		Return([]*EventRecordWithTime{{EventRecord: EventRecord{ID: 5}}}, nil)
+
+	events, err := client.FetchStateSyncEvents(ctx, 3, to, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, uint64(5), events[2].ID)
+}
@@ -85,11 +85,13 @@ func (e *executionClient) InsertBlocks(ctx context.Context, blocks []*types.Bloc
 
 func (e *executionClient) UpdateForkChoice(ctx context.Context, tip *types.Header, finalizedHeader *types.Header) (common.Hash, error) {
 	tipHash := tip.Hash()
+	finalizedHash := finalizedHeader.Hash()
 
+	// on bor only milestones and checkpoints give finality guarantees, so safe follows finalized
 	request := executionproto.ForkChoice{
 		HeadBlockHash:      gointerfaces.ConvertHashToH256(tipHash),
-		SafeBlockHash:      gointerfaces.ConvertHashToH256(tipHash),
-		FinalizedBlockHash: gointerfaces.ConvertHashToH256(finalizedHeader.Hash()),
+		SafeBlockHash:      gointerfaces.ConvertHashToH256(finalizedHash),
+		FinalizedBlockHash: gointerfaces.ConvertHashToH256(finalizedHash),
 		Timeout:            0,
 	}
 
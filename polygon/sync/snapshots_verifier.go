@@ -0,0 +1,182 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/rlp"
+)
+
+var (
+	ErrSnapshotsMissingHeader     = errors.New("header missing in snapshots")
+	ErrSnapshotsDisconnectedRange = errors.New("disconnected block ranges in snapshots")
+	ErrSnapshotsBadEvents         = errors.New("bad state sync events in snapshots")
+)
+
+type SnapshotsVerifierReader interface {
+	heimdall.SnapshotReader
+	HeaderByNumber(ctx context.Context, tx kv.Getter, blockNum uint64) (*types.Header, error)
+	EventsByBlock(ctx context.Context, tx kv.Tx, hash common.Hash, blockNum uint64) ([]rlp.RawValue, error)
+	FrozenBlocks() uint64
+	LastFrozenEventBlockNum() uint64
+}
+
+// VerifySnapshots checks the Heimdall data embedded in bor snapshots before syncing from it
+// without Heimdall. The headers of every checkpointed block range are checked against the
+// checkpoint root hash, the state sync events must be attached to these headers with
+// consecutive ids, and the spans and checkpoints must cover consecutive block ranges.
+func VerifySnapshots(ctx context.Context, reader SnapshotsVerifierReader, logger log.Logger) error {
+	client := heimdall.NewSnapshotClient(reader, nil, logger)
+	defer client.Close()
+
+	if err := verifySnapshotSpans(ctx, client, reader.LastFrozenSpanId()); err != nil {
+		return err
+	}
+
+	checkpointCount, err := client.FetchCheckpointCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	progressLogTicker := time.NewTicker(30 * time.Second)
+	defer progressLogTicker.Stop()
+
+	frozenBlocks := reader.FrozenBlocks()
+	var nextBlockNum, lastEventId uint64
+	var checkpointsVerified int64
+	for id := int64(1); id <= checkpointCount; id++ {
+		checkpoint, err := client.FetchCheckpoint(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		blockRange := checkpoint.BlockNumRange()
+		if blockRange.End > frozenBlocks {
+			break
+		}
+
+		if blockRange.Start != nextBlockNum {
+			return fmt.Errorf(
+				"VerifySnapshots: %w: checkpoint %d starts at %d, expected %d",
+				ErrSnapshotsDisconnectedRange, id, blockRange.Start, nextBlockNum,
+			)
+		}
+
+		headers := make([]*types.Header, 0, blockRange.Len())
+		for blockNum := blockRange.Start; blockNum <= blockRange.End; blockNum++ {
+			header, err := reader.HeaderByNumber(ctx, nil, blockNum)
+			if err != nil {
+				return err
+			}
+			if header == nil {
+				return fmt.Errorf("VerifySnapshots: %w: %d", ErrSnapshotsMissingHeader, blockNum)
+			}
+
+			events, err := reader.EventsByBlock(ctx, nil, header.Hash(), blockNum)
+			if err != nil {
+				return err
+			}
+
+			for _, event := range events {
+				eventId := heimdall.EventId(event)
+				if lastEventId != 0 && eventId != lastEventId+1 {
+					return fmt.Errorf(
+						"VerifySnapshots: %w: block %d has event %d after event %d",
+						ErrSnapshotsBadEvents, blockNum, eventId, lastEventId,
+					)
+				}
+
+				lastEventId = eventId
+			}
+
+			headers = append(headers, header)
+		}
+
+		if err := VerifyCheckpointHeaders(checkpoint, headers); err != nil {
+			return fmt.Errorf("VerifySnapshots: checkpoint %d: %w", id, err)
+		}
+
+		nextBlockNum = blockRange.End + 1
+		checkpointsVerified = id
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-progressLogTicker.C:
+			logger.Info(
+				syncLogPrefix("verifying snapshots"),
+				"checkpoint", id,
+				"checkpoints", checkpointCount,
+				"block", blockRange.End,
+			)
+		default:
+		}
+	}
+
+	// all frozen events must have been found through the verified headers,
+	// otherwise some of them are attached to blocks which are not canonical
+	if nextBlockNum > reader.LastFrozenEventBlockNum() && lastEventId != reader.LastFrozenEventId() {
+		return fmt.Errorf(
+			"VerifySnapshots: %w: last event attached to checkpointed headers is %d, expected %d",
+			ErrSnapshotsBadEvents, lastEventId, reader.LastFrozenEventId(),
+		)
+	}
+
+	logger.Info(
+		syncLogPrefix("verified snapshots"),
+		"checkpoints", checkpointsVerified,
+		"blocks", nextBlockNum,
+		"spans", reader.LastFrozenSpanId()+1,
+		"events", lastEventId,
+	)
+
+	return nil
+}
+
+func verifySnapshotSpans(ctx context.Context, client heimdall.HeimdallClient, lastFrozenSpanId uint64) error {
+	if lastFrozenSpanId == 0 {
+		return nil
+	}
+
+	var nextBlockNum uint64
+	for id := uint64(0); id <= lastFrozenSpanId; id++ {
+		span, err := client.FetchSpan(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if uint64(span.Id) != id || span.StartBlock != nextBlockNum || span.EndBlock < span.StartBlock {
+			return fmt.Errorf(
+				"VerifySnapshots: %w: span %d (id=%d) covers %d-%d, expected to start at %d",
+				ErrSnapshotsDisconnectedRange, id, span.Id, span.StartBlock, span.EndBlock, nextBlockNum,
+			)
+		}
+
+		nextBlockNum = span.EndBlock + 1
+	}
+
+	return nil
+}
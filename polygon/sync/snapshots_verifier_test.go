@@ -0,0 +1,166 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/bor"
+	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/rlp"
+	"github.com/erigontech/erigon/turbo/testlog"
+)
+
+type testSnapshotsVerifierReader struct {
+	headers     []*types.Header
+	events      map[uint64][]rlp.RawValue // block num -> events
+	eventHashes map[uint64]common.Hash    // block num -> hash the events are attached to
+	spans       [][]byte
+	checkpoints [][]byte
+	lastEventId uint64
+}
+
+func newTestSnapshotsVerifierReader(t *testing.T) *testSnapshotsVerifierReader {
+	reader := &testSnapshotsVerifierReader{
+		events:      map[uint64][]rlp.RawValue{},
+		eventHashes: map[uint64]common.Hash{},
+	}
+
+	var parentHash common.Hash
+	for i := int64(0); i < 8; i++ {
+		header := &types.Header{Number: big.NewInt(i), ParentHash: parentHash, GasLimit: uint64(i)}
+		reader.headers = append(reader.headers, header)
+		parentHash = header.Hash()
+	}
+
+	// events at blocks 0, 4 and 6
+	for _, blockNum := range []uint64{0, 4, 4, 6} {
+		reader.lastEventId++
+		event := &heimdall.EventRecordWithTime{
+			EventRecord: heimdall.EventRecord{ID: reader.lastEventId},
+			Time:        time.Unix(int64(reader.lastEventId), 0),
+		}
+		raw, err := event.MarshallBytes()
+		require.NoError(t, err)
+		reader.events[blockNum] = append(reader.events[blockNum], raw)
+		reader.eventHashes[blockNum] = reader.headers[blockNum].Hash()
+	}
+
+	for i, blockRange := range []heimdall.ClosedRange{{Start: 0, End: 3}, {Start: 4, End: 7}} {
+		rootHash, err := bor.ComputeHeadersRootHash(reader.headers[blockRange.Start : blockRange.End+1])
+		require.NoError(t, err)
+		checkpoint, err := json.Marshal(&heimdall.Checkpoint{
+			Id: heimdall.CheckpointId(i + 1),
+			Fields: heimdall.WaypointFields{
+				StartBlock: new(big.Int).SetUint64(blockRange.Start),
+				EndBlock:   new(big.Int).SetUint64(blockRange.End),
+				RootHash:   common.BytesToHash(rootHash),
+			},
+		})
+		require.NoError(t, err)
+		reader.checkpoints = append(reader.checkpoints, checkpoint)
+	}
+
+	for i, blockRange := range []heimdall.ClosedRange{{Start: 0, End: 5}, {Start: 6, End: 11}} {
+		span, err := json.Marshal(&heimdall.Span{Id: heimdall.SpanId(i), StartBlock: blockRange.Start, EndBlock: blockRange.End})
+		require.NoError(t, err)
+		reader.spans = append(reader.spans, span)
+	}
+
+	return reader
+}
+
+func (r *testSnapshotsVerifierReader) Span(_ context.Context, _ kv.Getter, spanId uint64) ([]byte, error) {
+	return r.spans[spanId], nil
+}
+
+func (r *testSnapshotsVerifierReader) LastFrozenSpanId() uint64 {
+	return uint64(len(r.spans) - 1)
+}
+
+func (r *testSnapshotsVerifierReader) Checkpoint(_ context.Context, _ kv.Getter, checkpointId uint64) ([]byte, error) {
+	return r.checkpoints[checkpointId-1], nil
+}
+
+func (r *testSnapshotsVerifierReader) LastFrozenCheckpointId() uint64 {
+	return uint64(len(r.checkpoints))
+}
+
+func (r *testSnapshotsVerifierReader) EventsByIdFromSnapshot(uint64, time.Time, int) ([]*heimdall.EventRecordWithTime, bool, error) {
+	return nil, false, nil
+}
+
+func (r *testSnapshotsVerifierReader) LastFrozenEventId() uint64 {
+	return r.lastEventId
+}
+
+func (r *testSnapshotsVerifierReader) LastFrozenEventBlockNum() uint64 {
+	return 6
+}
+
+func (r *testSnapshotsVerifierReader) HeaderByNumber(_ context.Context, _ kv.Getter, blockNum uint64) (*types.Header, error) {
+	if blockNum >= uint64(len(r.headers)) {
+		return nil, nil
+	}
+	return r.headers[blockNum], nil
+}
+
+func (r *testSnapshotsVerifierReader) EventsByBlock(_ context.Context, _ kv.Tx, hash common.Hash, blockNum uint64) ([]rlp.RawValue, error) {
+	if r.eventHashes[blockNum] != hash {
+		return nil, nil
+	}
+	return r.events[blockNum], nil
+}
+
+func (r *testSnapshotsVerifierReader) FrozenBlocks() uint64 {
+	return uint64(len(r.headers) - 1)
+}
+
+func TestVerifySnapshots(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	reader := newTestSnapshotsVerifierReader(t)
+	require.NoError(t, VerifySnapshots(ctx, reader, logger))
+
+	// headers which do not match the checkpoint root
+	reader = newTestSnapshotsVerifierReader(t)
+	reader.headers[5] = &types.Header{Number: big.NewInt(5), TxHash: common.HexToHash("0x42")}
+	require.ErrorIs(t, VerifySnapshots(ctx, reader, logger), ErrBadHeadersRootHash)
+
+	// events attached to a block hash which is not canonical
+	reader = newTestSnapshotsVerifierReader(t)
+	reader.eventHashes[6] = common.HexToHash("0x01")
+	require.ErrorIs(t, VerifySnapshots(ctx, reader, logger), ErrSnapshotsBadEvents)
+
+	// spans with a gap between them
+	reader = newTestSnapshotsVerifierReader(t)
+	span, err := json.Marshal(&heimdall.Span{Id: 1, StartBlock: 7, EndBlock: 11})
+	require.NoError(t, err)
+	reader.spans[1] = span
+	require.ErrorIs(t, VerifySnapshots(ctx, reader, logger), ErrSnapshotsDisconnectedRange)
+}
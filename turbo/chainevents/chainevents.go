@@ -0,0 +1,382 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package chainevents - durable, replayable feed of canonical chain changes: blocks applied and reverted,
+// with receipts and account/storage diffs.
+//
+// Nothing is stored for this feed: events are derived from what stagedsync and Aggregator already have:
+//   - apply: block, re-generated receipts, keys changed by block (state history) with their values after block
+//   - revert: keys changed by non-canonical block (kv.ChangeSets3) with their values before block
+//
+// Consumer keeps a Cursor (last processed block) and can reconnect with it at any time: it will get all reverts
+// (down to common ancestor with canonical chain) and all applies since then.
+package chainevents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/types/accounts"
+	"github.com/erigontech/erigon/eth/stagedsync/stages"
+	"github.com/erigontech/erigon/turbo/services"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+// eventsPerTx - CatchUp doesn't keep read transaction open for too long: re-opens it after this amount of events
+const eventsPerTx = 256
+
+type EventType string
+
+const (
+	Apply  EventType = "apply"  // block became part of canonical chain
+	Revert EventType = "revert" // block is not part of canonical chain anymore
+)
+
+// Cursor - last block processed by consumer
+type Cursor struct {
+	BlockNumber uint64
+	BlockHash   libcommon.Hash // zero - canonical block of BlockNumber
+}
+
+type Options struct {
+	WithReceipts  bool // Apply events only
+	WithStateDiff bool
+}
+
+type Event struct {
+	Type        EventType      `json:"type"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   libcommon.Hash `json:"blockHash"`
+	ParentHash  libcommon.Hash `json:"parentHash"`
+	Receipts    types.Receipts `json:"receipts,omitempty"`
+	// state after this event: after block for Apply, before block for Revert
+	Accounts []*AccountDiff `json:"accounts,omitempty"`
+	Storage  []*StorageDiff `json:"storage,omitempty"`
+}
+
+// Cursor - consumer's cursor after processing of this event
+func (e *Event) Cursor() Cursor {
+	if e.Type == Revert {
+		return Cursor{BlockNumber: uint64(e.BlockNumber) - 1, BlockHash: e.ParentHash}
+	}
+	return Cursor{BlockNumber: uint64(e.BlockNumber), BlockHash: e.BlockHash}
+}
+
+type AccountDiff struct {
+	Address  libcommon.Address `json:"address"`
+	Deleted  bool              `json:"deleted,omitempty"`
+	Nonce    hexutil.Uint64    `json:"nonce"`
+	Balance  *hexutil.Big      `json:"balance"`
+	CodeHash libcommon.Hash    `json:"codeHash"`
+	Code     hexutility.Bytes  `json:"code,omitempty"` // empty if code is not changed by event
+}
+
+type StorageDiff struct {
+	Address  libcommon.Address `json:"address"`
+	Location libcommon.Hash    `json:"location"`
+	Value    hexutility.Bytes  `json:"value"` // empty if slot deleted
+}
+
+// ReceiptsGetter - receipts are not stored, they are re-generated by re-execution of block
+type ReceiptsGetter func(ctx context.Context, tx kv.TemporalTx, block *types.Block) (types.Receipts, error)
+
+type Reader struct {
+	blockReader services.FullBlockReader
+	receipts    ReceiptsGetter
+}
+
+func NewReader(blockReader services.FullBlockReader, receipts ReceiptsGetter) *Reader {
+	return &Reader{blockReader: blockReader, receipts: receipts}
+}
+
+// CatchUp - emits all events after `from` cursor up to latest executed block. Returns cursor of last emitted event.
+// Safe to call again with returned cursor when new blocks arrive: reorgs happened in-between are turned into Revert events.
+func (r *Reader) CatchUp(ctx context.Context, db kv.RoDB, from Cursor, opts Options, emit func(*Event) error) (Cursor, error) {
+	for {
+		var caughtUp bool
+		if err := db.View(ctx, func(tx kv.Tx) (err error) {
+			from, caughtUp, err = r.next(ctx, tx.(kv.TemporalTx), from, opts, eventsPerTx, emit)
+			return err
+		}); err != nil {
+			return from, err
+		}
+		if caughtUp {
+			return from, nil
+		}
+	}
+}
+
+func (r *Reader) next(ctx context.Context, tx kv.TemporalTx, cursor Cursor, opts Options, limit int, emit func(*Event) error) (_ Cursor, caughtUp bool, err error) {
+	head, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return cursor, false, err
+	}
+	if cursor.BlockHash == (libcommon.Hash{}) {
+		if cursor.BlockNumber > head {
+			return cursor, true, nil
+		}
+		hash, ok, err := r.blockReader.CanonicalHash(ctx, tx, cursor.BlockNumber)
+		if err != nil {
+			return cursor, false, err
+		}
+		if !ok {
+			return cursor, false, fmt.Errorf("canonical hash of block %d not found", cursor.BlockNumber)
+		}
+		cursor.BlockHash = hash
+	}
+
+	ancestor, reverted, err := r.commonAncestor(ctx, tx, cursor, head)
+	if err != nil {
+		return cursor, false, err
+	}
+	txNumsReader := rawdbv3.TxNums.WithCustomReadTxNumFunc(freezeblocks.ReadTxNumFuncFromBlockReader(ctx, r.blockReader))
+
+	var emitted int
+	for _, header := range reverted {
+		ev := &Event{Type: Revert, BlockNumber: hexutil.Uint64(header.Number.Uint64()), BlockHash: header.Hash(), ParentHash: header.ParentHash}
+		if opts.WithStateDiff {
+			ancestorTxNum, err := txNumsReader.Max(tx, ancestor)
+			if err != nil {
+				return cursor, false, err
+			}
+			if err := revertDiff(tx, ev, ancestorTxNum+1); err != nil {
+				return cursor, false, err
+			}
+		}
+		if err := emit(ev); err != nil {
+			return cursor, false, err
+		}
+		cursor = ev.Cursor()
+		if emitted++; emitted >= limit {
+			return cursor, false, nil
+		}
+	}
+
+	for blockNum := cursor.BlockNumber + 1; blockNum <= head; blockNum++ {
+		if err := ctx.Err(); err != nil {
+			return cursor, false, err
+		}
+		hash, ok, err := r.blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return cursor, false, err
+		}
+		if !ok {
+			return cursor, false, fmt.Errorf("canonical hash of block %d not found", blockNum)
+		}
+		block, _, err := r.blockReader.BlockWithSenders(ctx, tx, hash, blockNum)
+		if err != nil {
+			return cursor, false, err
+		}
+		if block == nil {
+			return cursor, false, fmt.Errorf("block %d %x not found", blockNum, hash)
+		}
+		ev := &Event{Type: Apply, BlockNumber: hexutil.Uint64(blockNum), BlockHash: hash, ParentHash: block.ParentHash()}
+		if opts.WithReceipts {
+			if ev.Receipts, err = r.receipts(ctx, tx, block); err != nil {
+				return cursor, false, err
+			}
+		}
+		if opts.WithStateDiff {
+			fromTxNum, err := txNumsReader.Min(tx, blockNum)
+			if err != nil {
+				return cursor, false, err
+			}
+			toTxNum, err := txNumsReader.Max(tx, blockNum)
+			if err != nil {
+				return cursor, false, err
+			}
+			if err := applyDiff(tx, ev, fromTxNum, toTxNum+1); err != nil {
+				return cursor, false, err
+			}
+		}
+		if err := emit(ev); err != nil {
+			return cursor, false, err
+		}
+		cursor = ev.Cursor()
+		if emitted++; emitted >= limit && blockNum < head {
+			return cursor, false, nil
+		}
+	}
+	return cursor, true, nil
+}
+
+// commonAncestor - walks from cursor back by parent hashes until canonical executed block.
+// Returns it and all blocks above it - in order of reverting.
+func (r *Reader) commonAncestor(ctx context.Context, tx kv.Tx, cursor Cursor, head uint64) (uint64, []*types.Header, error) {
+	var reverted []*types.Header
+	blockNum, hash := cursor.BlockNumber, cursor.BlockHash
+	for {
+		if blockNum <= head {
+			canonical, ok, err := r.blockReader.CanonicalHash(ctx, tx, blockNum)
+			if err != nil {
+				return 0, nil, err
+			}
+			if ok && canonical == hash {
+				return blockNum, reverted, nil
+			}
+		}
+		if blockNum == 0 {
+			return 0, nil, fmt.Errorf("cursor %d %x: genesis mismatch", cursor.BlockNumber, cursor.BlockHash)
+		}
+		header, err := r.blockReader.Header(ctx, tx, hash, blockNum)
+		if err != nil {
+			return 0, nil, err
+		}
+		if header == nil {
+			return 0, nil, fmt.Errorf("cursor %d %x: block %d %x not found", cursor.BlockNumber, cursor.BlockHash, blockNum, hash)
+		}
+		reverted = append(reverted, header)
+		blockNum, hash = blockNum-1, header.ParentHash
+	}
+}
+
+// applyDiff - keys changed in [fromTxNum, toTxNum) with their values at toTxNum
+func applyDiff(tx kv.TemporalTx, ev *Event, fromTxNum, toTxNum uint64) error {
+	d := newDiffBuilder()
+	for _, h := range []struct {
+		history kv.History
+		domain  kv.Domain
+	}{{kv.AccountsHistory, kv.AccountsDomain}, {kv.CodeHistory, kv.CodeDomain}, {kv.StorageHistory, kv.StorageDomain}} {
+		it, err := tx.HistoryRange(h.history, int(fromTxNum), int(toTxNum), order.Asc, kv.Unlim)
+		if err != nil {
+			return err
+		}
+		for it.HasNext() {
+			k, _, err := it.Next()
+			if err != nil {
+				it.Close()
+				return err
+			}
+			v, _, err := tx.DomainGetAsOf(h.domain, k, nil, toTxNum)
+			if err != nil {
+				it.Close()
+				return err
+			}
+			d.add(h.domain, k, v)
+		}
+		it.Close()
+	}
+	return d.build(tx, ev, toTxNum)
+}
+
+// revertDiff - keys changed by block with their values before block: taken from ChangeSets3 (kept for `config3.MaxReorgDepthV3` blocks).
+// ChangeSets3 doesn't have value if it was written at previous step: then value of common ancestor is used
+// (blocks above ancestor are already unwound from domains) - it's correct after revert of last non-canonical block.
+func revertDiff(tx kv.TemporalTx, ev *Event, ancestorTxNum uint64) error {
+	diffSet, ok, err := libstate.ReadDiffSet(tx, uint64(ev.BlockNumber), ev.BlockHash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("state diff of block %d %x not found: reorg is too deep or block was executed far from chain tip", ev.BlockNumber, ev.BlockHash)
+	}
+	d := newDiffBuilder()
+	for _, domain := range []kv.Domain{kv.AccountsDomain, kv.CodeDomain, kv.StorageDomain} {
+		exact := map[string]bool{}
+		for _, entry := range diffSet[domain] {
+			k, stepBytes := entry.Key[:len(entry.Key)-8], entry.Key[len(entry.Key)-8:]
+			if len(entry.Value) > 0 || bytes.Equal(stepBytes, entry.PrevStepBytes) {
+				exact[string(k)] = true
+				d.add(domain, k, entry.Value)
+				continue
+			}
+			if exact[string(k)] {
+				continue
+			}
+			v, _, err := tx.DomainGetAsOf(domain, k, nil, ancestorTxNum)
+			if err != nil {
+				return err
+			}
+			d.add(domain, k, v)
+		}
+	}
+	return d.build(tx, ev, ancestorTxNum)
+}
+
+type diffBuilder struct {
+	accounts map[libcommon.Address][]byte
+	codes    map[libcommon.Address][]byte
+	storage  []*StorageDiff
+	storageI map[string]int
+}
+
+func newDiffBuilder() *diffBuilder {
+	return &diffBuilder{accounts: map[libcommon.Address][]byte{}, codes: map[libcommon.Address][]byte{}, storageI: map[string]int{}}
+}
+
+func (d *diffBuilder) add(domain kv.Domain, k, v []byte) {
+	switch domain {
+	case kv.AccountsDomain:
+		d.accounts[libcommon.BytesToAddress(k)] = libcommon.Copy(v)
+	case kv.CodeDomain:
+		d.codes[libcommon.BytesToAddress(k)] = libcommon.Copy(v)
+	case kv.StorageDomain:
+		s := &StorageDiff{
+			Address:  libcommon.BytesToAddress(k[:length.Addr]),
+			Location: libcommon.BytesToHash(k[length.Addr:]),
+			Value:    libcommon.Copy(v),
+		}
+		if i, ok := d.storageI[string(k)]; ok {
+			d.storage[i] = s
+			return
+		}
+		d.storageI[string(k)] = len(d.storage)
+		d.storage = append(d.storage, s)
+	}
+}
+
+// build - fills event. code changes are attached to accounts: if account itself has no diff - its value at `txNum` is used
+func (d *diffBuilder) build(tx kv.TemporalTx, ev *Event, txNum uint64) error {
+	for addr := range d.codes {
+		if _, ok := d.accounts[addr]; ok {
+			continue
+		}
+		v, _, err := tx.DomainGetAsOf(kv.AccountsDomain, addr[:], nil, txNum)
+		if err != nil {
+			return err
+		}
+		d.accounts[addr] = v
+	}
+	for addr, v := range d.accounts {
+		diff := &AccountDiff{Address: addr, Deleted: len(v) == 0, Balance: (*hexutil.Big)(new(big.Int))}
+		if !diff.Deleted {
+			var acc accounts.Account
+			if err := accounts.DeserialiseV3(&acc, v); err != nil {
+				return fmt.Errorf("account %x: %w", addr, err)
+			}
+			diff.Nonce, diff.Balance, diff.CodeHash = hexutil.Uint64(acc.Nonce), (*hexutil.Big)(acc.Balance.ToBig()), acc.CodeHash
+		}
+		diff.Code = d.codes[addr]
+		ev.Accounts = append(ev.Accounts, diff)
+	}
+	sort.Slice(ev.Accounts, func(i, j int) bool {
+		return bytes.Compare(ev.Accounts[i].Address[:], ev.Accounts[j].Address[:]) < 0
+	})
+	ev.Storage = d.storage
+	return nil
+}
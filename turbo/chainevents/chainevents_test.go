@@ -0,0 +1,129 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package chainevents_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/turbo/chainevents"
+	"github.com/erigontech/erigon/turbo/jsonrpc/receipts"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+func TestCatchUpAfterReorg(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSignerForChainID(nil)
+		gspec   = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1e18)}},
+		}
+	)
+	m := mock.MockWithGenesis(t, gspec, key, false)
+	require := require.New(t)
+
+	// every block sends 1 wei to `to(chainID, i)`
+	to := func(chainID, i int) libcommon.Address { return libcommon.Address{byte(chainID), byte(i + 1)} }
+	genChain := func(chainID, n int) *core.ChainPack {
+		chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, n, func(i int, b *core.BlockGen) {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(address), to(chainID, i), uint256.NewInt(1), 21000, uint256.NewInt(1), nil), *signer, key)
+			require.NoError(err)
+			b.AddTx(txn)
+		})
+		require.NoError(err)
+		return chain
+	}
+	chain, longerChain := genChain(1, 3), genChain(2, 4)
+
+	gen := receipts.NewGenerator(32, m.BlockReader, m.Engine)
+	reader := chainevents.NewReader(m.BlockReader, func(ctx context.Context, tx kv.TemporalTx, block *types.Block) (types.Receipts, error) {
+		return gen.GetReceipts(ctx, m.ChainConfig, tx, block)
+	})
+	opts := chainevents.Options{WithReceipts: true, WithStateDiff: true}
+	catchUp := func(from chainevents.Cursor) (events []*chainevents.Event, cursor chainevents.Cursor) {
+		cursor, err := reader.CatchUp(m.Ctx, m.DB, from, opts, func(ev *chainevents.Event) error {
+			events = append(events, ev)
+			return nil
+		})
+		require.NoError(err)
+		return events, cursor
+	}
+	account := func(ev *chainevents.Event, addr libcommon.Address) *chainevents.AccountDiff {
+		for _, acc := range ev.Accounts {
+			if acc.Address == addr {
+				return acc
+			}
+		}
+		return nil
+	}
+
+	require.NoError(m.InsertChain(chain))
+	events, cursor := catchUp(chainevents.Cursor{BlockNumber: 0})
+	require.Len(events, 3)
+	for i, ev := range events {
+		require.Equal(chainevents.Apply, ev.Type)
+		require.Equal(chain.Blocks[i].Hash(), ev.BlockHash)
+		require.Len(ev.Receipts, 1)
+		require.Equal(types.ReceiptStatusSuccessful, ev.Receipts[0].Status)
+		require.Equal(uint64(i+1), uint64(account(ev, address).Nonce))
+		require.Equal(big.NewInt(1), account(ev, to(1, i)).Balance.ToInt())
+	}
+	require.Equal(chainevents.Cursor{BlockNumber: 3, BlockHash: chain.TopBlock.Hash()}, cursor)
+
+	// nothing new
+	events, cursor = catchUp(cursor)
+	require.Empty(events)
+	require.Equal(chainevents.Cursor{BlockNumber: 3, BlockHash: chain.TopBlock.Hash()}, cursor)
+
+	// reorg happened while consumer was offline: it must get reverts down to genesis, then applies of new chain
+	require.NoError(m.InsertChain(longerChain))
+	events, cursor = catchUp(cursor)
+	require.Len(events, 7)
+	for i, ev := range events[:3] {
+		reverted := chain.Blocks[2-i]
+		require.Equal(chainevents.Revert, ev.Type)
+		require.Equal(reverted.Hash(), ev.BlockHash)
+		require.Equal(reverted.ParentHash(), ev.ParentHash)
+		require.Empty(ev.Receipts)
+		require.Equal(uint64(2-i), uint64(account(ev, address).Nonce))
+		require.True(account(ev, to(1, 2-i)).Deleted)
+	}
+	for i, ev := range events[3:] {
+		require.Equal(chainevents.Apply, ev.Type)
+		require.Equal(longerChain.Blocks[i].Hash(), ev.BlockHash)
+		require.Equal(uint64(i+1), uint64(account(ev, address).Nonce))
+		require.Equal(big.NewInt(1), account(ev, to(2, i)).Balance.ToInt())
+		require.Nil(account(ev, to(1, i)))
+	}
+	require.Equal(chainevents.Cursor{BlockNumber: 4, BlockHash: longerChain.TopBlock.Hash()}, cursor)
+
+	// cursor on unknown block
+	_, err := reader.CatchUp(m.Ctx, m.DB, chainevents.Cursor{BlockNumber: 2, BlockHash: libcommon.Hash{1}}, opts, func(*chainevents.Event) error { return nil })
+	require.Error(err)
+}
@@ -167,6 +167,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.DownloaderVerifyFlag,
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
+	&utils.HeimdallQuorumFlag,
 	&utils.WebSeedsFlag,
 	&utils.WithoutHeimdallFlag,
 	&utils.BorBlockPeriodFlag,
@@ -175,6 +176,8 @@ var DefaultFlags = []cli.Flag{
 	&utils.WithHeimdallWaypoints,
 	&utils.PolygonSyncFlag,
 	&utils.PolygonSyncStageFlag,
+	&utils.PolygonSyncFromSnapshotsFlag,
+	&utils.PolygonSyncVerifySnapshotsFlag,
 	&utils.EthStatsURLFlag,
 	&utils.OverridePragueFlag,
 
@@ -182,8 +185,14 @@ var DefaultFlags = []cli.Flag{
 	&utils.CaplinDiscoveryPortFlag,
 	&utils.CaplinDiscoveryTCPPortFlag,
 	&utils.CaplinCheckpointSyncUrlFlag,
+	&utils.CaplinCheckpointSyncStateFileFlag,
+	&utils.CaplinCheckpointSyncBlockFileFlag,
+	&utils.CaplinCheckpointSyncFallbackFlag,
+	&utils.CaplinWeakSubjectivityCheckpointFlag,
 	&utils.CaplinSubscribeAllTopicsFlag,
 	&utils.CaplinMaxPeerCount,
+	&utils.CaplinCustodyGroupCountFlag,
+	&utils.CaplinGossipTraceFileFlag,
 	&utils.SentinelAddrFlag,
 	&utils.SentinelPortFlag,
 	&utils.SentinelBootnodes,
@@ -220,7 +229,17 @@ var DefaultFlags = []cli.Flag{
 	&utils.CaplinArchiveFlag,
 	&utils.CaplinEnableSnapshotGeneration,
 	&utils.CaplinMevRelayUrl,
+	&utils.CaplinMevRelayTimeoutFlag,
+	&utils.CaplinMevBuilderBoostFactorFlag,
 	&utils.CaplinValidatorMonitorFlag,
+	&utils.CaplinValidatorMonitorValidatorsFlag,
+	&utils.CaplinValidatorClientFlag,
+	&utils.CaplinValidatorKeystoresDirFlag,
+	&utils.CaplinValidatorPasswordFileFlag,
+	&utils.CaplinValidatorFeeRecipientFlag,
+	&utils.CaplinValidatorGraffitiFlag,
+	&utils.CaplinKeymanagerAPIAddrFlag,
+	&utils.CaplinKeymanagerAPITokenFileFlag,
 	&utils.CaplinCustomConfigFlag,
 	&utils.CaplinCustomGenesisFlag,
 
@@ -231,4 +250,7 @@ var DefaultFlags = []cli.Flag{
 	&SyncLoopBlockLimitFlag,
 	&SyncLoopBreakAfterFlag,
 	&SyncParallelStateFlushing,
+	&SyncParallelExecution,
+	&SyncExecProfile,
+	&SyncSlowBlockThreshold,
 }
@@ -156,6 +156,24 @@ var (
 		Value: true,
 	}
 
+	SyncParallelExecution = cli.BoolFlag{
+		Name:  "sync.parallel-execution",
+		Usage: "Enables optimistic parallel execution of transactions when far behind chain-tip (uses --exec.workers)",
+		Value: false,
+	}
+
+	SyncExecProfile = cli.BoolFlag{
+		Name:  "sync.exec-profile",
+		Usage: "Record per-block execution profile: time, gas/s, state reads, commitment time and most expensive txs. Available by /debug/diag/block-execution-profiles and debug_getBlockExecutionProfiles",
+		Value: false,
+	}
+
+	SyncSlowBlockThreshold = cli.DurationFlag{
+		Name:  "sync.slow-block-threshold",
+		Usage: "With --sync.exec-profile: log breakdown of blocks which execution took longer than this. 0 - disabled",
+		Value: 2 * time.Second,
+	}
+
 	UploadLocationFlag = cli.StringFlag{
 		Name:  "upload.location",
 		Usage: "Location to upload snapshot segments to",
@@ -351,6 +369,9 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		cfg.Sync.LoopBlockLimit = limit
 	}
 	cfg.Sync.ParallelStateFlushing = ctx.Bool(SyncParallelStateFlushing.Name)
+	cfg.Sync.ParallelExecution = ctx.Bool(SyncParallelExecution.Name)
+	cfg.Sync.ExecProfile = ctx.Bool(SyncExecProfile.Name)
+	cfg.Sync.SlowBlockThreshold = ctx.Duration(SyncSlowBlockThreshold.Name)
 
 	if location := ctx.String(UploadLocationFlag.Name); len(location) > 0 {
 		cfg.Sync.UploadLocation = location
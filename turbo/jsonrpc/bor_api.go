@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon/consensus"
 	"github.com/erigontech/erigon/polygon/bor"
@@ -41,6 +42,14 @@ type BorAPI interface {
 	GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (common.Address, error)
 	GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash) (BlockSigners, error)
 	GetRootHash(start uint64, end uint64) (string, error)
+
+	// State sync events related (see ./bor_state_sync.go)
+	GetStateSyncEvents(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*StateSyncEvent, error)
+	GetStateSyncEventsByIdRange(ctx context.Context, fromId hexutil.Uint64, toId hexutil.Uint64) ([]*StateSyncEvent, error)
+	GetStateSyncEventsByContract(ctx context.Context, contract common.Address, fromId hexutil.Uint64, toId hexutil.Uint64) ([]*StateSyncEvent, error)
+
+	// Finality related (see ./bor_finality.go)
+	GetFinalityProof(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*BorFinalityProof, error)
 }
 
 type spanProducersReader interface {
@@ -0,0 +1,257 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/bor"
+	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/turbo/rpchelper"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+var errBlockNotFinalized = errors.New("block is not covered by a checkpoint or milestone yet")
+
+const (
+	borFinalityProofCheckpoint = "checkpoint"
+	borFinalityProofMilestone  = "milestone"
+)
+
+// BorFinalityProof proves the finality of a bor block with the checkpoint or milestone covering it.
+// For checkpoints Proof is the merkle proof of the block's header leaf against the checkpoint root hash,
+// which is what gets submitted to the root chain. Milestones commit to the hash of their end block
+// instead, so Proof is empty and the block is tied to the milestone by the parent hash chain.
+type BorFinalityProof struct {
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Kind        string          `json:"kind"`
+	ID          hexutil.Uint64  `json:"id"`
+	StartBlock  hexutil.Uint64  `json:"startBlock"`
+	EndBlock    hexutil.Uint64  `json:"endBlock"`
+	RootHash    common.Hash     `json:"rootHash"`
+	Proposer    common.Address  `json:"proposer"`
+	Timestamp   hexutil.Uint64  `json:"timestamp"`
+	Leaf        *common.Hash    `json:"leaf,omitempty"`
+	LeafIndex   *hexutil.Uint64 `json:"leafIndex,omitempty"`
+	Proof       []common.Hash   `json:"proof"`
+}
+
+type borWaypointsReader interface {
+	CheckpointsFromBlock(ctx context.Context, startBlock uint64) (heimdall.Checkpoints, error)
+	MilestonesFromBlock(ctx context.Context, startBlock uint64) (heimdall.Milestones, error)
+}
+
+// GetFinalityProof returns the checkpoint covering the given block along with the merkle proof of its header,
+// or the milestone covering it if no checkpoint has been submitted for the block yet
+func (api *BorImpl) GetFinalityProof(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*BorFinalityProof, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNum, blockHash, _, err := rpchelper.GetCanonicalBlockNumber(ctx, blockNrOrHash, tx, api._blockReader, api.filters)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := api.coveringCheckpoint(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil {
+		return api.checkpointFinalityProof(ctx, tx, blockNum, blockHash, checkpoint)
+	}
+
+	milestone, err := api.coveringMilestone(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if milestone != nil {
+		return api.milestoneFinalityProof(ctx, tx, blockNum, blockHash, milestone)
+	}
+
+	return nil, errBlockNotFinalized
+}
+
+func (api *BorImpl) checkpointFinalityProof(ctx context.Context, tx kv.Tx, blockNum uint64, blockHash common.Hash, checkpoint *heimdall.Checkpoint) (*BorFinalityProof, error) {
+	blockRange := checkpoint.BlockNumRange()
+	if blockRange.Len() > bor.MaxCheckpointLength {
+		return nil, &bor.MaxCheckpointLengthExceededError{Start: blockRange.Start, End: blockRange.End}
+	}
+
+	headers := make([]*types.Header, 0, blockRange.Len())
+	for number := blockRange.Start; number <= blockRange.End; number++ {
+		header, err := api._blockReader.HeaderByNumber(ctx, tx, number)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("%w: %d", errUnknownBlock, number)
+		}
+
+		headers = append(headers, header)
+	}
+
+	leafIndex := blockNum - blockRange.Start
+	root, leaf, proof, err := bor.ComputeHeadersRootHashProof(headers, int(leafIndex))
+	if err != nil {
+		return nil, err
+	}
+	if common.BytesToHash(root) != checkpoint.RootHash() {
+		return nil, fmt.Errorf("local headers root hash %x does not match checkpoint %d root hash %x", root, checkpoint.Id, checkpoint.RootHash())
+	}
+
+	result := newBorFinalityProof(borFinalityProofCheckpoint, checkpoint.RawId(), checkpoint, blockNum, blockHash)
+	leafHash := common.BytesToHash(leaf)
+	index := hexutil.Uint64(leafIndex)
+	result.Leaf = &leafHash
+	result.LeafIndex = &index
+	for _, sibling := range proof {
+		result.Proof = append(result.Proof, common.BytesToHash(sibling))
+	}
+
+	return result, nil
+}
+
+func (api *BorImpl) milestoneFinalityProof(ctx context.Context, tx kv.Tx, blockNum uint64, blockHash common.Hash, milestone *heimdall.Milestone) (*BorFinalityProof, error) {
+	endBlock := milestone.EndBlock().Uint64()
+	endHash, ok, err := api._blockReader.CanonicalHash(ctx, tx, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownBlock, endBlock)
+	}
+	if endHash != milestone.RootHash() {
+		return nil, fmt.Errorf("local block %d hash %x does not match milestone %d end block hash %x", endBlock, endHash, milestone.Id, milestone.RootHash())
+	}
+
+	return newBorFinalityProof(borFinalityProofMilestone, milestone.RawId(), milestone, blockNum, blockHash), nil
+}
+
+func newBorFinalityProof(kind string, id uint64, waypoint heimdall.Waypoint, blockNum uint64, blockHash common.Hash) *BorFinalityProof {
+	var proposer common.Address
+	switch w := waypoint.(type) {
+	case *heimdall.Checkpoint:
+		proposer = w.Fields.Proposer
+	case *heimdall.Milestone:
+		proposer = w.Fields.Proposer
+	}
+
+	return &BorFinalityProof{
+		BlockNumber: hexutil.Uint64(blockNum),
+		BlockHash:   blockHash,
+		Kind:        kind,
+		ID:          hexutil.Uint64(id),
+		StartBlock:  hexutil.Uint64(waypoint.StartBlock().Uint64()),
+		EndBlock:    hexutil.Uint64(waypoint.EndBlock().Uint64()),
+		RootHash:    waypoint.RootHash(),
+		Proposer:    proposer,
+		Timestamp:   hexutil.Uint64(waypoint.Timestamp()),
+		Proof:       []common.Hash{},
+	}
+}
+
+// coveringCheckpoint looks up the checkpoint covering blockNum in the heimdall store if there is one,
+// then in the block reader which also serves checkpoints from snapshots
+func (api *BorImpl) coveringCheckpoint(ctx context.Context, tx kv.Tx, blockNum uint64) (*heimdall.Checkpoint, error) {
+	if reader, ok := api.spanProducersReader.(borWaypointsReader); ok {
+		checkpoints, err := reader.CheckpointsFromBlock(ctx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if len(checkpoints) > 0 && checkpoints[0].CmpRange(blockNum) == 0 {
+			return checkpoints[0], nil
+		}
+	}
+
+	return findWaypoint[heimdall.Checkpoint](ctx, tx, blockNum, api._blockReader.LastCheckpointId, api._blockReader.Checkpoint, freezeblocks.ErrCheckpointNotFound)
+}
+
+func (api *BorImpl) coveringMilestone(ctx context.Context, tx kv.Tx, blockNum uint64) (*heimdall.Milestone, error) {
+	if reader, ok := api.spanProducersReader.(borWaypointsReader); ok {
+		milestones, err := reader.MilestonesFromBlock(ctx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if len(milestones) > 0 && milestones[0].CmpRange(blockNum) == 0 {
+			return milestones[0], nil
+		}
+	}
+
+	return findWaypoint[heimdall.Milestone](ctx, tx, blockNum, api._blockReader.LastMilestoneId, api._blockReader.Milestone, freezeblocks.ErrMilestoneNotFound)
+}
+
+// findWaypoint binary searches waypoint ids for the one covering blockNum. Old waypoints may have been
+// pruned, so missing ids are treated as being below blockNum.
+func findWaypoint[T any, PT interface {
+	*T
+	heimdall.Waypoint
+}](
+	ctx context.Context,
+	tx kv.Tx,
+	blockNum uint64,
+	lastId func(ctx context.Context, tx kv.Tx) (uint64, bool, error),
+	get func(ctx context.Context, tx kv.Getter, id uint64) ([]byte, error),
+	errNotFound error,
+) (PT, error) {
+	last, ok, err := lastId(ctx, tx)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	lo, hi := uint64(1), last
+	for lo <= hi {
+		mid := lo + (hi-lo)/2
+
+		data, err := get(ctx, tx, mid)
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				lo = mid + 1
+				continue
+			}
+
+			return nil, err
+		}
+
+		var waypoint PT = new(T)
+		if err := json.Unmarshal(data, waypoint); err != nil {
+			return nil, err
+		}
+
+		switch waypoint.CmpRange(blockNum) {
+		case 0:
+			return waypoint, nil
+		case -1:
+			hi = mid - 1
+		default:
+			lo = mid + 1
+		}
+	}
+
+	return nil, nil
+}
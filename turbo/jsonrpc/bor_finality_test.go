@@ -0,0 +1,70 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+func TestFindWaypoint(t *testing.T) {
+	ctx := context.Background()
+
+	// checkpoints 1..10 cover blocks [0, 99], [100, 199], ... [900, 999]; 1..3 have been pruned
+	checkpoints := map[uint64][]byte{}
+	for id := uint64(4); id <= 10; id++ {
+		data, err := json.Marshal(&heimdall.Checkpoint{
+			Id: heimdall.CheckpointId(id),
+			Fields: heimdall.WaypointFields{
+				StartBlock: new(big.Int).SetUint64((id - 1) * 100),
+				EndBlock:   new(big.Int).SetUint64(id*100 - 1),
+			},
+		})
+		require.NoError(t, err)
+		checkpoints[id] = data
+	}
+
+	lastId := func(context.Context, kv.Tx) (uint64, bool, error) { return 10, true, nil }
+	get := func(_ context.Context, _ kv.Getter, id uint64) ([]byte, error) {
+		if data, ok := checkpoints[id]; ok {
+			return data, nil
+		}
+		return nil, fmt.Errorf("%w, id: %d", freezeblocks.ErrCheckpointNotFound, id)
+	}
+
+	for blockNum, wantId := range map[uint64]uint64{300: 4, 350: 4, 699: 7, 700: 8, 999: 10} {
+		checkpoint, err := findWaypoint[heimdall.Checkpoint](ctx, nil, blockNum, lastId, get, freezeblocks.ErrCheckpointNotFound)
+		require.NoError(t, err)
+		require.NotNil(t, checkpoint)
+		require.Equal(t, wantId, checkpoint.RawId(), "block %d", blockNum)
+	}
+
+	for _, blockNum := range []uint64{1000, 299} {
+		checkpoint, err := findWaypoint[heimdall.Checkpoint](ctx, nil, blockNum, lastId, get, freezeblocks.ErrCheckpointNotFound)
+		require.NoError(t, err)
+		require.Nil(t, checkpoint, "block %d", blockNum)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/polygon/bor/borabi"
+	"github.com/erigontech/erigon/polygon/bor/borcfg"
+	bortypes "github.com/erigontech/erigon/polygon/bor/types"
+	"github.com/erigontech/erigon/polygon/bridge"
+	"github.com/erigontech/erigon/polygon/heimdall"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/turbo/rpchelper"
+)
+
+// maxStateSyncEventsRange caps the number of event ids scanned by a single range query
+const maxStateSyncEventsRange = 1000
+
+var (
+	errStateSyncEventsNotBor         = errors.New("state sync events are only available on bor chains")
+	errStateSyncEventsNoBridgeReader = errors.New("state sync event range queries require a local polygon bridge database")
+)
+
+var stateCommittedEventId = borabi.StateReceiverContractABI().Events["StateCommitted"].ID
+
+// StateSyncEvent is a state sync event along with the bor state sync transaction it was applied in.
+// Block and transaction fields are null for events which have not been committed to a block yet.
+// Success is the result of the StateReceiver commitState call and is null when it could not be determined.
+type StateSyncEvent struct {
+	ID              hexutil.Uint64   `json:"id"`
+	Contract        common.Address   `json:"contract"`
+	Data            hexutility.Bytes `json:"data"`
+	RootTxHash      common.Hash      `json:"rootTxHash"`
+	RootLogIndex    hexutil.Uint64   `json:"rootLogIndex"`
+	Time            hexutil.Uint64   `json:"time"`
+	BlockNumber     *hexutil.Uint64  `json:"blockNumber"`
+	BlockHash       *common.Hash     `json:"blockHash"`
+	TransactionHash *common.Hash     `json:"transactionHash"`
+	Success         *bool            `json:"success"`
+}
+
+func newStateSyncEvent(event *heimdall.EventRecordWithTime) *StateSyncEvent {
+	return &StateSyncEvent{
+		ID:           hexutil.Uint64(event.ID),
+		Contract:     event.Contract,
+		Data:         event.Data,
+		RootTxHash:   event.TxHash,
+		RootLogIndex: hexutil.Uint64(event.LogIndex),
+		Time:         hexutil.Uint64(event.Time.Unix()),
+	}
+}
+
+// GetStateSyncEvents returns the state sync events committed in the given block
+func (api *BorImpl) GetStateSyncEvents(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*StateSyncEvent, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig.Bor == nil {
+		return nil, errStateSyncEventsNotBor
+	}
+
+	blockNum, blockHash, _, err := rpchelper.GetBlockNumber(ctx, blockNrOrHash, tx, api._blockReader, api.filters)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := api.stateSyncEvents(ctx, tx, blockHash, blockNum, chainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*StateSyncEvent, 0, len(msgs))
+	for _, msg := range msgs {
+		var event heimdall.EventRecordWithTime
+		if err := event.UnmarshallBytes(msg.Data()); err != nil {
+			return nil, err
+		}
+
+		result = append(result, newStateSyncEvent(&event))
+	}
+
+	if err := api.fillStateSyncEventsExecution(ctx, tx, blockNum, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetStateSyncEventsByIdRange returns the state sync events with ids in [fromId, toId]
+func (api *BorImpl) GetStateSyncEventsByIdRange(ctx context.Context, fromId hexutil.Uint64, toId hexutil.Uint64) ([]*StateSyncEvent, error) {
+	return api.stateSyncEventsByIdRange(ctx, uint64(fromId), uint64(toId), nil)
+}
+
+// GetStateSyncEventsByContract returns the state sync events with ids in [fromId, toId] received by the given contract
+func (api *BorImpl) GetStateSyncEventsByContract(ctx context.Context, contract common.Address, fromId hexutil.Uint64, toId hexutil.Uint64) ([]*StateSyncEvent, error) {
+	return api.stateSyncEventsByIdRange(ctx, uint64(fromId), uint64(toId), &contract)
+}
+
+func (api *BorImpl) stateSyncEventsByIdRange(ctx context.Context, fromId, toId uint64, contract *common.Address) ([]*StateSyncEvent, error) {
+	if toId < fromId {
+		return nil, fmt.Errorf("invalid state sync event id range: from %d > to %d", fromId, toId)
+	}
+	if toId-fromId >= maxStateSyncEventsRange {
+		return nil, fmt.Errorf("state sync event id range too large: %d > %d", toId-fromId+1, maxStateSyncEventsRange)
+	}
+
+	eventsReader, ok := api.bridgeReader.(bridge.PolygonBridgeEventsReader)
+	if !ok {
+		return nil, errStateSyncEventsNoBridgeReader
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig.Bor == nil {
+		return nil, errStateSyncEventsNotBor
+	}
+
+	// events pruned from the bridge store after being frozen are read from the bor snapshots
+	frozenEvents, frozenBlockNums, err := api._blockReader.FrozenEventsByIdRange(fromId, toId)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*heimdall.EventRecordWithTime, 0, len(frozenEvents))
+	for _, raw := range frozenEvents {
+		var event heimdall.EventRecordWithTime
+		if err := event.UnmarshallBytes(raw); err != nil {
+			return nil, err
+		}
+
+		events = append(events, &event)
+	}
+
+	if len(events) > 0 {
+		fromId = events[len(events)-1].ID + 1
+	}
+	if fromId <= toId {
+		storeEvents, err := eventsReader.EventsByIdRange(ctx, fromId, toId)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, storeEvents...)
+	}
+
+	result := make([]*StateSyncEvent, 0, len(events))
+	blockEvents := map[uint64][]*StateSyncEvent{}
+	var blockNums []uint64
+	for i, event := range events {
+		if contract != nil && event.Contract != *contract {
+			continue
+		}
+
+		e := newStateSyncEvent(event)
+		result = append(result, e)
+
+		var blockNum uint64
+		if i < len(frozenBlockNums) {
+			blockNum = frozenBlockNums[i]
+		} else {
+			var ok bool
+			blockNum, ok, err = eventsReader.EventBlockNum(ctx, event.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if _, seen := blockEvents[blockNum]; !seen {
+			blockNums = append(blockNums, blockNum)
+		}
+		blockEvents[blockNum] = append(blockEvents[blockNum], e)
+	}
+
+	for _, blockNum := range blockNums {
+		if err := api.fillStateSyncEventsExecution(ctx, tx, blockNum, blockEvents[blockNum]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// fillStateSyncEventsExecution sets the block, bor transaction and execution result of events committed in blockNum.
+// The execution result is read from the StateCommitted logs in the block's bor receipt.
+func (api *BorImpl) fillStateSyncEventsExecution(ctx context.Context, tx kv.Tx, blockNum uint64, events []*StateSyncEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return nil
+	}
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	blockHash := block.Hash()
+	number := hexutil.Uint64(blockNum)
+	borTxHash := bortypes.ComputeBorTxHash(blockNum, blockHash)
+	for _, event := range events {
+		event.BlockNumber = &number
+		event.BlockHash = &blockHash
+		event.TransactionHash = &borTxHash
+	}
+
+	msgs, err := api.stateSyncEvents(ctx, tx, blockHash, blockNum, chainConfig)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	receipts, err := api.getReceipts(ctx, tx, block)
+	if err != nil {
+		return fmt.Errorf("getReceipts error: %w", err)
+	}
+
+	borReceipt, err := api.borReceiptGenerator.GenerateBorReceipt(ctx, tx, block, msgs, chainConfig, receipts)
+	if err != nil {
+		return err
+	}
+
+	results := stateCommittedResults(borReceipt.Logs, chainConfig.Bor.(*borcfg.BorConfig).StateReceiverContractAddress())
+	for _, event := range events {
+		if success, ok := results[uint64(event.ID)]; ok {
+			event.Success = &success
+		}
+	}
+
+	return nil
+}
+
+// stateCommittedResults maps state sync event ids to the success flag of their StateCommitted log
+func stateCommittedResults(logs types.Logs, stateReceiverContract common.Address) map[uint64]bool {
+	results := map[uint64]bool{}
+	for _, log := range logs {
+		if log.Address != stateReceiverContract || len(log.Topics) != 2 || log.Topics[0] != stateCommittedEventId {
+			continue
+		}
+		if len(log.Data) != 32 {
+			continue
+		}
+
+		results[log.Topics[1].Big().Uint64()] = log.Data[31] == 1
+	}
+
+	return results
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/core/types"
+)
+
+func TestStateCommittedResults(t *testing.T) {
+	stateReceiver := common.HexToAddress("0x0000000000000000000000000000000000001001")
+	stateCommitted := func(address common.Address, id int64, success bool) *types.Log {
+		data := make([]byte, 32)
+		if success {
+			data[31] = 1
+		}
+
+		return &types.Log{
+			Address: address,
+			Topics:  []common.Hash{stateCommittedEventId, common.BigToHash(big.NewInt(id))},
+			Data:    data,
+		}
+	}
+
+	logs := types.Logs{
+		stateCommitted(stateReceiver, 7, true),
+		// log emitted by the receiving contract
+		{Address: common.HexToAddress("0x01"), Topics: []common.Hash{common.HexToHash("0x02")}},
+		stateCommitted(stateReceiver, 8, false),
+		// StateCommitted lookalike from another contract
+		stateCommitted(common.HexToAddress("0x03"), 9, true),
+	}
+
+	require.Equal(t, map[uint64]bool{7: true, 8: false}, stateCommittedResults(logs, stateReceiver))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/diagnostics"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/order"
 	"github.com/erigontech/erigon-lib/kv/rawdbv3"
@@ -62,6 +63,7 @@ type PrivateDebugAPI interface {
 	AccountAt(ctx context.Context, blockHash common.Hash, txIndex uint64, account common.Address) (*AccountResult, error)
 	GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
 	GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
+	GetBlockExecutionProfiles(ctx context.Context, limit *int) ([]diagnostics.BlockExecutionProfile, error)
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
@@ -69,6 +71,13 @@ type PrivateDebugAPIImpl struct {
 	*BaseAPI
 	db     kv.RoDB
 	GasCap uint64
+
+	execProfiles blockExecutionProfilesReader // nil in standalone rpcdaemon
+}
+
+// blockExecutionProfilesReader - diagnostics client of Erigon process, which receives profiles from stage Execution
+type blockExecutionProfilesReader interface {
+	BlockExecutionProfiles(limit int) ([]diagnostics.BlockExecutionProfile, error)
 }
 
 // NewPrivateDebugAPI returns PrivateDebugAPIImpl instance
@@ -401,3 +410,21 @@ func (api *PrivateDebugAPIImpl) GetRawBlock(ctx context.Context, blockNrOrHash r
 	}
 	return rlp.EncodeToBytes(block)
 }
+
+// GetBlockExecutionProfiles implements debug_getBlockExecutionProfiles. Returns up to `limit` (default - all available) last
+// per-block execution profiles, newest first. Profiles are recorded by stage Execution with `--sync.exec-profile` and kept in
+// memory of diagnostics client of Erigon process: available only by RPC embedded into Erigon (not by standalone rpcdaemon)
+// and only if diagnostics are not disabled.
+func (api *PrivateDebugAPIImpl) GetBlockExecutionProfiles(ctx context.Context, limit *int) ([]diagnostics.BlockExecutionProfile, error) {
+	if limit != nil && *limit < 0 {
+		return nil, fmt.Errorf("negative limit %d", *limit)
+	}
+	var l int
+	if limit != nil {
+		l = *limit
+	}
+	if api.execProfiles == nil {
+		return nil, errors.New("block execution profiles are available only by RPC embedded into Erigon")
+	}
+	return api.execProfiles.BlockExecutionProfiles(l)
+}
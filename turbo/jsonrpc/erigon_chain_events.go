@@ -0,0 +1,115 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/common/debug"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/turbo/chainevents"
+	"github.com/erigontech/erigon/turbo/rpchelper"
+)
+
+// ChainEventsArgs - cursor of consumer (last block it processed) and content of events
+type ChainEventsArgs struct {
+	BlockNumber   *hexutil.Uint64 `json:"blockNumber"` // nil - latest executed block
+	BlockHash     *common.Hash    `json:"blockHash"`   // nil - canonical block of BlockNumber
+	WithReceipts  bool            `json:"withReceipts"`
+	WithStateDiff bool            `json:"withStateDiff"`
+}
+
+// ChainEvents implements erigon_subscribe("chainEvents", args). Sends "revert" events for blocks of cursor which
+// are not canonical anymore, then "apply" events for canonical blocks after it - and keeps sending them as chain changes.
+// Consumer can re-subscribe with cursor of last processed event and will not lose any events.
+func (api *ErigonImpl) ChainEvents(ctx context.Context, args *ChainEventsArgs) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if args == nil {
+		args = &ChainEventsArgs{}
+	}
+
+	var cursor chainevents.Cursor
+	if args.BlockNumber == nil {
+		if err := api.db.View(ctx, func(tx kv.Tx) (err error) {
+			cursor.BlockNumber, err = rpchelper.GetLatestExecutedBlockNumber(tx)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		cursor.BlockNumber = uint64(*args.BlockNumber)
+	}
+	if args.BlockHash != nil {
+		cursor.BlockHash = *args.BlockHash
+	}
+	opts := chainevents.Options{WithReceipts: args.WithReceipts, WithStateDiff: args.WithStateDiff}
+	reader := chainevents.NewReader(api._blockReader, func(ctx context.Context, tx kv.TemporalTx, block *types.Block) (types.Receipts, error) {
+		return api.getReceipts(ctx, tx, block)
+	})
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer debug.LogPanic()
+		// subscribe before catching up - to not miss blocks produced meanwhile
+		headers, id := api.filters.SubscribeNewHeads(32)
+		defer api.filters.UnsubscribeHeads(id)
+
+		subCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-rpcSub.Err():
+				cancel()
+			case <-subCtx.Done():
+			}
+		}()
+
+		notify := func(ev *chainevents.Event) error { return notifier.Notify(rpcSub.ID, ev) }
+		for {
+			var err error
+			if cursor, err = reader.CatchUp(subCtx, api.db, cursor, opts, notify); err != nil {
+				if subCtx.Err() == nil {
+					log.Warn("[rpc] chain events subscription closed", "err", err)
+				}
+				return
+			}
+			select {
+			case _, ok := <-headers:
+				if !ok {
+					log.Warn("[rpc] new heads channel was closed")
+					return
+				}
+			case <-subCtx.Done():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
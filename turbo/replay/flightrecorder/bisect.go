@@ -0,0 +1,172 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package flightrecorder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/consensus"
+	"github.com/erigontech/erigon/core"
+)
+
+// Divergence - first difference between recorded and replayed execution of block
+type Divergence struct {
+	TxIndex  int            // -1 - block initialisation, len(txs) - block finalisation
+	TxHash   libcommon.Hash // empty for block initialisation/finalisation
+	Field    string         // error, gasUsed, status, logs, writes, steps
+	Recorded string
+	Replayed string
+	// Step - index of first diverging opcode of txn, -1 if opcode traces are equal (or not recorded)
+	Step     int
+	PrevStep *Step // last common opcode before divergence
+	// MissingState - state read by replay, but absent in recording: execution took another path before
+	// divergence became visible in results of txn
+	MissingState []string
+}
+
+func (d *Divergence) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "txIndex=%d", d.TxIndex)
+	if d.TxHash != (libcommon.Hash{}) {
+		fmt.Fprintf(&sb, " txHash=%x", d.TxHash)
+	}
+	fmt.Fprintf(&sb, " field=%s\n  recorded: %s\n  replayed: %s", d.Field, d.Recorded, d.Replayed)
+	if d.Step >= 0 {
+		fmt.Fprintf(&sb, "\n  first diverging opcode: step %d", d.Step)
+		if d.PrevStep != nil {
+			fmt.Fprintf(&sb, ", after %s", d.PrevStep)
+		}
+	}
+	for _, m := range d.MissingState {
+		fmt.Fprintf(&sb, "\n  missing in recording: %s", m)
+	}
+	return sb.String()
+}
+
+// Replay - re-executes recorded block offline and bisects it down to first diverging txn (and opcode in it).
+// Returns nil Divergence if replay matches recording: then the bug is not in execution of block, but in
+// state commitment or in code which produced recorded results (different binary, different chain config).
+func Replay(rec *Recording, engine consensus.Engine, logger log.Logger) (*Divergence, error) {
+	block, err := rec.DecodeBlock()
+	if err != nil {
+		return nil, err
+	}
+	getHash := func(n uint64) libcommon.Hash { return rec.BlockHashes[n] }
+	reader := &recordedState{pre: rec.Pre}
+	// Bor and AuRa read chain (spans, state sync events, validator sets) - it's not recorded, blocks of such chains
+	// may diverge at block initialisation/finalisation
+	chainReader := &core.FakeChainReader{Cfg: rec.ChainConfig}
+	replayed, err := executeBlock(rec.ChainConfig, engine, chainReader, block, reader, getHash, MaxSteps, logger)
+	if err != nil {
+		return nil, err
+	}
+	if len(replayed) != len(rec.Txs) {
+		return nil, fmt.Errorf("recording has %d parts of block, replay %d", len(rec.Txs), len(replayed))
+	}
+	for i := range replayed {
+		if d := compareTx(rec.Txs[i], replayed[i]); d != nil {
+			d.MissingState = reader.missing
+			return d, nil
+		}
+	}
+	if len(reader.missing) > 0 {
+		return &Divergence{Field: "state", Step: -1, MissingState: reader.missing}, nil
+	}
+	return nil, nil
+}
+
+func compareTx(rec, rep *TxRecord) *Divergence {
+	d := &Divergence{TxIndex: rec.TxIndex, TxHash: rec.TxHash, Step: -1}
+	// opcode trace diverges before results of txn do: find first diverging opcode for any kind of divergence
+	if !bytes.Equal(rec.Steps, rep.Steps) {
+		recSteps, err := DecodeSteps(rec.Steps)
+		if err != nil {
+			d.Field, d.Recorded = "steps", err.Error()
+			return d
+		}
+		repSteps, _ := DecodeSteps(rep.Steps)
+		d.Step = firstDiff(recSteps, repSteps)
+		if d.Step > 0 {
+			d.PrevStep = &recSteps[d.Step-1]
+		}
+	}
+	switch {
+	case rec.Error != rep.Error:
+		d.Field, d.Recorded, d.Replayed = "error", rec.Error, rep.Error
+	case rec.GasUsed != rep.GasUsed:
+		d.Field, d.Recorded, d.Replayed = "gasUsed", fmt.Sprint(rec.GasUsed), fmt.Sprint(rep.GasUsed)
+	case rec.Status != rep.Status:
+		d.Field, d.Recorded, d.Replayed = "status", fmt.Sprint(rec.Status), fmt.Sprint(rep.Status)
+	case len(rec.Logs) != len(rep.Logs):
+		d.Field, d.Recorded, d.Replayed = "logs", fmt.Sprintf("%d logs", len(rec.Logs)), fmt.Sprintf("%d logs", len(rep.Logs))
+	default:
+		for i := range rec.Logs {
+			if a, b := rec.Logs[i], rep.Logs[i]; a.Address != b.Address || !equalTopics(a.Topics, b.Topics) || !bytes.Equal(a.Data, b.Data) {
+				d.Field, d.Recorded, d.Replayed = fmt.Sprintf("logs[%d]", i), fmt.Sprintf("%+v", *a), fmt.Sprintf("%+v", *b)
+				return d
+			}
+		}
+		for i := 0; i < len(rec.Writes) || i < len(rep.Writes); i++ {
+			var a, b string
+			if i < len(rec.Writes) {
+				a = rec.Writes[i].String()
+			}
+			if i < len(rep.Writes) {
+				b = rep.Writes[i].String()
+			}
+			if a != b {
+				d.Field, d.Recorded, d.Replayed = fmt.Sprintf("writes[%d]", i), a, b
+				return d
+			}
+		}
+		if d.Step < 0 {
+			return nil
+		}
+		d.Field = "steps"
+		d.Recorded, d.Replayed = fmt.Sprintf("%d bytes", len(rec.Steps)), fmt.Sprintf("%d bytes", len(rep.Steps))
+	}
+	return d
+}
+
+func firstDiff(a, b []Step) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
+
+func equalTopics(a, b []libcommon.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,586 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package flightrecorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain"
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutility"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/consensus"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/types/accounts"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/consensuschain"
+	"github.com/erigontech/erigon/rlp"
+	"github.com/erigontech/erigon/turbo/services"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+	"github.com/erigontech/erigon/turbo/transactions"
+)
+
+// Recording - "flight recorder" of 1 block: everything needed to re-execute it offline (without datadir) and
+// how it was executed by recording node. Block initialisation, each txn and block finalisation are executed
+// the same way as ExecV3 does: each on own IntraBlockState, on top of writes of previous ones.
+type Recording struct {
+	Reason      string                            `json:"reason"` // why block was recorded
+	ChainConfig *chain.Config                     `json:"chainConfig"`
+	Block       hexutility.Bytes                  `json:"block"`       // RLP
+	BlockHashes map[uint64]libcommon.Hash         `json:"blockHashes"` // read by BLOCKHASH
+	Pre         map[libcommon.Address]*PreAccount `json:"pre"`         // every state read by block, with values as of block start
+	Txs         []*TxRecord                       `json:"txs"`         // block initialisation, txs, block finalisation
+}
+
+// PreAccount - state of account as of block start, only parts read by block
+type PreAccount struct {
+	Account *Account                            `json:"account"` // nil - account doesn't exist
+	Code    hexutility.Bytes                    `json:"code,omitempty"`
+	Storage map[libcommon.Hash]hexutility.Bytes `json:"storage,omitempty"`
+
+	accountRead bool
+}
+
+type Account struct {
+	Nonce       uint64         `json:"nonce"`
+	Balance     *uint256.Int   `json:"balance"`
+	CodeHash    libcommon.Hash `json:"codeHash"`
+	Incarnation uint64         `json:"incarnation"`
+}
+
+func newAccount(a *accounts.Account) *Account {
+	if a == nil {
+		return nil
+	}
+	return &Account{Nonce: a.Nonce, Balance: a.Balance.Clone(), CodeHash: a.CodeHash, Incarnation: a.Incarnation}
+}
+
+func (a *Account) account() *accounts.Account {
+	if a == nil {
+		return nil
+	}
+	acc := accounts.NewAccount()
+	acc.Nonce, acc.Balance, acc.CodeHash, acc.Incarnation = a.Nonce, *a.Balance, a.CodeHash, a.Incarnation
+	acc.Initialised = true
+	return &acc
+}
+
+// TxRecord - result of block initialisation (TxIndex=-1), txn, or block finalisation (TxIndex=len(txs))
+type TxRecord struct {
+	TxIndex int            `json:"txIndex"`
+	TxHash  libcommon.Hash `json:"txHash,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	GasUsed uint64         `json:"gasUsed,omitempty"`
+	Status  uint64         `json:"status,omitempty"`
+	Logs    []*Log         `json:"logs,omitempty"`
+	Writes  []*Write       `json:"writes"`
+	// Steps - opcode trace of txn: (pc, op, gas, depth) of each step, see DecodeSteps
+	Steps          hexutility.Bytes `json:"steps,omitempty"`
+	StepsTruncated bool             `json:"stepsTruncated,omitempty"`
+}
+
+type Log struct {
+	Address libcommon.Address `json:"address"`
+	Topics  []libcommon.Hash  `json:"topics"`
+	Data    hexutility.Bytes  `json:"data"`
+}
+
+type WriteKind string
+
+const (
+	WriteAccount WriteKind = "account"
+	WriteDelete  WriteKind = "delete"
+	WriteCreate  WriteKind = "create" // contract creation: storage is cleared
+	WriteCode    WriteKind = "code"
+	WriteStorage WriteKind = "storage"
+)
+
+type Write struct {
+	Kind    WriteKind         `json:"kind"`
+	Address libcommon.Address `json:"address"`
+	Account *Account          `json:"account,omitempty"`
+	Slot    *libcommon.Hash   `json:"slot,omitempty"`
+	Value   hexutility.Bytes  `json:"value,omitempty"` // code or storage value
+}
+
+func (w *Write) String() string {
+	b, _ := json.Marshal(w)
+	return string(b)
+}
+
+// MaxSteps - limit of recorded opcode trace of 1 txn
+const MaxSteps = 1 << 20
+
+// RecordBlock - records `block` on top of state of `tx` as of block start. Block may be not executed yet (bad block
+// at chain tip): then latest state of `tx` must be state of its parent.
+func RecordBlock(ctx context.Context, tx kv.TemporalTx, cc *chain.Config, engine consensus.Engine, blockReader services.FullBlockReader,
+	block *types.Block, reason string, logger log.Logger) (*Recording, error) {
+	txNumsReader := rawdbv3.TxNums.WithCustomReadTxNumFunc(freezeblocks.ReadTxNumFuncFromBlockReader(ctx, blockReader))
+	minTxNum, err := txNumsReader.Min(tx, block.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	// history has no changes after last executed block: reads fall back to latest state
+	historyReader := state.NewHistoryReaderV3()
+	historyReader.SetTx(tx)
+	historyReader.SetTxNum(minTxNum)
+	chainReader := consensuschain.NewReader(cc, tx, blockReader, logger)
+	getHash := transactions.MakeHeaderGetter(true /* requireCanonical */, tx, blockReader)
+	return Record(cc, engine, chainReader, block, historyReader, getHash, reason, logger)
+}
+
+// Record - executes `block` on top of `pre` (state as of block start) and records it. `getHash` - as BLOCKHASH opcode.
+func Record(cc *chain.Config, engine consensus.Engine, chainReader consensus.ChainReader, block *types.Block,
+	pre state.StateReader, getHash func(n uint64) libcommon.Hash, reason string, logger log.Logger) (*Recording, error) {
+	blockRlp, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Recording{
+		Reason:      reason,
+		ChainConfig: cc,
+		Block:       blockRlp,
+		BlockHashes: map[uint64]libcommon.Hash{},
+		Pre:         map[libcommon.Address]*PreAccount{},
+	}
+	recordingGetHash := func(n uint64) libcommon.Hash {
+		h := getHash(n)
+		rec.BlockHashes[n] = h
+		return h
+	}
+	reader := &preStateRecorder{base: pre, pre: rec.Pre}
+	if rec.Txs, err = executeBlock(cc, engine, chainReader, block, reader, recordingGetHash, MaxSteps, logger); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// WriteFile - writes gzipped json
+func (r *Recording) WriteFile(fname string) error {
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(r); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func ReadRecording(fname string) (*Recording, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	var r Recording
+	if err := json.NewDecoder(zr).Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	return &r, nil
+}
+
+func (r *Recording) DecodeBlock() (*types.Block, error) {
+	var block types.Block
+	if err := rlp.DecodeBytes(r.Block, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// executeBlock - block initialisation, each txn and block finalisation on own IntraBlockState, as ExecV3 does
+func executeBlock(cc *chain.Config, engine consensus.Engine, chainReader consensus.ChainReader, block *types.Block,
+	reader state.StateReader, getHash func(n uint64) libcommon.Hash, maxSteps int, logger log.Logger) ([]*TxRecord, error) {
+	header, txs := block.HeaderNoCopy(), block.Transactions()
+	rules := cc.Rules(header.Number.Uint64(), header.Time)
+	overlay := newOverlayState(reader)
+	writer := &recordingWriter{overlay: overlay}
+	records := make([]*TxRecord, 0, len(txs)+2)
+
+	writer.rec = &TxRecord{TxIndex: -1}
+	ibs := state.New(overlay)
+	engine.Initialize(cc, chainReader, header, ibs, func(contract libcommon.Address, data []byte, ibs *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
+		return core.SysCallContract(contract, data, cc, ibs, header, engine, constCall)
+	}, logger, nil)
+	if err := ibs.FinalizeTx(rules, writer); err != nil {
+		return nil, err
+	}
+	records = append(records, writer.done())
+
+	gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(cc.GetMaxBlobGasPerBlock())
+	usedGas, usedBlobGas := new(uint64), new(uint64)
+	receipts := make(types.Receipts, 0, len(txs))
+	for i, txn := range txs {
+		writer.rec = &TxRecord{TxIndex: i, TxHash: txn.Hash()}
+		steps := &stepRecorder{max: maxSteps}
+		ibs := state.New(overlay)
+		ibs.SetTxContext(i)
+		receipt, _, err := core.ApplyTransaction(cc, getHash, engine, nil, gp, ibs, writer, header, txn, usedGas, usedBlobGas, vm.Config{Debug: true, Tracer: steps})
+		writer.rec.Steps, writer.rec.StepsTruncated = steps.buf, steps.truncated
+		if err != nil {
+			writer.rec.Error = err.Error()
+			records = append(records, writer.done())
+			continue
+		}
+		writer.rec.GasUsed, writer.rec.Status = receipt.GasUsed, receipt.Status
+		for _, l := range receipt.Logs {
+			writer.rec.Logs = append(writer.rec.Logs, &Log{Address: l.Address, Topics: l.Topics, Data: l.Data})
+		}
+		receipts = append(receipts, receipt)
+		records = append(records, writer.done())
+	}
+
+	writer.rec = &TxRecord{TxIndex: len(txs)}
+	ibs = state.New(overlay)
+	syscall := func(contract libcommon.Address, data []byte) ([]byte, error) {
+		return core.SysCallContract(contract, data, cc, ibs, header, engine, false /* constCall */)
+	}
+	if _, _, _, err := engine.Finalize(cc, types.CopyHeader(header), ibs, txs, block.Uncles(), receipts, block.Withdrawals(), chainReader, syscall, logger); err != nil {
+		writer.rec.Error = err.Error()
+	} else if err := ibs.CommitBlock(rules, writer); err != nil {
+		return nil, err
+	}
+	return append(records, writer.done()), nil
+}
+
+// preStateRecorder - remembers first read of each key: reads of keys written by block are served by overlayState,
+// so all values are as of block start
+type preStateRecorder struct {
+	base state.StateReader
+	pre  map[libcommon.Address]*PreAccount
+}
+
+func (r *preStateRecorder) get(address libcommon.Address) *PreAccount {
+	a, ok := r.pre[address]
+	if !ok {
+		a = &PreAccount{}
+		r.pre[address] = a
+	}
+	return a
+}
+
+func (r *preStateRecorder) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	acc, err := r.base.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	if a := r.get(address); !a.accountRead {
+		a.Account, a.accountRead = newAccount(acc), true
+	}
+	return acc, nil
+}
+
+func (r *preStateRecorder) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	v, err := r.base.ReadAccountStorage(address, incarnation, key)
+	if err != nil {
+		return nil, err
+	}
+	a := r.get(address)
+	if a.Storage == nil {
+		a.Storage = map[libcommon.Hash]hexutility.Bytes{}
+	}
+	if _, ok := a.Storage[*key]; !ok {
+		a.Storage[*key] = libcommon.Copy(v)
+	}
+	return v, nil
+}
+
+func (r *preStateRecorder) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	code, err := r.base.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	r.get(address).Code = libcommon.Copy(code)
+	return code, nil
+}
+
+func (r *preStateRecorder) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (r *preStateRecorder) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return r.base.ReadAccountIncarnation(address)
+}
+
+// recordedState - reads state from Recording.Pre. Keys which are not in recording are read as empty and remembered:
+// execution which reads them diverged from recorded one.
+type recordedState struct {
+	pre     map[libcommon.Address]*PreAccount
+	missing []string
+}
+
+func (r *recordedState) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	a, ok := r.pre[address]
+	if !ok {
+		r.missing = append(r.missing, fmt.Sprintf("account %x", address))
+		return nil, nil
+	}
+	return a.Account.account(), nil
+}
+
+func (r *recordedState) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	a, ok := r.pre[address]
+	if ok {
+		if v, ok := a.Storage[*key]; ok {
+			return v, nil
+		}
+	}
+	r.missing = append(r.missing, fmt.Sprintf("storage %x %x", address, *key))
+	return nil, nil
+}
+
+func (r *recordedState) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	if a, ok := r.pre[address]; ok {
+		return a.Code, nil
+	}
+	r.missing = append(r.missing, fmt.Sprintf("code %x", address))
+	return nil, nil
+}
+
+func (r *recordedState) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (r *recordedState) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return 0, nil
+}
+
+// overlayState - state of block start (`base`) with writes of already executed parts of block on top
+type overlayState struct {
+	base     state.StateReader
+	accounts map[libcommon.Address]*accounts.Account // nil - deleted
+	code     map[libcommon.Address][]byte
+	storage  map[libcommon.Address]map[libcommon.Hash][]byte
+	wiped    map[libcommon.Address]bool // storage of account was cleared: by deletion or contract creation
+}
+
+func newOverlayState(base state.StateReader) *overlayState {
+	return &overlayState{
+		base:     base,
+		accounts: map[libcommon.Address]*accounts.Account{},
+		code:     map[libcommon.Address][]byte{},
+		storage:  map[libcommon.Address]map[libcommon.Hash][]byte{},
+		wiped:    map[libcommon.Address]bool{},
+	}
+}
+
+func (o *overlayState) wipe(address libcommon.Address) {
+	o.wiped[address] = true
+	delete(o.storage, address)
+}
+
+func (o *overlayState) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	if a, ok := o.accounts[address]; ok {
+		if a == nil {
+			return nil, nil
+		}
+		acc := *a
+		return &acc, nil
+	}
+	return o.base.ReadAccountData(address)
+}
+
+func (o *overlayState) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	if v, ok := o.storage[address][*key]; ok {
+		return v, nil
+	}
+	if o.wiped[address] {
+		return nil, nil
+	}
+	return o.base.ReadAccountStorage(address, incarnation, key)
+}
+
+func (o *overlayState) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	if code, ok := o.code[address]; ok {
+		return code, nil
+	}
+	return o.base.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (o *overlayState) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := o.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (o *overlayState) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return o.base.ReadAccountIncarnation(address)
+}
+
+// recordingWriter - applies writes to overlayState and records them into TxRecord of currently executed part of block
+type recordingWriter struct {
+	overlay *overlayState
+	rec     *TxRecord
+}
+
+func (w *recordingWriter) done() *TxRecord {
+	rec := w.rec
+	sort.SliceStable(rec.Writes, func(i, j int) bool {
+		a, b := rec.Writes[i], rec.Writes[j]
+		if c := bytes.Compare(a.Address[:], b.Address[:]); c != 0 {
+			return c < 0
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Slot != nil && b.Slot != nil {
+			return bytes.Compare(a.Slot[:], b.Slot[:]) < 0
+		}
+		return false
+	})
+	w.rec = nil
+	return rec
+}
+
+func (w *recordingWriter) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	if original.Incarnation > account.Incarnation {
+		w.overlay.wipe(address)
+		w.overlay.code[address] = nil
+	}
+	acc := *account
+	w.overlay.accounts[address] = &acc
+	w.rec.Writes = append(w.rec.Writes, &Write{Kind: WriteAccount, Address: address, Account: newAccount(account)})
+	return nil
+}
+
+func (w *recordingWriter) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	w.overlay.code[address] = libcommon.Copy(code)
+	w.rec.Writes = append(w.rec.Writes, &Write{Kind: WriteCode, Address: address, Value: libcommon.Copy(code)})
+	return nil
+}
+
+func (w *recordingWriter) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	w.overlay.accounts[address] = nil
+	w.overlay.wipe(address)
+	w.overlay.code[address] = nil
+	w.rec.Writes = append(w.rec.Writes, &Write{Kind: WriteDelete, Address: address})
+	return nil
+}
+
+func (w *recordingWriter) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	if _, ok := w.overlay.storage[address]; !ok {
+		w.overlay.storage[address] = map[libcommon.Hash][]byte{}
+	}
+	v := value.Bytes()
+	w.overlay.storage[address][*key] = v
+	slot := *key
+	w.rec.Writes = append(w.rec.Writes, &Write{Kind: WriteStorage, Address: address, Slot: &slot, Value: v})
+	return nil
+}
+
+func (w *recordingWriter) CreateContract(address libcommon.Address) error {
+	w.overlay.wipe(address)
+	w.rec.Writes = append(w.rec.Writes, &Write{Kind: WriteCreate, Address: address})
+	return nil
+}
+
+func (w *recordingWriter) WriteChangeSets() error { return nil }
+func (w *recordingWriter) WriteHistory() error    { return nil }
+
+// Step - 1 executed opcode
+type Step struct {
+	Pc    uint64    `json:"pc"`
+	Op    vm.OpCode `json:"op"`
+	Gas   uint64    `json:"gas"`
+	Depth uint64    `json:"depth"`
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("pc=%d op=%s gas=%d depth=%d", s.Pc, s.Op, s.Gas, s.Depth)
+}
+
+func DecodeSteps(buf []byte) ([]Step, error) {
+	var steps []Step
+	for len(buf) > 0 {
+		var s Step
+		var n int
+		if s.Pc, n = binary.Uvarint(buf); n <= 0 || n >= len(buf) {
+			return nil, fmt.Errorf("corrupted steps at %d", len(steps))
+		}
+		s.Op, buf = vm.OpCode(buf[n]), buf[n+1:]
+		if s.Gas, n = binary.Uvarint(buf); n <= 0 {
+			return nil, fmt.Errorf("corrupted steps at %d", len(steps))
+		}
+		buf = buf[n:]
+		if s.Depth, n = binary.Uvarint(buf); n <= 0 {
+			return nil, fmt.Errorf("corrupted steps at %d", len(steps))
+		}
+		buf = buf[n:]
+		steps = append(steps, s)
+	}
+	return steps, nil
+}
+
+// stepRecorder - compact opcode trace: uvarint(pc), op, uvarint(gas), uvarint(depth) of each step
+type stepRecorder struct {
+	buf       []byte
+	count     int
+	max       int
+	truncated bool
+}
+
+func (t *stepRecorder) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.count >= t.max {
+		t.truncated = true
+		return
+	}
+	t.count++
+	t.buf = binary.AppendUvarint(t.buf, pc)
+	t.buf = append(t.buf, byte(op))
+	t.buf = binary.AppendUvarint(t.buf, gas)
+	t.buf = binary.AppendUvarint(t.buf, uint64(depth))
+}
+
+func (t *stepRecorder) CaptureTxStart(gasLimit uint64) {}
+func (t *stepRecorder) CaptureTxEnd(restGas uint64)    {}
+func (t *stepRecorder) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+}
+func (t *stepRecorder) CaptureEnd(output []byte, usedGas uint64, err error) {}
+func (t *stepRecorder) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+}
+func (t *stepRecorder) CaptureExit(output []byte, usedGas uint64, err error) {}
+func (t *stepRecorder) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
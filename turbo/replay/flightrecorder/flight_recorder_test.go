@@ -0,0 +1,115 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package flightrecorder_test
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/turbo/replay/flightrecorder"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSignerForChainID(nil)
+		gspec   = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1e18)}},
+		}
+		// runtime: SSTORE(0, 1); LOG0(0, 0)
+		runtime  = libcommon.FromHex("600160005560006000a000")
+		initCode = append(libcommon.FromHex("600b600c600039600b6000f3"), runtime...)
+		contract = crypto.CreateAddress(address, 0)
+	)
+	m := mock.MockWithGenesis(t, gspec, key, false)
+	require := require.New(t)
+
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 2, func(i int, b *core.BlockGen) {
+		var txn types.Transaction
+		if i == 0 {
+			txn = types.NewContractCreation(b.TxNonce(address), uint256.NewInt(0), 100_000, uint256.NewInt(1), initCode)
+		} else {
+			txn = types.NewTransaction(b.TxNonce(address), contract, uint256.NewInt(0), 100_000, uint256.NewInt(1), nil)
+		}
+		txn, err := types.SignTx(txn, *signer, key)
+		require.NoError(err)
+		b.AddTx(txn)
+	})
+	require.NoError(err)
+	require.NoError(m.InsertChain(chain))
+
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+
+	// call of contract
+	rec, err := flightrecorder.RecordBlock(m.Ctx, tx.(kv.TemporalTx), m.ChainConfig, m.Engine, m.BlockReader, chain.Blocks[1], "test", m.Log)
+	require.NoError(err)
+	require.Len(rec.Txs, 3) // block initialisation, txn, block finalisation
+	txRec := rec.Txs[1]
+	require.Equal(chain.Blocks[1].Transactions()[0].Hash(), txRec.TxHash)
+	require.Equal(chain.Receipts[1][0].GasUsed, txRec.GasUsed)
+	require.Len(txRec.Logs, 1)
+	steps, err := flightrecorder.DecodeSteps(txRec.Steps)
+	require.NoError(err)
+	require.Len(steps, 7)
+	require.Equal(vm.SSTORE, steps[2].Op)
+	require.Equal(runtime, []byte(rec.Pre[contract].Code))
+
+	fname := filepath.Join(t.TempDir(), "rec.json.gz")
+	require.NoError(rec.WriteFile(fname))
+	rec, err = flightrecorder.ReadRecording(fname)
+	require.NoError(err)
+
+	d, err := flightrecorder.Replay(rec, m.Engine, m.Log)
+	require.NoError(err)
+	require.Nil(d)
+
+	// storage slot already has value: SSTORE is cheaper
+	slot := libcommon.Hash{}
+	rec.Pre[contract].Storage[slot] = []byte{1}
+	d, err = flightrecorder.Replay(rec, m.Engine, m.Log)
+	require.NoError(err)
+	require.NotNil(d)
+	require.Equal(0, d.TxIndex)
+	require.Equal("gasUsed", d.Field)
+	require.Equal(3, d.Step) // gas after SSTORE
+	require.Equal(vm.SSTORE, d.PrevStep.Op)
+	require.Empty(d.MissingState)
+
+	// state read by replay is not in recording
+	delete(rec.Pre, contract)
+	d, err = flightrecorder.Replay(rec, m.Engine, m.Log)
+	require.NoError(err)
+	require.NotNil(d)
+	require.Equal(0, d.TxIndex)
+	require.NotEmpty(d.MissingState)
+}
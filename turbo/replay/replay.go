@@ -0,0 +1,303 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package replay - offline re-execution of historical blocks with differential comparison.
+//
+// Every txn (including block initialisation and finalisation system txs) is executed on top of state as of its txNum
+// (history), so divergence of one txn doesn't cascade to the next ones. Results are compared with what was stored
+// by Execution stage:
+//   - writes: with state history (keys changed at this txNum) and values as of next txNum
+//   - receipts: gas used - with kv.ReceiptDomain, logs - with kv.LogAddrIdx/kv.LogTopicIdx
+//   - block: gas used and receipts root - with header
+//
+// Nothing is written to db.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/stream"
+	"github.com/erigontech/erigon-lib/log/v3"
+
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core/rawdb/rawtemporaldb"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/types/accounts"
+)
+
+type MismatchKind string
+
+const (
+	ExecutionError  MismatchKind = "execution error" // txn failed on historical state, but it's in canonical chain
+	StateMismatch   MismatchKind = "state"           // replayed writes are different from state history
+	ReceiptMismatch MismatchKind = "receipt"         // replayed receipt is different from kv.ReceiptDomain or logs indices
+	BlockMismatch   MismatchKind = "block"           // replayed block is different from header
+)
+
+type Mismatch struct {
+	Kind     MismatchKind
+	BlockNum uint64
+	TxNum    uint64
+	TxIndex  int            // -1 - block initialisation, len(block.Transactions()) - block finalisation
+	TxHash   libcommon.Hash // zero for system txs
+	Domain   kv.Domain      // StateMismatch only
+	Key      []byte         // StateMismatch only
+	Field    string
+	Stored   string
+	Replayed string
+}
+
+// SystemTx - mismatch happened in block initialisation or finalisation: they can't be reproduced as standalone txn
+func (m *Mismatch) SystemTx() bool { return m.TxHash == (libcommon.Hash{}) }
+
+func (m *Mismatch) String() string {
+	s := fmt.Sprintf("%s mismatch: block=%d, txIndex=%d, txNum=%d", m.Kind, m.BlockNum, m.TxIndex, m.TxNum)
+	if !m.SystemTx() {
+		s += fmt.Sprintf(", txHash=%x", m.TxHash)
+	}
+	if m.Kind == StateMismatch {
+		s += fmt.Sprintf(", domain=%s, key=%x", m.Domain, m.Key)
+	}
+	return s + fmt.Sprintf(", %s: stored=%s, replayed=%s", m.Field, m.Stored, m.Replayed)
+}
+
+// Run - re-executes blocks [fromBlock, toBlock] on historical state and calls `onMismatch` for each difference found.
+// Mismatches of one txn are reported sequentially, txns are reported in order of execution.
+func Run(ctx context.Context, tx kv.TemporalTx, cfg *exec3.ExecArgs, fromBlock, toBlock uint64, onMismatch func(*Mismatch) error, logger log.Logger) error {
+	if fromBlock == 0 {
+		return fmt.Errorf("genesis block can't be replayed: start from block 1")
+	}
+	r := &replayer{onMismatch: onMismatch}
+	consumer := exec3.TraceConsumer{
+		NewTracer: func() exec3.GenericTracer { return nil },
+		Reduce:    r.reduce,
+		StateDiff: true,
+	}
+	return exec3.CustomTraceMapReduce(fromBlock, toBlock, consumer, ctx, tx, cfg, logger)
+}
+
+type replayer struct {
+	onMismatch func(*Mismatch) error
+}
+
+func (r *replayer) report(task *state.TxTask, m *Mismatch) error {
+	m.BlockNum, m.TxNum, m.TxIndex = task.BlockNum, task.TxNum, task.TxIndex
+	if task.Tx != nil {
+		m.TxHash = task.Tx.Hash()
+	}
+	return r.onMismatch(m)
+}
+
+func (r *replayer) reduce(task *state.TxTask, tx kv.Tx) error {
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return fmt.Errorf("expected TemporalTx, got %T", tx)
+	}
+	if task.Error != nil {
+		return r.report(task, &Mismatch{Kind: ExecutionError, Field: "error", Stored: "<nil>", Replayed: task.Error.Error()})
+	}
+	if err := r.compareWrites(ttx, task); err != nil {
+		return err
+	}
+	if task.Final {
+		return r.compareBlock(task)
+	}
+	if task.TxIndex >= 0 {
+		return r.compareReceipt(ttx, task)
+	}
+	return nil
+}
+
+var stateHistories = []struct {
+	history kv.History
+	domain  kv.Domain
+}{{kv.AccountsHistory, kv.AccountsDomain}, {kv.CodeHistory, kv.CodeDomain}, {kv.StorageHistory, kv.StorageDomain}}
+
+// compareWrites - every replayed write must be equal to stored value after txn, every key changed by txn in history must be replayed
+func (r *replayer) compareWrites(tx kv.TemporalTx, task *state.TxTask) error {
+	replayed := make(map[kv.Domain]map[string][]byte, len(stateHistories))
+	for _, h := range stateHistories {
+		replayed[h.domain] = map[string][]byte{}
+		if list, ok := task.WriteLists[h.domain.String()]; ok {
+			for i, k := range list.Keys {
+				replayed[h.domain][k] = list.Vals[i] // last write wins
+			}
+		}
+	}
+
+	// balance increases without reading account (coinbase, rewards) are applied by applyLoop on top of latest state
+	for addr, increase := range task.BalanceIncreaseSet {
+		var acc accounts.Account
+		enc, ok := replayed[kv.AccountsDomain][string(addr[:])]
+		if !ok {
+			var err error
+			if enc, _, err = tx.DomainGetAsOf(kv.AccountsDomain, addr[:], nil, task.TxNum); err != nil {
+				return err
+			}
+		}
+		if len(enc) > 0 {
+			if err := accounts.DeserialiseV3(&acc, enc); err != nil {
+				return err
+			}
+		}
+		acc.Balance.Add(&acc.Balance, &increase)
+		if task.Rules.IsSpuriousDragon && acc.Nonce == 0 && acc.Balance.IsZero() && acc.IsEmptyCodeHash() {
+			replayed[kv.AccountsDomain][string(addr[:])] = nil
+		} else {
+			replayed[kv.AccountsDomain][string(addr[:])] = accounts.SerialiseV3(&acc)
+		}
+	}
+
+	for _, h := range stateHistories {
+		keys := make([]string, 0, len(replayed[h.domain]))
+		for k := range replayed[h.domain] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := replayed[h.domain][k]
+			stored, _, err := tx.DomainGetAsOf(h.domain, []byte(k), nil, task.TxNum+1)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(stored, v) {
+				continue
+			}
+			if err := r.report(task, &Mismatch{Kind: StateMismatch, Domain: h.domain, Key: []byte(k), Field: "value", Stored: fmt.Sprintf("%x", stored), Replayed: fmt.Sprintf("%x", v)}); err != nil {
+				return err
+			}
+		}
+
+		it, err := tx.HistoryRange(h.history, int(task.TxNum), int(task.TxNum+1), order.Asc, kv.Unlim)
+		if err != nil {
+			return err
+		}
+		if err := r.compareNotReplayed(tx, task, h.domain, it, replayed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *replayer) compareNotReplayed(tx kv.TemporalTx, task *state.TxTask, domain kv.Domain, it stream.KV, replayed map[kv.Domain]map[string][]byte) error {
+	defer it.Close()
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if _, ok := replayed[domain][string(k)]; ok {
+			continue
+		}
+		after, _, err := tx.DomainGetAsOf(domain, k, nil, task.TxNum+1)
+		if err != nil {
+			return err
+		}
+		// storage and code of deleted (or re-created) account are cleaned by applyLoop, they are not in write-set
+		if _, accReplayed := replayed[kv.AccountsDomain][string(k[:min(len(k), length.Addr)])]; accReplayed && domain != kv.AccountsDomain && len(after) == 0 {
+			continue
+		}
+		before, _, err := tx.DomainGetAsOf(domain, k, nil, task.TxNum)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(before, after) {
+			continue
+		}
+		if err := r.report(task, &Mismatch{Kind: StateMismatch, Domain: domain, Key: libcommon.Copy(k), Field: "value", Stored: fmt.Sprintf("%x", after), Replayed: fmt.Sprintf("<not written, %x>", before)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareReceipt - txn gas used with kv.ReceiptDomain (cumulative gas is stored), logs with logs indices
+func (r *replayer) compareReceipt(tx kv.TemporalTx, task *state.TxTask) error {
+	cumGasBefore, _, _, err := rawtemporaldb.ReceiptAsOf(tx, task.TxNum)
+	if err != nil {
+		return err
+	}
+	cumGasAfter, _, _, err := rawtemporaldb.ReceiptAsOf(tx, task.TxNum+1)
+	if err != nil {
+		return err
+	}
+	if storedGas := cumGasAfter - cumGasBefore; storedGas != task.UsedGas {
+		if err := r.report(task, &Mismatch{Kind: ReceiptMismatch, Field: "gasUsed", Stored: fmt.Sprint(storedGas), Replayed: fmt.Sprint(task.UsedGas)}); err != nil {
+			return err
+		}
+	}
+
+	for i, l := range task.Logs {
+		if err := r.compareLogIndex(tx, task, kv.LogAddrIdx, l.Address[:], fmt.Sprintf("logs[%d].address", i)); err != nil {
+			return err
+		}
+		for j, topic := range l.Topics {
+			if err := r.compareLogIndex(tx, task, kv.LogTopicIdx, topic[:], fmt.Sprintf("logs[%d].topics[%d]", i, j)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *replayer) compareLogIndex(tx kv.TemporalTx, task *state.TxTask, idx kv.InvertedIdx, k []byte, field string) error {
+	it, err := tx.IndexRange(idx, k, int(task.TxNum), int(task.TxNum+1), order.Asc, kv.Unlim)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	if it.HasNext() {
+		return nil
+	}
+	return r.report(task, &Mismatch{Kind: ReceiptMismatch, Field: field, Stored: "<not indexed>", Replayed: fmt.Sprintf("%x", k)})
+}
+
+// compareBlock - at block finalisation all receipts of block are replayed
+func (r *replayer) compareBlock(task *state.TxTask) error {
+	var gasUsed uint64
+	if len(task.BlockReceipts) > 0 {
+		gasUsed = task.BlockReceipts[len(task.BlockReceipts)-1].CumulativeGasUsed
+	}
+	if gasUsed != task.Header.GasUsed {
+		if err := r.report(task, &Mismatch{Kind: BlockMismatch, Field: "gasUsed", Stored: fmt.Sprint(task.Header.GasUsed), Replayed: fmt.Sprint(gasUsed)}); err != nil {
+			return err
+		}
+	}
+	// receipts of pre-Byzantium blocks have intermediate state roots - which are not computed by replay
+	if !task.Rules.IsByzantium {
+		return nil
+	}
+	for _, receipt := range task.BlockReceipts {
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	}
+	if receiptsRoot := types.DeriveSha(task.BlockReceipts); receiptsRoot != task.Header.ReceiptHash {
+		if err := r.report(task, &Mismatch{Kind: BlockMismatch, Field: "receiptsRoot", Stored: task.Header.ReceiptHash.String(), Replayed: receiptsRoot.String()}); err != nil {
+			return err
+		}
+	}
+	if bloom := types.CreateBloom(task.BlockReceipts); bloom != task.Header.Bloom {
+		return r.report(task, &Mismatch{Kind: BlockMismatch, Field: "logsBloom", Stored: fmt.Sprintf("%x", task.Header.Bloom), Replayed: fmt.Sprintf("%x", bloom)})
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package replay_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/crypto"
+	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/turbo/replay"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+func TestReplay(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSignerForChainID(nil)
+		gspec   = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1e18)}},
+		}
+		// runtime: SSTORE(0, 1); LOG0(0, 0)
+		runtime  = libcommon.FromHex("600160005560006000a000")
+		initCode = append(libcommon.FromHex("600b600c600039600b6000f3"), runtime...)
+		contract = crypto.CreateAddress(address, 0)
+	)
+	m := mock.MockWithGenesis(t, gspec, key, false)
+	require := require.New(t)
+
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, b *core.BlockGen) {
+		var txn types.Transaction
+		switch i {
+		case 0:
+			txn = types.NewContractCreation(b.TxNonce(address), uint256.NewInt(0), 100_000, uint256.NewInt(1), initCode)
+		case 1:
+			txn = types.NewTransaction(b.TxNonce(address), contract, uint256.NewInt(0), 100_000, uint256.NewInt(1), nil)
+		default:
+			txn = types.NewTransaction(b.TxNonce(address), libcommon.Address{1}, uint256.NewInt(1), 21000, uint256.NewInt(1), nil)
+		}
+		txn, err := types.SignTx(txn, *signer, key)
+		require.NoError(err)
+		b.AddTx(txn)
+	})
+	require.NoError(err)
+	require.NoError(m.InsertChain(chain))
+
+	cfg := &exec3.ExecArgs{
+		ChainDB:     m.DB,
+		BlockReader: m.BlockReader,
+		ChainConfig: m.ChainConfig,
+		Dirs:        m.Dirs,
+		Engine:      m.Engine,
+		Genesis:     gspec,
+		Workers:     1,
+	}
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	ttx := tx.(kv.TemporalTx)
+
+	var mismatches []*replay.Mismatch
+	err = replay.Run(m.Ctx, ttx, cfg, 1, 3, func(mismatch *replay.Mismatch) error {
+		mismatches = append(mismatches, mismatch)
+		return nil
+	}, m.Log)
+	require.NoError(err)
+	require.Empty(mismatches)
+
+	err = replay.Run(m.Ctx, ttx, cfg, 0, 3, func(*replay.Mismatch) error { return nil }, m.Log)
+	require.Error(err)
+
+	// call of contract: reads its code and storage
+	r, err := replay.NewReproducer(m.Ctx, ttx, cfg, 2, 0)
+	require.NoError(err)
+	require.Equal(uint64(1), r.Alloc[address].Nonce)
+	require.Equal(runtime, r.Alloc[contract].Code)
+	require.Empty(r.Alloc[contract].Storage)
+	require.Equal(uint64(2), uint64(r.Env.Number))
+	require.Len(r.Txs, 1)
+	require.Equal(chain.Blocks[1].Transactions()[0].Hash(), r.Txs[0].Hash)
+
+	dir := t.TempDir()
+	require.NoError(r.Write(dir))
+	for _, name := range []string{"alloc.json", "env.json", "txs.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(err)
+		require.True(json.Valid(data))
+	}
+	require.Contains(r.Command(dir), "--state.fork=")
+
+	_, err = replay.NewReproducer(m.Ctx, ttx, cfg, 2, 1)
+	require.Error(err)
+}
+
+func TestReplayExecutionErrorParallel(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSignerForChainID(nil)
+		gspec   = &types.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1e18)}},
+		}
+	)
+	m := mock.MockWithGenesis(t, gspec, key, false)
+	require := require.New(t)
+
+	// initcode above EIP-3860 limit: valid before Shanghai
+	var bigCreation libcommon.Hash
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, b *core.BlockGen) {
+		txn := types.Transaction(types.NewTransaction(b.TxNonce(address), libcommon.Address{1}, uint256.NewInt(1), 21000, uint256.NewInt(1), nil))
+		if i == 1 {
+			txn = types.NewContractCreation(b.TxNonce(address), uint256.NewInt(0), 1_000_000, uint256.NewInt(1), make([]byte, params.MaxInitCodeSize+1))
+		}
+		txn, err := types.SignTx(txn, *signer, key)
+		require.NoError(err)
+		if i == 1 {
+			bigCreation = txn.Hash()
+		}
+		b.AddTx(txn)
+	})
+	require.NoError(err)
+	require.NoError(m.InsertChain(chain))
+
+	// replay with Shanghai: the creation can't be executed on historical state
+	chainConfig := *m.ChainConfig
+	chainConfig.ShanghaiTime = big.NewInt(0)
+	cfg := &exec3.ExecArgs{
+		ChainDB:     m.DB,
+		BlockReader: m.BlockReader,
+		ChainConfig: &chainConfig,
+		Dirs:        m.Dirs,
+		Engine:      m.Engine,
+		Genesis:     gspec,
+		Workers:     3,
+	}
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+
+	var executionErrors []*replay.Mismatch
+	err = replay.Run(m.Ctx, tx.(kv.TemporalTx), cfg, 1, 3, func(mismatch *replay.Mismatch) error {
+		if mismatch.Kind == replay.ExecutionError {
+			executionErrors = append(executionErrors, mismatch)
+		}
+		return nil
+	}, m.Log)
+	require.NoError(err)
+	require.Len(executionErrors, 1)
+	require.Equal(bigCreation, executionErrors[0].TxHash)
+	require.Equal(uint64(2), executionErrors[0].BlockNum)
+	require.Contains(executionErrors[0].Replayed, core.ErrMaxInitCodeSizeExceeded.Error())
+}
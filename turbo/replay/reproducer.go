@@ -0,0 +1,273 @@
+// Copyright 2024 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/erigontech/erigon-lib/chain"
+	libcommon "github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+
+	"github.com/erigontech/erigon/cmd/state/exec3"
+	"github.com/erigontech/erigon/common/math"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/types"
+	"github.com/erigontech/erigon/core/types/accounts"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/turbo/jsonrpc"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+	"github.com/erigontech/erigon/turbo/transactions"
+)
+
+// Env - block environment in format of `evm t8n --input.env`
+type Env struct {
+	Coinbase    libcommon.Address                      `json:"currentCoinbase"`
+	Difficulty  *math.HexOrDecimal256                  `json:"currentDifficulty,omitempty"`
+	Random      *math.HexOrDecimal256                  `json:"currentRandom,omitempty"`
+	GasLimit    math.HexOrDecimal64                    `json:"currentGasLimit"`
+	Number      math.HexOrDecimal64                    `json:"currentNumber"`
+	Timestamp   math.HexOrDecimal64                    `json:"currentTimestamp"`
+	BaseFee     *math.HexOrDecimal256                  `json:"currentBaseFee,omitempty"`
+	BlockHashes map[math.HexOrDecimal64]libcommon.Hash `json:"blockHashes,omitempty"`
+	// t8n requires withdrawals after Shanghai, reproducer has only 1 txn of block - so they are always empty
+	Withdrawals []*types.Withdrawal `json:"withdrawals"`
+}
+
+// Reproducer - minimal input of `evm t8n` to re-execute 1 txn: block environment, txn and state accessed by txn
+type Reproducer struct {
+	Fork    string
+	ChainID *big.Int
+	Alloc   types.GenesisAlloc
+	Env     *Env
+	Txs     []*jsonrpc.RPCTransaction
+}
+
+// NewReproducer - executes txn `txIndex` of block `blockNum` on historical state and records all state it reads
+func NewReproducer(ctx context.Context, tx kv.TemporalTx, cfg *exec3.ExecArgs, blockNum uint64, txIndex int) (*Reproducer, error) {
+	block, err := cfg.BlockReader.BlockByNumber(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	}
+	if txIndex < 0 || txIndex >= len(block.Transactions()) {
+		return nil, fmt.Errorf("block %d has no txn %d", blockNum, txIndex)
+	}
+	txNumsReader := rawdbv3.TxNums.WithCustomReadTxNumFunc(freezeblocks.ReadTxNumFuncFromBlockReader(ctx, cfg.BlockReader))
+	minTxNum, err := txNumsReader.Min(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, header, txn := cfg.ChainConfig, block.HeaderNoCopy(), block.Transactions()[txIndex]
+	rules := cc.Rules(blockNum, header.Time)
+	msg, err := txn.AsMessage(*types.MakeSigner(cc, blockNum, header.Time), header.BaseFee, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	historyReader := state.NewHistoryReaderV3()
+	historyReader.SetTx(tx)
+	historyReader.SetTxNum(minTxNum + 1 + uint64(txIndex)) // +1 - block initialisation system txn
+	reader := newRecordingReader(historyReader)
+	ibs := state.New(reader)
+	ibs.SetTxContext(txIndex)
+	if msg.FeeCap().IsZero() {
+		// Only zero-gas transactions may be service ones
+		syscall := func(contract libcommon.Address, data []byte) ([]byte, error) {
+			return core.SysCallContract(contract, data, cc, ibs, header, cfg.Engine, true /* constCall */)
+		}
+		msg.SetIsFree(cfg.Engine.IsServiceTransaction(msg.From(), syscall))
+	}
+
+	env := newEnv(header, rules.IsShanghai)
+	blockCtx := transactions.NewEVMBlockContext(cfg.Engine, header, true /* requireCanonical */, tx, cfg.BlockReader, cc)
+	getHash := blockCtx.GetHash
+	blockCtx.GetHash = func(n uint64) libcommon.Hash {
+		h := getHash(n)
+		env.BlockHashes[math.HexOrDecimal64(n)] = h
+		return h
+	}
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), ibs, cc, vm.Config{SkipAnalysis: core.SkipAnalysis(cc, blockNum)})
+	gp := new(core.GasPool).AddGas(txn.GetGas()).AddBlobGas(txn.GetBlobGas())
+	// txn which fails on historical state is reproducer itself: t8n will reject it the same way
+	_, _ = core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+
+	alloc, err := reader.alloc()
+	if err != nil {
+		return nil, err
+	}
+	return &Reproducer{
+		Fork:    forkName(rules, header),
+		ChainID: cc.ChainID,
+		Alloc:   alloc,
+		Env:     env,
+		Txs:     []*jsonrpc.RPCTransaction{jsonrpc.NewRPCTransaction(txn, block.Hash(), blockNum, uint64(txIndex), header.BaseFee)},
+	}, nil
+}
+
+func newEnv(header *types.Header, isShanghai bool) *Env {
+	env := &Env{
+		Coinbase:    header.Coinbase,
+		GasLimit:    math.HexOrDecimal64(header.GasLimit),
+		Number:      math.HexOrDecimal64(header.Number.Uint64()),
+		Timestamp:   math.HexOrDecimal64(header.Time),
+		BlockHashes: map[math.HexOrDecimal64]libcommon.Hash{},
+	}
+	if header.Difficulty != nil && header.Difficulty.Sign() != 0 {
+		env.Difficulty = (*math.HexOrDecimal256)(new(big.Int).Set(header.Difficulty))
+	} else {
+		env.Random = (*math.HexOrDecimal256)(new(big.Int).SetBytes(header.MixDigest[:]))
+	}
+	if header.BaseFee != nil {
+		env.BaseFee = (*math.HexOrDecimal256)(new(big.Int).Set(header.BaseFee))
+	}
+	if isShanghai {
+		env.Withdrawals = []*types.Withdrawal{}
+	}
+	return env
+}
+
+// forkName - name of latest fork active in given block, as in `evm t8n --state.fork`
+func forkName(rules *chain.Rules, header *types.Header) string {
+	switch {
+	case rules.IsPrague:
+		return "Prague"
+	case rules.IsCancun:
+		return "Cancun"
+	case rules.IsShanghai:
+		return "Shanghai"
+	case rules.IsLondon && header.Difficulty != nil && header.Difficulty.Sign() == 0:
+		return "Merge"
+	case rules.IsLondon:
+		return "London"
+	case rules.IsBerlin:
+		return "Berlin"
+	case rules.IsIstanbul:
+		return "Istanbul"
+	case rules.IsPetersburg:
+		return "ConstantinopleFix"
+	case rules.IsConstantinople:
+		return "Constantinople"
+	case rules.IsByzantium:
+		return "Byzantium"
+	case rules.IsSpuriousDragon:
+		return "EIP158"
+	case rules.IsTangerineWhistle:
+		return "EIP150"
+	case rules.IsHomestead:
+		return "Homestead"
+	default:
+		return "Frontier"
+	}
+}
+
+// Write - writes alloc.json, env.json and txs.json into `dir`
+func (r *Reproducer) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, v := range map[string]interface{}{"alloc.json": r.Alloc, "env.json": r.Env, "txs.json": r.Txs} {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Command - `evm t8n` invocation for reproducer written into `dir`
+func (r *Reproducer) Command(dir string) string {
+	return fmt.Sprintf("evm t8n --input.alloc=%s --input.env=%s --input.txs=%s --state.fork=%s --state.chainid=%d --output.result=stdout --output.alloc=stdout",
+		filepath.Join(dir, "alloc.json"), filepath.Join(dir, "env.json"), filepath.Join(dir, "txs.json"), r.Fork, r.ChainID)
+}
+
+// recordingReader - remembers everything read from underlying state: it's pre-state of txn
+type recordingReader struct {
+	state.StateReader
+	accounts map[libcommon.Address]*accounts.Account
+	storage  map[libcommon.Address]map[libcommon.Hash]libcommon.Hash
+}
+
+func newRecordingReader(r state.StateReader) *recordingReader {
+	return &recordingReader{
+		StateReader: r,
+		accounts:    map[libcommon.Address]*accounts.Account{},
+		storage:     map[libcommon.Address]map[libcommon.Hash]libcommon.Hash{},
+	}
+}
+
+func (r *recordingReader) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	acc, err := r.StateReader.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	r.accounts[address] = acc
+	return acc, nil
+}
+
+func (r *recordingReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	v, err := r.StateReader.ReadAccountStorage(address, incarnation, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.storage[address]; !ok {
+		r.storage[address] = map[libcommon.Hash]libcommon.Hash{}
+	}
+	r.storage[address][*key] = libcommon.BytesToHash(v)
+	return v, nil
+}
+
+func (r *recordingReader) alloc() (types.GenesisAlloc, error) {
+	alloc := types.GenesisAlloc{}
+	for addr, acc := range r.accounts {
+		if acc == nil {
+			continue // t8n treats absent accounts as empty
+		}
+		ga := types.GenesisAccount{Balance: acc.Balance.ToBig(), Nonce: acc.Nonce}
+		if !acc.IsEmptyCodeHash() {
+			code, err := r.StateReader.ReadAccountCode(addr, acc.Incarnation, acc.CodeHash)
+			if err != nil {
+				return nil, err
+			}
+			ga.Code = code
+		}
+		for k, v := range r.storage[addr] {
+			if v == (libcommon.Hash{}) {
+				continue
+			}
+			if ga.Storage == nil {
+				ga.Storage = map[libcommon.Hash]libcommon.Hash{}
+			}
+			ga.Storage[k] = v
+		}
+		alloc[addr] = ga
+	}
+	return alloc, nil
+}
@@ -18,7 +18,6 @@ package rpchelper
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	libcommon "github.com/erigontech/erigon-lib/common"
@@ -26,11 +25,8 @@ import (
 	"github.com/erigontech/erigon-lib/kv/kvcache"
 	"github.com/erigontech/erigon-lib/kv/rawdbv3"
 	"github.com/erigontech/erigon-lib/wrap"
-	"github.com/erigontech/erigon/core/rawdb"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/eth/stagedsync/stages"
-	borfinality "github.com/erigontech/erigon/polygon/bor/finality"
-	"github.com/erigontech/erigon/polygon/bor/finality/whitelist"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/turbo/services"
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
@@ -74,17 +70,6 @@ func _GetBlockNumber(ctx context.Context, requireCanonical bool, blockNrOrHash r
 		case rpc.EarliestBlockNumber:
 			blockNumber = 0
 		case rpc.FinalizedBlockNumber:
-			if whitelist.GetWhitelistingService() != nil {
-				num := borfinality.GetFinalizedBlockNumber(tx)
-				if num == 0 {
-					// nolint
-					return 0, libcommon.Hash{}, false, false, errors.New("No finalized block")
-				}
-
-				blockNum := borfinality.CurrentFinalizedBlock(tx, num).NumberU64()
-				blockHash := rawdb.ReadHeaderByNumber(tx, blockNum).Hash()
-				return blockNum, blockHash, false, false, nil
-			}
 			blockNumber, err = GetFinalizedBlockNumber(tx)
 			if err != nil {
 				return 0, libcommon.Hash{}, false, false, err
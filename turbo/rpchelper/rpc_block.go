@@ -17,6 +17,7 @@
 package rpchelper
 
 import (
+	"errors"
 	"fmt"
 
 	libcommon "github.com/erigontech/erigon-lib/common"
@@ -24,6 +25,8 @@ import (
 
 	"github.com/erigontech/erigon/core/rawdb"
 	"github.com/erigontech/erigon/eth/stagedsync/stages"
+	borfinality "github.com/erigontech/erigon/polygon/bor/finality"
+	"github.com/erigontech/erigon/polygon/bor/finality/whitelist"
 	"github.com/erigontech/erigon/rpc"
 )
 
@@ -49,7 +52,13 @@ func GetLatestBlockNumber(tx kv.Tx) (uint64, error) {
 	return blockNum, nil
 }
 
+// GetFinalizedBlockNumber - on Bor chains with the whitelisting service it's the latest whitelisted milestone
+// or checkpoint, otherwise the finalized block of the last fork choice update.
 func GetFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
+	if whitelist.GetWhitelistingService() != nil {
+		return getBorFinalizedBlockNumber(tx)
+	}
+
 	forkchoiceFinalizedHash := rawdb.ReadForkchoiceFinalized(tx)
 	if forkchoiceFinalizedHash != (libcommon.Hash{}) {
 		forkchoiceFinalizedNum := rawdb.ReadHeaderNumber(tx, forkchoiceFinalizedHash)
@@ -61,7 +70,13 @@ func GetFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
 	return 0, UnknownBlockError
 }
 
+// GetSafeBlockNumber - on Bor chains milestones and checkpoints are the only source of finality,
+// so "safe" follows "finalized" there.
 func GetSafeBlockNumber(tx kv.Tx) (uint64, error) {
+	if whitelist.GetWhitelistingService() != nil {
+		return getBorFinalizedBlockNumber(tx)
+	}
+
 	forkchoiceSafeHash := rawdb.ReadForkchoiceSafe(tx)
 	if forkchoiceSafeHash != (libcommon.Hash{}) {
 		forkchoiceSafeNum := rawdb.ReadHeaderNumber(tx, forkchoiceSafeHash)
@@ -72,6 +87,16 @@ func GetSafeBlockNumber(tx kv.Tx) (uint64, error) {
 	return 0, UnknownBlockError
 }
 
+func getBorFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
+	num := borfinality.GetFinalizedBlockNumber(tx)
+	if num == 0 {
+		// nolint
+		return 0, errors.New("No finalized block")
+	}
+
+	return num, nil
+}
+
 func GetLatestExecutedBlockNumber(tx kv.Tx) (uint64, error) {
 	blockNum, err := stages.GetStageProgress(tx, stages.Execution)
 	if err != nil {
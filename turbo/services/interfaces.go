@@ -61,6 +61,8 @@ type BorEventReader interface {
 	BorStartEventID(ctx context.Context, tx kv.Tx, hash common.Hash, blockNum uint64) (uint64, error)
 	LastFrozenEventId() uint64
 	LastFrozenEventBlockNum() uint64
+	// FrozenEventsByIdRange returns the raw events with IDs in [fromId, toId] from snapshots and the blocks they were committed in
+	FrozenEventsByIdRange(fromId, toId uint64) ([]rlp.RawValue, []uint64, error)
 }
 
 type BorSpanReader interface {
@@ -335,6 +335,10 @@ func (r *RemoteBlockReader) LastFrozenEventBlockNum() uint64 {
 	panic("not implemented")
 }
 
+func (r *RemoteBlockReader) FrozenEventsByIdRange(fromId, toId uint64) ([]rlp.RawValue, []uint64, error) {
+	panic("not implemented")
+}
+
 func (r *RemoteBlockReader) Span(_ context.Context, _ kv.Getter, _ uint64) ([]byte, error) {
 	panic("not implemented")
 }
@@ -1574,6 +1578,44 @@ func (r *BlockReader) EventsByIdFromSnapshot(from uint64, to time.Time, limit in
 	return result, maxTime, nil
 }
 
+// FrozenEventsByIdRange returns the raw events with IDs in [fromId, toId] found in the bor snapshots
+// along with the numbers of the blocks they were committed in
+func (r *BlockReader) FrozenEventsByIdRange(fromId, toId uint64) ([]rlp.RawValue, []uint64, error) {
+	if r.borSn == nil || toId < fromId {
+		return nil, nil, nil
+	}
+
+	segments := r.borSn.ViewType(borsnaptype.BorEvents)
+	defer segments.Close()
+
+	var buf []byte
+	var events []rlp.RawValue
+	var blockNums []uint64
+	for _, sn := range segments.VisibleSegments {
+		if sn.src.Index() == nil {
+			continue
+		}
+
+		gg := sn.src.MakeGetter()
+		for gg.HasNext() {
+			buf, _ = gg.Next(buf[:0])
+
+			eventId := binary.BigEndian.Uint64(buf[length.Hash+length.BlockNum : length.Hash+length.BlockNum+8])
+			if eventId < fromId {
+				continue
+			}
+			if eventId > toId {
+				return events, blockNums, nil
+			}
+
+			events = append(events, common.Copy(buf[length.Hash+length.BlockNum+8:]))
+			blockNums = append(blockNums, binary.BigEndian.Uint64(buf[length.Hash:length.Hash+length.BlockNum]))
+		}
+	}
+
+	return events, blockNums, nil
+}
+
 func (r *BlockReader) LastEventId(_ context.Context, tx kv.Tx) (uint64, bool, error) {
 	cursor, err := tx.Cursor(kv.BorEvents)
 	if err != nil {
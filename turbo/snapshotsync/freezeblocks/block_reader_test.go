@@ -205,6 +205,41 @@ func TestBlockReaderLastFrozenEventIdReturnsZeroWhenAllSegmentsDoNotHaveIdx(t *t
 	require.Equal(t, uint64(0), blockReader.LastFrozenEventId())
 }
 
+func TestBlockReaderFrozenEventsByIdRange(t *testing.T) {
+	t.Parallel()
+
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	createTestBorEventSegmentFile(t, 0, 500_000, 132, dir, logger)
+	createTestBorEventSegmentFile(t, 500_000, 1_000_000, 264, dir, logger)
+	createTestBorEventSegmentFile(t, 1_000_000, 1_500_000, 528, dir, logger)
+	createTestSegmentFile(t, 0, 500_000, borsnaptype.Enums.BorSpans, dir, 1, logger)
+	createTestSegmentFile(t, 500_000, 1_000_000, borsnaptype.Enums.BorSpans, dir, 1, logger)
+	createTestSegmentFile(t, 1_000_000, 1_500_000, borsnaptype.Enums.BorSpans, dir, 1, logger)
+	// delete idx file for last bor events segment to simulate segment with missing idx file
+	idxFileToDelete := filepath.Join(dir, snaptype.IdxFileName(1, 1_000_000, 1_500_000, borsnaptype.BorEvents.Name()))
+	err := os.Remove(idxFileToDelete)
+	require.NoError(t, err)
+	borRoSnapshots := NewBorRoSnapshots(ethconfig.BlocksFreezing{ChainName: networkname.BorMainnet}, dir, 0, logger)
+	defer borRoSnapshots.Close()
+	err = borRoSnapshots.OpenFolder()
+	require.NoError(t, err)
+
+	blockReader := &BlockReader{borSn: borRoSnapshots}
+	events, blockNums, err := blockReader.FrozenEventsByIdRange(100, 600)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, []uint64{0, 0}, blockNums)
+
+	events, _, err = blockReader.FrozenEventsByIdRange(200, 264)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	events, _, err = blockReader.FrozenEventsByIdRange(265, 600)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
 func createTestBorEventSegmentFile(t *testing.T, from, to, eventId uint64, dir string, logger log.Logger) {
 	compressCfg := seg.DefaultCfg
 	compressCfg.MinPatternScore = 100
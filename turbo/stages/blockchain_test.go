@@ -21,6 +21,7 @@ package stages_test
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -45,18 +46,21 @@ import (
 	"github.com/erigontech/erigon-lib/common/length"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/bitmapdb"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
 	types2 "github.com/erigontech/erigon-lib/types"
 
 	"github.com/erigontech/erigon/common/u256"
 	"github.com/erigontech/erigon/consensus/ethash"
 	"github.com/erigontech/erigon/core"
 	"github.com/erigontech/erigon/core/rawdb"
+	"github.com/erigontech/erigon/core/rawdb/rawtemporaldb"
 	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/core/types"
 	"github.com/erigontech/erigon/core/vm"
 	"github.com/erigontech/erigon/crypto"
 	"github.com/erigontech/erigon/ethdb/prune"
 	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
 	"github.com/erigontech/erigon/turbo/stages/mock"
 )
 
@@ -2283,3 +2287,99 @@ func current(m *mock.MockSentry, tx kv.Tx) *types.Block {
 	}
 	return b
 }
+
+// Blocks executed far from chain-tip by optimistic parallel execution must produce bit-identical receipts and state as
+// serial execution: conflicting txs (same recipients, same storage slots, transfers between senders) force re-executions.
+func TestParallelExecutionMatchesSerial(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip()
+	}
+	const blocks = 80
+	var (
+		keys     = make([]*ecdsa.PrivateKey, 4)
+		addrs    = make([]libcommon.Address, len(keys))
+		funds    = new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))
+		counter  = libcommon.HexToAddress("0xc0")
+		shared   = libcommon.HexToAddress("0xaa")
+		gasPrice = uint256.NewInt(params.GWei)
+		alloc    = types.GenesisAlloc{
+			// slot0++; sstore(slot0, caller); log1(0, 0, slot0)
+			counter: {Balance: new(big.Int), Code: hexutil.MustDecode("0x6000546001018060005533815560006000a100")},
+		}
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		alloc[addrs[i]] = types.GenesisAccount{Balance: funds}
+	}
+	gspec := &types.Genesis{
+		Config: &libchain.Config{ChainID: big.NewInt(1), HomesteadBlock: new(big.Int), TangerineWhistleBlock: new(big.Int), SpuriousDragonBlock: new(big.Int),
+			ByzantiumBlock: new(big.Int), ConstantinopleBlock: new(big.Int), PetersburgBlock: new(big.Int), IstanbulBlock: new(big.Int), BerlinBlock: new(big.Int)},
+		Alloc: alloc,
+	}
+	serial := mock.MockWithGenesis(t, gspec, keys[0], false)
+	parallel := mock.MockWithParallelExecution(t, gspec, keys[0])
+
+	signer := types.LatestSigner(gspec.Config)
+	chain, err := core.GenerateChain(serial.ChainConfig, serial.Genesis, serial.Engine, serial.DB, blocks, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(libcommon.Address{byte(i % 3)})
+		for j, key := range keys {
+			txs := []types.Transaction{
+				types.NewTransaction(b.TxNonce(addrs[j]), shared, uint256.NewInt(uint64(i+1)), params.TxGas, gasPrice, nil),
+				types.NewTransaction(b.TxNonce(addrs[j])+1, counter, new(uint256.Int), 100_000, gasPrice, nil),
+				types.NewTransaction(b.TxNonce(addrs[j])+2, addrs[(j+1)%len(addrs)], uint256.NewInt(params.GWei), params.TxGas, gasPrice, nil),
+			}
+			for _, txn := range txs {
+				signed, err := types.SignTx(txn, *signer, key)
+				require.NoError(t, err)
+				b.AddTx(signed)
+			}
+		}
+	})
+	require.NoError(t, err)
+
+	// both insertions check state root and receipts root of every block
+	require.NoError(t, serial.InsertChain(chain))
+	require.NoError(t, parallel.InsertChain(chain))
+
+	serialTx, err := serial.DB.BeginRo(serial.Ctx)
+	require.NoError(t, err)
+	defer serialTx.Rollback()
+	parallelTx, err := parallel.DB.BeginRo(parallel.Ctx)
+	require.NoError(t, err)
+	defer parallelTx.Rollback()
+
+	txNumsReader := rawdbv3.TxNums.WithCustomReadTxNumFunc(freezeblocks.ReadTxNumFuncFromBlockReader(parallel.Ctx, parallel.BlockReader))
+	for i, block := range chain.Blocks {
+		minTxNum, err := txNumsReader.Min(parallelTx, block.NumberU64())
+		require.NoError(t, err)
+		for j, receipt := range chain.Receipts[i] {
+			txNum := minTxNum + 1 + uint64(j) // first txNum of block is system tx
+			serialGas, _, serialLogIndex, err := rawtemporaldb.ReceiptAsOf(serialTx.(kv.TemporalTx), txNum+1)
+			require.NoError(t, err)
+			parallelGas, _, parallelLogIndex, err := rawtemporaldb.ReceiptAsOf(parallelTx.(kv.TemporalTx), txNum+1)
+			require.NoError(t, err)
+			require.Equal(t, receipt.CumulativeGasUsed, serialGas, "block %d, tx %d", block.NumberU64(), j)
+			require.Equal(t, serialGas, parallelGas, "block %d, tx %d", block.NumberU64(), j)
+			require.Equal(t, serialLogIndex, parallelLogIndex, "block %d, tx %d", block.NumberU64(), j)
+		}
+	}
+
+	serialState, parallelState := serial.NewStateReader(serialTx), parallel.NewStateReader(parallelTx)
+	for _, addr := range append([]libcommon.Address{counter, shared, {0}, {1}, {2}}, addrs...) {
+		serialAcc, err := serialState.ReadAccountData(addr)
+		require.NoError(t, err)
+		parallelAcc, err := parallelState.ReadAccountData(addr)
+		require.NoError(t, err)
+		require.Equal(t, serialAcc, parallelAcc, "account %x", addr)
+	}
+	for slot := uint64(0); slot <= blocks*uint64(len(keys)); slot++ {
+		key := libcommon.BigToHash(new(big.Int).SetUint64(slot))
+		serialVal, err := serialState.ReadAccountStorage(counter, 1, &key)
+		require.NoError(t, err)
+		parallelVal, err := parallelState.ReadAccountStorage(counter, 1, &key)
+		require.NoError(t, err)
+		require.Equal(t, serialVal, parallelVal, "slot %d", slot)
+	}
+}